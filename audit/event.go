@@ -0,0 +1,157 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	otelattr "go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/otelx/semconv"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/sqlxx"
+)
+
+// attributes which are not persisted because they may contain personal data.
+var omittedAttributes = map[otelattr.Key]struct{}{
+	otelattr.Key(events.AttributeKeyWebhookRequestBody):  {},
+	otelattr.Key(events.AttributeKeyWebhookResponseBody): {},
+}
+
+// An Audit Log Event
+//
+// Audit log events are written for every event emitted by Ory Kratos, for
+// example when a login succeeds or an identity is updated.
+//
+// swagger:model auditEvent
+type Event struct {
+	// The event's unique ID.
+	//
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	NID uuid.UUID `json:"-" faker:"-" db:"nid"`
+
+	// The event's name, for example `LoginSucceeded` or `IdentityUpdated`.
+	//
+	// required: true
+	Event string `json:"event" db:"event"`
+
+	// The ID of the identity the event is about, if any.
+	IdentityID uuid.NullUUID `json:"identity_id" db:"identity_id"`
+
+	// Who triggered the event. This is `admin` for events triggered through the
+	// admin API, `cli` for events triggered through the command line interface,
+	// the actor given when impersonating an identity, or the ID of the identity
+	// for self-service events. It is empty if the actor is unknown.
+	//
+	// required: true
+	Actor string `json:"actor" db:"actor"`
+
+	// The ID of the session the event is about, if any.
+	SessionID uuid.NullUUID `json:"session_id" db:"session_id"`
+
+	// The ID of the self-service flow the event occurred in, if any.
+	FlowID uuid.NullUUID `json:"flow_id" db:"flow_id"`
+
+	// The IP address of the client which triggered the event.
+	//
+	// required: true
+	IPAddress string `json:"ip_address" db:"ip_address"`
+
+	// The user agent of the client which triggered the event.
+	//
+	// required: true
+	UserAgent string `json:"user_agent" db:"user_agent"`
+
+	// All further attributes of the event.
+	//
+	// required: true
+	Attributes sqlxx.JSONRawMessage `json:"attributes" faker:"-" db:"attributes"`
+
+	// When the event occurred.
+	//
+	// required: true
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+}
+
+func (e Event) TableName() string { return "audit_events" }
+
+func (e Event) PageToken() keysetpagination.PageToken {
+	return keysetpagination.NewPageToken(
+		keysetpagination.Column{
+			Name:  "created_at",
+			Order: keysetpagination.OrderDescending,
+			Value: e.CreatedAt,
+		}, keysetpagination.Column{
+			Name:  "id",
+			Value: e.ID,
+		},
+	)
+}
+
+func (e Event) DefaultPageToken() keysetpagination.PageToken {
+	return Event{ID: uuid.Nil, CreatedAt: time.Date(2200, 12, 31, 23, 59, 59, 0, time.UTC)}.PageToken()
+}
+
+// NewEvent creates an audit log event from the name and attributes of an
+// event emitted through package events.
+func NewEvent(name string, attrs []otelattr.KeyValue) (*Event, error) {
+	e := &Event{Event: name}
+
+	var actor, impersonator string
+	rest := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		switch attr.Key {
+		case otelattr.Key(semconv.AttributeKeyIdentityID):
+			e.IdentityID = parseNullUUID(attr.Value.AsString())
+		case otelattr.Key(events.AttributeKeySessionID):
+			e.SessionID = parseNullUUID(attr.Value.AsString())
+		case otelattr.Key(events.AttributeKeyFlowID):
+			e.FlowID = parseNullUUID(attr.Value.AsString())
+		case otelattr.Key(semconv.AttributeKeyClientIP):
+			e.IPAddress = attr.Value.AsString()
+		case otelattr.Key(events.AttributeKeyUserAgent):
+			e.UserAgent = attr.Value.AsString()
+		case otelattr.Key(events.AttributeKeyActor):
+			actor = attr.Value.AsString()
+		case otelattr.Key(events.AttributeKeyImpersonationActorID):
+			impersonator = attr.Value.AsString()
+			rest[string(attr.Key)] = attr.Value.AsInterface()
+		default:
+			if _, ok := omittedAttributes[attr.Key]; !ok {
+				rest[string(attr.Key)] = attr.Value.AsInterface()
+			}
+		}
+	}
+
+	switch {
+	case impersonator != "":
+		e.Actor = impersonator
+	case actor != "":
+		e.Actor = actor
+	case e.IdentityID.Valid:
+		e.Actor = e.IdentityID.UUID.String()
+	}
+
+	raw, err := json.Marshal(rest)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	e.Attributes = raw
+
+	return e, nil
+}
+
+func parseNullUUID(v string) uuid.NullUUID {
+	id, err := uuid.FromString(v)
+	if err != nil || id == uuid.Nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}
+}
@@ -0,0 +1,86 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	otelattr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/otelx/semconv"
+)
+
+func TestNewEvent(t *testing.T) {
+	identityID, sessionID, flowID := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	ctx := semconv.ContextWithAttributes(context.Background(),
+		semconv.AttrClientIP("192.0.2.1"),
+		otelattr.String(events.AttributeKeyUserAgent.String(), "test-agent"),
+	)
+
+	newEvent := func(name string, opt trace.EventOption) (*audit.Event, error) {
+		c := trace.NewEventConfig(opt)
+		return audit.NewEvent(name, c.Attributes())
+	}
+
+	t.Run("case=extracts the well-known attributes", func(t *testing.T) {
+		e, err := newEvent(events.NewLoginSucceeded(ctx, &events.LoginSucceededOpts{
+			SessionID:  sessionID,
+			IdentityID: identityID,
+			FlowID:     flowID,
+			FlowType:   "browser",
+			Method:     "password",
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, events.LoginSucceeded.String(), e.Event)
+		assert.Equal(t, uuid.NullUUID{UUID: identityID, Valid: true}, e.IdentityID)
+		assert.Equal(t, uuid.NullUUID{UUID: sessionID, Valid: true}, e.SessionID)
+		assert.Equal(t, uuid.NullUUID{UUID: flowID, Valid: true}, e.FlowID)
+		assert.Equal(t, "192.0.2.1", e.IPAddress)
+		assert.Equal(t, "test-agent", e.UserAgent)
+		assert.Equal(t, identityID.String(), e.Actor)
+		assert.Equal(t, "password", gjson.GetBytes(e.Attributes, events.AttributeKeySelfServiceMethodUsed.String()).String(), "%s", e.Attributes)
+		assert.False(t, gjson.GetBytes(e.Attributes, semconv.AttributeKeyIdentityID.String()).Exists(), "%s", e.Attributes)
+	})
+
+	t.Run("case=ignores nil IDs", func(t *testing.T) {
+		e, err := newEvent(events.NewLoginFailed(ctx, uuid.Nil, "browser", "password", "aal1", false, assert.AnError))
+		require.NoError(t, err)
+		assert.False(t, e.FlowID.Valid)
+		assert.False(t, e.IdentityID.Valid)
+		assert.Empty(t, e.Actor)
+	})
+
+	t.Run("case=prefers the actor of the context", func(t *testing.T) {
+		ctx := semconv.ContextWithAttributes(ctx, otelattr.String(events.AttributeKeyActor.String(), events.ActorAdmin))
+
+		e, err := newEvent(events.NewIdentityUpdated(ctx, identityID))
+		require.NoError(t, err)
+		assert.Equal(t, events.ActorAdmin, e.Actor)
+		assert.Equal(t, uuid.NullUUID{UUID: identityID, Valid: true}, e.IdentityID)
+
+		e, err = newEvent(events.NewSessionImpersonated(ctx, sessionID, identityID, "support-agent"))
+		require.NoError(t, err)
+		assert.Equal(t, "support-agent", e.Actor)
+	})
+
+	t.Run("case=omits webhook bodies", func(t *testing.T) {
+		u, err := url.Parse("https://example.org/hook")
+		require.NoError(t, err)
+
+		e, err := newEvent(events.NewWebhookDelivered(ctx, u, []byte(`{"password":"secret"}`), 200, []byte(`{}`), 1, uuid.Nil, uuid.Nil, "hook"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(e.Attributes), "secret")
+		assert.Equal(t, u.String(), gjson.GetBytes(e.Attributes, events.AttributeKeyWebhookURL.String()).String(), "%s", e.Attributes)
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/x/httprouterx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+const AdminRouteListEvents = "/audit/events"
+
+type (
+	handlerDependencies interface {
+		x.WriterProvider
+		nosurfx.CSRFProvider
+		PersistenceProvider
+		config.Provider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		AuditHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
+	h.r.CSRFHandler().IgnoreGlobs(httprouterx.AdminPrefix+AdminRouteListEvents, AdminRouteListEvents)
+	public.GET(httprouterx.AdminPrefix+AdminRouteListEvents, redir.RedirectToAdminRoute(h.r))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
+	admin.GET(AdminRouteListEvents, h.listAuditEvents)
+}
+
+// Paginated Audit Event List Response
+//
+// swagger:response listAuditEvents
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listAuditEventsResponse struct {
+	keysetpagination.ResponseHeaders
+
+	// List of audit log events
+	//
+	// in:body
+	Body []Event
+}
+
+// Paginated List Audit Events Parameters
+//
+// swagger:parameters listAuditEvents
+type ListEventsParameters struct {
+	keysetpagination.RequestParameters
+
+	// IdentityID only returns events about the identity with this ID.
+	//
+	// required: false
+	// in: query
+	IdentityID uuid.NullUUID `json:"identity_id"`
+
+	// Events only returns events with one of these names, for example
+	// `LoginSucceeded`.
+	//
+	// required: false
+	// in: query
+	Events []string `json:"event"`
+
+	// Since only returns events which occurred at or after this time.
+	//
+	// required: false
+	// in: query
+	Since *time.Time `json:"since"`
+
+	// Until only returns events which occurred before this time.
+	//
+	// required: false
+	// in: query
+	Until *time.Time `json:"until"`
+}
+
+// swagger:route GET /admin/audit/events audit listAuditEvents
+//
+// # List Audit Log Events
+//
+// Lists the events of the audit log, newest first. Events are only written
+// if the audit log is enabled.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: listAuditEvents
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listAuditEvents(w http.ResponseWriter, r *http.Request) {
+	keys := h.r.Config().SecretsPagination(r.Context())
+	filter, paginator, err := parseEventsFilter(r, keys)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	l, nextPage, err := h.r.AuditPersister().ListAuditEvents(r.Context(), filter, paginator)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	u := *r.URL
+	keysetpagination.SetLinkHeader(w, keys, &u, nextPage)
+	h.r.Writer().Write(w, r, l)
+}
+
+func parseEventsFilter(r *http.Request, keys [][32]byte) (ListEventsParameters, []keysetpagination.Option, error) {
+	query := r.URL.Query()
+	filter := ListEventsParameters{Events: query["event"]}
+
+	if query.Has("identity_id") {
+		id, err := uuid.FromString(query.Get("identity_id"))
+		if err != nil {
+			return filter, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The identity_id query parameter must be a UUID.").WithDebug(err.Error()))
+		}
+		filter.IdentityID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	for key, t := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if !query.Has(key) {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, query.Get(key))
+		if err != nil {
+			return filter, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The %s query parameter must be a RFC 3339 timestamp.", key).WithDebug(err.Error()))
+		}
+		parsed = parsed.UTC()
+		*t = &parsed
+	}
+
+	opts, err := keysetpagination.ParseQueryParams(keys, query)
+	if err != nil {
+		return filter, nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The page token is invalid.").WithDebug(err.Error()))
+	}
+
+	return filter, opts, nil
+}
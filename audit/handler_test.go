@@ -0,0 +1,163 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/httprouterx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValue(config.ViperKeySecurityAuditLogEnabled, true),
+	)
+	publicTS, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	list := func(t *testing.T, ts *httptest.Server, href string, expectCode int) (gjson.Result, *http.Response) {
+		t.Helper()
+		res, err := ts.Client().Get(ts.URL + href)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, expectCode, res.StatusCode, "%s", body)
+		return gjson.ParseBytes(body), res
+	}
+
+	createIdentity := func(t *testing.T) uuid.UUID {
+		t.Helper()
+		res, err := adminTS.Client().Post(adminTS.URL+"/admin/identities", "application/json",
+			strings.NewReader(`{"schema_id":"default","traits":{"email":"`+uuid.Must(uuid.NewV4()).String()+`@ory.sh"}}`))
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		return uuid.FromStringOrNil(gjson.GetBytes(body, "id").String())
+	}
+
+	t.Run("case=records events of the admin API", func(t *testing.T) {
+		id := createIdentity(t)
+
+		events, _ := list(t, adminTS, "/admin"+audit.AdminRouteListEvents+"?identity_id="+id.String(), http.StatusOK)
+		require.Len(t, events.Array(), 1, "%s", events.Raw)
+		assert.Equal(t, "IdentityCreated", events.Get("0.event").String())
+		assert.Equal(t, id.String(), events.Get("0.identity_id").String())
+		assert.Equal(t, "admin", events.Get("0.actor").String())
+		assert.Equal(t, "Go-http-client/1.1", events.Get("0.user_agent").String())
+
+		t.Run("case=is available through the public API prefix", func(t *testing.T) {
+			redirected, _ := list(t, publicTS, httprouterx.AdminPrefix+audit.AdminRouteListEvents+"?identity_id="+id.String(), http.StatusOK)
+			assert.Equal(t, events.Raw, redirected.Raw)
+		})
+	})
+
+	t.Run("case=does not record ignored events", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySecurityAuditLogIgnoredEvents, []string{"IdentityCreated"})
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySecurityAuditLogIgnoredEvents, []string{}) })
+
+		id := createIdentity(t)
+		events, _ := list(t, adminTS, "/admin"+audit.AdminRouteListEvents+"?identity_id="+id.String(), http.StatusOK)
+		assert.Empty(t, events.Array(), "%s", events.Raw)
+	})
+
+	t.Run("case=does not record events if disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySecurityAuditLogEnabled, false)
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySecurityAuditLogEnabled, true) })
+
+		id := createIdentity(t)
+		events, _ := list(t, adminTS, "/admin"+audit.AdminRouteListEvents+"?identity_id="+id.String(), http.StatusOK)
+		assert.Empty(t, events.Array(), "%s", events.Raw)
+	})
+
+	t.Run("case=filters and paginates events", func(t *testing.T) {
+		identityID := uuid.Must(uuid.NewV4())
+		now := time.Now().UTC().Truncate(time.Second)
+		for i, name := range []string{"LoginSucceeded", "LoginFailed", "LoginSucceeded"} {
+			require.NoError(t, reg.AuditPersister().CreateAuditEvent(ctx, &audit.Event{
+				Event:      name,
+				IdentityID: uuid.NullUUID{UUID: identityID, Valid: true},
+				Attributes: []byte("{}"),
+				CreatedAt:  now.Add(time.Duration(i-3) * time.Hour),
+			}))
+		}
+
+		href := func(query url.Values) string {
+			query.Set("identity_id", identityID.String())
+			return "/admin" + audit.AdminRouteListEvents + "?" + query.Encode()
+		}
+
+		t.Run("case=newest first", func(t *testing.T) {
+			events, _ := list(t, adminTS, href(url.Values{}), http.StatusOK)
+			require.Len(t, events.Array(), 3, "%s", events.Raw)
+			assert.True(t, events.Get("0.created_at").Time().After(events.Get("2.created_at").Time()), "%s", events.Raw)
+		})
+
+		t.Run("case=by event", func(t *testing.T) {
+			events, _ := list(t, adminTS, href(url.Values{"event": {"LoginFailed"}}), http.StatusOK)
+			require.Len(t, events.Array(), 1, "%s", events.Raw)
+			assert.Equal(t, "LoginFailed", events.Get("0.event").String())
+
+			events, _ = list(t, adminTS, href(url.Values{"event": {"LoginFailed", "LoginSucceeded"}}), http.StatusOK)
+			assert.Len(t, events.Array(), 3, "%s", events.Raw)
+		})
+
+		t.Run("case=by time", func(t *testing.T) {
+			events, _ := list(t, adminTS, href(url.Values{
+				"since": {now.Add(-150 * time.Minute).Format(time.RFC3339)},
+				"until": {now.Add(-time.Hour).Format(time.RFC3339)},
+			}), http.StatusOK)
+			require.Len(t, events.Array(), 1, "%s", events.Raw)
+			assert.Equal(t, "LoginFailed", events.Get("0.event").String())
+		})
+
+		t.Run("case=paginates", func(t *testing.T) {
+			first, res := list(t, adminTS, href(url.Values{"page_size": {"2"}}), http.StatusOK)
+			require.Len(t, first.Array(), 2, "%s", first.Raw)
+
+			_, next, isLast := keysetpagination.ParseHeader(res)
+			require.False(t, isLast)
+
+			second, res := list(t, adminTS, href(url.Values{"page_size": {"2"}, "page_token": {next}}), http.StatusOK)
+			require.Len(t, second.Array(), 1, "%s", second.Raw)
+			assert.NotEqual(t, first.Get("1.id").String(), second.Get("0.id").String())
+
+			_, _, isLast = keysetpagination.ParseHeader(res)
+			assert.True(t, isLast)
+		})
+	})
+
+	t.Run("case=rejects invalid filters", func(t *testing.T) {
+		for _, query := range []string{"identity_id=not-a-uuid", "since=yesterday", "until=1", "page_token=invalid"} {
+			t.Run("query="+query, func(t *testing.T) {
+				list(t, adminTS, "/admin"+audit.AdminRouteListEvents+"?"+query, http.StatusBadRequest)
+			})
+		}
+	})
+}
@@ -0,0 +1,28 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+type (
+	Persister interface {
+		// CreateAuditEvent appends the event to the audit log.
+		CreateAuditEvent(context.Context, *Event) error
+
+		// ListAuditEvents lists the events matching the filter, newest first.
+		ListAuditEvents(context.Context, ListEventsParameters, []keysetpagination.Option) ([]Event, *keysetpagination.Paginator, error)
+
+		// DeleteExpiredAuditEvents removes events which are older than the given
+		// time and the configured retention.
+		DeleteExpiredAuditEvents(context.Context, time.Time, int) error
+	}
+	PersistenceProvider interface {
+		AuditPersister() Persister
+	}
+)
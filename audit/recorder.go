@@ -0,0 +1,62 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"slices"
+
+	otelattr "go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+)
+
+var _ events.Recorder = new(Recorder)
+
+type (
+	recorderDependencies interface {
+		config.Provider
+		x.LoggingProvider
+		PersistenceProvider
+	}
+	// Recorder writes the events emitted through package events to the audit
+	// log.
+	Recorder struct {
+		d recorderDependencies
+	}
+	RecorderProvider interface {
+		AuditRecorder() *Recorder
+	}
+)
+
+func NewRecorder(d recorderDependencies) *Recorder {
+	return &Recorder{d: d}
+}
+
+// RecordEvent writes the event to the audit log, unless the audit log is
+// disabled or the event is ignored.
+//
+// Events emitted inside a database transaction are written in the same
+// transaction, so they are only persisted if the transaction is committed.
+// Failing to write an event is logged, but does not fail the request.
+func (r *Recorder) RecordEvent(ctx context.Context, name string, attrs []otelattr.KeyValue) {
+	conf := r.d.Config().SecurityAuditLog(ctx)
+	if !conf.Enabled || slices.Contains(conf.IgnoredEvents, name) {
+		return
+	}
+
+	e, err := NewEvent(name, attrs)
+	if err == nil {
+		// The event must be written even if the client went away.
+		err = r.d.AuditPersister().CreateAuditEvent(context.WithoutCancel(ctx), e)
+	}
+	if err != nil {
+		r.d.Logger().
+			WithError(err).
+			WithField("event", name).
+			Error("Unable to write the event to the audit log.")
+	}
+}
@@ -74,6 +74,7 @@ func init() {
 		"NewInfoSelfServiceRegisterWebAuthnDisplayName":           text.NewInfoSelfServiceRegisterWebAuthnDisplayName(),
 		"NewInfoSelfServiceRemoveWebAuthn":                        text.NewInfoSelfServiceRemoveWebAuthn("{display_name}", aSecondAgo),
 		"NewInfoSelfServiceRemovePasskey":                         text.NewInfoSelfServiceRemovePasskey("{display_name}", aSecondAgo),
+		"NewInfoSelfServiceRemovePassword":                        text.NewInfoSelfServiceRemovePassword(),
 		"NewErrorValidationVerificationFlowExpired":               text.NewErrorValidationVerificationFlowExpired(aSecondAgo),
 		"NewInfoSelfServiceVerificationSuccessful":                text.NewInfoSelfServiceVerificationSuccessful(),
 		"NewVerificationEmailSent":                                text.NewVerificationEmailSent(),
@@ -106,6 +107,7 @@ func init() {
 		"NewErrorValidationPasswordTooManyBreaches":               text.NewErrorValidationPasswordTooManyBreaches(101),
 		"NewErrorValidationPasswordNewSameAsOld":                  text.NewErrorValidationPasswordNewSameAsOld(),
 		"NewErrorValidationInvalidCredentials":                    text.NewErrorValidationInvalidCredentials(),
+		"NewErrorValidationLoginLockedOut":                        text.NewErrorValidationLoginLockedOut(inAMinute),
 		"NewErrorValidationDuplicateCredentials":                  text.NewErrorValidationDuplicateCredentials(),
 		"NewErrorValidationDuplicateCredentialsWithHints":         text.NewErrorValidationDuplicateCredentialsWithHints([]string{"{available_credential_types_list}"}, []string{"{available_oidc_providers_list}"}, "{credential_identifier_hint}"),
 		"NewErrorValidationDuplicateCredentialsOnOIDCLink":        text.NewErrorValidationDuplicateCredentialsOnOIDCLink(),
@@ -188,6 +190,11 @@ func init() {
 		"NewInfoSelfServiceLoginAAL2CodeAddress":                  text.NewInfoSelfServiceLoginAAL2CodeAddress("{channel}", "{address}"),
 		"NewErrorCaptchaFailed":                                   text.NewErrorCaptchaFailed(),
 		"NewCaptchaContainerMessage":                              text.NewCaptchaContainerMessage(),
+		"NewErrorValidationDeletionFlowExpired":                   text.NewErrorValidationDeletionFlowExpired(aSecondAgo),
+		"NewErrorValidationDeletionNotConfirmed":                  text.NewErrorValidationDeletionNotConfirmed(),
+		"NewInfoSelfServiceDeletionConfirm":                       text.NewInfoSelfServiceDeletionConfirm(),
+		"NewInfoSelfServiceDeletionSubmit":                        text.NewInfoSelfServiceDeletionSubmit(),
+		"NewInfoSelfServiceDeletionSuccessful":                    text.NewInfoSelfServiceDeletionSuccessful(),
 	}
 }
 
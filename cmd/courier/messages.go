@@ -0,0 +1,173 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+	otelattr "go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/otelx/semconv"
+)
+
+type registryFactory func(cmd *cobra.Command) (driver.Registry, error)
+
+func newRegistryFactory(dOpts []driver.RegistryOption) registryFactory {
+	return func(cmd *cobra.Command) (driver.Registry, error) {
+		return driver.New(cmd.Context(), cmd.ErrOrStderr(), append(dOpts, driver.WithConfigOptions(configx.WithFlags(cmd.Flags())))...)
+	}
+}
+
+// cliContext returns a context in which events are recorded in the audit log
+// with the command line interface as their actor.
+func cliContext(ctx context.Context, r driver.Registry) context.Context {
+	ctx = semconv.ContextWithAttributes(ctx, otelattr.String(events.AttributeKeyActor.String(), events.ActorCLI))
+	return events.WithRecorder(ctx, r.AuditRecorder())
+}
+
+func NewRetryCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	return newRetryCmd(newRegistryFactory(dOpts))
+}
+
+func newRetryCmd(newRegistry registryFactory) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "retry id-0 [id-1] [id-2] [id-n]",
+		Short: "Retry one or more abandoned, cancelled, or sent messages by their ID(s)",
+		Long: `This command queues one or more messages again and resets their send count.
+Only abandoned, cancelled, or sent messages can be retried.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := newRegistry(cmd)
+			if err != nil {
+				return err
+			}
+			return forEachMessage(cmd, args, func(ctx context.Context, id uuid.UUID) error {
+				_, err := courier.RetryMessage(cliContext(ctx, r), r, id)
+				return err
+			})
+		},
+	}
+	cmdx.RegisterFormatFlags(c.Flags())
+	return c
+}
+
+func NewCancelCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	return newCancelCmd(newRegistryFactory(dOpts))
+}
+
+func newCancelCmd(newRegistry registryFactory) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cancel id-0 [id-1] [id-2] [id-n]",
+		Short: "Cancel one or more queued messages by their ID(s)",
+		Long: `This command cancels one or more queued messages, so that they are not sent.
+Cancelled messages can be retried.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := newRegistry(cmd)
+			if err != nil {
+				return err
+			}
+			return forEachMessage(cmd, args, func(ctx context.Context, id uuid.UUID) error {
+				_, err := courier.CancelMessage(cliContext(ctx, r), r, id)
+				return err
+			})
+		},
+	}
+	cmdx.RegisterFormatFlags(c.Flags())
+	return c
+}
+
+func NewRequeueCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	return newRequeueCmd(newRegistryFactory(dOpts))
+}
+
+func newRequeueCmd(newRegistry registryFactory) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "requeue",
+		Short: "Retry all messages matching a filter",
+		Long: `This command queues all messages matching the filter again and resets their send count.
+The status must be one of abandoned, cancelled, or sent. It prints the number of requeued messages.`,
+		Example: `To resend all abandoned recovery codes, run:
+
+	{{ .CommandPath }} --status abandoned --template-type recovery_code_valid`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			flagStatus, _ := cmd.Flags().GetString("status")
+			status, err := courier.ToMessageStatus(flagStatus)
+			if err != nil {
+				return err
+			}
+			recipient, _ := cmd.Flags().GetString("recipient")
+			templateType, _ := cmd.Flags().GetString("template-type")
+
+			r, err := newRegistry(cmd)
+			if err != nil {
+				return err
+			}
+
+			count, err := courier.RequeueMessages(cliContext(cmd.Context(), r), r, courier.ListCourierMessagesParameters{
+				Status:       &status,
+				Recipient:    recipient,
+				TemplateType: template.TemplateType(templateType),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), count)
+			return nil
+		},
+	}
+	c.Flags().String("status", "abandoned", "Only requeue messages with this status, one of abandoned, cancelled, or sent")
+	c.Flags().String("recipient", "", "Only requeue messages sent to this recipient")
+	c.Flags().String("template-type", "", "Only requeue messages with this template type, for example recovery_code_valid")
+	return c
+}
+
+func forEachMessage(cmd *cobra.Command, args []string, f func(ctx context.Context, id uuid.UUID) error) error {
+	var (
+		done   = make([]cmdx.OutputIder, 0, len(args))
+		failed = make(map[string]error)
+	)
+
+	for _, a := range args {
+		id, err := uuid.FromString(a)
+		if err != nil {
+			failed[a] = err
+			continue
+		}
+		if err := f(cmd.Context(), id); err != nil {
+			if r := *new(herodot.ReasonCarrier); errors.As(err, &r) && r.Reason() != "" {
+				err = fmt.Errorf("%w: %s", err, r.Reason())
+			}
+			failed[a] = err
+			continue
+		}
+		done = append(done, cmdx.OutputIder(a))
+	}
+
+	if len(done) == 1 {
+		cmdx.PrintRow(cmd, &done[0])
+	} else if len(done) > 1 {
+		cmdx.PrintTable(cmd, &cmdx.OutputIderCollection{Items: done})
+	}
+
+	cmdx.PrintErrors(cmd, failed)
+	if len(failed) != 0 {
+		return cmdx.FailSilently(cmd)
+	}
+
+	return nil
+}
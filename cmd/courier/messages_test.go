@@ -0,0 +1,98 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/uuidx"
+)
+
+func TestMessageCommands(t *testing.T) {
+	ctx := t.Context()
+	_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValue(config.ViperKeySecurityAuditLogEnabled, true))
+	newRegistry := func(*cobra.Command) (driver.Registry, error) { return reg, nil }
+
+	addMessage := func(t *testing.T, status courier.MessageStatus) courier.Message {
+		t.Helper()
+		var m courier.Message
+		require.NoError(t, faker.FakeData(&m))
+		m.Type = courier.MessageTypeEmail
+		m.TemplateType = "stub"
+		require.NoError(t, reg.CourierPersister().AddMessage(ctx, &m))
+		require.NoError(t, reg.Persister().GetConnection(ctx).RawQuery("UPDATE courier_messages SET status = ? WHERE id = ?", status, m.ID).Exec())
+		return m
+	}
+
+	status := func(t *testing.T, m courier.Message) courier.MessageStatus {
+		t.Helper()
+		actual, err := reg.CourierPersister().FetchMessage(ctx, m.ID)
+		require.NoError(t, err)
+		return actual.Status
+	}
+
+	t.Run("command=retry", func(t *testing.T) {
+		m1, m2 := addMessage(t, courier.MessageStatusAbandoned), addMessage(t, courier.MessageStatusSent)
+
+		stdOut := cmdx.ExecNoErr(t, newRetryCmd(newRegistry), "--quiet", m1.ID.String(), m2.ID.String())
+		assert.Equal(t, m1.ID.String()+"\n"+m2.ID.String()+"\n", stdOut)
+		assert.Equal(t, courier.MessageStatusQueued, status(t, m1))
+		assert.Equal(t, courier.MessageStatusQueued, status(t, m2))
+
+		events, _, err := reg.AuditPersister().ListAuditEvents(ctx, audit.ListEventsParameters{Events: []string{"CourierMessageRequeued"}}, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, events)
+		assert.Equal(t, "cli", events[0].Actor)
+	})
+
+	t.Run("command=retry fails for queued messages", func(t *testing.T) {
+		m := addMessage(t, courier.MessageStatusQueued)
+
+		_, stdErr, err := cmdx.Exec(t, newRetryCmd(newRegistry), nil, m.ID.String(), "not-a-uuid")
+		require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+		assert.Contains(t, stdErr, "Only abandoned, cancelled, or sent messages can be retried")
+		assert.Contains(t, stdErr, "not-a-uuid")
+		assert.Equal(t, courier.MessageStatusQueued, status(t, m))
+	})
+
+	t.Run("command=cancel", func(t *testing.T) {
+		m := addMessage(t, courier.MessageStatusQueued)
+
+		stdOut := cmdx.ExecNoErr(t, newCancelCmd(newRegistry), "--quiet", m.ID.String())
+		assert.Equal(t, m.ID.String()+"\n", stdOut)
+		assert.Equal(t, courier.MessageStatusCancelled, status(t, m))
+	})
+
+	t.Run("command=requeue", func(t *testing.T) {
+		recipient := "cli-" + uuidx.NewV4().String() + "@ory.sh"
+		ms := []courier.Message{addMessage(t, courier.MessageStatusCancelled), addMessage(t, courier.MessageStatusCancelled), addMessage(t, courier.MessageStatusCancelled)}
+		for _, m := range ms[:2] {
+			require.NoError(t, reg.Persister().GetConnection(ctx).RawQuery("UPDATE courier_messages SET recipient = ? WHERE id = ?", recipient, m.ID).Exec())
+		}
+
+		stdOut := cmdx.ExecNoErr(t, newRequeueCmd(newRegistry), "--status", "cancelled", "--recipient", recipient)
+		assert.Equal(t, "2", strings.TrimSpace(stdOut))
+		assert.Equal(t, courier.MessageStatusQueued, status(t, ms[0]))
+		assert.Equal(t, courier.MessageStatusQueued, status(t, ms[1]))
+		assert.Equal(t, courier.MessageStatusCancelled, status(t, ms[2]))
+	})
+
+	t.Run("command=requeue rejects queued status", func(t *testing.T) {
+		_, _, err := cmdx.Exec(t, newRequeueCmd(newRegistry), nil, "--status", "queued")
+		require.Error(t, err)
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/ory/graceful"
 	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/configx"
 	"github.com/ory/x/prometheusx"
 	"github.com/ory/x/reqlog"
@@ -83,6 +84,7 @@ func ServeMetrics(ctx context.Context, r driver.Registry, port int) error {
 
 func Watch(ctx context.Context, r driver.Registry) error {
 	ctx, cancel := context.WithCancel(ctx)
+	ctx = events.WithRecorder(ctx, r.AuditRecorder())
 
 	r.Logger().Println("Courier worker started.")
 	if err := graceful.Graceful(func() error {
@@ -34,6 +34,7 @@ import (
 	"github.com/ory/kratos/selfservice/strategy/oidc"
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/x/healthx"
 	"github.com/ory/x/httprouterx"
@@ -68,6 +69,7 @@ func servePublic(ctx context.Context, r *driver.RegistryDefault, cmd *cobra.Comm
 	}
 
 	n.UseFunc(semconv.Middleware)
+	n.Use(events.NewRecorderMiddleware(r.AuditRecorder(), ""))
 	n.Use(publicLogger)
 	n.Use(x.HTTPLoaderContextMiddleware(r))
 	n.UseFunc(httprouterx.NoCacheNegroni)
@@ -165,6 +167,7 @@ func serveAdmin(ctx context.Context, r *driver.RegistryDefault, cmd *cobra.Comma
 		)
 	}
 	n.UseFunc(semconv.Middleware)
+	n.Use(events.NewRecorderMiddleware(r.AuditRecorder(), events.ActorAdmin))
 	n.Use(adminLogger)
 	n.UseFunc(httprouterx.AddAdminPrefixIfNotPresentNegroni)
 	n.UseFunc(httprouterx.NoCacheNegroni)
@@ -327,6 +330,29 @@ func courierTask(ctx context.Context, d driver.Registry) func() error {
 	}
 }
 
+func webhookOutboxTask(ctx context.Context, d driver.Registry) func() error {
+	return func() error {
+		if !d.Config().WebhookOutbox(ctx).Enabled {
+			return nil
+		}
+
+		ctx, cancel := context.WithCancel(events.WithRecorder(ctx, d.AuditRecorder()))
+		d.Logger().Println("Webhook outbox worker started.")
+		if err := graceful.Graceful(func() error {
+			return d.WebhookOutbox().Work(ctx)
+		}, func(_ context.Context) error {
+			cancel()
+			return nil
+		}); err != nil {
+			d.Logger().WithError(err).Error("Failed to run webhook outbox worker.")
+			return err
+		}
+
+		d.Logger().Println("Webhook outbox worker was shutdown gracefully.")
+		return nil
+	}
+}
+
 func ServeAll(d *driver.RegistryDefault) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		ctx := cmd.Context()
@@ -346,6 +372,7 @@ func ServeAll(d *driver.RegistryDefault) func(cmd *cobra.Command, args []string)
 			publicSrv,
 			adminSrv,
 			courierTask(ctx, d),
+			webhookOutboxTask(ctx, d),
 		}
 		for _, task := range tasks {
 			g.Go(task)
@@ -0,0 +1,108 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identities
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/flagx"
+
+	"github.com/ory/kratos/cmd/cliclient"
+)
+
+func NewExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export resources",
+	}
+	cmd.AddCommand(NewExportIdentitiesCmd())
+	cliclient.RegisterClientFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+func NewExportIdentitiesCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "identities",
+		Short: "Export identities including their credentials",
+		Long: `Export all identities including their credentials as newline-delimited JSON.
+
+Every line is an identity in the format expected by "... import identities", which makes the export suitable for migrating
+identities to another Ory Kratos instance and for disaster recovery. Passwords are exported as hashes, and the initial
+OpenID Connect tokens remain encrypted with the cipher secret of Ory Kratos.
+
+The export contains credentials and must be stored securely.`,
+		Example: `{{ .CommandPath }} > identities.jsonl
+
+To export the identities of an organization matching a filter expression, run:
+
+	{{ .CommandPath }} --organization-id 5a5c8e3c-0000-0000-0000-000000000000 --filter "metadata_public.plan=enterprise"
+
+To import the export into another instance, run:
+
+	{{ .Root.Name }} import identities identities.jsonl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cliclient.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			conf := c.GetConfig()
+			endpoint, err := conf.ServerURLWithContext(cmd.Context(), "IdentityAPIService.ListIdentities")
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			query := url.Values{}
+			if orgID := flagx.MustGetString(cmd, "organization-id"); orgID != "" {
+				query.Set("organization_id", orgID)
+			}
+			for _, filter := range flagx.MustGetStringArray(cmd, "filter") {
+				query.Add("filter", filter)
+			}
+			if consistency := flagx.MustGetString(cmd, "consistency"); consistency != "" {
+				query.Set("consistency", consistency)
+			}
+
+			req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, endpoint+"/admin/identities/export?"+query.Encode(), nil)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			// The export streams all identities, which can take longer than the
+			// timeout of the client.
+			hc := *conf.HTTPClient
+			hc.Timeout = 0
+			res, err := hc.Do(req)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			defer func() { _ = res.Body.Close() }()
+
+			if res.StatusCode != http.StatusOK {
+				body, _ := io.ReadAll(res.Body)
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Could not export identities: %s\n%s\n", res.Status, body)
+				return cmdx.FailSilently(cmd)
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), res.Body); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "The export is incomplete: %s\n", err)
+				return cmdx.FailSilently(cmd)
+			}
+
+			return nil
+		},
+	}
+	c.Flags().String("organization-id", "", "Only export identities that belong to this organization.")
+	c.Flags().StringArray("filter", nil, "Only export identities matching this filter expression, for example \"traits.email=foo@ory.sh\". Can be repeated.")
+	c.Flags().String("consistency", "eventual", "The read consistency to use. Can be either \"strong\" or \"eventual\". Defaults to \"eventual\".")
+	return c
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identities_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/cmdx"
+
+	"github.com/ory/kratos/cmd/cliclient"
+	"github.com/ory/kratos/cmd/identities"
+	"github.com/ory/kratos/identity"
+)
+
+func TestExportCmd(t *testing.T) {
+	reg, cmd := setup(t, identities.NewExportIdentitiesCmd)
+
+	t.Run("case=exports all identities", func(t *testing.T) {
+		is, ids := makeIdentities(t, reg, 3)
+		t.Cleanup(func() {
+			for _, i := range is {
+				_ = reg.Persister().DeleteIdentity(context.Background(), i.ID)
+			}
+		})
+
+		stdOut := cmd.ExecNoErr(t)
+
+		lines := strings.Split(strings.TrimSpace(stdOut), "\n")
+		require.Len(t, lines, 3, "%s", stdOut)
+		assert.ElementsMatch(t, ids, []string{gjson.Get(lines[0], "id").String(), gjson.Get(lines[1], "id").String(), gjson.Get(lines[2], "id").String()})
+	})
+
+	t.Run("case=exports identities matching a filter", func(t *testing.T) {
+		is, ids := makeIdentities(t, reg, 2)
+		t.Cleanup(func() {
+			for _, i := range is {
+				_ = reg.Persister().DeleteIdentity(context.Background(), i.ID)
+			}
+		})
+
+		stdOut := cmd.ExecNoErr(t, "--filter", "metadata_public.foo=bar", "--filter", "state=active")
+		assert.Len(t, strings.Split(strings.TrimSpace(stdOut), "\n"), 2, "%s", stdOut)
+		for _, id := range ids {
+			assert.Contains(t, stdOut, id)
+		}
+
+		stdOut = cmd.ExecNoErr(t, "--filter", "metadata_public.foo=baz")
+		assert.Empty(t, strings.TrimSpace(stdOut))
+	})
+
+	t.Run("case=fails on a malformed filter", func(t *testing.T) {
+		stdErr := cmd.ExecExpectedErr(t, "--filter", "password=secret")
+		assert.Contains(t, stdErr, "unknown field")
+	})
+
+	t.Run("case=the export can be imported again", func(t *testing.T) {
+		is, ids := makeIdentities(t, reg, 2)
+
+		stdOut := cmd.ExecNoErr(t)
+		for _, i := range is {
+			require.NoError(t, reg.Persister().DeleteIdentity(context.Background(), i.ID))
+		}
+
+		importCmd := &cmdx.CommandExecuter{
+			New: func() *cobra.Command {
+				c := identities.NewImportIdentitiesCmd()
+				cliclient.RegisterClientFlags(c.Flags())
+				cmdx.RegisterFormatFlags(c.Flags())
+				return c
+			},
+			PersistentArgs: cmd.PersistentArgs,
+		}
+		imported, stdErr, err := importCmd.Exec(strings.NewReader(stdOut))
+		require.NoError(t, err, "%s %s", imported, stdErr)
+
+		for _, id := range ids {
+			assert.Contains(t, imported, id)
+			_, err := reg.Persister().GetIdentity(context.Background(), uuid.FromStringOrNil(id), identity.ExpandNothing)
+			assert.NoError(t, err)
+		}
+	})
+}
@@ -17,7 +17,16 @@ import (
 func parseIdentities(raw []byte) (rawIdentities []string) {
 	res := gjson.ParseBytes(raw)
 	if !res.IsArray() {
-		return []string{res.Raw}
+		// Newline-delimited JSON, as written by "export identities", contains
+		// one identity per line.
+		gjson.ForEachLine(string(raw), func(v gjson.Result) bool {
+			rawIdentities = append(rawIdentities, v.Raw)
+			return true
+		})
+		if len(rawIdentities) == 0 {
+			return []string{res.Raw}
+		}
+		return
 	}
 	res.ForEach(func(_, v gjson.Result) bool {
 		rawIdentities = append(rawIdentities, v.Raw)
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/pkg/errors"
+
 	kratos "github.com/ory/kratos/internal/httpclient"
 
 	"github.com/ory/x/cmdx"
@@ -50,7 +52,7 @@ Alternatively:
 	cat file.json | {{ .CommandPath }}`,
 		Long: `Import identities from files or STD_IN.
 
-Files can contain only a single or an array of identities. The validity of files can be tested beforehand using "... identities validate".`,
+Files can contain a single identity, an array of identities, or one identity per line as written by "... export identities". The validity of files can be tested beforehand using "... identities validate".`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, err := cliclient.NewClient(cmd)
 			if err != nil {
@@ -73,7 +75,7 @@ Files can contain only a single or an array of identities. The validity of files
 					return cmdx.FailSilently(cmd)
 				}
 
-				ident, _, err := c.IdentityAPI.CreateIdentity(cmd.Context()).CreateIdentityBody(params).Execute()
+				ident, err := importIdentity(cmd, c, params)
 				if err != nil {
 					failed[src] = cmdx.PrintOpenAPIError(cmd, err)
 				} else {
@@ -96,3 +98,29 @@ Files can contain only a single or an array of identities. The validity of files
 		},
 	}
 }
+
+// importIdentity creates the identity. Identities with an ID, such as the ones
+// written by "export identities", are created using the batch endpoint, as it
+// is the only one keeping the ID.
+func importIdentity(cmd *cobra.Command, c *kratos.APIClient, params kratos.CreateIdentityBody) (*kratos.Identity, error) {
+	if _, ok := params.AdditionalProperties["id"]; !ok {
+		ident, _, err := c.IdentityAPI.CreateIdentity(cmd.Context()).CreateIdentityBody(params).Execute()
+		return ident, err
+	}
+
+	res, _, err := c.IdentityAPI.BatchPatchIdentities(cmd.Context()).PatchIdentitiesBody(kratos.PatchIdentitiesBody{
+		Identities: []kratos.IdentityPatch{{Create: &params}},
+	}).Execute()
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Identities) != 1 {
+		return nil, errors.Errorf("expected one result but got %d", len(res.Identities))
+	}
+	if res.Identities[0].Identity == nil {
+		return nil, errors.Errorf("could not import identity: %v", res.Identities[0].Error)
+	}
+
+	ident, _, err := c.IdentityAPI.GetIdentity(cmd.Context(), *res.Identities[0].Identity).Execute()
+	return ident, err
+}
@@ -5,9 +5,33 @@ package courier
 
 import (
 	"context"
+	"net/http"
 )
 
 type Channel interface {
 	ID() string
 	Dispatch(ctx context.Context, msg Message) error
 }
+
+// ReceiptChannel is a channel whose provider reports whether the dispatched
+// messages reached their recipients.
+type ReceiptChannel interface {
+	Channel
+
+	// DispatchWithProviderMessageID dispatches the message and returns the ID
+	// the provider assigned to it. Delivery receipts refer to this ID.
+	DispatchWithProviderMessageID(ctx context.Context, msg Message) (string, error)
+
+	// ParseDeliveryReceipts authenticates the request of the provider and
+	// returns the delivery receipts it contains. Receipts which do not report
+	// a final delivery status are omitted.
+	ParseDeliveryReceipts(r *http.Request) ([]DeliveryReceipt, error)
+}
+
+// DeliveryReceipt reports the delivery status of a dispatched message.
+type DeliveryReceipt struct {
+	ProviderMessageID string
+	Status            CourierMessageDeliveryStatus
+	// Error describes why the message was not delivered, if the provider told.
+	Error string
+}
@@ -34,6 +34,7 @@ type (
 		QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error)
 		DispatchQueue(ctx context.Context) error
 		DispatchMessage(ctx context.Context, msg Message) error
+		ReceiptChannel(ctx context.Context, name string) (ReceiptChannel, error)
 		UseBackoff(b backoff.BackOff)
 		FailOnDispatchError()
 	}
@@ -48,6 +49,7 @@ type (
 
 	courier struct {
 		deps                        Dependencies
+		defaultWorkerID             string
 		failOnDispatchError         bool
 		backoff                     backoff.BackOff
 		newEmailTemplateFromMessage func(d template.Dependencies, msg Message) (EmailTemplate, error)
@@ -61,11 +63,20 @@ func NewCourier(ctx context.Context, deps Dependencies) (Courier, error) {
 func NewCourierWithCustomTemplates(_ context.Context, deps Dependencies, newEmailTemplateFromMessage func(d template.Dependencies, msg Message) (EmailTemplate, error)) (Courier, error) {
 	return &courier{
 		deps:                        deps,
+		defaultWorkerID:             x.NewWorkerID("courier"),
 		backoff:                     backoff.NewExponentialBackOff(),
 		newEmailTemplateFromMessage: newEmailTemplateFromMessage,
 	}, nil
 }
 
+// workerID returns the configured worker ID, or the random default.
+func (c *courier) workerID(ctx context.Context) string {
+	if id := c.deps.CourierConfig().CourierWorkerID(ctx); id != "" {
+		return id
+	}
+	return c.defaultWorkerID
+}
+
 func (c *courier) FailOnDispatchError() {
 	c.failOnDispatchError = true
 }
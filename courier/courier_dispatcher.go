@@ -4,44 +4,157 @@
 package courier
 
 import (
+	"cmp"
 	"context"
+	"math/rand/v2"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/otelx"
 )
 
-func (c *courier) channels(ctx context.Context, id string) (Channel, error) {
+// namedChannel is a configured channel together with the name which is
+// recorded for its delivery attempts.
+type namedChannel struct {
+	Channel
+	name string
+}
+
+// channels returns the channels configured for the ID in the order in which
+// they should be attempted. Channels are ordered by priority. Channels with
+// the same priority are shuffled according to their weight, so that messages
+// are split between them and each is a fallback for the others.
+func (c *courier) channels(ctx context.Context, id string) ([]namedChannel, error) {
 	cs, err := c.deps.CourierConfig().CourierChannels(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var configured []*config.CourierChannel
 	for _, channel := range cs {
-		if channel.ID != id {
+		if channel.ID == id {
+			configured = append(configured, channel)
+		}
+	}
+	if len(configured) == 0 {
+		return nil, errors.Errorf("no courier channels configured for: %s", id)
+	}
+
+	configured = orderChannels(configured, rand.IntN)
+
+	channels := make([]namedChannel, 0, len(configured))
+	for _, channel := range configured {
+		courierChannel, err := c.newChannel(channel)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, namedChannel{Channel: courierChannel, name: channelName(channel)})
+	}
+
+	return channels, nil
+}
+
+// channelName returns the name which is recorded for the delivery attempts of
+// the channel. It defaults to the channel type.
+func channelName(channel *config.CourierChannel) string {
+	if channel.Name != "" {
+		return channel.Name
+	}
+	return channel.Type
+}
+
+func (c *courier) newChannel(channel *config.CourierChannel) (Channel, error) {
+	switch channel.Type {
+	case "smtp":
+		return NewSMTPChannelWithCustomTemplates(c.deps, channel.SMTPConfig, c.newEmailTemplateFromMessage)
+	case "http":
+		return newHttpChannel(channel.ID, &channel.RequestConfig, c.deps), nil
+	case "twilio":
+		return newTwilioChannel(channel.ID, channelName(channel), channel.TwilioConfig, c.deps), nil
+	case "vonage":
+		return newVonageChannel(channel.ID, channelName(channel), channel.VonageConfig, c.deps), nil
+	case "sns":
+		return newSNSChannel(channel.ID, channelName(channel), channel.SNSConfig, c.deps), nil
+	default:
+		return nil, errors.Errorf("unknown courier channel type: %s", channel.Type)
+	}
+}
+
+// ReceiptChannel returns the configured channel with the name, if its
+// provider reports delivery receipts.
+func (c *courier) ReceiptChannel(ctx context.Context, name string) (ReceiptChannel, error) {
+	cs, err := c.deps.CourierConfig().CourierChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range cs {
+		if channelName(channel) != name {
 			continue
 		}
-		switch channel.Type {
-		case "smtp":
-			courierChannel, err := NewSMTPChannelWithCustomTemplates(c.deps, channel.SMTPConfig, c.newEmailTemplateFromMessage)
-			if err != nil {
-				return nil, err
+
+		courierChannel, err := c.newChannel(channel)
+		if err != nil {
+			return nil, err
+		}
+		if rc, ok := courierChannel.(ReceiptChannel); ok {
+			return rc, nil
+		}
+	}
+
+	return nil, errors.WithStack(herodot.ErrNotFound.WithReasonf("No courier channel named %q accepts delivery receipts.", name))
+}
+
+// orderChannels sorts the channels by priority and shuffles channels with the
+// same priority by weight. The random function must return a number in
+// [0, n).
+func orderChannels(cs []*config.CourierChannel, random func(n int) int) []*config.CourierChannel {
+	ordered := slices.Clone(cs)
+	slices.SortStableFunc(ordered, func(a, b *config.CourierChannel) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+
+	weight := func(c *config.CourierChannel) int {
+		return max(c.Weight, 1)
+	}
+
+	for start := 0; start < len(ordered); {
+		end := start + 1
+		for end < len(ordered) && ordered[end].Priority == ordered[start].Priority {
+			end++
+		}
+
+		// Weighted random selection without replacement.
+		for i := start; i < end-1; i++ {
+			total := 0
+			for _, c := range ordered[i:end] {
+				total += weight(c)
+			}
+			pick := random(total)
+			for j := i; j < end; j++ {
+				if pick -= weight(ordered[j]); pick < 0 {
+					ordered[i], ordered[j] = ordered[j], ordered[i]
+					break
+				}
 			}
-			return courierChannel, nil
-		case "http":
-			return newHttpChannel(channel.ID, &channel.RequestConfig, c.deps), nil
-		default:
-			return nil, errors.Errorf("unknown courier channel type: %s", channel.Type)
 		}
+
+		start = end
 	}
 
-	return nil, errors.Errorf("no courier channels configured for: %s", id)
+	return ordered
 }
 
+// DispatchMessage attempts the channels configured for the message until one
+// of them delivers it. Every attempt is recorded as a dispatch of the message.
+// The message must be claimed by the worker, see Persister.NextMessages.
 func (c *courier) DispatchMessage(ctx context.Context, msg Message) (err error) {
 	ctx, span := c.deps.Tracer(ctx).Tracer().Start(ctx, "courier.DispatchMessage", trace.WithAttributes(
 		attribute.Stringer("message.id", msg.ID),
@@ -60,37 +173,88 @@ func (c *courier) DispatchMessage(ctx context.Context, msg Message) (err error)
 		WithField("message_subject", msg.Subject).
 		WithField("trace_id", span.SpanContext().TraceID())
 
-	if err := c.deps.CourierPersister().IncrementMessageSendCount(ctx, msg.ID); err != nil {
+	if err := c.deps.CourierPersister().IncrementMessageSendCount(ctx, msg.ID, msg.WorkerID.String()); err != nil {
 		logger.
 			WithError(err).
 			Error(`Unable to increment the message's "send_count" field`)
 		return err
 	}
 
-	channel, err := c.channels(ctx, msg.Channel.String())
+	channels, err := c.channels(ctx, msg.Channel.String())
 	if err != nil {
+		if recordErr := c.recordDispatch(ctx, msg, "", "", err); recordErr != nil {
+			return recordErr
+		}
 		return err
 	}
 
-	span.SetAttributes(attribute.String("channel.id", channel.ID()))
-	logger = logger.
-		WithField("channel", channel.ID())
+	for _, channel := range channels {
+		logger := logger.
+			WithField("channel", channel.ID()).
+			WithField("channel_name", channel.name)
 
-	if err := channel.Dispatch(ctx, msg); err != nil {
-		return err
-	}
-	span.AddEvent(events.NewCourierMessageDispatched(ctx, msg.ID, msg.Channel.String(), string(msg.TemplateType)))
+		span.AddEvent("courier.DispatchMessage.Attempt", trace.WithAttributes(attribute.String("channel.name", channel.name)))
+		var providerMessageID string
+		if rc, ok := channel.Channel.(ReceiptChannel); ok {
+			providerMessageID, err = rc.DispatchWithProviderMessageID(ctx, msg)
+		} else {
+			err = channel.Dispatch(ctx, msg)
+		}
+		if err != nil {
+			logger.
+				WithError(err).
+				Warn(`Unable to dispatch message.`)
+			if recordErr := c.recordDispatch(ctx, msg, channel.name, "", err); recordErr != nil {
+				return recordErr
+			}
+			continue
+		}
 
-	if err := c.deps.CourierPersister().SetMessageStatus(ctx, msg.ID, MessageStatusSent); err != nil {
-		logger.
-			WithError(err).
-			Error(`Unable to set the message status to "sent".`)
-		return err
+		span.SetAttributes(
+			attribute.String("channel.id", channel.ID()),
+			attribute.String("channel.name", channel.name),
+		)
+		events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageDispatched(ctx, msg.ID, msg.Channel.String(), string(msg.TemplateType)))
+
+		if err := c.deps.CourierPersister().SetMessageStatus(ctx, msg.ID, msg.WorkerID.String(), MessageStatusSent); err != nil {
+			logger.
+				WithError(err).
+				Error(`Unable to set the message status to "sent".`)
+			return err
+		}
+
+		if err := c.recordDispatch(ctx, msg, channel.name, providerMessageID, nil); err != nil {
+			// continue with execution, as the message was successfully dispatched
+			logger.WithError(err).Error(`Unable to record success log entry.`)
+		}
+
+		dispatchDuration := time.Since(msg.CreatedAt).Milliseconds()
+		logger.WithField("dispatch_duration_ms", dispatchDuration).Debug("Courier sent out message.")
+
+		return nil
 	}
 
-	dispatchDuration := time.Since(msg.CreatedAt).Milliseconds()
-	logger.WithField("dispatch_duration_ms", dispatchDuration).Debug("Courier sent out message.")
+	// All channels failed, err is the error of the last one.
+	return err
+}
 
+// recordDispatch records an attempt of delivering the message. Errors are only
+// returned if the courier should fail on dispatch errors.
+func (c *courier) recordDispatch(ctx context.Context, msg Message, channel string, providerMessageID string, dispatchErr error) error {
+	status := CourierMessageDispatchStatusSuccess
+	if dispatchErr != nil {
+		status = CourierMessageDispatchStatusFailed
+	}
+
+	if err := c.deps.CourierPersister().RecordDispatch(ctx, msg.ID, channel, providerMessageID, status, dispatchErr); err != nil {
+		c.deps.Logger().
+			WithError(err).
+			WithField("message_id", msg.ID).
+			Error(`Unable to record dispatch log entry.`)
+		if c.failOnDispatchError {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -99,9 +263,11 @@ func (c *courier) DispatchQueue(ctx context.Context) (err error) {
 	defer otelx.End(span, &err)
 	maxRetries := c.deps.CourierConfig().CourierMessageRetries(ctx)
 	pullCount := c.deps.CourierConfig().CourierWorkerPullCount(ctx)
+	workerID := c.workerID(ctx)
+	lease := c.deps.CourierConfig().CourierWorkerLease(ctx)
 
 	//nolint:gosec // disable G115
-	messages, err := c.deps.CourierPersister().NextMessages(ctx, uint8(pullCount))
+	messages, err := c.deps.CourierPersister().NextMessages(ctx, uint8(pullCount), workerID, lease)
 	if err != nil {
 		if errors.Is(err, ErrQueueEmpty) {
 			return nil
@@ -118,15 +284,41 @@ func (c *courier) DispatchQueue(ctx context.Context) (err error) {
 			WithField("message_template_type", msg.TemplateType).
 			WithField("message_subject", msg.Subject)
 
-		if msg.SendCount > maxRetries {
-			if err := c.deps.CourierPersister().SetMessageStatus(ctx, msg.ID, MessageStatusAbandoned); err != nil {
+		// The lease is renewed for every message, as dispatching the previous
+		// ones may have taken up most of it.
+		if err := c.deps.CourierPersister().RenewMessageLease(ctx, msg.ID, workerID, lease); errors.Is(err, ErrLeaseLost) {
+			logger.Warn(`Skipping the message because it was claimed by another worker.`)
+			continue
+		} else if err != nil {
+			logger.
+				WithError(err).
+				Error(`Unable to renew the message's lease.`)
+			return err
+		}
+
+		if expiresAt := time.Time(msg.ExpiresAt); !expiresAt.IsZero() && time.Now().After(expiresAt) {
+			if err := c.deps.CourierPersister().SetMessageStatus(ctx, msg.ID, workerID, MessageStatusExpired); err != nil {
+				logger.
+					WithError(err).
+					Error(`Unable to set the expired message's status to "expired".`)
+				return err
+			}
+
+			events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageExpired(ctx, msg.ID, msg.Channel.String(), string(msg.TemplateType)))
+
+			// Skip the message
+			logger.
+				WithField("message_expires_at", expiresAt).
+				Warn(`Message was not dispatched because it expired`)
+		} else if msg.SendCount > maxRetries {
+			if err := c.deps.CourierPersister().SetMessageStatus(ctx, msg.ID, workerID, MessageStatusAbandoned); err != nil {
 				logger.
 					WithError(err).
 					Error(`Unable to set the retried message's status to "abandoned".`)
 				return err
 			}
 
-			span.AddEvent(events.NewCourierMessageAbandoned(ctx, msg.ID, msg.Channel.String(), string(msg.TemplateType)))
+			events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageAbandoned(ctx, msg.ID, msg.Channel.String(), string(msg.TemplateType)))
 
 			// Skip the message
 			logger.
@@ -135,17 +327,20 @@ func (c *courier) DispatchQueue(ctx context.Context) (err error) {
 			logger.
 				WithError(err).
 				Warn(`Unable to dispatch message.`)
-			if err := c.deps.CourierPersister().RecordDispatch(ctx, msg.ID, CourierMessageDispatchStatusFailed, err); err != nil {
-				logger.
-					WithError(err).
-					Error(`Unable to record failure log entry.`)
-				if c.failOnDispatchError {
-					return err
-				}
+
+			// If the worker stops here, the remaining messages are queued
+			// again as well, instead of waiting for their lease to expire.
+			release := messages[k : k+1]
+			if c.failOnDispatchError {
+				release = messages[k:]
 			}
 
-			for _, replace := range messages[k:] {
-				if err := c.deps.CourierPersister().SetMessageStatus(ctx, replace.ID, MessageStatusQueued); err != nil {
+			for _, replace := range release {
+				if err := c.deps.CourierPersister().SetMessageStatus(ctx, replace.ID, workerID, MessageStatusQueued); errors.Is(err, ErrLeaseLost) {
+					// The message was abandoned by the channel or claimed by
+					// another worker.
+					continue
+				} else if err != nil {
 					logger.
 						WithError(err).
 						Error(`Unable to reset the failed message's status to "queued".`)
@@ -158,11 +353,6 @@ func (c *courier) DispatchQueue(ctx context.Context) (err error) {
 			if c.failOnDispatchError {
 				return err
 			}
-		} else if err := c.deps.CourierPersister().RecordDispatch(ctx, msg.ID, CourierMessageDispatchStatusSuccess, nil); err != nil {
-			logger.
-				WithError(err).
-				Error(`Unable to record success log entry.`)
-			// continue with execution, as the message was successfully dispatched
 		}
 	}
 
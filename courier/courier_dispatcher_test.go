@@ -4,7 +4,14 @@
 package courier_test
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
@@ -41,17 +48,20 @@ func TestDispatchMessageWithInvalidSMTP(t *testing.T) {
 
 	t.Run("case=failed sending", func(t *testing.T) {
 		id := queueNewMessage(t, c)
-		message, err := reg.CourierPersister().LatestQueuedMessage(t.Context())
+		messages, err := reg.CourierPersister().NextMessages(t.Context(), 10, "worker-0", time.Minute)
 		require.NoError(t, err)
-		require.Equal(t, id, message.ID)
+		require.Len(t, messages, 1)
+		require.Equal(t, id, messages[0].ID)
 
-		err = c.DispatchMessage(t.Context(), *message)
+		err = c.DispatchMessage(t.Context(), messages[0])
 		// sending the email fails, because there is no SMTP server at foo.url
 		require.Error(t, err)
+		require.NotErrorIs(t, err, courier.ErrLeaseLost)
 
-		messages, err := reg.CourierPersister().NextMessages(t.Context(), 10)
+		actual, err := reg.CourierPersister().FetchMessage(t.Context(), id)
 		require.NoError(t, err)
-		require.Len(t, messages, 1)
+		assert.Equal(t, courier.MessageStatusProcessing, actual.Status)
+		assert.Equal(t, 1, actual.SendCount)
 	})
 }
 
@@ -74,7 +84,10 @@ func TestDispatchMessage(t *testing.T) {
 			TemplateType: "stub",
 		}
 		require.NoError(t, reg.CourierPersister().AddMessage(t.Context(), &message))
-		assert.ErrorContains(t, c.DispatchMessage(t.Context(), message), "no courier channels configured for: invalid-channel")
+		messages, err := reg.CourierPersister().NextMessages(t.Context(), 1, "worker-0", time.Minute)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.ErrorContains(t, c.DispatchMessage(t.Context(), messages[0]), "no courier channels configured for: invalid-channel")
 	})
 }
 
@@ -113,3 +126,147 @@ func TestDispatchQueue(t *testing.T) {
 	require.Contains(t, gjson.GetBytes(message.Dispatches[0].Error, "reason").String(), "failed to send email via smtp")
 	require.Contains(t, gjson.GetBytes(message.Dispatches[1].Error, "reason").String(), "failed to send email via smtp")
 }
+
+func TestDispatchQueueWorkerID(t *testing.T) {
+	ctx := t.Context()
+	_, reg := internal.NewRegistryDefaultWithDSN(t, "", configx.WithValues(map[string]any{
+		config.ViperKeyCourierMessageRetries: 5,
+		config.ViperKeyCourierWorkerLease:    "1h",
+	}))
+
+	c, err := reg.Courier(ctx)
+	require.NoError(t, err)
+
+	t.Run("case=uses random worker id by default", func(t *testing.T) {
+		id := queueNewMessage(t, c)
+		require.NoError(t, c.DispatchQueue(ctx))
+
+		message, err := reg.CourierPersister().FetchMessage(ctx, id)
+		require.NoError(t, err)
+		hostname, _ := os.Hostname()
+		assert.True(t, strings.HasPrefix(message.WorkerID.String(), hostname+"-"), message.WorkerID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), time.Time(message.LeaseExpiresAt), time.Minute)
+	})
+
+	t.Run("case=uses configured worker id", func(t *testing.T) {
+		reg.Config().MustSet(ctx, config.ViperKeyCourierWorkerID, "courier-1")
+		t.Cleanup(func() { reg.Config().MustSet(ctx, config.ViperKeyCourierWorkerID, "") })
+
+		id := queueNewMessage(t, c)
+		require.NoError(t, c.DispatchQueue(ctx))
+
+		message, err := reg.CourierPersister().FetchMessage(ctx, id)
+		require.NoError(t, err)
+		assert.EqualValues(t, "courier-1", message.WorkerID)
+	})
+}
+
+func newChannelServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	var received atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &received
+}
+
+func emailChannel(name, url string, priority, weight int) string {
+	return fmt.Sprintf(`{
+		"id": "email",
+		"name": %q,
+		"type": "http",
+		"priority": %d,
+		"weight": %d,
+		"request_config": {
+			"url": %q,
+			"method": "POST",
+			"body": "file://./stub/request.config.mailer.jsonnet"
+		}
+	}`, name, priority, weight, url)
+}
+
+func TestDispatchMessageFailover(t *testing.T) {
+	primary, primaryReceived := newChannelServer(t, http.StatusInternalServerError)
+	secondary, secondaryReceived := newChannelServer(t, http.StatusOK)
+
+	_, reg := internal.NewRegistryDefaultWithDSN(t, "", configx.WithValues(map[string]any{
+		config.ViperKeyCourierMessageRetries: 1,
+		config.ViperKeyCourierChannels: fmt.Sprintf("[%s, %s]",
+			emailChannel("secondary", secondary.URL, 1, 1),
+			emailChannel("primary", primary.URL, 0, 1)),
+	}))
+
+	c, err := reg.Courier(t.Context())
+	require.NoError(t, err)
+
+	t.Run("case=falls back to the next channel", func(t *testing.T) {
+		id := queueNewMessage(t, c)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		assert.EqualValues(t, 1, primaryReceived.Load())
+		assert.EqualValues(t, 1, secondaryReceived.Load())
+
+		message, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusSent, message.Status)
+
+		// Dispatches are ordered newest first.
+		require.Len(t, message.Dispatches, 2)
+		assert.Equal(t, "secondary", message.Dispatches[0].Channel.String())
+		assert.Equal(t, courier.CourierMessageDispatchStatusSuccess, message.Dispatches[0].Status)
+		assert.Equal(t, "primary", message.Dispatches[1].Channel.String())
+		assert.Equal(t, courier.CourierMessageDispatchStatusFailed, message.Dispatches[1].Status)
+	})
+
+	t.Run("case=queues message again if all channels fail", func(t *testing.T) {
+		primaryReceived.Store(0)
+		secondaryReceived.Store(0)
+		secondary.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secondaryReceived.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		id := queueNewMessage(t, c)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		assert.EqualValues(t, 1, primaryReceived.Load())
+		assert.EqualValues(t, 1, secondaryReceived.Load())
+
+		message, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusQueued, message.Status)
+		require.Len(t, message.Dispatches, 2)
+		for _, d := range message.Dispatches {
+			assert.Equal(t, courier.CourierMessageDispatchStatusFailed, d.Status)
+		}
+	})
+}
+
+func TestDispatchMessageWeightedRouting(t *testing.T) {
+	heavy, heavyReceived := newChannelServer(t, http.StatusOK)
+	light, lightReceived := newChannelServer(t, http.StatusOK)
+
+	_, reg := internal.NewRegistryDefaultWithDSN(t, "", configx.WithValues(map[string]any{
+		config.ViperKeyCourierWorkerPullCount: 100,
+		config.ViperKeyCourierChannels: fmt.Sprintf("[%s, %s]",
+			emailChannel("heavy", heavy.URL, 0, 3),
+			emailChannel("light", light.URL, 0, 1)),
+	}))
+
+	c, err := reg.Courier(t.Context())
+	require.NoError(t, err)
+
+	const messages = 200
+	for range messages {
+		queueNewMessage(t, c)
+	}
+	for range messages / 100 {
+		require.NoError(t, c.DispatchQueue(t.Context()))
+	}
+
+	require.EqualValues(t, messages, heavyReceived.Load()+lightReceived.Load(), "every message is delivered exactly once")
+	// The heavy channel receives 150 messages on average, the bounds are more
+	// than six standard deviations away.
+	assert.InDelta(t, 150, heavyReceived.Load(), 40)
+}
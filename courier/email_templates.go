@@ -32,6 +32,10 @@ type (
 	RequestHeadersCarrier interface {
 		RequestHeaders() http.Header
 	}
+
+	LocaleCarrier interface {
+		Locale() string
+	}
 )
 
 func NewEmailTemplateFromMessage(d template.Dependencies, msg Message) (EmailTemplate, error) {
@@ -102,6 +106,13 @@ func NewEmailTemplateFromMessage(d template.Dependencies, msg Message) (EmailTem
 			return nil, err
 		}
 		return email.NewRegistrationCodeValid(d, &t), nil
+	case template.TypePasswordChanged, template.TypeMFAMethodAdded, template.TypeMFAMethodRemoved,
+		template.TypeOIDCProviderLinked, template.TypeSessionsRevoked, template.TypeNewDeviceLogin, template.TypeAccountDeleted:
+		var t email.SecurityNotificationModel
+		if err := json.Unmarshal(msg.TemplateData, &t); err != nil {
+			return nil, err
+		}
+		return email.NewSecurityNotification(d, msg.TemplateType, &t), nil
 	default:
 		return nil, errors.Errorf("received unexpected message template type: %s", msg.TemplateType)
 	}
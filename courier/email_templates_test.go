@@ -33,6 +33,9 @@ func TestNewEmailTemplateFromMessage(t *testing.T) {
 		template.TypeTestStub:                email.NewTestStub(&email.TestStubModel{To: "far", Subject: "test subject", Body: "test body"}),
 		template.TypeLoginCodeValid:          email.NewLoginCodeValid(reg, &email.LoginCodeValidModel{To: "far", LoginCode: "123456"}),
 		template.TypeRegistrationCodeValid:   email.NewRegistrationCodeValid(reg, &email.RegistrationCodeValidModel{To: "far", RegistrationCode: "123456"}),
+		template.TypePasswordChanged:         email.NewSecurityNotification(reg, template.TypePasswordChanged, &email.SecurityNotificationModel{To: "far"}),
+		template.TypeNewDeviceLogin:          email.NewSecurityNotification(reg, template.TypeNewDeviceLogin, &email.SecurityNotificationModel{To: "far", Device: &email.SecurityNotificationDevice{UserAgent: "agent"}}),
+		template.TypeAccountDeleted:          email.NewSecurityNotification(reg, template.TypeAccountDeleted, &email.SecurityNotificationModel{To: "far"}),
 	} {
 		t.Run(fmt.Sprintf("case=%s", tmplType), func(t *testing.T) {
 			tmplData, err := json.Marshal(expectedTmpl)
@@ -5,23 +5,37 @@ package courier
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 
 	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/kratos/x/redir"
 	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/jsonx"
 	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/sqlcon"
 )
 
 const (
 	AdminRouteCourier      = "/courier"
 	AdminRouteListMessages = AdminRouteCourier + "/messages"
 	AdminRouteGetMessage   = AdminRouteCourier + "/messages/{msgID}"
+
+	AdminRouteRetryMessage    = AdminRouteGetMessage + "/retry"
+	AdminRouteCancelMessage   = AdminRouteGetMessage + "/cancel"
+	AdminRouteRequeueMessages = AdminRouteListMessages + "/requeue"
+
+	AdminRouteListSuppressions = AdminRouteCourier + "/suppressions"
+	AdminRouteSuppression      = AdminRouteListSuppressions + "/{suppressionID}"
+
+	RouteDeliveryReceipts = "/courier/channels/{channel}/receipts"
 )
 
 type (
@@ -30,6 +44,7 @@ type (
 		x.LoggingProvider
 		nosurfx.CSRFProvider
 		PersistenceProvider
+		Provider
 		config.Provider
 	}
 	Handler struct {
@@ -45,14 +60,41 @@ func NewHandler(r handlerDependencies) *Handler {
 }
 
 func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
-	h.r.CSRFHandler().IgnoreGlobs(httprouterx.AdminPrefix+AdminRouteListMessages, AdminRouteListMessages)
+	h.r.CSRFHandler().IgnoreGlobs(
+		httprouterx.AdminPrefix+AdminRouteListMessages,
+		AdminRouteListMessages,
+		httprouterx.AdminPrefix+AdminRouteListMessages+"/*",
+		AdminRouteListMessages+"/*",
+		httprouterx.AdminPrefix+AdminRouteListMessages+"/*/*",
+		AdminRouteListMessages+"/*/*",
+		httprouterx.AdminPrefix+AdminRouteListSuppressions,
+		AdminRouteListSuppressions,
+		httprouterx.AdminPrefix+AdminRouteListSuppressions+"/*",
+		AdminRouteListSuppressions+"/*",
+		strings.ReplaceAll(RouteDeliveryReceipts, "{channel}", "*"),
+	)
 	public.GET(httprouterx.AdminPrefix+AdminRouteListMessages, redir.RedirectToAdminRoute(h.r))
 	public.GET(httprouterx.AdminPrefix+AdminRouteGetMessage, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteRetryMessage, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteCancelMessage, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteRequeueMessages, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+AdminRouteListSuppressions, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteListSuppressions, redir.RedirectToAdminRoute(h.r))
+	public.DELETE(httprouterx.AdminPrefix+AdminRouteSuppression, redir.RedirectToAdminRoute(h.r))
+
+	public.GET(RouteDeliveryReceipts, h.receiveDeliveryReceipts)
+	public.POST(RouteDeliveryReceipts, h.receiveDeliveryReceipts)
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.GET(AdminRouteListMessages, h.listCourierMessages)
 	admin.GET(AdminRouteGetMessage, h.getCourierMessage)
+	admin.POST(AdminRouteRetryMessage, h.retryCourierMessage)
+	admin.POST(AdminRouteCancelMessage, h.cancelCourierMessage)
+	admin.POST(AdminRouteRequeueMessages, h.requeueCourierMessages)
+	admin.GET(AdminRouteListSuppressions, h.listCourierSuppressions)
+	admin.POST(AdminRouteListSuppressions, h.createCourierSuppression)
+	admin.DELETE(AdminRouteSuppression, h.deleteCourierSuppression)
 }
 
 // Paginated Courier Message List Response
@@ -89,6 +131,14 @@ type ListCourierMessagesParameters struct {
 	// required: false
 	// in: query
 	Recipient string `json:"recipient"`
+
+	// TemplateType filters out messages based on the template type, for
+	// example `recovery_code_valid`.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	TemplateType template.TemplateType `json:"template_type"`
 }
 
 // swagger:route GET /admin/courier/messages courier listCourierMessages
@@ -152,8 +202,9 @@ func parseMessagesFilter(r *http.Request, keys [][32]byte) (ListCourierMessagesP
 	}
 
 	return ListCourierMessagesParameters{
-		Status:    status,
-		Recipient: r.URL.Query().Get("recipient"),
+		Status:       status,
+		Recipient:    r.URL.Query().Get("recipient"),
+		TemplateType: template.TemplateType(r.URL.Query().Get("template_type")),
 	}, opts, nil
 }
 
@@ -202,6 +253,484 @@ func (h *Handler) getCourierMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.writeMessage(w, r, message)
+}
+
+// Retry Courier Message Parameters
+//
+// swagger:parameters retryCourierMessage
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type retryCourierMessage struct {
+	// MessageID is the ID of the message.
+	//
+	// required: true
+	// in: path
+	MessageID string `json:"id"`
+}
+
+// swagger:route POST /admin/courier/messages/{id}/retry courier retryCourierMessage
+//
+// # Retry a Message
+//
+// Queues an abandoned, cancelled, or sent message again and resets its send
+// count. The message is then delivered by the next courier worker.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: message
+//		400: errorGeneric
+//		404: errorGeneric
+//		409: errorGeneric
+//		default: errorGeneric
+func (h *Handler) retryCourierMessage(w http.ResponseWriter, r *http.Request) {
+	msgID, err := uuid.FromString(r.PathValue("msgID"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, herodot.ErrBadRequest.WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", r.PathValue("msgID")))
+		return
+	}
+
+	message, err := RetryMessage(r.Context(), h.r, msgID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.writeMessage(w, r, message)
+}
+
+// Cancel Courier Message Parameters
+//
+// swagger:parameters cancelCourierMessage
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type cancelCourierMessage struct {
+	// MessageID is the ID of the message.
+	//
+	// required: true
+	// in: path
+	MessageID string `json:"id"`
+}
+
+// swagger:route POST /admin/courier/messages/{id}/cancel courier cancelCourierMessage
+//
+// # Cancel a Message
+//
+// Cancels a queued message, so that it is not delivered. Cancelled messages
+// can be retried.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: message
+//		400: errorGeneric
+//		404: errorGeneric
+//		409: errorGeneric
+//		default: errorGeneric
+func (h *Handler) cancelCourierMessage(w http.ResponseWriter, r *http.Request) {
+	msgID, err := uuid.FromString(r.PathValue("msgID"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, herodot.ErrBadRequest.WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", r.PathValue("msgID")))
+		return
+	}
+
+	message, err := CancelMessage(r.Context(), h.r, msgID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.writeMessage(w, r, message)
+}
+
+// Requeue Courier Messages Request Body
+//
+// swagger:model requeueCourierMessagesBody
+type RequeueCourierMessagesBody struct {
+	// Status selects the messages to requeue. Must be one of `abandoned`,
+	// `cancelled`, or `sent`.
+	//
+	// required: true
+	Status MessageStatus `json:"status"`
+
+	// Recipient only requeues messages sent to this recipient.
+	Recipient string `json:"recipient"`
+
+	// TemplateType only requeues messages with this template type, for example
+	// `recovery_code_valid`.
+	TemplateType template.TemplateType `json:"template_type"`
+}
+
+// Requeue Courier Messages Parameters
+//
+// swagger:parameters requeueCourierMessages
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type requeueCourierMessages struct {
+	// in: body
+	Body RequeueCourierMessagesBody
+}
+
+// Requeue Courier Messages Response
+//
+// swagger:model requeueCourierMessagesResponse
+type RequeueCourierMessagesResponse struct {
+	// Count is the number of messages which were queued again.
+	//
+	// required: true
+	Count int `json:"count"`
+}
+
+// swagger:route POST /admin/courier/messages/requeue courier requeueCourierMessages
+//
+// # Requeue Messages
+//
+// Queues all messages matching the filter again and resets their send count.
+// This is useful to resend messages after an outage of the email or SMS
+// provider.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: requeueCourierMessagesResponse
+//		400: errorGeneric
+//		default: errorGeneric
+func (h *Handler) requeueCourierMessages(w http.ResponseWriter, r *http.Request) {
+	var body RequeueCourierMessagesBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error())))
+		return
+	}
+
+	count, err := RequeueMessages(r.Context(), h.r, ListCourierMessagesParameters{
+		Status:       &body.Status,
+		Recipient:    body.Recipient,
+		TemplateType: body.TemplateType,
+	})
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, &RequeueCourierMessagesResponse{Count: count})
+}
+
+// Paginated Courier Suppression List Response
+//
+// swagger:response listCourierSuppressions
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listCourierSuppressionsResponse struct {
+	keysetpagination.ResponseHeaders
+
+	// List of suppressions
+	//
+	// in:body
+	Body []Suppression
+}
+
+// Paginated List Courier Suppression Parameters
+//
+// swagger:parameters listCourierSuppressions
+type ListCourierSuppressionsParameters struct {
+	keysetpagination.RequestParameters
+
+	// Recipient filters out suppressions based on recipient.
+	// If no value is provided, it doesn't take effect on filter.
+	//
+	// required: false
+	// in: query
+	Recipient string `json:"recipient"`
+}
+
+// swagger:route GET /admin/courier/suppressions courier listCourierSuppressions
+//
+// # List Suppressions
+//
+// Lists the recipients on the suppression list. Messages to these recipients
+// are not delivered.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: listCourierSuppressions
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listCourierSuppressions(w http.ResponseWriter, r *http.Request) {
+	keys := h.r.Config().SecretsPagination(r.Context())
+	opts, err := keysetpagination.ParseQueryParams(keys, r.URL.Query())
+	if err != nil {
+		h.r.Writer().WriteErrorCode(w, r, http.StatusBadRequest, errors.WithStack(err))
+		return
+	}
+
+	l, nextPage, err := h.r.CourierPersister().ListSuppressions(r.Context(), ListCourierSuppressionsParameters{
+		Recipient: r.URL.Query().Get("recipient"),
+	}, opts)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	u := *r.URL
+	keysetpagination.SetLinkHeader(w, keys, &u, nextPage)
+	h.r.Writer().Write(w, r, l)
+}
+
+// Create Courier Suppression Request Body
+//
+// swagger:model createCourierSuppressionBody
+type CreateCourierSuppressionBody struct {
+	// Recipient is the email address or phone number to suppress.
+	//
+	// required: true
+	Recipient string `json:"recipient"`
+
+	// Reason is why the recipient is suppressed. Must be one of `bounced`,
+	// `complained`, or `manual`.
+	//
+	// required: true
+	Reason SuppressionReason `json:"reason"`
+}
+
+// Create Courier Suppression Parameters
+//
+// swagger:parameters createCourierSuppression
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createCourierSuppression struct {
+	// in: body
+	Body CreateCourierSuppressionBody
+}
+
+// swagger:route POST /admin/courier/suppressions courier createCourierSuppression
+//
+// # Suppress a Recipient
+//
+// Adds a recipient to the suppression list, for example because messages to
+// the address bounced. Messages queued for the recipient afterwards are stored
+// with the status `suppressed` and are not delivered.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		201: courierSuppression
+//		400: errorGeneric
+//		409: errorGeneric
+//		default: errorGeneric
+func (h *Handler) createCourierSuppression(w http.ResponseWriter, r *http.Request) {
+	var body CreateCourierSuppressionBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error())))
+		return
+	}
+
+	if NormalizeRecipient(body.Recipient) == "" {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("The recipient must not be empty.")))
+		return
+	}
+	if err := body.Reason.IsValid(); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	s := &Suppression{Recipient: body.Recipient, Reason: body.Reason}
+	if err := h.r.CourierPersister().CreateSuppression(r.Context(), s); err != nil {
+		if errors.Is(err, sqlcon.ErrUniqueViolation) {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict.WithReason("The recipient is suppressed already.")))
+		} else {
+			h.r.Writer().WriteError(w, r, err)
+		}
+		return
+	}
+
+	h.r.Writer().WriteCode(w, r, http.StatusCreated, s)
+}
+
+// Delete Courier Suppression Parameters
+//
+// swagger:parameters deleteCourierSuppression
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type deleteCourierSuppression struct {
+	// ID is the ID of the suppression.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route DELETE /admin/courier/suppressions/{id} courier deleteCourierSuppression
+//
+// # Remove a Suppression
+//
+// Removes a recipient from the suppression list, so that messages queued for
+// the recipient afterwards are delivered again. Messages which were suppressed
+// already are not delivered.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		204: emptyResponse
+//		400: errorGeneric
+//		404: errorGeneric
+//		default: errorGeneric
+func (h *Handler) deleteCourierSuppression(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.FromString(r.PathValue("suppressionID"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, herodot.ErrBadRequest.WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", r.PathValue("suppressionID")))
+		return
+	}
+
+	if err := h.r.CourierPersister().DeleteSuppression(r.Context(), id); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Receive Courier Delivery Receipts Parameters
+//
+// swagger:parameters receiveCourierDeliveryReceipts
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type receiveCourierDeliveryReceipts struct {
+	// Channel is the name of the courier channel.
+	//
+	// required: true
+	// in: path
+	Channel string `json:"channel"`
+
+	// Token authenticates delivery receipts of channels which do not sign
+	// their requests. It must equal the delivery receipt secret of the
+	// channel.
+	//
+	// in: query
+	Token string `json:"token"`
+}
+
+// swagger:route POST /courier/channels/{channel}/receipts courier receiveCourierDeliveryReceipts
+//
+// # Receive Delivery Receipts
+//
+// Receives the delivery status callbacks of the SMS provider of a courier
+// channel and records whether the messages reached their recipients. The
+// provider calls this endpoint, the request format depends on the channel
+// type.
+//
+//	Consumes:
+//	- application/json
+//	- application/x-www-form-urlencoded
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		204: emptyResponse
+//		400: errorGeneric
+//		403: errorGeneric
+//		404: errorGeneric
+//		default: errorGeneric
+func (h *Handler) receiveDeliveryReceipts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("channel")
+
+	c, err := h.r.Courier(ctx)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	channel, err := c.ReceiptChannel(ctx, name)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	receipts, err := channel.ParseDeliveryReceipts(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	for _, receipt := range receipts {
+		var deliveryErr error
+		if receipt.Error != "" {
+			deliveryErr = errors.New(receipt.Error)
+		}
+
+		dispatch, err := h.r.CourierPersister().UpdateDispatchDeliveryStatus(ctx, name, receipt.ProviderMessageID, receipt.Status, deliveryErr)
+		if errors.Is(err, sqlcon.ErrNoRows) {
+			// The provider retries receipts which are not acknowledged, so
+			// receipts of unknown messages are acknowledged and dropped.
+			h.r.Logger().
+				WithRequest(r).
+				WithField("channel_name", name).
+				WithField("provider_message_id", receipt.ProviderMessageID).
+				Info("Ignoring delivery receipt of an unknown message.")
+			continue
+		} else if err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+
+		events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageDelivery(ctx, dispatch.MessageID, name, string(receipt.Status)))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeMessage(w http.ResponseWriter, r *http.Request, message *Message) {
 	if !h.r.Config().IsInsecureDevMode(r.Context()) {
 		message.Body = "<redacted-unless-dev-mode>"
 	}
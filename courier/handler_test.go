@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -19,6 +20,7 @@ import (
 	"github.com/tidwall/gjson"
 
 	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/internal/testhelpers"
@@ -108,7 +110,7 @@ func TestHandler(t *testing.T) {
 			require.NoError(t, reg.CourierPersister().AddMessage(context.Background(), &messages[i]))
 		}
 		for i := range procCount {
-			require.NoError(t, reg.CourierPersister().SetMessageStatus(context.Background(), messages[i].ID, courier.MessageStatusProcessing))
+			require.NoError(t, reg.Persister().GetConnection(context.Background()).RawQuery("UPDATE courier_messages SET status = ? WHERE id = ?", courier.MessageStatusProcessing, messages[i].ID).Exec())
 		}
 
 		t.Run("paging", func(t *testing.T) {
@@ -225,7 +227,7 @@ func TestHandler(t *testing.T) {
 		message.Type = courier.MessageTypeEmail
 		message.Body = "body content"
 		require.NoError(t, reg.CourierPersister().AddMessage(context.Background(), &message))
-		require.NoError(t, reg.CourierPersister().RecordDispatch(ctx, message.ID, courier.CourierMessageDispatchStatusSuccess, errors.New("some error")))
+		require.NoError(t, reg.CourierPersister().RecordDispatch(ctx, message.ID, "smtp", "", courier.CourierMessageDispatchStatusSuccess, errors.New("some error")))
 
 		getCourierMessag := func(s *httptest.Server, id string) gjson.Result {
 
@@ -278,4 +280,183 @@ func TestHandler(t *testing.T) {
 			}
 		})
 	})
+
+	post := func(t *testing.T, base *httptest.Server, href string, body string, expectCode int) gjson.Result {
+		t.Helper()
+		res, err := base.Client().Post(base.URL+href, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		assert.EqualValuesf(t, expectCode, res.StatusCode, "%s", resBody)
+		return gjson.ParseBytes(resBody)
+	}
+
+	addMessage := func(t *testing.T, status courier.MessageStatus, templateType template.TemplateType) courier.Message {
+		t.Helper()
+		message := courier.Message{}
+		require.NoError(t, faker.FakeData(&message))
+		message.Type = courier.MessageTypeEmail
+		message.TemplateType = templateType
+		require.NoError(t, reg.CourierPersister().AddMessage(ctx, &message))
+		require.NoError(t, reg.Persister().GetConnection(ctx).RawQuery("UPDATE courier_messages SET status = ?, send_count = 3 WHERE id = ?", status, message.ID).Exec())
+		return message
+	}
+
+	t.Run("handler=retryCourierMessage", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				href := func(id string) string {
+					return httprouterx.AdminPrefix + strings.ReplaceAll(courier.AdminRouteRetryMessage, "{msgID}", id)
+				}
+
+				for _, status := range []courier.MessageStatus{courier.MessageStatusAbandoned, courier.MessageStatusCancelled, courier.MessageStatusSent} {
+					t.Run("status="+status.String(), func(t *testing.T) {
+						message := addMessage(t, status, "stub")
+
+						body := post(t, tc.s, href(message.ID.String()), "", http.StatusOK)
+						assert.Equal(t, message.ID.String(), body.Get("id").String())
+						assert.Equal(t, "queued", body.Get("status").String())
+						assert.EqualValues(t, 0, body.Get("send_count").Int())
+
+						actual, err := reg.CourierPersister().FetchMessage(ctx, message.ID)
+						require.NoError(t, err)
+						assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+						assert.Equal(t, 0, actual.SendCount)
+					})
+				}
+
+				t.Run("case=fails for processing messages", func(t *testing.T) {
+					message := addMessage(t, courier.MessageStatusProcessing, "stub")
+					body := post(t, tc.s, href(message.ID.String()), "", http.StatusConflict)
+					assert.Contains(t, body.Get("error.reason").String(), "the message is processing")
+				})
+
+				t.Run("case=fails for unknown messages", func(t *testing.T) {
+					post(t, tc.s, href(uuid.Nil.String()), "", http.StatusNotFound)
+				})
+
+				t.Run("case=fails for malformed ids", func(t *testing.T) {
+					post(t, tc.s, href("not-a-uuid"), "", http.StatusBadRequest)
+				})
+			})
+		}
+	})
+
+	t.Run("handler=cancelCourierMessage", func(t *testing.T) {
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				href := func(id string) string {
+					return httprouterx.AdminPrefix + strings.ReplaceAll(courier.AdminRouteCancelMessage, "{msgID}", id)
+				}
+
+				t.Run("case=cancels queued messages", func(t *testing.T) {
+					message := addMessage(t, courier.MessageStatusQueued, "stub")
+
+					body := post(t, tc.s, href(message.ID.String()), "", http.StatusOK)
+					assert.Equal(t, "cancelled", body.Get("status").String())
+
+					actual, err := reg.CourierPersister().FetchMessage(ctx, message.ID)
+					require.NoError(t, err)
+					assert.Equal(t, courier.MessageStatusCancelled, actual.Status)
+				})
+
+				t.Run("case=fails for sent messages", func(t *testing.T) {
+					message := addMessage(t, courier.MessageStatusSent, "stub")
+					body := post(t, tc.s, href(message.ID.String()), "", http.StatusConflict)
+					assert.Contains(t, body.Get("error.reason").String(), "the message is sent")
+				})
+			})
+		}
+	})
+
+	t.Run("handler=requeueCourierMessages", func(t *testing.T) {
+		href := httprouterx.AdminPrefix + courier.AdminRouteRequeueMessages
+
+		templateType := template.TemplateType("requeue_" + uuidx.NewV4().String())
+		recipient := "requeue-" + uuidx.NewV4().String() + "@ory.sh"
+		var abandoned []courier.Message
+		for i := range 3 {
+			m := addMessage(t, courier.MessageStatusAbandoned, templateType)
+			if i == 0 {
+				require.NoError(t, reg.Persister().GetConnection(ctx).RawQuery("UPDATE courier_messages SET recipient = ? WHERE id = ?", recipient, m.ID).Exec())
+			}
+			abandoned = append(abandoned, m)
+		}
+		sent := addMessage(t, courier.MessageStatusSent, templateType)
+
+		t.Run("case=requeues messages by recipient", func(t *testing.T) {
+			body := post(t, publicTS, href, fmt.Sprintf(`{"status":"abandoned","template_type":%q,"recipient":%q}`, templateType, recipient), http.StatusOK)
+			assert.EqualValues(t, 1, body.Get("count").Int(), "%s", body.Raw)
+
+			actual, err := reg.CourierPersister().FetchMessage(ctx, abandoned[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+		})
+
+		t.Run("case=requeues messages by template type", func(t *testing.T) {
+			body := post(t, adminTS, courier.AdminRouteRequeueMessages, fmt.Sprintf(`{"status":"abandoned","template_type":%q}`, templateType), http.StatusOK)
+			assert.EqualValues(t, 2, body.Get("count").Int(), "%s", body.Raw)
+
+			for _, m := range abandoned {
+				actual, err := reg.CourierPersister().FetchMessage(ctx, m.ID)
+				require.NoError(t, err)
+				assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+				assert.Equal(t, 0, actual.SendCount)
+			}
+
+			actual, err := reg.CourierPersister().FetchMessage(ctx, sent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusSent, actual.Status)
+		})
+
+		t.Run("case=requires a retriable status", func(t *testing.T) {
+			for _, body := range []string{`{}`, `{"status":"queued"}`, `{"status":"invalid"}`, `{"unknown":"field"}`} {
+				post(t, adminTS, courier.AdminRouteRequeueMessages, body, http.StatusBadRequest)
+			}
+		})
+	})
+
+	t.Run("handler=courierSuppressions", func(t *testing.T) {
+		del := func(t *testing.T, base *httptest.Server, href string, expectCode int) {
+			t.Helper()
+			req, err := http.NewRequest(http.MethodDelete, base.URL+href, nil)
+			require.NoError(t, err)
+			res, err := base.Client().Do(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.EqualValues(t, expectCode, res.StatusCode)
+		}
+
+		for _, tc := range tss {
+			t.Run("endpoint="+tc.name, func(t *testing.T) {
+				href := httprouterx.AdminPrefix + courier.AdminRouteListSuppressions
+				recipient := "suppressed-" + uuidx.NewV4().String() + "@ory.sh"
+
+				created := post(t, tc.s, href, fmt.Sprintf(`{"recipient":%q,"reason":"bounced"}`, strings.ToUpper(recipient)), http.StatusCreated)
+				assert.Equal(t, recipient, created.Get("recipient").String(), "%s", created.Raw)
+				assert.Equal(t, "bounced", created.Get("reason").String(), "%s", created.Raw)
+
+				post(t, tc.s, href, fmt.Sprintf(`{"recipient":%q,"reason":"manual"}`, recipient), http.StatusConflict)
+
+				list := get(t, tc.s, href+"?recipient="+recipient, http.StatusOK)
+				require.Len(t, list.Array(), 1, "%s", list.Raw)
+				assert.Equal(t, created.Get("id").String(), list.Get("0.id").String())
+
+				item := href + "/" + created.Get("id").String()
+				del(t, tc.s, item, http.StatusNoContent)
+				del(t, tc.s, item, http.StatusNotFound)
+				del(t, tc.s, href+"/not-a-uuid", http.StatusBadRequest)
+
+				assert.Len(t, get(t, tc.s, href+"?recipient="+recipient, http.StatusOK).Array(), 0)
+			})
+		}
+
+		t.Run("case=rejects invalid suppressions", func(t *testing.T) {
+			for _, body := range []string{`{}`, `{"recipient":"foo@ory.sh"}`, `{"recipient":" ","reason":"manual"}`, `{"recipient":"foo@ory.sh","reason":"rate_limit_recipient"}`, `{"unknown":"field"}`} {
+				post(t, adminTS, courier.AdminRouteListSuppressions, body, http.StatusBadRequest)
+			}
+		})
+	})
 }
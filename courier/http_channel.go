@@ -60,6 +60,8 @@ type httpDataModel struct {
 	TemplateData   Template              `json:"template_data"`
 	MessageType    string                `json:"message_type"`
 	RequestHeaders json.RawMessage       `json:"request_headers"`
+	// Locale is the locale in which the message was rendered, if known.
+	Locale string `json:"locale,omitempty"`
 }
 
 func (c *httpChannel) Dispatch(ctx context.Context, msg Message) (err error) {
@@ -86,6 +88,10 @@ func (c *httpChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 		MessageType:    msg.Type.String(),
 	}
 
+	if lc, ok := tmpl.(LocaleCarrier); ok {
+		td.Locale = lc.Locale()
+	}
+
 	c.tryPopulateHTMLBody(ctx, tmpl, &td)
 
 	req, err := builder.BuildRequest(ctx, td)
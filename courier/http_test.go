@@ -4,20 +4,26 @@
 package courier_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 
 	"github.com/ory/kratos/courier/template/email"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/request"
 	"github.com/ory/x/configx"
 )
 
@@ -113,3 +119,94 @@ func TestQueueHTTPEmail(t *testing.T) {
 		i++
 	}
 }
+
+func TestQueueHTTPEmailSigned(t *testing.T) {
+	received := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rb, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		received <- request.VerifyHMACSignature(r.Header.Get(request.DefaultHMACHeader), rb, time.Minute, "old-secret-0123456789")
+	}))
+	t.Cleanup(srv.Close)
+
+	requestConfig := fmt.Sprintf(`{
+		"url": "%s",
+		"method": "POST",
+		"auth": {
+			"type": "hmac",
+			"config": {
+				"secrets": ["new-secret-0123456789", "old-secret-0123456789"]
+			}
+		},
+		"body": "file://./stub/request.config.mailer.jsonnet"
+	}`, srv.URL)
+
+	_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeyCourierDeliveryStrategy:  "http",
+		config.ViperKeyCourierHTTPRequestConfig: requestConfig,
+		config.ViperKeyCourierSMTPURL:           "http://foo.url",
+	}))
+
+	courier, err := reg.Courier(t.Context())
+	require.NoError(t, err)
+
+	_, err = courier.QueueEmail(t.Context(), email.NewTestStub(&email.TestStubModel{
+		To:      "test-2@test.com",
+		Subject: "test-mailer-subject",
+		Body:    "test-mailer-body",
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, courier.DispatchQueue(t.Context()))
+	close(received)
+
+	require.Len(t, received, 1)
+	assert.NoError(t, <-received)
+}
+
+func TestQueueHTTPEmailLocale(t *testing.T) {
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rb, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received <- rb
+	}))
+	t.Cleanup(srv.Close)
+
+	templates := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(templates, "recovery_code", "valid"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(templates, "recovery_code", "valid", "email.subject.de.gotmpl"), []byte("Dein Wiederherstellungscode"), 0o600))
+
+	requestConfig := fmt.Sprintf(`{
+		"url": "%s",
+		"method": "POST",
+		"body": "base64://%s"
+	}`, srv.URL, base64.StdEncoding.EncodeToString([]byte("function(ctx) ctx")))
+
+	_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeyCourierDeliveryStrategy:  "http",
+		config.ViperKeyCourierHTTPRequestConfig: requestConfig,
+		config.ViperKeyCourierTemplatesPath:     templates,
+		config.ViperKeyCourierSMTPURL:           "http://foo.url",
+	}))
+
+	courier, err := reg.Courier(t.Context())
+	require.NoError(t, err)
+
+	_, err = courier.QueueEmail(t.Context(), email.NewRecoveryCodeValid(reg, &email.RecoveryCodeValidModel{
+		To:           "test@ory.sh",
+		RecoveryCode: "123456",
+		Locale:       "de-CH",
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, courier.DispatchQueue(t.Context()))
+	close(received)
+
+	require.Len(t, received, 1)
+	body := <-received
+	assert.Equal(t, "de-CH", gjson.GetBytes(body, "locale").String(), "%s", body)
+	assert.Equal(t, "de-CH", gjson.GetBytes(body, "template_data.locale").String(), "%s", body)
+	assert.Equal(t, "Dein Wiederherstellungscode", gjson.GetBytes(body, "subject").String(), "%s", body)
+}
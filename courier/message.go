@@ -27,6 +27,9 @@ const (
 	MessageStatusSent
 	MessageStatusProcessing
 	MessageStatusAbandoned
+	MessageStatusCancelled
+	MessageStatusSuppressed
+	MessageStatusExpired
 )
 
 const (
@@ -34,6 +37,9 @@ const (
 	messageStatusSentText       = "sent"
 	messageStatusProcessingText = "processing"
 	messageStatusAbandonedText  = "abandoned"
+	messageStatusCancelledText  = "cancelled"
+	messageStatusSuppressedText = "suppressed"
+	messageStatusExpiredText    = "expired"
 )
 
 func ToMessageStatus(str string) (MessageStatus, error) {
@@ -46,6 +52,12 @@ func ToMessageStatus(str string) (MessageStatus, error) {
 		return MessageStatusProcessing, nil
 	case s.AddCase(MessageStatusAbandoned.String()):
 		return MessageStatusAbandoned, nil
+	case s.AddCase(MessageStatusCancelled.String()):
+		return MessageStatusCancelled, nil
+	case s.AddCase(MessageStatusSuppressed.String()):
+		return MessageStatusSuppressed, nil
+	case s.AddCase(MessageStatusExpired.String()):
+		return MessageStatusExpired, nil
 	default:
 		return 0, errors.WithStack(herodot.ErrBadRequest.WithWrap(s.ToUnknownCaseErr()).WithReason("Message status is not valid"))
 	}
@@ -61,6 +73,12 @@ func (ms MessageStatus) String() string {
 		return messageStatusProcessingText
 	case MessageStatusAbandoned:
 		return messageStatusAbandonedText
+	case MessageStatusCancelled:
+		return messageStatusCancelledText
+	case MessageStatusSuppressed:
+		return messageStatusSuppressedText
+	case MessageStatusExpired:
+		return messageStatusExpiredText
 	default:
 		return ""
 	}
@@ -68,7 +86,7 @@ func (ms MessageStatus) String() string {
 
 func (ms MessageStatus) IsValid() error {
 	switch ms {
-	case MessageStatusQueued, MessageStatusSent, MessageStatusProcessing, MessageStatusAbandoned:
+	case MessageStatusQueued, MessageStatusSent, MessageStatusProcessing, MessageStatusAbandoned, MessageStatusCancelled, MessageStatusSuppressed, MessageStatusExpired:
 		return nil
 	default:
 		return errors.WithStack(herodot.ErrBadRequest.WithReason("Message status is not valid"))
@@ -193,6 +211,21 @@ type Message struct {
 	// required: true
 	SendCount int `json:"send_count" db:"send_count"`
 
+	// WorkerID identifies the courier worker which claimed the message last.
+	WorkerID sqlxx.NullString `json:"worker_id,omitempty" faker:"-" db:"worker_id"`
+
+	// LeaseExpiresAt is the time until which the message is claimed by the
+	// worker. Once the lease expired, the message may be claimed by another
+	// worker.
+	LeaseExpiresAt sqlxx.NullTime `json:"-" faker:"-" db:"lease_expires_at"`
+
+	// SendAfter is the time before which the message is not sent.
+	SendAfter sqlxx.NullTime `json:"send_after,omitzero" faker:"-" db:"send_after"`
+
+	// ExpiresAt is the time after which the message is no longer sent. Expired
+	// messages are not sent but get the expired status.
+	ExpiresAt sqlxx.NullTime `json:"expires_at,omitzero" faker:"-" db:"expires_at"`
+
 	// Dispatches store information about the attempts of delivering a message
 	// May contain an error if any happened, or just the `success` state.
 	Dispatches []MessageDispatch `json:"dispatches,omitempty" has_many:"courier_message_dispatches" order_by:"created_at desc" faker:"-"`
@@ -19,6 +19,20 @@ const (
 	CourierMessageDispatchStatusSuccess CourierMessageDispatchStatus = "success"
 )
 
+// A delivery status reported by the provider of a channel
+//
+// swagger:enum CourierMessageDeliveryStatus
+type CourierMessageDeliveryStatus string
+
+const (
+	// CourierMessageDeliveryStatusDelivered is reported if the message reached
+	// the recipient.
+	CourierMessageDeliveryStatusDelivered CourierMessageDeliveryStatus = "delivered"
+	// CourierMessageDeliveryStatusUndelivered is reported if the provider
+	// accepted the message, but was unable to deliver it to the recipient.
+	CourierMessageDeliveryStatusUndelivered CourierMessageDeliveryStatus = "undelivered"
+)
+
 // MessageDispatch represents an attempt of sending a courier message
 // It contains the status of the attempt (failed or successful) and the error if any occured
 //
@@ -32,6 +46,9 @@ type MessageDispatch struct {
 	// required: true
 	MessageID uuid.UUID `json:"message_id" db:"message_id"`
 
+	// The name of the channel which attempted the delivery
+	Channel sqlxx.NullString `json:"channel,omitempty" db:"channel"`
+
 	// The status of this dispatch
 	// Either "failed" or "success"
 	// required: true
@@ -40,6 +57,14 @@ type MessageDispatch struct {
 	// An optional error
 	Error sqlxx.JSONRawMessage `json:"error,omitempty" db:"error"`
 
+	// The ID the provider of the channel assigned to the message
+	ProviderMessageID sqlxx.NullString `json:"provider_message_id,omitempty" db:"provider_message_id"`
+
+	// The delivery status reported by the provider of the channel
+	// Either "delivered" or "undelivered", or empty if the provider did not
+	// report the delivery status yet
+	DeliveryStatus CourierMessageDeliveryStatus `json:"delivery_status,omitempty" db:"delivery_status"`
+
 	// CreatedAt is a helper struct field for gobuffalo.pop.
 	// required: true
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
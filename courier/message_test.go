@@ -24,6 +24,9 @@ func TestToMessageStatus(t *testing.T) {
 			"sent":       courier.MessageStatusSent,
 			"processing": courier.MessageStatusProcessing,
 			"abandoned":  courier.MessageStatusAbandoned,
+			"cancelled":  courier.MessageStatusCancelled,
+			"suppressed": courier.MessageStatusSuppressed,
+			"expired":    courier.MessageStatusExpired,
 		} {
 			result, err := courier.ToMessageStatus(str)
 			require.NoError(t, err)
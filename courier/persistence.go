@@ -5,26 +5,64 @@ package courier
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier/template"
+
 	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
 )
 
-var ErrQueueEmpty = errors.New("queue is empty")
+var (
+	ErrQueueEmpty = errors.New("queue is empty")
+
+	// ErrLeaseLost is returned if a message is no longer claimed by the
+	// worker, for example because its lease expired and another worker
+	// claimed it.
+	ErrLeaseLost = errors.New("the message is no longer claimed by the worker")
+
+	// ErrMessageStatusChanged is returned if the status of a message was
+	// changed concurrently.
+	ErrMessageStatusChanged = herodot.ErrConflict.WithReason("The status of the message was changed concurrently, please try again.")
+)
 
 type (
 	Persister interface {
 		AddMessage(context.Context, *Message) error
 
-		NextMessages(context.Context, uint8) ([]Message, error)
+		// NextMessages claims up to limit messages for the worker. Queued
+		// messages and messages whose lease expired are claimed and leased to
+		// the worker for the given duration. Messages claimed concurrently by
+		// other workers are skipped.
+		NextMessages(ctx context.Context, limit uint8, workerID string, lease time.Duration) ([]Message, error)
 
-		SetMessageStatus(context.Context, uuid.UUID, MessageStatus) error
+		// RenewMessageLease extends the lease of a message claimed by the
+		// worker. Returns ErrLeaseLost if the worker no longer claims it.
+		RenewMessageLease(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration) error
+
+		// SetMessageStatus sets the status of a message claimed by the worker.
+		// Returns ErrLeaseLost if the worker no longer claims it.
+		SetMessageStatus(ctx context.Context, id uuid.UUID, workerID string, ms MessageStatus) error
 
 		LatestQueuedMessage(ctx context.Context) (*Message, error)
 
-		IncrementMessageSendCount(context.Context, uuid.UUID) error
+		// IncrementMessageSendCount increments the send count of a message
+		// claimed by the worker. Returns ErrLeaseLost if the worker no longer
+		// claims it.
+		IncrementMessageSendCount(ctx context.Context, id uuid.UUID, workerID string) error
+
+		// CancelMessage sets the status of a queued message to cancelled.
+		// Returns ErrMessageStatusChanged if the message is not queued.
+		CancelMessage(context.Context, uuid.UUID) error
+
+		// RequeueMessage queues a message with one of the given statuses again
+		// and resets its send count, so that it is retried as many times as a
+		// new message. Returns ErrMessageStatusChanged if the message has
+		// another status.
+		RequeueMessage(ctx context.Context, id uuid.UUID, statuses []MessageStatus) error
 
 		// ListMessages lists all messages in the store given the page, itemsPerPage, status and recipient.
 		// Returns list of messages, total count of messages satisfied by given filter, and error if any
@@ -33,9 +71,37 @@ type (
 		// FetchMessage returns a message with the id or nil and an error if not found
 		FetchMessage(context.Context, uuid.UUID) (*Message, error)
 
-		// Records an attempt of sending out a courier message
+		// Records an attempt of sending out a courier message using the named channel
+		// and the ID the provider of the channel assigned to the message, if any.
 		// Returns an error if it fails
-		RecordDispatch(ctx context.Context, msgID uuid.UUID, status CourierMessageDispatchStatus, err error) error
+		RecordDispatch(ctx context.Context, msgID uuid.UUID, channel string, providerMessageID string, status CourierMessageDispatchStatus, err error) error
+
+		// UpdateDispatchDeliveryStatus sets the delivery status of the dispatch
+		// with the provider message ID of the named channel. The delivery error is
+		// recorded as the error of the dispatch, if any. Returns the updated
+		// dispatch.
+		UpdateDispatchDeliveryStatus(ctx context.Context, channel string, providerMessageID string, status CourierMessageDeliveryStatus, deliveryErr error) (*MessageDispatch, error)
+
+		// CountRecipientMessages counts the messages queued for the recipient
+		// since the given time. Recipients are compared after normalizing them,
+		// see NormalizeRecipient. If the template type is not empty, only
+		// messages of this template type are counted. Suppressed messages are
+		// not counted.
+		CountRecipientMessages(ctx context.Context, recipient string, templateType template.TemplateType, since time.Time) (int, error)
+
+		// CreateSuppression adds the recipient to the suppression list. Returns
+		// a conflict error if the recipient is suppressed already.
+		CreateSuppression(context.Context, *Suppression) error
+
+		// FindSuppressionByRecipient returns the suppression list entry of the
+		// recipient or an error if the recipient is not suppressed.
+		FindSuppressionByRecipient(ctx context.Context, recipient string) (*Suppression, error)
+
+		// ListSuppressions lists the entries of the suppression list.
+		ListSuppressions(context.Context, ListCourierSuppressionsParameters, []keysetpagination.Option) ([]Suppression, *keysetpagination.Paginator, error)
+
+		// DeleteSuppression removes an entry from the suppression list.
+		DeleteSuppression(context.Context, uuid.UUID) error
 	}
 	PersistenceProvider interface {
 		CourierPersister() Persister
@@ -0,0 +1,109 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+)
+
+// messageTTL returns how long messages of the template type are useful, which
+// is as long as the code or link they contain is valid. Returns zero if the
+// messages do not expire.
+func (c *courier) messageTTL(ctx context.Context, templateType template.TemplateType) time.Duration {
+	switch templateType {
+	case template.TypeRecoveryCodeValid, template.TypeVerificationCodeValid, template.TypeLoginCodeValid, template.TypeRegistrationCodeValid:
+		return c.deps.CourierConfig().SelfServiceCodeMethodLifespan(ctx)
+	case template.TypeRecoveryValid, template.TypeVerificationValid:
+		return c.deps.CourierConfig().SelfServiceLinkMethodLifespan(ctx)
+	default:
+		return 0
+	}
+}
+
+// setExpiry sets when the message expires, if messages of its template type
+// do. The time to live starts when the message is queued.
+func (c *courier) setExpiry(ctx context.Context, m *Message) {
+	if ttl := c.messageTTL(ctx, m.TemplateType); ttl > 0 {
+		m.ExpiresAt = sqlxx.NullTime(time.Now().UTC().Add(ttl))
+	}
+}
+
+// addMessage stores the message in the queue. Messages to recipients on the
+// suppression list or exceeding a rate limit are stored with the suppressed
+// status instead, so that they are not delivered but remain visible to
+// administrators. The caller is not told about the suppression, because it
+// would reveal to end users whether a recipient is suppressed.
+func (c *courier) addMessage(ctx context.Context, m *Message) error {
+	reason, err := c.suppressionReason(ctx, m)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		m.Status = MessageStatusSuppressed
+	}
+
+	if err := c.deps.CourierPersister().AddMessage(ctx, m); err != nil {
+		return err
+	}
+
+	if reason != "" {
+		c.deps.Logger().
+			WithField("message_id", m.ID).
+			WithField("message_type", m.Type).
+			WithField("message_template_type", m.TemplateType).
+			WithField("suppression_reason", reason).
+			Info("Courier message was suppressed and will not be delivered.")
+		events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageSuppressed(ctx, m.ID, m.Channel.String(), string(m.TemplateType), reason))
+	}
+	return nil
+}
+
+// suppressionReason returns why the message must not be delivered, or an empty
+// string if it may be delivered.
+func (c *courier) suppressionReason(ctx context.Context, m *Message) (string, error) {
+	s, err := c.deps.CourierPersister().FindSuppressionByRecipient(ctx, m.Recipient)
+	if err == nil {
+		return string(s.Reason), nil
+	} else if !errors.Is(err, sqlcon.ErrNoRows) {
+		return "", err
+	}
+
+	if exceeded, err := c.rateLimitExceeded(ctx, m, "", c.deps.CourierConfig().CourierRateLimitPerRecipient(ctx)); err != nil {
+		return "", err
+	} else if exceeded {
+		return suppressionReasonRateLimitRecipient, nil
+	}
+
+	if exceeded, err := c.rateLimitExceeded(ctx, m, m.TemplateType, c.deps.CourierConfig().CourierRateLimitPerTemplateType(ctx, string(m.TemplateType))); err != nil {
+		return "", err
+	} else if exceeded {
+		return suppressionReasonRateLimitTemplateType, nil
+	}
+
+	return "", nil
+}
+
+// rateLimitExceeded returns true if the recipient of the message was sent as
+// many messages within the window of the limit as the limit allows. If the
+// template type is not empty, only messages of this template type count.
+func (c *courier) rateLimitExceeded(ctx context.Context, m *Message, templateType template.TemplateType, limit *config.CourierRateLimit) (bool, error) {
+	if limit.MaxMessages <= 0 {
+		return false, nil
+	}
+
+	count, err := c.deps.CourierPersister().CountRecipientMessages(ctx, m.Recipient, templateType, time.Now().UTC().Add(-limit.Window))
+	if err != nil {
+		return false, err
+	}
+	return count >= limit.MaxMessages, nil
+}
@@ -0,0 +1,193 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/courier/template/sms"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/uuidx"
+)
+
+func TestQueueSuppression(t *testing.T) {
+	t.Parallel()
+
+	status := func(t *testing.T, reg *driver.RegistryDefault, id uuid.UUID) courier.MessageStatus {
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		return m.Status
+	}
+
+	queueEmail := func(t *testing.T, c courier.Courier, to string) uuid.UUID {
+		id, err := c.QueueEmail(t.Context(), email.NewTestStub(&email.TestStubModel{To: to, Subject: "subject", Body: "body"}))
+		require.NoError(t, err)
+		require.NotZero(t, id)
+		return id
+	}
+
+	t.Run("case=suppresses messages to recipients on the suppression list", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t)
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		recipient := uuidx.NewV4().String() + "@ory.sh"
+		require.NoError(t, reg.CourierPersister().CreateSuppression(t.Context(), &courier.Suppression{Recipient: recipient, Reason: courier.SuppressionReasonBounced}))
+		require.NoError(t, reg.CourierPersister().CreateSuppression(t.Context(), &courier.Suppression{Recipient: "+12065550101", Reason: courier.SuppressionReasonManual}))
+
+		assert.Equal(t, courier.MessageStatusSuppressed, status(t, reg, queueEmail(t, c, recipient)))
+		assert.Equal(t, courier.MessageStatusQueued, status(t, reg, queueEmail(t, c, uuidx.NewV4().String()+"@ory.sh")))
+
+		id, err := c.QueueSMS(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "+12065550101", Body: "body"}))
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusSuppressed, status(t, reg, id))
+	})
+
+	t.Run("case=suppresses messages exceeding the per recipient rate limit", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierRateLimitPerRecipient + ".max_messages": 2,
+			config.ViperKeyCourierRateLimitPerRecipient + ".window":       "1h",
+		}))
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		recipient := uuidx.NewV4().String() + "@ory.sh"
+		for range 2 {
+			assert.Equal(t, courier.MessageStatusQueued, status(t, reg, queueEmail(t, c, recipient)))
+		}
+		assert.Equal(t, courier.MessageStatusSuppressed, status(t, reg, queueEmail(t, c, recipient)))
+		assert.Equal(t, courier.MessageStatusSuppressed, status(t, reg, queueEmail(t, c, recipient)))
+		assert.Equal(t, courier.MessageStatusQueued, status(t, reg, queueEmail(t, c, uuidx.NewV4().String()+"@ory.sh")))
+	})
+
+	t.Run("case=suppresses messages exceeding the per template type rate limit", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierRateLimitPerTemplateType + ".stub.max_messages": 1,
+		}))
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		recipient := uuidx.NewV4().String() + "@ory.sh"
+		assert.Equal(t, courier.MessageStatusQueued, status(t, reg, queueEmail(t, c, recipient)))
+		assert.Equal(t, courier.MessageStatusSuppressed, status(t, reg, queueEmail(t, c, recipient)))
+	})
+}
+
+func TestQueueSchedule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("case=holds messages until they are due", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t)
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+		c.FailOnDispatchError()
+
+		id, err := c.QueueEmail(t.Context(), email.NewTestStub(&email.TestStubModel{To: "scheduled@ory.sh", Subject: "subject", Body: "body"}))
+		require.NoError(t, err)
+		sendAfter := time.Now().UTC().Add(time.Hour)
+		require.NoError(t, reg.Persister().GetConnection(t.Context()).RawQuery("UPDATE courier_messages SET send_after = ? WHERE id = ?", sendAfter, id).Exec())
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusQueued, m.Status)
+		assert.Equal(t, 0, m.SendCount)
+		assert.WithinDuration(t, sendAfter, time.Time(m.SendAfter), time.Second)
+	})
+
+	t.Run("case=sets the expiry from the code lifespan", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValue(config.ViperKeyCodeLifespan, "30s"))
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		id, err := c.QueueEmail(t.Context(), email.NewRecoveryCodeValid(reg, &email.RecoveryCodeValidModel{
+			To:           "recovery@ory.sh",
+			RecoveryCode: "123456",
+		}))
+		require.NoError(t, err)
+
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(30*time.Second), time.Time(m.ExpiresAt), 5*time.Second)
+		assert.True(t, m.SendAfter.IsZero())
+	})
+
+	t.Run("case=sets the expiry from the link lifespan", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t, configx.WithValue(config.ViperKeyLinkLifespan, "2h"))
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		id, err := c.QueueEmail(t.Context(), email.NewRecoveryValid(reg, &email.RecoveryValidModel{
+			To:          "recovery@ory.sh",
+			RecoveryURL: "https://www.ory.sh/",
+		}))
+		require.NoError(t, err)
+
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), time.Time(m.ExpiresAt), time.Minute)
+	})
+
+	t.Run("case=does not expire other messages", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t)
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		id, err := c.QueueEmail(t.Context(), email.NewTestStub(&email.TestStubModel{To: "stub@ory.sh", Subject: "subject", Body: "body"}))
+		require.NoError(t, err)
+
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.True(t, m.ExpiresAt.IsZero())
+	})
+
+	t.Run("case=expires messages instead of dispatching them", func(t *testing.T) {
+		t.Parallel()
+		_, reg := internal.NewFastRegistryWithMocks(t)
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+		c.FailOnDispatchError()
+
+		m := courier.Message{
+			Status:       courier.MessageStatusQueued,
+			Type:         courier.MessageTypeEmail,
+			Channel:      "email",
+			Recipient:    "expired@ory.sh",
+			Subject:      "subject",
+			Body:         "body",
+			TemplateType: template.TypeRecoveryCodeValid,
+			ExpiresAt:    sqlxx.NullTime(time.Now().UTC().Add(-time.Minute)),
+		}
+		require.NoError(t, reg.CourierPersister().AddMessage(t.Context(), &m))
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		actual, err := reg.CourierPersister().FetchMessage(t.Context(), m.ID)
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusExpired, actual.Status)
+		assert.Empty(t, actual.Dispatches)
+
+		_, err = courier.RetryMessage(t.Context(), reg, m.ID)
+		require.ErrorIs(t, err, herodot.ErrConflict)
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/x/events"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+// retriableStatuses are the statuses of messages which may be queued again.
+var retriableStatuses = []MessageStatus{MessageStatusAbandoned, MessageStatusCancelled, MessageStatusSent}
+
+// RetryMessage queues an abandoned, cancelled, or sent message again. The send
+// count of the message is reset, so that it is retried as many times as a new
+// message.
+func RetryMessage(ctx context.Context, d PersistenceProvider, id uuid.UUID) (*Message, error) {
+	m, err := d.CourierPersister().FetchMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(retriableStatuses, m.Status) {
+		return nil, errors.WithStack(herodot.ErrConflict.WithReasonf("Only abandoned, cancelled, or sent messages can be retried, but the message is %s.", m.Status))
+	}
+
+	if err := requeue(ctx, d, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CancelMessage cancels a queued message, so that it is not sent.
+func CancelMessage(ctx context.Context, d PersistenceProvider, id uuid.UUID) (*Message, error) {
+	m, err := d.CourierPersister().FetchMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Status != MessageStatusQueued {
+		return nil, errors.WithStack(herodot.ErrConflict.WithReasonf("Only queued messages can be cancelled, but the message is %s.", m.Status))
+	}
+
+	if err := d.CourierPersister().CancelMessage(ctx, m.ID); err != nil {
+		return nil, err
+	}
+	m.Status = MessageStatusCancelled
+
+	events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageCancelled(ctx, m.ID, m.Channel.String(), string(m.TemplateType)))
+	return m, nil
+}
+
+// RequeueMessages retries all messages matching the filter and returns how many
+// messages were queued again. The filter must select abandoned, cancelled, or
+// sent messages.
+func RequeueMessages(ctx context.Context, d PersistenceProvider, filter ListCourierMessagesParameters) (int, error) {
+	if filter.Status == nil || !slices.Contains(retriableStatuses, *filter.Status) {
+		return 0, errors.WithStack(herodot.ErrBadRequest.WithReason("The status must be one of abandoned, cancelled, or sent."))
+	}
+
+	var count int
+	opts := []keysetpagination.Option{keysetpagination.WithSize(250)}
+	for {
+		messages, next, err := d.CourierPersister().ListMessages(ctx, filter, opts)
+		if err != nil {
+			return count, err
+		}
+
+		for i := range messages {
+			if err := requeue(ctx, d, &messages[i]); errors.Is(err, ErrMessageStatusChanged) {
+				// The message was retried or dispatched meanwhile.
+				continue
+			} else if err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if next.IsLast() {
+			return count, nil
+		}
+		opts = next.ToOptions()
+	}
+}
+
+func requeue(ctx context.Context, d PersistenceProvider, m *Message) error {
+	if err := d.CourierPersister().RequeueMessage(ctx, m.ID, retriableStatuses); err != nil {
+		return err
+	}
+	m.SendCount = 0
+	m.Status = MessageStatusQueued
+
+	events.SpanFromContext(ctx).AddEvent(events.NewCourierMessageRequeued(ctx, m.ID, m.Channel.String(), string(m.TemplateType)))
+	return nil
+}
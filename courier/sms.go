@@ -44,7 +44,9 @@ func (c *courier) QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error
 		RequestHeaders: requestHeaders,
 		Body:           body,
 	}
-	if err := c.deps.CourierPersister().AddMessage(ctx, message); err != nil {
+	c.setExpiry(ctx, message)
+
+	if err := c.addMessage(ctx, message); err != nil {
 		return uuid.Nil, err
 	}
 
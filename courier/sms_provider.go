@@ -0,0 +1,61 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/urlx"
+)
+
+// maxProviderResponseSize limits how much of the response of an SMS provider
+// is read.
+const maxProviderResponseSize = 64 * 1024
+
+// deliveryReceiptURL returns the URL to which the provider of the named
+// channel sends delivery receipts.
+func deliveryReceiptURL(ctx context.Context, d channelDependencies, name string) *url.URL {
+	return urlx.AppendPaths(d.CourierConfig().SelfPublicURL(ctx), strings.ReplaceAll(RouteDeliveryReceipts, "{channel}", url.PathEscape(name)))
+}
+
+// doProviderRequest sends the request to the API of an SMS provider and
+// returns the status code and the body of the response.
+func doProviderRequest(ctx context.Context, d channelDependencies, req *retryablehttp.Request) (int, []byte, error) {
+	res, err := d.HTTPClient(ctx,
+		// fail fast and let the courier retry if needed instead of blocking the queue
+		httpx.ResilientClientWithMaxRetry(0),
+		httpx.ResilientClientWithConnectionTimeout(10*time.Second),
+	).Do(req)
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxProviderResponseSize))
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	return res.StatusCode, body, nil
+}
+
+// verifyReceiptToken checks that the delivery receipt carries the secret of
+// the channel in the `token` query parameter. Receipts are rejected if no
+// secret is configured.
+func verifyReceiptToken(r *http.Request, secret string) error {
+	if secret == "" || subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(secret)) != 1 {
+		return errors.WithStack(herodot.ErrForbidden.WithReason("The delivery receipt could not be authenticated."))
+	}
+	return nil
+}
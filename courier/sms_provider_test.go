@@ -0,0 +1,267 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //#nosec G505 -- Twilio signs requests with HMAC-SHA1
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template/sms"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/x/configx"
+)
+
+func TestSMSProviderChannels(t *testing.T) {
+	t.Parallel()
+
+	const receiptSecret = "a-delivery-receipt-secret"
+
+	// setup starts the provider API, configures a channel of the given type
+	// with it, and returns the registry and the public server.
+	setup := func(t *testing.T, channelType string, api http.HandlerFunc, channelConfig func(apiURL string) string) (*driver.RegistryDefault, *httptest.Server) {
+		apiServer := httptest.NewServer(api)
+		t.Cleanup(apiServer.Close)
+
+		conf, reg := internal.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierChannels: fmt.Sprintf(`[{"id": "sms", "name": "%s-primary", "type": %q, "%s_config": %s}]`, channelType, channelType, channelType, channelConfig(apiServer.URL)),
+		}))
+		publicTS, _ := testhelpers.NewKratosServerWithCSRF(t, reg)
+		conf.MustSet(t.Context(), config.ViperKeyPublicBaseURL, publicTS.URL)
+		return reg, publicTS
+	}
+
+	// dispatch queues and dispatches an SMS and returns its dispatch.
+	dispatch := func(t *testing.T, reg *driver.RegistryDefault) courier.MessageDispatch {
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+		c.FailOnDispatchError()
+
+		id, err := c.QueueSMS(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "+12065550101", Body: "your code is 123456"}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		require.Equal(t, courier.MessageStatusSent, m.Status)
+		require.Len(t, m.Dispatches, 1)
+		return m.Dispatches[0]
+	}
+
+	deliveryStatus := func(t *testing.T, reg *driver.RegistryDefault, messageID uuid.UUID) (courier.CourierMessageDeliveryStatus, string) {
+		m, err := reg.CourierPersister().FetchMessage(t.Context(), messageID)
+		require.NoError(t, err)
+		require.Len(t, m.Dispatches, 1)
+		return m.Dispatches[0].DeliveryStatus, string(m.Dispatches[0].Error)
+	}
+
+	send := func(t *testing.T, req *http.Request, expectCode int) {
+		t.Helper()
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(res.Body)
+		require.NoError(t, res.Body.Close())
+		assert.Equalf(t, expectCode, res.StatusCode, "%s", body)
+	}
+
+	t.Run("type=twilio", func(t *testing.T) {
+		t.Parallel()
+
+		var received url.Values
+		reg, publicTS := setup(t, "twilio", func(w http.ResponseWriter, r *http.Request) {
+			user, pass, _ := r.BasicAuth()
+			assert.Equal(t, "AC123", user)
+			assert.Equal(t, "auth-token", pass)
+			assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			received = r.PostForm
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"sid":"SM123"}`))
+		}, func(apiURL string) string {
+			return fmt.Sprintf(`{"account_sid": "AC123", "auth_token": "auth-token", "from": "+12065550100", "base_url": %q}`, apiURL)
+		})
+
+		d := dispatch(t, reg)
+		assert.Equal(t, "SM123", d.ProviderMessageID.String())
+		assert.Equal(t, "twilio-primary", d.Channel.String())
+		assert.Equal(t, "+12065550101", received.Get("To"))
+		assert.Equal(t, "+12065550100", received.Get("From"))
+		assert.Equal(t, "your code is 123456", received.Get("Body"))
+
+		callback := received.Get("StatusCallback")
+		assert.Equal(t, publicTS.URL+"/courier/channels/twilio-primary/receipts", callback)
+
+		receipt := func(form url.Values, signature string) *http.Request {
+			req, err := http.NewRequest(http.MethodPost, callback, strings.NewReader(form.Encode()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Twilio-Signature", signature)
+			return req
+		}
+		sign := func(form url.Values) string {
+			payload := callback
+			for _, k := range []string{"ErrorCode", "MessageSid", "MessageStatus"} {
+				if v := form.Get(k); v != "" {
+					payload += k + v
+				}
+			}
+			mac := hmac.New(sha1.New, []byte("auth-token"))
+			_, _ = mac.Write([]byte(payload))
+			return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		}
+
+		t.Run("case=rejects unsigned receipts", func(t *testing.T) {
+			form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"delivered"}}
+			send(t, receipt(form, "invalid"), http.StatusForbidden)
+			status, _ := deliveryStatus(t, reg, d.MessageID)
+			assert.Empty(t, status)
+		})
+
+		t.Run("case=ignores intermediate statuses", func(t *testing.T) {
+			form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"sent"}}
+			send(t, receipt(form, sign(form)), http.StatusNoContent)
+			status, _ := deliveryStatus(t, reg, d.MessageID)
+			assert.Empty(t, status)
+		})
+
+		t.Run("case=records undelivered messages", func(t *testing.T) {
+			form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+			send(t, receipt(form, sign(form)), http.StatusNoContent)
+			status, dispatchErr := deliveryStatus(t, reg, d.MessageID)
+			assert.Equal(t, courier.CourierMessageDeliveryStatusUndelivered, status)
+			assert.Contains(t, dispatchErr, "30003")
+		})
+
+		t.Run("case=records delivered messages", func(t *testing.T) {
+			form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"delivered"}}
+			send(t, receipt(form, sign(form)), http.StatusNoContent)
+			status, _ := deliveryStatus(t, reg, d.MessageID)
+			assert.Equal(t, courier.CourierMessageDeliveryStatusDelivered, status)
+		})
+
+		t.Run("case=acknowledges receipts of unknown messages", func(t *testing.T) {
+			form := url.Values{"MessageSid": {"SM-unknown"}, "MessageStatus": {"delivered"}}
+			send(t, receipt(form, sign(form)), http.StatusNoContent)
+		})
+
+		t.Run("case=rejects receipts for unknown channels", func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, publicTS.URL+"/courier/channels/unknown/receipts", nil)
+			require.NoError(t, err)
+			send(t, req, http.StatusNotFound)
+		})
+	})
+
+	t.Run("type=vonage", func(t *testing.T) {
+		t.Parallel()
+
+		var received url.Values
+		reg, publicTS := setup(t, "vonage", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/sms/json", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			received = r.PostForm
+			_, _ = w.Write([]byte(`{"message-count":"1","messages":[{"to":"12065550101","message-id":"vonage-123","status":"0"}]}`))
+		}, func(apiURL string) string {
+			return fmt.Sprintf(`{"api_key": "key", "api_secret": "secret", "from": "Ory", "base_url": %q, "delivery_receipt_secret": %q}`, apiURL, receiptSecret)
+		})
+
+		d := dispatch(t, reg)
+		assert.Equal(t, "vonage-123", d.ProviderMessageID.String())
+		assert.Equal(t, "12065550101", received.Get("to"))
+		assert.Equal(t, "key", received.Get("api_key"))
+		assert.Equal(t, publicTS.URL+"/courier/channels/vonage-primary/receipts?token="+receiptSecret, received.Get("callback"))
+
+		t.Run("case=rejects receipts without the secret", func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, publicTS.URL+"/courier/channels/vonage-primary/receipts?messageId=vonage-123&status=delivered&token=wrong", nil)
+			require.NoError(t, err)
+			send(t, req, http.StatusForbidden)
+		})
+
+		t.Run("case=records failed messages", func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, received.Get("callback"), strings.NewReader(`{"messageId":"vonage-123","status":"failed","err-code":"6"}`))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			send(t, req, http.StatusNoContent)
+
+			status, dispatchErr := deliveryStatus(t, reg, d.MessageID)
+			assert.Equal(t, courier.CourierMessageDeliveryStatusUndelivered, status)
+			assert.Contains(t, dispatchErr, "error code 6")
+		})
+
+		t.Run("case=records delivered messages", func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, received.Get("callback")+"&messageId=vonage-123&status=delivered", nil)
+			require.NoError(t, err)
+			send(t, req, http.StatusNoContent)
+
+			status, _ := deliveryStatus(t, reg, d.MessageID)
+			assert.Equal(t, courier.CourierMessageDeliveryStatusDelivered, status)
+		})
+	})
+
+	t.Run("type=sns", func(t *testing.T) {
+		t.Parallel()
+
+		var received url.Values
+		reg, publicTS := setup(t, "sns", func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"), r.Header.Get("Authorization"))
+			assert.Contains(t, r.Header.Get("Authorization"), "/eu-central-1/sns/aws4_request")
+			require.NoError(t, r.ParseForm())
+			received = r.PostForm
+			_, _ = w.Write([]byte(`<PublishResponse><PublishResult><MessageId>sns-123</MessageId></PublishResult></PublishResponse>`))
+		}, func(apiURL string) string {
+			return fmt.Sprintf(`{"region": "eu-central-1", "access_key_id": "AKID", "secret_access_key": "secret", "sender_id": "Ory", "base_url": %q, "delivery_receipt_secret": %q}`, apiURL, receiptSecret)
+		})
+
+		d := dispatch(t, reg)
+		assert.Equal(t, "sns-123", d.ProviderMessageID.String())
+		assert.Equal(t, "Publish", received.Get("Action"))
+		assert.Equal(t, "+12065550101", received.Get("PhoneNumber"))
+		assert.Equal(t, "Ory", received.Get("MessageAttributes.entry.2.Value.StringValue"))
+
+		href := publicTS.URL + "/courier/channels/sns-primary/receipts?token=" + receiptSecret
+		post := func(t *testing.T, href, body string, expectCode int) {
+			req, err := http.NewRequest(http.MethodPost, href, strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "text/plain")
+			send(t, req, expectCode)
+		}
+
+		t.Run("case=rejects receipts without the secret", func(t *testing.T) {
+			post(t, publicTS.URL+"/courier/channels/sns-primary/receipts", `{"notification":{"messageId":"sns-123"},"status":"SUCCESS"}`, http.StatusForbidden)
+		})
+
+		t.Run("case=ignores subscription confirmations", func(t *testing.T) {
+			post(t, href, `{"Type":"SubscriptionConfirmation","SubscribeURL":"https://sns.example.com/confirm"}`, http.StatusNoContent)
+		})
+
+		t.Run("case=records failed messages from topic notifications", func(t *testing.T) {
+			post(t, href, `{"Type":"Notification","Message":"{\"notification\":{\"messageId\":\"sns-123\"},\"delivery\":{\"providerResponse\":\"Phone is currently unreachable\"},\"status\":\"FAILURE\"}"}`, http.StatusNoContent)
+
+			status, dispatchErr := deliveryStatus(t, reg, d.MessageID)
+			assert.Equal(t, courier.CourierMessageDeliveryStatusUndelivered, status)
+			assert.Contains(t, dispatchErr, "Phone is currently unreachable")
+		})
+
+		t.Run("case=records delivered messages", func(t *testing.T) {
+			post(t, href, `{"notification":{"messageId":"sns-123"},"status":"SUCCESS"}`, http.StatusNoContent)
+
+			status, _ := deliveryStatus(t, reg, d.MessageID)
+			assert.Equal(t, courier.CourierMessageDeliveryStatusDelivered, status)
+		})
+	})
+}
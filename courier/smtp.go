@@ -140,7 +140,9 @@ func (c *courier) QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, e
 		RequestHeaders: requestHeaders,
 	}
 
-	if err := c.deps.CourierPersister().AddMessage(ctx, message); err != nil {
+	c.setExpiry(ctx, message)
+
+	if err := c.addMessage(ctx, message); err != nil {
 		return uuid.Nil, errors.WithStack(err)
 	}
 
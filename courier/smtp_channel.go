@@ -24,6 +24,7 @@ import (
 type (
 	SMTPChannel struct {
 		smtpClient *SMTPClient
+		cfg        *config.SMTPConfig
 		d          Dependencies
 
 		newEmailTemplateFromMessage func(d template.Dependencies, msg Message) (EmailTemplate, error)
@@ -43,6 +44,7 @@ func NewSMTPChannelWithCustomTemplates(deps Dependencies, cfg *config.SMTPConfig
 	}
 	return &SMTPChannel{
 		smtpClient:                  smtpClient,
+		cfg:                         cfg,
 		d:                           deps,
 		newEmailTemplateFromMessage: newEmailTemplateFromMessage,
 	}, nil
@@ -60,19 +62,7 @@ func (c *SMTPChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 		return errors.WithStack(herodot.ErrInternalServerError.WithErrorf("Courier tried to deliver an email but %s is not set!", config.ViperKeyCourierSMTPURL))
 	}
 
-	channels, err := c.d.CourierConfig().CourierChannels(ctx)
-	if err != nil {
-		return err
-	}
-
-	var cfg *config.SMTPConfig
-	for _, channel := range channels {
-		if channel.ID == "email" && channel.SMTPConfig != nil {
-			cfg = channel.SMTPConfig
-			break
-		}
-	}
-
+	cfg := c.cfg
 	if cfg == nil {
 		return errors.WithStack(herodot.ErrInternalServerError.WithErrorf("Courier tried to deliver an email but SMTP channel is misconfigured."))
 	}
@@ -153,7 +143,7 @@ func (c *SMTPChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 		case errors.As(err, &protoErr) && protoErr.Code >= 500:
 			// See https://en.wikipedia.org/wiki/List_of_SMTP_server_return_codes
 			// If the SMTP server responds with 5xx, sending the message should not be retried (without changing something about the request)
-			if err := c.d.CourierPersister().SetMessageStatus(ctx, msg.ID, MessageStatusAbandoned); err != nil {
+			if err := c.d.CourierPersister().SetMessageStatus(ctx, msg.ID, msg.WorkerID.String(), MessageStatusAbandoned); err != nil {
 				logger.
 					WithError(err).
 					Error(`Unable to reset the retried message's status to "abandoned".`)
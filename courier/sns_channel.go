@@ -0,0 +1,224 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/otelx"
+)
+
+// snsChannel sends SMS using the Amazon SNS API or a compatible API.
+type snsChannel struct {
+	id   string
+	name string
+	conf *config.SNSConfig
+	d    channelDependencies
+	now  func() time.Time
+}
+
+var _ ReceiptChannel = new(snsChannel)
+
+func newSNSChannel(id, name string, conf *config.SNSConfig, d channelDependencies) *snsChannel {
+	return &snsChannel{id: id, name: name, conf: conf, d: d, now: time.Now}
+}
+
+func (c *snsChannel) ID() string {
+	return c.id
+}
+
+func (c *snsChannel) Dispatch(ctx context.Context, msg Message) error {
+	_, err := c.DispatchWithProviderMessageID(ctx, msg)
+	return err
+}
+
+func (c *snsChannel) DispatchWithProviderMessageID(ctx context.Context, msg Message) (_ string, err error) {
+	ctx, span := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.snsChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	endpoint := c.conf.BaseURL
+	if endpoint == "" {
+		endpoint = "https://sns." + c.conf.Region + ".amazonaws.com/"
+	}
+
+	form := url.Values{
+		"Action":                         {"Publish"},
+		"Version":                        {"2010-03-31"},
+		"PhoneNumber":                    {msg.Recipient},
+		"Message":                        {msg.Body},
+		"MessageAttributes.entry.1.Name": {"AWS.SNS.SMS.SMSType"},
+		"MessageAttributes.entry.1.Value.DataType":    {"String"},
+		"MessageAttributes.entry.1.Value.StringValue": {"Transactional"},
+	}
+	if c.conf.SenderID != "" {
+		form.Set("MessageAttributes.entry.2.Name", "AWS.SNS.SMS.SenderID")
+		form.Set("MessageAttributes.entry.2.Value.DataType", "String")
+		form.Set("MessageAttributes.entry.2.Value.StringValue", c.conf.SenderID)
+	}
+	body := []byte(form.Encode())
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSV4(req.Request, body, c.conf.Region, "sns", c.conf.AccessKeyID, c.conf.SecretAccessKey, c.now().UTC())
+
+	status, resBody, err := doProviderRequest(ctx, c.d, req)
+	if err != nil {
+		return "", err
+	}
+
+	if status < 200 || status >= 300 {
+		var res struct {
+			Error struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		_ = xml.Unmarshal(resBody, &res)
+		return "", errors.Errorf("unable to dispatch SMS because the SNS API replied with status code %d and error %s: %s", status, res.Error.Code, res.Error.Message)
+	}
+
+	var res struct {
+		MessageID string `xml:"PublishResult>MessageId"`
+	}
+	if err := xml.Unmarshal(resBody, &res); err != nil {
+		return "", errors.Wrapf(err, "unable to decode the response of the SNS API")
+	}
+	if res.MessageID == "" {
+		return "", errors.New("unable to dispatch SMS because the SNS API did not return a message ID")
+	}
+	return res.MessageID, nil
+}
+
+// ParseDeliveryReceipts accepts SMS delivery status logs, either directly or
+// as notifications of an SNS topic subscription.
+func (c *snsChannel) ParseDeliveryReceipts(r *http.Request) ([]DeliveryReceipt, error) {
+	if err := verifyReceiptToken(r, c.conf.DeliveryReceiptSecret); err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxProviderResponseSize))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var envelope struct {
+		Type         string `json:"Type"`
+		Message      string `json:"Message"`
+		SubscribeURL string `json:"SubscribeURL"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt is malformed.").WithWrap(err))
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		// Confirming the subscription would require requesting a URL chosen by
+		// the caller, so it is left to the operator.
+		c.d.Logger().
+			WithField("channel_name", c.name).
+			WithField("subscribe_url", envelope.SubscribeURL).
+			Warn("Received an SNS subscription confirmation for delivery receipts. Visit the subscribe URL to confirm the subscription.")
+		return nil, nil
+	case "Notification":
+		body = []byte(envelope.Message)
+	}
+
+	var log struct {
+		Notification struct {
+			MessageID string `json:"messageId"`
+		} `json:"notification"`
+		Delivery struct {
+			ProviderResponse string `json:"providerResponse"`
+		} `json:"delivery"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &log); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt is malformed.").WithWrap(err))
+	}
+
+	receipt := DeliveryReceipt{ProviderMessageID: log.Notification.MessageID}
+	switch log.Status {
+	case "SUCCESS":
+		receipt.Status = CourierMessageDeliveryStatusDelivered
+	case "FAILURE":
+		receipt.Status = CourierMessageDeliveryStatusUndelivered
+		receipt.Error = log.Delivery.ProviderResponse
+	default:
+		return nil, nil
+	}
+
+	if receipt.ProviderMessageID == "" {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt does not contain a message ID."))
+	}
+	return []DeliveryReceipt{receipt}, nil
+}
+
+// signAWSV4 signs the request with AWS Signature Version 4.
+func signAWSV4(req *http.Request, body []byte, region, service, accessKeyID, secretAccessKey string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	const signedHeaders = "content-type;host;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.Query().Encode(),
+		"content-type:" + req.Header.Get("Content-Type") + "\n" +
+			"host:" + req.URL.Host + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+hex.EncodeToString(hmacSHA256(key, stringToSign)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
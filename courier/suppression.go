@@ -0,0 +1,101 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+// A Suppression's Reason
+//
+// swagger:enum SuppressionReason
+type SuppressionReason string
+
+const (
+	// SuppressionReasonBounced is used for addresses which bounced.
+	SuppressionReasonBounced SuppressionReason = "bounced"
+	// SuppressionReasonComplained is used for recipients who marked a message
+	// as spam.
+	SuppressionReasonComplained SuppressionReason = "complained"
+	// SuppressionReasonManual is used for recipients which were blocked by an
+	// administrator.
+	SuppressionReasonManual SuppressionReason = "manual"
+)
+
+// Suppression reasons which are recorded on messages that were suppressed
+// because of a rate limit. They can not be used for suppression list entries.
+const (
+	suppressionReasonRateLimitRecipient    = "rate_limit_recipient"
+	suppressionReasonRateLimitTemplateType = "rate_limit_template_type"
+)
+
+func (r SuppressionReason) IsValid() error {
+	switch r {
+	case SuppressionReasonBounced, SuppressionReasonComplained, SuppressionReasonManual:
+		return nil
+	default:
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The suppression reason must be one of bounced, complained, or manual, but got %q.", r))
+	}
+}
+
+// Suppression is an entry of the suppression list. Messages to suppressed
+// recipients are stored with the status `suppressed` and are not delivered.
+//
+// swagger:model courierSuppression
+type Suppression struct {
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	NID uuid.UUID `json:"-" faker:"-" db:"nid"`
+
+	// Recipient is the suppressed email address or phone number.
+	//
+	// required: true
+	Recipient string `json:"recipient" db:"recipient"`
+
+	// required: true
+	Reason SuppressionReason `json:"reason" db:"reason"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	// required: true
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	// required: true
+	UpdatedAt time.Time `json:"updated_at" faker:"-" db:"updated_at"`
+}
+
+func (s Suppression) PageToken() keysetpagination.PageToken {
+	return keysetpagination.NewPageToken(
+		keysetpagination.Column{
+			Name:  "created_at",
+			Order: keysetpagination.OrderDescending,
+			Value: s.CreatedAt,
+		}, keysetpagination.Column{
+			Name:  "id",
+			Value: s.ID,
+		},
+	)
+}
+
+func (s Suppression) DefaultPageToken() keysetpagination.PageToken {
+	return Suppression{ID: uuid.Nil, CreatedAt: time.Date(2200, 12, 31, 23, 59, 59, 0, time.UTC)}.PageToken()
+}
+
+func (s Suppression) TableName() string { return "courier_suppressions" }
+func (s *Suppression) GetID() uuid.UUID { return s.ID }
+
+// NormalizeRecipient normalizes an email address or phone number, so that the
+// suppression list and the rate limits match it regardless of its case and
+// surrounding spaces. Messages are stored with the recipient as given and
+// compared with LOWER(TRIM(recipient)), which matches this normalization.
+func NormalizeRecipient(recipient string) string {
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
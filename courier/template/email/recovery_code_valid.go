@@ -24,6 +24,7 @@ type (
 		Identity           map[string]interface{} `json:"identity"`
 		RequestURL         string                 `json:"request_url"`
 		TransientPayload   map[string]interface{} `json:"transient_payload"`
+		Locale             string                 `json:"locale,omitempty"`
 		ExpiresInMinutes   int                    `json:"expires_in_minutes"`
 		UserRequestHeaders http.Header            `json:"-"`
 	}
@@ -38,23 +39,27 @@ func (t *RecoveryCodeValid) EmailRecipient() (string, error) {
 }
 
 func (t *RecoveryCodeValid) EmailSubject(ctx context.Context) (string, error) {
-	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "recovery_code/valid/email.subject.gotmpl", "recovery_code/valid/email.subject*", t.model, t.deps.CourierConfig().CourierTemplatesRecoveryCodeValid(ctx).Subject)
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "recovery_code/valid/email.subject.gotmpl", "recovery_code/valid/email.subject*", t.model.Locale, t.model, t.deps.CourierConfig().CourierTemplatesRecoveryCodeValid(ctx).Subject)
 
 	return strings.TrimSpace(subject), err
 }
 
 func (t *RecoveryCodeValid) EmailBody(ctx context.Context) (string, error) {
-	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "recovery_code/valid/email.body.gotmpl", "recovery_code/valid/email.body*", t.model, t.deps.CourierConfig().CourierTemplatesRecoveryCodeValid(ctx).Body.HTML)
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "recovery_code/valid/email.body.gotmpl", "recovery_code/valid/email.body*", t.model.Locale, t.model, t.deps.CourierConfig().CourierTemplatesRecoveryCodeValid(ctx).Body.HTML)
 }
 
 func (t *RecoveryCodeValid) EmailBodyPlaintext(ctx context.Context) (string, error) {
-	return template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "recovery_code/valid/email.body.plaintext.gotmpl", "recovery_code/valid/email.body.plaintext*", t.model, t.deps.CourierConfig().CourierTemplatesRecoveryCodeValid(ctx).Body.PlainText)
+	return template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), "recovery_code/valid/email.body.plaintext.gotmpl", "recovery_code/valid/email.body.plaintext*", t.model.Locale, t.model, t.deps.CourierConfig().CourierTemplatesRecoveryCodeValid(ctx).Body.PlainText)
 }
 
 func (t *RecoveryCodeValid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.model)
 }
 
+func (t *RecoveryCodeValid) Locale() string {
+	return t.model.Locale
+}
+
 func (t *RecoveryCodeValid) TemplateType() template.TemplateType {
 	return template.TypeRecoveryCodeValid
 }
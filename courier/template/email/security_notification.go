@@ -0,0 +1,80 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ory/kratos/courier/template"
+)
+
+type (
+	// SecurityNotification informs an identity about a sensitive event of its
+	// account. The template type names the event, and the templates are loaded
+	// from `security_notification/<type>/`.
+	SecurityNotification struct {
+		deps  template.Dependencies
+		typ   template.TemplateType
+		model *SecurityNotificationModel
+	}
+	SecurityNotificationModel struct {
+		To       string                 `json:"to"`
+		Identity map[string]interface{} `json:"identity"`
+		// Method is the credentials type of the added or removed second factor.
+		Method string `json:"method,omitempty"`
+		// Provider is the ID of the linked social sign in provider.
+		Provider string `json:"provider,omitempty"`
+		// Device is the device of the login from a new device.
+		Device     *SecurityNotificationDevice `json:"device,omitempty"`
+		OccurredAt time.Time                   `json:"occurred_at"`
+		Locale     string                      `json:"locale,omitempty"`
+	}
+	SecurityNotificationDevice struct {
+		IPAddress string `json:"ip_address"`
+		UserAgent string `json:"user_agent"`
+		Location  string `json:"location"`
+	}
+)
+
+func NewSecurityNotification(d template.Dependencies, typ template.TemplateType, m *SecurityNotificationModel) *SecurityNotification {
+	return &SecurityNotification{deps: d, typ: typ, model: m}
+}
+
+func (t *SecurityNotification) EmailRecipient() (string, error) {
+	return t.model.To, nil
+}
+
+func (t *SecurityNotification) EmailSubject(ctx context.Context) (string, error) {
+	subject, err := template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), t.dir()+"email.subject.gotmpl", t.dir()+"email.subject*", t.model.Locale, t.model, t.deps.CourierConfig().CourierTemplatesSecurityNotification(ctx, string(t.typ)).Subject)
+
+	return strings.TrimSpace(subject), err
+}
+
+func (t *SecurityNotification) EmailBody(ctx context.Context) (string, error) {
+	return template.LoadHTML(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), t.dir()+"email.body.gotmpl", t.dir()+"email.body*", t.model.Locale, t.model, t.deps.CourierConfig().CourierTemplatesSecurityNotification(ctx, string(t.typ)).Body.HTML)
+}
+
+func (t *SecurityNotification) EmailBodyPlaintext(ctx context.Context) (string, error) {
+	return template.LoadText(ctx, t.deps, os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)), t.dir()+"email.body.plaintext.gotmpl", t.dir()+"email.body.plaintext*", t.model.Locale, t.model, t.deps.CourierConfig().CourierTemplatesSecurityNotification(ctx, string(t.typ)).Body.PlainText)
+}
+
+func (t *SecurityNotification) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.model)
+}
+
+func (t *SecurityNotification) Locale() string {
+	return t.model.Locale
+}
+
+func (t *SecurityNotification) TemplateType() template.TemplateType {
+	return t.typ
+}
+
+func (t *SecurityNotification) dir() string {
+	return "security_notification/" + string(t.typ) + "/"
+}
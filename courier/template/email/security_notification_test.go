@@ -0,0 +1,58 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package email_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/courier/template/testhelpers"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+)
+
+func TestSecurityNotification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	for _, typ := range []template.TemplateType{
+		template.TypePasswordChanged,
+		template.TypeMFAMethodAdded,
+		template.TypeMFAMethodRemoved,
+		template.TypeOIDCProviderLinked,
+		template.TypeSessionsRevoked,
+		template.TypeNewDeviceLogin,
+		template.TypeAccountDeleted,
+	} {
+		t.Run("type="+string(typ), func(t *testing.T) {
+			t.Run("test=with courier templates directory", func(t *testing.T) {
+				_, reg := internal.NewFastRegistryWithMocks(t)
+				tpl := email.NewSecurityNotification(reg, typ, &email.SecurityNotificationModel{
+					Device: &email.SecurityNotificationDevice{UserAgent: "agent"},
+				})
+
+				testhelpers.TestRendered(t, ctx, tpl)
+				assert.Equal(t, typ, tpl.TemplateType())
+			})
+
+			t.Run("test=with configured template", func(t *testing.T) {
+				_, reg := internal.NewFastRegistryWithMocks(t)
+				require.NoError(t, reg.Config().Set(ctx, config.ViperKeyCourierTemplatesSecurityNotification+"."+string(typ)+".email", &config.CourierEmailTemplate{
+					Body:    &config.CourierEmailBodyTemplate{},
+					Subject: "base64://T3ZlcnJpZGRlbg==",
+				}))
+				tpl := email.NewSecurityNotification(reg, typ, &email.SecurityNotificationModel{})
+
+				subject, err := tpl.EmailSubject(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, "Overridden", subject)
+			})
+		})
+	}
+}
@@ -21,6 +21,7 @@ type (
 		To               string                 `json:"to"`
 		RequestURL       string                 `json:"request_url"`
 		TransientPayload map[string]interface{} `json:"transient_payload"`
+		Locale           string                 `json:"locale,omitempty"`
 	}
 )
 
@@ -39,6 +40,7 @@ func (t *VerificationCodeInvalid) EmailSubject(ctx context.Context) (string, err
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/invalid/email.subject.gotmpl",
 		"verification_code/invalid/email.subject*",
+		t.m.Locale,
 		t.m,
 		t.d.CourierConfig().CourierTemplatesVerificationCodeInvalid(ctx).Subject,
 	)
@@ -53,6 +55,7 @@ func (t *VerificationCodeInvalid) EmailBody(ctx context.Context) (string, error)
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/invalid/email.body.gotmpl",
 		"verification_code/invalid/email.body*",
+		t.m.Locale,
 		t.m,
 		t.d.CourierConfig().CourierTemplatesVerificationCodeInvalid(ctx).Body.HTML,
 	)
@@ -65,6 +68,7 @@ func (t *VerificationCodeInvalid) EmailBodyPlaintext(ctx context.Context) (strin
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/invalid/email.body.plaintext.gotmpl",
 		"verification_code/invalid/email.body.plaintext*",
+		t.m.Locale,
 		t.m,
 		t.d.CourierConfig().CourierTemplatesVerificationCodeInvalid(ctx).Body.PlainText,
 	)
@@ -74,6 +78,10 @@ func (t *VerificationCodeInvalid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.m)
 }
 
+func (t *VerificationCodeInvalid) Locale() string {
+	return t.m.Locale
+}
+
 func (t *VerificationCodeInvalid) TemplateType() template.TemplateType {
 	return template.TypeVerificationCodeInvalid
 }
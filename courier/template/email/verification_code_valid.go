@@ -24,6 +24,7 @@ type (
 		Identity         map[string]interface{} `json:"identity"`
 		RequestURL       string                 `json:"request_url"`
 		TransientPayload map[string]interface{} `json:"transient_payload"`
+		Locale           string                 `json:"locale,omitempty"`
 		ExpiresInMinutes int                    `json:"expires_in_minutes"`
 	}
 )
@@ -43,6 +44,7 @@ func (t *VerificationCodeValid) EmailSubject(ctx context.Context) (string, error
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/valid/email.subject.gotmpl",
 		"verification_code/valid/email.subject*",
+		t.m.Locale,
 		t.m,
 		t.d.CourierConfig().CourierTemplatesVerificationCodeValid(ctx).Subject,
 	)
@@ -56,6 +58,7 @@ func (t *VerificationCodeValid) EmailBody(ctx context.Context) (string, error) {
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/valid/email.body.gotmpl",
 		"verification_code/valid/email.body*",
+		t.m.Locale,
 		t.m,
 		t.d.CourierConfig().CourierTemplatesVerificationCodeValid(ctx).Body.HTML,
 	)
@@ -67,6 +70,7 @@ func (t *VerificationCodeValid) EmailBodyPlaintext(ctx context.Context) (string,
 		os.DirFS(t.d.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/valid/email.body.plaintext.gotmpl",
 		"verification_code/valid/email.body.plaintext*",
+		t.m.Locale,
 		t.m,
 		t.d.CourierConfig().CourierTemplatesVerificationCodeValid(ctx).Body.PlainText,
 	)
@@ -76,6 +80,10 @@ func (t *VerificationCodeValid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.m)
 }
 
+func (t *VerificationCodeValid) Locale() string {
+	return t.m.Locale
+}
+
 func (t *VerificationCodeValid) TemplateType() template.TemplateType {
 	return template.TypeVerificationCodeValid
 }
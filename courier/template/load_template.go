@@ -104,7 +104,8 @@ func loadRemoteTemplate(ctx context.Context, d templateDependencies, url string,
 	return t, nil
 }
 
-func loadTemplate(filesystem fs.FS, name, pattern string, html bool) (Template, error) {
+func loadTemplate(filesystem fs.FS, name, pattern, locale string, html bool) (Template, error) {
+	name = localizedName(filesystem, name, locale)
 	if t, found := Cache.Get(name); found {
 		return t, nil
 	}
@@ -145,7 +146,7 @@ func loadTemplate(filesystem fs.FS, name, pattern string, html bool) (Template,
 	return tpl, nil
 }
 
-func LoadText(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, model interface{}, remoteURL string) (string, error) {
+func LoadText(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern, locale string, model interface{}, remoteURL string) (string, error) {
 	var t Template
 	var err error
 	if remoteURL != "" {
@@ -154,7 +155,7 @@ func LoadText(ctx context.Context, d templateDependencies, filesystem fs.FS, nam
 			return "", err
 		}
 	} else {
-		t, err = loadTemplate(filesystem, name, pattern, false)
+		t, err = loadTemplate(filesystem, name, pattern, locale, false)
 		if err != nil {
 			return "", err
 		}
@@ -167,7 +168,7 @@ func LoadText(ctx context.Context, d templateDependencies, filesystem fs.FS, nam
 	return b.String(), nil
 }
 
-func LoadHTML(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern string, model interface{}, remoteURL string) (string, error) {
+func LoadHTML(ctx context.Context, d templateDependencies, filesystem fs.FS, name, pattern, locale string, model interface{}, remoteURL string) (string, error) {
 	var t Template
 	var err error
 	if remoteURL != "" {
@@ -176,7 +177,7 @@ func LoadHTML(ctx context.Context, d templateDependencies, filesystem fs.FS, nam
 			return "", err
 		}
 	} else {
-		t, err = loadTemplate(filesystem, name, pattern, true)
+		t, err = loadTemplate(filesystem, name, pattern, locale, true)
 		if err != nil {
 			return "", err
 		}
@@ -29,14 +29,14 @@ import (
 func TestLoadTextTemplate(t *testing.T) {
 	executeTextTemplate := func(t *testing.T, dir, name, pattern string, model map[string]interface{}) string {
 		_, reg := internal.NewFastRegistryWithMocks(t)
-		tp, err := template.LoadText(t.Context(), reg, os.DirFS(dir), name, pattern, model, "")
+		tp, err := template.LoadText(t.Context(), reg, os.DirFS(dir), name, pattern, "", model, "")
 		require.NoError(t, err)
 		return tp
 	}
 
 	executeHTMLTemplate := func(t *testing.T, dir, name, pattern string, model map[string]interface{}) string {
 		_, reg := internal.NewFastRegistryWithMocks(t)
-		tp, err := template.LoadHTML(t.Context(), reg, os.DirFS(dir), name, pattern, model, "")
+		tp, err := template.LoadHTML(t.Context(), reg, os.DirFS(dir), name, pattern, "", model, "")
 		require.NoError(t, err)
 		return tp
 	}
@@ -67,7 +67,7 @@ func TestLoadTextTemplate(t *testing.T) {
 
 		for _, tc := range nonhermetic {
 			t.Run("case=should not support function: "+tc, func(t *testing.T) {
-				_, err := template.LoadText(t.Context(), reg, x.NewStubFS(tc, []byte(fmt.Sprintf("{{ %s }}", tc))), tc, "", map[string]interface{}{}, "")
+				_, err := template.LoadText(t.Context(), reg, x.NewStubFS(tc, []byte(fmt.Sprintf("{{ %s }}", tc))), tc, "", "", map[string]interface{}{}, "")
 				assert.ErrorContains(t, err, fmt.Sprintf("function %q not defined", tc))
 			})
 		}
@@ -80,6 +80,38 @@ func TestLoadTextTemplate(t *testing.T) {
 		assert.Contains(t, actual, "lang=en_US")
 	})
 
+	t.Run("method=localized", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "recovery"), 0o700))
+		for name, body := range map[string]string{
+			"email.body.gotmpl":       "default body",
+			"email.body.de.gotmpl":    "deutscher Text",
+			"email.body.pt_BR.gotmpl": "texto em português",
+		} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "recovery", name), []byte(body), 0o600))
+		}
+
+		_, reg := internal.NewFastRegistryWithMocks(t)
+		for _, tc := range []struct {
+			locale, expected string
+		}{
+			{locale: "", expected: "default body"},
+			{locale: "de", expected: "deutscher Text"},
+			{locale: "de-CH", expected: "deutscher Text"},
+			{locale: "pt-BR", expected: "texto em português"},
+			{locale: "pt-PT", expected: "default body"},
+			{locale: "fr", expected: "default body"},
+			{locale: "../recovery", expected: "default body"},
+		} {
+			t.Run("locale="+tc.locale, func(t *testing.T) {
+				template.Cache, _ = lru.New[string, template.Template](16) // prevent Cache hit
+				actual, err := template.LoadText(t.Context(), reg, os.DirFS(dir), "recovery/email.body.gotmpl", "recovery/email.body*", tc.locale, nil, "")
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			})
+		}
+	})
+
 	t.Run("method=Cache works", func(t *testing.T) {
 		dir := os.TempDir()
 		name := x.NewUUID().String() + ".body.gotmpl"
@@ -104,7 +136,7 @@ func TestLoadTextTemplate(t *testing.T) {
 				f, err := os.ReadFile("courier/builtin/templates/test_stub/email.body.html.en_US.gotmpl")
 				require.NoError(t, err)
 				b64 := base64.StdEncoding.EncodeToString(f)
-				tp, err := template.LoadHTML(ctx, reg, nil, "", "", m, "base64://"+b64)
+				tp, err := template.LoadHTML(ctx, reg, nil, "", "", "", m, "base64://"+b64)
 				require.NoError(t, err)
 				assert.Contains(t, tp, "lang=en_US")
 			})
@@ -116,7 +148,7 @@ func TestLoadTextTemplate(t *testing.T) {
 
 				b64 := base64.StdEncoding.EncodeToString(f)
 
-				tp, err := template.LoadText(ctx, reg, nil, "", "", m, "base64://"+b64)
+				tp, err := template.LoadText(ctx, reg, nil, "", "", "", m, "base64://"+b64)
 				require.NoError(t, err)
 				assert.Contains(t, tp, "stub email body something")
 			})
@@ -125,14 +157,14 @@ func TestLoadTextTemplate(t *testing.T) {
 		t.Run("case=file resource", func(t *testing.T) {
 			t.Run("case=html template", func(t *testing.T) {
 				m := map[string]interface{}{"lang": "en_US"}
-				tp, err := template.LoadHTML(ctx, reg, nil, "", "", m, "file://courier/builtin/templates/test_stub/email.body.html.en_US.gotmpl")
+				tp, err := template.LoadHTML(ctx, reg, nil, "", "", "", m, "file://courier/builtin/templates/test_stub/email.body.html.en_US.gotmpl")
 				require.NoError(t, err)
 				assert.Contains(t, tp, "lang=en_US")
 			})
 
 			t.Run("case=plaintext", func(t *testing.T) {
 				m := map[string]interface{}{"Body": "something"}
-				tp, err := template.LoadText(ctx, reg, nil, "", "", m, "file://courier/builtin/templates/test_stub/email.body.plaintext.gotmpl")
+				tp, err := template.LoadText(ctx, reg, nil, "", "", "", m, "file://courier/builtin/templates/test_stub/email.body.plaintext.gotmpl")
 				require.NoError(t, err)
 				assert.Contains(t, tp, "stub email body something")
 			})
@@ -151,25 +183,25 @@ func TestLoadTextTemplate(t *testing.T) {
 
 			t.Run("case=html template", func(t *testing.T) {
 				m := map[string]interface{}{"lang": "en_US"}
-				tp, err := template.LoadHTML(ctx, reg, nil, "", "", m, ts.URL+"/html")
+				tp, err := template.LoadHTML(ctx, reg, nil, "", "", "", m, ts.URL+"/html")
 				require.NoError(t, err)
 				assert.Contains(t, tp, "lang=en_US")
 			})
 
 			t.Run("case=plaintext", func(t *testing.T) {
 				m := map[string]interface{}{"Body": "something"}
-				tp, err := template.LoadText(ctx, reg, nil, "", "", m, ts.URL+"/plaintext")
+				tp, err := template.LoadText(ctx, reg, nil, "", "", "", m, ts.URL+"/plaintext")
 				require.NoError(t, err)
 				assert.Contains(t, tp, "stub email body something")
 			})
 		})
 
 		t.Run("case=unsupported resource", func(t *testing.T) {
-			tp, err := template.LoadHTML(ctx, reg, nil, "", "", map[string]interface{}{}, "grpc://unsupported-url")
+			tp, err := template.LoadHTML(ctx, reg, nil, "", "", "", map[string]interface{}{}, "grpc://unsupported-url")
 			require.ErrorIs(t, err, fetcher.ErrUnknownScheme)
 			require.Empty(t, tp)
 
-			tp, err = template.LoadText(ctx, reg, nil, "", "", map[string]interface{}{}, "grpc://unsupported-url")
+			tp, err = template.LoadText(ctx, reg, nil, "", "", "", map[string]interface{}{}, "grpc://unsupported-url")
 			require.ErrorIs(t, err, fetcher.ErrUnknownScheme)
 			require.Empty(t, tp)
 		})
@@ -179,18 +211,18 @@ func TestLoadTextTemplate(t *testing.T) {
 			reg.HTTPClient(ctx).RetryMax = 1
 			reg.HTTPClient(ctx).RetryWaitMax = time.Millisecond
 
-			_, err := template.LoadHTML(ctx, reg, nil, "", "", map[string]interface{}{}, "http://localhost:8080/1234")
+			_, err := template.LoadHTML(ctx, reg, nil, "", "", "", map[string]interface{}{}, "http://localhost:8080/1234")
 			assert.ErrorContains(t, err, "is not a permitted destination")
 
-			_, err = template.LoadText(ctx, reg, nil, "", "", map[string]interface{}{}, "http://localhost:8080/1234")
+			_, err = template.LoadText(ctx, reg, nil, "", "", "", map[string]interface{}{}, "http://localhost:8080/1234")
 			assert.ErrorContains(t, err, "is not a permitted destination")
 		})
 
 		t.Run("method=cache works", func(t *testing.T) {
-			tp1, err := template.LoadText(ctx, reg, nil, "", "", map[string]interface{}{}, "base64://e3sgJGwgOj0gY2F0ICJsYW5nPSIgLmxhbmcgfX0Ke3sgbm9zcGFjZSAkbCB9fQ==")
+			tp1, err := template.LoadText(ctx, reg, nil, "", "", "", map[string]interface{}{}, "base64://e3sgJGwgOj0gY2F0ICJsYW5nPSIgLmxhbmcgfX0Ke3sgbm9zcGFjZSAkbCB9fQ==")
 			require.NoError(t, err)
 
-			tp2, err := template.LoadText(ctx, reg, nil, "", "", map[string]interface{}{}, "base64://c3R1YiBlbWFpbCBib2R5IHt7IC5Cb2R5IH19")
+			tp2, err := template.LoadText(ctx, reg, nil, "", "", "", map[string]interface{}{}, "base64://c3R1YiBlbWFpbCBib2R5IHt7IC5Cb2R5IH19")
 			require.NoError(t, err)
 
 			assert.NotEqualf(t, tp1, tp2, "Expected remote template 1 and remote template 2 to not be equal")
@@ -0,0 +1,56 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"golang.org/x/text/language"
+
+	"github.com/ory/kratos/x"
+)
+
+// ResolveLocale returns the locale in which messages to an identity are
+// rendered. It is read from the identity trait or public metadata key
+// configured in `courier.locale`. If neither is set, the fallback is used,
+// which usually is the preferred language of the browser which started the
+// flow. The result is a canonical BCP 47 tag or empty.
+func ResolveLocale(ctx context.Context, d Dependencies, traits, metadataPublic []byte, fallback string) string {
+	if path := d.CourierConfig().CourierLocaleTrait(ctx); path != "" && len(traits) > 0 {
+		if locale := x.NormalizeLocale(gjson.GetBytes(traits, path).String()); locale != "" {
+			return locale
+		}
+	}
+	if path := d.CourierConfig().CourierLocaleMetadataPublic(ctx); path != "" && len(metadataPublic) > 0 {
+		if locale := x.NormalizeLocale(gjson.GetBytes(metadataPublic, path).String()); locale != "" {
+			return locale
+		}
+	}
+	return x.NormalizeLocale(fallback)
+}
+
+// localizedName returns the name of the template for the locale if the
+// filesystem contains one, and the name as is otherwise. For the locale "de-CH"
+// and the name "email.body.gotmpl", "email.body.de-CH.gotmpl",
+// "email.body.de_CH.gotmpl", and "email.body.de.gotmpl" are tried in order.
+func localizedName(filesystem fs.FS, name, locale string) string {
+	tag, err := language.Parse(locale)
+	if locale == "" || err != nil {
+		return name
+	}
+	base, _ := tag.Base()
+
+	candidates := slices.Compact([]string{tag.String(), strings.ReplaceAll(tag.String(), "-", "_"), base.String()})
+	for _, candidate := range candidates {
+		localized := strings.TrimSuffix(name, ".gotmpl") + "." + candidate + ".gotmpl"
+		if _, err := fs.Stat(filesystem, localized); err == nil {
+			return localized
+		}
+	}
+	return name
+}
@@ -0,0 +1,49 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package template_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/x/contextx"
+)
+
+func TestResolveLocale(t *testing.T) {
+	_, reg := internal.NewFastRegistryWithMocks(t)
+
+	traits := []byte(`{"email":"foo@ory.sh","preferences":{"language":"de_CH"}}`)
+	metadataPublic := []byte(`{"locale":"fr"}`)
+
+	for _, tc := range []struct {
+		name, trait, metadata, fallback, expected string
+		traits, metadataPublic                    []byte
+	}{
+		{name: "no configuration", fallback: "en-US", expected: "en-US"},
+		{name: "no configuration and no fallback", expected: ""},
+		{name: "trait", trait: "preferences.language", metadata: "locale", fallback: "en", expected: "de-CH"},
+		{name: "missing trait", trait: "locale", metadata: "locale", fallback: "en", expected: "fr"},
+		{name: "metadata", metadata: "locale", fallback: "en", expected: "fr"},
+		{name: "missing metadata", metadata: "language", fallback: "en", expected: "en"},
+		{name: "unknown identity", trait: "preferences.language", metadata: "locale", fallback: "en", traits: []byte{}, metadataPublic: []byte{}, expected: "en"},
+		{name: "invalid trait", trait: "email", fallback: "en", expected: "en"},
+		{name: "invalid fallback", fallback: "not a locale", expected: ""},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			ctx := contextx.WithConfigValues(t.Context(), map[string]any{
+				config.ViperKeyCourierLocaleTrait:          tc.trait,
+				config.ViperKeyCourierLocaleMetadataPublic: tc.metadata,
+			})
+			tr, md := traits, metadataPublic
+			if tc.traits != nil {
+				tr, md = tc.traits, tc.metadataPublic
+			}
+			assert.Equal(t, tc.expected, template.ResolveLocale(ctx, reg, tr, md, tc.fallback))
+		})
+	}
+}
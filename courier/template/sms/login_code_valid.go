@@ -23,6 +23,7 @@ type (
 		Identity           map[string]any `json:"identity"`
 		RequestURL         string         `json:"request_url"`
 		TransientPayload   map[string]any `json:"transient_payload"`
+		Locale             string         `json:"locale,omitempty"`
 		ExpiresInMinutes   int            `json:"expires_in_minutes"`
 		UserRequestHeaders http.Header    `json:"-"`
 	}
@@ -43,6 +44,7 @@ func (t *LoginCodeValid) SMSBody(ctx context.Context) (string, error) {
 		os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)),
 		"login_code/valid/sms.body.gotmpl",
 		"login_code/valid/sms.body*",
+		t.model.Locale,
 		t.model,
 		t.deps.CourierConfig().CourierSMSTemplatesLoginCodeValid(ctx).Body.PlainText,
 	)
@@ -52,6 +54,10 @@ func (t *LoginCodeValid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.model)
 }
 
+func (t *LoginCodeValid) Locale() string {
+	return t.model.Locale
+}
+
 func (t *LoginCodeValid) TemplateType() template.TemplateType {
 	return template.TypeLoginCodeValid
 }
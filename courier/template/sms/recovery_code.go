@@ -25,6 +25,7 @@ type (
 		RequestURL         string         `json:"request_url"`
 		RequestURLDomain   string         `json:"request_url_domain"`
 		TransientPayload   map[string]any `json:"transient_payload"`
+		Locale             string         `json:"locale,omitempty"`
 		ExpiresInMinutes   int            `json:"expires_in_minutes"`
 		UserRequestHeaders http.Header    `json:"-"`
 	}
@@ -45,6 +46,7 @@ func (t *RecoveryCodeValid) SMSBody(ctx context.Context) (string, error) {
 		os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)),
 		"recovery_code/valid/sms.body.gotmpl",
 		"recovery_code/valid/sms.body*",
+		t.model.Locale,
 		t.model,
 		t.deps.CourierConfig().CourierSMSTemplatesRecoveryCodeValid(ctx).Body.PlainText,
 	)
@@ -53,6 +55,10 @@ func (t *RecoveryCodeValid) SMSBody(ctx context.Context) (string, error) {
 func (t *RecoveryCodeValid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.model)
 }
+
+func (t *RecoveryCodeValid) Locale() string {
+	return t.model.Locale
+}
 func (t *RecoveryCodeValid) TemplateType() template.TemplateType {
 	return template.TypeRecoveryCodeValid
 }
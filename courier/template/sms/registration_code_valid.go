@@ -23,6 +23,7 @@ type (
 		Identity           map[string]any `json:"identity"`
 		RequestURL         string         `json:"request_url"`
 		TransientPayload   map[string]any `json:"transient_payload"`
+		Locale             string         `json:"locale,omitempty"`
 		ExpiresInMinutes   int            `json:"expires_in_minutes"`
 		UserRequestHeaders http.Header    `json:"-"`
 	}
@@ -43,6 +44,7 @@ func (t *RegistrationCodeValid) SMSBody(ctx context.Context) (string, error) {
 		os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)),
 		"registration_code/valid/sms.body.gotmpl",
 		"registration_code/valid/sms.body*",
+		t.model.Locale,
 		t.model,
 		t.deps.CourierConfig().CourierSMSTemplatesRegistrationCodeValid(ctx).Body.PlainText,
 	)
@@ -52,6 +54,10 @@ func (t *RegistrationCodeValid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.model)
 }
 
+func (t *RegistrationCodeValid) Locale() string {
+	return t.model.Locale
+}
+
 func (t *RegistrationCodeValid) TemplateType() template.TemplateType {
 	return template.TypeRegistrationCodeValid
 }
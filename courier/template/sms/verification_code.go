@@ -23,6 +23,7 @@ type (
 		Identity         map[string]interface{} `json:"identity"`
 		RequestURL       string                 `json:"request_url"`
 		TransientPayload map[string]interface{} `json:"transient_payload"`
+		Locale           string                 `json:"locale,omitempty"`
 		ExpiresInMinutes int                    `json:"expires_in_minutes"`
 	}
 )
@@ -42,6 +43,7 @@ func (t *VerificationCodeValid) SMSBody(ctx context.Context) (string, error) {
 		os.DirFS(t.deps.CourierConfig().CourierTemplatesRoot(ctx)),
 		"verification_code/valid/sms.body.gotmpl",
 		"verification_code/valid/sms.body*",
+		t.model.Locale,
 		t.model,
 		t.deps.CourierConfig().CourierSMSTemplatesVerificationCodeValid(ctx).Body.PlainText,
 	)
@@ -51,6 +53,10 @@ func (t *VerificationCodeValid) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.model)
 }
 
+func (t *VerificationCodeValid) Locale() string {
+	return t.model.Locale
+}
+
 func (t *VerificationCodeValid) TemplateType() template.TemplateType {
 	return template.TypeVerificationCodeValid
 }
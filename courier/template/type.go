@@ -20,4 +20,11 @@ const (
 	TypeTestStub                TemplateType = "stub"
 	TypeLoginCodeValid          TemplateType = "login_code_valid"
 	TypeRegistrationCodeValid   TemplateType = "registration_code_valid"
+	TypePasswordChanged         TemplateType = "password_changed"
+	TypeMFAMethodAdded          TemplateType = "mfa_method_added"
+	TypeMFAMethodRemoved        TemplateType = "mfa_method_removed"
+	TypeOIDCProviderLinked      TemplateType = "oidc_provider_linked"
+	TypeSessionsRevoked         TemplateType = "sessions_revoked"
+	TypeNewDeviceLogin          TemplateType = "new_device_login"
+	TypeAccountDeleted          TemplateType = "account_deleted"
 )
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/ory/pop/v6"
 	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
 	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
 )
 
 type PersisterWrapper interface {
@@ -35,9 +37,10 @@ type NetworkWrapper func(t *testing.T, ctx context.Context) (uuid.UUID, Persiste
 func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper, newNetwork NetworkWrapper) func(t *testing.T) {
 	return func(t *testing.T) {
 		nid, p := newNetworkUnlessExisting(t, ctx)
+		const workerID = "worker-0"
 
 		t.Run("case=no messages in queue", func(t *testing.T) {
-			m, err := p.NextMessages(ctx, 10)
+			m, err := p.NextMessages(ctx, 10, workerID, time.Hour)
 			require.ErrorIs(t, err, courier.ErrQueueEmpty)
 			assert.Len(t, m, 0)
 
@@ -50,6 +53,8 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 			now := time.Now()
 			for k := range messages {
 				require.NoError(t, faker.FakeData(&messages[k]))
+				// Suppressed messages are stored as such and never queued.
+				messages[k].Status = courier.MessageStatusQueued
 				require.NoError(t, p.AddMessage(ctx, &messages[k]))
 				require.NoError(t, p.GetConnection(ctx).
 					RawQuery(
@@ -74,7 +79,7 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 			for k, expected := range messages {
 				expected.Status = courier.MessageStatusProcessing
 				t.Run(fmt.Sprintf("message=%d", k), func(t *testing.T) {
-					messages, err := p.NextMessages(ctx, 1)
+					messages, err := p.NextMessages(ctx, 1, workerID, time.Hour)
 					require.NoError(t, err)
 					require.Len(t, messages, 1)
 
@@ -85,39 +90,151 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 					assert.Equal(t, expected.Status, actual.Status)
 					assert.Equal(t, expected.Type, actual.Type)
 					assert.Equal(t, expected.Recipient, actual.Recipient)
+					assert.EqualValues(t, workerID, actual.WorkerID)
+					assert.WithinDuration(t, time.Now().Add(time.Hour), time.Time(actual.LeaseExpiresAt), time.Minute)
 				})
 			}
 
-			_, err := p.NextMessages(ctx, 10)
+			_, err := p.NextMessages(ctx, 10, workerID, time.Hour)
 			require.ErrorIs(t, err, courier.ErrQueueEmpty)
 		})
 
+		t.Run("case=pull scheduled messages only once they are due", func(t *testing.T) {
+			nid, p := newNetwork(t, ctx)
+
+			m := courier.Message{
+				Type:      courier.MessageTypeEmail,
+				Recipient: "scheduled@ory.sh",
+				SendAfter: sqlxx.NullTime(time.Now().UTC().Add(time.Hour)),
+				ExpiresAt: sqlxx.NullTime(time.Now().UTC().Add(2 * time.Hour)),
+			}
+			require.NoError(t, p.AddMessage(ctx, &m))
+
+			_, err := p.NextMessages(ctx, 10, workerID, time.Hour)
+			require.ErrorIs(t, err, courier.ErrQueueEmpty)
+
+			require.NoError(t, p.GetConnection(ctx).
+				RawQuery("UPDATE courier_messages SET send_after = ? WHERE id = ? AND nid = ?", time.Now().UTC().Add(-time.Minute), m.ID, nid).
+				Exec())
+
+			messages, err := p.NextMessages(ctx, 10, workerID, time.Hour)
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, m.ID, messages[0].ID)
+			assert.WithinDuration(t, time.Time(m.ExpiresAt), time.Time(messages[0].ExpiresAt), time.Second)
+		})
+
 		t.Run("case=setting message status", func(t *testing.T) {
-			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, courier.MessageStatusQueued))
-			ms, err := p.NextMessages(ctx, 1)
+			require.ErrorIs(t, p.SetMessageStatus(ctx, messages[0].ID, "worker-1", courier.MessageStatusQueued), courier.ErrLeaseLost)
+
+			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, workerID, courier.MessageStatusQueued))
+			ms, err := p.NextMessages(ctx, 1, workerID, time.Hour)
 			require.NoError(t, err)
 			require.Len(t, ms, 1)
 			assert.Equal(t, messages[0].ID, ms[0].ID)
 
-			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, courier.MessageStatusSent))
-			_, err = p.NextMessages(ctx, 1)
+			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, workerID, courier.MessageStatusSent))
+			_, err = p.NextMessages(ctx, 1, workerID, time.Hour)
 			require.ErrorIs(t, err, courier.ErrQueueEmpty)
 
-			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, courier.MessageStatusAbandoned))
-			_, err = p.NextMessages(ctx, 1)
-			require.ErrorIs(t, err, courier.ErrQueueEmpty)
+			// The message is no longer processing.
+			require.ErrorIs(t, p.SetMessageStatus(ctx, messages[0].ID, workerID, courier.MessageStatusAbandoned), courier.ErrLeaseLost)
+			actual, err := p.FetchMessage(ctx, messages[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusSent, actual.Status)
+		})
+
+		t.Run("case=renewing the lease", func(t *testing.T) {
+			require.NoError(t, p.RequeueMessage(ctx, messages[0].ID, []courier.MessageStatus{courier.MessageStatusSent}))
+			ms, err := p.NextMessages(ctx, 1, workerID, time.Minute)
+			require.NoError(t, err)
+			require.Len(t, ms, 1)
+			assert.Equal(t, messages[0].ID, ms[0].ID)
+
+			require.NoError(t, p.RenewMessageLease(ctx, messages[0].ID, workerID, time.Hour))
+			actual, err := p.FetchMessage(ctx, messages[0].ID)
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), time.Time(actual.LeaseExpiresAt), time.Minute)
+
+			require.ErrorIs(t, p.RenewMessageLease(ctx, messages[0].ID, "worker-1", time.Hour), courier.ErrLeaseLost)
 		})
 
 		t.Run("case=incrementing send count", func(t *testing.T) {
-			originalSendCount := messages[0].SendCount
-			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, courier.MessageStatusQueued))
+			expected, err := p.FetchMessage(ctx, messages[0].ID)
+			require.NoError(t, err)
+
+			require.NoError(t, p.IncrementMessageSendCount(ctx, messages[0].ID, workerID))
+			require.ErrorIs(t, p.IncrementMessageSendCount(ctx, messages[0].ID, "worker-1"), courier.ErrLeaseLost)
+
+			actual, err := p.FetchMessage(ctx, messages[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected.SendCount+1, actual.SendCount)
+		})
+
+		t.Run("case=requeueing messages", func(t *testing.T) {
+			retriable := []courier.MessageStatus{courier.MessageStatusAbandoned, courier.MessageStatusSent}
+
+			require.ErrorIs(t, p.RequeueMessage(ctx, messages[0].ID, retriable), courier.ErrMessageStatusChanged)
+
+			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, workerID, courier.MessageStatusAbandoned))
+			require.NoError(t, p.RequeueMessage(ctx, messages[0].ID, retriable))
 
-			require.NoError(t, p.IncrementMessageSendCount(ctx, messages[0].ID))
-			ms, err := p.NextMessages(ctx, 1)
+			actual, err := p.FetchMessage(ctx, messages[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+			assert.Equal(t, 0, actual.SendCount)
+			assert.Empty(t, actual.WorkerID)
+			assert.True(t, actual.LeaseExpiresAt.IsZero())
+
+			require.ErrorIs(t, p.RequeueMessage(ctx, messages[0].ID, retriable), courier.ErrMessageStatusChanged)
+
+			_, err = p.NextMessages(ctx, 1, workerID, time.Hour)
+			require.NoError(t, err)
+			require.NoError(t, p.SetMessageStatus(ctx, messages[0].ID, workerID, courier.MessageStatusAbandoned))
+			_, otherNetwork := newNetwork(t, ctx)
+			require.ErrorIs(t, otherNetwork.RequeueMessage(ctx, messages[0].ID, retriable), courier.ErrMessageStatusChanged)
+		})
+
+		t.Run("case=cancelling messages", func(t *testing.T) {
+			require.ErrorIs(t, p.CancelMessage(ctx, messages[0].ID), courier.ErrMessageStatusChanged)
+
+			require.NoError(t, p.RequeueMessage(ctx, messages[0].ID, []courier.MessageStatus{courier.MessageStatusAbandoned}))
+			_, otherNetwork := newNetwork(t, ctx)
+			require.ErrorIs(t, otherNetwork.CancelMessage(ctx, messages[0].ID), courier.ErrMessageStatusChanged)
+
+			require.NoError(t, p.CancelMessage(ctx, messages[0].ID))
+			actual, err := p.FetchMessage(ctx, messages[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusCancelled, actual.Status)
+
+			require.ErrorIs(t, p.CancelMessage(ctx, messages[0].ID), courier.ErrMessageStatusChanged)
+
+			require.NoError(t, p.RequeueMessage(ctx, messages[0].ID, []courier.MessageStatus{courier.MessageStatusCancelled}))
+			ms, err := p.NextMessages(ctx, 1, workerID, time.Hour)
 			require.NoError(t, err)
 			require.Len(t, ms, 1)
 			assert.Equal(t, messages[0].ID, ms[0].ID)
-			assert.Equal(t, originalSendCount+1, ms[0].SendCount)
+		})
+
+		t.Run("case=requeue messages with expired lease", func(t *testing.T) {
+			require.NoError(t, p.GetConnection(ctx).
+				RawQuery("UPDATE courier_messages SET lease_expires_at = ? WHERE id = ? AND nid = ?", time.Now().UTC().Add(-time.Minute), messages[1].ID, nid).
+				Exec())
+
+			ms, err := p.NextMessages(ctx, 10, "worker-1", time.Hour)
+			require.NoError(t, err)
+			require.Len(t, ms, 1)
+			assert.Equal(t, messages[1].ID, ms[0].ID)
+			assert.EqualValues(t, "worker-1", ms[0].WorkerID)
+
+			actual, err := p.FetchMessage(ctx, messages[1].ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusProcessing, actual.Status)
+			assert.EqualValues(t, "worker-1", actual.WorkerID)
+
+			// The lease was renewed, so the message is not claimed again.
+			_, err = p.NextMessages(ctx, 10, workerID, time.Hour)
+			require.ErrorIs(t, err, courier.ErrQueueEmpty)
 		})
 
 		t.Run("case=list messages", func(t *testing.T) {
@@ -139,6 +256,17 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				// Check that the 'order by created_at desc' works.
 				require.True(t, slices.IsSortedFunc(ms, func(a, b courier.Message) int { return b.CreatedAt.Compare(a.CreatedAt) }))
 			}
+			// List by template type.
+			{
+				filter := courier.ListCourierMessagesParameters{
+					TemplateType: messages[2].TemplateType,
+				}
+				ms, _, err := p.ListMessages(ctx, filter, []keysetpagination.Option{})
+
+				require.NoError(t, err)
+				require.Len(t, ms, 1)
+				assert.Equal(t, messages[2].ID, ms[0].ID)
+			}
 			// Query fewer items than the total, multiple times.
 			{
 				filter := courier.ListCourierMessagesParameters{}
@@ -270,7 +398,7 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				assert.EqualValues(t, expected.ID, actual.ID)
 				assert.EqualValues(t, nid, actual.NID)
 
-				actuals, err := p.NextMessages(ctx, 255)
+				actuals, err := p.NextMessages(ctx, 255, workerID, time.Hour)
 				require.NoError(t, err)
 
 				actual = &actuals[0]
@@ -293,7 +421,7 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				assert.EqualValues(t, id, actual.ID)
 				assert.EqualValues(t, nid, actual.NID)
 
-				actuals, err := p.NextMessages(ctx, 255)
+				actuals, err := p.NextMessages(ctx, 255, workerID, time.Hour)
 				require.NoError(t, err)
 
 				actual = &actuals[0]
@@ -307,14 +435,14 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				_, err := p.LatestQueuedMessage(ctx)
 				require.ErrorIs(t, err, courier.ErrQueueEmpty)
 
-				_, err = p.NextMessages(ctx, 255)
+				_, err = p.NextMessages(ctx, 255, workerID, time.Hour)
 				require.ErrorIs(t, err, courier.ErrQueueEmpty)
 			})
 
 			t.Run("can not update on another network", func(t *testing.T) {
 				_, p := newNetwork(t, ctx)
-				err := p.SetMessageStatus(ctx, id, courier.MessageStatusProcessing)
-				require.ErrorIs(t, err, sqlcon.ErrNoRows)
+				err := p.SetMessageStatus(ctx, id, workerID, courier.MessageStatusProcessing)
+				require.ErrorIs(t, err, courier.ErrLeaseLost)
 			})
 		})
 
@@ -336,7 +464,7 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 		t.Run("case=RecordDispatch", func(t *testing.T) {
 			msgID := messages[0].ID
 
-			err := p.RecordDispatch(ctx, msgID, courier.CourierMessageDispatchStatusFailed, errors.New("testerror"))
+			err := p.RecordDispatch(ctx, msgID, "smtp", "", courier.CourierMessageDispatchStatusFailed, errors.New("testerror"))
 			require.NoError(t, err)
 
 			message, err := p.FetchMessage(ctx, msgID)
@@ -344,6 +472,7 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 
 			require.Len(t, message.Dispatches, 1)
 			assert.Equal(t, "testerror", gjson.GetBytes(message.Dispatches[0].Error, "message").String())
+			assert.Equal(t, "smtp", message.Dispatches[0].Channel.String())
 
 			t.Run("can not get on another network", func(t *testing.T) {
 				_, p := newNetwork(t, ctx)
@@ -352,5 +481,117 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				require.ErrorIs(t, err, sqlcon.ErrNoRows)
 			})
 		})
+
+		t.Run("case=UpdateDispatchDeliveryStatus", func(t *testing.T) {
+			msgID := messages[1].ID
+			providerMessageID := x.NewUUID().String()
+
+			require.NoError(t, p.RecordDispatch(ctx, msgID, "twilio", providerMessageID, courier.CourierMessageDispatchStatusSuccess, nil))
+
+			_, err := p.UpdateDispatchDeliveryStatus(ctx, "vonage", providerMessageID, courier.CourierMessageDeliveryStatusDelivered, nil)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+			dispatch, err := p.UpdateDispatchDeliveryStatus(ctx, "twilio", providerMessageID, courier.CourierMessageDeliveryStatusUndelivered, errors.New("unreachable"))
+			require.NoError(t, err)
+			assert.Equal(t, msgID, dispatch.MessageID)
+
+			message, err := p.FetchMessage(ctx, msgID)
+			require.NoError(t, err)
+			require.Len(t, message.Dispatches, 1)
+			assert.Equal(t, providerMessageID, message.Dispatches[0].ProviderMessageID.String())
+			assert.Equal(t, courier.CourierMessageDeliveryStatusUndelivered, message.Dispatches[0].DeliveryStatus)
+			assert.Equal(t, "unreachable", gjson.GetBytes(message.Dispatches[0].Error, "message").String())
+
+			t.Run("can not update on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				_, err := p.UpdateDispatchDeliveryStatus(ctx, "twilio", providerMessageID, courier.CourierMessageDeliveryStatusDelivered, nil)
+				require.ErrorIs(t, err, sqlcon.ErrNoRows)
+			})
+		})
+
+		t.Run("case=CountRecipientMessages", func(t *testing.T) {
+			recipient := x.NewUUID().String() + "@ory.sh"
+			for _, m := range []courier.Message{
+				{Recipient: recipient, TemplateType: "recovery_code_valid"},
+				{Recipient: recipient, TemplateType: "recovery_code_valid"},
+				{Recipient: " " + strings.ToUpper(recipient), TemplateType: "login_code_valid"},
+				{Recipient: recipient, TemplateType: "recovery_code_valid", Status: courier.MessageStatusSuppressed},
+			} {
+				m.Type = courier.MessageTypeEmail
+				require.NoError(t, p.AddMessage(ctx, &m))
+			}
+
+			count, err := p.CountRecipientMessages(ctx, recipient, "", time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, 3, count)
+
+			count, err = p.CountRecipientMessages(ctx, strings.ToUpper(recipient), "", time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, 3, count)
+
+			count, err = p.CountRecipientMessages(ctx, recipient, "recovery_code_valid", time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, 2, count)
+
+			count, err = p.CountRecipientMessages(ctx, recipient, "", time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, 0, count)
+
+			t.Run("stores the recipient as given", func(t *testing.T) {
+				ms, _, err := p.ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: strings.ToUpper(recipient)}, nil)
+				require.NoError(t, err)
+				require.Len(t, ms, 4)
+				assert.ElementsMatch(t,
+					[]string{recipient, recipient, " " + strings.ToUpper(recipient), recipient},
+					[]string{ms[0].Recipient, ms[1].Recipient, ms[2].Recipient, ms[3].Recipient})
+			})
+
+			t.Run("can not count on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				count, err := p.CountRecipientMessages(ctx, recipient, "", time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+				assert.Equal(t, 0, count)
+			})
+		})
+
+		t.Run("case=suppressions", func(t *testing.T) {
+			recipient := x.NewUUID().String() + "@ory.sh"
+			s := courier.Suppression{Recipient: " " + strings.ToUpper(recipient), Reason: courier.SuppressionReasonBounced}
+			require.NoError(t, p.CreateSuppression(ctx, &s))
+			assert.Equal(t, recipient, s.Recipient)
+
+			err := p.CreateSuppression(ctx, &courier.Suppression{Recipient: recipient, Reason: courier.SuppressionReasonManual})
+			require.ErrorIs(t, err, sqlcon.ErrUniqueViolation)
+
+			actual, err := p.FindSuppressionByRecipient(ctx, strings.ToUpper(recipient))
+			require.NoError(t, err)
+			assert.Equal(t, s.ID, actual.ID)
+			assert.Equal(t, courier.SuppressionReasonBounced, actual.Reason)
+
+			list, _, err := p.ListSuppressions(ctx, courier.ListCourierSuppressionsParameters{Recipient: recipient}, nil)
+			require.NoError(t, err)
+			require.Len(t, list, 1)
+			assert.Equal(t, s.ID, list[0].ID)
+
+			t.Run("can not get on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+
+				_, err := p.FindSuppressionByRecipient(ctx, recipient)
+				require.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+				list, _, err := p.ListSuppressions(ctx, courier.ListCourierSuppressionsParameters{}, nil)
+				require.NoError(t, err)
+				assert.Empty(t, list)
+
+				require.ErrorIs(t, p.DeleteSuppression(ctx, s.ID), sqlcon.ErrNoRows)
+			})
+
+			require.NoError(t, p.DeleteSuppression(ctx, s.ID))
+			_, err = p.FindSuppressionByRecipient(ctx, recipient)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+			require.ErrorIs(t, p.DeleteSuppression(ctx, s.ID), sqlcon.ErrNoRows)
+		})
 	}
 }
@@ -0,0 +1,153 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //#nosec G505 -- Twilio signs requests with HMAC-SHA1
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/urlx"
+)
+
+const twilioDefaultBaseURL = "https://api.twilio.com"
+
+// twilioChannel sends SMS using the Twilio Messaging API or a compatible API.
+type twilioChannel struct {
+	id   string
+	name string
+	conf *config.TwilioConfig
+	d    channelDependencies
+}
+
+var _ ReceiptChannel = new(twilioChannel)
+
+func newTwilioChannel(id, name string, conf *config.TwilioConfig, d channelDependencies) *twilioChannel {
+	return &twilioChannel{id: id, name: name, conf: conf, d: d}
+}
+
+func (c *twilioChannel) ID() string {
+	return c.id
+}
+
+func (c *twilioChannel) Dispatch(ctx context.Context, msg Message) error {
+	_, err := c.DispatchWithProviderMessageID(ctx, msg)
+	return err
+}
+
+func (c *twilioChannel) DispatchWithProviderMessageID(ctx context.Context, msg Message) (_ string, err error) {
+	ctx, span := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.twilioChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	baseURL := c.conf.BaseURL
+	if baseURL == "" {
+		baseURL = twilioDefaultBaseURL
+	}
+	endpoint, err := url.Parse(baseURL)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	endpoint = urlx.AppendPaths(endpoint, "2010-04-01", "Accounts", url.PathEscape(c.conf.AccountSID), "Messages.json")
+
+	form := url.Values{
+		"To":             {msg.Recipient},
+		"From":           {c.conf.From},
+		"Body":           {msg.Body},
+		"StatusCallback": {deliveryReceiptURL(ctx, c.d, c.name).String()},
+	}
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.conf.AccountSID, c.conf.AuthToken)
+
+	status, body, err := doProviderRequest(ctx, c.d, req)
+	if err != nil {
+		return "", err
+	}
+
+	var res struct {
+		SID     string `json:"sid"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil && status < 300 {
+		return "", errors.Wrapf(err, "unable to decode the response of the Twilio API")
+	}
+	if status < 200 || status >= 300 {
+		return "", errors.Errorf("unable to dispatch SMS because the Twilio API replied with status code %d and error %d: %s", status, res.Code, res.Message)
+	}
+	if res.SID == "" {
+		return "", errors.New("unable to dispatch SMS because the Twilio API did not return a message SID")
+	}
+
+	return res.SID, nil
+}
+
+func (c *twilioChannel) ParseDeliveryReceipts(r *http.Request) ([]DeliveryReceipt, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt is malformed.").WithWrap(err))
+	}
+
+	expected := twilioSignature(c.conf.AuthToken, deliveryReceiptURL(r.Context(), c.d, c.name).String(), r.PostForm)
+	if !hmac.Equal([]byte(r.Header.Get("X-Twilio-Signature")), []byte(expected)) {
+		return nil, errors.WithStack(herodot.ErrForbidden.WithReason("The delivery receipt could not be authenticated."))
+	}
+
+	receipt := DeliveryReceipt{ProviderMessageID: r.PostForm.Get("MessageSid")}
+	switch r.PostForm.Get("MessageStatus") {
+	case "delivered":
+		receipt.Status = CourierMessageDeliveryStatusDelivered
+	case "undelivered", "failed":
+		receipt.Status = CourierMessageDeliveryStatusUndelivered
+		if code := r.PostForm.Get("ErrorCode"); code != "" {
+			receipt.Error = "Twilio error code " + code
+		}
+	default:
+		// Intermediate statuses such as "queued" or "sent" are not final.
+		return nil, nil
+	}
+
+	if receipt.ProviderMessageID == "" {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt does not contain a message SID."))
+	}
+	return []DeliveryReceipt{receipt}, nil
+}
+
+// twilioSignature computes the signature Twilio sends in the
+// X-Twilio-Signature header: the URL followed by the sorted POST parameters,
+// signed with HMAC-SHA1 using the auth token.
+func twilioSignature(authToken, u string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(u)
+	for _, k := range keys {
+		for _, v := range params[k] {
+			b.WriteString(k)
+			b.WriteString(v)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	_, _ = mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
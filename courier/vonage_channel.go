@@ -0,0 +1,157 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/urlx"
+)
+
+const vonageDefaultBaseURL = "https://rest.nexmo.com"
+
+// vonageChannel sends SMS using the Vonage SMS API or a compatible API.
+type vonageChannel struct {
+	id   string
+	name string
+	conf *config.VonageConfig
+	d    channelDependencies
+}
+
+var _ ReceiptChannel = new(vonageChannel)
+
+func newVonageChannel(id, name string, conf *config.VonageConfig, d channelDependencies) *vonageChannel {
+	return &vonageChannel{id: id, name: name, conf: conf, d: d}
+}
+
+func (c *vonageChannel) ID() string {
+	return c.id
+}
+
+func (c *vonageChannel) Dispatch(ctx context.Context, msg Message) error {
+	_, err := c.DispatchWithProviderMessageID(ctx, msg)
+	return err
+}
+
+func (c *vonageChannel) DispatchWithProviderMessageID(ctx context.Context, msg Message) (_ string, err error) {
+	ctx, span := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.vonageChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	baseURL := c.conf.BaseURL
+	if baseURL == "" {
+		baseURL = vonageDefaultBaseURL
+	}
+	endpoint, err := url.Parse(baseURL)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	endpoint = urlx.AppendPaths(endpoint, "sms", "json")
+
+	form := url.Values{
+		"api_key":    {c.conf.APIKey},
+		"api_secret": {c.conf.APISecret},
+		"from":       {c.conf.From},
+		// Vonage expects the number in international format without the plus.
+		"to":   {strings.TrimPrefix(msg.Recipient, "+")},
+		"text": {msg.Body},
+	}
+	if c.conf.DeliveryReceiptSecret != "" {
+		callback := deliveryReceiptURL(ctx, c.d, c.name)
+		callback.RawQuery = url.Values{"token": {c.conf.DeliveryReceiptSecret}}.Encode()
+		form.Set("callback", callback.String())
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	status, body, err := doProviderRequest(ctx, c.d, req)
+	if err != nil {
+		return "", err
+	}
+	if status < 200 || status >= 300 {
+		return "", errors.Errorf("unable to dispatch SMS because the Vonage API replied with status code %d", status)
+	}
+
+	var res struct {
+		Messages []struct {
+			MessageID string `json:"message-id"`
+			Status    string `json:"status"`
+			ErrorText string `json:"error-text"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return "", errors.Wrapf(err, "unable to decode the response of the Vonage API")
+	}
+	if len(res.Messages) == 0 {
+		return "", errors.New("unable to dispatch SMS because the Vonage API did not return a message")
+	}
+
+	// Long messages are split into several parts. Receipts are reported for
+	// every part, so the first part identifies the message.
+	for _, m := range res.Messages {
+		if m.Status != "0" {
+			return "", errors.Errorf("unable to dispatch SMS because the Vonage API replied with status %s: %s", m.Status, m.ErrorText)
+		}
+	}
+	return res.Messages[0].MessageID, nil
+}
+
+func (c *vonageChannel) ParseDeliveryReceipts(r *http.Request) ([]DeliveryReceipt, error) {
+	if err := verifyReceiptToken(r, c.conf.DeliveryReceiptSecret); err != nil {
+		return nil, err
+	}
+
+	var dlr struct {
+		MessageID string `json:"messageId"`
+		Status    string `json:"status"`
+		ErrCode   string `json:"err-code"`
+	}
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/json" {
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxProviderResponseSize)).Decode(&dlr); err != nil {
+			return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt is malformed.").WithWrap(err))
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt is malformed.").WithWrap(err))
+		}
+		dlr.MessageID = r.Form.Get("messageId")
+		dlr.Status = r.Form.Get("status")
+		dlr.ErrCode = r.Form.Get("err-code")
+	}
+
+	receipt := DeliveryReceipt{ProviderMessageID: dlr.MessageID}
+	switch dlr.Status {
+	case "delivered":
+		receipt.Status = CourierMessageDeliveryStatusDelivered
+	case "failed", "rejected", "expired":
+		receipt.Status = CourierMessageDeliveryStatusUndelivered
+		receipt.Error = "Vonage delivery status " + dlr.Status
+		if dlr.ErrCode != "" && dlr.ErrCode != "0" {
+			receipt.Error += " with error code " + dlr.ErrCode
+		}
+	default:
+		// Intermediate statuses such as "accepted" or "buffered" are not final.
+		return nil, nil
+	}
+
+	if receipt.ProviderMessageID == "" {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The delivery receipt does not contain a message ID."))
+	}
+	return []DeliveryReceipt{receipt}, nil
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"os"
 	"runtime"
@@ -75,6 +76,7 @@ const (
 	ViperKeyCourierHTTPRequestConfig                         = "courier.http.request_config"
 	ViperKeyCourierTemplatesLoginCodeValidEmail              = "courier.templates.login_code.valid.email"
 	ViperKeyCourierTemplatesRegistrationCodeValidEmail       = "courier.templates.registration_code.valid.email"
+	ViperKeyCourierTemplatesSecurityNotification             = "courier.templates.security_notification"
 	ViperKeyCourierSMTP                                      = "courier.smtp"
 	ViperKeyCourierSMTPFrom                                  = "courier.smtp.from_address"
 	ViperKeyCourierSMTPFromName                              = "courier.smtp.from_name"
@@ -83,7 +85,13 @@ const (
 	ViperKeyCourierMessageRetries                            = "courier.message_retries"
 	ViperKeyCourierWorkerPullCount                           = "courier.worker.pull_count"
 	ViperKeyCourierWorkerPullWait                            = "courier.worker.pull_wait"
+	ViperKeyCourierWorkerID                                  = "courier.worker.id"
+	ViperKeyCourierWorkerLease                               = "courier.worker.lease"
+	ViperKeyCourierLocaleTrait                               = "courier.locale.trait"
+	ViperKeyCourierLocaleMetadataPublic                      = "courier.locale.metadata_public"
 	ViperKeyCourierChannels                                  = "courier.channels"
+	ViperKeyCourierRateLimitPerRecipient                     = "courier.rate_limit.per_recipient"
+	ViperKeyCourierRateLimitPerTemplateType                  = "courier.rate_limit.per_template_type"
 	ViperKeySecretsDefault                                   = "secrets.default"
 	ViperKeySecretsCookie                                    = "secrets.cookie"
 	ViperKeySecretsCipher                                    = "secrets.cipher"
@@ -108,6 +116,7 @@ const (
 	ViperKeyLegacyOIDCRegistrationGroup                      = "feature_flags.legacy_oidc_registration_node_group"
 	ViperKeyUseLegacyRequireVerifiedLoginError               = "feature_flags.legacy_require_verified_login_error"
 	ViperKeySessionRefreshMinTimeLeft                        = "session.earliest_possible_extend"
+	ViperKeySessionImpersonationLifespan                     = "session.impersonation.lifespan"
 	ViperKeyCookieSameSite                                   = "cookies.same_site"
 	ViperKeyCookieDomain                                     = "cookies.domain"
 	ViperKeyCookiePath                                       = "cookies.path"
@@ -117,6 +126,7 @@ const (
 	ViperKeyURLsAllowedReturnToDomains                       = "selfservice.allowed_return_urls"
 	ViperKeySelfServiceRegistrationEnabled                   = "selfservice.flows.registration.enabled"
 	ViperKeySelfServiceRegistrationLoginHints                = "selfservice.flows.registration.login_hints"
+	ViperKeySelfServiceRegistrationAccountLinking            = "selfservice.flows.registration.account_linking"
 	ViperKeySelfServiceRegistrationEnableLegacyOneStep       = "selfservice.flows.registration.enable_legacy_one_step"
 	ViperKeySelfServiceRegistrationFlowStyle                 = "selfservice.flows.registration.style"
 	ViperKeySelfServiceRegistrationUI                        = "selfservice.flows.registration.ui_url"
@@ -126,9 +136,44 @@ const (
 	ViperKeySelfServiceLoginUI                               = "selfservice.flows.login.ui_url"
 	ViperKeySelfServiceLoginFlowStyle                        = "selfservice.flows.login.style"
 	ViperKeySecurityAccountEnumerationMitigate               = "security.account_enumeration.mitigate"
+	ViperKeySecurityTrustedProxies                           = "security.trusted_proxies"
+	ViperKeySecurityLoginLockoutEnabled                      = "security.login_lockout.enabled"
+	ViperKeySecurityLoginLockoutMaxAttempts                  = "security.login_lockout.max_attempts"
+	ViperKeySecurityLoginLockoutMaxAttemptsPerIP             = "security.login_lockout.max_attempts_per_ip"
+	ViperKeySecurityLoginLockoutBaseDuration                 = "security.login_lockout.base_duration"
+	ViperKeySecurityLoginLockoutMaxDuration                  = "security.login_lockout.max_duration"
+	ViperKeySecurityLoginLockoutResetAfter                   = "security.login_lockout.reset_after"
+	ViperKeySecurityAuditLogEnabled                          = "security.audit_log.enabled"
+	ViperKeySecurityAuditLogIgnoredEvents                    = "security.audit_log.ignored_events"
+	ViperKeySecurityAuditLogRetention                        = "security.audit_log.retention"
+	ViperKeySecurityRiskBasedStepUpEnabled                   = "security.risk_based_step_up.enabled"
+	ViperKeySecurityRiskBasedStepUpThreshold                 = "security.risk_based_step_up.threshold"
+	ViperKeySecurityRiskBasedStepUpLookback                  = "security.risk_based_step_up.lookback"
+	ViperKeySecurityRiskBasedStepUpWeightNewIPAddress        = "security.risk_based_step_up.weights.new_ip_address"
+	ViperKeySecurityRiskBasedStepUpWeightNewUserAgent        = "security.risk_based_step_up.weights.new_user_agent"
+	ViperKeySecurityRiskBasedStepUpWeightNewLocation         = "security.risk_based_step_up.weights.new_location"
+	ViperKeySecurityNotificationsPasswordChanged             = "security.notifications.password_changed"
+	ViperKeySecurityNotificationsMFAMethodAdded              = "security.notifications.mfa_method_added"
+	ViperKeySecurityNotificationsMFAMethodRemoved            = "security.notifications.mfa_method_removed"
+	ViperKeySecurityNotificationsOIDCProviderLinked          = "security.notifications.oidc_provider_linked"
+	ViperKeySecurityNotificationsSessionsRevoked             = "security.notifications.sessions_revoked"
+	ViperKeySecurityNotificationsNewDeviceLogin              = "security.notifications.new_device_login"
+	ViperKeySecurityCaptchaEnabled                           = "security.captcha.enabled"
+	ViperKeySecurityCaptchaProvider                          = "security.captcha.provider"
+	ViperKeySecurityCaptchaSiteKey                           = "security.captcha.site_key"
+	ViperKeySecurityCaptchaSecretKey                         = "security.captcha.secret_key"
+	ViperKeySecurityCaptchaVerifyURL                         = "security.captcha.verify_url"
+	ViperKeySecurityCaptchaScriptURL                         = "security.captcha.script_url"
+	ViperKeySecurityCaptchaMinScore                          = "security.captcha.min_score"
+	ViperKeySecurityCaptchaFlows                             = "security.captcha.flows"
+	ViperKeySecurityCaptchaRequiredAfterFailedAttempts       = "security.captcha.required_after_failed_attempts"
 	ViperKeySelfServiceLoginRequestLifespan                  = "selfservice.flows.login.lifespan"
 	ViperKeySelfServiceLoginAfter                            = "selfservice.flows.login.after"
 	ViperKeySelfServiceLoginBeforeHooks                      = "selfservice.flows.login.before.hooks"
+	ViperKeySelfServiceLoginDeviceEnabled                    = "selfservice.flows.login.device.enabled"
+	ViperKeySelfServiceLoginDeviceUI                         = "selfservice.flows.login.device.ui_url"
+	ViperKeySelfServiceLoginDeviceLifespan                   = "selfservice.flows.login.device.lifespan"
+	ViperKeySelfServiceLoginDevicePollingInterval            = "selfservice.flows.login.device.polling_interval"
 	ViperKeySelfServiceErrorUI                               = "selfservice.flows.error.ui_url"
 	ViperKeySelfServiceLogoutBrowserDefaultReturnTo          = "selfservice.flows.logout.after." + DefaultBrowserReturnURL
 	ViperKeySelfServiceSettingsURL                           = "selfservice.flows.settings.ui_url"
@@ -145,6 +190,13 @@ const (
 	ViperKeySelfServiceRecoveryRequestLifespan               = "selfservice.flows.recovery.lifespan"
 	ViperKeySelfServiceRecoveryBrowserDefaultReturnTo        = "selfservice.flows.recovery.after." + DefaultBrowserReturnURL
 	ViperKeySelfServiceRecoveryNotifyUnknownRecipients       = "selfservice.flows.recovery.notify_unknown_recipients"
+	ViperKeySelfServiceDeletionEnabled                       = "selfservice.flows.deletion.enabled"
+	ViperKeySelfServiceDeletionUI                            = "selfservice.flows.deletion.ui_url"
+	ViperKeySelfServiceDeletionRequestLifespan               = "selfservice.flows.deletion.lifespan"
+	ViperKeySelfServiceDeletionRequireConfirmation           = "selfservice.flows.deletion.require_confirmation"
+	ViperKeySelfServiceDeletionBeforeHooks                   = "selfservice.flows.deletion.before.hooks"
+	ViperKeySelfServiceDeletionAfter                         = "selfservice.flows.deletion.after"
+	ViperKeySelfServiceDeletionBrowserDefaultReturnTo        = "selfservice.flows.deletion.after." + DefaultBrowserReturnURL
 	ViperKeySelfServiceVerificationEnabled                   = "selfservice.flows.verification.enabled"
 	ViperKeySelfServiceVerificationUI                        = "selfservice.flows.verification.ui_url"
 	ViperKeySelfServiceVerificationRequestLifespan           = "selfservice.flows.verification.lifespan"
@@ -155,6 +207,11 @@ const (
 	ViperKeySelfServiceVerificationNotifyUnknownRecipients   = "selfservice.flows.verification.notify_unknown_recipients"
 	ViperKeyDefaultIdentitySchemaID                          = "identity.default_schema_id"
 	ViperKeyIdentitySchemas                                  = "identity.schemas"
+	ViperKeyIdentitySCIMEnabled                              = "identity.scim.enabled"
+	ViperKeyIdentitySCIMMapperURL                            = "identity.scim.mapper_url"
+	ViperKeyIdentitySCIMSchemaID                             = "identity.scim.schema_id"
+	ViperKeyIdentitySoftDeleteEnabled                        = "identity.soft_delete.enabled"
+	ViperKeyIdentitySoftDeleteRetention                      = "identity.soft_delete.retention"
 	ViperKeyHasherAlgorithm                                  = "hashers.algorithm"
 	ViperKeyHasherArgon2ConfigMemory                         = "hashers.argon2.memory"
 	ViperKeyHasherArgon2ConfigIterations                     = "hashers.argon2.iterations"
@@ -197,6 +254,12 @@ const (
 	ViperKeyClientHTTPNoPrivateIPRanges                      = "clients.http.disallow_private_ip_ranges"
 	ViperKeyClientHTTPPrivateIPExceptionURLs                 = "clients.http.private_ip_exception_urls"
 	ViperKeyWebhookHeaderAllowlist                           = "clients.web_hook.header_allowlist"
+	ViperKeyWebhookOutboxEnabled                             = "clients.web_hook.outbox.enabled"
+	ViperKeyWebhookOutboxMaxRetries                          = "clients.web_hook.outbox.max_retries"
+	ViperKeyWebhookOutboxRetryBackoff                        = "clients.web_hook.outbox.retry_backoff"
+	ViperKeyWebhookOutboxWorkerPullCount                     = "clients.web_hook.outbox.worker.pull_count"
+	ViperKeyWebhookOutboxWorkerPullWait                      = "clients.web_hook.outbox.worker.pull_wait"
+	ViperKeyWebhookOutboxWorkerLease                         = "clients.web_hook.outbox.worker.lease"
 	ViperKeyPreviewDefaultReadConsistencyLevel               = "preview.default_read_consistency_level"
 	ViperKeyVersion                                          = "version"
 	ViperKeyPasswordMigrationHook                            = "selfservice.methods.password.config.migrate_hook"
@@ -257,6 +320,72 @@ type (
 		MinPasswordLength                uint   `json:"min_password_length"`
 		IdentifierSimilarityCheckEnabled bool   `json:"identifier_similarity_check_enabled"`
 	}
+	LoginLockout struct {
+		Enabled          bool          `json:"enabled"`
+		MaxAttempts      int           `json:"max_attempts"`
+		MaxAttemptsPerIP int           `json:"max_attempts_per_ip"`
+		BaseDuration     time.Duration `json:"base_duration"`
+		MaxDuration      time.Duration `json:"max_duration"`
+		ResetAfter       time.Duration `json:"reset_after"`
+	}
+	RiskBasedStepUp struct {
+		Enabled   bool          `json:"enabled"`
+		Threshold int           `json:"threshold"`
+		Lookback  time.Duration `json:"lookback"`
+		Weights   RiskWeights   `json:"weights"`
+	}
+	RiskWeights struct {
+		NewIPAddress int `json:"new_ip_address"`
+		NewUserAgent int `json:"new_user_agent"`
+		NewLocation  int `json:"new_location"`
+	}
+	WebhookOutbox struct {
+		Enabled      bool          `json:"enabled"`
+		MaxRetries   int           `json:"max_retries"`
+		RetryBackoff time.Duration `json:"retry_backoff"`
+		PullCount    int           `json:"pull_count"`
+		PullWait     time.Duration `json:"pull_wait"`
+		Lease        time.Duration `json:"lease"`
+	}
+	AuditLog struct {
+		Enabled       bool          `json:"enabled"`
+		IgnoredEvents []string      `json:"ignored_events"`
+		Retention     time.Duration `json:"retention"`
+	}
+	SecurityNotifications struct {
+		PasswordChanged    bool `json:"password_changed"`
+		MFAMethodAdded     bool `json:"mfa_method_added"`
+		MFAMethodRemoved   bool `json:"mfa_method_removed"`
+		OIDCProviderLinked bool `json:"oidc_provider_linked"`
+		SessionsRevoked    bool `json:"sessions_revoked"`
+		NewDeviceLogin     bool `json:"new_device_login"`
+	}
+	Captcha struct {
+		Enabled                     bool     `json:"enabled"`
+		Provider                    string   `json:"provider"`
+		SiteKey                     string   `json:"site_key"`
+		SecretKey                   string   `json:"secret_key"`
+		VerifyURL                   *url.URL `json:"verify_url"`
+		ScriptURL                   *url.URL `json:"script_url"`
+		MinScore                    float64  `json:"min_score"`
+		Flows                       []string `json:"flows"`
+		RequiredAfterFailedAttempts int      `json:"required_after_failed_attempts"`
+	}
+	SCIM struct {
+		Enabled   bool   `json:"enabled"`
+		MapperURL string `json:"mapper_url"`
+		SchemaID  string `json:"schema_id"`
+	}
+	SoftDelete struct {
+		Enabled   bool          `json:"enabled"`
+		Retention time.Duration `json:"retention"`
+	}
+	LoginDevice struct {
+		Enabled         bool          `json:"enabled"`
+		UI              *url.URL      `json:"ui_url"`
+		Lifespan        time.Duration `json:"lifespan"`
+		PollingInterval time.Duration `json:"polling_interval"`
+	}
 	Schemas                  []Schema
 	CourierEmailBodyTemplate struct {
 		PlainText string `json:"plaintext"`
@@ -273,10 +402,46 @@ type (
 		PlainText string `json:"plaintext"`
 	}
 	CourierChannel struct {
-		ID            string         `json:"id" koanf:"id"`
-		Type          string         `json:"type" koanf:"type"`
+		ID   string `json:"id" koanf:"id"`
+		Name string `json:"name" koanf:"name"`
+		Type string `json:"type" koanf:"type"`
+		// Priority orders the channels with the same ID. Channels with a lower
+		// priority are attempted first, the others are fallbacks.
+		Priority int `json:"priority" koanf:"priority"`
+		// Weight splits messages between channels with the same ID and priority.
+		Weight        int            `json:"weight" koanf:"weight"`
 		SMTPConfig    *SMTPConfig    `json:"smtp_config" koanf:"smtp_config"`
 		RequestConfig request.Config `json:"request_config" koanf:"request_config"`
+		TwilioConfig  *TwilioConfig  `json:"twilio_config" koanf:"twilio_config"`
+		VonageConfig  *VonageConfig  `json:"vonage_config" koanf:"vonage_config"`
+		SNSConfig     *SNSConfig     `json:"sns_config" koanf:"sns_config"`
+	}
+	TwilioConfig struct {
+		AccountSID string `json:"account_sid" koanf:"account_sid"`
+		AuthToken  string `json:"auth_token" koanf:"auth_token"`
+		From       string `json:"from" koanf:"from"`
+		BaseURL    string `json:"base_url" koanf:"base_url"`
+	}
+	VonageConfig struct {
+		APIKey                string `json:"api_key" koanf:"api_key"`
+		APISecret             string `json:"api_secret" koanf:"api_secret"`
+		From                  string `json:"from" koanf:"from"`
+		BaseURL               string `json:"base_url" koanf:"base_url"`
+		DeliveryReceiptSecret string `json:"delivery_receipt_secret" koanf:"delivery_receipt_secret"`
+	}
+	SNSConfig struct {
+		Region                string `json:"region" koanf:"region"`
+		AccessKeyID           string `json:"access_key_id" koanf:"access_key_id"`
+		SecretAccessKey       string `json:"secret_access_key" koanf:"secret_access_key"`
+		SenderID              string `json:"sender_id" koanf:"sender_id"`
+		BaseURL               string `json:"base_url" koanf:"base_url"`
+		DeliveryReceiptSecret string `json:"delivery_receipt_secret" koanf:"delivery_receipt_secret"`
+	}
+	// CourierRateLimit limits how many messages are queued for a recipient
+	// within the window. A limit with zero max messages is disabled.
+	CourierRateLimit struct {
+		MaxMessages int           `json:"max_messages"`
+		Window      time.Duration `json:"window"`
 	}
 	SMTPConfig struct {
 		ConnectionURI  string            `json:"connection_uri" koanf:"connection_uri"`
@@ -313,6 +478,7 @@ type (
 		CourierTemplatesVerificationCodeValid(ctx context.Context) *CourierEmailTemplate
 		CourierTemplatesLoginCodeValid(ctx context.Context) *CourierEmailTemplate
 		CourierTemplatesRegistrationCodeValid(ctx context.Context) *CourierEmailTemplate
+		CourierTemplatesSecurityNotification(ctx context.Context, event string) *CourierEmailTemplate
 		CourierSMSTemplatesVerificationCodeValid(ctx context.Context) *CourierSMSTemplate
 		CourierSMSTemplatesRecoveryCodeValid(ctx context.Context) *CourierSMSTemplate
 		CourierSMSTemplatesLoginCodeValid(ctx context.Context) *CourierSMSTemplate
@@ -320,7 +486,16 @@ type (
 		CourierMessageRetries(ctx context.Context) int
 		CourierWorkerPullCount(ctx context.Context) int
 		CourierWorkerPullWait(ctx context.Context) time.Duration
+		CourierWorkerID(ctx context.Context) string
+		CourierWorkerLease(ctx context.Context) time.Duration
+		CourierLocaleTrait(ctx context.Context) string
+		CourierLocaleMetadataPublic(ctx context.Context) string
 		CourierChannels(context.Context) ([]*CourierChannel, error)
+		CourierRateLimitPerRecipient(ctx context.Context) *CourierRateLimit
+		CourierRateLimitPerTemplateType(ctx context.Context, templateType string) *CourierRateLimit
+		SelfServiceCodeMethodLifespan(ctx context.Context) time.Duration
+		SelfServiceLinkMethodLifespan(ctx context.Context) time.Duration
+		SelfPublicURL(ctx context.Context) *url.URL
 	}
 )
 
@@ -661,6 +836,10 @@ func (p *Config) SelfServiceFlowRegistrationLoginHints(ctx context.Context) bool
 	return p.GetProvider(ctx).Bool(ViperKeySelfServiceRegistrationLoginHints)
 }
 
+func (p *Config) SelfServiceFlowRegistrationAccountLinking(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeySelfServiceRegistrationAccountLinking)
+}
+
 func (p *Config) SelfServiceFlowRegistrationPasswordMethodProfileGroup(ctx context.Context) string {
 	switch g := p.GetProvider(ctx).String(ViperKeyPasswordRegistrationProfileGroup); g {
 	case "password":
@@ -730,6 +909,24 @@ func (p *Config) SelfServiceFlowRecoveryUse(ctx context.Context) string {
 	return p.GetProvider(ctx).String(ViperKeySelfServiceRecoveryUse)
 }
 
+func (p *Config) SelfServiceFlowDeletionEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).BoolF(ViperKeySelfServiceDeletionEnabled, false)
+}
+
+// SelfServiceFlowDeletionRequireConfirmation returns true when the user has to
+// confirm the deletion of their account explicitly. This is the default.
+func (p *Config) SelfServiceFlowDeletionRequireConfirmation(ctx context.Context) bool {
+	return p.GetProvider(ctx).BoolF(ViperKeySelfServiceDeletionRequireConfirmation, true)
+}
+
+func (p *Config) SelfServiceFlowDeletionBeforeHooks(ctx context.Context) []SelfServiceHook {
+	return p.selfServiceHooks(ctx, ViperKeySelfServiceDeletionBeforeHooks)
+}
+
+func (p *Config) SelfServiceFlowDeletionAfterHooks(ctx context.Context) []SelfServiceHook {
+	return p.selfServiceHooks(ctx, HookStrategyKey(ViperKeySelfServiceDeletionAfter, HookGlobal))
+}
+
 func (p *Config) SelfServiceFlowLoginBeforeHooks(ctx context.Context) []SelfServiceHook {
 	return p.selfServiceHooks(ctx, ViperKeySelfServiceLoginBeforeHooks)
 }
@@ -942,6 +1139,18 @@ func (p *Config) WebhookHeaderAllowlist(ctx context.Context) []string {
 	return p.GetProvider(ctx).Strings(ViperKeyWebhookHeaderAllowlist)
 }
 
+func (p *Config) WebhookOutbox(ctx context.Context) *WebhookOutbox {
+	pp := p.GetProvider(ctx)
+	return &WebhookOutbox{
+		Enabled:      pp.BoolF(ViperKeyWebhookOutboxEnabled, false),
+		MaxRetries:   pp.IntF(ViperKeyWebhookOutboxMaxRetries, 5),
+		RetryBackoff: pp.DurationF(ViperKeyWebhookOutboxRetryBackoff, 30*time.Second),
+		PullCount:    pp.IntF(ViperKeyWebhookOutboxWorkerPullCount, 10),
+		PullWait:     pp.DurationF(ViperKeyWebhookOutboxWorkerPullWait, time.Second),
+		Lease:        pp.DurationF(ViperKeyWebhookOutboxWorkerLease, 5*time.Minute),
+	}
+}
+
 func (p *Config) OAuth2ProviderHeader(ctx context.Context) http.Header {
 	hh := map[string]string{}
 	if err := p.GetProvider(ctx).Unmarshal(ViperKeyOAuth2ProviderHeader, &hh); err != nil {
@@ -997,11 +1206,20 @@ func (p *Config) SelfServiceFlowRecoveryUI(ctx context.Context) *url.URL {
 	return p.ParseAbsoluteOrRelativeURIOrFail(ctx, ViperKeySelfServiceRecoveryUI)
 }
 
+func (p *Config) SelfServiceFlowDeletionUI(ctx context.Context) *url.URL {
+	return p.ParseAbsoluteOrRelativeURIOrFail(ctx, ViperKeySelfServiceDeletionUI)
+}
+
 // SessionLifespan returns time.Hour*24 when the value is not set.
 func (p *Config) SessionLifespan(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).DurationF(ViperKeySessionLifespan, time.Hour*24)
 }
 
+// SessionImpersonationLifespan returns 15 minutes when the value is not set.
+func (p *Config) SessionImpersonationLifespan(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeySessionImpersonationLifespan, time.Minute*15)
+}
+
 func (p *Config) SessionPersistentCookie(ctx context.Context) bool {
 	return p.GetProvider(ctx).Bool(ViperKeySessionPersistentCookie)
 }
@@ -1041,6 +1259,22 @@ func (p *Config) SelfServiceFlowLoginRequestLifespan(ctx context.Context) time.D
 	return p.GetProvider(ctx).DurationF(ViperKeySelfServiceLoginRequestLifespan, time.Hour)
 }
 
+// SelfServiceFlowLoginDevice returns the device authorization configuration.
+// The UI where users enter the user code defaults to the login UI.
+func (p *Config) SelfServiceFlowLoginDevice(ctx context.Context) *LoginDevice {
+	pp := p.GetProvider(ctx)
+	ui := p.SelfServiceFlowLoginUI(ctx)
+	if pp.String(ViperKeySelfServiceLoginDeviceUI) != "" {
+		ui = p.ParseAbsoluteOrRelativeURIOrFail(ctx, ViperKeySelfServiceLoginDeviceUI)
+	}
+	return &LoginDevice{
+		Enabled:         pp.BoolF(ViperKeySelfServiceLoginDeviceEnabled, false),
+		UI:              ui,
+		Lifespan:        pp.DurationF(ViperKeySelfServiceLoginDeviceLifespan, 10*time.Minute),
+		PollingInterval: pp.DurationF(ViperKeySelfServiceLoginDevicePollingInterval, 5*time.Second),
+	}
+}
+
 func (p *Config) SelfServiceFlowSettingsFlowLifespan(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).DurationF(ViperKeySelfServiceSettingsRequestLifespan, time.Hour)
 }
@@ -1181,6 +1415,12 @@ func (p *Config) CourierTemplatesRegistrationCodeValid(ctx context.Context) *Cou
 	return p.CourierEmailTemplatesHelper(ctx, ViperKeyCourierTemplatesRegistrationCodeValidEmail)
 }
 
+// CourierTemplatesSecurityNotification returns the email template of the
+// security notification sent for the event, for example "password_changed".
+func (p *Config) CourierTemplatesSecurityNotification(ctx context.Context, event string) *CourierEmailTemplate {
+	return p.CourierEmailTemplatesHelper(ctx, ViperKeyCourierTemplatesSecurityNotification+"."+event+".email")
+}
+
 func (p *Config) CourierMessageRetries(ctx context.Context) int {
 	return p.GetProvider(ctx).IntF(ViperKeyCourierMessageRetries, 5)
 }
@@ -1193,6 +1433,38 @@ func (p *Config) CourierWorkerPullWait(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).Duration(ViperKeyCourierWorkerPullWait)
 }
 
+func (p *Config) CourierWorkerID(ctx context.Context) string {
+	return p.GetProvider(ctx).String(ViperKeyCourierWorkerID)
+}
+
+func (p *Config) CourierWorkerLease(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyCourierWorkerLease, 5*time.Minute)
+}
+
+func (p *Config) CourierLocaleTrait(ctx context.Context) string {
+	return p.GetProvider(ctx).String(ViperKeyCourierLocaleTrait)
+}
+
+func (p *Config) CourierLocaleMetadataPublic(ctx context.Context) string {
+	return p.GetProvider(ctx).String(ViperKeyCourierLocaleMetadataPublic)
+}
+
+func (p *Config) CourierRateLimitPerRecipient(ctx context.Context) *CourierRateLimit {
+	return p.courierRateLimit(ctx, ViperKeyCourierRateLimitPerRecipient)
+}
+
+func (p *Config) CourierRateLimitPerTemplateType(ctx context.Context, templateType string) *CourierRateLimit {
+	return p.courierRateLimit(ctx, ViperKeyCourierRateLimitPerTemplateType+"."+templateType)
+}
+
+func (p *Config) courierRateLimit(ctx context.Context, key string) *CourierRateLimit {
+	pp := p.GetProvider(ctx)
+	return &CourierRateLimit{
+		MaxMessages: pp.IntF(key+".max_messages", 0),
+		Window:      pp.DurationF(key+".window", time.Hour),
+	}
+}
+
 func (p *Config) CourierSMTPHeaders(ctx context.Context) map[string]string {
 	return p.GetProvider(ctx).StringMap(ViperKeyCourierSMTPHeaders)
 }
@@ -1202,6 +1474,13 @@ func (p *Config) CourierChannels(ctx context.Context) (ccs []*CourierChannel, _
 		return nil, errors.WithStack(err)
 	}
 
+	// Channels with the email ID replace the legacy email configuration.
+	for _, c := range ccs {
+		if c.ID == "email" {
+			return ccs, nil
+		}
+	}
+
 	// load legacy configs
 	channel := CourierChannel{
 		ID:   "email",
@@ -1311,6 +1590,14 @@ func (p *Config) SelfServiceFlowRecoveryRequestLifespan(ctx context.Context) tim
 	return p.GetProvider(ctx).DurationF(ViperKeySelfServiceRecoveryRequestLifespan, time.Hour)
 }
 
+func (p *Config) SelfServiceFlowDeletionReturnTo(ctx context.Context, defaultReturnTo *url.URL) *url.URL {
+	return p.GetProvider(ctx).RequestURIF(ViperKeySelfServiceDeletionBrowserDefaultReturnTo, defaultReturnTo)
+}
+
+func (p *Config) SelfServiceFlowDeletionRequestLifespan(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeySelfServiceDeletionRequestLifespan, time.Hour)
+}
+
 func (p *Config) SelfServiceFlowRecoveryNotifyUnknownRecipients(ctx context.Context) bool {
 	return p.GetProvider(ctx).BoolF(ViperKeySelfServiceRecoveryNotifyUnknownRecipients, false)
 }
@@ -1606,3 +1893,111 @@ func (p *Config) SelfServiceLoginFlowIdentifierFirstEnabled(ctx context.Context)
 func (p *Config) SecurityAccountEnumerationMitigate(ctx context.Context) bool {
 	return p.GetProvider(ctx).Bool(ViperKeySecurityAccountEnumerationMitigate)
 }
+
+// SecurityTrustedProxies returns the networks of the proxies which are trusted
+// to set the X-Forwarded-For header. Single IP addresses are accepted as well,
+// invalid entries are ignored.
+func (p *Config) SecurityTrustedProxies(ctx context.Context) []netip.Prefix {
+	var proxies []netip.Prefix
+	for _, v := range p.GetProvider(ctx).StringsF(ViperKeySecurityTrustedProxies, []string{}) {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(v)
+			if addrErr != nil {
+				p.l.WithError(err).Warnf("Ignoring invalid trusted proxy %q.", v)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies
+}
+
+func (p *Config) SecurityLoginLockout(ctx context.Context) *LoginLockout {
+	pp := p.GetProvider(ctx)
+	return &LoginLockout{
+		Enabled:          pp.BoolF(ViperKeySecurityLoginLockoutEnabled, false),
+		MaxAttempts:      pp.IntF(ViperKeySecurityLoginLockoutMaxAttempts, 5),
+		MaxAttemptsPerIP: pp.IntF(ViperKeySecurityLoginLockoutMaxAttemptsPerIP, 50),
+		BaseDuration:     pp.DurationF(ViperKeySecurityLoginLockoutBaseDuration, time.Minute),
+		MaxDuration:      pp.DurationF(ViperKeySecurityLoginLockoutMaxDuration, time.Hour),
+		ResetAfter:       pp.DurationF(ViperKeySecurityLoginLockoutResetAfter, time.Hour),
+	}
+}
+
+func (p *Config) SecurityRiskBasedStepUp(ctx context.Context) *RiskBasedStepUp {
+	pp := p.GetProvider(ctx)
+	return &RiskBasedStepUp{
+		Enabled:   pp.BoolF(ViperKeySecurityRiskBasedStepUpEnabled, false),
+		Threshold: pp.IntF(ViperKeySecurityRiskBasedStepUpThreshold, 50),
+		Lookback:  pp.DurationF(ViperKeySecurityRiskBasedStepUpLookback, 90*24*time.Hour),
+		Weights: RiskWeights{
+			NewIPAddress: pp.IntF(ViperKeySecurityRiskBasedStepUpWeightNewIPAddress, 20),
+			NewUserAgent: pp.IntF(ViperKeySecurityRiskBasedStepUpWeightNewUserAgent, 30),
+			NewLocation:  pp.IntF(ViperKeySecurityRiskBasedStepUpWeightNewLocation, 50),
+		},
+	}
+}
+
+func (p *Config) SecurityAuditLog(ctx context.Context) *AuditLog {
+	pp := p.GetProvider(ctx)
+	return &AuditLog{
+		Enabled:       pp.BoolF(ViperKeySecurityAuditLogEnabled, false),
+		IgnoredEvents: pp.StringsF(ViperKeySecurityAuditLogIgnoredEvents, []string{}),
+		Retention:     pp.DurationF(ViperKeySecurityAuditLogRetention, 90*24*time.Hour),
+	}
+}
+
+// SecurityNotifications returns which security notifications are sent. All
+// of them are disabled by default.
+func (p *Config) SecurityNotifications(ctx context.Context) *SecurityNotifications {
+	pp := p.GetProvider(ctx)
+	return &SecurityNotifications{
+		PasswordChanged:    pp.BoolF(ViperKeySecurityNotificationsPasswordChanged, false),
+		MFAMethodAdded:     pp.BoolF(ViperKeySecurityNotificationsMFAMethodAdded, false),
+		MFAMethodRemoved:   pp.BoolF(ViperKeySecurityNotificationsMFAMethodRemoved, false),
+		OIDCProviderLinked: pp.BoolF(ViperKeySecurityNotificationsOIDCProviderLinked, false),
+		SessionsRevoked:    pp.BoolF(ViperKeySecurityNotificationsSessionsRevoked, false),
+		NewDeviceLogin:     pp.BoolF(ViperKeySecurityNotificationsNewDeviceLogin, false),
+	}
+}
+
+// SecurityCaptcha returns the captcha configuration. VerifyURL and ScriptURL
+// are nil unless set, in which case the provider's defaults apply.
+func (p *Config) SecurityCaptcha(ctx context.Context) *Captcha {
+	pp := p.GetProvider(ctx)
+	return &Captcha{
+		Enabled:                     pp.BoolF(ViperKeySecurityCaptchaEnabled, false),
+		Provider:                    pp.StringF(ViperKeySecurityCaptchaProvider, "turnstile"),
+		SiteKey:                     pp.String(ViperKeySecurityCaptchaSiteKey),
+		SecretKey:                   pp.String(ViperKeySecurityCaptchaSecretKey),
+		VerifyURL:                   pp.URIF(ViperKeySecurityCaptchaVerifyURL, nil),
+		ScriptURL:                   pp.URIF(ViperKeySecurityCaptchaScriptURL, nil),
+		MinScore:                    pp.Float64F(ViperKeySecurityCaptchaMinScore, 0.5),
+		Flows:                       pp.StringsF(ViperKeySecurityCaptchaFlows, []string{"login", "registration", "recovery", "verification"}),
+		RequiredAfterFailedAttempts: pp.IntF(ViperKeySecurityCaptchaRequiredAfterFailedAttempts, 0),
+	}
+}
+
+// IdentitySCIM returns the SCIM provisioning configuration. The schema ID
+// defaults to the default identity schema.
+func (p *Config) IdentitySCIM(ctx context.Context) *SCIM {
+	pp := p.GetProvider(ctx)
+	return &SCIM{
+		Enabled:   pp.BoolF(ViperKeyIdentitySCIMEnabled, false),
+		MapperURL: pp.String(ViperKeyIdentitySCIMMapperURL),
+		SchemaID:  pp.StringF(ViperKeyIdentitySCIMSchemaID, p.DefaultIdentityTraitsSchemaID(ctx)),
+	}
+}
+
+// IdentitySoftDelete returns the soft deletion configuration. Soft-deleted
+// identities are purged once they were deleted longer than the retention
+// period, unless the retention is zero.
+func (p *Config) IdentitySoftDelete(ctx context.Context) *SoftDelete {
+	pp := p.GetProvider(ctx)
+	return &SoftDelete{
+		Enabled:   pp.BoolF(ViperKeyIdentitySoftDeleteEnabled, false),
+		Retention: pp.DurationF(ViperKeyIdentitySoftDeleteRetention, 30*24*time.Hour),
+	}
+}
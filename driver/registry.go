@@ -9,15 +9,20 @@ import (
 
 	"github.com/gorilla/sessions"
 
+	"github.com/ory/kratos/audit"
 	"github.com/ory/kratos/cipher"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/notification"
 	"github.com/ory/kratos/persistence"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/scim"
+	"github.com/ory/kratos/selfservice/captcha"
 	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow/deletion"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/logout"
 	"github.com/ory/kratos/selfservice/flow/recovery"
@@ -29,6 +34,7 @@ import (
 	"github.com/ory/kratos/selfservice/strategy/link"
 	password2 "github.com/ory/kratos/selfservice/strategy/password"
 	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/webhook"
 	"github.com/ory/kratos/x"
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/nosurf"
@@ -88,6 +94,8 @@ type Registry interface {
 	errorx.HandlerProvider
 	errorx.PersistenceProvider
 
+	captcha.VerificationProvider
+
 	hash.HashProvider
 
 	identity.HandlerProvider
@@ -96,11 +104,22 @@ type Registry interface {
 	identity.PrivilegedPoolProvider
 	identity.ManagementProvider
 	identity.ActiveCredentialsCounterStrategyProvider
+	identity.LoginLockoutManagementProvider
+	identity.LoginLockoutPersistenceProvider
 
 	courier.HandlerProvider
 	courier.PersistenceProvider
 
 	schema.HandlerProvider
+	scim.HandlerProvider
+
+	audit.HandlerProvider
+	audit.RecorderProvider
+	audit.PersistenceProvider
+
+	webhook.HandlerProvider
+	webhook.OutboxProvider
+	webhook.PersistenceProvider
 	schema.IdentitySchemaProvider
 
 	password2.ValidationProvider
@@ -109,13 +128,25 @@ type Registry interface {
 	session.ManagementProvider
 	session.PersistenceProvider
 	session.TokenizerProvider
+	session.RiskEvaluatorProvider
+	session.RevocationNotifierProvider
+	session.DevicePersistenceProvider
+
+	notification.SecurityNotifierProvider
 
 	settings.HandlerProvider
 	settings.ErrorHandlerProvider
 	settings.FlowPersistenceProvider
 	settings.StrategyProvider
 
+	deletion.HandlerProvider
+	deletion.ErrorHandlerProvider
+	deletion.FlowPersistenceProvider
+	deletion.HookExecutorProvider
+	deletion.HooksProvider
+
 	login.FlowPersistenceProvider
+	login.DeviceAuthorizationPersistenceProvider
 	login.ErrorHandlerProvider
 	login.HooksProvider
 	login.HookExecutorProvider
@@ -174,6 +205,7 @@ type options struct {
 	configOptions                 []configx.OptionModifier
 	replaceTracer                 func(*otelx.Tracer) *otelx.Tracer
 	replaceIdentitySchemaProvider func(Registry) schema.IdentitySchemaProvider
+	replaceSessionRiskEvaluator   func(Registry) session.RiskEvaluator
 	inspect                       func(Registry) error
 	extraMigrations               []fs.FS
 	extraGoMigrations             popx.Migrations
@@ -224,6 +256,14 @@ func WithIdentitySchemaProvider(f func(r Registry) schema.IdentitySchemaProvider
 	}
 }
 
+// WithSessionRiskEvaluator replaces the default risk evaluator, which
+// compares the device of a login with the devices the identity used before.
+func WithSessionRiskEvaluator(f func(r Registry) session.RiskEvaluator) RegistryOption {
+	return func(o *options) {
+		o.replaceSessionRiskEvaluator = f
+	}
+}
+
 func ReplaceTracer(f func(*otelx.Tracer) *otelx.Tracer) RegistryOption {
 	return func(o *options) {
 		o.replaceTracer = f
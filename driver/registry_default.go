@@ -20,6 +20,7 @@ import (
 	"github.com/urfave/negroni"
 
 	"github.com/ory/herodot"
+	"github.com/ory/kratos/audit"
 	"github.com/ory/kratos/cipher"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
@@ -27,10 +28,14 @@ import (
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/notification"
 	"github.com/ory/kratos/persistence"
 	"github.com/ory/kratos/persistence/sql"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/scim"
+	"github.com/ory/kratos/selfservice/captcha"
 	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow/deletion"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/logout"
 	"github.com/ory/kratos/selfservice/flow/recovery"
@@ -40,15 +45,18 @@ import (
 	"github.com/ory/kratos/selfservice/hook"
 	"github.com/ory/kratos/selfservice/strategy/code"
 	"github.com/ory/kratos/selfservice/strategy/idfirst"
+	"github.com/ory/kratos/selfservice/strategy/ldap"
 	"github.com/ory/kratos/selfservice/strategy/link"
 	"github.com/ory/kratos/selfservice/strategy/lookup"
 	"github.com/ory/kratos/selfservice/strategy/oidc"
 	"github.com/ory/kratos/selfservice/strategy/passkey"
 	"github.com/ory/kratos/selfservice/strategy/password"
 	"github.com/ory/kratos/selfservice/strategy/profile"
+	"github.com/ory/kratos/selfservice/strategy/saml"
 	"github.com/ory/kratos/selfservice/strategy/totp"
 	"github.com/ory/kratos/selfservice/strategy/webauthn"
 	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/webhook"
 	"github.com/ory/kratos/x"
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/kratos/x/webauthnx"
@@ -99,10 +107,19 @@ type RegistryDefault struct {
 	identityHandler        *identity.Handler
 	identityValidator      *identity.Validator
 	identityManager        *identity.Manager
+	loginLockoutManager    *identity.LoginLockoutManager
 	identitySchemaProvider schema.IdentitySchemaProvider
 
 	courierHandler *courier.Handler
 
+	scimHandler *scim.Handler
+
+	auditHandler  *audit.Handler
+	auditRecorder *audit.Recorder
+
+	webhookHandler *webhook.Handler
+	webhookOutbox  *webhook.Outbox
+
 	continuityManager continuity.Manager
 
 	schemaHandler *schema.Handler
@@ -110,6 +127,9 @@ type RegistryDefault struct {
 	sessionHandler   *session.Handler
 	sessionManager   session.Manager
 	sessionTokenizer *session.Tokenizer
+	sessionRisk      session.RiskEvaluator
+
+	securityNotifier *notification.SecurityNotifier
 
 	passwordHasher    hash.Hasher
 	passwordValidator password.Validator
@@ -119,6 +139,8 @@ type RegistryDefault struct {
 	errorHandler *errorx.Handler
 	errorManager *errorx.Manager
 
+	captchaVerifier *captcha.Verifier
+
 	selfserviceRegistrationExecutor            *registration.HookExecutor
 	selfserviceRegistrationHandler             *registration.Handler
 	seflserviceRegistrationErrorHandler        *registration.ErrorHandler
@@ -146,6 +168,10 @@ type RegistryDefault struct {
 
 	selfserviceLogoutHandler *logout.Handler
 
+	selfserviceDeletionHandler      *deletion.Handler
+	selfserviceDeletionErrorHandler *deletion.ErrorHandler
+	selfserviceDeletionExecutor     *deletion.HookExecutor
+
 	selfserviceStrategies            []any
 	replacementSelfserviceStrategies []NewStrategy
 
@@ -177,7 +203,11 @@ func (m *RegistryDefault) RegisterPublicRoutes(ctx context.Context, router *http
 	m.RegistrationHandler().RegisterPublicRoutes(router)
 	m.LogoutHandler().RegisterPublicRoutes(router)
 	m.SettingsHandler().RegisterPublicRoutes(router)
+	m.DeletionHandler().RegisterPublicRoutes(router)
 	m.IdentityHandler().RegisterPublicRoutes(router)
+	m.SCIMHandler().RegisterPublicRoutes(router)
+	m.AuditHandler().RegisterPublicRoutes(router)
+	m.WebhookHandler().RegisterPublicRoutes(router)
 	m.CourierHandler().RegisterPublicRoutes(router)
 	m.SessionHandler().RegisterPublicRoutes(router)
 	m.SelfServiceErrorHandler().RegisterPublicRoutes(router)
@@ -207,7 +237,11 @@ func (m *RegistryDefault) RegisterAdminRoutes(ctx context.Context, router *httpr
 	m.LogoutHandler().RegisterAdminRoutes(router)
 	m.SchemaHandler().RegisterAdminRoutes(router)
 	m.SettingsHandler().RegisterAdminRoutes(router)
+	m.DeletionHandler().RegisterAdminRoutes(router)
 	m.IdentityHandler().RegisterAdminRoutes(router)
+	m.SCIMHandler().RegisterAdminRoutes(router)
+	m.AuditHandler().RegisterAdminRoutes(router)
+	m.WebhookHandler().RegisterAdminRoutes(router)
 	m.CourierHandler().RegisterAdminRoutes(router)
 	m.SelfServiceErrorHandler().RegisterAdminRoutes(router)
 
@@ -326,6 +360,8 @@ func (m *RegistryDefault) selfServiceStrategies() []any {
 			m.selfserviceStrategies = []any{
 				profile.NewStrategy(m), // <- should remain first
 				password.NewStrategy(m),
+				ldap.NewStrategy(m),
+				saml.NewStrategy(m), // <- must come before oidc
 				oidc.NewStrategy(m),
 				code.NewStrategy(m),
 				link.NewStrategy(m),
@@ -460,6 +496,49 @@ func (m *RegistryDefault) IdentityHandler() *identity.Handler {
 	return m.identityHandler
 }
 
+func (m *RegistryDefault) SCIMHandler() *scim.Handler {
+	if m.scimHandler == nil {
+		m.scimHandler = scim.NewHandler(m)
+	}
+	return m.scimHandler
+}
+
+func (m *RegistryDefault) AuditHandler() *audit.Handler {
+	if m.auditHandler == nil {
+		m.auditHandler = audit.NewHandler(m)
+	}
+	return m.auditHandler
+}
+
+func (m *RegistryDefault) AuditRecorder() *audit.Recorder {
+	if m.auditRecorder == nil {
+		m.auditRecorder = audit.NewRecorder(m)
+	}
+	return m.auditRecorder
+}
+
+func (m *RegistryDefault) AuditPersister() audit.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) WebhookHandler() *webhook.Handler {
+	if m.webhookHandler == nil {
+		m.webhookHandler = webhook.NewHandler(m)
+	}
+	return m.webhookHandler
+}
+
+func (m *RegistryDefault) WebhookOutbox() *webhook.Outbox {
+	if m.webhookOutbox == nil {
+		m.webhookOutbox = webhook.NewOutbox(m)
+	}
+	return m.webhookOutbox
+}
+
+func (m *RegistryDefault) WebhookPersister() webhook.Persister {
+	return m.Persister()
+}
+
 func (m *RegistryDefault) CourierHandler() *courier.Handler {
 	if m.courierHandler == nil {
 		m.courierHandler = courier.NewHandler(m)
@@ -601,6 +680,13 @@ func (m *RegistryDefault) SelfServiceErrorManager() *errorx.Manager {
 	return m.errorManager
 }
 
+func (m *RegistryDefault) CaptchaVerifier() *captcha.Verifier {
+	if m.captchaVerifier == nil {
+		m.captchaVerifier = captcha.NewVerifier(m)
+	}
+	return m.captchaVerifier
+}
+
 func (m *RegistryDefault) Init(ctx context.Context, ctxer contextx.Contextualizer, opts ...RegistryOption) error {
 	if m.persister != nil {
 		// The DSN connection can not be hot-reloaded!
@@ -630,6 +716,10 @@ func (m *RegistryDefault) Init(ctx context.Context, ctxer contextx.Contextualize
 		m.identitySchemaProvider = o.replaceIdentitySchemaProvider(m)
 	}
 
+	if o.replaceSessionRiskEvaluator != nil {
+		m.sessionRisk = o.replaceSessionRiskEvaluator(m)
+	}
+
 	bc := backoff.NewExponentialBackOff()
 	bc.MaxElapsedTime = time.Minute * 5
 	bc.Reset()
@@ -764,10 +854,18 @@ func (m *RegistryDefault) LoginFlowPersister() login.FlowPersister {
 	return m.persister
 }
 
+func (m *RegistryDefault) LoginDeviceAuthorizationPersister() login.DeviceAuthorizationPersister {
+	return m.persister
+}
+
 func (m *RegistryDefault) SettingsFlowPersister() settings.FlowPersister {
 	return m.persister
 }
 
+func (m *RegistryDefault) DeletionFlowPersister() deletion.FlowPersister {
+	return m.persister
+}
+
 func (m *RegistryDefault) SelfServiceErrorPersister() errorx.Persister {
 	return m.persister
 }
@@ -776,6 +874,10 @@ func (m *RegistryDefault) SessionPersister() session.Persister {
 	return m.persister
 }
 
+func (m *RegistryDefault) SessionDevicePersister() session.DevicePersister {
+	return m.persister
+}
+
 func (m *RegistryDefault) CourierPersister() courier.Persister {
 	return m.persister
 }
@@ -838,6 +940,17 @@ func (m *RegistryDefault) IdentityManager() *identity.Manager {
 	return m.identityManager
 }
 
+func (m *RegistryDefault) LoginLockoutManager() *identity.LoginLockoutManager {
+	if m.loginLockoutManager == nil {
+		m.loginLockoutManager = identity.NewLoginLockoutManager(m)
+	}
+	return m.loginLockoutManager
+}
+
+func (m *RegistryDefault) LoginLockoutPersister() identity.LoginLockoutPersister {
+	return m.Persister()
+}
+
 func (m *RegistryDefault) HTTPClient(_ context.Context, opts ...httpx.ResilientOptions) *retryablehttp.Client {
 	opts = append([]httpx.ResilientOptions{
 		httpx.ResilientClientWithLogger(m.Logger()),
@@ -894,6 +1007,24 @@ func (m *RegistryDefault) SessionTokenizer() *session.Tokenizer {
 	return m.sessionTokenizer
 }
 
+func (m *RegistryDefault) SessionRiskEvaluator() session.RiskEvaluator {
+	if m.sessionRisk == nil {
+		m.sessionRisk = session.NewDeviceHistoryRiskEvaluator(m)
+	}
+	return m.sessionRisk
+}
+
+func (m *RegistryDefault) SecurityNotifier() *notification.SecurityNotifier {
+	if m.securityNotifier == nil {
+		m.securityNotifier = notification.NewSecurityNotifier(m)
+	}
+	return m.securityNotifier
+}
+
+func (m *RegistryDefault) SessionRevocationNotifier() session.RevocationNotifier {
+	return m.SecurityNotifier()
+}
+
 func (m *RegistryDefault) ExtraHandlers() []x.Handler {
 	if m.extraHandlers == nil {
 		for _, newHandler := range m.extraHandlerFactories {
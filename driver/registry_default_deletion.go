@@ -0,0 +1,40 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/selfservice/flow/deletion"
+)
+
+func (m *RegistryDefault) DeletionHandler() *deletion.Handler {
+	if m.selfserviceDeletionHandler == nil {
+		m.selfserviceDeletionHandler = deletion.NewHandler(m)
+	}
+	return m.selfserviceDeletionHandler
+}
+
+func (m *RegistryDefault) DeletionFlowErrorHandler() *deletion.ErrorHandler {
+	if m.selfserviceDeletionErrorHandler == nil {
+		m.selfserviceDeletionErrorHandler = deletion.NewErrorHandler(m)
+	}
+	return m.selfserviceDeletionErrorHandler
+}
+
+func (m *RegistryDefault) DeletionExecutor() *deletion.HookExecutor {
+	if m.selfserviceDeletionExecutor == nil {
+		m.selfserviceDeletionExecutor = deletion.NewHookExecutor(m)
+	}
+	return m.selfserviceDeletionExecutor
+}
+
+func (m *RegistryDefault) PreDeletionHooks(ctx context.Context) ([]deletion.PreHookExecutor, error) {
+	return getHooks[deletion.PreHookExecutor](m, "", m.Config().SelfServiceFlowDeletionBeforeHooks(ctx))
+}
+
+func (m *RegistryDefault) PostDeletionHooks(ctx context.Context) ([]deletion.PostHookExecutor, error) {
+	return getHooks[deletion.PostHookExecutor](m, config.HookGlobal, m.Config().SelfServiceFlowDeletionAfterHooks(ctx))
+}
@@ -94,6 +94,7 @@ const (
 	CredentialsTypePasskey  CredentialsType = "passkey"
 	CredentialsTypeProfile  CredentialsType = "profile"
 	CredentialsTypeSAML     CredentialsType = "saml"
+	CredentialsTypeLDAP     CredentialsType = "ldap"
 )
 
 func (c CredentialsType) String() string {
@@ -104,8 +105,12 @@ func (c CredentialsType) ToUiNodeGroup() node.UiNodeGroup {
 	switch c {
 	case CredentialsTypePassword:
 		return node.PasswordGroup
-	case CredentialsTypeOIDC, CredentialsTypeSAML:
+	case CredentialsTypeOIDC:
 		return node.OpenIDConnectGroup
+	case CredentialsTypeSAML:
+		return node.SAMLGroup
+	case CredentialsTypeLDAP:
+		return node.LDAPGroup
 	case CredentialsTypeTOTP:
 		return node.TOTPGroup
 	case CredentialsTypeWebAuthn:
@@ -124,7 +129,8 @@ func (c CredentialsType) ToUiNodeGroup() node.UiNodeGroup {
 var AllCredentialTypes = []CredentialsType{
 	CredentialsTypePassword,
 	CredentialsTypeOIDC,
-	// CredentialsTypeSAML, placeholder for the OEL version
+	CredentialsTypeSAML,
+	CredentialsTypeLDAP,
 	CredentialsTypeTOTP,
 	CredentialsTypeLookup,
 	CredentialsTypeWebAuthn,
@@ -137,6 +143,11 @@ const (
 	// It is not used within the credentials object itself.
 	CredentialsTypeRecoveryLink CredentialsType = "link_recovery"
 	CredentialsTypeRecoveryCode CredentialsType = "code_recovery"
+
+	// CredentialsTypeImpersonation is a special credential type used in the authentication methods of
+	// sessions issued by an administrator to impersonate an identity. It is not used within the
+	// credentials object itself.
+	CredentialsTypeImpersonation CredentialsType = "impersonation"
 )
 
 // ParseCredentialsType parses a string into a CredentialsType or returns false as the second argument.
@@ -145,6 +156,7 @@ func ParseCredentialsType(in string) (CredentialsType, bool) {
 	case CredentialsTypePassword,
 		CredentialsTypeOIDC,
 		CredentialsTypeSAML,
+		CredentialsTypeLDAP,
 		CredentialsTypeTOTP,
 		CredentialsTypeLookup,
 		CredentialsTypeWebAuthn,
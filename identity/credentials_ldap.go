@@ -0,0 +1,20 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+// CredentialsLDAP contains the configuration for credentials of the type ldap.
+//
+// The credentials' identifier is the value of the configured unique ID
+// attribute of the user's directory entry. The password is never stored, it is
+// verified by binding against the directory on every login.
+//
+// swagger:model identityCredentialsLDAP
+type CredentialsLDAP struct {
+	// DN is the distinguished name of the user's directory entry at the time of
+	// the last login.
+	DN string `json:"dn"`
+
+	// Username is the identifier the user entered at the last login.
+	Username string `json:"username"`
+}
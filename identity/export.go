@@ -0,0 +1,150 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+// exportIdentitiesPageSize is the number of identities the export loads from
+// the database at once.
+const exportIdentitiesPageSize = 250
+
+// ExportIdentity returns the identity in the format of the create identity
+// API, so that it can be imported again into another Ory Kratos instance.
+//
+// The identity must be loaded with its credentials. Passwords are exported as
+// hashes, and the initial OpenID Connect tokens are exported as they are
+// stored, encrypted with the cipher secret. Code credentials are not exported,
+// because they are derived from the traits again when the export is imported.
+// Exporting an identity with any other credentials fails, so that no
+// credentials are lost silently.
+func ExportIdentity(i *Identity) (*CreateIdentityBody, error) {
+	export := &CreateIdentityBody{
+		ID:                  i.ID,
+		SchemaID:            i.SchemaID,
+		Traits:              json.RawMessage(i.Traits),
+		VerifiableAddresses: i.VerifiableAddresses,
+		RecoveryAddresses:   i.RecoveryAddresses,
+		MetadataPublic:      json.RawMessage(i.MetadataPublic),
+		MetadataAdmin:       json.RawMessage(i.MetadataAdmin),
+		State:               i.State,
+		OrganizationID:      i.OrganizationID,
+		ExternalID:          string(i.ExternalID),
+	}
+
+	var creds IdentityWithCredentials
+	var hasCredentials bool
+	for ct, c := range i.Credentials {
+		switch ct {
+		case CredentialsTypePassword:
+			var config CredentialsPassword
+			if err := json.Unmarshal(c.Config, &config); err != nil {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the password credentials of identity %s.", i.ID).WithWrap(err))
+			}
+			creds.Password = &AdminIdentityImportCredentialsPassword{Config: AdminIdentityImportCredentialsPasswordConfig{
+				HashedPassword:           config.HashedPassword,
+				UsePasswordMigrationHook: config.UsePasswordMigrationHook,
+			}}
+		case CredentialsTypeOIDC:
+			providers, err := exportOIDCProviders(i.ID, ct, c)
+			if err != nil {
+				return nil, err
+			}
+			creds.OIDC = &AdminIdentityImportCredentialsOIDC{Config: AdminIdentityImportCredentialsOIDCConfig{Providers: providers}}
+		case CredentialsTypeSAML:
+			providers, err := exportOIDCProviders(i.ID, ct, c)
+			if err != nil {
+				return nil, err
+			}
+			saml := &AdminIdentityImportCredentialsSAML{}
+			for _, p := range providers {
+				saml.Config.Providers = append(saml.Config.Providers, AdminCreateIdentityImportCredentialsSAMLProvider{
+					Subject:      p.Subject,
+					Provider:     p.Provider,
+					Organization: p.Organization,
+				})
+			}
+			creds.SAML = saml
+		case CredentialsTypeTOTP:
+			var config CredentialsTOTPConfig
+			if err := json.Unmarshal(c.Config, &config); err != nil {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the TOTP credentials of identity %s.", i.ID).WithWrap(err))
+			}
+			creds.TOTP = &AdminIdentityImportCredentialsTOTP{Config: AdminIdentityImportCredentialsTOTPConfig{TOTPURL: config.TOTPURL}}
+		case CredentialsTypeWebAuthn, CredentialsTypePasskey:
+			var config CredentialsWebAuthnConfig
+			if err := json.Unmarshal(c.Config, &config); err != nil {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the %s credentials of identity %s.", ct, i.ID).WithWrap(err))
+			}
+			if ct == CredentialsTypeWebAuthn {
+				creds.WebAuthn = &AdminIdentityImportCredentialsWebAuthn{Config: config}
+			} else {
+				creds.Passkey = &AdminIdentityImportCredentialsWebAuthn{Config: config}
+			}
+		case CredentialsTypeLookup:
+			var config CredentialsLookupConfig
+			if err := json.Unmarshal(c.Config, &config); err != nil {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the lookup secret credentials of identity %s.", i.ID).WithWrap(err))
+			}
+			creds.LookupSecret = &AdminIdentityImportCredentialsLookupSecret{Config: config}
+		case CredentialsTypeLDAP:
+			var config CredentialsLDAP
+			if err := json.Unmarshal(c.Config, &config); err != nil {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the LDAP credentials of identity %s.", i.ID).WithWrap(err))
+			} else if len(c.Identifiers) != 1 {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The LDAP credentials of identity %s must have exactly one identifier.", i.ID))
+			}
+			creds.LDAP = &AdminIdentityImportCredentialsLDAP{Config: AdminIdentityImportCredentialsLDAPConfig{
+				ID:       c.Identifiers[0],
+				DN:       config.DN,
+				Username: config.Username,
+			}}
+		case CredentialsTypeCodeAuth:
+			continue
+		default:
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to export the %s credentials of identity %s.", ct, i.ID))
+		}
+		hasCredentials = true
+	}
+
+	if hasCredentials {
+		export.Credentials = &creds
+	}
+
+	return export, nil
+}
+
+func exportOIDCProviders(id uuid.UUID, ct CredentialsType, c Credentials) ([]AdminCreateIdentityImportCredentialsOIDCProvider, error) {
+	var config CredentialsOIDC
+	if err := json.Unmarshal(c.Config, &config); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the %s credentials of identity %s.", ct, id).WithWrap(err))
+	}
+
+	providers := make([]AdminCreateIdentityImportCredentialsOIDCProvider, 0, len(config.Providers))
+	for _, p := range config.Providers {
+		provider := AdminCreateIdentityImportCredentialsOIDCProvider{
+			Subject:             p.Subject,
+			Provider:            p.Provider,
+			UseAutoLink:         p.UseAutoLink,
+			InitialIDToken:      p.InitialIDToken,
+			InitialAccessToken:  p.InitialAccessToken,
+			InitialRefreshToken: p.InitialRefreshToken,
+		}
+		if p.Organization != "" {
+			org, err := uuid.FromString(p.Organization)
+			if err != nil {
+				return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to decode the %s credentials of identity %s.", ct, id).WithWrap(err))
+			}
+			provider.Organization = uuid.NullUUID{UUID: org, Valid: true}
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
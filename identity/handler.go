@@ -46,6 +46,11 @@ const (
 	RouteCollection     = "/identities"
 	RouteItem           = RouteCollection + "/{id}"
 	RouteCredentialItem = RouteItem + "/credentials/{type}"
+	RouteLoginLockouts  = RouteItem + "/lockouts"
+	RouteUndelete       = RouteItem + "/undelete"
+	RouteExport         = RouteCollection + "/export"
+	RouteVersions       = RouteItem + "/versions"
+	RouteVersionItem    = RouteVersions + "/{version}"
 
 	BatchPatchIdentitiesLimit             = 1000
 	BatchPatchIdentitiesWithPasswordLimit = 200
@@ -57,10 +62,12 @@ type (
 		PrivilegedPoolProvider
 		ManagementProvider
 		x.WriterProvider
+		x.LoggingProvider
 		config.Provider
 		nosurfx.CSRFProvider
 		cipher.Provider
 		hash.HashProvider
+		LoginLockoutPersistenceProvider
 	}
 	HandlerProvider interface {
 		IdentityHandler() *Handler
@@ -83,35 +90,59 @@ func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
 		RouteCollection,
 		RouteCollection+"/*",
 		RouteCollection+"/*/credentials/*",
+		RouteCollection+"/*/lockouts",
+		RouteCollection+"/*/versions/*/restore",
+		RouteCollection+"/*/undelete",
 		httprouterx.AdminPrefix+RouteCollection,
 		httprouterx.AdminPrefix+RouteCollection+"/*",
 		httprouterx.AdminPrefix+RouteCollection+"/*/credentials/*",
+		httprouterx.AdminPrefix+RouteCollection+"/*/lockouts",
+		httprouterx.AdminPrefix+RouteCollection+"/*/versions/*/restore",
+		httprouterx.AdminPrefix+RouteCollection+"/*/undelete",
 	)
 
 	public.GET(RouteCollection, redir.RedirectToAdminRoute(h.r))
+	public.GET(RouteExport, redir.RedirectToAdminRoute(h.r))
 	public.GET(RouteCollection+"/by/external/{externalID}", redir.RedirectToAdminRoute(h.r))
 	public.GET(RouteItem, redir.RedirectToAdminRoute(h.r))
 	public.DELETE(RouteItem, redir.RedirectToAdminRoute(h.r))
+	public.POST(RouteUndelete, redir.RedirectToAdminRoute(h.r))
 	public.POST(RouteCollection, redir.RedirectToAdminRoute(h.r))
 	public.PUT(RouteItem, redir.RedirectToAdminRoute(h.r))
 	public.PATCH(RouteItem, redir.RedirectToAdminRoute(h.r))
 	public.DELETE(RouteCredentialItem, redir.RedirectToAdminRoute(h.r))
+	public.GET(RouteLoginLockouts, redir.RedirectToAdminRoute(h.r))
+	public.DELETE(RouteLoginLockouts, redir.RedirectToAdminRoute(h.r))
+	public.GET(RouteVersions, redir.RedirectToAdminRoute(h.r))
+	public.GET(RouteVersionItem, redir.RedirectToAdminRoute(h.r))
+	public.GET(RouteVersionItem+"/diff", redir.RedirectToAdminRoute(h.r))
+	public.POST(RouteVersionItem+"/restore", redir.RedirectToAdminRoute(h.r))
 
 	public.GET(httprouterx.AdminPrefix+RouteCollection, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+RouteExport, redir.RedirectToAdminRoute(h.r))
 	public.GET(httprouterx.AdminPrefix+RouteCollection+"/by/external/{externalID}", redir.RedirectToAdminRoute(h.r))
 	public.GET(httprouterx.AdminPrefix+RouteItem, redir.RedirectToAdminRoute(h.r))
 	public.DELETE(httprouterx.AdminPrefix+RouteItem, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+RouteUndelete, redir.RedirectToAdminRoute(h.r))
 	public.POST(httprouterx.AdminPrefix+RouteCollection, redir.RedirectToAdminRoute(h.r))
 	public.PUT(httprouterx.AdminPrefix+RouteItem, redir.RedirectToAdminRoute(h.r))
 	public.PATCH(httprouterx.AdminPrefix+RouteItem, redir.RedirectToAdminRoute(h.r))
 	public.DELETE(httprouterx.AdminPrefix+RouteCredentialItem, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+RouteLoginLockouts, redir.RedirectToAdminRoute(h.r))
+	public.DELETE(httprouterx.AdminPrefix+RouteLoginLockouts, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+RouteVersions, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+RouteVersionItem, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+RouteVersionItem+"/diff", redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+RouteVersionItem+"/restore", redir.RedirectToAdminRoute(h.r))
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.GET(RouteCollection, h.list)
+	admin.GET(RouteExport, h.export)
 	admin.GET(RouteItem, h.get)
 	admin.GET(RouteCollection+"/by/external/{externalID}", h.getByExternalID)
 	admin.DELETE(RouteItem, h.delete)
+	admin.POST(RouteUndelete, h.undelete)
 	admin.PATCH(RouteItem, h.patch)
 
 	admin.POST(RouteCollection, h.create)
@@ -119,6 +150,14 @@ func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.PUT(RouteItem, h.update)
 
 	admin.DELETE(RouteCredentialItem, h.deleteIdentityCredentials)
+
+	admin.GET(RouteLoginLockouts, h.listIdentityLoginLockouts)
+	admin.DELETE(RouteLoginLockouts, h.deleteIdentityLoginLockouts)
+
+	admin.GET(RouteVersions, h.listIdentityVersions)
+	admin.GET(RouteVersionItem, h.getIdentityVersion)
+	admin.GET(RouteVersionItem+"/diff", h.diffIdentityVersions)
+	admin.POST(RouteVersionItem+"/restore", h.restoreIdentityVersion)
 }
 
 // Paginated Identity List Response
@@ -185,6 +224,26 @@ type _ struct {
 	// in: query
 	OrganizationID string `json:"organization_id"`
 
+	// Filter identities by expressions of the form `<field><operator><value>`.
+	//
+	// The field is one of `state`, `schema_id`, `created_at`, `updated_at`, or a
+	// path within `traits`, `metadata_public`, or `metadata_admin`, for example
+	// `traits.email` or `metadata_public.address.country`. The operator is one of
+	// `=` and `!=`, or, for `created_at` and `updated_at`, one of `<`, `<=`, `>`,
+	// and `>=` with an RFC 3339 timestamp. Values within `traits` and metadata
+	// are compared by their text representation. Identities without a value at
+	// the path do not match.
+	//
+	// Unlike other filters, filter expressions can be combined with each other
+	// and with other filters. An identity must match all of them.
+	//
+	// Soft-deleted identities are only listed when filtering by `state`, for
+	// example `state=deleted`.
+	//
+	// required: false
+	// in: query
+	Filter []string `json:"filter"`
+
 	crdbx.ConsistencyRequestParameters
 }
 
@@ -228,6 +287,11 @@ func parseListIdentitiesParameters(r *http.Request) (params ListIdentityParamete
 		params.CredentialsIdentifierSimilar = identifier
 	}
 
+	params.Filters, err = parseListIdentityFilters(query)
+	if err != nil {
+		return params, err
+	}
+
 	for _, v := range query["include_credential"] {
 		params.Expand = ExpandEverything
 		tc, ok := ParseCredentialsType(v)
@@ -282,7 +346,7 @@ func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
 
 	if params.PagePagination != nil {
 		total := int64(len(is))
-		if params.CredentialsIdentifier == "" {
+		if params.CredentialsIdentifier == "" && len(params.Filters) == 0 {
 			total, err = h.r.IdentityPool().CountIdentities(r.Context())
 			if err != nil {
 				h.r.Writer().WriteError(w, r, err)
@@ -475,6 +539,16 @@ type createIdentity struct {
 //
 // swagger:model createIdentityBody
 type CreateIdentityBody struct {
+	// ID is the ID of the identity to create. If not set, a new ID is generated.
+	//
+	// The ID can only be set when importing identities with the
+	// `batchPatchIdentities` endpoint, and must be a version 4 UUID. Keep the ID
+	// when importing an export of another Ory Kratos instance, as TOTP and
+	// WebAuthn credentials are bound to it.
+	//
+	// required: false
+	ID uuid.UUID `json:"id,omitzero"`
+
 	// SchemaID is the ID of the JSON Schema to be used for validating the identity's traits.
 	//
 	// required: true
@@ -543,6 +617,21 @@ type IdentityWithCredentials struct {
 
 	// OIDC if set will import an OIDC credential.
 	SAML *AdminIdentityImportCredentialsSAML `json:"saml"`
+
+	// TOTP if set will import a TOTP credential.
+	TOTP *AdminIdentityImportCredentialsTOTP `json:"totp,omitempty"`
+
+	// WebAuthn if set will import WebAuthn credentials.
+	WebAuthn *AdminIdentityImportCredentialsWebAuthn `json:"webauthn,omitempty"`
+
+	// Passkey if set will import passkey credentials.
+	Passkey *AdminIdentityImportCredentialsWebAuthn `json:"passkey,omitempty"`
+
+	// LookupSecret if set will import lookup secret credentials.
+	LookupSecret *AdminIdentityImportCredentialsLookupSecret `json:"lookup_secret,omitempty"`
+
+	// LDAP if set will import an LDAP credential.
+	LDAP *AdminIdentityImportCredentialsLDAP `json:"ldap,omitempty"`
 }
 
 // Create Identity and Import Password Credentials
@@ -602,6 +691,88 @@ type AdminCreateIdentityImportCredentialsOIDCProvider struct {
 
 	// The organization to assign for the provider.
 	Organization uuid.NullUUID `json:"organization,omitempty"`
+
+	// The initial ID token of the provider, encrypted with the cipher secret of
+	// Ory Kratos. Only set this when importing an export of an Ory Kratos
+	// instance using the same cipher secret.
+	//
+	// required: false
+	InitialIDToken string `json:"initial_id_token,omitempty"`
+
+	// The initial access token of the provider, encrypted with the cipher secret
+	// of Ory Kratos.
+	//
+	// required: false
+	InitialAccessToken string `json:"initial_access_token,omitempty"`
+
+	// The initial refresh token of the provider, encrypted with the cipher
+	// secret of Ory Kratos.
+	//
+	// required: false
+	InitialRefreshToken string `json:"initial_refresh_token,omitempty"`
+}
+
+// Create Identity and Import TOTP Credentials
+//
+// swagger:model identityWithCredentialsTotp
+type AdminIdentityImportCredentialsTOTP struct {
+	// Configuration options for the import.
+	Config AdminIdentityImportCredentialsTOTPConfig `json:"config"`
+}
+
+// Create Identity and Import TOTP Credentials Configuration
+//
+// swagger:model identityWithCredentialsTotpConfig
+type AdminIdentityImportCredentialsTOTPConfig struct {
+	// The TOTP URL containing the secret of the authenticator app.
+	//
+	// For more details see: https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+	//
+	// required: true
+	TOTPURL string `json:"totp_url"`
+}
+
+// Create Identity and Import WebAuthn or Passkey Credentials
+//
+// WebAuthn and passkey credentials are bound to the ID of the identity, which
+// must therefore be kept when importing them.
+//
+// swagger:model identityWithCredentialsWebAuthn
+type AdminIdentityImportCredentialsWebAuthn struct {
+	// Configuration options for the import.
+	Config CredentialsWebAuthnConfig `json:"config"`
+}
+
+// Create Identity and Import Lookup Secret Credentials
+//
+// swagger:model identityWithCredentialsLookupSecret
+type AdminIdentityImportCredentialsLookupSecret struct {
+	// Configuration options for the import.
+	Config CredentialsLookupConfig `json:"config"`
+}
+
+// Create Identity and Import LDAP Credentials
+//
+// swagger:model identityWithCredentialsLdap
+type AdminIdentityImportCredentialsLDAP struct {
+	// Configuration options for the import.
+	Config AdminIdentityImportCredentialsLDAPConfig `json:"config"`
+}
+
+// Create Identity and Import LDAP Credentials Configuration
+//
+// swagger:model identityWithCredentialsLdapConfig
+type AdminIdentityImportCredentialsLDAPConfig struct {
+	// The value of the unique ID attribute of the user's directory entry.
+	//
+	// required: true
+	ID string `json:"id"`
+
+	// The distinguished name of the user's directory entry.
+	DN string `json:"dn,omitempty"`
+
+	// The identifier the user signed in with.
+	Username string `json:"username,omitempty"`
 }
 
 // Payload to import SAML credentials
@@ -669,6 +840,11 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !cr.ID.IsNil() {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("The identity ID can only be set when importing identities using the batch patch identities endpoint.")))
+		return
+	}
+
 	i, err := h.identityFromCreateIdentityBody(r.Context(), &cr)
 	if err != nil {
 		h.r.Writer().WriteError(w, r, err)
@@ -705,6 +881,7 @@ func (h *Handler) identityFromCreateIdentityBody(ctx context.Context, cr *Create
 	}
 
 	i := &Identity{
+		ID:                  cr.ID,
 		SchemaID:            cr.SchemaID,
 		Traits:              []byte(cr.Traits),
 		State:               state,
@@ -792,6 +969,11 @@ func (h *Handler) batchPatchIdentities(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validateImportedIdentityIDs(req.Identities); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
 	res.Identities = make([]*BatchIdentityPatchResponse, len(req.Identities))
 	// Array to look up the index of the identity in the identities array.
 	indexInIdentities := make([]*int, len(req.Identities))
@@ -1015,6 +1197,11 @@ type deleteIdentity struct {
 // Calling this endpoint irrecoverably and permanently deletes the [identity](https://www.ory.sh/docs/kratos/concepts/identity-user-model) given its ID. This action can not be undone.
 // This endpoint returns 204 when the identity was deleted or 404 if the identity was not found.
 //
+// If soft deletion is enabled (`identity.soft_delete.enabled`), the identity is instead moved to the `deleted`
+// state and all of its sessions are revoked. Soft-deleted identities can not sign in, are hidden from the identity
+// list unless filtering by state, and can be restored using the undelete endpoint until they are purged by
+// `kratos cleanup sql` after the retention period (`identity.soft_delete.retention`).
+//
 //	Produces:
 //	- application/json
 //
@@ -1028,7 +1215,15 @@ type deleteIdentity struct {
 //	  404: errorGeneric
 //	  default: errorGeneric
 func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
-	if err := h.r.PrivilegedIdentityPool().DeleteIdentity(r.Context(), x.ParseUUID(r.PathValue("id"))); err != nil {
+	ctx := r.Context()
+	id := x.ParseUUID(r.PathValue("id"))
+
+	deleteIdentity := h.r.PrivilegedIdentityPool().DeleteIdentity
+	if h.r.Config().IdentitySoftDelete(ctx).Enabled {
+		deleteIdentity = h.r.PrivilegedIdentityPool().SoftDeleteIdentity
+	}
+
+	if err := deleteIdentity(ctx, id); err != nil {
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
@@ -1036,6 +1231,59 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Undelete Identity Parameters
+//
+// swagger:parameters undeleteIdentity
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type undeleteIdentity struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route POST /admin/identities/{id}/undelete identity undeleteIdentity
+//
+// # Undelete an Identity
+//
+// Restores a soft-deleted identity to the state it had before it was deleted. Sessions revoked by the deletion
+// are not restored. This endpoint returns 404 if the identity was not found, for example because it was already
+// purged, and 409 if the identity is not deleted.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identity
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) undelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := x.ParseUUID(r.PathValue("id"))
+
+	if err := h.r.PrivilegedIdentityPool().UndeleteIdentity(ctx, id); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	i, err := h.r.PrivilegedIdentityPool().GetIdentityConfidential(ctx, id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, WithCredentialsNoConfigAndAdminMetadataInJSON(*i))
+}
+
 // Patch Identity Parameters
 //
 // swagger:parameters patchIdentity
@@ -1205,7 +1453,7 @@ func (h *Handler) deleteIdentityCredentials(w http.ResponseWriter, r *http.Reque
 			h.r.Writer().WriteError(w, r, err)
 			return
 		}
-	case CredentialsTypePassword, CredentialsTypeOIDC, CredentialsTypeSAML:
+	case CredentialsTypePassword, CredentialsTypeOIDC, CredentialsTypeSAML, CredentialsTypeLDAP:
 		firstFactor, err := h.r.IdentityManager().CountActiveFirstFactorCredentials(ctx, identity)
 		if err != nil {
 			h.r.Writer().WriteError(w, r, err)
@@ -1226,6 +1474,8 @@ func (h *Handler) deleteIdentityCredentials(w http.ResponseWriter, r *http.Reque
 				h.r.Writer().WriteError(w, r, err)
 				return
 			}
+		case CredentialsTypeLDAP:
+			identity.DeleteCredentialsType(cred.Type)
 		}
 	default:
 		// A bunch of credential type deletions are not yet implemented, e.g. passkeys, etc.
@@ -1244,3 +1494,105 @@ func (h *Handler) deleteIdentityCredentials(w http.ResponseWriter, r *http.Reque
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// List Identity Login Lockouts Response
+//
+// swagger:response listIdentityLoginLockouts
+type _ struct {
+	// in: body
+	Body []LoginLockout
+}
+
+// List Identity Login Lockouts Parameters
+//
+// swagger:parameters listIdentityLoginLockouts
+type _ struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/identities/{id}/lockouts identity listIdentityLoginLockouts
+//
+// # List an Identity's Login Lockouts
+//
+// Lists the failed login attempt counters of an identity, including the time
+// until which logins are locked. Counters for client IP addresses are not
+// bound to an identity and are not included.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listIdentityLoginLockouts
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listIdentityLoginLockouts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	i, err := h.r.IdentityPool().GetIdentity(ctx, x.ParseUUID(r.PathValue("id")), ExpandNothing)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	lockouts, err := h.r.LoginLockoutPersister().ListLoginLockoutsByIdentity(ctx, i.ID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, lockouts)
+}
+
+// Delete Identity Login Lockouts Parameters
+//
+// swagger:parameters deleteIdentityLoginLockouts
+type _ struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route DELETE /admin/identities/{id}/lockouts identity deleteIdentityLoginLockouts
+//
+// # Clear an Identity's Login Lockouts
+//
+// Resets the failed login attempt counters of an identity and lifts any
+// active login lockout.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) deleteIdentityLoginLockouts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	i, err := h.r.IdentityPool().GetIdentity(ctx, x.ParseUUID(r.PathValue("id")), ExpandNothing)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.LoginLockoutPersister().DeleteLoginLockoutsByIdentity(ctx, i.ID); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
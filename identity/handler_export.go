@@ -0,0 +1,153 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/crdbx"
+	"github.com/ory/x/pagination/keysetpagination"
+)
+
+// Export Identities Parameters
+//
+// swagger:parameters exportIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type exportIdentities struct {
+	// Only export identities that belong to a specific organization.
+	//
+	// required: false
+	// in: query
+	OrganizationID string `json:"organization_id"`
+
+	// Only export identities matching all filter expressions. See the `filter`
+	// parameter of `listIdentities` for the syntax.
+	//
+	// required: false
+	// in: query
+	Filter []string `json:"filter"`
+
+	crdbx.ConsistencyRequestParameters
+}
+
+// Exported Identities
+//
+// swagger:response exportIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type exportIdentitiesResponse struct {
+	// One identity per line in the format of the create identity API.
+	//
+	// in: body
+	Body string
+}
+
+func parseExportIdentitiesParameters(r *http.Request) (params ListIdentityParameters, err error) {
+	query := r.URL.Query()
+	params.Expand = ExpandEverything
+
+	if orgID := query.Get("organization_id"); orgID != "" {
+		params.OrganizationID, err = uuid.FromString(orgID)
+		if err != nil {
+			return params, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Invalid UUID value `%s` for parameter `organization_id`.", orgID))
+		}
+	}
+
+	params.Filters, err = parseListIdentityFilters(query)
+	if err != nil {
+		return params, err
+	}
+
+	params.KeySetPagination = []keysetpagination.Option{keysetpagination.WithSize(exportIdentitiesPageSize)}
+	params.ConsistencyLevel = crdbx.ConsistencyLevelFromRequest(r)
+
+	return params, nil
+}
+
+// swagger:route GET /admin/identities/export identity exportIdentities
+//
+// # Export Identities
+//
+// Streams all [identities](https://www.ory.sh/docs/kratos/concepts/identity-user-model) including their credentials
+// as newline-delimited JSON. Every line is an identity in the format of the `createIdentity` and `batchPatchIdentities`
+// APIs, which can be used to import the identities into another Ory Kratos instance.
+//
+// Hashed passwords, TOTP, lookup secret, WebAuthn, passkey, OpenID Connect, SAML, and LDAP credentials are exported.
+// Code credentials are derived from the traits when the identities are imported. The initial OpenID Connect tokens
+// remain encrypted with the cipher secret, and can only be used by an instance configured with the same secret.
+// Identities must be imported with the `batchPatchIdentities` endpoint to keep their IDs.
+//
+// If the export fails after it started, for example because an identity has credentials which can not be exported,
+// the connection is aborted instead of ending the response.
+//
+//	Produces:
+//	- application/x-ndjson
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: exportIdentities
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) export(w http.ResponseWriter, r *http.Request) {
+	params, err := parseExportIdentitiesParameters(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	// Load the first page before writing the header so that errors can still be
+	// returned as such.
+	is, nextPage, err := h.r.IdentityPool().ListIdentities(r.Context(), params)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	// The status was already sent, so if the export fails from here on,
+	// aborting the connection is the only way to tell the client that the
+	// export is incomplete.
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for {
+		for k := range is {
+			export, err := ExportIdentity(&is[k])
+			if err != nil {
+				h.r.Logger().WithRequest(r).WithError(err).Error("Aborting the identity export because an identity could not be exported.")
+				panic(http.ErrAbortHandler)
+			}
+			if err := enc.Encode(export); err != nil {
+				panic(http.ErrAbortHandler)
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if nextPage == nil || nextPage.IsLast() {
+			return
+		}
+
+		params.KeySetPagination = nextPage.ToOptions()
+		is, nextPage, err = h.r.IdentityPool().ListIdentities(r.Context(), params)
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
+	"github.com/pquerna/otp"
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/hash"
@@ -19,7 +21,7 @@ func (h *Handler) importCredentials(ctx context.Context, i *Identity, creds *Ide
 		return nil
 	}
 
-	// This method only support password and OIDC import at the moment.
+	// This method only support password, OIDC, SAML, TOTP, lookup secret, WebAuthn, passkey, and LDAP import at the moment.
 	// If other methods are added please ensure that the available AAL is set correctly in the identity.
 	//
 	// It would actually be good if we would validate the identity post-creation to see if the credentials are working.
@@ -45,9 +47,97 @@ func (h *Handler) importCredentials(ctx context.Context, i *Identity, creds *Ide
 		}
 	}
 
+	if creds.TOTP != nil {
+		if err := h.importTOTPCredentials(ctx, i, creds.TOTP); err != nil {
+			return err
+		}
+	}
+
+	if creds.WebAuthn != nil {
+		// Like passwords, the identifiers of WebAuthn credentials are set by the identity validation.
+		if err := i.SetCredentialsWithConfig(CredentialsTypeWebAuthn, Credentials{}, creds.WebAuthn.Config); err != nil {
+			return err
+		}
+	}
+
+	if creds.Passkey != nil {
+		if len(creds.Passkey.Config.UserHandle) == 0 {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The imported passkey credentials must contain a user handle."))
+		}
+		if err := i.SetCredentialsWithConfig(CredentialsTypePasskey, Credentials{Identifiers: []string{string(creds.Passkey.Config.UserHandle)}}, creds.Passkey.Config); err != nil {
+			return err
+		}
+	}
+
+	if creds.LookupSecret != nil {
+		if err := h.importLookupSecretCredentials(ctx, i, creds.LookupSecret); err != nil {
+			return err
+		}
+	}
+
+	if creds.LDAP != nil {
+		if creds.LDAP.Config.ID == "" {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The imported LDAP credentials must contain the ID of the directory entry."))
+		}
+		if err := i.SetCredentialsWithConfig(CredentialsTypeLDAP, Credentials{Identifiers: []string{creds.LDAP.Config.ID}}, CredentialsLDAP{
+			DN:       creds.LDAP.Config.DN,
+			Username: creds.LDAP.Config.Username,
+		}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validateImportedIdentityIDs ensures that the identity IDs set in a batch
+// import look like the ones Ory Kratos generates, and that no ID is used twice.
+func validateImportedIdentityIDs(patches []*BatchIdentityPatch) error {
+	seen := make(map[uuid.UUID]struct{}, len(patches))
+	for _, patch := range patches {
+		if patch == nil || patch.Create == nil || patch.Create.ID.IsNil() {
+			continue
+		}
+
+		id := patch.Create.ID
+		if id.Version() != uuid.V4 || id.Variant() != uuid.VariantRFC4122 {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The imported identity ID %s must be a version 4 UUID.", id))
+		}
+		if _, ok := seen[id]; ok {
+			return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The imported identity ID %s is used more than once.", id))
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+func (h *Handler) importLookupSecretCredentials(_ context.Context, i *Identity, creds *AdminIdentityImportCredentialsLookupSecret) error {
+	if len(creds.Config.RecoveryCodes) == 0 {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The imported lookup secret credentials must contain at least one recovery code."))
+	}
+
+	// Like TOTP credentials, lookup secrets are identified by the identity's ID.
+	if i.ID.IsNil() {
+		i.ID = x.NewUUID()
+	}
+
+	return i.SetCredentialsWithConfig(CredentialsTypeLookup, Credentials{Identifiers: []string{i.ID.String()}}, creds.Config)
+}
+
+func (h *Handler) importTOTPCredentials(_ context.Context, i *Identity, creds *AdminIdentityImportCredentialsTOTP) error {
+	if _, err := otp.NewKeyFromURL(creds.Config.TOTPURL); err != nil {
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("The imported TOTP URL is invalid: %s", err))
+	}
+
+	// TOTP credentials are identified by the identity's ID, which is therefore
+	// generated here if it was not imported.
+	if i.ID.IsNil() {
+		i.ID = x.NewUUID()
+	}
+
+	return i.SetCredentialsWithConfig(CredentialsTypeTOTP, Credentials{Identifiers: []string{i.ID.String()}}, CredentialsTOTPConfig{TOTPURL: creds.Config.TOTPURL})
+}
+
 func (h *Handler) importPasswordCredentials(ctx context.Context, i *Identity, creds *AdminIdentityImportCredentialsPassword) (err error) {
 	if creds.Config.UsePasswordMigrationHook {
 		return i.SetCredentialsWithConfig(CredentialsTypePassword, Credentials{}, CredentialsPassword{UsePasswordMigrationHook: true})
@@ -82,9 +172,12 @@ func (h *Handler) importOIDCCredentials(_ context.Context, i *Identity, creds *A
 		for _, p := range creds.Config.Providers {
 			ids = append(ids, OIDCUniqueID(p.Provider, p.Subject))
 			provider := CredentialsOIDCProvider{
-				Subject:     p.Subject,
-				Provider:    p.Provider,
-				UseAutoLink: p.UseAutoLink,
+				Subject:             p.Subject,
+				Provider:            p.Provider,
+				UseAutoLink:         p.UseAutoLink,
+				InitialIDToken:      p.InitialIDToken,
+				InitialAccessToken:  p.InitialAccessToken,
+				InitialRefreshToken: p.InitialRefreshToken,
 			}
 			if p.Organization.Valid {
 				provider.Organization = p.Organization.UUID.String()
@@ -106,9 +199,12 @@ func (h *Handler) importOIDCCredentials(_ context.Context, i *Identity, creds *A
 	for _, p := range creds.Config.Providers {
 		c.Identifiers = append(c.Identifiers, OIDCUniqueID(p.Provider, p.Subject))
 		provider := CredentialsOIDCProvider{
-			Subject:     p.Subject,
-			Provider:    p.Provider,
-			UseAutoLink: p.UseAutoLink,
+			Subject:             p.Subject,
+			Provider:            p.Provider,
+			UseAutoLink:         p.UseAutoLink,
+			InitialIDToken:      p.InitialIDToken,
+			InitialAccessToken:  p.InitialAccessToken,
+			InitialRefreshToken: p.InitialRefreshToken,
 		}
 		if p.Organization.Valid {
 			provider.Organization = p.Organization.UUID.String()
@@ -6,6 +6,7 @@ package identity_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 	"github.com/tidwall/gjson"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/ory/herodot"
 	"github.com/ory/x/configx"
 
 	"github.com/ory/kratos/driver/config"
@@ -35,6 +37,7 @@ import (
 	"github.com/ory/kratos/internal/testhelpers"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/ioutilx"
 	"github.com/ory/x/randx"
 	"github.com/ory/x/snapshotx"
@@ -1379,6 +1382,42 @@ func TestHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("case=should list and reset login lockouts", func(t *testing.T) {
+		i := &identity.Identity{Traits: identity.Traits(fmt.Sprintf(`{"subject":"%s"}`, x.NewUUID()))}
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(context.Background(), i))
+
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("endpoint="+name, func(t *testing.T) {
+				subject := x.NewUUID().String()
+				_, err := reg.LoginLockoutPersister().UpdateLoginLockout(context.Background(), identity.LoginLockoutScopeIdentifier, subject, identity.CredentialsTypePassword, func(l *identity.LoginLockout) {
+					l.IdentityID = uuid.NullUUID{UUID: i.ID, Valid: true}
+					l.FailedAttempts = 5
+					l.LastFailedAt = time.Now().UTC()
+					l.LockedUntil = sqlxx.NullTime(time.Now().UTC().Add(time.Hour))
+				})
+				require.NoError(t, err)
+
+				res := get(t, ts, "/identities/"+i.ID.String()+"/lockouts", http.StatusOK)
+				require.Len(t, res.Array(), 1, "%s", res.Raw)
+				assert.EqualValues(t, identity.LoginLockoutScopeIdentifier, res.Get("0.scope").String(), "%s", res.Raw)
+				assert.EqualValues(t, subject, res.Get("0.subject").String(), "%s", res.Raw)
+				assert.EqualValues(t, identity.CredentialsTypePassword, res.Get("0.credentials_type").String(), "%s", res.Raw)
+				assert.EqualValues(t, 5, res.Get("0.failed_attempts").Int(), "%s", res.Raw)
+				assert.True(t, res.Get("0.locked_until").Exists(), "%s", res.Raw)
+
+				remove(t, ts, "/identities/"+i.ID.String()+"/lockouts", http.StatusNoContent)
+
+				res = get(t, ts, "/identities/"+i.ID.String()+"/lockouts", http.StatusOK)
+				assert.Len(t, res.Array(), 0, "%s", res.Raw)
+			})
+		}
+
+		t.Run("case=should return 404 for an unknown identity", func(t *testing.T) {
+			_ = get(t, adminTS, "/identities/"+x.NewUUID().String()+"/lockouts", http.StatusNotFound)
+			remove(t, adminTS, "/identities/"+x.NewUUID().String()+"/lockouts", http.StatusNotFound)
+		})
+	})
+
 	t.Run("case=PATCH update should not persist if schema id is invalid", func(t *testing.T) {
 		sub := x.NewUUID().String()
 		i := &identity.Identity{Traits: identity.Traits(fmt.Sprintf(`{"subject":"%s"}`, sub))}
@@ -1885,6 +1924,386 @@ func TestHandler(t *testing.T) {
 		})
 	})
 
+	t.Run("filter expressions", func(t *testing.T) {
+		orgID := uuid.Must(uuid.NewV4())
+		email := x.NewUUID().String() + "@ory.sh"
+		i := &identity.Identity{
+			Traits:         identity.Traits(`{"email":"` + email + `"}`),
+			MetadataPublic: []byte(`{"plan":"enterprise"}`),
+			OrganizationID: uuid.NullUUID{UUID: orgID, Valid: true},
+		}
+		require.NoError(t, reg.IdentityManager().Create(t.Context(), i))
+
+		t.Run("case=should list identities matching all filters", func(t *testing.T) {
+			vals := url.Values{"filter": {"traits.email=" + email, "metadata_public.plan=enterprise", "state=active"}}
+			res := get(t, adminTS, "/identities?"+vals.Encode(), http.StatusOK)
+			require.Len(t, res.Array(), 1, "%s", res.Raw)
+			assert.EqualValues(t, i.ID.String(), res.Get("0.id").String(), "%s", res.Raw)
+
+			vals.Add("filter", "metadata_public.plan=free")
+			res = get(t, adminTS, "/identities?"+vals.Encode(), http.StatusOK)
+			assert.Len(t, res.Array(), 0, "%s", res.Raw)
+		})
+
+		t.Run("case=can be combined with other filters", func(t *testing.T) {
+			vals := url.Values{"filter": {"traits.email=" + email}, "organization_id": {orgID.String()}}
+			res := get(t, adminTS, "/identities?"+vals.Encode(), http.StatusOK)
+			assert.Len(t, res.Array(), 1, "%s", res.Raw)
+
+			vals.Set("organization_id", x.NewUUID().String())
+			res = get(t, adminTS, "/identities?"+vals.Encode(), http.StatusOK)
+			assert.Len(t, res.Array(), 0, "%s", res.Raw)
+		})
+
+		t.Run("case=malformed filters should return an error", func(t *testing.T) {
+			for expression, reason := range map[string]string{
+				"traits.email":             "is malformed",
+				"password=secret":          "unknown field `password`",
+				"traits=foo":               "must select a value within `traits`",
+				"state.foo=bar":            "not a JSON document",
+				"traits.email>a":           "may only use the operators",
+				"created_at>yesterday":     "RFC 3339 timestamp",
+				"state=unknown":            "valid state",
+				"traits.email;drop=x@y.z":  "is malformed",
+				"traits.some..email=x@y.z": "invalid path",
+			} {
+				t.Run("filter="+expression, func(t *testing.T) {
+					res := get(t, adminTS, "/identities?"+url.Values{"filter": {expression}}.Encode(), http.StatusBadRequest)
+					assert.Contains(t, res.Get("error.reason").String(), reason, "%s", res.Raw)
+				})
+			}
+		})
+
+		t.Run("case=the number of filters is capped", func(t *testing.T) {
+			vals := url.Values{}
+			for range 11 {
+				vals.Add("filter", "state=active")
+			}
+			res := get(t, adminTS, "/identities?"+vals.Encode(), http.StatusBadRequest)
+			assert.Contains(t, res.Get("error.reason").String(), "must not exceed 10")
+		})
+	})
+
+	t.Run("suite=export", func(t *testing.T) {
+		marker := x.NewUUID().String()
+		exportURL := "/identities/export?" + url.Values{"filter": {"metadata_public.export=" + marker}}.Encode()
+
+		export := func(t *testing.T, ts *httptest.Server, href string) []string {
+			t.Helper()
+			res, err := ts.Client().Get(ts.URL + href)
+			require.NoError(t, err)
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+
+			require.EqualValues(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+			return strings.FieldsFunc(string(body), func(r rune) bool { return r == '\n' })
+		}
+
+		hashed, err := reg.Hasher(t.Context()).Generate(t.Context(), []byte(x.NewUUID().String()))
+		require.NoError(t, err)
+		idToken, err := reg.Cipher(t.Context()).Encrypt(t.Context(), []byte("id-token"))
+		require.NoError(t, err)
+
+		id := x.NewUUID()
+		subject := x.NewUUID().String()
+		ldapID := x.NewUUID().String()
+		i := &identity.Identity{
+			ID:             id,
+			Traits:         identity.Traits(`{"email":"` + x.NewUUID().String() + `@ory.sh"}`),
+			MetadataPublic: []byte(`{"export":"` + marker + `"}`),
+			MetadataAdmin:  []byte(`{"secret":"admin"}`),
+			ExternalID:     sqlxx.NullString(x.NewUUID().String()),
+			State:          identity.StateInactive,
+			Credentials: map[identity.CredentialsType]identity.Credentials{
+				identity.CredentialsTypePassword: {
+					Type:   identity.CredentialsTypePassword,
+					Config: []byte(`{"hashed_password":"` + string(hashed) + `"}`),
+				},
+				identity.CredentialsTypeOIDC: {
+					Type:        identity.CredentialsTypeOIDC,
+					Identifiers: []string{identity.OIDCUniqueID("github", subject)},
+					Config:      []byte(`{"providers":[{"subject":"` + subject + `","provider":"github","initial_id_token":"` + idToken + `","initial_access_token":"","initial_refresh_token":""}]}`),
+				},
+				identity.CredentialsTypeTOTP: {
+					Type:        identity.CredentialsTypeTOTP,
+					Identifiers: []string{id.String()},
+					Config:      []byte(`{"totp_url":"otpauth://totp/test?secret=JBSWY3DPEHPK3PXP"}`),
+				},
+				identity.CredentialsTypeWebAuthn: {
+					Type:   identity.CredentialsTypeWebAuthn,
+					Config: []byte(`{"credentials":[{"id":"THTndqZP5Mjvae1BFvJMaMfEMm7O7HE1ju+7PBaYA7Y=","added_at":"2022-12-16T14:11:55Z","public_key":"pQECAyYgASFYIMJLQhJxQRzhnKPTcPCUODOmxYDYo2obrm9bhp5lvSZ3IlggXjhZvJaPUqF9PXqZqTdWYPR7R+b2n/Wi+IxKKXsS4rU=","display_name":"test","authenticator":{"aaguid":"rc4AAjW8xgpkiwsl8fBVAw==","sign_count":0,"clone_warning":false},"is_passwordless":false,"attestation_type":"none"}],"user_handle":"` + base64.StdEncoding.EncodeToString(id.Bytes()) + `"}`),
+				},
+				identity.CredentialsTypeLookup: {
+					Type:        identity.CredentialsTypeLookup,
+					Identifiers: []string{id.String()},
+					Config:      []byte(`{"recovery_codes":[{"code":"aaaa1111","used_at":null},{"code":"bbbb2222","used_at":"2022-12-16T14:11:55Z"}]}`),
+				},
+				identity.CredentialsTypeLDAP: {
+					Type:        identity.CredentialsTypeLDAP,
+					Identifiers: []string{ldapID},
+					Config:      []byte(`{"dn":"uid=jdoe,ou=people,dc=example,dc=org","username":"jdoe"}`),
+				},
+			},
+		}
+		require.NoError(t, reg.IdentityManager().Create(t.Context(), i))
+
+		other := &identity.Identity{
+			Traits:         identity.Traits(`{}`),
+			MetadataPublic: []byte(`{"export":"` + marker + `"}`),
+		}
+		require.NoError(t, reg.IdentityManager().Create(t.Context(), other))
+
+		expected, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), i.ID)
+		require.NoError(t, err)
+
+		t.Run("case=should export identities including their credentials", func(t *testing.T) {
+			for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+				t.Run("endpoint="+name, func(t *testing.T) {
+					lines := export(t, ts, exportURL)
+					require.Len(t, lines, 2)
+
+					ids := []string{gjson.Get(lines[0], "id").String(), gjson.Get(lines[1], "id").String()}
+					assert.ElementsMatch(t, []string{i.ID.String(), other.ID.String()}, ids)
+
+					for _, line := range lines {
+						if gjson.Get(line, "id").String() != i.ID.String() {
+							assert.Nil(t, gjson.Get(line, "credentials").Value(), "%s", line)
+							continue
+						}
+						assert.Equal(t, string(hashed), gjson.Get(line, "credentials.password.config.hashed_password").String(), "%s", line)
+						assert.Equal(t, idToken, gjson.Get(line, "credentials.oidc.config.providers.0.initial_id_token").String(), "%s", line)
+						assert.Equal(t, "otpauth://totp/test?secret=JBSWY3DPEHPK3PXP", gjson.Get(line, "credentials.totp.config.totp_url").String(), "%s", line)
+						assert.Len(t, gjson.Get(line, "credentials.webauthn.config.credentials").Array(), 1, "%s", line)
+						assert.Len(t, gjson.Get(line, "credentials.lookup_secret.config.recovery_codes").Array(), 2, "%s", line)
+						assert.Equal(t, ldapID, gjson.Get(line, "credentials.ldap.config.id").String(), "%s", line)
+						assert.Equal(t, "admin", gjson.Get(line, "metadata_admin.secret").String(), "%s", line)
+						assert.Equal(t, string(identity.StateInactive), gjson.Get(line, "state").String(), "%s", line)
+					}
+				})
+			}
+		})
+
+		t.Run("case=should not export identities which do not match", func(t *testing.T) {
+			lines := export(t, adminTS, "/identities/export?"+url.Values{"filter": {"metadata_public.export=" + x.NewUUID().String()}}.Encode())
+			assert.Len(t, lines, 0)
+		})
+
+		t.Run("case=should reject malformed filters", func(t *testing.T) {
+			res := get(t, adminTS, "/identities/export?"+url.Values{"filter": {"password=secret"}}.Encode(), http.StatusBadRequest)
+			assert.Contains(t, res.Get("error.reason").String(), "unknown field `password`", "%s", res.Raw)
+		})
+
+		t.Run("case=should import the export losslessly", func(t *testing.T) {
+			lines := export(t, adminTS, exportURL)
+			require.Len(t, lines, 2)
+
+			remove(t, adminTS, "/identities/"+i.ID.String(), http.StatusNoContent)
+			remove(t, adminTS, "/identities/"+other.ID.String(), http.StatusNoContent)
+
+			patches := make([]json.RawMessage, len(lines))
+			for k, line := range lines {
+				patches[k] = json.RawMessage(`{"create":` + line + `}`)
+			}
+			res := send(t, adminTS, "PATCH", "/identities", http.StatusOK, map[string]any{"identities": patches})
+			for k, line := range lines {
+				assert.Equal(t, gjson.Get(line, "id").String(), res.Get(fmt.Sprintf("identities.%d.identity", k)).String(), "%s", res.Raw)
+			}
+
+			actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), i.ID)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expected.Traits), string(actual.Traits))
+			assert.JSONEq(t, string(expected.MetadataPublic), string(actual.MetadataPublic))
+			assert.JSONEq(t, string(expected.MetadataAdmin), string(actual.MetadataAdmin))
+			assert.Equal(t, expected.State, actual.State)
+			assert.Equal(t, expected.ExternalID, actual.ExternalID)
+			require.Len(t, actual.Credentials, len(expected.Credentials))
+			for ct, c := range expected.Credentials {
+				t.Run("type="+string(ct), func(t *testing.T) {
+					require.Contains(t, actual.Credentials, ct)
+					assert.ElementsMatch(t, c.Identifiers, actual.Credentials[ct].Identifiers)
+					assert.JSONEq(t, string(c.Config), string(actual.Credentials[ct].Config))
+				})
+			}
+
+			lines = export(t, adminTS, exportURL)
+			require.Len(t, lines, 2)
+		})
+
+		t.Run("case=should only accept identity IDs in batch imports", func(t *testing.T) {
+			body := func(id string) json.RawMessage {
+				return json.RawMessage(`{"id":"` + id + `","schema_id":"default","traits":{}}`)
+			}
+
+			res := send(t, adminTS, "POST", "/identities", http.StatusBadRequest, body(x.NewUUID().String()))
+			assert.Contains(t, res.Get("error.reason").String(), "batch patch identities endpoint", "%s", res.Raw)
+
+			res = send(t, adminTS, "PATCH", "/identities", http.StatusBadRequest, map[string]any{"identities": []any{
+				map[string]any{"create": body(uuid.Must(uuid.NewV7()).String())},
+			}})
+			assert.Contains(t, res.Get("error.reason").String(), "must be a version 4 UUID", "%s", res.Raw)
+
+			duplicate := x.NewUUID().String()
+			res = send(t, adminTS, "PATCH", "/identities", http.StatusBadRequest, map[string]any{"identities": []any{
+				map[string]any{"create": body(duplicate)},
+				map[string]any{"create": body(duplicate)},
+			}})
+			assert.Contains(t, res.Get("error.reason").String(), "is used more than once", "%s", res.Raw)
+		})
+
+		t.Run("case=should fail on credentials which can not be exported", func(t *testing.T) {
+			_, err := identity.ExportIdentity(&identity.Identity{
+				ID:          x.NewUUID(),
+				Credentials: map[identity.CredentialsType]identity.Credentials{identity.CredentialsTypeProfile: {Type: identity.CredentialsTypeProfile}},
+			})
+			var herr *herodot.DefaultError
+			require.ErrorAs(t, err, &herr)
+			assert.Contains(t, herr.Reason(), "Unable to export the profile credentials")
+		})
+	})
+
+	t.Run("suite=versions", func(t *testing.T) {
+		email := x.NewUUID().String() + "@ory.sh"
+		created := send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(`{"traits":{"bar":"v1","email":"`+email+`"},"metadata_public":{"plan":"free"}}`))
+		id := created.Get("id").String()
+		send(t, adminTS, "PUT", "/identities/"+id, http.StatusOK, json.RawMessage(`{"traits":{"bar":"v2","email":"`+email+`"},"metadata_public":{"plan":"free"},"state":"inactive"}`))
+
+		t.Run("case=should list versions newest first", func(t *testing.T) {
+			for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+				t.Run("endpoint="+name, func(t *testing.T) {
+					res := get(t, ts, "/identities/"+id+"/versions", http.StatusOK)
+					require.Len(t, res.Array(), 2, "%s", res.Raw)
+					assert.EqualValues(t, 2, res.Get("0.version").Int(), "%s", res.Raw)
+					assert.Equal(t, "v2", res.Get("0.traits.bar").String(), "%s", res.Raw)
+					assert.Equal(t, "inactive", res.Get("0.state").String(), "%s", res.Raw)
+					assert.Equal(t, events.ActorAdmin, res.Get("0.actor").String(), "%s", res.Raw)
+					assert.EqualValues(t, 1, res.Get("1.version").Int(), "%s", res.Raw)
+					assert.Equal(t, "v1", res.Get("1.traits.bar").String(), "%s", res.Raw)
+				})
+			}
+		})
+
+		t.Run("case=should paginate versions", func(t *testing.T) {
+			res, hres := getFull(t, adminTS, "/identities/"+id+"/versions?page_size=1", http.StatusOK)
+			require.Len(t, res.Array(), 1, "%s", res.Raw)
+			assert.EqualValues(t, 2, res.Get("0.version").Int(), "%s", res.Raw)
+			assert.Contains(t, hres.Header.Get("Link"), "page_token=")
+		})
+
+		t.Run("case=should get a version", func(t *testing.T) {
+			res := get(t, adminTS, "/identities/"+id+"/versions/1", http.StatusOK)
+			assert.Equal(t, "v1", res.Get("traits.bar").String(), "%s", res.Raw)
+			assert.Equal(t, "active", res.Get("state").String(), "%s", res.Raw)
+			assert.Equal(t, "free", res.Get("metadata_public.plan").String(), "%s", res.Raw)
+			assert.Equal(t, "password", res.Get("credential_types.0").String(), "%s", res.Raw)
+
+			get(t, adminTS, "/identities/"+id+"/versions/99", http.StatusNotFound)
+			get(t, adminTS, "/identities/"+x.NewUUID().String()+"/versions/1", http.StatusNotFound)
+			get(t, adminTS, "/identities/"+x.NewUUID().String()+"/versions", http.StatusNotFound)
+			get(t, adminTS, "/identities/"+id+"/versions/latest", http.StatusBadRequest)
+		})
+
+		t.Run("case=should diff against the previous version", func(t *testing.T) {
+			res := get(t, adminTS, "/identities/"+id+"/versions/2/diff", http.StatusOK)
+			assert.EqualValues(t, 1, res.Get("from").Int())
+			assert.EqualValues(t, 2, res.Get("to").Int())
+			assert.JSONEq(t, `[
+				{"path":"state","from":"active","to":"inactive"},
+				{"path":"traits.bar","from":"v1","to":"v2"}
+			]`, res.Get("changes").Raw)
+		})
+
+		t.Run("case=should diff the first version against nothing", func(t *testing.T) {
+			res := get(t, adminTS, "/identities/"+id+"/versions/1/diff", http.StatusOK)
+			assert.EqualValues(t, 0, res.Get("from").Int())
+			assert.Equal(t, "v1", res.Get(`changes.#(path=="traits.bar").to`).String(), "%s", res.Raw)
+			assert.False(t, res.Get(`changes.#(path=="traits.bar").from`).Exists(), "%s", res.Raw)
+		})
+
+		t.Run("case=should diff against a given version", func(t *testing.T) {
+			res := get(t, adminTS, "/identities/"+id+"/versions/1/diff?from=2", http.StatusOK)
+			assert.JSONEq(t, `[
+				{"path":"state","from":"inactive","to":"active"},
+				{"path":"traits.bar","from":"v2","to":"v1"}
+			]`, res.Get("changes").Raw)
+
+			get(t, adminTS, "/identities/"+id+"/versions/1/diff?from=99", http.StatusNotFound)
+		})
+
+		t.Run("case=should restore a version", func(t *testing.T) {
+			for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+				t.Run("endpoint="+name, func(t *testing.T) {
+					res := send(t, ts, "POST", "/identities/"+id+"/versions/1/restore", http.StatusOK, nil)
+					assert.Equal(t, "v1", res.Get("traits.bar").String(), "%s", res.Raw)
+					assert.Equal(t, "active", res.Get("state").String(), "%s", res.Raw)
+
+					latest := get(t, adminTS, "/identities/"+id+"/versions?page_size=1", http.StatusOK)
+					assert.Equal(t, "v1", latest.Get("0.traits.bar").String(), "%s", latest.Raw)
+					assert.Equal(t, events.ActorAdmin, latest.Get("0.actor").String(), "%s", latest.Raw)
+
+					send(t, ts, "POST", "/identities/"+id+"/versions/2/restore", http.StatusOK, nil)
+				})
+			}
+		})
+
+		t.Run("case=should validate the restored version", func(t *testing.T) {
+			send(t, adminTS, "PUT", "/identities/"+id, http.StatusOK, json.RawMessage(`{"traits":{"bar":"v3","email":"`+x.NewUUID().String()+`@ory.sh"}}`))
+			send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(`{"traits":{"email":"`+email+`"}}`))
+
+			send(t, adminTS, "POST", "/identities/"+id+"/versions/1/restore", http.StatusConflict, nil)
+
+			actual := get(t, adminTS, "/identities/"+id, http.StatusOK)
+			assert.Equal(t, "v3", actual.Get("traits.bar").String(), "%s", actual.Raw)
+		})
+	})
+
+	t.Run("suite=soft delete", func(t *testing.T) {
+		ctx := context.Background()
+		reg.Config().MustSet(ctx, config.ViperKeyIdentitySoftDeleteEnabled, true)
+		t.Cleanup(func() {
+			reg.Config().MustSet(ctx, config.ViperKeyIdentitySoftDeleteEnabled, false)
+		})
+
+		for name, ts := range map[string]*httptest.Server{"public": publicTS, "admin": adminTS} {
+			t.Run("endpoint="+name, func(t *testing.T) {
+				created := send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(`{"traits":{"bar":"baz"},"state":"inactive"}`))
+				id := created.Get("id").String()
+
+				remove(t, ts, "/identities/"+id, http.StatusNoContent)
+
+				actual := get(t, ts, "/identities/"+id, http.StatusOK)
+				assert.Equal(t, "deleted", actual.Get("state").String(), "%s", actual.Raw)
+
+				listed := get(t, ts, "/identities?ids="+id, http.StatusOK)
+				assert.Len(t, listed.Array(), 0, "%s", listed.Raw)
+				listed = get(t, ts, "/identities?ids="+id+"&filter=state%3Ddeleted", http.StatusOK)
+				assert.Len(t, listed.Array(), 1, "%s", listed.Raw)
+
+				undeleted := send(t, ts, "POST", "/identities/"+id+"/undelete", http.StatusOK, nil)
+				assert.Equal(t, id, undeleted.Get("id").String(), "%s", undeleted.Raw)
+				assert.Equal(t, "inactive", undeleted.Get("state").String(), "%s", undeleted.Raw)
+
+				send(t, ts, "POST", "/identities/"+id+"/undelete", http.StatusConflict, nil)
+			})
+		}
+
+		t.Run("case=should return 404 when undeleting an unknown identity", func(t *testing.T) {
+			send(t, adminTS, "POST", "/identities/"+x.NewUUID().String()+"/undelete", http.StatusNotFound, nil)
+		})
+
+		t.Run("case=should not restore the deleted state of a version", func(t *testing.T) {
+			created := send(t, adminTS, "POST", "/identities", http.StatusCreated, json.RawMessage(`{"traits":{"bar":"baz"}}`))
+			id := created.Get("id").String()
+			remove(t, adminTS, "/identities/"+id, http.StatusNoContent)
+			send(t, adminTS, "POST", "/identities/"+id+"/undelete", http.StatusOK, nil)
+
+			restored := send(t, adminTS, "POST", "/identities/"+id+"/versions/2/restore", http.StatusOK, nil)
+			assert.Equal(t, "active", restored.Get("state").String(), "%s", restored.Raw)
+		})
+	})
+
 	t.Run("case=should list all identities with credentials", func(t *testing.T) {
 		t.Run("include_credential=oidc should include OIDC credentials config", func(t *testing.T) {
 			res := get(t, adminTS, "/identities?include_credential=oidc", http.StatusOK)
@@ -0,0 +1,295 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+
+	"github.com/ory/kratos/x"
+)
+
+// Paginated Identity Version List Response
+//
+// swagger:response listIdentityVersions
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listIdentityVersionsResponse struct {
+	keysetpagination.ResponseHeaders
+
+	// List of identity versions
+	//
+	// in:body
+	Body []Version
+}
+
+// List Identity Versions Parameters
+//
+// swagger:parameters listIdentityVersions
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listIdentityVersions struct {
+	keysetpagination.RequestParameters
+
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/identities/{id}/versions identity listIdentityVersions
+//
+// # List an Identity's Versions
+//
+// Lists the versions of an identity, newest first. A version is written every
+// time the identity is created or updated, and contains the identity's traits,
+// metadata, state, schema, and credential types, but no secrets.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listIdentityVersions
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listIdentityVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	i, err := h.r.IdentityPool().GetIdentity(ctx, x.ParseUUID(r.PathValue("id")), ExpandNothing)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	keys := h.r.Config().SecretsPagination(ctx)
+	opts, err := keysetpagination.ParseQueryParams(keys, r.URL.Query())
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("The page token is invalid.").WithDebug(err.Error())))
+		return
+	}
+
+	versions, nextPage, err := h.r.PrivilegedIdentityPool().ListIdentityVersions(ctx, i.ID, opts)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	u := *r.URL
+	keysetpagination.SetLinkHeader(w, keys, &u, nextPage)
+	h.r.Writer().Write(w, r, versions)
+}
+
+// Get Identity Version Parameters
+//
+// swagger:parameters getIdentityVersion
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getIdentityVersion struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Version is the version number.
+	//
+	// required: true
+	// in: path
+	Version int `json:"version"`
+}
+
+// swagger:route GET /admin/identities/{id}/versions/{version} identity getIdentityVersion
+//
+// # Get an Identity's Version
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identityVersion
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getIdentityVersion(w http.ResponseWriter, r *http.Request) {
+	v, err := h.versionFromRequest(r, r.PathValue("version"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, v)
+}
+
+// Diff Identity Versions Parameters
+//
+// swagger:parameters diffIdentityVersions
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type diffIdentityVersions struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Version is the version number the changes lead to.
+	//
+	// required: true
+	// in: path
+	Version int `json:"version"`
+
+	// From is the version number the changes are relative to. Defaults to the
+	// previous version.
+	//
+	// required: false
+	// in: query
+	From int `json:"from"`
+}
+
+// swagger:route GET /admin/identities/{id}/versions/{version}/diff identity diffIdentityVersions
+//
+// # Compare two Versions of an Identity
+//
+// Returns the changes between two versions of an identity. By default, the
+// version is compared to the previous version. Objects such as the traits and
+// metadata are compared key by key, all other values as a whole.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identityVersionDiff
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) diffIdentityVersions(w http.ResponseWriter, r *http.Request) {
+	to, err := h.versionFromRequest(r, r.PathValue("version"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	from := &Version{IdentityID: to.IdentityID, Traits: Traits("{}")}
+	if raw := r.URL.Query().Get("from"); raw != "" {
+		from, err = h.versionFromRequest(r, raw)
+	} else if to.Version > 1 {
+		from, err = h.versionFromRequest(r, strconv.Itoa(to.Version-1))
+	}
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	diff, err := DiffVersions(from, to)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, diff)
+}
+
+// Restore Identity Version Parameters
+//
+// swagger:parameters restoreIdentityVersion
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type restoreIdentityVersion struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Version is the version number to restore.
+	//
+	// required: true
+	// in: path
+	Version int `json:"version"`
+}
+
+// swagger:route POST /admin/identities/{id}/versions/{version}/restore identity restoreIdentityVersion
+//
+// # Restore a Version of an Identity
+//
+// Restores the traits, metadata, state, and schema of an earlier version of
+// an identity. The restored identity is validated against its schema like any
+// other update, and the restore is recorded as a new version. Credentials are
+// not restored because versions do not contain secrets.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identity
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) restoreIdentityVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	v, err := h.versionFromRequest(r, r.PathValue("version"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	i, err := h.r.PrivilegedIdentityPool().GetIdentityConfidential(ctx, v.IdentityID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	v.Restore(i)
+	if err := h.r.IdentityManager().Update(
+		ctx,
+		i,
+		ManagerAllowWriteProtectedTraits,
+	); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, WithCredentialsNoConfigAndAdminMetadataInJSON(*i))
+}
+
+func (h *Handler) versionFromRequest(r *http.Request, raw string) (*Version, error) {
+	version, err := strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Invalid version `%s`, expected a positive number.", raw))
+	}
+
+	return h.r.PrivilegedIdentityPool().GetIdentityVersion(r.Context(), x.ParseUUID(r.PathValue("id")), version)
+}
@@ -26,7 +26,8 @@ import (
 
 // An Identity's State
 //
-// The state can either be `active` or `inactive`.
+// The state can either be `active` or `inactive`. Identities which were
+// soft-deleted are in the `deleted` state until they are undeleted or purged.
 //
 // swagger:enum State
 type State string
@@ -34,6 +35,7 @@ type State string
 const (
 	StateActive   State = "active"
 	StateInactive State = "inactive"
+	StateDeleted  State = "deleted"
 )
 
 func (lt State) IsValid() error {
@@ -0,0 +1,161 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+// ListIdentityFilterField is the field of an identity a filter expression
+// applies to.
+type ListIdentityFilterField string
+
+const (
+	ListIdentityFilterFieldTraits         ListIdentityFilterField = "traits"
+	ListIdentityFilterFieldMetadataPublic ListIdentityFilterField = "metadata_public"
+	ListIdentityFilterFieldMetadataAdmin  ListIdentityFilterField = "metadata_admin"
+	ListIdentityFilterFieldState          ListIdentityFilterField = "state"
+	ListIdentityFilterFieldSchemaID       ListIdentityFilterField = "schema_id"
+	ListIdentityFilterFieldCreatedAt      ListIdentityFilterField = "created_at"
+	ListIdentityFilterFieldUpdatedAt      ListIdentityFilterField = "updated_at"
+)
+
+// IsJSON returns true if the field is a JSON document which is filtered by a
+// path within the document.
+func (f ListIdentityFilterField) IsJSON() bool {
+	switch f {
+	case ListIdentityFilterFieldTraits, ListIdentityFilterFieldMetadataPublic, ListIdentityFilterFieldMetadataAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTime returns true if the field is a timestamp.
+func (f ListIdentityFilterField) IsTime() bool {
+	return f == ListIdentityFilterFieldCreatedAt || f == ListIdentityFilterFieldUpdatedAt
+}
+
+// ListIdentityFilterOperator compares the field of a filter expression with
+// its value.
+type ListIdentityFilterOperator string
+
+const (
+	ListIdentityFilterOperatorEqual              ListIdentityFilterOperator = "="
+	ListIdentityFilterOperatorNotEqual           ListIdentityFilterOperator = "!="
+	ListIdentityFilterOperatorGreaterThan        ListIdentityFilterOperator = ">"
+	ListIdentityFilterOperatorGreaterThanOrEqual ListIdentityFilterOperator = ">="
+	ListIdentityFilterOperatorLessThan           ListIdentityFilterOperator = "<"
+	ListIdentityFilterOperatorLessThanOrEqual    ListIdentityFilterOperator = "<="
+)
+
+// maxListIdentityFilters is the maximum number of filter expressions of a
+// single request.
+const maxListIdentityFilters = 10
+
+var (
+	listIdentityFilterExpression = regexp.MustCompile(`^([A-Za-z0-9_.\-]+)(!=|>=|<=|=|>|<)(.*)$`)
+	listIdentityFilterPathKey    = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+)
+
+// ListIdentityFilter is a filter expression of the identity list. Identities
+// match if the field compares to the value using the operator.
+type ListIdentityFilter struct {
+	Field ListIdentityFilterField
+
+	// Path is the path to the value within the traits or metadata. Keys which
+	// only consist of digits are array indices.
+	Path []string
+
+	Operator ListIdentityFilterOperator
+
+	// Value is a time.Time for the created_at and updated_at fields, and a
+	// string otherwise. JSON values are compared by their text
+	// representation, for example `true` or `42`.
+	Value any
+}
+
+// parseListIdentityFilters parses the `filter` query parameters of the list
+// and export identities endpoints.
+func parseListIdentityFilters(query url.Values) ([]ListIdentityFilter, error) {
+	expressions := query["filter"]
+	if len(expressions) > maxListIdentityFilters {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The number of filters must not exceed %d.", maxListIdentityFilters))
+	}
+
+	var filters []ListIdentityFilter
+	for _, v := range expressions {
+		filter, err := ParseListIdentityFilter(v)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// ParseListIdentityFilter parses a filter expression of the form
+// `<field><operator><value>`, for example `traits.email=foo@ory.sh`,
+// `metadata_public.plan!=free`, `state=inactive`, or
+// `created_at>=2025-01-01T00:00:00Z`.
+func ParseListIdentityFilter(expression string) (f ListIdentityFilter, _ error) {
+	matches := listIdentityFilterExpression.FindStringSubmatch(expression)
+	if matches == nil {
+		return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` is malformed. Filters must be of the form `<field><operator><value>`.", expression))
+	}
+
+	path := strings.Split(matches[1], ".")
+	f.Field = ListIdentityFilterField(path[0])
+	f.Path = path[1:]
+	f.Operator = ListIdentityFilterOperator(matches[2])
+
+	switch f.Field {
+	case ListIdentityFilterFieldTraits, ListIdentityFilterFieldMetadataPublic, ListIdentityFilterFieldMetadataAdmin,
+		ListIdentityFilterFieldState, ListIdentityFilterFieldSchemaID, ListIdentityFilterFieldCreatedAt, ListIdentityFilterFieldUpdatedAt:
+	default:
+		return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` uses the unknown field `%s`.", expression, f.Field))
+	}
+
+	if f.Field.IsJSON() {
+		if len(f.Path) == 0 {
+			return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` must select a value within `%s`, for example `%s.email`.", expression, f.Field, f.Field))
+		}
+		for _, key := range f.Path {
+			if !listIdentityFilterPathKey.MatchString(key) {
+				return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` contains an invalid path.", expression))
+			}
+		}
+	} else if len(f.Path) > 0 {
+		return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` selects a path within `%s`, which is not a JSON document.", expression, f.Field))
+	}
+
+	if !f.Field.IsTime() && f.Operator != ListIdentityFilterOperatorEqual && f.Operator != ListIdentityFilterOperatorNotEqual {
+		return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` may only use the operators `=` and `!=`.", expression))
+	}
+
+	switch {
+	case f.Field.IsTime():
+		t, err := time.Parse(time.RFC3339, matches[3])
+		if err != nil {
+			return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` must compare with an RFC 3339 timestamp.", expression).WithWrap(err))
+		}
+		f.Value = t.UTC()
+	case f.Field == ListIdentityFilterFieldState:
+		if err := State(matches[3]).IsValid(); err != nil && State(matches[3]) != StateDeleted {
+			return f, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter `%s` must compare with a valid state.", expression).WithWrap(err))
+		}
+		f.Value = matches[3]
+	default:
+		f.Value = matches[3]
+	}
+
+	return f, nil
+}
@@ -0,0 +1,300 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+)
+
+// LoginLockoutScope is the scope of a failed login attempt counter.
+//
+// swagger:enum LoginLockoutScope
+type LoginLockoutScope string
+
+const (
+	// LoginLockoutScopeIdentifier counts failed attempts per identifier and credentials type.
+	LoginLockoutScopeIdentifier LoginLockoutScope = "identifier"
+
+	// LoginLockoutScopeIP counts failed attempts per client IP address across all credentials types.
+	LoginLockoutScopeIP LoginLockoutScope = "ip"
+)
+
+// Login Lockout
+//
+// A login lockout counts consecutive failed login attempts for an identifier
+// or a client IP address and locks further attempts once a threshold is reached.
+//
+// swagger:model identityLoginLockout
+type LoginLockout struct {
+	ID  uuid.UUID `json:"-" db:"id"`
+	NID uuid.UUID `json:"-" faker:"-" db:"nid"`
+
+	// Scope is either "identifier" or "ip".
+	//
+	// required: true
+	Scope LoginLockoutScope `json:"scope" db:"scope"`
+
+	// Subject is the identifier or IP address the failed attempts are counted for.
+	//
+	// required: true
+	Subject string `json:"subject" db:"subject"`
+
+	// CredentialsType is the login method the failed attempts are counted
+	// for. It is empty for IP address counters.
+	CredentialsType CredentialsType `json:"credentials_type,omitempty" db:"credentials_type"`
+
+	// IdentityID is set if the subject belongs to a known identity.
+	IdentityID uuid.NullUUID `json:"identity_id,omitempty" db:"identity_id"`
+
+	// FailedAttempts is the number of consecutive failed attempts.
+	//
+	// required: true
+	FailedAttempts int `json:"failed_attempts" db:"failed_attempts"`
+
+	// LastFailedAt is the time of the last failed attempt.
+	//
+	// required: true
+	LastFailedAt time.Time `json:"last_failed_at" db:"last_failed_at"`
+
+	// LockedUntil is set while further login attempts are locked.
+	LockedUntil sqlxx.NullTime `json:"locked_until,omitempty" db:"locked_until"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (LoginLockout) TableName() string {
+	return "identity_login_lockouts"
+}
+
+// IsLocked returns true if login attempts are locked at the given time.
+func (l *LoginLockout) IsLocked(at time.Time) bool {
+	return time.Time(l.LockedUntil).After(at)
+}
+
+type (
+	LoginLockoutPersister interface {
+		// GetLoginLockout returns the counter for the given subject or sqlcon.ErrNoRows if none exists.
+		GetLoginLockout(ctx context.Context, scope LoginLockoutScope, subject string, ct CredentialsType) (*LoginLockout, error)
+
+		// UpdateLoginLockout creates or loads the counter for the given subject,
+		// applies the update, and stores the counter within one transaction.
+		// Concurrent updates of the same counter are applied one after the other.
+		UpdateLoginLockout(ctx context.Context, scope LoginLockoutScope, subject string, ct CredentialsType, update func(*LoginLockout)) (*LoginLockout, error)
+
+		// ListLoginLockoutsByIdentity lists all counters which belong to the identity.
+		ListLoginLockoutsByIdentity(ctx context.Context, identityID uuid.UUID) ([]LoginLockout, error)
+
+		// DeleteLoginLockout removes the counter for the given subject.
+		DeleteLoginLockout(ctx context.Context, scope LoginLockoutScope, subject string, ct CredentialsType) error
+
+		// DeleteLoginLockoutsByIdentity removes all counters which belong to the identity.
+		DeleteLoginLockoutsByIdentity(ctx context.Context, identityID uuid.UUID) error
+
+		// DeleteExpiredLoginLockouts removes counters which are not locked and
+		// have not seen a failed attempt since the given time or within the
+		// configured reset window, whichever is earlier.
+		DeleteExpiredLoginLockouts(ctx context.Context, olderThan time.Time, limit int) error
+	}
+
+	LoginLockoutPersistenceProvider interface {
+		LoginLockoutPersister() LoginLockoutPersister
+	}
+
+	loginLockoutManagerDependencies interface {
+		config.Provider
+		LoginLockoutPersistenceProvider
+		x.TracingProvider
+		x.LoggingProvider
+	}
+
+	LoginLockoutManagementProvider interface {
+		LoginLockoutManager() *LoginLockoutManager
+	}
+
+	// LoginLockoutManager protects login methods against brute-force attacks
+	// by counting failed attempts per identifier and per client IP address.
+	LoginLockoutManager struct {
+		r loginLockoutManagerDependencies
+	}
+)
+
+func NewLoginLockoutManager(r loginLockoutManagerDependencies) *LoginLockoutManager {
+	return &LoginLockoutManager{r: r}
+}
+
+// maxLockoutSubjectLength is the length of the subject column.
+const maxLockoutSubjectLength = 255
+
+func normalizeLockoutIdentifier(identifier string) string {
+	return lockoutSubject(strings.ToLower(strings.TrimSpace(identifier)))
+}
+
+// lockoutSubject replaces subjects which do not fit into the subject column
+// by their hash, so that an overlong identifier still counts as a failed
+// attempt instead of failing to store.
+func lockoutSubject(subject string) string {
+	if len(subject) <= maxLockoutSubjectLength {
+		return subject
+	}
+	h := sha256.Sum256([]byte(subject))
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+// LoginLockoutClientIP returns the client IP address failed login attempts
+// are counted for. Client IP headers are only trusted if set by a trusted
+// proxy, as otherwise clients could evade the counters or lock out others.
+func LoginLockoutClientIP(ctx context.Context, conf *config.Config, r *http.Request) string {
+	return lockoutSubject(x.TrustedClientIP(r, conf.SecurityTrustedProxies(ctx)))
+}
+
+// Check returns an error if login attempts for the identifier or from the
+// client IP address are currently locked.
+func (m *LoginLockoutManager) Check(r *http.Request, ct CredentialsType, identifier string) (err error) {
+	ctx, span := m.r.Tracer(r.Context()).Tracer().Start(r.Context(), "identity.LoginLockoutManager.Check")
+	defer otelx.End(span, &err)
+
+	conf := m.r.Config().SecurityLoginLockout(ctx)
+	if !conf.Enabled {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if l, err := m.get(ctx, LoginLockoutScopeIdentifier, normalizeLockoutIdentifier(identifier), ct); err != nil {
+		return err
+	} else if l != nil && l.IsLocked(now) {
+		return lockedOutError(l)
+	}
+
+	if conf.MaxAttemptsPerIP > 0 {
+		if l, err := m.get(ctx, LoginLockoutScopeIP, LoginLockoutClientIP(ctx, m.r.Config(), r), ""); err != nil {
+			return err
+		} else if l != nil && l.IsLocked(now) {
+			return lockedOutError(l)
+		}
+	}
+
+	return nil
+}
+
+// RecordFailure counts a failed login attempt for the identifier and the
+// client IP address. It returns the login locked error if the attempt caused
+// a lockout, and the cause annotated with the number of failed attempts
+// otherwise.
+func (m *LoginLockoutManager) RecordFailure(r *http.Request, ct CredentialsType, identifier string, identityID uuid.UUID, cause error) (err error) {
+	ctx, span := m.r.Tracer(r.Context()).Tracer().Start(r.Context(), "identity.LoginLockoutManager.RecordFailure")
+	defer otelx.End(span, &err)
+
+	conf := m.r.Config().SecurityLoginLockout(ctx)
+	if !conf.Enabled {
+		return cause
+	}
+
+	now := time.Now().UTC()
+	l, err := m.r.LoginLockoutPersister().UpdateLoginLockout(ctx, LoginLockoutScopeIdentifier, normalizeLockoutIdentifier(identifier), ct, func(l *LoginLockout) {
+		if identityID != uuid.Nil {
+			l.IdentityID = uuid.NullUUID{UUID: identityID, Valid: true}
+		}
+		registerFailedAttempt(conf, l, conf.MaxAttempts, now)
+	})
+	if err != nil {
+		return err
+	}
+	var ipl *LoginLockout
+	if conf.MaxAttemptsPerIP > 0 {
+		ipl, err = m.r.LoginLockoutPersister().UpdateLoginLockout(ctx, LoginLockoutScopeIP, LoginLockoutClientIP(ctx, m.r.Config(), r), "", func(l *LoginLockout) {
+			registerFailedAttempt(conf, l, conf.MaxAttemptsPerIP, now)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if l.IsLocked(now) {
+		m.r.Logger().
+			WithField("identity_id", identityID).
+			WithField("credentials_type", ct).
+			WithField("failed_attempts", l.FailedAttempts).
+			Info("Locked login after too many failed attempts.")
+		return lockedOutError(l)
+	} else if ipl != nil && ipl.IsLocked(now) {
+		return lockedOutError(ipl)
+	}
+
+	return x.WrapWithLockoutError(cause, string(l.Scope), l.FailedAttempts, time.Time{})
+}
+
+// RecordSuccess resets the failed attempts of the identifier. The counter of
+// the client IP address is left untouched, as otherwise an attacker could
+// reset it by signing into their own account.
+func (m *LoginLockoutManager) RecordSuccess(r *http.Request, ct CredentialsType, identifier string) (err error) {
+	ctx, span := m.r.Tracer(r.Context()).Tracer().Start(r.Context(), "identity.LoginLockoutManager.RecordSuccess")
+	defer otelx.End(span, &err)
+
+	if !m.r.Config().SecurityLoginLockout(ctx).Enabled {
+		return nil
+	}
+
+	return m.r.LoginLockoutPersister().DeleteLoginLockout(ctx, LoginLockoutScopeIdentifier, normalizeLockoutIdentifier(identifier), ct)
+}
+
+func (m *LoginLockoutManager) get(ctx context.Context, scope LoginLockoutScope, subject string, ct CredentialsType) (*LoginLockout, error) {
+	l, err := m.r.LoginLockoutPersister().GetLoginLockout(ctx, scope, subject, ct)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func registerFailedAttempt(conf *config.LoginLockout, l *LoginLockout, maxAttempts int, now time.Time) {
+	if !l.IsLocked(now) && now.Sub(l.LastFailedAt) > conf.ResetAfter {
+		l.FailedAttempts = 0
+		l.LockedUntil = sqlxx.NullTime{}
+	}
+
+	l.FailedAttempts++
+	l.LastFailedAt = now
+	if l.FailedAttempts >= maxAttempts {
+		l.LockedUntil = sqlxx.NullTime(now.Add(LoginLockoutDuration(conf, l.FailedAttempts-maxAttempts)))
+	}
+}
+
+// LoginLockoutDuration returns the lockout duration after the given number of
+// failed attempts beyond the threshold. The duration doubles with every
+// attempt and is capped at the configured maximum.
+func LoginLockoutDuration(conf *config.LoginLockout, exceeded int) time.Duration {
+	d := conf.BaseDuration
+	for range exceeded {
+		if d >= conf.MaxDuration {
+			break
+		}
+		d *= 2
+	}
+	return min(d, conf.MaxDuration)
+}
+
+func lockedOutError(l *LoginLockout) error {
+	lockedUntil := time.Time(l.LockedUntil)
+	return x.WrapWithLockoutError(schema.NewLoginLockedOutError(lockedUntil), string(l.Scope), l.FailedAttempts, lockedUntil)
+}
@@ -0,0 +1,226 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+)
+
+func TestLoginLockoutDuration(t *testing.T) {
+	conf := &config.LoginLockout{BaseDuration: time.Minute, MaxDuration: 10 * time.Minute}
+
+	for exceeded, expected := range []time.Duration{
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		8 * time.Minute,
+		10 * time.Minute,
+		10 * time.Minute,
+	} {
+		assert.Equal(t, expected, identity.LoginLockoutDuration(conf, exceeded), "exceeded=%d", exceeded)
+	}
+
+	assert.Equal(t, 10*time.Minute, identity.LoginLockoutDuration(conf, 1000))
+}
+
+func TestLoginLockoutManager(t *testing.T) {
+	ctx := t.Context()
+	conf, reg := internal.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeySecurityLoginLockoutEnabled:          true,
+		config.ViperKeySecurityLoginLockoutMaxAttempts:      3,
+		config.ViperKeySecurityLoginLockoutMaxAttemptsPerIP: 5,
+		config.ViperKeySecurityLoginLockoutBaseDuration:     "1m",
+		config.ViperKeySecurityLoginLockoutMaxDuration:      "1h",
+		config.ViperKeyDefaultIdentitySchemaID:              "default",
+	}), configx.WithValues(testhelpers.IdentitySchemasConfig(map[string]string{
+		"default": "file://./stub/identity.schema.json",
+	})))
+	m := reg.LoginLockoutManager()
+
+	newRequest := func(ip string) *http.Request {
+		r := httptest.NewRequest("POST", "/", nil).WithContext(ctx)
+		r.RemoteAddr = ip + ":1234"
+		return r
+	}
+
+	cause := errors.WithStack(schema.NewInvalidCredentialsError())
+
+	assertLocked := func(t *testing.T, err error, scope identity.LoginLockoutScope) {
+		var lockoutErr *x.WithLockoutError
+		require.ErrorAs(t, err, &lockoutErr)
+		assert.True(t, lockoutErr.Locked())
+		assert.Equal(t, string(scope), lockoutErr.Scope())
+
+		var validationErr *schema.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Messages, 1)
+		assert.Equal(t, text.ErrorValidationLoginLockedOut, validationErr.Messages[0].ID)
+	}
+
+	t.Run("case=locks the identifier after too many failed attempts", func(t *testing.T) {
+		r := newRequest("10.0.0.1")
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		id := i.ID
+
+		for attempt := 1; attempt < 3; attempt++ {
+			require.NoError(t, m.Check(r, identity.CredentialsTypePassword, "locked@ory.sh"))
+
+			err := m.RecordFailure(r, identity.CredentialsTypePassword, "locked@ory.sh", id, cause)
+			assert.ErrorIs(t, err, cause)
+
+			var lockoutErr *x.WithLockoutError
+			require.ErrorAs(t, err, &lockoutErr)
+			assert.False(t, lockoutErr.Locked())
+			assert.Equal(t, attempt, lockoutErr.FailedAttempts())
+		}
+
+		assertLocked(t, m.RecordFailure(r, identity.CredentialsTypePassword, "locked@ory.sh", id, cause), identity.LoginLockoutScopeIdentifier)
+
+		t.Run("case=identifier is normalized", func(t *testing.T) {
+			assertLocked(t, m.Check(r, identity.CredentialsTypePassword, " Locked@Ory.sh "), identity.LoginLockoutScopeIdentifier)
+		})
+
+		t.Run("case=other credentials types are not locked", func(t *testing.T) {
+			require.NoError(t, m.Check(r, identity.CredentialsTypeCodeAuth, "locked@ory.sh"))
+		})
+
+		t.Run("case=counter is bound to the identity", func(t *testing.T) {
+			lockouts, err := reg.LoginLockoutPersister().ListLoginLockoutsByIdentity(ctx, id)
+			require.NoError(t, err)
+			require.Len(t, lockouts, 1)
+			assert.Equal(t, 3, lockouts[0].FailedAttempts)
+			assert.True(t, lockouts[0].IsLocked(time.Now()))
+			assert.WithinDuration(t, time.Now().Add(time.Minute), time.Time(lockouts[0].LockedUntil), 5*time.Second)
+		})
+	})
+
+	t.Run("case=backs off exponentially", func(t *testing.T) {
+		r := newRequest("10.0.0.2")
+
+		_, err := reg.LoginLockoutPersister().UpdateLoginLockout(ctx, identity.LoginLockoutScopeIdentifier, "backoff@ory.sh", identity.CredentialsTypePassword, func(l *identity.LoginLockout) {
+			l.FailedAttempts = 4
+			l.LastFailedAt = time.Now().UTC()
+		})
+		require.NoError(t, err)
+
+		assertLocked(t, m.RecordFailure(r, identity.CredentialsTypePassword, "backoff@ory.sh", uuid.Nil, cause), identity.LoginLockoutScopeIdentifier)
+
+		l, err := reg.LoginLockoutPersister().GetLoginLockout(ctx, identity.LoginLockoutScopeIdentifier, "backoff@ory.sh", identity.CredentialsTypePassword)
+		require.NoError(t, err)
+		assert.Equal(t, 5, l.FailedAttempts)
+		assert.WithinDuration(t, time.Now().Add(4*time.Minute), time.Time(l.LockedUntil), 5*time.Second)
+	})
+
+	t.Run("case=resets stale failed attempts", func(t *testing.T) {
+		r := newRequest("10.0.0.3")
+
+		_, err := reg.LoginLockoutPersister().UpdateLoginLockout(ctx, identity.LoginLockoutScopeIdentifier, "stale@ory.sh", identity.CredentialsTypePassword, func(l *identity.LoginLockout) {
+			l.FailedAttempts = 2
+			l.LastFailedAt = time.Now().UTC().Add(-2 * time.Hour)
+		})
+		require.NoError(t, err)
+
+		var lockoutErr *x.WithLockoutError
+		require.ErrorAs(t, m.RecordFailure(r, identity.CredentialsTypePassword, "stale@ory.sh", uuid.Nil, cause), &lockoutErr)
+		assert.False(t, lockoutErr.Locked())
+		assert.Equal(t, 1, lockoutErr.FailedAttempts())
+	})
+
+	t.Run("case=success resets the identifier", func(t *testing.T) {
+		r := newRequest("10.0.0.4")
+
+		require.Error(t, m.RecordFailure(r, identity.CredentialsTypePassword, "success@ory.sh", uuid.Nil, cause))
+		require.NoError(t, m.RecordSuccess(r, identity.CredentialsTypePassword, "success@ory.sh"))
+
+		_, err := reg.LoginLockoutPersister().GetLoginLockout(ctx, identity.LoginLockoutScopeIdentifier, "success@ory.sh", identity.CredentialsTypePassword)
+		assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+	})
+
+	t.Run("case=locks the client IP address", func(t *testing.T) {
+		r := newRequest("10.0.0.5")
+
+		for k := range 4 {
+			require.NoError(t, m.Check(r, identity.CredentialsTypePassword, x.NewUUID().String()))
+			assert.ErrorIs(t, m.RecordFailure(r, identity.CredentialsTypePassword, x.NewUUID().String(), uuid.Nil, cause), cause, "%d", k)
+		}
+
+		assertLocked(t, m.RecordFailure(r, identity.CredentialsTypePassword, x.NewUUID().String(), uuid.Nil, cause), identity.LoginLockoutScopeIP)
+		assertLocked(t, m.Check(r, identity.CredentialsTypeTOTP, x.NewUUID().String()), identity.LoginLockoutScopeIP)
+		require.NoError(t, m.Check(newRequest("10.0.0.6"), identity.CredentialsTypePassword, x.NewUUID().String()))
+	})
+
+	t.Run("case=ignores client IP headers unless set by a trusted proxy", func(t *testing.T) {
+		spoofed := func(forwardedFor string) *http.Request {
+			r := newRequest("10.0.1.5")
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+			r.Header.Set("X-Real-Ip", forwardedFor)
+			r.Header.Set("True-Client-Ip", forwardedFor)
+			return r
+		}
+
+		for k := range 4 {
+			assert.ErrorIs(t, m.RecordFailure(spoofed(fmt.Sprintf("192.0.2.%d", k)), identity.CredentialsTypePassword, x.NewUUID().String(), uuid.Nil, cause), cause, "%d", k)
+		}
+		assertLocked(t, m.RecordFailure(spoofed("192.0.2.10"), identity.CredentialsTypePassword, x.NewUUID().String(), uuid.Nil, cause), identity.LoginLockoutScopeIP)
+		require.NoError(t, m.Check(newRequest("192.0.2.10"), identity.CredentialsTypePassword, x.NewUUID().String()))
+
+		conf.MustSet(ctx, config.ViperKeySecurityTrustedProxies, []string{"10.0.2.0/24"})
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySecurityTrustedProxies, []string{}) })
+
+		proxied := newRequest("10.0.2.1")
+		proxied.Header.Set("X-Forwarded-For", "192.0.2.20")
+		for range 5 {
+			require.Error(t, m.RecordFailure(proxied, identity.CredentialsTypePassword, x.NewUUID().String(), uuid.Nil, cause))
+		}
+		assertLocked(t, m.Check(newRequest("192.0.2.20"), identity.CredentialsTypePassword, x.NewUUID().String()), identity.LoginLockoutScopeIP)
+		require.NoError(t, m.Check(newRequest("10.0.2.1"), identity.CredentialsTypePassword, x.NewUUID().String()))
+	})
+
+	t.Run("case=counts overlong identifiers", func(t *testing.T) {
+		identifier := strings.Repeat("a", 300) + "@ory.sh"
+		r := newRequest("10.0.0.8")
+
+		for range 2 {
+			assert.ErrorIs(t, m.RecordFailure(r, identity.CredentialsTypePassword, identifier, uuid.Nil, cause), cause)
+		}
+		assertLocked(t, m.RecordFailure(r, identity.CredentialsTypePassword, identifier, uuid.Nil, cause), identity.LoginLockoutScopeIdentifier)
+		assertLocked(t, m.Check(r, identity.CredentialsTypePassword, identifier), identity.LoginLockoutScopeIdentifier)
+	})
+
+	t.Run("case=does nothing if disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySecurityLoginLockoutEnabled, false)
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySecurityLoginLockoutEnabled, true) })
+
+		r := newRequest("10.0.0.7")
+		for range 5 {
+			assert.Equal(t, cause, m.RecordFailure(r, identity.CredentialsTypePassword, "disabled@ory.sh", uuid.Nil, cause))
+		}
+		require.NoError(t, m.Check(r, identity.CredentialsTypePassword, "disabled@ory.sh"))
+
+		_, err := reg.LoginLockoutPersister().GetLoginLockout(ctx, identity.LoginLockoutScopeIdentifier, "disabled@ory.sh", identity.CredentialsTypePassword)
+		assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/jsonnetsecure"
+)
+
+var jsonnetCache, _ = ristretto.NewCache(&ristretto.Config[[]byte, []byte]{
+	MaxCost:     100 << 20, // 100MB,
+	NumCounters: 1_000_000, // 1kB per snippet -> 100k snippets -> 1M counters
+	BufferItems: 64,
+})
+
+type (
+	jsonnetMapperDependencies interface {
+		x.HTTPClientProvider
+		jsonnetsecure.VMProvider
+	}
+
+	// JsonnetMapper maps data from an external source, such as a SCIM client
+	// or an LDAP directory, onto an identity.
+	//
+	// The mapper receives the data as `std.extVar(Var)` and returns the
+	// identity's traits and, optionally, its public and admin metadata, using
+	// the same format as the OpenID Connect mapper.
+	JsonnetMapper struct {
+		// Name names the mapper in error messages, e.g. "SCIM".
+		Name string
+
+		// Method is the self-service method reported when the mapping fails.
+		Method string
+
+		// URL is the location of the Jsonnet snippet.
+		URL string
+
+		// Var is the name of the external variable holding the data.
+		Var string
+
+		// Subject describes the data in error messages, e.g. "user".
+		Subject string
+
+		// EvaluationError is returned if the snippet fails to evaluate.
+		// Defaults to an internal server error.
+		EvaluationError *herodot.DefaultError
+	}
+)
+
+// Map evaluates the Jsonnet snippet with the given data and applies the traits
+// and metadata it returns to the identity. It returns the output of the
+// snippet.
+func (m *JsonnetMapper) Map(ctx context.Context, d jsonnetMapperDependencies, data []byte, i *Identity) (evaluated string, err error) {
+	defer func() {
+		if err != nil {
+			events.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(ctx, err, data, evaluated, "", m.Method))
+		}
+	}()
+
+	fetch := fetcher.NewFetcher(fetcher.WithClient(d.HTTPClient(ctx)), fetcher.WithCache(jsonnetCache, 60*time.Minute))
+	jsonnetSnippet, err := fetch.FetchContext(ctx, m.URL)
+	if err != nil {
+		return "", errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to fetch the %s Jsonnet mapper: %s", m.Name, err))
+	}
+
+	vm, err := d.JsonnetVM(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	vm.ExtCode(m.Var, string(data))
+	evaluated, err = vm.EvaluateAnonymousSnippet(m.URL, jsonnetSnippet.String())
+	if err != nil {
+		evalErr := &herodot.ErrInternalServerError
+		if m.EvaluationError != nil {
+			evalErr = m.EvaluationError
+		}
+		return "", errors.WithStack(evalErr.WithReasonf("The %s Jsonnet mapper failed to map the %s: %s", m.Name, m.Subject, err))
+	}
+
+	traits := gjson.Get(evaluated, "identity.traits")
+	if !traits.IsObject() {
+		return evaluated, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("%s Jsonnet mapper did not return an object for key identity.traits. Please check your Jsonnet code!", m.Name))
+	}
+	i.Traits = Traits(traits.Raw)
+
+	if metadata := gjson.Get(evaluated, "identity.metadata_public"); metadata.Exists() {
+		if !metadata.IsObject() {
+			return evaluated, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("%s Jsonnet mapper did not return an object for key identity.metadata_public. Please check your Jsonnet code!", m.Name))
+		}
+		i.MetadataPublic = []byte(metadata.Raw)
+	}
+
+	if metadata := gjson.Get(evaluated, "identity.metadata_admin"); metadata.Exists() {
+		if !metadata.IsObject() {
+			return evaluated, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("%s Jsonnet mapper did not return an object for key identity.metadata_admin. Please check your Jsonnet code!", m.Name))
+		}
+		i.MetadataAdmin = []byte(metadata.Raw)
+	}
+
+	return evaluated, nil
+}
@@ -5,6 +5,7 @@ package identity
 
 import (
 	"context"
+	"time"
 
 	"github.com/ory/kratos/x"
 	"github.com/ory/x/crdbx"
@@ -47,6 +48,7 @@ type (
 		DeclassifyCredentials        []CredentialsType
 		KeySetPagination             []keysetpagination.Option
 		OrganizationID               uuid.UUID
+		Filters                      []ListIdentityFilter
 		ConsistencyLevel             crdbx.ConsistencyLevel
 		StatementTransformer         func(string) string
 
@@ -90,6 +92,7 @@ type (
 
 	PrivilegedPool interface {
 		Pool
+		VersionPool
 
 		// FindByCredentialsIdentifier returns an identity by querying for it's credential identifiers.
 		FindByCredentialsIdentifier(ctx context.Context, ct CredentialsType, match string) (*Identity, *Credentials, error)
@@ -102,6 +105,19 @@ type (
 		// if any identity does not exists, or backend connectivity is broken.
 		DeleteIdentities(context.Context, []uuid.UUID) error
 
+		// SoftDeleteIdentity moves an identity to the deleted state and revokes
+		// all of its sessions. Will return an error if the identity does not
+		// exist.
+		SoftDeleteIdentity(context.Context, uuid.UUID) error
+
+		// UndeleteIdentity restores the state a soft-deleted identity had before
+		// it was deleted.
+		UndeleteIdentity(context.Context, uuid.UUID) error
+
+		// PurgeDeletedIdentities removes up to limit identities which were
+		// soft-deleted before olderThan and before the configured retention.
+		PurgeDeletedIdentities(ctx context.Context, olderThan time.Time, limit int) error
+
 		// UpdateVerifiableAddress updates an identity's verifiable address.
 		UpdateVerifiableAddress(ctx context.Context, address *VerifiableAddress, updateColumns ...string) error
 
@@ -27,6 +27,7 @@ import (
 	"github.com/ory/kratos/persistence"
 	idpersistence "github.com/ory/kratos/persistence/sql/identity"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/x"
 	"github.com/ory/x/assertx"
 	"github.com/ory/x/contextx"
@@ -669,6 +670,219 @@ func TestPool(ctx context.Context, p persistence.Persister, m *identity.Manager,
 			assert.Equal(t, identity.StateActive, actual.State, "the state remains unchanged")
 		})
 
+		t.Run("suite=versions", func(t *testing.T) {
+			i := passwordIdentity("", x.NewUUID().String())
+			i.Traits = identity.Traits(`{"email":"versions@ory.sh"}`)
+			require.NoError(t, p.CreateIdentity(ctx, i))
+			createdIDs = append(createdIDs, i.ID)
+
+			i.Traits = identity.Traits(`{"email":"versions-updated@ory.sh"}`)
+			i.MetadataPublic = sqlxx.NullJSONRawMessage(`{"plan":"free"}`)
+			i.SetCredentials(identity.CredentialsTypeOIDC, identity.Credentials{
+				Type: identity.CredentialsTypeOIDC, Identifiers: []string{x.NewUUID().String()},
+				Config: sqlxx.JSONRawMessage(`{}`),
+			})
+			require.NoError(t, p.UpdateIdentity(ctx, i))
+
+			t.Run("case=lists versions newest first", func(t *testing.T) {
+				versions, _, err := p.ListIdentityVersions(ctx, i.ID, nil)
+				require.NoError(t, err)
+				require.Len(t, versions, 2)
+
+				assert.Equal(t, 2, versions[0].Version)
+				assert.JSONEq(t, `{"email":"versions-updated@ory.sh"}`, string(versions[0].Traits))
+				assert.JSONEq(t, `{"plan":"free"}`, string(versions[0].MetadataPublic))
+				assert.EqualValues(t, []string{"oidc", "password"}, versions[0].CredentialTypes)
+
+				assert.Equal(t, 1, versions[1].Version)
+				assert.JSONEq(t, `{"email":"versions@ory.sh"}`, string(versions[1].Traits))
+				assert.EqualValues(t, []string{"password"}, versions[1].CredentialTypes)
+				assert.Equal(t, i.ID.String(), versions[1].Actor)
+			})
+
+			t.Run("case=does not contain secrets", func(t *testing.T) {
+				v, err := p.GetIdentityVersion(ctx, i.ID, 2)
+				require.NoError(t, err)
+
+				raw, err := json.Marshal(v)
+				require.NoError(t, err)
+				assert.NotContains(t, string(raw), `"foo":"bar"`)
+			})
+
+			t.Run("case=get version", func(t *testing.T) {
+				v, err := p.GetIdentityVersion(ctx, i.ID, 1)
+				require.NoError(t, err)
+				assert.Equal(t, i.ID, v.IdentityID)
+				assert.Equal(t, identity.StateActive, v.State)
+				assert.Equal(t, config.DefaultIdentityTraitsSchemaID, v.SchemaID)
+
+				_, err = p.GetIdentityVersion(ctx, i.ID, 3)
+				assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+			})
+
+			t.Run("case=not if on another network", func(t *testing.T) {
+				_, on := testhelpers.NewNetwork(t, ctx, p)
+				versions, _, err := on.ListIdentityVersions(ctx, i.ID, nil)
+				require.NoError(t, err)
+				assert.Empty(t, versions)
+
+				_, err = on.GetIdentityVersion(ctx, i.ID, 1)
+				assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+			})
+
+			t.Run("case=versions are deleted with the identity", func(t *testing.T) {
+				other := passwordIdentity("", x.NewUUID().String())
+				require.NoError(t, p.CreateIdentity(ctx, other))
+				require.NoError(t, p.DeleteIdentity(ctx, other.ID))
+
+				versions, _, err := p.ListIdentityVersions(ctx, other.ID, nil)
+				require.NoError(t, err)
+				assert.Empty(t, versions)
+			})
+		})
+
+		t.Run("suite=soft delete", func(t *testing.T) {
+			email := x.NewUUID().String()
+			i := passwordIdentity("", email)
+			i.State = identity.StateInactive
+			i.VerifiableAddresses = []identity.VerifiableAddress{{Value: email, Via: identity.AddressTypeEmail, Status: identity.VerifiableAddressStatusPending}}
+			i.RecoveryAddresses = []identity.RecoveryAddress{{Value: email, Via: identity.AddressTypeEmail}}
+			require.NoError(t, p.CreateIdentity(ctx, i))
+			createdIDs = append(createdIDs, i.ID)
+
+			count, err := p.CountIdentities(ctx)
+			require.NoError(t, err)
+
+			s := &session.Session{
+				ID:              x.NewUUID(),
+				Token:           x.NewUUID().String(),
+				Active:          true,
+				Identity:        i,
+				IdentityID:      i.ID,
+				ExpiresAt:       time.Now().Add(time.Hour).UTC(),
+				AuthenticatedAt: time.Now().UTC(),
+				IssuedAt:        time.Now().UTC(),
+			}
+			require.NoError(t, p.UpsertSession(ctx, s))
+
+			require.NoError(t, p.SoftDeleteIdentity(ctx, i.ID))
+
+			t.Run("case=identity is deleted", func(t *testing.T) {
+				actual, err := p.GetIdentity(ctx, i.ID, identity.ExpandNothing)
+				require.NoError(t, err)
+				assert.Equal(t, identity.StateDeleted, actual.State)
+				assert.False(t, actual.IsActive())
+			})
+
+			t.Run("case=deleting again is a no-op", func(t *testing.T) {
+				before, err := p.GetIdentity(ctx, i.ID, identity.ExpandNothing)
+				require.NoError(t, err)
+
+				require.NoError(t, p.SoftDeleteIdentity(ctx, i.ID))
+
+				after, err := p.GetIdentity(ctx, i.ID, identity.ExpandNothing)
+				require.NoError(t, err)
+				assert.Equal(t, before.StateChangedAt, after.StateChangedAt)
+			})
+
+			t.Run("case=sessions are revoked", func(t *testing.T) {
+				actual, err := p.GetSession(ctx, s.ID, session.ExpandNothing)
+				require.NoError(t, err)
+				assert.False(t, actual.Active)
+			})
+
+			t.Run("case=can not sign in", func(t *testing.T) {
+				_, _, err := p.FindByCredentialsIdentifier(ctx, identity.CredentialsTypePassword, email)
+				assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+				_, err = p.FindIdentityByCredentialIdentifier(ctx, email, false, identity.ExpandNothing)
+				assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+			})
+
+			t.Run("case=is not counted", func(t *testing.T) {
+				actual, err := p.CountIdentities(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, count-1, actual)
+			})
+
+			t.Run("case=addresses are not found", func(t *testing.T) {
+				_, err := p.FindVerifiableAddressByValue(ctx, identity.AddressTypeEmail, email)
+				assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+				_, err = p.FindRecoveryAddressByValue(ctx, identity.AddressTypeEmail, email)
+				assert.ErrorIs(t, err, sqlcon.ErrNoRows)
+
+				actual, err := p.FindAllRecoveryAddressesForIdentityByRecoveryAddressValue(ctx, email)
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+			})
+
+			t.Run("case=is only listed when filtering by state", func(t *testing.T) {
+				actual, _, err := p.ListIdentities(ctx, identity.ListIdentityParameters{IdsFilter: []uuid.UUID{i.ID}})
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+
+				f, err := identity.ParseListIdentityFilter("state=deleted")
+				require.NoError(t, err)
+				actual, _, err = p.ListIdentities(ctx, identity.ListIdentityParameters{IdsFilter: []uuid.UUID{i.ID}, Filters: []identity.ListIdentityFilter{f}})
+				require.NoError(t, err)
+				require.Len(t, actual, 1)
+				assert.Equal(t, i.ID, actual[0].ID)
+			})
+
+			t.Run("case=not if on another network", func(t *testing.T) {
+				_, on := testhelpers.NewNetwork(t, ctx, p)
+				require.ErrorIs(t, on.SoftDeleteIdentity(ctx, i.ID), sqlcon.ErrNoRows)
+				require.ErrorIs(t, on.UndeleteIdentity(ctx, i.ID), sqlcon.ErrNoRows)
+			})
+
+			t.Run("case=undelete restores the previous state", func(t *testing.T) {
+				require.NoError(t, p.UndeleteIdentity(ctx, i.ID))
+
+				actual, err := p.GetIdentity(ctx, i.ID, identity.ExpandNothing)
+				require.NoError(t, err)
+				assert.Equal(t, identity.StateInactive, actual.State)
+
+				versions, _, err := p.ListIdentityVersions(ctx, i.ID, nil)
+				require.NoError(t, err)
+				require.Len(t, versions, 3)
+				assert.Equal(t, identity.StateInactive, versions[0].State)
+				assert.Equal(t, identity.StateDeleted, versions[1].State)
+
+				_, _, err = p.FindByCredentialsIdentifier(ctx, identity.CredentialsTypePassword, email)
+				require.NoError(t, err)
+				_, err = p.FindRecoveryAddressByValue(ctx, identity.AddressTypeEmail, email)
+				require.NoError(t, err)
+
+				n, err := p.CountIdentities(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, count, n)
+
+				err = p.UndeleteIdentity(ctx, i.ID)
+				require.ErrorIs(t, err, herodot.ErrConflict)
+			})
+
+			t.Run("case=purge only removes deleted identities", func(t *testing.T) {
+				other := passwordIdentity("", x.NewUUID().String())
+				require.NoError(t, p.CreateIdentity(ctx, other))
+				require.NoError(t, p.SoftDeleteIdentity(ctx, other.ID))
+
+				// Purging respects the retention, so the identity must have been
+				// deleted long ago.
+				deletedAt := sqlxx.NullTime(time.Now().Add(-10 * 365 * 24 * time.Hour).UTC())
+				other.State = identity.StateDeleted
+				other.StateChangedAt = &deletedAt
+				require.NoError(t, p.UpdateIdentityColumns(ctx, other, "state_changed_at"))
+
+				require.NoError(t, p.PurgeDeletedIdentities(ctx, time.Now(), 100))
+
+				_, err := p.GetIdentity(ctx, other.ID, identity.ExpandNothing)
+				require.ErrorIs(t, err, sqlcon.ErrNoRows)
+				_, err = p.GetIdentity(ctx, i.ID, identity.ExpandNothing)
+				require.NoError(t, err)
+			})
+		})
+
 		t.Run("case=should fail to insert identity because credentials from traits exist", func(t *testing.T) {
 			email := randx.MustString(16, randx.AlphaLowerNum) + "@ory.sh"
 			first := passwordIdentity("", email)
@@ -951,6 +1165,119 @@ func TestPool(ctx context.Context, p persistence.Persister, m *identity.Manager,
 			})
 		})
 
+		t.Run("case=list identities by filter expressions", func(t *testing.T) {
+			nid, p := testhelpers.NewNetwork(t, ctx, p)
+
+			create := func(schemaID string, state identity.State, traits, metadataPublic, metadataAdmin string, createdAt time.Time) *identity.Identity {
+				i := identity.NewIdentity(schemaID)
+				i.State = state
+				i.Traits = identity.Traits(traits)
+				i.MetadataPublic = sqlxx.NullJSONRawMessage(metadataPublic)
+				i.MetadataAdmin = sqlxx.NullJSONRawMessage(metadataAdmin)
+				require.NoError(t, p.CreateIdentity(ctx, i))
+				require.NoError(t, p.GetConnection(ctx).
+					RawQuery("UPDATE identities SET created_at = ? WHERE id = ? AND nid = ?", createdAt, i.ID, nid).
+					Exec())
+				return i
+			}
+
+			now := time.Now().UTC().Truncate(time.Second)
+			alice := create(config.DefaultIdentityTraitsSchemaID, identity.StateActive,
+				`{"email":"alice@ory.sh","tier":2,"verified":true,"emails":["alice@ory.sh","alice@example.org"],"address":{"country":"DE"}}`,
+				`{"plan":"pro"}`, `{"support":"vip"}`, now.Add(-48*time.Hour))
+			bob := create(config.DefaultIdentityTraitsSchemaID, identity.StateInactive,
+				`{"email":"bob@ory.sh","tier":1,"verified":false,"address":{"country":"US"}}`,
+				`{"plan":"free"}`, `null`, now.Add(-24*time.Hour))
+			carol := create(altSchema.ID, identity.StateActive,
+				`{"email":"carol@ory.sh","tier":1,"address":{"country":"DE"}}`,
+				`null`, `{"support":"standard"}`, now)
+
+			list := func(t *testing.T, expressions ...string) []uuid.UUID {
+				params := identity.ListIdentityParameters{Expand: identity.ExpandNothing}
+				for _, e := range expressions {
+					f, err := identity.ParseListIdentityFilter(e)
+					require.NoError(t, err)
+					params.Filters = append(params.Filters, f)
+				}
+
+				actual, next, err := p.ListIdentities(ctx, params)
+				require.NoError(t, err)
+				assert.True(t, next.IsLast())
+
+				ids := make([]uuid.UUID, len(actual))
+				for k := range actual {
+					ids[k] = actual[k].ID
+				}
+				return ids
+			}
+
+			for _, tc := range []struct {
+				expressions []string
+				expected    []*identity.Identity
+			}{
+				{expressions: []string{"traits.email=alice@ory.sh"}, expected: []*identity.Identity{alice}},
+				{expressions: []string{"traits.email=ALICE@ory.sh"}},
+				{expressions: []string{"traits.email!=alice@ory.sh"}, expected: []*identity.Identity{bob, carol}},
+				{expressions: []string{"traits.tier=1"}, expected: []*identity.Identity{bob, carol}},
+				{expressions: []string{"traits.verified=true"}, expected: []*identity.Identity{alice}},
+				{expressions: []string{"traits.verified!=true"}, expected: []*identity.Identity{bob}},
+				{expressions: []string{"traits.emails.1=alice@example.org"}, expected: []*identity.Identity{alice}},
+				{expressions: []string{"traits.address.country=DE"}, expected: []*identity.Identity{alice, carol}},
+				{expressions: []string{"traits.address.country=DE", "traits.tier=1"}, expected: []*identity.Identity{carol}},
+				{expressions: []string{"traits.unknown=DE"}},
+				{expressions: []string{"metadata_public.plan=pro"}, expected: []*identity.Identity{alice}},
+				{expressions: []string{"metadata_admin.support=standard"}, expected: []*identity.Identity{carol}},
+				{expressions: []string{"state=inactive"}, expected: []*identity.Identity{bob}},
+				{expressions: []string{"state!=inactive"}, expected: []*identity.Identity{alice, carol}},
+				{expressions: []string{"schema_id=" + altSchema.ID}, expected: []*identity.Identity{carol}},
+				{expressions: []string{"created_at<" + now.Add(-time.Hour).Format(time.RFC3339)}, expected: []*identity.Identity{alice, bob}},
+				{expressions: []string{"created_at>=" + now.Add(-24*time.Hour).Format(time.RFC3339), "state=active"}, expected: []*identity.Identity{carol}},
+				{expressions: []string{"updated_at>" + now.Add(time.Hour).Format(time.RFC3339)}},
+			} {
+				t.Run("filter="+strings.Join(tc.expressions, "&"), func(t *testing.T) {
+					expected := make([]uuid.UUID, len(tc.expected))
+					for k, i := range tc.expected {
+						expected[k] = i.ID
+					}
+					assert.ElementsMatch(t, expected, list(t, tc.expressions...))
+				})
+			}
+
+			t.Run("case=preserves keyset pagination", func(t *testing.T) {
+				f, err := identity.ParseListIdentityFilter("traits.address.country=DE")
+				require.NoError(t, err)
+
+				var ids []uuid.UUID
+				opts := []keysetpagination.Option{keysetpagination.WithSize(1)}
+				for range 3 {
+					actual, next, err := p.ListIdentities(ctx, identity.ListIdentityParameters{
+						Expand:           identity.ExpandNothing,
+						Filters:          []identity.ListIdentityFilter{f},
+						KeySetPagination: opts,
+					})
+					require.NoError(t, err)
+					for _, i := range actual {
+						ids = append(ids, i.ID)
+					}
+					if next.IsLast() {
+						break
+					}
+					opts = next.ToOptions()
+				}
+				assert.ElementsMatch(t, []uuid.UUID{alice.ID, carol.ID}, ids)
+			})
+
+			t.Run("case=not if on another network", func(t *testing.T) {
+				_, on := testhelpers.NewNetwork(t, ctx, p)
+				f, err := identity.ParseListIdentityFilter("traits.email=alice@ory.sh")
+				require.NoError(t, err)
+
+				actual, _, err := on.ListIdentities(ctx, identity.ListIdentityParameters{Filters: []identity.ListIdentityFilter{f}})
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+			})
+		})
+
 		t.Run("case=find identity by its credentials type and identifier", func(t *testing.T) {
 			email := randx.MustString(16, randx.AlphaLowerNum) + "@ory.sh"
 			expected := passwordIdentity("", email)
@@ -0,0 +1,298 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	otelattr "go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/otelx/semconv"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/sqlxx"
+)
+
+// VersionPool reads the versions of identities. Versions are written by the
+// privileged pool whenever an identity is created or updated.
+type VersionPool interface {
+	// ListIdentityVersions lists the versions of an identity, newest first.
+	ListIdentityVersions(ctx context.Context, identityID uuid.UUID, opts []keysetpagination.Option) ([]Version, *keysetpagination.Paginator, error)
+
+	// GetIdentityVersion returns a version of an identity.
+	GetIdentityVersion(ctx context.Context, identityID uuid.UUID, version int) (*Version, error)
+}
+
+// An Identity Version
+//
+// A version is a snapshot of an identity which is written every time the
+// identity is created or updated. It contains the identity's traits, metadata,
+// state, and schema, and the types of its credentials, but no secrets.
+//
+// swagger:model identityVersion
+type Version struct {
+	// The version's unique ID.
+	//
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	NID uuid.UUID `json:"-" faker:"-" db:"nid"`
+
+	// The ID of the identity.
+	//
+	// required: true
+	IdentityID uuid.UUID `json:"identity_id" faker:"-" db:"identity_id"`
+
+	// The version number, starting with 1 when the identity is created.
+	//
+	// required: true
+	Version int `json:"version" db:"version"`
+
+	// The identity's schema ID at this version.
+	//
+	// required: true
+	SchemaID string `json:"schema_id" db:"schema_id"`
+
+	// The identity's state at this version.
+	//
+	// required: true
+	State State `json:"state" db:"state"`
+
+	// The identity's traits at this version.
+	//
+	// required: true
+	Traits Traits `json:"traits" faker:"-" db:"traits"`
+
+	// The identity's public metadata at this version.
+	MetadataPublic sqlxx.NullJSONRawMessage `json:"metadata_public" faker:"-" db:"metadata_public"`
+
+	// The identity's admin metadata at this version.
+	MetadataAdmin sqlxx.NullJSONRawMessage `json:"metadata_admin" faker:"-" db:"metadata_admin"`
+
+	// The types of the identity's credentials at this version, for example
+	// `password` or `totp`.
+	//
+	// required: true
+	CredentialTypes sqlxx.StringSliceJSONFormat `json:"credential_types" faker:"-" db:"credential_types"`
+
+	// Who wrote this version. This is `admin` for changes through the admin
+	// API, `cli` for changes through the command line interface, the
+	// administrator impersonating the identity, or the ID of the identity for
+	// self-service changes.
+	//
+	// required: true
+	Actor string `json:"actor" db:"actor"`
+
+	// When this version was written.
+	//
+	// required: true
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+}
+
+func (v Version) TableName() string { return "identity_versions" }
+
+func (v Version) PageToken() keysetpagination.PageToken {
+	return keysetpagination.NewPageToken(keysetpagination.Column{
+		Name:  "version",
+		Order: keysetpagination.OrderDescending,
+		Value: v.Version,
+	})
+}
+
+func (v Version) DefaultPageToken() keysetpagination.PageToken {
+	return Version{Version: math.MaxInt32}.PageToken()
+}
+
+// NewVersion returns a snapshot of the identity with the given version number.
+// The actor is taken from the context, and defaults to the identity itself.
+func NewVersion(ctx context.Context, i *Identity, version int) *Version {
+	v := &Version{
+		ID:              x.NewUUID(),
+		NID:             i.NID,
+		IdentityID:      i.ID,
+		Version:         version,
+		SchemaID:        i.SchemaID,
+		State:           i.State,
+		Traits:          i.Traits,
+		MetadataPublic:  i.MetadataPublic,
+		MetadataAdmin:   i.MetadataAdmin,
+		CredentialTypes: make(sqlxx.StringSliceJSONFormat, 0, len(i.Credentials)),
+		Actor:           i.ID.String(),
+		CreatedAt:       time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if len(v.Traits) == 0 {
+		v.Traits = Traits("{}")
+	}
+	for ct := range i.Credentials {
+		v.CredentialTypes = append(v.CredentialTypes, string(ct))
+	}
+	slices.Sort(v.CredentialTypes)
+
+	for _, attr := range semconv.AttributesFromContext(ctx) {
+		if attr.Key == otelattr.Key(events.AttributeKeyActor) && attr.Value.AsString() != "" {
+			v.Actor = attr.Value.AsString()
+		}
+	}
+
+	return v
+}
+
+// An Identity Version Change
+//
+// swagger:model identityVersionChange
+type VersionChange struct {
+	// The path of the changed value, for example `state`, `traits.email`, or
+	// `credential_types`.
+	//
+	// required: true
+	Path string `json:"path"`
+
+	// The value before the change. Not set if the value was added.
+	From json.RawMessage `json:"from,omitempty"`
+
+	// The value after the change. Not set if the value was removed.
+	To json.RawMessage `json:"to,omitempty"`
+}
+
+// An Identity Version Diff
+//
+// swagger:model identityVersionDiff
+type VersionDiff struct {
+	// The version the changes are relative to.
+	//
+	// required: true
+	From int `json:"from"`
+
+	// The version the changes lead to.
+	//
+	// required: true
+	To int `json:"to"`
+
+	// The changes between both versions, ordered by their path.
+	//
+	// required: true
+	Changes []VersionChange `json:"changes"`
+}
+
+// DiffVersions returns the changes between two versions of an identity.
+// Objects are compared key by key, all other values, including arrays, as a
+// whole.
+func DiffVersions(from, to *Version) (*VersionDiff, error) {
+	fromDoc, err := from.document()
+	if err != nil {
+		return nil, err
+	}
+	toDoc, err := to.document()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &VersionDiff{From: from.Version, To: to.Version, Changes: []VersionChange{}}
+	if err := diffValues(&d.Changes, "", fromDoc, toDoc); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// document returns the fields of the version which are compared by
+// DiffVersions.
+func (v *Version) document() (map[string]any, error) {
+	doc := map[string]any{}
+	if v.SchemaID != "" {
+		doc["schema_id"] = v.SchemaID
+	}
+	if v.State != "" {
+		doc["state"] = string(v.State)
+	}
+	if len(v.CredentialTypes) > 0 {
+		doc["credential_types"] = []string(v.CredentialTypes)
+	}
+	for key, raw := range map[string][]byte{
+		"traits":          v.Traits,
+		"metadata_public": v.MetadataPublic,
+		"metadata_admin":  v.MetadataAdmin,
+	} {
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		doc[key] = value
+	}
+	return doc, nil
+}
+
+func diffValues(changes *[]VersionChange, path string, from, to any) error {
+	fromObject, fromIsObject := from.(map[string]any)
+	toObject, toIsObject := to.(map[string]any)
+	if fromIsObject && toIsObject {
+		keys := slices.Sorted(maps.Keys(fromObject))
+		for key := range toObject {
+			if _, ok := fromObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			if err := diffValues(changes, child, fromObject[key], toObject[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	change := VersionChange{Path: path}
+	if from != nil {
+		raw, err := json.Marshal(from)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		change.From = raw
+	}
+	if to != nil {
+		raw, err := json.Marshal(to)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		change.To = raw
+	}
+	*changes = append(*changes, change)
+	return nil
+}
+
+// Restore applies the traits, metadata, state, and schema of the version to
+// the identity. Credentials are not restored, because versions do not contain
+// secrets. The deleted state is neither restored nor replaced, as soft
+// deletion revokes sessions and is undone by undeleting the identity.
+func (v *Version) Restore(i *Identity) {
+	if i.State != v.State && i.State != StateDeleted && v.State != StateDeleted {
+		stateChangedAt := sqlxx.NullTime(time.Now().UTC())
+		i.StateChangedAt = &stateChangedAt
+		i.State = v.State
+	}
+	i.SchemaID = v.SchemaID
+	i.Traits = v.Traits
+	i.MetadataPublic = v.MetadataPublic
+	i.MetadataAdmin = v.MetadataAdmin
+}
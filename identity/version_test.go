@@ -0,0 +1,82 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelattr "go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/otelx/semconv"
+)
+
+func TestNewVersion(t *testing.T) {
+	i := NewIdentity("default")
+	i.ID = x.NewUUID()
+	i.SetCredentials(CredentialsTypeTOTP, Credentials{Type: CredentialsTypeTOTP, Config: []byte(`{"totp_url":"otpauth://totp/secret"}`)})
+	i.SetCredentials(CredentialsTypePassword, Credentials{Type: CredentialsTypePassword, Config: []byte(`{"hashed_password":"secret"}`)})
+
+	t.Run("case=defaults to the identity as actor", func(t *testing.T) {
+		v := NewVersion(context.Background(), i, 1)
+		assert.Equal(t, i.ID.String(), v.Actor)
+		assert.EqualValues(t, []string{"password", "totp"}, v.CredentialTypes)
+		assert.JSONEq(t, `{}`, string(v.Traits))
+
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "secret")
+	})
+
+	t.Run("case=uses the actor of the context", func(t *testing.T) {
+		ctx := semconv.ContextWithAttributes(context.Background(), otelattr.String(events.AttributeKeyActor.String(), events.ActorCLI))
+		assert.Equal(t, events.ActorCLI, NewVersion(ctx, i, 2).Actor)
+	})
+}
+
+func TestDiffVersions(t *testing.T) {
+	from := &Version{
+		Version:         1,
+		SchemaID:        "default",
+		State:           StateActive,
+		Traits:          Traits(`{"email":"foo@ory.sh","name":{"first":"Foo","last":"Bar"},"tags":["a"]}`),
+		MetadataPublic:  []byte(`{"plan":"free"}`),
+		CredentialTypes: []string{"password"},
+	}
+	to := &Version{
+		Version:         3,
+		SchemaID:        "default",
+		State:           StateActive,
+		Traits:          Traits(`{"email":"foo@ory.sh","name":{"first":"Foo"},"tags":["a","b"],"age":42}`),
+		MetadataAdmin:   []byte(`{"note":"vip"}`),
+		CredentialTypes: []string{"password", "totp"},
+	}
+
+	d, err := DiffVersions(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 1, d.From)
+	assert.Equal(t, 3, d.To)
+
+	raw, err := json.Marshal(d.Changes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"path":"credential_types","from":["password"],"to":["password","totp"]},
+		{"path":"metadata_admin","to":{"note":"vip"}},
+		{"path":"metadata_public","from":{"plan":"free"}},
+		{"path":"traits.age","to":42},
+		{"path":"traits.name.last","from":"Bar"},
+		{"path":"traits.tags","from":["a"],"to":["a","b"]}
+	]`, string(raw))
+
+	t.Run("case=no changes", func(t *testing.T) {
+		d, err := DiffVersions(from, from)
+		require.NoError(t, err)
+		assert.Empty(t, d.Changes)
+	})
+}
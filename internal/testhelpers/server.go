@@ -13,6 +13,7 @@ import (
 	"github.com/ory/kratos/driver"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/x/httprouterx"
 )
@@ -32,10 +33,12 @@ func NewKratosServerWithCSRFAndRouters(t *testing.T, reg driver.Registry) (publi
 	reg.WithCSRFHandler(csrfHandler)
 
 	ran := negroni.New()
+	ran.Use(events.NewRecorderMiddleware(reg.AuditRecorder(), events.ActorAdmin))
 	ran.UseFunc(httprouterx.AddAdminPrefixIfNotPresentNegroni)
 	ran.UseHandler(ra)
 
 	rpn := negroni.New()
+	rpn.Use(events.NewRecorderMiddleware(reg.AuditRecorder(), ""))
 	rpn.UseFunc(x.HTTPLoaderContextMiddleware(reg))
 	rpn.UseHandler(rp)
 
@@ -53,10 +56,12 @@ func NewKratosServerWithCSRFAndRouters(t *testing.T, reg driver.Registry) (publi
 
 func NewKratosServerWithRouters(t *testing.T, reg driver.Registry, rp *httprouterx.RouterPublic, ra *httprouterx.RouterAdmin) (public, admin *httptest.Server) {
 	np := negroni.New()
+	np.Use(events.NewRecorderMiddleware(reg.AuditRecorder(), ""))
 	np.UseFunc(httprouterx.TrimTrailingSlashNegroni)
 	np.UseHandler(rp)
 
 	na := negroni.New()
+	na.Use(events.NewRecorderMiddleware(reg.AuditRecorder(), events.ActorAdmin))
 	na.UseFunc(httprouterx.AddAdminPrefixIfNotPresentNegroni)
 	na.UseFunc(httprouterx.TrimTrailingSlashNegroni)
 	na.UseHandler(ra)
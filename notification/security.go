@@ -0,0 +1,280 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+var _ session.RevocationNotifier = new(SecurityNotifier)
+
+type (
+	securityNotifierDependencies interface {
+		config.Provider
+		courier.Provider
+		courier.ConfigProvider
+		identity.PrivilegedPoolProvider
+		x.HTTPClientProvider
+		x.LoggingProvider
+		x.TracingProvider
+	}
+	// SecurityNotifier sends emails to identities when sensitive events of
+	// their account occur. Which events are notified is configured in
+	// `security.notifications`.
+	SecurityNotifier struct {
+		d securityNotifierDependencies
+	}
+	SecurityNotifierProvider interface {
+		SecurityNotifier() *SecurityNotifier
+	}
+)
+
+func NewSecurityNotifier(d securityNotifierDependencies) *SecurityNotifier {
+	return &SecurityNotifier{d: d}
+}
+
+// CredentialsNotificationsEnabled returns true if any notification is enabled
+// which NotifyCredentialsChanged sends. Callers use it to skip loading the
+// original identity.
+func (n *SecurityNotifier) CredentialsNotificationsEnabled(ctx context.Context) bool {
+	conf := n.d.Config().SecurityNotifications(ctx)
+	return conf.PasswordChanged || conf.MFAMethodAdded || conf.MFAMethodRemoved || conf.OIDCProviderLinked
+}
+
+// NotifyCredentialsChanged compares the credentials of the identity before and
+// after an update and notifies the identity about a changed password, added
+// or removed second factors, and linked social sign in providers.
+func (n *SecurityNotifier) NotifyCredentialsChanged(ctx context.Context, r *http.Request, original, updated *identity.Identity) {
+	ctx, span := n.d.Tracer(ctx).Tracer().Start(ctx, "notification.SecurityNotifier.NotifyCredentialsChanged")
+	defer span.End()
+
+	conf := n.d.Config().SecurityNotifications(ctx)
+
+	if conf.PasswordChanged && passwordChanged(original, updated) {
+		n.notify(ctx, r, updated, template.TypePasswordChanged, email.SecurityNotificationModel{})
+	}
+
+	before, after := mfaMethods(original), mfaMethods(updated)
+	if conf.MFAMethodAdded {
+		for key, method := range after {
+			if _, ok := before[key]; !ok {
+				n.notify(ctx, r, updated, template.TypeMFAMethodAdded, email.SecurityNotificationModel{Method: string(method)})
+			}
+		}
+	}
+	if conf.MFAMethodRemoved {
+		for key, method := range before {
+			if _, ok := after[key]; !ok {
+				n.notify(ctx, r, updated, template.TypeMFAMethodRemoved, email.SecurityNotificationModel{Method: string(method)})
+			}
+		}
+	}
+
+	if conf.OIDCProviderLinked {
+		linked := oidcProviders(original)
+		for _, provider := range oidcProviders(updated) {
+			if !slices.Contains(linked, provider) {
+				n.notify(ctx, r, updated, template.TypeOIDCProviderLinked, email.SecurityNotificationModel{Provider: provider.Provider})
+			}
+		}
+	}
+}
+
+// NotifySessionsRevoked notifies the identity that its sessions were revoked.
+func (n *SecurityNotifier) NotifySessionsRevoked(ctx context.Context, r *http.Request, identityID uuid.UUID) {
+	if !n.d.Config().SecurityNotifications(ctx).SessionsRevoked {
+		return
+	}
+
+	ctx, span := n.d.Tracer(ctx).Tracer().Start(ctx, "notification.SecurityNotifier.NotifySessionsRevoked")
+	defer span.End()
+
+	i, err := n.d.PrivilegedIdentityPool().GetIdentity(ctx, identityID, identity.ExpandDefault)
+	if err != nil {
+		n.logError(r, err, template.TypeSessionsRevoked, identityID)
+		return
+	}
+	n.notify(ctx, r, i, template.TypeSessionsRevoked, email.SecurityNotificationModel{})
+}
+
+// NotifyAccountDeleted confirms to the identity that its account was deleted
+// using the self-service deletion flow. It is always sent, because the
+// identity can no longer sign in to see the outcome. The identity must be
+// loaded before it is deleted.
+func (n *SecurityNotifier) NotifyAccountDeleted(ctx context.Context, r *http.Request, i *identity.Identity) {
+	ctx, span := n.d.Tracer(ctx).Tracer().Start(ctx, "notification.SecurityNotifier.NotifyAccountDeleted")
+	defer span.End()
+
+	n.notify(ctx, r, i, template.TypeAccountDeleted, email.SecurityNotificationModel{})
+}
+
+// NotifyLogin notifies the identity if the latest device of the session, which
+// is the device of the login that is currently performed, uses a user agent
+// which is not in the device history of the identity. The first login of an
+// identity is not notified, because there is nothing to compare with.
+func (n *SecurityNotifier) NotifyLogin(ctx context.Context, r *http.Request, s *session.Session, history []session.Device) {
+	if !n.d.Config().SecurityNotifications(ctx).NewDeviceLogin || s.Identity == nil || len(s.Devices) == 0 {
+		return
+	}
+
+	ctx, span := n.d.Tracer(ctx).Tracer().Start(ctx, "notification.SecurityNotifier.NotifyLogin")
+	defer span.End()
+
+	current := s.Devices[len(s.Devices)-1]
+	if current.UserAgent == nil || *current.UserAgent == "" {
+		return
+	}
+
+	if len(history) == 0 || slices.ContainsFunc(history, func(d session.Device) bool {
+		return d.UserAgent != nil && *d.UserAgent == *current.UserAgent
+	}) {
+		return
+	}
+
+	n.notify(ctx, r, s.Identity, template.TypeNewDeviceLogin, email.SecurityNotificationModel{
+		Device: &email.SecurityNotificationDevice{
+			IPAddress: stringOrEmpty(current.IPAddress),
+			UserAgent: *current.UserAgent,
+			Location:  stringOrEmpty(current.Location),
+		},
+	})
+}
+
+// notify queues the notification for every email address of the identity.
+// Failures are logged, because a notification must never fail the request
+// which triggered it.
+func (n *SecurityNotifier) notify(ctx context.Context, r *http.Request, i *identity.Identity, typ template.TemplateType, m email.SecurityNotificationModel) {
+	recipients := emailRecipients(i)
+	if len(recipients) == 0 {
+		n.d.Logger().
+			WithRequest(r).
+			WithField("identity_id", i.ID).
+			WithField("template_type", typ).
+			Debug("Not sending security notification because the identity has no email address.")
+		return
+	}
+
+	c, err := n.d.Courier(ctx)
+	if err != nil {
+		n.logError(r, err, typ, i.ID)
+		return
+	}
+
+	model, err := x.StructToMap(i.CopyWithoutCredentials())
+	if err != nil {
+		n.logError(r, err, typ, i.ID)
+		return
+	}
+
+	m.Identity = model
+	m.OccurredAt = time.Now().UTC()
+	m.Locale = template.ResolveLocale(ctx, n.d, i.Traits, i.MetadataPublic, x.PreferredLanguage(r.Header))
+	for _, to := range recipients {
+		m := m
+		m.To = to
+		if _, err := c.QueueEmail(ctx, email.NewSecurityNotification(n.d, typ, &m)); err != nil {
+			n.logError(r, err, typ, i.ID)
+		}
+	}
+}
+
+func (n *SecurityNotifier) logError(r *http.Request, err error, typ template.TemplateType, identityID uuid.UUID) {
+	n.d.Logger().
+		WithRequest(r).
+		WithError(err).
+		WithField("identity_id", identityID).
+		WithField("template_type", typ).
+		Error("Unable to send security notification.")
+}
+
+// emailRecipients returns the recovery email addresses of the identity, or its
+// verifiable email addresses if it has no recovery email address.
+func emailRecipients(i *identity.Identity) []string {
+	var recipients []string
+	for _, a := range i.RecoveryAddresses {
+		if a.Via == identity.AddressTypeEmail && !slices.Contains(recipients, a.Value) {
+			recipients = append(recipients, a.Value)
+		}
+	}
+	if len(recipients) > 0 {
+		return recipients
+	}
+	for _, a := range i.VerifiableAddresses {
+		if a.Via == identity.AddressTypeEmail && !slices.Contains(recipients, a.Value) {
+			recipients = append(recipients, a.Value)
+		}
+	}
+	return recipients
+}
+
+func passwordChanged(original, updated *identity.Identity) bool {
+	var before, after identity.CredentialsPassword
+	if _, err := updated.ParseCredentials(identity.CredentialsTypePassword, &after); err != nil || after.HashedPassword == "" {
+		return false
+	}
+	if _, err := original.ParseCredentials(identity.CredentialsTypePassword, &before); err != nil {
+		return true
+	}
+	return before.HashedPassword != after.HashedPassword
+}
+
+// mfaMethods returns the second factors of the identity, keyed by a string
+// which identifies the individual TOTP app, lookup secret set, or security
+// key.
+func mfaMethods(i *identity.Identity) map[string]identity.CredentialsType {
+	methods := make(map[string]identity.CredentialsType)
+	if c, ok := i.GetCredentials(identity.CredentialsTypeTOTP); ok && len(c.Config) > 0 {
+		methods[string(identity.CredentialsTypeTOTP)] = identity.CredentialsTypeTOTP
+	}
+	if c, ok := i.GetCredentials(identity.CredentialsTypeLookup); ok && len(c.Config) > 0 {
+		methods[string(identity.CredentialsTypeLookup)] = identity.CredentialsTypeLookup
+	}
+
+	var webAuthn identity.CredentialsWebAuthnConfig
+	if _, err := i.ParseCredentials(identity.CredentialsTypeWebAuthn, &webAuthn); err == nil {
+		for _, c := range webAuthn.Credentials {
+			if !c.IsPasswordless {
+				methods[string(identity.CredentialsTypeWebAuthn)+":"+base64.RawURLEncoding.EncodeToString(c.ID)] = identity.CredentialsTypeWebAuthn
+			}
+		}
+	}
+	return methods
+}
+
+type oidcProvider struct {
+	Provider, Subject string
+}
+
+func oidcProviders(i *identity.Identity) []oidcProvider {
+	var conf identity.CredentialsOIDC
+	if _, err := i.ParseCredentials(identity.CredentialsTypeOIDC, &conf); err != nil {
+		return nil
+	}
+	providers := make([]oidcProvider, 0, len(conf.Providers))
+	for _, p := range conf.Providers {
+		providers = append(providers, oidcProvider{Provider: p.Provider, Subject: p.Subject})
+	}
+	return providers
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
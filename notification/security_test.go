@@ -0,0 +1,196 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/session"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/pointerx"
+	"github.com/ory/x/sqlxx"
+)
+
+func TestSecurityNotifier(t *testing.T) {
+	t.Parallel()
+
+	allEnabled := configx.WithValues(map[string]any{
+		config.ViperKeySecurityNotificationsPasswordChanged:    true,
+		config.ViperKeySecurityNotificationsMFAMethodAdded:     true,
+		config.ViperKeySecurityNotificationsMFAMethodRemoved:   true,
+		config.ViperKeySecurityNotificationsOIDCProviderLinked: true,
+		config.ViperKeySecurityNotificationsSessionsRevoked:    true,
+		config.ViperKeySecurityNotificationsNewDeviceLogin:     true,
+	})
+
+	newRegistry := func(t *testing.T, opts ...configx.OptionModifier) *driver.RegistryDefault {
+		_, reg := internal.NewFastRegistryWithMocks(t, append([]configx.OptionModifier{
+			configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		}, opts...)...)
+		return reg
+	}
+
+	newIdentity := func(t *testing.T, reg *driver.RegistryDefault) *identity.Identity {
+		email := uuid.Must(uuid.NewV4()).String() + "@ory.sh"
+		id := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		id.Traits = identity.Traits(`{"email":"` + email + `"}`)
+		id.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Identifiers: []string{email},
+			Config:      sqlxx.JSONRawMessage(`{"hashed_password":"foo"}`),
+		})
+		require.NoError(t, reg.IdentityManager().Create(t.Context(), id))
+
+		id, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), id.ID)
+		require.NoError(t, err)
+		return id
+	}
+
+	messages := func(t *testing.T, ctx context.Context, reg *driver.RegistryDefault, id *identity.Identity) []courier.Message {
+		msgs, _, err := reg.CourierPersister().ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: id.RecoveryAddresses[0].Value}, nil)
+		require.NoError(t, err)
+		return msgs
+	}
+
+	templateTypes := func(msgs []courier.Message) []template.TemplateType {
+		types := make([]template.TemplateType, len(msgs))
+		for k, m := range msgs {
+			types[k] = m.TemplateType
+		}
+		return types
+	}
+
+	req := testhelpers.NewTestHTTPRequest(t, "GET", "/", nil)
+
+	t.Run("case=notifies about changed credentials", func(t *testing.T) {
+		t.Parallel()
+		reg := newRegistry(t, allEnabled)
+		ctx := t.Context()
+
+		original := newIdentity(t, reg)
+		updated := *original
+		updated.Credentials = map[identity.CredentialsType]identity.Credentials{}
+		for k, v := range original.Credentials {
+			updated.Credentials[k] = v
+		}
+		updated.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Identifiers: original.Credentials[identity.CredentialsTypePassword].Identifiers,
+			Config:      sqlxx.JSONRawMessage(`{"hashed_password":"bar"}`),
+		})
+		updated.SetCredentials(identity.CredentialsTypeTOTP, identity.Credentials{
+			Config: sqlxx.JSONRawMessage(`{"totp_url":"otpauth://totp/foo"}`),
+		})
+		oidc, err := identity.NewCredentialsOIDC(new(identity.CredentialsOIDCEncryptedTokens), "google", "subject", "")
+		require.NoError(t, err)
+		updated.SetCredentials(identity.CredentialsTypeOIDC, *oidc)
+
+		reg.SecurityNotifier().NotifyCredentialsChanged(ctx, req, original, &updated)
+
+		msgs := messages(t, ctx, reg, original)
+		assert.ElementsMatch(t, []template.TemplateType{template.TypePasswordChanged, template.TypeMFAMethodAdded, template.TypeOIDCProviderLinked}, templateTypes(msgs))
+		for _, m := range msgs {
+			var data map[string]any
+			require.NoError(t, json.Unmarshal(m.TemplateData, &data))
+			assert.NotContains(t, data["identity"], "credentials")
+			switch m.TemplateType {
+			case template.TypeMFAMethodAdded:
+				assert.Equal(t, "totp", data["method"])
+			case template.TypeOIDCProviderLinked:
+				assert.Equal(t, "google", data["provider"])
+			}
+		}
+
+		reg.SecurityNotifier().NotifyCredentialsChanged(ctx, req, &updated, original)
+		assert.Contains(t, templateTypes(messages(t, ctx, reg, original)), template.TypeMFAMethodRemoved)
+	})
+
+	t.Run("case=does not notify about disabled events", func(t *testing.T) {
+		t.Parallel()
+		reg := newRegistry(t)
+		ctx := t.Context()
+
+		original := newIdentity(t, reg)
+		assert.False(t, reg.SecurityNotifier().CredentialsNotificationsEnabled(ctx))
+
+		updated := *original
+		updated.Credentials = map[identity.CredentialsType]identity.Credentials{}
+		updated.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Config: sqlxx.JSONRawMessage(`{"hashed_password":"bar"}`),
+		})
+		reg.SecurityNotifier().NotifyCredentialsChanged(ctx, req, original, &updated)
+		reg.SecurityNotifier().NotifySessionsRevoked(ctx, req, original.ID)
+
+		assert.Empty(t, messages(t, ctx, reg, original))
+	})
+
+	t.Run("case=notifies about revoked sessions", func(t *testing.T) {
+		t.Parallel()
+		reg := newRegistry(t, allEnabled)
+		ctx := t.Context()
+
+		id := newIdentity(t, reg)
+		reg.SecurityNotifier().NotifySessionsRevoked(ctx, req, id.ID)
+
+		assert.Equal(t, []template.TemplateType{template.TypeSessionsRevoked}, templateTypes(messages(t, ctx, reg, id)))
+	})
+
+	t.Run("case=always confirms account deletion", func(t *testing.T) {
+		t.Parallel()
+		reg := newRegistry(t)
+		ctx := t.Context()
+
+		id := newIdentity(t, reg)
+		reg.SecurityNotifier().NotifyAccountDeleted(ctx, req, id)
+
+		assert.Equal(t, []template.TemplateType{template.TypeAccountDeleted}, templateTypes(messages(t, ctx, reg, id)))
+	})
+
+	t.Run("case=notifies about logins from new devices", func(t *testing.T) {
+		t.Parallel()
+		reg := newRegistry(t, allEnabled)
+		ctx := t.Context()
+
+		id := newIdentity(t, reg)
+		login := func(userAgent string) *session.Session {
+			s, err := testhelpers.NewActiveSession(req, reg, id, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+			require.NoError(t, err)
+			s.Devices = []session.Device{{IPAddress: pointerx.Ptr("192.0.2.1"), UserAgent: pointerx.Ptr(userAgent)}}
+			return s
+		}
+
+		history := func() []session.Device {
+			devices, err := reg.SessionDevicePersister().ListDevicesByIdentity(ctx, id.ID, time.Time{}, session.DeviceHistoryLimit)
+			require.NoError(t, err)
+			return devices
+		}
+
+		// The first login has no history to compare with.
+		first := login("agent-a")
+		reg.SecurityNotifier().NotifyLogin(ctx, req, first, history())
+		require.NoError(t, reg.SessionPersister().UpsertSession(ctx, first))
+		assert.Empty(t, messages(t, ctx, reg, id))
+
+		reg.SecurityNotifier().NotifyLogin(ctx, req, login("agent-a"), history())
+		assert.Empty(t, messages(t, ctx, reg, id))
+
+		reg.SecurityNotifier().NotifyLogin(ctx, req, login("agent-b"), history())
+		msgs := messages(t, ctx, reg, id)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, template.TypeNewDeviceLogin, msgs[0].TemplateType)
+		assert.Contains(t, string(msgs[0].TemplateData), "agent-b")
+	})
+}
@@ -18,10 +18,12 @@ import (
 
 	"github.com/ory/x/popx"
 
+	"github.com/ory/kratos/audit"
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow/deletion"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -30,6 +32,7 @@ import (
 	"github.com/ory/kratos/selfservice/strategy/code"
 	"github.com/ory/kratos/selfservice/strategy/link"
 	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/webhook"
 )
 
 type Provider interface {
@@ -38,13 +41,18 @@ type Provider interface {
 }
 
 type Persister interface {
+	audit.Persister
 	continuity.Persister
 	identity.PrivilegedPool
+	identity.LoginLockoutPersister
 	registration.FlowPersister
 	login.FlowPersister
+	login.DeviceAuthorizationPersister
 	settings.FlowPersister
+	deletion.FlowPersister
 	courier.Persister
 	session.Persister
+	session.DevicePersister
 	sessiontokenexchange.Persister
 	errorx.Persister
 	verification.FlowPersister
@@ -55,6 +63,7 @@ type Persister interface {
 	code.VerificationCodePersister
 	code.RegistrationCodePersister
 	code.LoginCodePersister
+	webhook.Persister
 
 	CleanupDatabase(context.Context, time.Duration, time.Duration, int) error
 	Close(context.Context) error
@@ -5,6 +5,7 @@ package devices
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 
@@ -43,3 +44,15 @@ func (p *DevicePersister) CreateDevice(ctx context.Context, d *session.Device) e
 	d.NID = p.NetworkID(ctx)
 	return sqlcon.HandleError(popx.GetConnection(ctx, p.c.WithContext(ctx)).Create(d))
 }
+
+func (p *DevicePersister) ListDevicesByIdentity(ctx context.Context, identityID uuid.UUID, since time.Time, limit int) ([]session.Device, error) {
+	var devices []session.Device
+	if err := popx.GetConnection(ctx, p.c.WithContext(ctx)).
+		Where("identity_id = ? AND nid = ? AND created_at > ?", identityID, p.NetworkID(ctx), since).
+		Order("created_at DESC").
+		Limit(limit).
+		All(&devices); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return devices, nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+)
+
+// listFilterCondition returns the SQL condition and its arguments for a filter
+// expression of the identity list.
+//
+// Values within JSON documents are compared by their text representation,
+// which every dialect extracts differently: Postgres and CockroachDB use the
+// JSONB path operator, MySQL and SQLite their JSON functions.
+func listFilterCondition(dialect string, f identity.ListIdentityFilter) (string, []any, error) {
+	var operator string
+	switch f.Operator {
+	case identity.ListIdentityFilterOperatorEqual:
+		operator = "="
+	case identity.ListIdentityFilterOperatorNotEqual:
+		operator = "<>"
+	case identity.ListIdentityFilterOperatorGreaterThan:
+		operator = ">"
+	case identity.ListIdentityFilterOperatorGreaterThanOrEqual:
+		operator = ">="
+	case identity.ListIdentityFilterOperatorLessThan:
+		operator = "<"
+	case identity.ListIdentityFilterOperatorLessThanOrEqual:
+		operator = "<="
+	default:
+		return "", nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter operator `%s` is not supported.", f.Operator))
+	}
+
+	var column string
+	switch f.Field {
+	case identity.ListIdentityFilterFieldTraits:
+		column = "identities.traits"
+	case identity.ListIdentityFilterFieldMetadataPublic:
+		column = "identities.metadata_public"
+	case identity.ListIdentityFilterFieldMetadataAdmin:
+		column = "identities.metadata_admin"
+	case identity.ListIdentityFilterFieldState:
+		column = "identities.state"
+	case identity.ListIdentityFilterFieldSchemaID:
+		column = "identities.schema_id"
+	case identity.ListIdentityFilterFieldCreatedAt:
+		column = "identities.created_at"
+	case identity.ListIdentityFilterFieldUpdatedAt:
+		column = "identities.updated_at"
+	default:
+		return "", nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("The filter field `%s` is not supported.", f.Field))
+	}
+
+	if !f.Field.IsJSON() {
+		return fmt.Sprintf("%s %s ?", column, operator), []any{f.Value}, nil
+	}
+
+	switch dialect {
+	case "postgres", "cockroach":
+		return fmt.Sprintf("%s #>> CAST(? AS TEXT[]) %s ?", column, operator), []any{postgresJSONPath(f.Path), f.Value}, nil
+	case "mysql":
+		return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, ?)) %s ?", column, operator), []any{jsonPath(f.Path), f.Value}, nil
+	case "sqlite3":
+		// json_extract returns SQL values, so booleans are converted back to
+		// their JSON representation to match the other dialects.
+		return fmt.Sprintf("(CASE json_type(%[1]s, ?) WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(json_extract(%[1]s, ?) AS TEXT) END) %[2]s ?", column, operator),
+			[]any{jsonPath(f.Path), jsonPath(f.Path), f.Value}, nil
+	default:
+		return "", nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Filtering identities by %s is not supported for the %s database.", f.Field, dialect))
+	}
+}
+
+// jsonPath returns the path in the syntax of MySQL and SQLite, for example
+// `$."emails"[0]`. The keys of the path were validated to be alphanumeric.
+func jsonPath(path []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, key := range path {
+		if _, err := strconv.ParseUint(key, 10, 32); err == nil {
+			b.WriteString("[" + key + "]")
+		} else {
+			b.WriteString(`."` + key + `"`)
+		}
+	}
+	return b.String()
+}
+
+// postgresJSONPath returns the path as a Postgres text array, for example
+// `{emails,0}`. The keys of the path were validated to be alphanumeric.
+func postgresJSONPath(path []string) string {
+	return "{" + strings.Join(path, ",") + "}"
+}
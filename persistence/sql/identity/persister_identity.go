@@ -135,6 +135,9 @@ func NormalizeIdentifier(ct identity.CredentialsType, match string) string {
 		return match
 	case identity.CredentialsTypePassword, identity.CredentialsTypeCodeAuth, identity.CredentialsTypeWebAuthn:
 		return stringToLowerTrim(match)
+	case identity.CredentialsTypeLDAP:
+		// directory entries are matched case-insensitively
+		return stringToLowerTrim(match)
 	default:
 		return match
 	}
@@ -175,7 +178,17 @@ LIMIT 1`,
 		return nil, sqlcon.HandleError(err)
 	}
 
-	return p.GetIdentity(ctx, find.IdentityID, expand)
+	i, err := p.GetIdentity(ctx, find.IdentityID, expand)
+	if err != nil {
+		return nil, err
+	}
+
+	// Soft-deleted identities can not sign in.
+	if i.State == identity.StateDeleted {
+		return nil, errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return i, nil
 }
 
 func (p *IdentityPersister) FindByCredentialsIdentifier(ctx context.Context, ct identity.CredentialsType, match string) (_ *identity.Identity, _ *identity.Credentials, err error) {
@@ -225,6 +238,11 @@ func (p *IdentityPersister) FindByCredentialsIdentifier(ctx context.Context, ct
 		return nil, nil, err
 	}
 
+	// Soft-deleted identities can not sign in.
+	if i.State == identity.StateDeleted {
+		return nil, nil, errors.WithStack(sqlcon.ErrNoRows)
+	}
+
 	creds, ok := i.GetCredentials(ct)
 	if !ok {
 		return nil, nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The SQL adapter failed to return the appropriate credentials_type \"%s\". This is a bug in the code.", ct))
@@ -263,11 +281,13 @@ INNER JOIN identity_credentials
      )
 WHERE identity_credentials.config ->> '%s' = ? AND identity_credentials.config ->> '%s' IS NOT NULL
   AND identities.nid = ?
+  AND identities.state != ?
 LIMIT 1`, columns,
 		jsonPath, jsonPath),
 		identity.CredentialsTypeWebAuthn,
 		base64.StdEncoding.EncodeToString(userHandle),
 		p.NetworkID(ctx),
+		identity.StateDeleted,
 	).First(&id); err != nil {
 		return nil, sqlcon.HandleError(err)
 	}
@@ -602,7 +622,8 @@ func (p *IdentityPersister) CountIdentities(ctx context.Context) (n int64, err e
 			attribute.Stringer("network.id", p.NetworkID(ctx))))
 	defer otelx.End(span, &err)
 
-	count, err := p.c.WithContext(ctx).Where("nid = ?", p.NetworkID(ctx)).Count(new(identity.Identity))
+	// Soft-deleted identities are not counted, like they are not listed.
+	count, err := p.c.WithContext(ctx).Where("nid = ? AND state != ?", p.NetworkID(ctx), identity.StateDeleted).Count(new(identity.Identity))
 	if err != nil {
 		return 0, sqlcon.HandleError(err)
 	}
@@ -736,6 +757,7 @@ func (p *IdentityPersister) CreateIdentities(ctx context.Context, identities ...
 			partialErr = identity.NewCreateIdentitiesError(len(failedIdentityIDs))
 			idsToBeRemoved := make([]uuid.UUID, 0, len(failedIdentityIDs))
 
+			succeeded := make([]*identity.Identity, 0, len(identities))
 			for _, ident := range identities {
 				if info, ok := failedIdentityIDs[ident.ID]; ok {
 					partialErr.AddFailedIdentity(ident, sqlcon.ErrUniqueViolation)
@@ -744,6 +766,7 @@ func (p *IdentityPersister) CreateIdentities(ctx context.Context, identities ...
 					}
 				} else {
 					succeededIDs = append(succeededIDs, ident.ID)
+					succeeded = append(succeeded, ident)
 				}
 			}
 			// Manually roll back by deleting the identities that were inserted before the
@@ -752,7 +775,7 @@ func (p *IdentityPersister) CreateIdentities(ctx context.Context, identities ...
 				return sqlcon.HandleError(err)
 			}
 
-			return nil
+			return p.createInitialIdentityVersions(ctx, tx, succeeded...)
 		} else {
 			// No failures: report all identities as created.
 			for _, ident := range identities {
@@ -760,14 +783,14 @@ func (p *IdentityPersister) CreateIdentities(ctx context.Context, identities ...
 			}
 		}
 
-		return nil
+		return p.createInitialIdentityVersions(ctx, tx, identities...)
 	}); err != nil {
 		return err
 	}
 
 	// Report succeeded identities as created.
 	for _, identID := range succeededIDs {
-		span.AddEvent(events.NewIdentityCreated(ctx, identID))
+		events.SpanFromContext(ctx).AddEvent(events.NewIdentityCreated(ctx, identID))
 	}
 
 	return partialErr.ErrOrNil()
@@ -944,6 +967,7 @@ func paginationAttributes(params *identity.ListIdentityParameters, paginator *ke
 		attribute.StringSlice("expand", params.Expand.ToEager()),
 		attribute.Bool("use:credential_identifier_filter", params.CredentialsIdentifier != ""),
 		attribute.Bool("use:credential_identifier_similar_filter", params.CredentialsIdentifierSimilar != ""),
+		attribute.Int("filters", len(params.Filters)),
 	}
 	if params.PagePagination != nil {
 		attrs = append(attrs,
@@ -1056,6 +1080,26 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 			)
 		}
 
+		filtersByState := false
+		for _, f := range params.Filters {
+			condition, conditionArgs, err := listFilterCondition(con.Dialect.Name(), f)
+			if err != nil {
+				return err
+			}
+			wheres += `
+				AND ` + condition
+			args = append(args, conditionArgs...)
+			filtersByState = filtersByState || f.Field == identity.ListIdentityFilterFieldState
+		}
+
+		// Soft-deleted identities are hidden unless explicitly filtered for.
+		if !filtersByState {
+			wheres += `
+				AND identities.state != ?
+			`
+			args = append(args, identity.StateDeleted)
+		}
+
 		if len(params.IdsFilter) > 0 {
 			wheres += `
 				AND identities.id in (?)
@@ -1177,7 +1221,7 @@ func (p *IdentityPersister) UpdateIdentityColumns(ctx context.Context, i *identi
 		return err
 	}
 
-	span.AddEvent(events.NewIdentityUpdated(ctx, i.ID))
+	events.SpanFromContext(ctx).AddEvent(events.NewIdentityUpdated(ctx, i.ID))
 	return nil
 }
 
@@ -1253,12 +1297,16 @@ func (p *IdentityPersister) UpdateIdentity(ctx context.Context, i *identity.Iden
 		}
 
 		i.Credentials, err = p.updateCredentialsAssociation(ctx, i.ID, oldCredentials, newCredentials)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return p.createIdentityVersion(ctx, tx, i)
 	})); err != nil {
 		return err
 	}
 
-	span.AddEvent(events.NewIdentityUpdated(ctx, i.ID))
+	events.SpanFromContext(ctx).AddEvent(events.NewIdentityUpdated(ctx, i.ID))
 	return nil
 }
 
@@ -1284,7 +1332,7 @@ func (p *IdentityPersister) DeleteIdentity(ctx context.Context, id uuid.UUID) (e
 	if count == 0 {
 		return errors.WithStack(sqlcon.ErrNoRows)
 	}
-	span.AddEvent(events.NewIdentityDeleted(ctx, id))
+	events.SpanFromContext(ctx).AddEvent(events.NewIdentityDeleted(ctx, id))
 	return nil
 }
 
@@ -1376,6 +1424,11 @@ func (p *IdentityPersister) FindIdentityByExternalID(ctx context.Context, extern
 	return &i, nil
 }
 
+// notSoftDeletedCondition excludes addresses of soft-deleted identities, so
+// that no codes or links are sent to them. It expects the network ID and
+// identity.StateDeleted as arguments.
+const notSoftDeletedCondition = "identity_id NOT IN (SELECT id FROM identities WHERE nid = ? AND state = ?)"
+
 func (p *IdentityPersister) FindVerifiableAddressByValue(ctx context.Context, via string, value string) (_ *identity.VerifiableAddress, err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.FindVerifiableAddressByValue",
 		trace.WithAttributes(
@@ -1383,7 +1436,10 @@ func (p *IdentityPersister) FindVerifiableAddressByValue(ctx context.Context, vi
 	otelx.End(span, &err)
 
 	var address identity.VerifiableAddress
-	if err := p.GetConnection(ctx).Where("nid = ? AND via = ? AND value = ?", p.NetworkID(ctx), via, stringToLowerTrim(value)).First(&address); err != nil {
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND via = ? AND value = ?", p.NetworkID(ctx), via, stringToLowerTrim(value)).
+		Where(notSoftDeletedCondition, p.NetworkID(ctx), identity.StateDeleted).
+		First(&address); err != nil {
 		return nil, sqlcon.HandleError(err)
 	}
 
@@ -1397,7 +1453,10 @@ func (p *IdentityPersister) FindRecoveryAddressByValue(ctx context.Context, via,
 	defer otelx.End(span, &err)
 
 	var address identity.RecoveryAddress
-	if err := p.GetConnection(ctx).Where("nid = ? AND via = ? AND value = ?", p.NetworkID(ctx), via, stringToLowerTrim(value)).First(&address); err != nil {
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND via = ? AND value = ?", p.NetworkID(ctx), via, stringToLowerTrim(value)).
+		Where(notSoftDeletedCondition, p.NetworkID(ctx), identity.StateDeleted).
+		First(&address); err != nil {
 		return nil, sqlcon.HandleError(err)
 	}
 
@@ -1432,10 +1491,13 @@ ON A.identity_id = B.identity_id
 AND A.nid = B.nid
 WHERE B.value = ?
 AND A.nid = ?
+AND A.`+notSoftDeletedCondition+`
 LIMIT 10
 		`,
 		stringToLowerTrim(anyRecoveryAddress),
 		p.NetworkID(ctx),
+		p.NetworkID(ctx),
+		identity.StateDeleted,
 	).
 		All(&recoveryAddresses)
 	if err != nil {
@@ -0,0 +1,174 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+)
+
+func (p *IdentityPersister) SoftDeleteIdentity(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.SoftDeleteIdentity",
+		trace.WithAttributes(
+			attribute.Stringer("identity.id", id),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	var deleted bool
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		deleted = false
+		i, err := p.GetIdentity(ctx, id, identity.ExpandCredentials)
+		if err != nil {
+			return err
+		}
+
+		// Deleting an identity twice must not extend its retention.
+		if i.State == identity.StateDeleted {
+			return nil
+		}
+
+		if err := p.setIdentityState(ctx, tx, i, identity.StateDeleted); err != nil {
+			return err
+		}
+
+		//#nosec G201 -- TableName is static
+		if err := tx.RawQuery(fmt.Sprintf(
+			"UPDATE %s SET active = false WHERE identity_id = ? AND nid = ?",
+			session.Session{}.TableName(),
+		),
+			id,
+			p.NetworkID(ctx),
+		).Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		deleted = true
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if deleted {
+		events.SpanFromContext(ctx).AddEvent(events.NewIdentitySoftDeleted(ctx, id))
+	}
+	return nil
+}
+
+func (p *IdentityPersister) UndeleteIdentity(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UndeleteIdentity",
+		trace.WithAttributes(
+			attribute.Stringer("identity.id", id),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		i, err := p.GetIdentity(ctx, id, identity.ExpandCredentials)
+		if err != nil {
+			return err
+		}
+
+		if i.State != identity.StateDeleted {
+			return errors.WithStack(herodot.ErrConflict.WithReason("The identity is not deleted."))
+		}
+
+		// Identities created before versions were recorded have no previous
+		// state, and were active unless they were disabled later on.
+		var previous []identity.State
+		if err := tx.RawQuery(
+			"SELECT state FROM identity_versions WHERE identity_id = ? AND nid = ? AND state IN (?, ?) ORDER BY version DESC LIMIT 1",
+			id, p.NetworkID(ctx), identity.StateActive, identity.StateInactive,
+		).All(&previous); err != nil {
+			return sqlcon.HandleError(err)
+		}
+
+		state := identity.StateActive
+		if len(previous) > 0 {
+			state = previous[0]
+		}
+
+		return p.setIdentityState(ctx, tx, i, state)
+	}); err != nil {
+		return err
+	}
+
+	events.SpanFromContext(ctx).AddEvent(events.NewIdentityUndeleted(ctx, id))
+	return nil
+}
+
+// setIdentityState changes the state of the identity and records the change as
+// a new version. It must be called within a transaction.
+func (p *IdentityPersister) setIdentityState(ctx context.Context, tx *pop.Connection, i *identity.Identity, state identity.State) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	stateChangedAt := sqlxx.NullTime(now)
+	i.State = state
+	i.StateChangedAt = &stateChangedAt
+	i.UpdatedAt = now
+
+	if _, err := tx.Where("id = ? AND nid = ?", i.ID, p.NetworkID(ctx)).UpdateQuery(i, "state", "state_changed_at", "updated_at"); err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	return p.createIdentityVersion(ctx, tx, i)
+}
+
+func (p *IdentityPersister) PurgeDeletedIdentities(ctx context.Context, olderThan time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.PurgeDeletedIdentities",
+		trace.WithAttributes(
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	retention := p.r.Config().IdentitySoftDelete(ctx).Retention
+	if retention <= 0 {
+		return nil
+	}
+
+	if keepFrom := time.Now().UTC().Add(-retention); olderThan.After(keepFrom) {
+		olderThan = keepFrom
+	}
+
+	var ids []uuid.UUID
+	if err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("SELECT id FROM identities WHERE state = ? AND state_changed_at < ? AND nid = ? ORDER BY state_changed_at ASC LIMIT %d", limit),
+		identity.StateDeleted, olderThan, p.NetworkID(ctx),
+	).All(&ids); err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	tableName := new(identity.Identity).TableName(ctx)
+	if p.c.Dialect.Name() == "cockroach" {
+		tableName += "@primary"
+	}
+	for _, id := range ids {
+		// The state is checked again, because the identity might have been
+		// undeleted in the meantime.
+		count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND nid = ? AND state = ?", tableName),
+			id,
+			p.NetworkID(ctx),
+			identity.StateDeleted,
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		}
+		if count > 0 {
+			events.SpanFromContext(ctx).AddEvent(events.NewIdentityDeleted(ctx, id))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/persistence/sql/batch"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/otelx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ identity.VersionPool = new(IdentityPersister)
+
+// createInitialIdentityVersions writes the first version of newly created
+// identities.
+func (p *IdentityPersister) createInitialIdentityVersions(ctx context.Context, tx *pop.Connection, identities ...*identity.Identity) error {
+	versions := make([]*identity.Version, len(identities))
+	for k, i := range identities {
+		versions[k] = identity.NewVersion(ctx, i, 1)
+	}
+
+	return batch.Create(ctx, &batch.TracerConnection{Tracer: p.r.Tracer(ctx), Connection: tx}, versions)
+}
+
+// createIdentityVersion writes the next version of an updated identity. It
+// must be called within the transaction updating the identity.
+func (p *IdentityPersister) createIdentityVersion(ctx context.Context, tx *pop.Connection, i *identity.Identity) error {
+	var latest int
+	if err := tx.RawQuery(
+		"SELECT COALESCE(MAX(version), 0) FROM identity_versions WHERE identity_id = ? AND nid = ?",
+		i.ID, p.NetworkID(ctx),
+	).First(&latest); err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	return sqlcon.HandleError(tx.Create(identity.NewVersion(ctx, i, latest+1)))
+}
+
+func (p *IdentityPersister) ListIdentityVersions(ctx context.Context, identityID uuid.UUID, opts []keysetpagination.Option) (_ []identity.Version, _ *keysetpagination.Paginator, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListIdentityVersions",
+		trace.WithAttributes(
+			attribute.Stringer("identity.id", identityID),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	opts = append(opts, keysetpagination.WithDefaultToken(identity.Version{}.DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(250))
+	paginator := keysetpagination.NewPaginator(opts...)
+
+	versions := make([]identity.Version, paginator.Size())
+	if err := p.GetConnection(ctx).
+		Where("identity_id = ? AND nid = ?", identityID, p.NetworkID(ctx)).
+		Scope(keysetpagination.Paginate[identity.Version](paginator)).
+		All(&versions); err != nil {
+		return nil, nil, sqlcon.HandleError(err)
+	}
+
+	versions, nextPage := keysetpagination.Result(versions, paginator)
+	return versions, nextPage, nil
+}
+
+func (p *IdentityPersister) GetIdentityVersion(ctx context.Context, identityID uuid.UUID, version int) (_ *identity.Version, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetIdentityVersion",
+		trace.WithAttributes(
+			attribute.Stringer("identity.id", identityID),
+			attribute.Int("identity.version", version),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	var v identity.Version
+	if err := p.GetConnection(ctx).
+		Where("identity_id = ? AND version = ? AND nid = ?", identityID, version, p.NetworkID(ctx)).
+		First(&v); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &v, nil
+}
@@ -0,0 +1,35 @@
+CREATE TABLE identity_login_lockouts
+(
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    scope VARCHAR(16) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    credentials_type VARCHAR(32) NOT NULL DEFAULT '',
+    identity_id UUID NULL DEFAULT NULL,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    last_failed_at timestamp NOT NULL,
+    locked_until timestamp NULL DEFAULT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT identity_login_lockouts_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE,
+    CONSTRAINT identity_login_lockouts_identities_id_fk
+        FOREIGN KEY (identity_id)
+        REFERENCES identities (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT * FROM identity_login_lockouts
+--   WHERE nid = ? AND scope = ? AND subject = ? AND credentials_type = ?
+CREATE UNIQUE INDEX identity_login_lockouts_nid_scope_subject_type_uq_idx ON identity_login_lockouts (nid, scope, subject, credentials_type);
+
+-- Relevant query:
+--   SELECT * FROM identity_login_lockouts WHERE nid = ? AND identity_id = ?
+CREATE INDEX identity_login_lockouts_nid_identity_id_idx ON identity_login_lockouts (nid, identity_id);
+
+-- Relevant query:
+--   DELETE FROM identity_login_lockouts WHERE last_failed_at <= ? AND ... AND nid = ?
+CREATE INDEX identity_login_lockouts_nid_last_failed_at_idx ON identity_login_lockouts (nid, last_failed_at);
@@ -0,0 +1 @@
+ALTER TABLE sessions DROP COLUMN impersonation;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE sessions
+    ADD COLUMN impersonation JSON;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE sessions
+    ADD COLUMN impersonation JSONB;
\ No newline at end of file
@@ -0,0 +1,34 @@
+CREATE TABLE selfservice_login_device_authorizations
+(
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    device_code VARCHAR(64) NOT NULL,
+    user_code VARCHAR(16) NOT NULL,
+    state VARCHAR(16) NOT NULL,
+    identity_id UUID NULL DEFAULT NULL,
+    session_id UUID NULL DEFAULT NULL,
+    expires_at timestamp NOT NULL,
+    last_polled_at timestamp NULL DEFAULT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT selfservice_login_device_authorizations_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE,
+    CONSTRAINT selfservice_login_device_authorizations_identities_id_fk
+        FOREIGN KEY (identity_id)
+        REFERENCES identities (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT * FROM selfservice_login_device_authorizations WHERE device_code = ? AND nid = ?
+CREATE UNIQUE INDEX selfservice_login_device_authorizations_device_code_uq_idx ON selfservice_login_device_authorizations (device_code, nid);
+
+-- Relevant query:
+--   SELECT * FROM selfservice_login_device_authorizations WHERE user_code = ? AND nid = ?
+CREATE UNIQUE INDEX selfservice_login_device_authorizations_user_code_uq_idx ON selfservice_login_device_authorizations (user_code, nid);
+
+-- Relevant query:
+--   DELETE FROM selfservice_login_device_authorizations WHERE expires_at <= ? AND nid = ?
+CREATE INDEX selfservice_login_device_authorizations_nid_expires_at_idx ON selfservice_login_device_authorizations (nid, expires_at);
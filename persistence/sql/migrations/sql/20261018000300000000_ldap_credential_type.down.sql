@@ -0,0 +1 @@
+DELETE FROM identity_credential_types WHERE name = 'ldap';
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO identity_credential_types (id, name)
+SELECT 'cf2f0f1a-0646-44bc-b69e-3a2223bf171e', 'ldap'
+    WHERE NOT EXISTS ( SELECT * FROM identity_credential_types WHERE name = 'ldap');
\ No newline at end of file
@@ -0,0 +1,31 @@
+CREATE TABLE audit_events
+(
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    identity_id CHAR(36) NULL DEFAULT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    session_id CHAR(36) NULL DEFAULT NULL,
+    flow_id CHAR(36) NULL DEFAULT NULL,
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL,
+    attributes JSON NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT audit_events_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT * FROM audit_events WHERE nid = ? ORDER BY created_at DESC, id ASC
+--   DELETE FROM audit_events WHERE created_at < ? AND nid = ?
+CREATE INDEX audit_events_nid_created_at_id_idx ON audit_events (nid, created_at, id);
+
+-- Relevant query:
+--   SELECT * FROM audit_events WHERE nid = ? AND identity_id = ? ORDER BY created_at DESC, id ASC
+CREATE INDEX audit_events_nid_identity_id_created_at_id_idx ON audit_events (nid, identity_id, created_at, id);
+
+-- Relevant query:
+--   SELECT * FROM audit_events WHERE nid = ? AND event IN (?) ORDER BY created_at DESC, id ASC
+CREATE INDEX audit_events_nid_event_created_at_id_idx ON audit_events (nid, event, created_at, id);
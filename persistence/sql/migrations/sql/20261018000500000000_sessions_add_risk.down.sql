@@ -0,0 +1 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS risk;
\ No newline at end of file
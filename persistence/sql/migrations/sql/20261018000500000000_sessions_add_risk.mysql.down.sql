@@ -0,0 +1 @@
+ALTER TABLE sessions DROP COLUMN risk;
\ No newline at end of file
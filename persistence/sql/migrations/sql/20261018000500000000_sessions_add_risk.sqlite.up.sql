@@ -0,0 +1,2 @@
+ALTER TABLE sessions
+    ADD COLUMN risk JSONB;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE webhook_deliveries
+(
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    webhook_id VARCHAR(255) NOT NULL DEFAULT '',
+    trigger_id CHAR(36) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    url TEXT NOT NULL,
+    header JSON NOT NULL,
+    body TEXT NOT NULL,
+    send_count INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT webhook_deliveries_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT * FROM webhook_deliveries WHERE nid = ? AND status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at ASC
+CREATE INDEX webhook_deliveries_nid_status_next_attempt_at_idx ON webhook_deliveries (nid, status, next_attempt_at);
+
+-- Relevant query:
+--   SELECT * FROM webhook_deliveries WHERE nid = ? ORDER BY created_at DESC, id ASC
+--   DELETE FROM webhook_deliveries WHERE nid = ? AND status = ? AND created_at < ?
+CREATE INDEX webhook_deliveries_nid_created_at_id_idx ON webhook_deliveries (nid, created_at, id);
@@ -0,0 +1 @@
+ALTER TABLE webhook_deliveries DROP COLUMN auth;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries
+    ADD COLUMN auth TEXT NULL;
\ No newline at end of file
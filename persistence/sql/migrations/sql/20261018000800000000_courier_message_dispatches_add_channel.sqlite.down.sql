@@ -0,0 +1 @@
+ALTER TABLE courier_message_dispatches DROP COLUMN channel;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE courier_message_dispatches
+    ADD COLUMN IF NOT EXISTS channel VARCHAR(64) NULL;
\ No newline at end of file
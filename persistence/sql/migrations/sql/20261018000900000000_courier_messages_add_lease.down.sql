@@ -0,0 +1,2 @@
+ALTER TABLE courier_messages DROP COLUMN IF EXISTS lease_expires_at;
+ALTER TABLE courier_messages DROP COLUMN IF EXISTS worker_id;
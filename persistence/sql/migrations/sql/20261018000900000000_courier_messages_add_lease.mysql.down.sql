@@ -0,0 +1,2 @@
+ALTER TABLE courier_messages DROP COLUMN lease_expires_at;
+ALTER TABLE courier_messages DROP COLUMN worker_id;
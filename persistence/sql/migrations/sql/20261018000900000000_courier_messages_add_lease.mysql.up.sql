@@ -0,0 +1,3 @@
+ALTER TABLE courier_messages
+    ADD COLUMN worker_id VARCHAR(255) NULL,
+    ADD COLUMN lease_expires_at timestamp NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE courier_messages
+    ADD COLUMN worker_id VARCHAR(255) NULL;
+ALTER TABLE courier_messages
+    ADD COLUMN lease_expires_at timestamp NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE courier_messages
+    ADD COLUMN IF NOT EXISTS worker_id VARCHAR(255) NULL,
+    ADD COLUMN IF NOT EXISTS lease_expires_at timestamp NULL;
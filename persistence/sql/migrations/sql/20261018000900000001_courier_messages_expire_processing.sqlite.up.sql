@@ -0,0 +1,3 @@
+-- Messages which are already processing have been claimed without a lease.
+-- Let the lease expire right away so that they are requeued.
+UPDATE courier_messages SET lease_expires_at = CURRENT_TIMESTAMP WHERE status = 3 AND lease_expires_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE selfservice_verification_flows DROP COLUMN locale;
+ALTER TABLE selfservice_recovery_flows DROP COLUMN locale;
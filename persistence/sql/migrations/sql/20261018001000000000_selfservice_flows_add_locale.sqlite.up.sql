@@ -0,0 +1,4 @@
+ALTER TABLE selfservice_recovery_flows
+    ADD COLUMN locale VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE selfservice_verification_flows
+    ADD COLUMN locale VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,21 @@
+CREATE TABLE courier_suppressions
+(
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT courier_suppressions_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT * FROM courier_suppressions WHERE nid = ? AND recipient = ?
+CREATE UNIQUE INDEX courier_suppressions_nid_recipient_uq_idx ON courier_suppressions (nid, recipient);
+
+-- Relevant query:
+--   SELECT * FROM courier_suppressions WHERE nid = ? ORDER BY created_at DESC, id ASC
+CREATE INDEX courier_suppressions_nid_created_at_id_idx ON courier_suppressions (nid, created_at DESC, id);
@@ -0,0 +1,2 @@
+ALTER TABLE courier_message_dispatches DROP COLUMN IF EXISTS delivery_status;
+ALTER TABLE courier_message_dispatches DROP COLUMN IF EXISTS provider_message_id;
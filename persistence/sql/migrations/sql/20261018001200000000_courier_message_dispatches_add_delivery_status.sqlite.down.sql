@@ -0,0 +1,2 @@
+ALTER TABLE courier_message_dispatches DROP COLUMN delivery_status;
+ALTER TABLE courier_message_dispatches DROP COLUMN provider_message_id;
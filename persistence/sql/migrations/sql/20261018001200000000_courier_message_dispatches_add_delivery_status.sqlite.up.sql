@@ -0,0 +1,4 @@
+ALTER TABLE courier_message_dispatches
+    ADD COLUMN provider_message_id VARCHAR(255) NULL;
+ALTER TABLE courier_message_dispatches
+    ADD COLUMN delivery_status VARCHAR(16) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE courier_message_dispatches
+    ADD COLUMN IF NOT EXISTS provider_message_id VARCHAR(255) NULL;
+ALTER TABLE courier_message_dispatches
+    ADD COLUMN IF NOT EXISTS delivery_status VARCHAR(16) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS courier_message_dispatches_nid_provider_message_id_idx;
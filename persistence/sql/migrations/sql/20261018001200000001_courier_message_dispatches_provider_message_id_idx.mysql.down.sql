@@ -0,0 +1 @@
+DROP INDEX courier_message_dispatches_nid_provider_message_id_idx ON courier_message_dispatches;
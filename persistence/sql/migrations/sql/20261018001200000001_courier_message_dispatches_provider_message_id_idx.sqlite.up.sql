@@ -0,0 +1,3 @@
+-- Relevant query:
+--   SELECT * FROM courier_message_dispatches WHERE nid = ? AND provider_message_id = ? AND channel = ?
+CREATE INDEX courier_message_dispatches_nid_provider_message_id_idx ON courier_message_dispatches (nid, provider_message_id);
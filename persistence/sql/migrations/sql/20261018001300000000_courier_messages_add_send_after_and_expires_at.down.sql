@@ -0,0 +1,2 @@
+ALTER TABLE courier_messages DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE courier_messages DROP COLUMN IF EXISTS send_after;
@@ -0,0 +1,2 @@
+ALTER TABLE courier_messages DROP COLUMN expires_at;
+ALTER TABLE courier_messages DROP COLUMN send_after;
@@ -0,0 +1,3 @@
+ALTER TABLE courier_messages
+    ADD COLUMN send_after timestamp NULL,
+    ADD COLUMN expires_at timestamp NULL;
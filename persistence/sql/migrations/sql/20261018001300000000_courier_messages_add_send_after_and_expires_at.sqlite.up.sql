@@ -0,0 +1,4 @@
+ALTER TABLE courier_messages
+    ADD COLUMN send_after timestamp NULL;
+ALTER TABLE courier_messages
+    ADD COLUMN expires_at timestamp NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE courier_messages
+    ADD COLUMN IF NOT EXISTS send_after timestamp NULL,
+    ADD COLUMN IF NOT EXISTS expires_at timestamp NULL;
@@ -0,0 +1,28 @@
+CREATE TABLE identity_versions
+(
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    identity_id UUID NOT NULL,
+    version INT NOT NULL,
+    schema_id VARCHAR(2048) NOT NULL,
+    state VARCHAR(255) NOT NULL,
+    traits JSON NOT NULL,
+    metadata_public JSON NULL,
+    metadata_admin JSON NULL,
+    credential_types JSON NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    CONSTRAINT identity_versions_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE,
+    CONSTRAINT identity_versions_identities_id_fk
+        FOREIGN KEY (identity_id)
+        REFERENCES identities (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT * FROM identity_versions WHERE nid = ? AND identity_id = ? ORDER BY version DESC
+--   SELECT * FROM identity_versions WHERE nid = ? AND identity_id = ? AND version = ?
+CREATE UNIQUE INDEX identity_versions_nid_identity_id_version_uq_idx ON identity_versions (nid, identity_id, version);
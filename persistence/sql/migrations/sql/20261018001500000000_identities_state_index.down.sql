@@ -0,0 +1 @@
+DROP INDEX identities_nid_state_state_changed_at_idx;
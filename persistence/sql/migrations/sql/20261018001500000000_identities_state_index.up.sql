@@ -0,0 +1 @@
+CREATE INDEX identities_nid_state_state_changed_at_idx ON identities (nid, state, state_changed_at);
@@ -0,0 +1 @@
+DROP TABLE selfservice_deletion_flows;
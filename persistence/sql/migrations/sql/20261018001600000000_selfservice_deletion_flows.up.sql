@@ -0,0 +1,24 @@
+-- The identity is not a foreign key, because the flow must outlive the
+-- identity it deleted.
+CREATE TABLE selfservice_deletion_flows
+(
+    id UUID NOT NULL PRIMARY KEY,
+    nid UUID NOT NULL,
+    identity_id UUID NOT NULL,
+    type VARCHAR(16) NOT NULL DEFAULT 'browser',
+    request_url TEXT NOT NULL,
+    ui JSONB NULL,
+    state VARCHAR(255) NOT NULL,
+    expires_at timestamp NOT NULL,
+    issued_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    CONSTRAINT selfservice_deletion_flows_networks_id_fk
+        FOREIGN KEY (nid)
+        REFERENCES networks (id)
+        ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+-- Relevant query:
+--   SELECT id FROM selfservice_deletion_flows WHERE expires_at <= ? AND nid = ? ORDER BY expires_at ASC LIMIT ?
+CREATE INDEX selfservice_deletion_flows_nid_expires_at_idx ON selfservice_deletion_flows (nid, expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS lease_expires_at;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS worker_id;
@@ -0,0 +1,3 @@
+ALTER TABLE webhook_deliveries
+    ADD COLUMN worker_id VARCHAR(255) NULL,
+    ADD COLUMN lease_expires_at timestamp NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE webhook_deliveries
+    ADD COLUMN worker_id VARCHAR(255) NULL;
+ALTER TABLE webhook_deliveries
+    ADD COLUMN lease_expires_at timestamp NULL;
@@ -0,0 +1,3 @@
+-- Deliveries which are already processing have been claimed without a lease.
+-- Let the lease expire right away so that they are requeued.
+UPDATE webhook_deliveries SET lease_expires_at = CURRENT_TIMESTAMP WHERE status = 'processing' AND lease_expires_at IS NULL;
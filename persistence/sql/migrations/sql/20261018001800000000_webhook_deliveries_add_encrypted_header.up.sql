@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS encrypted_header TEXT NULL;
@@ -0,0 +1 @@
+DROP INDEX courier_messages_nid_normalized_recipient_created_at_idx;
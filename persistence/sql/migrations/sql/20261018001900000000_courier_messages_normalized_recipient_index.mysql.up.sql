@@ -0,0 +1,4 @@
+-- Relevant queries:
+--   SELECT * FROM courier_messages WHERE nid = ? AND LOWER(TRIM(recipient)) = ? ORDER BY created_at DESC, id ASC
+--   SELECT COUNT(*) FROM courier_messages WHERE nid = ? AND LOWER(TRIM(recipient)) = ? AND created_at >= ? AND status != ?
+CREATE INDEX courier_messages_nid_normalized_recipient_created_at_idx ON courier_messages (nid, (LOWER(TRIM(recipient))), created_at DESC);
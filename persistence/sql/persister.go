@@ -219,6 +219,12 @@ func (p *Persister) CleanupDatabase(ctx context.Context, wait time.Duration, old
 	}
 	time.Sleep(wait)
 
+	p.r.Logger().Println("Cleaning up expired deletion flows")
+	if err := p.DeleteExpiredDeletionFlows(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
 	p.r.Logger().Println("Cleaning up expired verification flows")
 	if err := p.DeleteExpiredVerificationFlows(ctx, currentTime, batchSize); err != nil {
 		return err
@@ -231,7 +237,58 @@ func (p *Persister) CleanupDatabase(ctx context.Context, wait time.Duration, old
 	}
 	time.Sleep(wait)
 
+	p.r.Logger().Println("Cleaning up expired login device authorizations")
+	if err := p.DeleteExpiredLoginDeviceAuthorizations(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
+	p.r.Logger().Println("Cleaning up expired login lockouts")
+	if err := p.DeleteExpiredLoginLockouts(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
+	p.r.Logger().Println("Cleaning up expired audit log events")
+	if err := p.DeleteExpiredAuditEvents(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
+	p.r.Logger().Println("Cleaning up sent webhook deliveries")
+	if err := p.DeleteSentWebhookDeliveries(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
+	p.r.Logger().Println("Purging soft-deleted identities")
+	if err := p.PurgeDeletedIdentities(ctx, currentTime, batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
 	p.r.Logger().Println("Successfully cleaned up the latest batch of the SQL database! " +
 		"This should be re-run periodically, to be sure that all expired data is purged.")
 	return nil
 }
+
+// forUpdateClause returns the locking clause which makes concurrent
+// transactions wait for each other before reading the selected rows. SQLite
+// does not support row locks, but serializes write transactions instead.
+func forUpdateClause(conn *pop.Connection) string {
+	switch conn.Dialect.Name() {
+	case "sqlite3":
+		return ""
+	default:
+		return "FOR UPDATE"
+	}
+}
+
+// skipLockedClause returns the locking clause which makes concurrent
+// transactions skip the rows claimed by each other.
+func skipLockedClause(conn *pop.Connection) string {
+	if c := forUpdateClause(conn); c != "" {
+		return c + " SKIP LOCKED"
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ory/kratos/audit"
+	"github.com/ory/x/otelx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ audit.Persister = new(Persister)
+
+func (p *Persister) CreateAuditEvent(ctx context.Context, e *audit.Event) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateAuditEvent")
+	defer otelx.End(span, &err)
+
+	e.NID = p.NetworkID(ctx)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(e))
+}
+
+func (p *Persister) ListAuditEvents(ctx context.Context, filter audit.ListEventsParameters, opts []keysetpagination.Option) (_ []audit.Event, _ *keysetpagination.Paginator, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListAuditEvents")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx))
+
+	if filter.IdentityID.Valid {
+		q = q.Where("identity_id = ?", filter.IdentityID.UUID)
+	}
+
+	if len(filter.Events) > 0 {
+		q = q.Where("event IN (?)", filter.Events)
+	}
+
+	if filter.Since != nil {
+		q = q.Where("created_at >= ?", *filter.Since)
+	}
+
+	if filter.Until != nil {
+		q = q.Where("created_at < ?", *filter.Until)
+	}
+
+	opts = append(opts, keysetpagination.WithDefaultToken(audit.Event{}.DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(250))
+	paginator := keysetpagination.NewPaginator(opts...)
+
+	events := make([]audit.Event, paginator.Size())
+	if err := q.Scope(keysetpagination.Paginate[audit.Event](paginator)).
+		All(&events); err != nil {
+		return nil, nil, sqlcon.HandleError(err)
+	}
+
+	events, nextPage := keysetpagination.Result(events, paginator)
+	return events, nextPage, nil
+}
+
+func (p *Persister) DeleteExpiredAuditEvents(ctx context.Context, olderThan time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteExpiredAuditEvents")
+	defer otelx.End(span, &err)
+
+	// Events within the retention period are kept, even if they are older than
+	// requested.
+	retention := p.r.Config().SecurityAuditLog(ctx).Retention
+	if retention <= 0 {
+		return nil
+	}
+	if keepFrom := time.Now().UTC().Add(-retention); olderThan.After(keepFrom) {
+		olderThan = keepFrom
+	}
+
+	//#nosec G201 -- TableName is static
+	return sqlcon.HandleError(p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"DELETE FROM %[1]s WHERE id in (SELECT id FROM (SELECT id FROM %[1]s c WHERE created_at < ? AND nid = ? ORDER BY created_at ASC LIMIT ?) AS s)",
+		audit.Event{}.TableName(),
+	),
+		olderThan,
+		p.NetworkID(ctx),
+		limit,
+	).Exec())
+}
@@ -8,10 +8,19 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ory/kratos/audit"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/webhook"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
 )
 
 func TestPersister_Cleanup(t *testing.T) {
@@ -139,6 +148,24 @@ func TestPersister_Settings_Cleanup(t *testing.T) {
 	})
 }
 
+func TestPersister_Deletion_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	p := reg.Persister()
+	currentTime := time.Now()
+	ctx := context.Background()
+
+	t.Run("case=should not throw error on cleanup deletion flows", func(t *testing.T) {
+		assert.Nil(t, p.DeleteExpiredDeletionFlows(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+
+	t.Run("case=should throw error on cleanup deletion flows", func(t *testing.T) {
+		require.NoError(t, p.GetConnection(ctx).Close())
+		assert.Error(t, p.DeleteExpiredDeletionFlows(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+}
+
 func TestPersister_Verification_Cleanup(t *testing.T) {
 	t.Parallel()
 
@@ -174,3 +201,164 @@ func TestPersister_SessionTokenExchange_Cleanup(t *testing.T) {
 		assert.Error(t, p.DeleteExpiredExchangers(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
 	})
 }
+
+func TestPersister_LoginLockout_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	p := reg.Persister()
+	currentTime := time.Now()
+	ctx := context.Background()
+
+	t.Run("case=should not throw error on cleanup login lockouts", func(t *testing.T) {
+		assert.Nil(t, p.DeleteExpiredLoginLockouts(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+
+	t.Run("case=should throw error on cleanup login lockouts", func(t *testing.T) {
+		require.NoError(t, p.GetConnection(ctx).Close())
+		assert.Error(t, p.DeleteExpiredLoginLockouts(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+}
+
+func TestPersister_LoginDeviceAuthorization_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	p := reg.Persister()
+	currentTime := time.Now()
+	ctx := context.Background()
+
+	t.Run("case=should not throw error on cleanup login device authorizations", func(t *testing.T) {
+		assert.Nil(t, p.DeleteExpiredLoginDeviceAuthorizations(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+
+	t.Run("case=should throw error on cleanup login device authorizations", func(t *testing.T) {
+		require.NoError(t, p.GetConnection(ctx).Close())
+		assert.Error(t, p.DeleteExpiredLoginDeviceAuthorizations(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+}
+
+func TestPersister_AuditEvent_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	p := reg.Persister()
+	currentTime := time.Now()
+	ctx := context.Background()
+
+	t.Run("case=should only remove events older than the retention", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySecurityAuditLogRetention, "720h")
+
+		expired := audit.Event{Event: "IdentityCreated", Attributes: []byte("{}"), CreatedAt: currentTime.Add(-31 * 24 * time.Hour).UTC()}
+		retained := audit.Event{Event: "IdentityCreated", Attributes: []byte("{}"), CreatedAt: currentTime.Add(-29 * 24 * time.Hour).UTC()}
+		require.NoError(t, p.CreateAuditEvent(ctx, &expired))
+		require.NoError(t, p.CreateAuditEvent(ctx, &retained))
+
+		require.NoError(t, p.DeleteExpiredAuditEvents(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+
+		actual, _, err := p.ListAuditEvents(ctx, audit.ListEventsParameters{}, nil)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, retained.ID, actual[0].ID)
+	})
+
+	t.Run("case=should not throw error on cleanup audit events", func(t *testing.T) {
+		assert.Nil(t, p.DeleteExpiredAuditEvents(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+
+	t.Run("case=should throw error on cleanup audit events", func(t *testing.T) {
+		require.NoError(t, p.GetConnection(ctx).Close())
+		assert.Error(t, p.DeleteExpiredAuditEvents(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+}
+
+func TestPersister_DeletedIdentity_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	p := reg.Persister()
+	currentTime := time.Now()
+	ctx := testhelpers.WithDefaultIdentitySchema(context.Background(), "file://./stub/identity.schema.json")
+
+	softDeleted := func(t *testing.T, deletedAt time.Time) *identity.Identity {
+		i := identity.NewIdentity("")
+		require.NoError(t, p.CreateIdentity(ctx, i))
+		require.NoError(t, p.SoftDeleteIdentity(ctx, i.ID))
+
+		stateChangedAt := sqlxx.NullTime(deletedAt.UTC())
+		i.StateChangedAt = &stateChangedAt
+		require.NoError(t, p.UpdateIdentityColumns(ctx, i, "state_changed_at"))
+		return i
+	}
+
+	t.Run("case=should only purge identities deleted before the retention", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyIdentitySoftDeleteRetention, "720h")
+
+		expired := softDeleted(t, currentTime.Add(-31*24*time.Hour))
+		retained := softDeleted(t, currentTime.Add(-29*24*time.Hour))
+
+		require.NoError(t, p.PurgeDeletedIdentities(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+
+		_, err := p.GetIdentity(ctx, expired.ID, identity.ExpandNothing)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows)
+		actual, err := p.GetIdentity(ctx, retained.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Equal(t, identity.StateDeleted, actual.State)
+	})
+
+	t.Run("case=should not purge identities without retention", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyIdentitySoftDeleteRetention, "0s")
+
+		retained := softDeleted(t, currentTime.Add(-365*24*time.Hour))
+
+		require.NoError(t, p.PurgeDeletedIdentities(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+
+		_, err := p.GetIdentity(ctx, retained.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+	})
+
+	t.Run("case=should throw error on purge deleted identities", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyIdentitySoftDeleteRetention, "720h")
+		require.NoError(t, p.GetConnection(ctx).Close())
+		assert.Error(t, p.PurgeDeletedIdentities(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+}
+
+func TestPersister_WebhookDelivery_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	p := reg.Persister()
+	currentTime := time.Now()
+	ctx := context.Background()
+
+	t.Run("case=should only remove old sent deliveries", func(t *testing.T) {
+		newDelivery := func(status webhook.DeliveryStatus, createdAt time.Time) *webhook.Delivery {
+			d := &webhook.Delivery{Method: "POST", URL: "https://www.ory.sh/", TriggerID: x.NewUUID(), NextAttemptAt: createdAt, CreatedAt: createdAt}
+			require.NoError(t, p.AddWebhookDelivery(ctx, d))
+			d.Status = status
+			require.NoError(t, p.GetConnection(ctx).RawQuery("UPDATE webhook_deliveries SET status = ? WHERE id = ?", status, d.ID).Exec())
+			return d
+		}
+
+		newDelivery(webhook.DeliveryStatusSent, currentTime.Add(-2*time.Hour).UTC())
+		abandoned := newDelivery(webhook.DeliveryStatusAbandoned, currentTime.Add(-2*time.Hour).UTC())
+		recent := newDelivery(webhook.DeliveryStatusSent, currentTime.UTC())
+
+		require.NoError(t, p.DeleteSentWebhookDeliveries(ctx, currentTime.Add(-time.Hour), reg.Config().DatabaseCleanupBatchSize(ctx)))
+
+		actual, _, err := p.ListWebhookDeliveries(ctx, webhook.ListDeliveriesParameters{}, nil)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.ElementsMatch(t, []uuid.UUID{abandoned.ID, recent.ID}, []uuid.UUID{actual[0].ID, actual[1].ID})
+	})
+
+	t.Run("case=should not throw error on cleanup webhook deliveries", func(t *testing.T) {
+		assert.Nil(t, p.DeleteSentWebhookDeliveries(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+
+	t.Run("case=should throw error on cleanup webhook deliveries", func(t *testing.T) {
+		require.NoError(t, p.GetConnection(ctx).Close())
+		assert.Error(t, p.DeleteSentWebhookDeliveries(ctx, currentTime, reg.Config().DatabaseCleanupBatchSize(ctx)))
+	})
+}
@@ -7,17 +7,22 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/persistence/sql/update"
 	"github.com/ory/pop/v6"
 	"github.com/ory/x/otelx"
 	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/popx"
 	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
 	"github.com/ory/x/uuidx"
 )
 
@@ -28,7 +33,9 @@ func (p *Persister) AddMessage(ctx context.Context, m *courier.Message) (err err
 	defer otelx.End(span, &err)
 
 	m.NID = p.NetworkID(ctx)
-	m.Status = courier.MessageStatusQueued
+	if m.Status != courier.MessageStatusSuppressed {
+		m.Status = courier.MessageStatusQueued
+	}
 	return sqlcon.HandleError(p.GetConnection(ctx).Create(m)) // do not create eager to avoid identity injection.
 }
 
@@ -43,7 +50,11 @@ func (p *Persister) ListMessages(ctx context.Context, filter courier.ListCourier
 	}
 
 	if filter.Recipient != "" {
-		q = q.Where("recipient=?", filter.Recipient)
+		q = q.Where("LOWER(TRIM(recipient))=?", courier.NormalizeRecipient(filter.Recipient))
+	}
+
+	if filter.TemplateType != "" {
+		q = q.Where("template_type=?", filter.TemplateType)
 	}
 
 	opts = append(opts, keysetpagination.WithDefaultToken(courier.Message{}.DefaultPageToken()))
@@ -61,20 +72,28 @@ func (p *Persister) ListMessages(ctx context.Context, filter courier.ListCourier
 	return messages, nextPage, nil
 }
 
-func (p *Persister) NextMessages(ctx context.Context, limit uint8) (messages []courier.Message, err error) {
+func (p *Persister) NextMessages(ctx context.Context, limit uint8, workerID string, lease time.Duration) (messages []courier.Message, err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.NextMessages")
 	defer otelx.End(span, &err)
 
 	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		now := time.Now().UTC()
+
 		var m []courier.Message
-		if err := tx.
-			Where("nid = ? AND status = ?",
-				p.NetworkID(ctx),
-				courier.MessageStatusQueued,
-			).
-			Order("created_at ASC").
-			Limit(int(limit)).
-			All(&m); err != nil {
+		//#nosec G201 -- TableName and the locking clause are static
+		if err := tx.RawQuery(fmt.Sprintf(
+			"SELECT %s FROM %s WHERE nid = ? AND (status = ? OR (status = ? AND lease_expires_at < ?)) AND (send_after IS NULL OR send_after <= ?) ORDER BY created_at ASC LIMIT ? %s",
+			popx.DBColumns[courier.Message](tx.Dialect),
+			courier.Message{}.TableName(),
+			skipLockedClause(tx),
+		),
+			p.NetworkID(ctx),
+			courier.MessageStatusQueued,
+			courier.MessageStatusProcessing,
+			now,
+			now,
+			int(limit),
+		).All(&m); err != nil {
 			return err
 		}
 
@@ -85,7 +104,9 @@ func (p *Persister) NextMessages(ctx context.Context, limit uint8) (messages []c
 		for i := range m {
 			message := &m[i]
 			message.Status = courier.MessageStatusProcessing
-			if err := update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), message, "status"); err != nil {
+			message.WorkerID = sqlxx.NullString(workerID)
+			message.LeaseExpiresAt = sqlxx.NullTime(now.Add(lease))
+			if err := update.Generic(ctx, tx, p.r.Tracer(ctx).Tracer(), message, "status", "worker_id", "lease_expires_at"); err != nil {
 				return err
 			}
 		}
@@ -127,42 +148,115 @@ func (p *Persister) LatestQueuedMessage(ctx context.Context) (_ *courier.Message
 	return &m, nil
 }
 
-func (p *Persister) SetMessageStatus(ctx context.Context, id uuid.UUID, ms courier.MessageStatus) (err error) {
+func (p *Persister) RenewMessageLease(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RenewMessageLease")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET lease_expires_at = ? WHERE id = ? AND nid = ? AND status = ? AND worker_id = ?",
+		time.Now().UTC().Add(lease),
+		id,
+		p.NetworkID(ctx),
+		courier.MessageStatusProcessing,
+		workerID,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(courier.ErrLeaseLost)
+	}
+
+	return nil
+}
+
+func (p *Persister) SetMessageStatus(ctx context.Context, id uuid.UUID, workerID string, ms courier.MessageStatus) (err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.SetMessageStatus")
 	defer otelx.End(span, &err)
 
 	count, err := p.GetConnection(ctx).RawQuery(
-		"UPDATE courier_messages SET status = ? WHERE id = ? AND nid = ?",
+		"UPDATE courier_messages SET status = ? WHERE id = ? AND nid = ? AND status = ? AND worker_id = ?",
 		ms,
 		id,
 		p.NetworkID(ctx),
+		courier.MessageStatusProcessing,
+		workerID,
 	).ExecWithCount()
 	if err != nil {
 		return sqlcon.HandleError(err)
 	}
 
 	if count == 0 {
-		return errors.WithStack(sqlcon.ErrNoRows)
+		return errors.WithStack(courier.ErrLeaseLost)
 	}
 
 	return nil
 }
 
-func (p *Persister) IncrementMessageSendCount(ctx context.Context, id uuid.UUID) (err error) {
+func (p *Persister) IncrementMessageSendCount(ctx context.Context, id uuid.UUID, workerID string) (err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.IncrementMessageSendCount")
 	defer otelx.End(span, &err)
 
 	count, err := p.GetConnection(ctx).RawQuery(
-		"UPDATE courier_messages SET send_count = send_count + 1 WHERE id = ? AND nid = ?",
+		"UPDATE courier_messages SET send_count = send_count + 1 WHERE id = ? AND nid = ? AND status = ? AND worker_id = ?",
 		id,
 		p.NetworkID(ctx),
+		courier.MessageStatusProcessing,
+		workerID,
 	).ExecWithCount()
 	if err != nil {
 		return sqlcon.HandleError(err)
 	}
 
 	if count == 0 {
-		return errors.WithStack(sqlcon.ErrNoRows)
+		return errors.WithStack(courier.ErrLeaseLost)
+	}
+
+	return nil
+}
+
+func (p *Persister) CancelMessage(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CancelMessage")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET status = ?, updated_at = ? WHERE id = ? AND nid = ? AND status = ?",
+		courier.MessageStatusCancelled,
+		time.Now().UTC(),
+		id,
+		p.NetworkID(ctx),
+		courier.MessageStatusQueued,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(courier.ErrMessageStatusChanged)
+	}
+
+	return nil
+}
+
+func (p *Persister) RequeueMessage(ctx context.Context, id uuid.UUID, statuses []courier.MessageStatus) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RequeueMessage")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET status = ?, send_count = 0, worker_id = NULL, lease_expires_at = NULL, updated_at = ? WHERE id = ? AND nid = ? AND status IN (?)",
+		courier.MessageStatusQueued,
+		time.Now().UTC(),
+		id,
+		p.NetworkID(ctx),
+		statuses,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(courier.ErrMessageStatusChanged)
 	}
 
 	return nil
@@ -183,23 +277,23 @@ func (p *Persister) FetchMessage(ctx context.Context, msgID uuid.UUID) (_ *couri
 	return &message, nil
 }
 
-func (p *Persister) RecordDispatch(ctx context.Context, msgID uuid.UUID, status courier.CourierMessageDispatchStatus, err error) error {
+func (p *Persister) RecordDispatch(ctx context.Context, msgID uuid.UUID, channel string, providerMessageID string, status courier.CourierMessageDispatchStatus, err error) error {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RecordDispatch")
 	defer otelx.End(span, &err)
 
 	dispatch := courier.MessageDispatch{
-		ID:        uuidx.NewV4(),
-		MessageID: msgID,
-		Status:    status,
-		NID:       p.NetworkID(ctx),
+		ID:                uuidx.NewV4(),
+		MessageID:         msgID,
+		Channel:           sqlxx.NullString(channel),
+		ProviderMessageID: sqlxx.NullString(providerMessageID),
+		Status:            status,
+		NID:               p.NetworkID(ctx),
 	}
 
 	if err != nil {
-		// We use herodot as a carrier for the error's data
-		her := herodot.ToDefaultError(err, "")
-		content, mErr := json.Marshal(her)
+		content, mErr := marshalDispatchError(err)
 		if mErr != nil {
-			return errors.WithStack(mErr)
+			return mErr
 		}
 		dispatch.Error = content
 	}
@@ -210,3 +304,127 @@ func (p *Persister) RecordDispatch(ctx context.Context, msgID uuid.UUID, status
 
 	return nil
 }
+
+func (p *Persister) CountRecipientMessages(ctx context.Context, recipient string, templateType template.TemplateType, since time.Time) (_ int, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CountRecipientMessages")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ? AND LOWER(TRIM(recipient)) = ? AND created_at >= ? AND status != ?",
+		p.NetworkID(ctx),
+		courier.NormalizeRecipient(recipient),
+		since.UTC(),
+		courier.MessageStatusSuppressed,
+	)
+	if templateType != "" {
+		q = q.Where("template_type = ?", templateType)
+	}
+
+	count, err := q.Count(new(courier.Message))
+	if err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+	return count, nil
+}
+
+func (p *Persister) CreateSuppression(ctx context.Context, s *courier.Suppression) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateSuppression")
+	defer otelx.End(span, &err)
+
+	s.NID = p.NetworkID(ctx)
+	s.Recipient = courier.NormalizeRecipient(s.Recipient)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(s))
+}
+
+func (p *Persister) FindSuppressionByRecipient(ctx context.Context, recipient string) (_ *courier.Suppression, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.FindSuppressionByRecipient")
+	defer otelx.End(span, &err)
+
+	var s courier.Suppression
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND recipient = ?", p.NetworkID(ctx), courier.NormalizeRecipient(recipient)).
+		First(&s); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &s, nil
+}
+
+func (p *Persister) ListSuppressions(ctx context.Context, filter courier.ListCourierSuppressionsParameters, opts []keysetpagination.Option) (_ []courier.Suppression, _ *keysetpagination.Paginator, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListSuppressions")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx))
+	if filter.Recipient != "" {
+		q = q.Where("recipient = ?", courier.NormalizeRecipient(filter.Recipient))
+	}
+
+	opts = append(opts, keysetpagination.WithDefaultToken(courier.Suppression{}.DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(10))
+	paginator := keysetpagination.NewPaginator(opts...)
+
+	suppressions := make([]courier.Suppression, paginator.Size())
+	if err := q.Scope(keysetpagination.Paginate[courier.Suppression](paginator)).
+		All(&suppressions); err != nil {
+		return nil, nil, sqlcon.HandleError(err)
+	}
+
+	suppressions, nextPage := keysetpagination.Result(suppressions, paginator)
+	return suppressions, nextPage, nil
+}
+
+func (p *Persister) DeleteSuppression(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteSuppression")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"DELETE FROM courier_suppressions WHERE id = ? AND nid = ?",
+		id,
+		p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (p *Persister) UpdateDispatchDeliveryStatus(ctx context.Context, channel string, providerMessageID string, status courier.CourierMessageDeliveryStatus, deliveryErr error) (_ *courier.MessageDispatch, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateDispatchDeliveryStatus")
+	defer otelx.End(span, &err)
+
+	var dispatch courier.MessageDispatch
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND provider_message_id = ? AND channel = ?", p.NetworkID(ctx), providerMessageID, channel).
+		First(&dispatch); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	dispatch.DeliveryStatus = status
+	dispatch.UpdatedAt = time.Now().UTC()
+	columns := []string{"delivery_status", "updated_at"}
+	if deliveryErr != nil {
+		content, err := marshalDispatchError(deliveryErr)
+		if err != nil {
+			return nil, err
+		}
+		dispatch.Error = content
+		columns = append(columns, "error")
+	}
+
+	if err := update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), &dispatch, columns...); err != nil {
+		return nil, err
+	}
+	return &dispatch, nil
+}
+
+// marshalDispatchError uses herodot as a carrier for the error's data.
+func marshalDispatchError(err error) ([]byte, error) {
+	content, mErr := json.Marshal(herodot.ToDefaultError(err, ""))
+	if mErr != nil {
+		return nil, errors.WithStack(mErr)
+	}
+	return content, nil
+}
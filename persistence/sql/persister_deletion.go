@@ -0,0 +1,64 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/kratos/persistence/sql/update"
+	"github.com/ory/kratos/selfservice/flow/deletion"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ deletion.FlowPersister = new(Persister)
+
+func (p *Persister) CreateDeletionFlow(ctx context.Context, r *deletion.Flow) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateDeletionFlow")
+	defer otelx.End(span, &err)
+
+	r.NID = p.NetworkID(ctx)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(r))
+}
+
+func (p *Persister) GetDeletionFlow(ctx context.Context, id uuid.UUID) (_ *deletion.Flow, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetDeletionFlow")
+	defer otelx.End(span, &err)
+
+	var r deletion.Flow
+	if err := p.GetConnection(ctx).Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).First(&r); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return &r, nil
+}
+
+func (p *Persister) UpdateDeletionFlow(ctx context.Context, r *deletion.Flow) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateDeletionFlow")
+	defer otelx.End(span, &err)
+
+	cp := *r
+	cp.NID = p.NetworkID(ctx)
+	return update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), cp)
+}
+
+func (p *Persister) DeleteExpiredDeletionFlows(ctx context.Context, expiresAt time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteExpiredDeletionFlows")
+	defer otelx.End(span, &err)
+	//#nosec G201 -- TableName is static
+	err = p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"DELETE FROM %[1]s WHERE id in (SELECT id FROM (SELECT id FROM %[1]s c WHERE expires_at <= ? and nid = ? ORDER BY expires_at ASC LIMIT ?) AS s)",
+		deletion.Flow{}.TableName(),
+	),
+		expiresAt,
+		p.NetworkID(ctx),
+		limit,
+	).Exec()
+
+	return sqlcon.HandleError(err)
+}
@@ -0,0 +1,143 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/popx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ identity.LoginLockoutPersister = new(Persister)
+
+func (p *Persister) GetLoginLockout(ctx context.Context, scope identity.LoginLockoutScope, subject string, ct identity.CredentialsType) (_ *identity.LoginLockout, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetLoginLockout")
+	defer otelx.End(span, &err)
+
+	var l identity.LoginLockout
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND scope = ? AND subject = ? AND credentials_type = ?", p.NetworkID(ctx), scope, subject, ct).
+		First(&l); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return &l, nil
+}
+
+func (p *Persister) UpdateLoginLockout(ctx context.Context, scope identity.LoginLockoutScope, subject string, ct identity.CredentialsType, update func(*identity.LoginLockout)) (_ *identity.LoginLockout, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateLoginLockout")
+	defer otelx.End(span, &err)
+
+	var l identity.LoginLockout
+	upsert := func() error {
+		return p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+			// The row is locked so that concurrent failed attempts are counted
+			// one after the other instead of overwriting each other.
+			l = identity.LoginLockout{}
+			//#nosec G201 -- TableName and the locking clause are static
+			if err := tx.RawQuery(fmt.Sprintf(
+				"SELECT %s FROM %s WHERE nid = ? AND scope = ? AND subject = ? AND credentials_type = ? %s",
+				popx.DBColumns[identity.LoginLockout](tx.Dialect),
+				identity.LoginLockout{}.TableName(),
+				forUpdateClause(tx),
+			),
+				p.NetworkID(ctx), scope, subject, ct,
+			).First(&l); errors.Is(sqlcon.HandleError(err), sqlcon.ErrNoRows) {
+				l = identity.LoginLockout{
+					NID:             p.NetworkID(ctx),
+					Scope:           scope,
+					Subject:         subject,
+					CredentialsType: ct,
+				}
+				update(&l)
+				return sqlcon.HandleError(tx.Create(&l))
+			} else if err != nil {
+				return sqlcon.HandleError(err)
+			}
+
+			update(&l)
+			return sqlcon.HandleError(tx.Update(&l))
+		})
+	}
+
+	// Two concurrent failed attempts may both try to create the counter. The
+	// one that loses the race retries and updates the row created by the other.
+	if err := upsert(); errors.Is(err, sqlcon.ErrUniqueViolation) {
+		if err := upsert(); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+func (p *Persister) ListLoginLockoutsByIdentity(ctx context.Context, identityID uuid.UUID) (_ []identity.LoginLockout, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListLoginLockoutsByIdentity")
+	defer otelx.End(span, &err)
+
+	ls := make([]identity.LoginLockout, 0)
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND identity_id = ?", p.NetworkID(ctx), identityID).
+		Order("last_failed_at DESC").
+		All(&ls); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return ls, nil
+}
+
+func (p *Persister) DeleteLoginLockout(ctx context.Context, scope identity.LoginLockoutScope, subject string, ct identity.CredentialsType) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteLoginLockout")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	return sqlcon.HandleError(p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("DELETE FROM %s WHERE nid = ? AND scope = ? AND subject = ? AND credentials_type = ?", identity.LoginLockout{}.TableName()),
+		p.NetworkID(ctx), scope, subject, ct,
+	).Exec())
+}
+
+func (p *Persister) DeleteLoginLockoutsByIdentity(ctx context.Context, identityID uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteLoginLockoutsByIdentity")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	return sqlcon.HandleError(p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("DELETE FROM %s WHERE nid = ? AND identity_id = ?", identity.LoginLockout{}.TableName()),
+		p.NetworkID(ctx), identityID,
+	).Exec())
+}
+
+func (p *Persister) DeleteExpiredLoginLockouts(ctx context.Context, olderThan time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteExpiredLoginLockouts")
+	defer otelx.End(span, &err)
+
+	// Counters which saw a failed attempt within the reset window are still
+	// relevant, even if they are older than requested.
+	if resetAt := time.Now().UTC().Add(-p.r.Config().SecurityLoginLockout(ctx).ResetAfter); olderThan.After(resetAt) {
+		olderThan = resetAt
+	}
+
+	//#nosec G201 -- TableName is static
+	return sqlcon.HandleError(p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"DELETE FROM %[1]s WHERE id in (SELECT id FROM (SELECT id FROM %[1]s c WHERE last_failed_at <= ? AND (locked_until IS NULL OR locked_until <= ?) AND nid = ? ORDER BY last_failed_at ASC LIMIT ?) AS s)",
+		identity.LoginLockout{}.TableName(),
+	),
+		olderThan,
+		time.Now().UTC(),
+		p.NetworkID(ctx),
+		limit,
+	).Exec())
+}
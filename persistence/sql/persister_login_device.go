@@ -0,0 +1,129 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ login.DeviceAuthorizationPersister = new(Persister)
+
+func (p *Persister) CreateLoginDeviceAuthorization(ctx context.Context, a *login.DeviceAuthorization) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateLoginDeviceAuthorization")
+	defer otelx.End(span, &err)
+
+	a.NID = p.NetworkID(ctx)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(a))
+}
+
+func (p *Persister) GetLoginDeviceAuthorizationByDeviceCode(ctx context.Context, deviceCode string) (_ *login.DeviceAuthorization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetLoginDeviceAuthorizationByDeviceCode")
+	defer otelx.End(span, &err)
+
+	var a login.DeviceAuthorization
+	if err := p.GetConnection(ctx).
+		Where("device_code = ? AND device_code <> '' AND nid = ?", deviceCode, p.NetworkID(ctx)).
+		First(&a); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return &a, nil
+}
+
+func (p *Persister) GetLoginDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (_ *login.DeviceAuthorization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetLoginDeviceAuthorizationByUserCode")
+	defer otelx.End(span, &err)
+
+	var a login.DeviceAuthorization
+	if err := p.GetConnection(ctx).
+		Where("user_code = ? AND user_code <> '' AND nid = ?", userCode, p.NetworkID(ctx)).
+		First(&a); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return &a, nil
+}
+
+func (p *Persister) SetLoginDeviceAuthorizationPolledAt(ctx context.Context, id uuid.UUID, polledAt time.Time) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.SetLoginDeviceAuthorizationPolledAt")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET last_polled_at = ?, updated_at = ? WHERE id = ? AND nid = ? AND state = ?", login.DeviceAuthorization{}.TableName()),
+		polledAt, time.Now().UTC(), id, p.NetworkID(ctx), login.DeviceAuthorizationStatePending,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (p *Persister) ResolveLoginDeviceAuthorization(ctx context.Context, a *login.DeviceAuthorization) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ResolveLoginDeviceAuthorization")
+	defer otelx.End(span, &err)
+
+	a.UpdatedAt = time.Now().UTC()
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("UPDATE %s SET state = ?, identity_id = ?, session_id = ?, updated_at = ? WHERE id = ? AND nid = ? AND state = ?", login.DeviceAuthorization{}.TableName()),
+		a.State, a.IdentityID, a.SessionID, a.UpdatedAt, a.ID, p.NetworkID(ctx), login.DeviceAuthorizationStatePending,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (p *Persister) DeleteLoginDeviceAuthorization(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteLoginDeviceAuthorization")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(
+		fmt.Sprintf("DELETE FROM %s WHERE id = ? AND nid = ?", login.DeviceAuthorization{}.TableName()),
+		id, p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (p *Persister) DeleteExpiredLoginDeviceAuthorizations(ctx context.Context, expiresAt time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteExpiredLoginDeviceAuthorizations")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	return sqlcon.HandleError(p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"DELETE FROM %[1]s WHERE id in (SELECT id FROM (SELECT id FROM %[1]s c WHERE expires_at <= ? and nid = ? ORDER BY expires_at ASC LIMIT ?) AS s)",
+		login.DeviceAuthorization{}.TableName(),
+	),
+		expiresAt,
+		p.NetworkID(ctx),
+		limit,
+	).Exec())
+}
@@ -31,6 +31,7 @@ import (
 	sqltesthelpers "github.com/ory/kratos/persistence/sql/testhelpers"
 	"github.com/ory/kratos/schema"
 	errorx "github.com/ory/kratos/selfservice/errorx/test"
+	deletion "github.com/ory/kratos/selfservice/flow/deletion/test"
 	lf "github.com/ory/kratos/selfservice/flow/login"
 	login "github.com/ory/kratos/selfservice/flow/login/test"
 	recovery "github.com/ory/kratos/selfservice/flow/recovery/test"
@@ -210,6 +211,30 @@ func TestPersister(t *testing.T) {
 				wg.Wait()
 			})
 
+			t.Run("racy login lockout updates", func(t *testing.T) {
+				t.Parallel()
+
+				_, reg := internal.NewRegistryDefaultWithDSN(t, dsn)
+				_, p := testhelpers.NewNetwork(t, ctx, reg.Persister())
+
+				const attempts = 20
+				eg := new(errgroup.Group)
+				for range attempts {
+					eg.Go(func() error {
+						_, err := p.UpdateLoginLockout(ctx, ri.LoginLockoutScopeIdentifier, "racy@ory.sh", ri.CredentialsTypePassword, func(l *ri.LoginLockout) {
+							l.FailedAttempts++
+							l.LastFailedAt = time.Now().UTC()
+						})
+						return err
+					})
+				}
+				require.NoError(t, eg.Wait())
+
+				l, err := p.GetLoginLockout(ctx, ri.LoginLockoutScopeIdentifier, "racy@ory.sh", ri.CredentialsTypePassword)
+				require.NoError(t, err)
+				assert.Equal(t, attempts, l.FailedAttempts)
+			})
+
 			t.Run("case=credential types exist", func(t *testing.T) {
 				t.Parallel()
 				_, reg := internal.NewRegistryDefaultWithDSN(t, dsn)
@@ -249,6 +274,12 @@ func TestPersister(t *testing.T) {
 				_, p := testhelpers.NewNetwork(t, ctx, reg.Persister())
 				settings.TestFlowPersister(ctx, p)(t)
 			})
+			t.Run("contract=deletion.TestFlowPersister", func(t *testing.T) {
+				t.Parallel()
+				_, reg := internal.NewRegistryDefaultWithDSN(t, dsn)
+				_, p := testhelpers.NewNetwork(t, ctx, reg.Persister())
+				deletion.TestFlowPersister(ctx, p)(t)
+			})
 			t.Run("contract=session.TestPersister", func(t *testing.T) {
 				// Don't run this in parallel on SQLite as it causes table locks.
 				if name != "sqlite" {
@@ -0,0 +1,232 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/persistence/sql/update"
+	"github.com/ory/kratos/webhook"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/otelx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/popx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+)
+
+var _ webhook.Persister = new(Persister)
+
+func (p *Persister) AddWebhookDelivery(ctx context.Context, d *webhook.Delivery) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.AddWebhookDelivery")
+	defer otelx.End(span, &err)
+
+	d.NID = p.NetworkID(ctx)
+	d.Status = webhook.DeliveryStatusQueued
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(d))
+}
+
+func (p *Persister) NextWebhookDeliveries(ctx context.Context, limit int, workerID string, lease time.Duration) (deliveries []webhook.Delivery, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.NextWebhookDeliveries")
+	defer otelx.End(span, &err)
+
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		now := time.Now().UTC()
+
+		var d []webhook.Delivery
+		//#nosec G201 -- TableName and the locking clause are static
+		if err := tx.RawQuery(fmt.Sprintf(
+			"SELECT %s FROM %s WHERE nid = ? AND (status = ? OR (status = ? AND lease_expires_at < ?)) AND next_attempt_at <= ? ORDER BY next_attempt_at ASC LIMIT ? %s",
+			popx.DBColumns[webhook.Delivery](tx.Dialect),
+			webhook.Delivery{}.TableName(),
+			skipLockedClause(tx),
+		),
+			p.NetworkID(ctx),
+			webhook.DeliveryStatusQueued,
+			webhook.DeliveryStatusProcessing,
+			now,
+			now,
+			limit,
+		).All(&d); err != nil {
+			return err
+		}
+
+		if len(d) == 0 {
+			return sql.ErrNoRows
+		}
+
+		for i := range d {
+			d[i].Status = webhook.DeliveryStatusProcessing
+			d[i].WorkerID = sqlxx.NullString(workerID)
+			d[i].LeaseExpiresAt = sqlxx.NullTime(now.Add(lease))
+			if err := update.Generic(ctx, tx, p.r.Tracer(ctx).Tracer(), &d[i], "status", "worker_id", "lease_expires_at"); err != nil {
+				return err
+			}
+		}
+
+		deliveries = d
+		return nil
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(webhook.ErrQueueEmpty)
+		}
+		return nil, sqlcon.HandleError(err)
+	}
+
+	return deliveries, nil
+}
+
+func (p *Persister) RenewWebhookDeliveryLease(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RenewWebhookDeliveryLease")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"UPDATE %s SET lease_expires_at = ? WHERE id = ? AND nid = ? AND status = ? AND worker_id = ?",
+		webhook.Delivery{}.TableName(),
+	),
+		time.Now().UTC().Add(lease),
+		id,
+		p.NetworkID(ctx),
+		webhook.DeliveryStatusProcessing,
+		workerID,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(webhook.ErrLeaseLost)
+	}
+
+	return nil
+}
+
+func (p *Persister) UpdateWebhookDelivery(ctx context.Context, d *webhook.Delivery) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateWebhookDelivery")
+	defer otelx.End(span, &err)
+
+	d.UpdatedAt = time.Now().UTC()
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"UPDATE %s SET status = ?, send_count = ?, last_error = ?, next_attempt_at = ?, updated_at = ? WHERE id = ? AND nid = ? AND status = ? AND worker_id = ?",
+		webhook.Delivery{}.TableName(),
+	),
+		d.Status,
+		d.SendCount,
+		d.LastError,
+		d.NextAttemptAt,
+		d.UpdatedAt,
+		d.ID,
+		p.NetworkID(ctx),
+		webhook.DeliveryStatusProcessing,
+		d.WorkerID,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(webhook.ErrLeaseLost)
+	}
+
+	return nil
+}
+
+func (p *Persister) ReplayWebhookDelivery(ctx context.Context, id uuid.UUID) (_ *webhook.Delivery, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ReplayWebhookDelivery")
+	defer otelx.End(span, &err)
+
+	now := time.Now().UTC()
+
+	//#nosec G201 -- TableName is static
+	count, err := p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"UPDATE %s SET status = ?, send_count = 0, next_attempt_at = ?, worker_id = NULL, lease_expires_at = NULL, updated_at = ? WHERE id = ? AND nid = ? AND status IN (?, ?)",
+		webhook.Delivery{}.TableName(),
+	),
+		webhook.DeliveryStatusQueued,
+		now,
+		now,
+		id,
+		p.NetworkID(ctx),
+		webhook.DeliveryStatusSent,
+		webhook.DeliveryStatusAbandoned,
+	).ExecWithCount()
+	if err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	d, err := p.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, errors.WithStack(webhook.ErrNotReplayable)
+	}
+
+	return d, nil
+}
+
+func (p *Persister) GetWebhookDelivery(ctx context.Context, id uuid.UUID) (_ *webhook.Delivery, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetWebhookDelivery")
+	defer otelx.End(span, &err)
+
+	var d webhook.Delivery
+	if err := p.GetConnection(ctx).Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).First(&d); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &d, nil
+}
+
+func (p *Persister) ListWebhookDeliveries(ctx context.Context, filter webhook.ListDeliveriesParameters, opts []keysetpagination.Option) (_ []webhook.Delivery, _ *keysetpagination.Paginator, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListWebhookDeliveries")
+	defer otelx.End(span, &err)
+
+	q := p.GetConnection(ctx).Where("nid = ?", p.NetworkID(ctx))
+
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if filter.WebhookID != "" {
+		q = q.Where("webhook_id = ?", filter.WebhookID)
+	}
+
+	opts = append(opts, keysetpagination.WithDefaultToken(webhook.Delivery{}.DefaultPageToken()))
+	opts = append(opts, keysetpagination.WithDefaultSize(250))
+	paginator := keysetpagination.NewPaginator(opts...)
+
+	deliveries := make([]webhook.Delivery, paginator.Size())
+	if err := q.Scope(keysetpagination.Paginate[webhook.Delivery](paginator)).
+		All(&deliveries); err != nil {
+		return nil, nil, sqlcon.HandleError(err)
+	}
+
+	deliveries, nextPage := keysetpagination.Result(deliveries, paginator)
+	return deliveries, nextPage, nil
+}
+
+func (p *Persister) DeleteSentWebhookDeliveries(ctx context.Context, olderThan time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteSentWebhookDeliveries")
+	defer otelx.End(span, &err)
+
+	//#nosec G201 -- TableName is static
+	return sqlcon.HandleError(p.GetConnection(ctx).RawQuery(fmt.Sprintf(
+		"DELETE FROM %[1]s WHERE id in (SELECT id FROM (SELECT id FROM %[1]s c WHERE created_at < ? AND nid = ? AND status = ? ORDER BY created_at ASC LIMIT ?) AS s)",
+		webhook.Delivery{}.TableName(),
+	),
+		olderThan,
+		p.NetworkID(ctx),
+		webhook.DeliveryStatusSent,
+		limit,
+	).Exec())
+}
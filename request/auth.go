@@ -4,12 +4,31 @@
 package request
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/sync/singleflight"
 )
 
+// DefaultHMACHeader is the header containing the signature of requests signed
+// by the hmac auth strategy.
+const DefaultHMACHeader = "Ory-Signature"
+
 type (
 	noopAuthStrategy  struct{}
 	basicAuthStrategy struct {
@@ -21,8 +40,15 @@ type (
 		value string
 		in    string
 	}
+	hmacStrategy struct {
+		header  string
+		secrets []string
+	}
+	oauth2ClientCredentialsStrategy struct {
+		config *clientcredentials.Config
+	}
 	AuthStrategy interface {
-		apply(req *retryablehttp.Request)
+		apply(ctx context.Context, req *retryablehttp.Request) error
 	}
 )
 
@@ -51,16 +77,57 @@ func authStrategy(typ string, config map[string]any) (AuthStrategy, error) {
 			return nil, fmt.Errorf("basic_auth auth strategy requires a string password")
 		}
 		return NewBasicAuthStrategy(user, password), nil
+	case "hmac":
+		secrets, ok := stringSlice(config["secrets"])
+		if !ok || len(secrets) == 0 {
+			return nil, fmt.Errorf("hmac auth strategy requires at least one string secret")
+		}
+		header, _ := config["header"].(string) // header is optional
+		return NewHMACStrategy(header, secrets...), nil
+	case "oauth2_client_credentials":
+		tokenURL, ok := config["token_url"].(string)
+		if !ok {
+			return nil, fmt.Errorf("oauth2_client_credentials auth strategy requires a string token_url")
+		}
+		clientID, ok := config["client_id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("oauth2_client_credentials auth strategy requires a string client_id")
+		}
+		clientSecret, ok := config["client_secret"].(string)
+		if !ok {
+			return nil, fmt.Errorf("oauth2_client_credentials auth strategy requires a string client_secret")
+		}
+		scopes, _ := stringSlice(config["scopes"]) // scopes are optional
+		audience, _ := config["audience"].(string) // audience is optional
+		return NewOAuth2ClientCredentialsStrategy(tokenURL, clientID, clientSecret, audience, scopes...), nil
 	}
 
 	return nil, fmt.Errorf("unsupported auth type: %s", typ)
 }
 
+func stringSlice(v any) ([]string, bool) {
+	switch v := v.(type) {
+	case []string:
+		return v, true
+	case []any:
+		s := make([]string, len(v))
+		for i, e := range v {
+			if s[i], _ = e.(string); s[i] == "" {
+				return nil, false
+			}
+		}
+		return s, true
+	}
+	return nil, false
+}
+
 func NewNoopAuthStrategy() AuthStrategy {
 	return &noopAuthStrategy{}
 }
 
-func (c *noopAuthStrategy) apply(_ *retryablehttp.Request) {}
+func (c *noopAuthStrategy) apply(_ context.Context, _ *retryablehttp.Request) error {
+	return nil
+}
 
 func NewBasicAuthStrategy(user, password string) AuthStrategy {
 	return &basicAuthStrategy{
@@ -69,8 +136,9 @@ func NewBasicAuthStrategy(user, password string) AuthStrategy {
 	}
 }
 
-func (c *basicAuthStrategy) apply(req *retryablehttp.Request) {
+func (c *basicAuthStrategy) apply(_ context.Context, req *retryablehttp.Request) error {
 	req.SetBasicAuth(c.user, c.password)
+	return nil
 }
 
 func NewAPIKeyStrategy(in, name, value string) AuthStrategy {
@@ -81,7 +149,7 @@ func NewAPIKeyStrategy(in, name, value string) AuthStrategy {
 	}
 }
 
-func (c *apiKeyStrategy) apply(req *retryablehttp.Request) {
+func (c *apiKeyStrategy) apply(_ context.Context, req *retryablehttp.Request) error {
 	switch c.in {
 	case "cookie":
 		req.AddCookie(&http.Cookie{Name: c.name, Value: c.value})
@@ -91,4 +159,198 @@ func (c *apiKeyStrategy) apply(req *retryablehttp.Request) {
 	case "header", "":
 		req.Header.Set(c.name, c.value)
 	}
+	return nil
+}
+
+// NewHMACStrategy signs the body of the request with every secret. The
+// signature header has the form
+//
+//	t=<unix timestamp>,v1=<signature>[,v1=<signature>...]
+//
+// where each signature is the hex encoded HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers should reject requests with an outdated timestamp to prevent
+// replay attacks. Signing with several secrets allows rotating them: add the
+// new secret first, update the receivers, then remove the old secret.
+func NewHMACStrategy(header string, secrets ...string) AuthStrategy {
+	if header == "" {
+		header = DefaultHMACHeader
+	}
+	return &hmacStrategy{
+		header:  header,
+		secrets: secrets,
+	}
+}
+
+func (c *hmacStrategy) apply(_ context.Context, req *retryablehttp.Request) error {
+	body, err := req.BodyBytes()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	timestamp := time.Now().Unix()
+	signature := make([]string, 0, len(c.secrets)+1)
+	signature = append(signature, "t="+strconv.FormatInt(timestamp, 10))
+	for _, secret := range c.secrets {
+		signature = append(signature, "v1="+hex.EncodeToString(computeHMACSignature(secret, timestamp, body)))
+	}
+
+	req.Header.Set(c.header, strings.Join(signature, ","))
+	return nil
+}
+
+func computeHMACSignature(secret string, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSignature verifies a signature header created by the hmac auth
+// strategy. The signature is valid if it was created by any of the secrets no
+// longer than tolerance ago.
+func VerifyHMACSignature(header string, body []byte, tolerance time.Duration, secrets ...string) error {
+	var (
+		timestamp  int64
+		signatures [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.Errorf("the signature timestamp is invalid: %s", err)
+			}
+			timestamp = t
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return errors.New("the signature header is malformed")
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return errors.New("the signature timestamp is outside of the tolerance")
+	}
+
+	for _, secret := range secrets {
+		expected := computeHMACSignature(secret, timestamp, body)
+		for _, sig := range signatures {
+			if subtle.ConstantTimeCompare(expected, sig) == 1 {
+				return nil
+			}
+		}
+	}
+	return errors.New("no signature matches")
+}
+
+// NewOAuth2ClientCredentialsStrategy authenticates requests with a bearer
+// token obtained from the token endpoint using the OAuth2 client credentials
+// grant. Tokens are cached until they expire.
+//
+// The token is requested using the HTTP client stored in the context under
+// oauth2.HTTPClient, if any.
+func NewOAuth2ClientCredentialsStrategy(tokenURL, clientID, clientSecret, audience string, scopes ...string) AuthStrategy {
+	c := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       scopes,
+	}
+	if audience != "" {
+		c.EndpointParams = url.Values{"audience": {audience}}
+	}
+	return &oauth2ClientCredentialsStrategy{config: c}
+}
+
+func (c *oauth2ClientCredentialsStrategy) apply(ctx context.Context, req *retryablehttp.Request) error {
+	token, err := oauth2Tokens.token(ctx, c.config)
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req.Request)
+	return nil
+}
+
+// oauth2TokenCache caches the tokens of all client credentials strategies, as
+// strategies are created for every request. Concurrent requests for the same
+// configuration share one token request, which is performed without holding
+// the lock, so a slow token endpoint only delays the requests that need it.
+type oauth2TokenCache struct {
+	sync.Mutex
+	tokens map[string]*oauth2.Token
+	group  singleflight.Group
+}
+
+var oauth2Tokens = &oauth2TokenCache{tokens: map[string]*oauth2.Token{}}
+
+func (c *oauth2TokenCache) token(ctx context.Context, config *clientcredentials.Config) (*oauth2.Token, error) {
+	key, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	h := sha256.Sum256(key)
+	k := hex.EncodeToString(h[:])
+
+	if t := c.get(k); t != nil {
+		return t, nil
+	}
+
+	// The token is shared by all waiting callers, so it must not be bound to
+	// the cancellation of the caller which happens to request it.
+	ch := c.group.DoChan(k, func() (any, error) {
+		t, err := config.Token(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c.set(k, t)
+		return t, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*oauth2.Token), nil
+	}
+}
+
+// get returns the cached token or nil if there is no valid one. Invalid
+// tokens are removed.
+func (c *oauth2TokenCache) get(k string) *oauth2.Token {
+	c.Lock()
+	defer c.Unlock()
+
+	t, ok := c.tokens[k]
+	if !ok {
+		return nil
+	}
+	if !t.Valid() {
+		delete(c.tokens, k)
+		return nil
+	}
+	return t
+}
+
+// set caches the token and removes all tokens which are no longer valid, such
+// as those of configurations whose secret was rotated.
+func (c *oauth2TokenCache) set(k string, t *oauth2.Token) {
+	c.Lock()
+	defer c.Unlock()
+
+	for key, cached := range c.tokens {
+		if !cached.Valid() {
+			delete(c.tokens, key)
+		}
+	}
+	c.tokens[k] = t
 }
@@ -4,19 +4,27 @@
 package request
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 )
 
 func TestNoopAuthStrategy(t *testing.T) {
 	req := retryablehttp.Request{Request: &http.Request{Header: map[string][]string{}}}
 	auth := noopAuthStrategy{}
 
-	auth.apply(&req)
+	require.NoError(t, auth.apply(context.Background(), &req))
 
 	assert.Empty(t, req.Header, "Empty auth strategy shall not modify any request headers")
 }
@@ -28,7 +36,7 @@ func TestBasicAuthStrategy(t *testing.T) {
 		password: "test-pass",
 	}
 
-	auth.apply(&req)
+	require.NoError(t, auth.apply(context.Background(), &req))
 
 	assert.Len(t, req.Header, 1)
 
@@ -45,7 +53,7 @@ func TestApiKeyInHeaderStrategy(t *testing.T) {
 		value: "my-api-key-value",
 	}
 
-	auth.apply(&req)
+	require.NoError(t, auth.apply(context.Background(), &req))
 
 	require.Len(t, req.Header, 1)
 
@@ -61,7 +69,7 @@ func TestApiKeyInCookieStrategy(t *testing.T) {
 		value: "my-api-key-value",
 	}
 
-	auth.apply(&req)
+	require.NoError(t, auth.apply(context.Background(), &req))
 
 	cookies := req.Cookies()
 	assert.Len(t, cookies, 1)
@@ -109,6 +117,23 @@ func TestAuthStrategy(t *testing.T) {
 			},
 			expected: &apiKeyStrategy{},
 		},
+		"hmac": {
+			name: "hmac",
+			config: map[string]any{
+				"secrets": []any{"secret-0123456789"},
+			},
+			expected: &hmacStrategy{},
+		},
+		"oauth2_client_credentials": {
+			name: "oauth2_client_credentials",
+			config: map[string]any{
+				"token_url":     "https://auth.example.com/oauth2/token",
+				"client_id":     "client-id",
+				"client_secret": "client-secret",
+				"scopes":        []any{"webhooks"},
+			},
+			expected: &oauth2ClientCredentialsStrategy{},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			strategy, err := authStrategy(tc.name, tc.config)
@@ -118,3 +143,178 @@ func TestAuthStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestHMACStrategy(t *testing.T) {
+	body := []byte(`{"identity_id":"8b1c6a34-5a3c-4bd2-9e7b-0a1b2c3d4e5f"}`)
+	newRequest := func(t *testing.T) *retryablehttp.Request {
+		req, err := retryablehttp.NewRequest("POST", "https://example.com", body)
+		require.NoError(t, err)
+		return req
+	}
+
+	t.Run("case=signs body with every secret", func(t *testing.T) {
+		req := newRequest(t)
+		require.NoError(t, NewHMACStrategy("", "new-secret-0123456789", "old-secret-0123456789").apply(context.Background(), req))
+
+		header := req.Header.Get(DefaultHMACHeader)
+		assert.Regexp(t, `^t=\d+,v1=[0-9a-f]{64},v1=[0-9a-f]{64}$`, header)
+
+		assert.NoError(t, VerifyHMACSignature(header, body, time.Minute, "new-secret-0123456789"))
+		assert.NoError(t, VerifyHMACSignature(header, body, time.Minute, "old-secret-0123456789"))
+		assert.Error(t, VerifyHMACSignature(header, body, time.Minute, "other-secret-0123456789"))
+		assert.Error(t, VerifyHMACSignature(header, []byte(`{}`), time.Minute, "new-secret-0123456789"), "tampered bodies must be rejected")
+	})
+
+	t.Run("case=uses custom header", func(t *testing.T) {
+		req := newRequest(t)
+		require.NoError(t, NewHMACStrategy("X-Signature", "secret-0123456789").apply(context.Background(), req))
+
+		assert.Empty(t, req.Header.Get(DefaultHMACHeader))
+		assert.NoError(t, VerifyHMACSignature(req.Header.Get("X-Signature"), body, time.Minute, "secret-0123456789"))
+	})
+
+	t.Run("case=rejects outdated signatures", func(t *testing.T) {
+		timestamp := time.Now().Add(-time.Hour).Unix()
+		header := fmt.Sprintf("t=%d,v1=%x", timestamp, computeHMACSignature("secret-0123456789", timestamp, body))
+
+		assert.NoError(t, VerifyHMACSignature(header, body, 2*time.Hour, "secret-0123456789"))
+		assert.Error(t, VerifyHMACSignature(header, body, time.Minute, "secret-0123456789"))
+	})
+
+	t.Run("case=rejects malformed headers", func(t *testing.T) {
+		for _, header := range []string{"", "t=1", "v1=abcd", "t=abc,v1=abcd"} {
+			assert.Error(t, VerifyHMACSignature(header, body, time.Minute, "secret-0123456789"), header)
+		}
+	})
+}
+
+func TestOAuth2ClientCredentialsStrategy(t *testing.T) {
+	var requests atomic.Int32
+	expiresIn := 3600
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.NoError(t, r.ParseForm())
+
+		user, pass, _ := r.BasicAuth()
+		if user != "client-id" || pass != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "webhooks", r.PostForm.Get("scope"))
+		assert.Equal(t, "https://api.example.com", r.PostForm.Get("audience"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", requests.Load()),
+			"token_type":   "bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(ts.Close)
+
+	apply := func(t *testing.T, strategy AuthStrategy) (*retryablehttp.Request, error) {
+		req := retryablehttp.Request{Request: &http.Request{Header: map[string][]string{}}}
+		return &req, strategy.apply(context.Background(), &req)
+	}
+
+	t.Run("case=caches token until it expires", func(t *testing.T) {
+		requests.Store(0)
+		strategy := NewOAuth2ClientCredentialsStrategy(ts.URL+"/cached", "client-id", "client-secret", "https://api.example.com", "webhooks")
+
+		for range 3 {
+			req, err := apply(t, strategy)
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+		}
+		assert.EqualValues(t, 1, requests.Load())
+
+		// Strategies are created for every request, but share the cache.
+		req, err := apply(t, NewOAuth2ClientCredentialsStrategy(ts.URL+"/cached", "client-id", "client-secret", "https://api.example.com", "webhooks"))
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+		assert.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("case=fetches new token after expiry", func(t *testing.T) {
+		requests.Store(0)
+		expiresIn = 1 // shorter than the expiry delta of the oauth2 package
+		t.Cleanup(func() { expiresIn = 3600 })
+		strategy := NewOAuth2ClientCredentialsStrategy(ts.URL+"/expiring", "client-id", "client-secret", "https://api.example.com", "webhooks")
+
+		for i := range 2 {
+			req, err := apply(t, strategy)
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("Bearer token-%d", i+1), req.Header.Get("Authorization"))
+		}
+		assert.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("case=concurrent requests share one token request", func(t *testing.T) {
+		requests.Store(0)
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req, err := apply(t, NewOAuth2ClientCredentialsStrategy(ts.URL+"/concurrent", "client-id", "client-secret", "https://api.example.com", "webhooks"))
+				assert.NoError(t, err)
+				assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+			}()
+		}
+		wg.Wait()
+		assert.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("case=slow token endpoint does not block other configurations", func(t *testing.T) {
+		release := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(slow.Close)
+		t.Cleanup(func() { close(release) })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			req := retryablehttp.Request{Request: &http.Request{Header: map[string][]string{}}}
+			done <- NewOAuth2ClientCredentialsStrategy(slow.URL, "client-id", "client-secret", "").apply(ctx, &req)
+		}()
+
+		req, err := apply(t, NewOAuth2ClientCredentialsStrategy(ts.URL+"/unblocked", "client-id", "client-secret", "https://api.example.com", "webhooks"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, req.Header.Get("Authorization"))
+
+		// A waiting caller gives up when its context is canceled.
+		cancel()
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the request for the slow token endpoint was not canceled")
+		}
+	})
+
+	t.Run("case=evicts invalid tokens", func(t *testing.T) {
+		cache := &oauth2TokenCache{tokens: map[string]*oauth2.Token{
+			"expired": {AccessToken: "expired", Expiry: time.Now().Add(-time.Hour)},
+			"valid":   {AccessToken: "valid", Expiry: time.Now().Add(time.Hour)},
+		}}
+
+		assert.Nil(t, cache.get("expired"))
+		assert.NotContains(t, cache.tokens, "expired")
+
+		cache.tokens["rotated"] = &oauth2.Token{AccessToken: "rotated", Expiry: time.Now().Add(-time.Hour)}
+		cache.set("new", &oauth2.Token{AccessToken: "new", Expiry: time.Now().Add(time.Hour)})
+		assert.NotContains(t, cache.tokens, "rotated")
+		assert.Contains(t, cache.tokens, "valid")
+		assert.Contains(t, cache.tokens, "new")
+	})
+
+	t.Run("case=fails if token can not be fetched", func(t *testing.T) {
+		req, err := apply(t, NewOAuth2ClientCredentialsStrategy(ts.URL+"/invalid", "client-id", "wrong-secret", "https://api.example.com", "webhooks"))
+		require.Error(t, err)
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+}
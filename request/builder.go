@@ -18,6 +18,7 @@ import (
 	"github.com/google/go-jsonnet"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/x"
@@ -48,10 +49,12 @@ type (
 		deps         Dependencies
 		cache        *ristretto.Cache[[]byte, []byte]
 		bodySizeHint uint
+		skipAuth     bool
 	}
 	options struct {
 		cache        *ristretto.Cache[[]byte, []byte]
 		bodySizeHint uint
+		skipAuth     bool
 	}
 	BuilderOption = func(*options)
 )
@@ -68,6 +71,16 @@ func WithBodySizeHint(hint uint) BuilderOption {
 	}
 }
 
+// WithoutAuth builds the request without applying the auth strategy. This is
+// useful for requests which are sent later, for example by the webhook outbox,
+// as signatures and tokens might be outdated by then. Use Authenticate to
+// apply the strategy before sending the request.
+func WithoutAuth() BuilderOption {
+	return func(o *options) {
+		o.skipAuth = true
+	}
+}
+
 func NewBuilder(ctx context.Context, c *Config, deps Dependencies, o ...BuilderOption) (_ *Builder, err error) {
 	_, span := deps.Tracer(ctx).Tracer().Start(ctx, "request.NewBuilder")
 	defer otelx.End(span, &err)
@@ -106,6 +119,7 @@ func NewBuilder(ctx context.Context, c *Config, deps Dependencies, o ...BuilderO
 		deps:         deps,
 		cache:        opts.cache,
 		bodySizeHint: opts.bodySizeHint,
+		skipAuth:     opts.skipAuth,
 	}, nil
 }
 
@@ -225,7 +239,6 @@ func (b *Builder) addURLEncodedBody(ctx context.Context, jsonnetSnippet []byte,
 
 func (b *Builder) BuildRequest(ctx context.Context, body interface{}) (*retryablehttp.Request, error) {
 	b.r.Header = b.Config.header
-	b.Config.auth.apply(b.r)
 
 	// According to the HTTP spec any request method, but TRACE is allowed to
 	// have a body. Even this is a bad practice for some of them, like for GET
@@ -235,9 +248,40 @@ func (b *Builder) BuildRequest(ctx context.Context, body interface{}) (*retryabl
 		}
 	}
 
+	// The auth strategy is applied last, as it may sign the body.
+	if err := b.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
 	return b.r, nil
 }
 
+func (b *Builder) authenticate(ctx context.Context) error {
+	if b.skipAuth {
+		return nil
+	}
+	return b.Config.auth.apply(withHTTPClient(ctx, b.deps.HTTPClient(ctx)), b.r)
+}
+
+// Authenticate applies the auth strategy of the config to a request which was
+// built using WithoutAuth.
+func Authenticate(ctx context.Context, req *retryablehttp.Request, c AuthConfig, client *retryablehttp.Client) error {
+	strategy, err := authStrategy(c.Type, c.Config)
+	if err != nil {
+		return err
+	}
+	return strategy.apply(withHTTPClient(ctx, client), req)
+}
+
+// withHTTPClient makes auth strategies use the client for their own requests,
+// such as fetching OAuth2 tokens.
+func withHTTPClient(ctx context.Context, client *retryablehttp.Client) context.Context {
+	if client == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, client.HTTPClient)
+}
+
 func (b *Builder) addRawBody(body any) (err error) {
 	if isNilInterface(body) {
 		return nil
@@ -264,9 +308,8 @@ func (b *Builder) addRawBody(body any) (err error) {
 	return nil
 }
 
-func (b *Builder) BuildRawRequest(body any) (*retryablehttp.Request, error) {
+func (b *Builder) BuildRawRequest(ctx context.Context, body any) (*retryablehttp.Request, error) {
 	b.r.Header = b.Config.header
-	b.Config.auth.apply(b.r)
 
 	// According to the HTTP spec any request method, but TRACE is allowed to
 	// have a body. Even this is a bad practice for some of them, like for GET
@@ -276,6 +319,10 @@ func (b *Builder) BuildRawRequest(body any) (*retryablehttp.Request, error) {
 		}
 	}
 
+	if err := b.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
 	return b.r, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -317,6 +318,49 @@ func TestBuildRequest(t *testing.T) {
 		_, err = rb.BuildRequest(context.Background(), json.RawMessage(`{}`))
 		require.ErrorIs(t, err, ErrCancel)
 	})
+
+	t.Run("case=signs rendered body", func(t *testing.T) {
+		rb, err := NewBuilder(context.Background(), &Config{
+			URL:         "https://test.kratos.ory.sh/my_endpoint8",
+			Method:      "POST",
+			TemplateURI: "file://./stub/test_body.jsonnet",
+			Auth: AuthConfig{
+				Type:   "hmac",
+				Config: map[string]any{"secrets": []any{"secret-0123456789"}},
+			},
+		}, newTestDependencyProvider(t))
+		require.NoError(t, err)
+
+		req, err := rb.BuildRequest(context.Background(), &testRequestBody{To: "+15056445993", From: "+12288534869", Body: "test-sms-body"})
+		require.NoError(t, err)
+
+		body, err := req.BodyBytes()
+		require.NoError(t, err)
+		assert.NoError(t, VerifyHMACSignature(req.Header.Get(DefaultHMACHeader), body, time.Minute, "secret-0123456789"))
+	})
+
+	t.Run("case=skips auth", func(t *testing.T) {
+		rb, err := NewBuilder(context.Background(), &Config{
+			URL:         "https://test.kratos.ory.sh/my_endpoint9",
+			Method:      "POST",
+			TemplateURI: "file://./stub/test_body.jsonnet",
+			Auth: AuthConfig{
+				Type:   "basic_auth",
+				Config: map[string]any{"user": "test-api-user", "password": "secret"},
+			},
+		}, newTestDependencyProvider(t), WithoutAuth())
+		require.NoError(t, err)
+
+		req, err := rb.BuildRequest(context.Background(), &testRequestBody{To: "+15056445993", From: "+12288534869", Body: "test-sms-body"})
+		require.NoError(t, err)
+		assert.Empty(t, req.Header.Get("Authorization"))
+
+		require.NoError(t, Authenticate(context.Background(), req, rb.Config.Auth, newTestDependencyProvider(t).HTTPClient(context.Background())))
+		user, pass, ok := req.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, "test-api-user", user)
+		assert.Equal(t, "secret", pass)
+	})
 }
 
 type testDependencyProvider struct {
@@ -5,6 +5,7 @@ package schema
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -369,6 +370,27 @@ func NewLinkedCredentialsDoNotMatch() error {
 	})
 }
 
+func NewLoginLockedOutError(lockedUntil time.Time) error {
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     `too many failed login attempts; the login is temporarily locked`,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(text.NewErrorValidationLoginLockedOut(lockedUntil)),
+	})
+}
+
+func NewCaptchaFailedError() error {
+	t := text.NewErrorCaptchaFailed()
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     `the captcha verification failed`,
+			InstancePtr: "#/",
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
 func NewUnknownAddressError() error {
 	return errors.WithStack(&ValidationError{
 		ValidationError: &jsonschema.ValidationError{
@@ -379,3 +401,13 @@ func NewUnknownAddressError() error {
 	},
 	)
 }
+
+func NewDeletionNotConfirmedError() error {
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     `the deletion of the account was not confirmed`,
+			InstancePtr: "#/confirm",
+		},
+		Messages: new(text.Messages).Add(text.NewErrorValidationDeletionNotConfirmed()),
+	})
+}
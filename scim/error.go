@@ -0,0 +1,72 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/pkg/errors"
+)
+
+// SCIM error types as defined in RFC 7644, Section 3.12. They are carried in
+// the ID of the herodot error.
+const (
+	ErrorTypeInvalidFilter = "invalidFilter"
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+	ErrorTypeInvalidPath   = "invalidPath"
+	ErrorTypeInvalidValue  = "invalidValue"
+	ErrorTypeNoTarget      = "noTarget"
+	ErrorTypeUniqueness    = "uniqueness"
+)
+
+var errorTypes = map[string]bool{
+	ErrorTypeInvalidFilter: true,
+	ErrorTypeInvalidSyntax: true,
+	ErrorTypeInvalidPath:   true,
+	ErrorTypeInvalidValue:  true,
+	ErrorTypeNoTarget:      true,
+	ErrorTypeUniqueness:    true,
+}
+
+// scimError is the error response of the SCIM API.
+//
+// swagger:model scimError
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, sqlcon.ErrUniqueViolation) {
+		err = errors.WithStack(herodot.ErrConflict.WithID(ErrorTypeUniqueness).WithReason("This user conflicts with another user that already exists.").WithWrap(err))
+	}
+
+	de := herodot.ToDefaultError(err, "")
+	e := scimError{
+		Schemas: []string{SchemaError},
+		Status:  strconv.Itoa(de.StatusCode()),
+		Detail:  de.Reason(),
+	}
+	if e.Detail == "" {
+		e.Detail = de.Error()
+	}
+	if errorTypes[de.ID()] {
+		e.ScimType = de.ID()
+	}
+
+	if de.StatusCode() >= http.StatusInternalServerError {
+		h.d.Logger().WithRequest(r).WithError(err).Error("An error occurred while handling a SCIM request.")
+		e.Detail = http.StatusText(de.StatusCode())
+	} else {
+		h.d.Logger().WithRequest(r).WithError(err).Info("A SCIM request was rejected.")
+	}
+
+	h.write(w, de.StatusCode(), e)
+}
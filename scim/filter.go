@@ -0,0 +1,63 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+// filter is a SCIM filter expression.
+//
+// Only the `eq` operator is supported, which is what SCIM clients such as
+// Okta and Microsoft Entra ID use to look up users and to select values of
+// multi-valued attributes in PATCH requests.
+type filter struct {
+	Attribute string
+	Value     any
+}
+
+func parseFilter(raw string) (*filter, error) {
+	attribute, rest, ok := strings.Cut(strings.TrimSpace(raw), " ")
+	if !ok || attribute == "" {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidFilter).WithReasonf("The filter %q is not a valid SCIM filter.", raw))
+	}
+
+	operator, value, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	if !strings.EqualFold(operator, "eq") {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidFilter).WithReasonf("The filter operator %q is not supported, only eq is supported.", operator))
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), &v); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidFilter).WithReasonf("The filter value %q is not valid: %s", value, err))
+	}
+
+	return &filter{Attribute: attribute, Value: v}, nil
+}
+
+// matches returns true if the filter matches a value of a multi-valued
+// attribute.
+func (f *filter) matches(value any) bool {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	return equal(m[findKey(m, f.Attribute)], f.Value)
+}
+
+// equal compares two attribute values. Strings are compared
+// case-insensitively, as SCIM attributes are not case exact by default.
+func equal(a, b any) bool {
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		return ok && strings.EqualFold(as, bs)
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,610 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/urlx"
+)
+
+const (
+	RouteBase                  = "/scim/v2"
+	RouteUsers                 = RouteBase + "/Users"
+	RouteUser                  = RouteUsers + "/{id}"
+	RouteServiceProviderConfig = RouteBase + "/ServiceProviderConfig"
+
+	defaultCount = 100
+	maxCount     = 1000
+)
+
+type (
+	handlerDependencies interface {
+		config.Provider
+		identity.PrivilegedPoolProvider
+		identity.ManagementProvider
+		jsonnetsecure.VMProvider
+		nosurfx.CSRFProvider
+		session.PersistenceProvider
+		x.HTTPClientProvider
+		x.LoggingProvider
+		x.TracingProvider
+		x.WriterProvider
+	}
+	HandlerProvider interface {
+		SCIMHandler() *Handler
+	}
+	// Handler implements the SCIM 2.0 user provisioning endpoints (RFC 7644)
+	// on top of the identity management.
+	Handler struct {
+		d handlerDependencies
+	}
+
+	// serviceProviderConfig describes the SCIM features supported by Ory
+	// Kratos.
+	//
+	// swagger:model scimServiceProviderConfig
+	serviceProviderConfig struct {
+		Schemas               []string          `json:"schemas"`
+		Patch                 supported         `json:"patch"`
+		Bulk                  bulkSupported     `json:"bulk"`
+		Filter                filterSupported   `json:"filter"`
+		ChangePassword        supported         `json:"changePassword"`
+		Sort                  supported         `json:"sort"`
+		ETag                  supported         `json:"etag"`
+		AuthenticationSchemes []any             `json:"authenticationSchemes"`
+		Meta                  map[string]string `json:"meta"`
+	}
+	supported struct {
+		Supported bool `json:"supported"`
+	}
+	bulkSupported struct {
+		Supported      bool `json:"supported"`
+		MaxOperations  int  `json:"maxOperations"`
+		MaxPayloadSize int  `json:"maxPayloadSize"`
+	}
+	filterSupported struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults"`
+	}
+
+	// listResponse is the response of the SCIM list endpoint.
+	//
+	// swagger:model scimListResponse
+	listResponse struct {
+		Schemas      []string `json:"schemas"`
+		TotalResults int64    `json:"totalResults"`
+		StartIndex   int      `json:"startIndex"`
+		ItemsPerPage int      `json:"itemsPerPage"`
+		Resources    []User   `json:"Resources"`
+	}
+)
+
+func NewHandler(d handlerDependencies) *Handler {
+	return &Handler{d: d}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
+	h.d.CSRFHandler().IgnoreGlobs(
+		httprouterx.AdminPrefix+RouteBase+"/*",
+		httprouterx.AdminPrefix+RouteUsers+"/*",
+	)
+
+	public.GET(httprouterx.AdminPrefix+RouteUsers, redir.RedirectToAdminRoute(h.d))
+	public.POST(httprouterx.AdminPrefix+RouteUsers, redir.RedirectToAdminRoute(h.d))
+	public.GET(httprouterx.AdminPrefix+RouteUser, redir.RedirectToAdminRoute(h.d))
+	public.PUT(httprouterx.AdminPrefix+RouteUser, redir.RedirectToAdminRoute(h.d))
+	public.PATCH(httprouterx.AdminPrefix+RouteUser, redir.RedirectToAdminRoute(h.d))
+	public.DELETE(httprouterx.AdminPrefix+RouteUser, redir.RedirectToAdminRoute(h.d))
+	public.GET(httprouterx.AdminPrefix+RouteServiceProviderConfig, redir.RedirectToAdminRoute(h.d))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
+	admin.GET(RouteUsers, h.enabled(h.listUsers))
+	admin.POST(RouteUsers, h.enabled(h.createUser))
+	admin.GET(RouteUser, h.enabled(h.getUser))
+	admin.PUT(RouteUser, h.enabled(h.replaceUser))
+	admin.PATCH(RouteUser, h.enabled(h.patchUser))
+	admin.DELETE(RouteUser, h.enabled(h.deleteUser))
+	admin.GET(RouteServiceProviderConfig, h.enabled(h.getServiceProviderConfig))
+}
+
+// enabled responds with 404 Not Found unless SCIM provisioning is enabled.
+func (h *Handler) enabled(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.d.Config().IdentitySCIM(r.Context()).Enabled {
+			h.writeError(w, r, errors.WithStack(herodot.ErrNotFound.WithReason("SCIM provisioning is disabled.")))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// swagger:route GET /admin/scim/v2/Users identity listScimUsers
+//
+// # List SCIM Users
+//
+// Lists identities as SCIM 2.0 user resources. The `filter` parameter supports
+// the `eq` operator on the `userName`, `externalId`, and `id` attributes.
+// Use `startIndex` and `count` to paginate.
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: scimListResponse
+//	  400: scimError
+//	  default: scimError
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, span := h.d.Tracer(r.Context()).Tracer().Start(r.Context(), "scim.Handler.listUsers")
+	defer otelx.End(span, &err)
+
+	query := r.URL.Query()
+	startIndex, count := 1, defaultCount
+	if v, err := strconv.Atoi(query.Get("startIndex")); err == nil && v > 0 {
+		startIndex = v
+	}
+	if v, err := strconv.Atoi(query.Get("count")); err == nil && v >= 0 {
+		count = min(v, maxCount)
+	}
+
+	res := listResponse{
+		Schemas:    []string{SchemaListResponse},
+		StartIndex: startIndex,
+		Resources:  []User{},
+	}
+
+	var identities []identity.Identity
+	if raw := query.Get("filter"); raw != "" {
+		var i *identity.Identity
+		if i, err = h.findByFilter(r, raw); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if i != nil {
+			res.TotalResults = 1
+			if startIndex == 1 && count > 0 {
+				identities = append(identities, *i)
+			}
+		}
+	} else {
+		if res.TotalResults, err = h.d.PrivilegedIdentityPool().CountIdentities(ctx); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		if count > 0 {
+			if identities, err = h.listIdentities(ctx, startIndex-1, count); err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+		}
+	}
+
+	for k := range identities {
+		res.Resources = append(res.Resources, h.user(r, &identities[k]))
+	}
+	res.ItemsPerPage = len(res.Resources)
+
+	h.write(w, http.StatusOK, res)
+}
+
+// listIdentities returns up to count identities starting at the 0-based
+// offset. SCIM paginates by offset, whereas identities are paginated by page,
+// which is why the pages enclosing the requested range are fetched and
+// trimmed.
+func (h *Handler) listIdentities(ctx context.Context, offset, count int) ([]identity.Identity, error) {
+	var identities []identity.Identity
+	first, last := offset/count, (offset+count-1)/count
+	for page := first; page <= last; page++ {
+		batch, _, err := h.d.PrivilegedIdentityPool().ListIdentities(ctx, identity.ListIdentityParameters{
+			PagePagination: &x.Page{Page: page, ItemsPerPage: count},
+		})
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, batch...)
+		if len(batch) < count {
+			break
+		}
+	}
+
+	skip := offset - first*count
+	if skip >= len(identities) {
+		return nil, nil
+	}
+	identities = identities[skip:]
+	return identities[:min(count, len(identities))], nil
+}
+
+// findByFilter returns the identity matching the filter or nil if there is
+// none.
+func (h *Handler) findByFilter(r *http.Request, raw string) (*identity.Identity, error) {
+	f, err := parseFilter(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := f.Value.(string)
+	if !ok {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidFilter).WithReasonf("The filter value for attribute %s must be a string.", f.Attribute))
+	}
+
+	var i *identity.Identity
+	switch {
+	case equal(f.Attribute, "userName"):
+		// The user name is mapped onto a trait which is used as the
+		// identifier of the identity's credentials.
+		i, err = h.d.PrivilegedIdentityPool().FindIdentityByCredentialIdentifier(r.Context(), value, false, identity.ExpandNothing)
+	case equal(f.Attribute, "externalId"):
+		i, err = h.d.PrivilegedIdentityPool().FindIdentityByExternalID(r.Context(), value, identity.ExpandNothing)
+	case equal(f.Attribute, "id"):
+		id, parseErr := uuid.FromString(value)
+		if parseErr != nil {
+			return nil, nil
+		}
+		i, err = h.d.PrivilegedIdentityPool().GetIdentity(r.Context(), id, identity.ExpandNothing)
+	default:
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidFilter).WithReasonf("Filtering by attribute %s is not supported.", f.Attribute))
+	}
+
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else if i.State == identity.StateDeleted {
+		return nil, nil
+	}
+
+	return i, nil
+}
+
+// swagger:route GET /admin/scim/v2/Users/{id} identity getScimUser
+//
+// # Get a SCIM User
+//
+// Returns the identity with the given ID as a SCIM 2.0 user resource.
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: scimUser
+//	  404: scimError
+//	  default: scimError
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	i, err := h.identity(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, h.user(r, i))
+}
+
+// swagger:route POST /admin/scim/v2/Users identity createScimUser
+//
+// # Create a SCIM User
+//
+// Creates an identity from a SCIM 2.0 user resource. The user resource is
+// mapped onto the identity's traits using the configured Jsonnet mapper.
+//
+//	Consumes:
+//	- application/scim+json
+//	- application/json
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: scimUser
+//	  400: scimError
+//	  409: scimError
+//	  default: scimError
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, span := h.d.Tracer(r.Context()).Tracer().Start(r.Context(), "scim.Handler.createUser")
+	defer otelx.End(span, &err)
+
+	u, err := h.decodeUser(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	i := identity.NewIdentity(h.d.Config().IdentitySCIM(ctx).SchemaID)
+	if err = h.applyUser(ctx, u, i); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if err = h.d.IdentityManager().Create(ctx, i); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", h.location(r, i))
+	h.write(w, http.StatusCreated, h.user(r, i))
+}
+
+// swagger:route PUT /admin/scim/v2/Users/{id} identity replaceScimUser
+//
+// # Replace a SCIM User
+//
+// Replaces the identity's SCIM 2.0 user resource and maps it onto the
+// identity's traits. Setting `active` to false deactivates the identity and
+// revokes its sessions.
+//
+//	Consumes:
+//	- application/scim+json
+//	- application/json
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: scimUser
+//	  400: scimError
+//	  404: scimError
+//	  409: scimError
+//	  default: scimError
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	i, err := h.identity(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	u, err := h.decodeUser(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.update(w, r, i, u)
+}
+
+// swagger:route PATCH /admin/scim/v2/Users/{id} identity patchScimUser
+//
+// # Patch a SCIM User
+//
+// Applies SCIM 2.0 PATCH operations to the identity's user resource and maps
+// it onto the identity's traits. The `add`, `replace`, and `remove`
+// operations are supported, including paths with value filters such as
+// `emails[type eq "work"].value`.
+//
+//	Consumes:
+//	- application/scim+json
+//	- application/json
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: scimUser
+//	  400: scimError
+//	  404: scimError
+//	  409: scimError
+//	  default: scimError
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	i, err := h.identity(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	var p patchRequest
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		h.writeError(w, r, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidSyntax).WithReasonf("The request body is not a valid SCIM PATCH request: %s", err)))
+		return
+	}
+
+	// The patch is applied to the user resource as returned by the API, so
+	// that attributes managed by Ory Kratos, such as `active`, are retained.
+	u := h.user(r, i)
+	if err := p.apply(u); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.update(w, r, i, u)
+}
+
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, i *identity.Identity, u User) {
+	var err error
+	ctx, span := h.d.Tracer(r.Context()).Tracer().Start(r.Context(), "scim.Handler.update")
+	defer otelx.End(span, &err)
+
+	if err = h.applyUser(ctx, u, i); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	// Changing the user name changes the identity's credential identifiers.
+	if err = h.d.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	// A deactivated user must not stay signed in.
+	if i.State == identity.StateInactive {
+		if _, err = h.d.SessionPersister().RevokeSessionsIdentityExcept(ctx, i.ID, uuid.Nil); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	}
+
+	h.write(w, http.StatusOK, h.user(r, i))
+}
+
+// swagger:route DELETE /admin/scim/v2/Users/{id} identity deleteScimUser
+//
+// # Delete a SCIM User
+//
+// Irrecoverably deletes the identity. To deactivate the identity instead,
+// set the `active` attribute to false.
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: scimError
+//	  default: scimError
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	deleteIdentity := h.d.PrivilegedIdentityPool().DeleteIdentity
+	if h.d.Config().IdentitySoftDelete(r.Context()).Enabled {
+		deleteIdentity = h.d.PrivilegedIdentityPool().SoftDeleteIdentity
+	}
+
+	i, err := h.identity(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if err := deleteIdentity(r.Context(), i.ID); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// swagger:route GET /admin/scim/v2/ServiceProviderConfig identity getScimServiceProviderConfig
+//
+// # Get the SCIM Service Provider Configuration
+//
+// Returns which SCIM 2.0 features are supported.
+//
+//	Produces:
+//	- application/scim+json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: scimServiceProviderConfig
+//	  default: scimError
+func (h *Handler) getServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	h.write(w, http.StatusOK, serviceProviderConfig{
+		Schemas:               []string{SchemaServiceProviderConfig},
+		Patch:                 supported{Supported: true},
+		Bulk:                  bulkSupported{Supported: false},
+		Filter:                filterSupported{Supported: true, MaxResults: maxCount},
+		ChangePassword:        supported{Supported: false},
+		Sort:                  supported{Supported: false},
+		ETag:                  supported{Supported: false},
+		AuthenticationSchemes: []any{},
+		Meta: map[string]string{
+			"resourceType": "ServiceProviderConfig",
+			"location":     urlx.AppendPaths(h.d.Config().SelfAdminURL(r.Context()), RouteServiceProviderConfig).String(),
+		},
+	})
+}
+
+func (h *Handler) identity(r *http.Request) (*identity.Identity, error) {
+	id, err := uuid.FromString(r.PathValue("id"))
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrNotFound.WithReason("The requested user could not be found."))
+	}
+
+	i, err := h.d.PrivilegedIdentityPool().GetIdentityConfidential(r.Context(), id)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil, errors.WithStack(herodot.ErrNotFound.WithReason("The requested user could not be found."))
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Soft-deleted identities are gone as far as SCIM is concerned, see
+	// RFC 7644 section 3.6.
+	if i.State == identity.StateDeleted {
+		return nil, errors.WithStack(herodot.ErrNotFound.WithReason("The requested user could not be found."))
+	}
+
+	return i, nil
+}
+
+func (h *Handler) decodeUser(r *http.Request) (User, error) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()))
+	}
+
+	u, err := newUser(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.userName() == "" {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidValue).WithReason("The attribute userName is required."))
+	}
+
+	return u, nil
+}
+
+func (h *Handler) user(r *http.Request, i *identity.Identity) User {
+	return userFromIdentity(i, h.location(r, i))
+}
+
+func (h *Handler) location(r *http.Request, i *identity.Identity) string {
+	return urlx.AppendPaths(h.d.Config().SelfAdminURL(r.Context()), RouteUsers, i.ID.String()).String()
+}
+
+func (h *Handler) write(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
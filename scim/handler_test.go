@@ -0,0 +1,326 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/scim"
+	"github.com/ory/kratos/session"
+	"github.com/ory/x/configx"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValue(config.ViperKeyIdentitySCIMEnabled, true),
+		configx.WithValue(config.ViperKeyIdentitySCIMMapperURL, "file://./stub/scim.jsonnet"),
+	)
+	_, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	send := func(t *testing.T, method, href, body string, expectCode int) gjson.Result {
+		t.Helper()
+		req, err := http.NewRequest(method, adminTS.URL+"/admin"+scim.RouteBase+href, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", scim.ContentType)
+
+		res, err := adminTS.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+
+		raw, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, expectCode, res.StatusCode, "%s", raw)
+		if len(raw) > 0 {
+			assert.Equal(t, scim.ContentType, res.Header.Get("Content-Type"))
+		}
+		return gjson.ParseBytes(raw)
+	}
+
+	createUser := func(t *testing.T, userName string) gjson.Result {
+		return send(t, "POST", "/Users", `{
+  "schemas": ["urn:ietf:params:scim:schemas:core:2.0:User", "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"],
+  "externalId": "ext-`+userName+`",
+  "userName": "`+userName+`",
+  "name": {"givenName": "Jane", "familyName": "Doe"},
+  "emails": [{"type": "work", "value": "`+userName+`", "primary": true}],
+  "groups": [{"value": "1", "display": "Engineering"}],
+  "password": "s3cr3t-p4ssw0rd",
+  "active": true,
+  "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User": {"department": "R&D"}
+}`, http.StatusCreated)
+	}
+
+	getIdentity := func(t *testing.T, id string) *identity.Identity {
+		i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), uuid.FromStringOrNil(id))
+		require.NoError(t, err)
+		return i
+	}
+
+	t.Run("case=creates an identity from a user", func(t *testing.T) {
+		user := createUser(t, "create@example.org")
+		id := user.Get("id").String()
+
+		assert.Equal(t, "create@example.org", user.Get("userName").String(), "%s", user)
+		assert.Equal(t, "ext-create@example.org", user.Get("externalId").String(), "%s", user)
+		assert.True(t, user.Get("active").Bool(), "%s", user)
+		assert.Equal(t, "R&D", user.Get(`urn:ietf:params:scim:schemas:extension:enterprise:2\.0:User.department`).String(), "%s", user)
+		assert.Equal(t, "User", user.Get("meta.resourceType").String(), "%s", user)
+		assert.True(t, strings.HasSuffix(user.Get("meta.location").String(), scim.RouteUsers+"/"+id), "%s", user)
+		assert.False(t, user.Get("password").Exists(), "%s", user)
+
+		i := getIdentity(t, id)
+		assert.JSONEq(t, `{"email":"create@example.org","name":{"first":"Jane","last":"Doe"},"department":"R&D"}`, string(i.Traits))
+		assert.JSONEq(t, `{"groups":["Engineering"]}`, string(i.MetadataPublic))
+		assert.Equal(t, "create@example.org", gjson.GetBytes(i.MetadataAdmin, "scim.userName").String())
+		assert.False(t, gjson.GetBytes(i.MetadataAdmin, "scim.password").Exists())
+		assert.EqualValues(t, "ext-create@example.org", i.ExternalID)
+		assert.Equal(t, identity.StateActive, i.State)
+
+		creds, ok := i.GetCredentials(identity.CredentialsTypePassword)
+		require.True(t, ok)
+		assert.Equal(t, []string{"create@example.org"}, creds.Identifiers)
+	})
+
+	t.Run("case=rejects conflicting users", func(t *testing.T) {
+		createUser(t, "conflict@example.org")
+		res := send(t, "POST", "/Users", `{"userName": "conflict@example.org"}`, http.StatusConflict)
+		assert.Equal(t, scim.SchemaError, res.Get("schemas.0").String(), "%s", res)
+		assert.Equal(t, "409", res.Get("status").String(), "%s", res)
+		assert.Equal(t, scim.ErrorTypeUniqueness, res.Get("scimType").String(), "%s", res)
+	})
+
+	t.Run("case=rejects invalid users", func(t *testing.T) {
+		res := send(t, "POST", "/Users", `{"name": {"givenName": "Jane"}}`, http.StatusBadRequest)
+		assert.Equal(t, scim.ErrorTypeInvalidValue, res.Get("scimType").String(), "%s", res)
+
+		res = send(t, "POST", "/Users", `{"userName": "not-an-email"}`, http.StatusBadRequest)
+		assert.Equal(t, "400", res.Get("status").String(), "%s", res)
+
+		send(t, "POST", "/Users", `{`, http.StatusBadRequest)
+	})
+
+	t.Run("case=gets a user", func(t *testing.T) {
+		id := createUser(t, "get@example.org").Get("id").String()
+
+		res := send(t, "GET", "/Users/"+id, "", http.StatusOK)
+		assert.Equal(t, id, res.Get("id").String(), "%s", res)
+		assert.Equal(t, "get@example.org", res.Get("userName").String(), "%s", res)
+		assert.Equal(t, scim.SchemaUser, res.Get("schemas.0").String(), "%s", res)
+
+		res = send(t, "GET", "/Users/"+uuid.Must(uuid.NewV4()).String(), "", http.StatusNotFound)
+		assert.Equal(t, "404", res.Get("status").String(), "%s", res)
+		send(t, "GET", "/Users/not-a-uuid", "", http.StatusNotFound)
+	})
+
+	t.Run("case=filters users", func(t *testing.T) {
+		id := createUser(t, "filter@example.org").Get("id").String()
+
+		for _, filter := range []string{
+			`userName eq "filter@example.org"`,
+			`username EQ "Filter@Example.org"`,
+			`externalId eq "ext-filter@example.org"`,
+			`id eq "` + id + `"`,
+		} {
+			t.Run("filter="+filter, func(t *testing.T) {
+				res := send(t, "GET", "/Users?filter="+url.QueryEscape(filter), "", http.StatusOK)
+				assert.Equal(t, scim.SchemaListResponse, res.Get("schemas.0").String(), "%s", res)
+				assert.EqualValues(t, 1, res.Get("totalResults").Int(), "%s", res)
+				assert.EqualValues(t, 1, res.Get("itemsPerPage").Int(), "%s", res)
+				assert.Equal(t, id, res.Get("Resources.0.id").String(), "%s", res)
+			})
+		}
+
+		res := send(t, "GET", "/Users?filter="+url.QueryEscape(`userName eq "unknown@example.org"`), "", http.StatusOK)
+		assert.EqualValues(t, 0, res.Get("totalResults").Int(), "%s", res)
+		assert.Empty(t, res.Get("Resources").Array(), "%s", res)
+
+		res = send(t, "GET", "/Users?filter="+url.QueryEscape(`userName sw "filter"`), "", http.StatusBadRequest)
+		assert.Equal(t, scim.ErrorTypeInvalidFilter, res.Get("scimType").String(), "%s", res)
+
+		res = send(t, "GET", "/Users?filter="+url.QueryEscape(`title eq "CEO"`), "", http.StatusBadRequest)
+		assert.Equal(t, scim.ErrorTypeInvalidFilter, res.Get("scimType").String(), "%s", res)
+	})
+
+	t.Run("case=paginates users", func(t *testing.T) {
+		for _, userName := range []string{"page-1@example.org", "page-2@example.org", "page-3@example.org"} {
+			createUser(t, userName)
+		}
+
+		total, err := reg.PrivilegedIdentityPool().CountIdentities(t.Context())
+		require.NoError(t, err)
+
+		seen := map[string]bool{}
+		for startIndex := 1; startIndex <= int(total); startIndex += 2 {
+			res := send(t, "GET", "/Users?count=2&startIndex="+strconv.Itoa(startIndex), "", http.StatusOK)
+			assert.EqualValues(t, total, res.Get("totalResults").Int(), "%s", res)
+			assert.EqualValues(t, startIndex, res.Get("startIndex").Int(), "%s", res)
+			for _, u := range res.Get("Resources").Array() {
+				assert.False(t, seen[u.Get("id").String()], "users must not be returned twice")
+				seen[u.Get("id").String()] = true
+			}
+		}
+		assert.Len(t, seen, int(total))
+
+		var all []string
+		for _, u := range send(t, "GET", "/Users?count="+strconv.Itoa(int(total)), "", http.StatusOK).Get("Resources").Array() {
+			all = append(all, u.Get("id").String())
+		}
+		require.Len(t, all, int(total))
+
+		t.Run("case=start index is not aligned with the count", func(t *testing.T) {
+			for startIndex := 2; startIndex <= int(total)+1; startIndex++ {
+				res := send(t, "GET", "/Users?count=2&startIndex="+strconv.Itoa(startIndex), "", http.StatusOK)
+				assert.EqualValues(t, startIndex, res.Get("startIndex").Int(), "%s", res)
+
+				actual := []string{}
+				for _, u := range res.Get("Resources").Array() {
+					actual = append(actual, u.Get("id").String())
+				}
+				expected := all[min(startIndex-1, len(all)):min(startIndex+1, len(all))]
+				assert.Equal(t, expected, actual, "startIndex=%d: %s", startIndex, res)
+			}
+		})
+
+		res := send(t, "GET", "/Users?count=0", "", http.StatusOK)
+		assert.EqualValues(t, total, res.Get("totalResults").Int(), "%s", res)
+		assert.Empty(t, res.Get("Resources").Array(), "%s", res)
+	})
+
+	t.Run("case=replaces a user", func(t *testing.T) {
+		id := createUser(t, "replace@example.org").Get("id").String()
+
+		res := send(t, "PUT", "/Users/"+id, `{
+  "schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+  "externalId": "ext-replaced",
+  "userName": "replaced@example.org",
+  "name": {"givenName": "Janet"},
+  "active": true
+}`, http.StatusOK)
+		assert.Equal(t, "replaced@example.org", res.Get("userName").String(), "%s", res)
+		assert.False(t, res.Get("emails").Exists(), "%s", res)
+
+		i := getIdentity(t, id)
+		assert.JSONEq(t, `{"email":"replaced@example.org","name":{"first":"Janet"}}`, string(i.Traits))
+		assert.EqualValues(t, "ext-replaced", i.ExternalID)
+
+		creds, ok := i.GetCredentials(identity.CredentialsTypePassword)
+		require.True(t, ok)
+		assert.Equal(t, []string{"replaced@example.org"}, creds.Identifiers)
+
+		send(t, "PUT", "/Users/"+uuid.Must(uuid.NewV4()).String(), `{"userName": "unknown@example.org"}`, http.StatusNotFound)
+	})
+
+	t.Run("case=patches a user", func(t *testing.T) {
+		id := createUser(t, "patch@example.org").Get("id").String()
+
+		res := send(t, "PATCH", "/Users/"+id, `{
+  "schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+  "Operations": [
+    {"op": "replace", "path": "name.givenName", "value": "Janet"},
+    {"op": "Replace", "path": "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "value": "Sales"},
+    {"op": "replace", "path": "emails[type eq \"work\"].value", "value": "janet@example.org"}
+  ]
+}`, http.StatusOK)
+		assert.Equal(t, "janet@example.org", res.Get("emails.0.value").String(), "%s", res)
+		assert.True(t, res.Get("active").Bool(), "%s", res)
+
+		i := getIdentity(t, id)
+		assert.JSONEq(t, `{"email":"patch@example.org","name":{"first":"Janet","last":"Doe"},"department":"Sales"}`, string(i.Traits))
+		assert.Equal(t, identity.StateActive, i.State)
+
+		res = send(t, "PATCH", "/Users/"+id, `{"Operations": [{"op": "move", "path": "userName"}]}`, http.StatusBadRequest)
+		assert.Equal(t, scim.ErrorTypeInvalidSyntax, res.Get("scimType").String(), "%s", res)
+	})
+
+	t.Run("case=deactivates and reactivates a user", func(t *testing.T) {
+		id := createUser(t, "deactivate@example.org").Get("id").String()
+
+		sess, err := testhelpers.NewActiveSession(testhelpers.NewTestHTTPRequest(t, "GET", "/", nil), reg, getIdentity(t, id), time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		require.NoError(t, reg.SessionPersister().UpsertSession(t.Context(), sess))
+
+		// Microsoft Entra ID sends booleans as strings.
+		res := send(t, "PATCH", "/Users/"+id, `{"Operations": [{"op": "Replace", "path": "active", "value": "False"}]}`, http.StatusOK)
+		assert.False(t, res.Get("active").Bool(), "%s", res)
+
+		i := getIdentity(t, id)
+		assert.Equal(t, identity.StateInactive, i.State)
+
+		// Deactivating the user revokes its sessions.
+		sess, err = reg.SessionPersister().GetSession(t.Context(), sess.ID, session.ExpandNothing)
+		require.NoError(t, err)
+		assert.False(t, sess.Active)
+		assert.JSONEq(t, `{"email":"deactivate@example.org","name":{"first":"Jane","last":"Doe"},"department":"R&D"}`, string(i.Traits))
+
+		// Other changes keep the identity inactive.
+		res = send(t, "PATCH", "/Users/"+id, `{"Operations": [{"op": "replace", "path": "name.familyName", "value": "Smith"}]}`, http.StatusOK)
+		assert.False(t, res.Get("active").Bool(), "%s", res)
+		assert.Equal(t, identity.StateInactive, getIdentity(t, id).State)
+
+		// Okta sends the attributes without a path.
+		res = send(t, "PATCH", "/Users/"+id, `{"Operations": [{"op": "replace", "value": {"active": true}}]}`, http.StatusOK)
+		assert.True(t, res.Get("active").Bool(), "%s", res)
+		assert.Equal(t, identity.StateActive, getIdentity(t, id).State)
+	})
+
+	t.Run("case=deletes a user", func(t *testing.T) {
+		id := createUser(t, "delete@example.org").Get("id").String()
+
+		send(t, "DELETE", "/Users/"+id, "", http.StatusNoContent)
+		send(t, "GET", "/Users/"+id, "", http.StatusNotFound)
+		send(t, "DELETE", "/Users/"+id, "", http.StatusNotFound)
+	})
+
+	t.Run("case=soft deletes a user if enabled", func(t *testing.T) {
+		conf.MustSet(t.Context(), config.ViperKeyIdentitySoftDeleteEnabled, true)
+		t.Cleanup(func() { conf.MustSet(context.Background(), config.ViperKeyIdentitySoftDeleteEnabled, false) })
+
+		id := createUser(t, "soft-delete@example.org").Get("id").String()
+
+		send(t, "DELETE", "/Users/"+id, "", http.StatusNoContent)
+		assert.Equal(t, identity.StateDeleted, getIdentity(t, id).State)
+
+		send(t, "GET", "/Users/"+id, "", http.StatusNotFound)
+		send(t, "DELETE", "/Users/"+id, "", http.StatusNotFound)
+		res := send(t, "GET", "/Users?filter="+url.QueryEscape(`id eq "`+id+`"`), "", http.StatusOK)
+		assert.EqualValues(t, 0, res.Get("totalResults").Int(), "%s", res)
+	})
+
+	t.Run("case=returns the service provider config", func(t *testing.T) {
+		res := send(t, "GET", "/ServiceProviderConfig", "", http.StatusOK)
+		assert.Equal(t, scim.SchemaServiceProviderConfig, res.Get("schemas.0").String(), "%s", res)
+		assert.True(t, res.Get("patch.supported").Bool(), "%s", res)
+		assert.True(t, res.Get("filter.supported").Bool(), "%s", res)
+		assert.False(t, res.Get("bulk.supported").Bool(), "%s", res)
+	})
+
+	t.Run("case=responds with not found if disabled", func(t *testing.T) {
+		conf.MustSet(t.Context(), config.ViperKeyIdentitySCIMEnabled, false)
+		t.Cleanup(func() {
+			conf.MustSet(t.Context(), config.ViperKeyIdentitySCIMEnabled, true)
+		})
+
+		res := send(t, "GET", "/Users", "", http.StatusNotFound)
+		assert.Equal(t, scim.SchemaError, res.Get("schemas.0").String(), "%s", res)
+	})
+}
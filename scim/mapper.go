@@ -0,0 +1,74 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/sqlxx"
+)
+
+// applyUser maps the user resource onto the identity using the configured
+// Jsonnet mapper.
+//
+// The mapper receives the user resource as `std.extVar('user')` and returns
+// the identity's traits and, optionally, its public and admin metadata,
+// using the same format as the OpenID Connect mapper. The user resource
+// itself is stored in the identity's admin metadata.
+func (h *Handler) applyUser(ctx context.Context, u User, i *identity.Identity) (err error) {
+	conf := h.d.Config().IdentitySCIM(ctx)
+
+	user, err := json.Marshal(u)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mapper := identity.JsonnetMapper{
+		Name:            "SCIM",
+		Method:          "scim",
+		URL:             conf.MapperURL,
+		Var:             "user",
+		Subject:         "user",
+		EvaluationError: herodot.ErrBadRequest.WithID(ErrorTypeInvalidValue),
+	}
+	evaluated, err := mapper.Map(ctx, h.d, user, i)
+	if err != nil {
+		return err
+	}
+
+	if i.MetadataAdmin, err = u.storeIn(i.MetadataAdmin); err != nil {
+		return err
+	}
+
+	i.ExternalID = sqlxx.NullString(u.externalID())
+
+	active, err := u.active()
+	if err != nil {
+		return err
+	}
+
+	state := identity.StateInactive
+	if active {
+		state = identity.StateActive
+	}
+	if i.State != state {
+		stateChangedAt := sqlxx.NullTime(time.Now())
+		i.State = state
+		i.StateChangedAt = &stateChangedAt
+	}
+
+	h.d.Logger().
+		WithSensitiveField("scim_user", u).
+		WithSensitiveField("mapper_jsonnet_output", evaluated).
+		WithField("mapper_jsonnet_url", conf.MapperURL).
+		Debug("SCIM Jsonnet mapper completed.")
+
+	return nil
+}
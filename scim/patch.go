@@ -0,0 +1,308 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+)
+
+type (
+	// patchRequest is a SCIM PATCH request as defined in RFC 7644, Section
+	// 3.5.2.
+	//
+	// swagger:model scimPatchRequest
+	patchRequest struct {
+		Schemas    []string         `json:"schemas"`
+		Operations []patchOperation `json:"Operations"`
+	}
+
+	patchOperation struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path,omitempty"`
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+
+	// path is a parsed SCIM attribute path, such as
+	// `emails[type eq "work"].value`.
+	path struct {
+		// Schema is set for attributes of schema extensions, for example
+		// `urn:ietf:params:scim:schemas:extension:enterprise:2.0:User`.
+		Schema       string
+		Attribute    string
+		Filter       *filter
+		SubAttribute string
+	}
+)
+
+// apply applies the operations of the PATCH request to the user resource.
+func (p *patchRequest) apply(u User) error {
+	for _, op := range p.Operations {
+		var value any
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidValue).WithReasonf("The value of the PATCH operation is not valid JSON: %s", err))
+			}
+		}
+
+		if err := applyOperation(u, strings.ToLower(op.Op), op.Path, value); err != nil {
+			return err
+		}
+	}
+
+	for _, attr := range readOnlyAttributes {
+		u.delete(attr)
+	}
+
+	return nil
+}
+
+func applyOperation(u User, op, rawPath string, value any) error {
+	switch op {
+	case patchOpAdd, patchOpReplace, patchOpRemove:
+	default:
+		return errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidSyntax).WithReasonf("The PATCH operation %q is not supported.", op))
+	}
+
+	if rawPath == "" {
+		// Without a path, the value contains the attributes to add or
+		// replace.
+		values, ok := value.(map[string]any)
+		if op == patchOpRemove || !ok {
+			return errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeNoTarget).WithReasonf("A PATCH operation without a path requires an object value and can not be used to remove attributes."))
+		}
+		for attr, v := range values {
+			if err := applyOperation(u, op, attr, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	p, err := parsePath(rawPath)
+	if err != nil {
+		return err
+	}
+
+	container := map[string]any(u)
+	if p.Schema != "" {
+		extension, ok := u.get(p.Schema).(map[string]any)
+		if !ok {
+			if op == patchOpRemove {
+				return nil
+			}
+			extension = map[string]any{}
+			u.set(p.Schema, extension)
+		}
+		container = extension
+	}
+
+	if p.Attribute == "" {
+		// The path refers to the schema extension itself.
+		if op == patchOpRemove {
+			u.delete(p.Schema)
+			return nil
+		}
+		return setValue(u, op, u.key(p.Schema), value)
+	}
+
+	key := findKey(container, p.Attribute)
+	if p.Filter != nil {
+		return applyFiltered(container, key, op, p, value)
+	}
+
+	if p.SubAttribute == "" {
+		if op == patchOpRemove {
+			delete(container, key)
+			return nil
+		}
+		return setValue(container, op, key, value)
+	}
+
+	switch existing := container[key].(type) {
+	case []any:
+		// Without a filter, the sub-attribute of all values is targeted.
+		for _, element := range existing {
+			if element, ok := element.(map[string]any); ok {
+				setSubAttribute(element, op, p.SubAttribute, value)
+			}
+		}
+	case map[string]any:
+		setSubAttribute(existing, op, p.SubAttribute, value)
+	default:
+		if op != patchOpRemove {
+			container[key] = map[string]any{p.SubAttribute: value}
+		}
+	}
+
+	return nil
+}
+
+// applyFiltered applies an operation to the values of a multi-valued
+// attribute which match the path's filter.
+func applyFiltered(container map[string]any, key, op string, p *path, value any) error {
+	existing, _ := container[key].([]any)
+
+	var (
+		matched bool
+		result  = make([]any, 0, len(existing))
+	)
+	for _, element := range existing {
+		if !p.Filter.matches(element) {
+			result = append(result, element)
+			continue
+		}
+		matched = true
+
+		element := element.(map[string]any)
+		switch {
+		case p.SubAttribute != "":
+			setSubAttribute(element, op, p.SubAttribute, value)
+		case op == patchOpRemove:
+			continue
+		case op == patchOpReplace:
+			v, ok := value.(map[string]any)
+			if !ok {
+				return errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidValue).WithReasonf("The value for path %q must be an object.", p))
+			}
+			element = v
+		default:
+			if v, ok := value.(map[string]any); ok {
+				for k, sv := range v {
+					element[findKey(element, k)] = sv
+				}
+			}
+		}
+		result = append(result, element)
+	}
+
+	if !matched {
+		if op == patchOpRemove || p.SubAttribute == "" {
+			return errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeNoTarget).WithReasonf("The filter of path %q did not match any values.", p))
+		}
+
+		// Clients such as Microsoft Entra ID set values like
+		// `emails[type eq "work"].value` even if no work email exists yet.
+		result = append(result, map[string]any{
+			p.Filter.Attribute: p.Filter.Value,
+			p.SubAttribute:     value,
+		})
+	}
+
+	container[key] = result
+	return nil
+}
+
+// setValue adds or replaces the value of an attribute. Sub-attributes of
+// complex attributes are merged, and values are appended to multi-valued
+// attributes when adding.
+func setValue(container map[string]any, op, key string, value any) error {
+	switch existing := container[key].(type) {
+	case map[string]any:
+		if v, ok := value.(map[string]any); ok {
+			for k, sv := range v {
+				existing[findKey(existing, k)] = sv
+			}
+			return nil
+		}
+	case []any:
+		if op == patchOpAdd {
+			if v, ok := value.([]any); ok {
+				container[key] = append(existing, v...)
+			} else {
+				container[key] = append(existing, value)
+			}
+			return nil
+		}
+	}
+
+	container[key] = value
+	return nil
+}
+
+func setSubAttribute(element map[string]any, op, name string, value any) {
+	if op == patchOpRemove {
+		delete(element, findKey(element, name))
+		return
+	}
+	element[findKey(element, name)] = value
+}
+
+// parsePath parses a SCIM attribute path.
+//
+// Attributes of schema extensions are prefixed with the schema URN, for
+// example `urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department`.
+// By convention, the URNs of user schema extensions end with `User`, which
+// is used to tell the schema apart from the attribute.
+func parsePath(raw string) (*path, error) {
+	var p path
+
+	rest := raw
+	if strings.HasPrefix(strings.ToLower(raw), "urn:") {
+		if strings.HasSuffix(strings.ToLower(raw), ":user") {
+			return &path{Schema: raw}, nil
+		}
+
+		// The last colon outside of a filter separates the schema from the
+		// attribute.
+		end := len(raw)
+		if i := strings.Index(raw, "["); i >= 0 {
+			end = i
+		}
+		i := strings.LastIndex(raw[:end], ":")
+		p.Schema, rest = raw[:i], raw[i+1:]
+	}
+
+	if i := strings.Index(rest, "["); i >= 0 {
+		j := strings.LastIndex(rest, "]")
+		if j < i {
+			return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidPath).WithReasonf("The path %q is not valid.", raw))
+		}
+
+		f, err := parseFilter(rest[i+1 : j])
+		if err != nil {
+			return nil, err
+		}
+
+		p.Attribute, p.Filter = rest[:i], f
+		p.SubAttribute = strings.TrimPrefix(rest[j+1:], ".")
+	} else {
+		p.Attribute, p.SubAttribute, _ = strings.Cut(rest, ".")
+	}
+
+	if p.Attribute == "" || strings.ContainsAny(p.Attribute+p.SubAttribute, "[]. ") {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidPath).WithReasonf("The path %q is not valid.", raw))
+	}
+
+	return &p, nil
+}
+
+func (p *path) String() string {
+	var b strings.Builder
+	if p.Schema != "" {
+		b.WriteString(p.Schema)
+		if p.Attribute != "" {
+			b.WriteString(":")
+		}
+	}
+	b.WriteString(p.Attribute)
+	if p.Filter != nil {
+		v, _ := json.Marshal(p.Filter.Value)
+		b.WriteString("[" + p.Filter.Attribute + " eq " + string(v) + "]")
+	}
+	if p.SubAttribute != "" {
+		b.WriteString("." + p.SubAttribute)
+	}
+	return b.String()
+}
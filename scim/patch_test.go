@@ -0,0 +1,177 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ory/herodot"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tc := range []struct {
+		raw      string
+		expected path
+	}{
+		{raw: "userName", expected: path{Attribute: "userName"}},
+		{raw: "name.givenName", expected: path{Attribute: "name", SubAttribute: "givenName"}},
+		{raw: `emails[type eq "work"]`, expected: path{Attribute: "emails", Filter: &filter{Attribute: "type", Value: "work"}}},
+		{raw: `emails[type eq "work"].value`, expected: path{Attribute: "emails", Filter: &filter{Attribute: "type", Value: "work"}, SubAttribute: "value"}},
+		{raw: `emails[primary eq true].value`, expected: path{Attribute: "emails", Filter: &filter{Attribute: "primary", Value: true}, SubAttribute: "value"}},
+		{
+			raw:      "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User",
+			expected: path{Schema: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"},
+		},
+		{
+			raw:      "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department",
+			expected: path{Schema: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User", Attribute: "department"},
+		},
+		{
+			raw:      "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:manager.value",
+			expected: path{Schema: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User", Attribute: "manager", SubAttribute: "value"},
+		},
+	} {
+		t.Run("path="+tc.raw, func(t *testing.T) {
+			p, err := parsePath(tc.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, *p)
+			assert.Equal(t, tc.raw, p.String())
+		})
+	}
+
+	for _, raw := range []string{
+		"",
+		"name.givenName.first",
+		`emails[type eq "work"`,
+		`emails[type gt "work"]`,
+		`emails[type eq work]`,
+	} {
+		t.Run("invalid="+raw, func(t *testing.T) {
+			_, err := parsePath(raw)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPatchRequest(t *testing.T) {
+	newUser := func() User {
+		return User{
+			"userName": "jane@example.org",
+			"name":     map[string]any{"givenName": "Jane", "familyName": "Doe"},
+			"emails": []any{
+				map[string]any{"type": "work", "value": "jane@example.org", "primary": true},
+				map[string]any{"type": "home", "value": "jane@example.com"},
+			},
+		}
+	}
+
+	apply := func(t *testing.T, u User, ops string) error {
+		var p patchRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"schemas":["`+SchemaPatchOp+`"],"Operations":`+ops+`}`), &p))
+		return p.apply(u)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		ops      string
+		expected func(u User)
+	}{
+		{
+			name:     "replace a simple attribute",
+			ops:      `[{"op":"replace","path":"userName","value":"john@example.org"}]`,
+			expected: func(u User) { u["userName"] = "john@example.org" },
+		},
+		{
+			name:     "operations and attribute names are case-insensitive",
+			ops:      `[{"op":"Replace","path":"USERNAME","value":"john@example.org"}]`,
+			expected: func(u User) { u["userName"] = "john@example.org" },
+		},
+		{
+			name:     "replace a sub-attribute",
+			ops:      `[{"op":"replace","path":"name.givenName","value":"Janet"}]`,
+			expected: func(u User) { u["name"].(map[string]any)["givenName"] = "Janet" },
+		},
+		{
+			name:     "replace without a path",
+			ops:      `[{"op":"replace","value":{"active":false,"name.familyName":"Smith"}}]`,
+			expected: func(u User) { u["active"] = false; u["name"].(map[string]any)["familyName"] = "Smith" },
+		},
+		{
+			name:     "add merges complex attributes",
+			ops:      `[{"op":"add","path":"name","value":{"middleName":"Q"}}]`,
+			expected: func(u User) { u["name"].(map[string]any)["middleName"] = "Q" },
+		},
+		{
+			name: "add appends to multi-valued attributes",
+			ops:  `[{"op":"add","path":"emails","value":[{"type":"other","value":"jd@example.net"}]}]`,
+			expected: func(u User) {
+				u["emails"] = append(u["emails"].([]any), map[string]any{"type": "other", "value": "jd@example.net"})
+			},
+		},
+		{
+			name:     "replace the sub-attribute of filtered values",
+			ops:      `[{"op":"replace","path":"emails[type eq \"work\"].value","value":"jane.doe@example.org"}]`,
+			expected: func(u User) { u["emails"].([]any)[0].(map[string]any)["value"] = "jane.doe@example.org" },
+		},
+		{
+			name: "replace the sub-attribute of a value which does not exist yet",
+			ops:  `[{"op":"replace","path":"phoneNumbers[type eq \"work\"].value","value":"+4912345"}]`,
+			expected: func(u User) {
+				u["phoneNumbers"] = []any{map[string]any{"type": "work", "value": "+4912345"}}
+			},
+		},
+		{
+			name:     "remove filtered values",
+			ops:      `[{"op":"remove","path":"emails[type eq \"home\"]"}]`,
+			expected: func(u User) { u["emails"] = u["emails"].([]any)[:1] },
+		},
+		{
+			name:     "remove an attribute",
+			ops:      `[{"op":"remove","path":"name"}]`,
+			expected: func(u User) { delete(u, "name") },
+		},
+		{
+			name: "add an extension attribute",
+			ops:  `[{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department","value":"Engineering"}]`,
+			expected: func(u User) {
+				u["urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"] = map[string]any{"department": "Engineering"}
+			},
+		},
+		{
+			name:     "read-only attributes are ignored",
+			ops:      `[{"op":"add","path":"id","value":"foo"},{"op":"add","path":"password","value":"secret"}]`,
+			expected: func(User) {},
+		},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			actual, expected := newUser(), newUser()
+			require.NoError(t, apply(t, actual, tc.ops))
+			tc.expected(expected)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	for _, tc := range []struct {
+		name, ops, errorType string
+	}{
+		{name: "unknown operation", ops: `[{"op":"move","path":"userName"}]`, errorType: ErrorTypeInvalidSyntax},
+		{name: "remove without a path", ops: `[{"op":"remove"}]`, errorType: ErrorTypeNoTarget},
+		{name: "filter without matches", ops: `[{"op":"remove","path":"emails[type eq \"other\"]"}]`, errorType: ErrorTypeNoTarget},
+		{name: "invalid path", ops: `[{"op":"replace","path":"emails[type eq \"work\"","value":"x"}]`, errorType: ErrorTypeInvalidPath},
+		{name: "unsupported filter", ops: `[{"op":"replace","path":"emails[type ne \"work\"].value","value":"x"}]`, errorType: ErrorTypeInvalidFilter},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			err := apply(t, newUser(), tc.ops)
+			require.Error(t, err)
+
+			var de *herodot.DefaultError
+			require.ErrorAs(t, err, &de)
+			assert.Equal(t, tc.errorType, de.ID())
+		})
+	}
+}
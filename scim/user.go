@@ -0,0 +1,177 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/sqlxx"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// ContentType is the media type of SCIM requests and responses.
+	ContentType = "application/scim+json"
+
+	// MetadataKey is the key in the identity's admin metadata under which the
+	// SCIM resource, as last provisioned by the client, is stored.
+	MetadataKey = "scim"
+)
+
+// User is a SCIM user resource.
+//
+// Apart from a few attributes which are managed by Ory Kratos, such as `id`,
+// `meta`, and `active`, the resource is stored as sent by the client, so
+// that custom and extension attributes survive a round trip.
+//
+// swagger:model scimUser
+type User map[string]any
+
+// attributes which are managed by Ory Kratos and never stored. Passwords are
+// dropped so that they do not end up in the identity's admin metadata.
+var readOnlyAttributes = []string{"id", "meta", "password"}
+
+// newUser decodes a user resource sent by a SCIM client.
+func newUser(raw []byte) (User, error) {
+	var u User
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidSyntax).WithReasonf("The request body is not a valid SCIM resource: %s", err))
+	}
+	if u == nil {
+		u = User{}
+	}
+
+	for _, attr := range readOnlyAttributes {
+		u.delete(attr)
+	}
+
+	return u, nil
+}
+
+// userFromIdentity returns the SCIM user resource of an identity.
+func userFromIdentity(i *identity.Identity, location string) User {
+	u := User{}
+	if stored := gjson.GetBytes(i.MetadataAdmin, MetadataKey); stored.IsObject() {
+		_ = json.Unmarshal([]byte(stored.Raw), &u)
+	}
+
+	if schemas, ok := u.get("schemas").([]any); !ok || len(schemas) == 0 {
+		u.set("schemas", []any{SchemaUser})
+	}
+	u.set("id", i.ID.String())
+	u.set("active", i.State == identity.StateActive)
+	if i.ExternalID != "" {
+		u.set("externalId", string(i.ExternalID))
+	}
+	u.set("meta", map[string]any{
+		"resourceType": "User",
+		"created":      i.CreatedAt.UTC().Format(time.RFC3339),
+		"lastModified": i.UpdatedAt.UTC().Format(time.RFC3339),
+		"location":     location,
+	})
+
+	return u
+}
+
+// active returns the value of the `active` attribute, which defaults to true.
+//
+// Some SCIM clients send booleans as strings, which is why those are
+// accepted as well.
+func (u User) active() (bool, error) {
+	switch v := u.get("active").(type) {
+	case nil:
+		return true, nil
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, errors.WithStack(herodot.ErrBadRequest.WithID(ErrorTypeInvalidValue).WithReason("The attribute active must be a boolean."))
+}
+
+// userName returns the value of the `userName` attribute.
+func (u User) userName() string {
+	v, _ := u.get("userName").(string)
+	return v
+}
+
+// externalID returns the value of the `externalId` attribute.
+func (u User) externalID() string {
+	v, _ := u.get("externalId").(string)
+	return v
+}
+
+// key returns the key of an attribute. Attribute names are case-insensitive.
+func (u User) key(name string) string {
+	return findKey(u, name)
+}
+
+func (u User) get(name string) any {
+	return u[u.key(name)]
+}
+
+func (u User) set(name string, value any) {
+	u[u.key(name)] = value
+}
+
+func (u User) delete(name string) {
+	delete(u, u.key(name))
+}
+
+// storeIn stores the user resource in the identity's admin metadata.
+func (u User) storeIn(metadata sqlxx.NullJSONRawMessage) (sqlxx.NullJSONRawMessage, error) {
+	stored := make(User, len(u))
+	for k, v := range u {
+		stored[k] = v
+	}
+	// The state is tracked by the identity itself.
+	stored.delete("active")
+
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(metadata) == 0 || !gjson.ParseBytes(metadata).IsObject() {
+		metadata = sqlxx.NullJSONRawMessage("{}")
+	}
+
+	updated, err := sjson.SetRawBytes(metadata, MetadataKey, raw)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return updated, nil
+}
+
+// findKey returns the key in m which matches name case-insensitively, or
+// name if there is none.
+func findKey(m map[string]any, name string) string {
+	if _, ok := m[name]; ok {
+		return name
+	}
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
@@ -0,0 +1,69 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+// failedLoginsScope is the scope of the counters of failed login attempts
+// per client IP address. They are stored alongside the login lockout
+// counters, but never lock logins.
+const failedLoginsScope identity.LoginLockoutScope = "captcha_ip"
+
+// failedLoginsWindow is the duration after which failed login attempts are
+// forgotten if no further attempt fails.
+const failedLoginsWindow = time.Hour
+
+// countsFailedLogins returns true if failed login attempts decide whether a
+// captcha is required.
+func (v *Verifier) countsFailedLogins(ctx context.Context) bool {
+	conf := v.d.Config().SecurityCaptcha(ctx)
+	return conf.Enabled && conf.RequiredAfterFailedAttempts > 0
+}
+
+// RecordFailedLogin counts a failed login attempt from the client IP address
+// if a captcha is only required after a number of failed attempts.
+func (v *Verifier) RecordFailedLogin(r *http.Request) (err error) {
+	ctx, span := v.d.Tracer(r.Context()).Tracer().Start(r.Context(), "selfservice.captcha.Verifier.RecordFailedLogin")
+	defer otelx.End(span, &err)
+
+	if !v.countsFailedLogins(ctx) {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	_, err = v.d.LoginLockoutPersister().UpdateLoginLockout(ctx, failedLoginsScope, identity.LoginLockoutClientIP(ctx, v.d.Config(), r), "", func(l *identity.LoginLockout) {
+		if now.Sub(l.LastFailedAt) > failedLoginsWindow {
+			l.FailedAttempts = 0
+		}
+		l.FailedAttempts++
+		l.LastFailedAt = now
+	})
+	return err
+}
+
+// failedLogins returns the number of failed login attempts from the client IP
+// address within the window.
+func (v *Verifier) failedLogins(ctx context.Context, r *http.Request) (int, error) {
+	l, err := v.d.LoginLockoutPersister().GetLoginLockout(ctx, failedLoginsScope, identity.LoginLockoutClientIP(ctx, v.d.Config(), r), "")
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	if time.Now().UTC().Sub(l.LastFailedAt) > failedLoginsWindow {
+		return 0, nil
+	}
+	return l.FailedAttempts, nil
+}
@@ -0,0 +1,200 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+)
+
+const (
+	ProviderTurnstile = "turnstile"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCAPTCHA = "recaptcha"
+
+	// TokenFieldName is the name of the form field the captcha response is
+	// submitted in. The provider specific field names are accepted as well.
+	TokenFieldName = "captcha_token"
+
+	nodeScript    = "captcha_script"
+	nodeContainer = "captcha"
+)
+
+var (
+	ErrVerificationFailed = errors.New("the captcha response could not be verified")
+	ErrMissingToken       = errors.New("the captcha response is missing")
+)
+
+type (
+	// Provider verifies captcha responses and describes the widget which
+	// produces them.
+	Provider interface {
+		// ID returns the provider's configuration name.
+		ID() string
+
+		// Nodes returns the UI nodes which render the widget for the given action.
+		Nodes(action string) node.Nodes
+
+		// TokenFields returns the form field names the response may be submitted in.
+		TokenFields() []string
+
+		// Verify checks the response with the provider's verification API.
+		Verify(ctx context.Context, token, remoteIP, action string) error
+	}
+
+	// siteVerifyProvider implements Provider for all supported captcha
+	// services, as they share the same `siteverify` style API: the secret
+	// and the response are posted as a form, and a JSON object containing
+	// `success` and optionally `action` and `score` is returned.
+	siteVerifyProvider struct {
+		id            string
+		class         string
+		responseField string
+		scriptURL     string
+		verifyURL     string
+		siteKey       string
+		secretKey     string
+		minScore      float64
+		scored        bool
+		client        *retryablehttp.Client
+	}
+
+	siteVerifyResponse struct {
+		Success    bool     `json:"success"`
+		Action     string   `json:"action"`
+		Score      *float64 `json:"score"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+)
+
+var _ Provider = (*siteVerifyProvider)(nil)
+
+// NewProvider returns the provider for the given configuration.
+func NewProvider(c *config.Captcha, client *retryablehttp.Client) (Provider, error) {
+	p := &siteVerifyProvider{
+		id:        c.Provider,
+		siteKey:   c.SiteKey,
+		secretKey: c.SecretKey,
+		minScore:  c.MinScore,
+		client:    client,
+	}
+
+	switch c.Provider {
+	case ProviderTurnstile:
+		p.class = "cf-turnstile"
+		p.responseField = "cf-turnstile-response"
+		p.scriptURL = "https://challenges.cloudflare.com/turnstile/v0/api.js"
+		p.verifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	case ProviderHCaptcha:
+		p.class = "h-captcha"
+		p.responseField = "h-captcha-response"
+		p.scriptURL = "https://js.hcaptcha.com/1/api.js"
+		p.verifyURL = "https://api.hcaptcha.com/siteverify"
+	case ProviderReCAPTCHA:
+		p.class = "g-recaptcha"
+		p.responseField = "g-recaptcha-response"
+		p.scriptURL = "https://www.google.com/recaptcha/api.js?render=" + url.QueryEscape(c.SiteKey)
+		p.verifyURL = "https://www.google.com/recaptcha/api/siteverify"
+		p.scored = true
+	default:
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("The captcha provider %q is not supported.", c.Provider))
+	}
+
+	if c.ScriptURL != nil {
+		p.scriptURL = c.ScriptURL.String()
+	}
+	if c.VerifyURL != nil {
+		p.verifyURL = c.VerifyURL.String()
+	}
+
+	return p, nil
+}
+
+func (p *siteVerifyProvider) ID() string {
+	return p.id
+}
+
+func (p *siteVerifyProvider) TokenFields() []string {
+	return []string{TokenFieldName, p.responseField}
+}
+
+func (p *siteVerifyProvider) Nodes(action string) node.Nodes {
+	data := map[string]string{
+		"sitekey": p.siteKey,
+		"action":  action,
+	}
+	if p.id == ProviderTurnstile {
+		// Turnstile writes the response into the field of our choosing.
+		data["response-field-name"] = TokenFieldName
+	}
+
+	return node.Nodes{
+		node.NewScriptField(nodeScript, p.scriptURL, node.CaptchaGroup, "", func(a *node.ScriptAttributes) {
+			// The provider's script is not versioned, so neither its
+			// integrity nor CORS can be enforced.
+			a.CrossOrigin = ""
+		}),
+		node.NewDivisionField(nodeContainer, node.CaptchaGroup, node.WithDivisionAttributes(func(a *node.DivisionAttributes) {
+			a.Classname = p.class
+			a.Data = data
+		})).WithMetaLabel(text.NewCaptchaContainerMessage()),
+		node.NewInputField(TokenFieldName, "", node.CaptchaGroup, node.InputAttributeTypeHidden),
+	}
+}
+
+func (p *siteVerifyProvider) Verify(ctx context.Context, token, remoteIP, action string) error {
+	form := url.Values{
+		"secret":   {p.secretKey},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	if p.id == ProviderHCaptcha {
+		form.Set("sitekey", p.siteKey)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, "POST", p.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return errors.WithStack(herodot.ErrUpstreamError.WithWrap(err).WithReasonf("Unable to reach the captcha verification API."))
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.WithStack(herodot.ErrUpstreamError.WithReasonf("The captcha verification API responded with status code %d.", res.StatusCode))
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return errors.WithStack(herodot.ErrUpstreamError.WithWrap(err).WithReasonf("Unable to decode the captcha verification response."))
+	}
+
+	switch {
+	case !result.Success:
+		return errors.Wrapf(ErrVerificationFailed, "error codes: %s", strings.Join(result.ErrorCodes, ", "))
+	case result.Action != "" && result.Action != action:
+		return errors.Wrapf(ErrVerificationFailed, "expected action %q but got %q", action, result.Action)
+	case p.scored && (result.Score == nil || *result.Score < p.minScore):
+		return errors.Wrap(ErrVerificationFailed, "score is below the configured minimum")
+	}
+
+	return nil
+}
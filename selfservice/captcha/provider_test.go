@@ -0,0 +1,151 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package captcha_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/selfservice/captcha"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/x/urlx"
+)
+
+// newSiteVerifyServer returns a fake verification API which responds with
+// the response registered for the submitted token.
+func newSiteVerifyServer(t *testing.T, responses map[string]any) (*httptest.Server, *[]url.Values) {
+	var requests []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		requests = append(requests, r.PostForm)
+
+		res, ok := responses[r.PostForm.Get("response")]
+		if !ok {
+			res = map[string]any{"success": false, "error-codes": []string{"invalid-input-response"}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &requests
+}
+
+func TestProvider(t *testing.T) {
+	ctx := t.Context()
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+
+	t.Run("case=unknown provider", func(t *testing.T) {
+		_, err := captcha.NewProvider(&config.Captcha{Provider: "unknown"}, client)
+		require.Error(t, err)
+	})
+
+	for _, tc := range []struct {
+		provider, class, script, responseField string
+	}{
+		{captcha.ProviderTurnstile, "cf-turnstile", "https://challenges.cloudflare.com/turnstile/v0/api.js", "cf-turnstile-response"},
+		{captcha.ProviderHCaptcha, "h-captcha", "https://js.hcaptcha.com/1/api.js", "h-captcha-response"},
+		{captcha.ProviderReCAPTCHA, "g-recaptcha", "https://www.google.com/recaptcha/api.js?render=site-key", "g-recaptcha-response"},
+	} {
+		t.Run("provider="+tc.provider, func(t *testing.T) {
+			p, err := captcha.NewProvider(&config.Captcha{Provider: tc.provider, SiteKey: "site-key", SecretKey: "secret-key", MinScore: 0.5}, client)
+			require.NoError(t, err)
+			assert.Equal(t, tc.provider, p.ID())
+			assert.Equal(t, []string{captcha.TokenFieldName, tc.responseField}, p.TokenFields())
+
+			nodes := p.Nodes("login")
+			require.Len(t, nodes, 3)
+			for _, n := range nodes {
+				assert.Equal(t, node.CaptchaGroup, n.Group)
+			}
+
+			script, ok := nodes[0].Attributes.(*node.ScriptAttributes)
+			require.True(t, ok)
+			assert.Equal(t, tc.script, script.Source)
+
+			container, ok := nodes[1].Attributes.(*node.DivisionAttributes)
+			require.True(t, ok)
+			assert.Equal(t, tc.class, container.Classname)
+			assert.Equal(t, "site-key", container.Data["sitekey"])
+			assert.Equal(t, "login", container.Data["action"])
+
+			assert.Equal(t, captcha.TokenFieldName, nodes[2].ID())
+		})
+	}
+
+	t.Run("case=verifies responses", func(t *testing.T) {
+		ts, requests := newSiteVerifyServer(t, map[string]any{
+			"valid":        map[string]any{"success": true},
+			"valid-action": map[string]any{"success": true, "action": "login"},
+			"other-action": map[string]any{"success": true, "action": "registration"},
+			"high-score":   map[string]any{"success": true, "score": 0.9},
+			"low-score":    map[string]any{"success": true, "score": 0.1},
+		})
+
+		newProvider := func(t *testing.T, provider string) captcha.Provider {
+			p, err := captcha.NewProvider(&config.Captcha{
+				Provider:  provider,
+				SiteKey:   "site-key",
+				SecretKey: "secret-key",
+				MinScore:  0.5,
+				VerifyURL: urlx.ParseOrPanic(ts.URL),
+			}, client)
+			require.NoError(t, err)
+			return p
+		}
+
+		t.Run("provider=turnstile", func(t *testing.T) {
+			p := newProvider(t, captcha.ProviderTurnstile)
+
+			require.NoError(t, p.Verify(ctx, "valid", "192.0.2.1", "login"))
+			req := (*requests)[len(*requests)-1]
+			assert.Equal(t, "secret-key", req.Get("secret"))
+			assert.Equal(t, "valid", req.Get("response"))
+			assert.Equal(t, "192.0.2.1", req.Get("remoteip"))
+
+			require.NoError(t, p.Verify(ctx, "valid-action", "", "login"))
+			assert.ErrorIs(t, p.Verify(ctx, "other-action", "", "login"), captcha.ErrVerificationFailed)
+			assert.ErrorIs(t, p.Verify(ctx, "invalid", "", "login"), captcha.ErrVerificationFailed)
+		})
+
+		t.Run("provider=hcaptcha", func(t *testing.T) {
+			p := newProvider(t, captcha.ProviderHCaptcha)
+
+			require.NoError(t, p.Verify(ctx, "valid", "", "login"))
+			assert.Equal(t, "site-key", (*requests)[len(*requests)-1].Get("sitekey"))
+			assert.ErrorIs(t, p.Verify(ctx, "invalid", "", "login"), captcha.ErrVerificationFailed)
+		})
+
+		t.Run("provider=recaptcha", func(t *testing.T) {
+			p := newProvider(t, captcha.ProviderReCAPTCHA)
+
+			require.NoError(t, p.Verify(ctx, "high-score", "", "login"))
+			assert.ErrorIs(t, p.Verify(ctx, "low-score", "", "login"), captcha.ErrVerificationFailed)
+			assert.ErrorIs(t, p.Verify(ctx, "valid", "", "login"), captcha.ErrVerificationFailed, "responses without a score must be rejected")
+		})
+	})
+
+	t.Run("case=fails on unexpected status codes", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(ts.Close)
+
+		p, err := captcha.NewProvider(&config.Captcha{Provider: captcha.ProviderTurnstile, VerifyURL: urlx.ParseOrPanic(ts.URL)}, client)
+		require.NoError(t, err)
+
+		err = p.Verify(ctx, "valid", "", "login")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, captcha.ErrVerificationFailed)
+	})
+}
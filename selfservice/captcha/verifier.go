@@ -0,0 +1,183 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package captcha
+
+import (
+	_ "embed"
+	"net/http"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/otelx"
+)
+
+//go:embed .schema/captcha.schema.json
+var captchaSchema []byte
+
+var dec = decoderx.NewHTTP()
+
+type (
+	verifierDependencies interface {
+		config.Provider
+		identity.LoginLockoutPersistenceProvider
+		x.HTTPClientProvider
+		x.LoggingProvider
+		x.TracingProvider
+	}
+
+	VerificationProvider interface {
+		CaptchaVerifier() *Verifier
+	}
+
+	// Verifier adds captcha challenges to self-service flows and verifies
+	// their responses before any strategy handles the submission.
+	Verifier struct {
+		d verifierDependencies
+	}
+)
+
+func NewVerifier(d verifierDependencies) *Verifier {
+	return &Verifier{d: d}
+}
+
+// Required returns true if a captcha must be solved before the flow can be
+// submitted.
+//
+// A captcha is only required while the flow asks for the initial input, so
+// that entering a code which was sent out does not need another challenge.
+// If `required_after_failed_attempts` is set, a captcha is only required once
+// the client IP address reached that many failed login attempts.
+func (v *Verifier) Required(r *http.Request, f flow.Flow) (bool, error) {
+	conf := v.d.Config().SecurityCaptcha(r.Context())
+	if !conf.Enabled || !slices.Contains(conf.Flows, string(f.GetFlowName())) {
+		return false, nil
+	}
+
+	switch f.GetState() {
+	case flow.StateChooseMethod, flow.StateRecoveryAwaitingAddress:
+	default:
+		return false, nil
+	}
+
+	if conf.RequiredAfterFailedAttempts == 0 {
+		return true, nil
+	}
+
+	attempts, err := v.failedLogins(r.Context(), r)
+	if err != nil {
+		return false, err
+	}
+
+	return attempts >= conf.RequiredAfterFailedAttempts, nil
+}
+
+// PopulateNodes adds the captcha widget to the flow's UI if a captcha is
+// required.
+func (v *Verifier) PopulateNodes(r *http.Request, f flow.Flow) (err error) {
+	ctx, span := v.d.Tracer(r.Context()).Tracer().Start(r.Context(), "selfservice.captcha.Verifier.PopulateNodes")
+	defer otelx.End(span, &err)
+	r = r.WithContext(ctx)
+
+	if required, err := v.Required(r, f); err != nil || !required {
+		return err
+	}
+
+	p, err := NewProvider(v.d.Config().SecurityCaptcha(ctx), v.d.HTTPClient(ctx))
+	if err != nil {
+		return err
+	}
+
+	for _, n := range p.Nodes(string(f.GetFlowName())) {
+		f.GetUI().Nodes.Upsert(n)
+	}
+
+	return nil
+}
+
+// Verify checks the captcha response submitted with the flow. If the
+// response is missing or invalid, the captcha widget is added to the flow's
+// UI and a validation error is returned.
+func (v *Verifier) Verify(r *http.Request, f flow.Flow) (err error) {
+	ctx, span := v.d.Tracer(r.Context()).Tracer().Start(r.Context(), "selfservice.captcha.Verifier.Verify")
+	defer otelx.End(span, &err)
+
+	// Flows are only submitted using POST. Other methods, such as following
+	// a verification link, do not carry a captcha response.
+	if r.Method != http.MethodPost {
+		return nil
+	}
+
+	if required, err := v.Required(r.WithContext(ctx), f); err != nil || !required {
+		return err
+	}
+
+	conf := v.d.Config().SecurityCaptcha(ctx)
+	p, err := NewProvider(conf, v.d.HTTPClient(ctx))
+	if err != nil {
+		return err
+	}
+
+	// The body is decoded from the original request, as the decoder restores
+	// it there for the strategies which handle the submission afterwards.
+	token, err := v.token(r, p)
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		err = ErrMissingToken
+	} else {
+		err = p.Verify(ctx, token, x.TrustedClientIP(r, v.d.Config().SecurityTrustedProxies(ctx)), string(f.GetFlowName()))
+	}
+
+	if err != nil {
+		v.d.Logger().
+			WithRequest(r).
+			WithError(err).
+			WithField("captcha_provider", p.ID()).
+			Info("Captcha verification failed.")
+
+		// The widget needs to be rendered again, as the response can only
+		// be used once.
+		for _, n := range p.Nodes(string(f.GetFlowName())) {
+			f.GetUI().Nodes.Upsert(n)
+		}
+
+		return errors.WithStack(schema.NewCaptchaFailedError())
+	}
+
+	return nil
+}
+
+func (v *Verifier) token(r *http.Request, p Provider) (string, error) {
+	var body map[string]any
+
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(captchaSchema)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if err := dec.Decode(r, &body, compiler,
+		decoderx.HTTPKeepRequestBody(true),
+		decoderx.HTTPDecoderAllowedMethods("POST"),
+		decoderx.HTTPDecoderSetValidatePayloads(false),
+		decoderx.HTTPDecoderJSONFollowsFormFormat()); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	for _, field := range p.TokenFields() {
+		if token, ok := body[field].(string); ok && token != "" {
+			return token, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,139 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package captcha_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/selfservice/captcha"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/text"
+	"github.com/ory/x/configx"
+)
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	ts, _ := newSiteVerifyServer(t, map[string]any{
+		"valid": map[string]any{"success": true, "action": "login"},
+	})
+
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValue(config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypePassword)+".enabled", true),
+		configx.WithValue(config.ViperKeySecurityCaptchaEnabled, true),
+		configx.WithValue(config.ViperKeySecurityCaptchaSiteKey, "site-key"),
+		configx.WithValue(config.ViperKeySecurityCaptchaSecretKey, "secret-key"),
+		configx.WithValue(config.ViperKeySecurityCaptchaVerifyURL, ts.URL),
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+	)
+	publicTS, _ := testhelpers.NewKratosServer(t, reg)
+	_ = testhelpers.NewLoginUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewErrorTestServer(t, reg)
+
+	submit := func(t *testing.T, values func(url.Values)) string {
+		return testhelpers.SubmitLoginForm(t, true, nil, publicTS, values, false, false,
+			http.StatusBadRequest, publicTS.URL+login.RouteSubmitFlow)
+	}
+
+	credentials := func(token string) func(url.Values) {
+		return func(v url.Values) {
+			v.Set("method", identity.CredentialsTypePassword.String())
+			v.Set("identifier", "captcha@ory.sh")
+			v.Set("password", "not-the-password")
+			if token != "" {
+				v.Set(captcha.TokenFieldName, token)
+			}
+		}
+	}
+
+	t.Run("case=flow contains the captcha widget", func(t *testing.T) {
+		f := testhelpers.InitializeLoginFlowViaAPI(t, http.DefaultClient, publicTS, false)
+
+		var groups []string
+		for _, n := range f.Ui.Nodes {
+			if n.Group == "captcha" {
+				groups = append(groups, n.Type)
+			}
+		}
+		assert.ElementsMatch(t, []string{"script", "div", "input"}, groups)
+	})
+
+	t.Run("case=rejects submissions without a captcha response", func(t *testing.T) {
+		body := submit(t, credentials(""))
+		assert.EqualValues(t, text.ErrorValidationCaptchaError, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		assert.True(t, gjson.Get(body, "ui.nodes.#(attributes.name==captcha_token)").Exists(), "%s", body)
+	})
+
+	t.Run("case=rejects submissions with an invalid captcha response", func(t *testing.T) {
+		body := submit(t, credentials("invalid"))
+		assert.EqualValues(t, text.ErrorValidationCaptchaError, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+	})
+
+	t.Run("case=passes submissions with a valid captcha response to the strategy", func(t *testing.T) {
+		body := submit(t, credentials("valid"))
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+	})
+
+	t.Run("case=only requires a captcha after failed attempts", func(t *testing.T) {
+		conf.MustSet(t.Context(), config.ViperKeySecurityCaptchaRequiredAfterFailedAttempts, 2)
+		t.Cleanup(func() {
+			conf.MustSet(t.Context(), config.ViperKeySecurityCaptchaRequiredAfterFailedAttempts, 0)
+		})
+
+		f := testhelpers.InitializeLoginFlowViaAPI(t, http.DefaultClient, publicTS, false)
+		for _, n := range f.Ui.Nodes {
+			assert.NotEqual(t, "captcha", n.Group)
+		}
+
+		body := submit(t, credentials(""))
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		assert.False(t, gjson.Get(body, "ui.nodes.#(group==captcha)").Exists(), "%s", body)
+
+		body = submit(t, credentials(""))
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		assert.True(t, gjson.Get(body, "ui.nodes.#(group==captcha)").Exists(), "the second failed attempt reaches the threshold: %s", body)
+
+		body = submit(t, credentials(""))
+		assert.EqualValues(t, text.ErrorValidationCaptchaError, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+	})
+
+	t.Run("case=counts failed attempts regardless of client IP headers", func(t *testing.T) {
+		conf.MustSet(t.Context(), config.ViperKeySecurityCaptchaRequiredAfterFailedAttempts, 2)
+		t.Cleanup(func() {
+			conf.MustSet(t.Context(), config.ViperKeySecurityCaptchaRequiredAfterFailedAttempts, 0)
+		})
+
+		f := &login.Flow{Type: flow.TypeAPI, State: flow.StateChooseMethod}
+		newRequest := func(forwardedFor string) *http.Request {
+			r := httptest.NewRequest("POST", "/", nil).WithContext(t.Context())
+			r.RemoteAddr = "192.0.2.1:1234"
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+			r.Header.Set("True-Client-Ip", forwardedFor)
+			return r
+		}
+
+		for _, ip := range []string{"198.51.100.1", "198.51.100.2"} {
+			required, err := reg.CaptchaVerifier().Required(newRequest(ip), f)
+			require.NoError(t, err)
+			assert.False(t, required)
+			require.NoError(t, reg.CaptchaVerifier().RecordFailedLogin(newRequest(ip)))
+		}
+
+		required, err := reg.CaptchaVerifier().Required(newRequest("198.51.100.3"), f)
+		require.NoError(t, err)
+		assert.True(t, required)
+	})
+}
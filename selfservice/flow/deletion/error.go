@@ -0,0 +1,208 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/urlx"
+)
+
+type (
+	errorHandlerDependencies interface {
+		config.Provider
+		errorx.ManagementProvider
+		x.WriterProvider
+		x.LoggingProvider
+		x.TracingProvider
+
+		HandlerProvider
+		FlowPersistenceProvider
+	}
+
+	ErrorHandlerProvider interface{ DeletionFlowErrorHandler() *ErrorHandler }
+
+	ErrorHandler struct {
+		d errorHandlerDependencies
+	}
+)
+
+func NewErrorHandler(d errorHandlerDependencies) *ErrorHandler {
+	return &ErrorHandler{d: d}
+}
+
+func (s *ErrorHandler) reauthenticate(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	f *Flow,
+	err *settings.FlowNeedsReAuth,
+) {
+	returnTo := urlx.CopyWithQuery(urlx.AppendPaths(s.d.Config().SelfPublicURL(ctx), RouteInitBrowserFlow), url.Values{"return_to": {f.ReturnTo}})
+	if f.ReturnTo == "" {
+		returnTo = urlx.AppendPaths(s.d.Config().SelfPublicURL(ctx), RouteInitBrowserFlow)
+	}
+
+	params := url.Values{}
+	params.Set("refresh", "true")
+	params.Set("return_to", returnTo.String())
+
+	redirectTo := urlx.AppendPaths(urlx.CopyWithQuery(s.d.Config().SelfPublicURL(ctx), params), login.RouteInitBrowserFlow).String()
+	err.RedirectBrowserTo = redirectTo
+	if f.Type == flow.TypeAPI || x.IsJSONRequest(r) {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
+
+func (s *ErrorHandler) WriteFlowError(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	f *Flow,
+	sess *session.Session,
+	err error,
+) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.flow.deletion.ErrorHandler.WriteFlowError",
+		trace.WithAttributes(
+			attribute.String("error", err.Error()),
+		))
+	r = r.WithContext(ctx)
+	defer otelx.End(span, &err)
+
+	logger := s.d.Audit().
+		WithError(err).
+		WithRequest(r).
+		WithField("deletion_flow", f.ToLoggerField())
+
+	logger.Info("Encountered self-service deletion error.")
+
+	shouldRespondWithJSON := x.IsJSONRequest(r)
+	if f != nil {
+		span.SetAttributes(attribute.String("flow_id", f.ID.String()))
+		if f.Type == flow.TypeAPI {
+			shouldRespondWithJSON = true
+		}
+	}
+
+	if e := new(session.ErrNoActiveSessionFound); errors.As(err, &e) {
+		if shouldRespondWithJSON {
+			s.d.Writer().WriteError(w, r, err)
+		} else {
+			u := urlx.AppendPaths(s.d.Config().SelfPublicURL(ctx), login.RouteInitBrowserFlow)
+			http.Redirect(w, r, u.String(), http.StatusSeeOther)
+		}
+		return
+	}
+
+	if aalErr := new(session.ErrAALNotSatisfied); errors.As(err, &aalErr) {
+		if shouldRespondWithJSON {
+			s.d.Writer().WriteError(w, r, aalErr)
+		} else {
+			http.Redirect(w, r, aalErr.RedirectTo, http.StatusSeeOther)
+		}
+		return
+	}
+
+	if f == nil {
+		events.SpanFromContext(ctx).AddEvent(events.NewDeletionFailed(ctx, uuid.Nil, "", err))
+		s.forward(ctx, w, r, nil, err)
+		return
+	}
+	events.SpanFromContext(ctx).AddEvent(events.NewDeletionFailed(ctx, f.ID, string(f.Type), err))
+
+	if expired := new(flow.ExpiredError); errors.As(err, &expired) {
+		if sess == nil {
+			s.forward(ctx, w, r, f, err)
+			return
+		}
+
+		// create new flow because the old one is not valid
+		nf, err := s.d.DeletionHandler().NewFlow(w, r, sess, f.Type)
+		if err != nil {
+			s.forward(ctx, w, r, f, err)
+			return
+		}
+
+		nf.RequestURL = f.RequestURL
+		nf.UI.Messages.Add(text.NewErrorValidationDeletionFlowExpired(expired.ExpiredAt))
+		if err := s.d.DeletionFlowPersister().UpdateDeletionFlow(ctx, nf); err != nil {
+			s.forward(ctx, w, r, f, err)
+			return
+		}
+
+		if shouldRespondWithJSON {
+			s.d.Writer().WriteError(w, r, expired.WithFlow(nf))
+		} else {
+			http.Redirect(w, r, nf.AppendTo(s.d.Config().SelfServiceFlowDeletionUI(ctx)).String(), http.StatusSeeOther)
+		}
+		return
+	}
+
+	if e := new(settings.FlowNeedsReAuth); errors.As(err, &e) {
+		s.reauthenticate(ctx, w, r, f, e)
+		return
+	}
+
+	f.UI.ResetMessages()
+	if err := f.UI.ParseError(node.DefaultGroup, err); err != nil {
+		s.forward(ctx, w, r, f, err)
+		return
+	}
+
+	if err := s.d.DeletionFlowPersister().UpdateDeletionFlow(ctx, f); err != nil {
+		s.forward(ctx, w, r, f, err)
+		return
+	}
+
+	if !shouldRespondWithJSON {
+		http.Redirect(w, r, f.AppendTo(s.d.Config().SelfServiceFlowDeletionUI(ctx)).String(), http.StatusSeeOther)
+		return
+	}
+
+	updatedFlow, innerErr := s.d.DeletionFlowPersister().GetDeletionFlow(ctx, f.ID)
+	if innerErr != nil {
+		s.forward(ctx, w, r, updatedFlow, innerErr)
+		return
+	}
+
+	s.d.Writer().WriteCode(w, r, x.RecoverStatusCode(err, http.StatusBadRequest), updatedFlow)
+}
+
+func (s *ErrorHandler) forward(ctx context.Context, w http.ResponseWriter, r *http.Request, rr *Flow, err error) {
+	if rr == nil {
+		if x.IsJSONRequest(r) {
+			s.d.Writer().WriteError(w, r, err)
+			return
+		}
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	if rr.Type == flow.TypeAPI || x.IsJSONRequest(r) {
+		s.d.Writer().WriteErrorCode(w, r, x.RecoverStatusCode(err, http.StatusBadRequest), err)
+	} else {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+	}
+}
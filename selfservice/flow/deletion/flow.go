@@ -0,0 +1,209 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/container"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/urlx"
+)
+
+// Flow represents an Account Deletion Flow
+//
+// This flow is used when an identity wants to delete its own account.
+//
+// swagger:model deletionFlow
+type Flow struct {
+	// ID represents the flow's unique ID. When performing the deletion flow, this
+	// represents the id in the deletion ui's query parameter: http://<selfservice.flows.deletion.ui_url>?flow=<id>
+	//
+	// required: true
+	// type: string
+	// format: uuid
+	ID uuid.UUID `json:"id" db:"id" faker:"-"`
+
+	// Type represents the flow's type which can be either "api" or "browser", depending on the flow interaction.
+	//
+	// required: true
+	Type flow.Type `json:"type" db:"type" faker:"flow_type"`
+
+	// ExpiresAt is the time (UTC) when the flow expires. If the user still wishes to delete the account,
+	// a new flow has to be initiated.
+	//
+	// required: true
+	ExpiresAt time.Time `json:"expires_at" faker:"time_type" db:"expires_at"`
+
+	// IssuedAt is the time (UTC) when the flow occurred.
+	//
+	// required: true
+	IssuedAt time.Time `json:"issued_at" faker:"time_type" db:"issued_at"`
+
+	// RequestURL is the initial URL that was requested from Ory Kratos. It can be used
+	// to forward information contained in the URL's path or query for example.
+	//
+	// required: true
+	RequestURL string `json:"request_url" db:"request_url"`
+
+	// ReturnTo contains the requested return_to URL.
+	ReturnTo string `json:"return_to,omitempty" db:"-"`
+
+	// UI contains data which must be shown in the user interface.
+	//
+	// required: true
+	UI *container.Container `json:"ui" db:"ui"`
+
+	// State represents the state of this flow. It knows two states:
+	//
+	// - show_form: The account was not deleted yet, and thus the form should be shown.
+	// - success: Indicates that the account was deleted.
+	//
+	// required: true
+	State State `json:"state" faker:"-" db:"state"`
+
+	// IdentityID is the ID of the identity which is being deleted.
+	//
+	// required: true
+	// type: string
+	// format: uuid
+	IdentityID uuid.UUID `json:"identity_id" faker:"-" db:"identity_id"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	CreatedAt time.Time `json:"-" faker:"-" db:"created_at"`
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	UpdatedAt time.Time `json:"-" faker:"-" db:"updated_at"`
+	NID       uuid.UUID `json:"-" faker:"-" db:"nid"`
+
+	// TransientPayload is used to pass data from the deletion flow to hooks and email templates
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty" faker:"-" db:"-"`
+}
+
+var _ flow.Flow = (*Flow)(nil)
+
+func NewFlow(conf *config.Config, exp time.Duration, csrf string, r *http.Request, s *session.Session, ft flow.Type) (*Flow, error) {
+	now := time.Now().UTC()
+	id := x.NewUUID()
+
+	// Pre-validate the return to URL which is contained in the HTTP request.
+	requestURL := x.RequestURL(r).String()
+	_, err := redir.SecureRedirectTo(r,
+		conf.SelfServiceBrowserDefaultReturnTo(r.Context()),
+		redir.SecureRedirectUseSourceURL(requestURL),
+		redir.SecureRedirectAllowURLs(conf.SelfServiceBrowserAllowedReturnToDomains(r.Context())),
+		redir.SecureRedirectAllowSelfServiceURLs(conf.SelfPublicURL(r.Context())),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Flow{
+		ID:         id,
+		ExpiresAt:  now.Add(exp),
+		IssuedAt:   now,
+		RequestURL: requestURL,
+		IdentityID: s.Identity.ID,
+		Type:       ft,
+		State:      flow.StateShowForm,
+		UI: &container.Container{
+			Method: "POST",
+			Action: flow.AppendFlowTo(urlx.AppendPaths(conf.SelfPublicURL(r.Context()), RouteSubmitFlow), id).String(),
+		},
+	}
+
+	if conf.SelfServiceFlowDeletionRequireConfirmation(r.Context()) {
+		f.UI.Nodes.Append(node.NewInputField("confirm", false, node.DefaultGroup, node.InputAttributeTypeCheckbox, node.WithRequiredInputAttribute).
+			WithMetaLabel(text.NewInfoSelfServiceDeletionConfirm()))
+	}
+	f.UI.Nodes.Append(node.NewInputField("method", "delete", node.DefaultGroup, node.InputAttributeTypeSubmit).
+		WithMetaLabel(text.NewInfoSelfServiceDeletionSubmit()))
+
+	if ft == flow.TypeBrowser {
+		f.UI.SetCSRF(csrf)
+	}
+
+	return f, nil
+}
+
+func (f *Flow) GetType() flow.Type                   { return f.Type }
+func (f *Flow) GetRequestURL() string                { return f.RequestURL }
+func (Flow) TableName() string                       { return "selfservice_deletion_flows" }
+func (f Flow) GetID() uuid.UUID                      { return f.ID }
+func (f *Flow) AppendTo(src *url.URL) *url.URL       { return flow.AppendFlowTo(src, f.ID) }
+func (f *Flow) GetUI() *container.Container          { return f.UI }
+func (f *Flow) GetState() State                      { return f.State }
+func (Flow) GetFlowName() flow.FlowName              { return flow.DeletionFlow }
+func (f *Flow) SetState(state State)                 { f.State = state }
+func (f *Flow) GetTransientPayload() json.RawMessage { return f.TransientPayload }
+
+// Valid returns an error if the flow expired or if it was initiated by
+// another identity than the one of the session.
+func (f *Flow) Valid(s *session.Session) error {
+	if f.ExpiresAt.Before(time.Now().UTC()) {
+		return errors.WithStack(flow.NewFlowExpiredError(f.ExpiresAt))
+	}
+
+	if f.IdentityID != s.Identity.ID {
+		return errors.WithStack(herodot.ErrForbidden.WithID(text.ErrIDInitiatedBySomeoneElse).WithReasonf(
+			"The request was initiated by someone else and has been blocked for security reasons. Please go back and try again."))
+	}
+
+	return nil
+}
+
+func (f Flow) MarshalJSON() ([]byte, error) {
+	type local Flow
+	f.SetReturnTo()
+	return json.Marshal(local(f))
+}
+
+func (f *Flow) SetReturnTo() {
+	// Return to is already set, do not overwrite it.
+	if len(f.ReturnTo) > 0 {
+		return
+	}
+	if u, err := url.Parse(f.RequestURL); err == nil {
+		f.ReturnTo = u.Query().Get("return_to")
+	}
+}
+
+func (f *Flow) AfterFind(*pop.Connection) error {
+	f.SetReturnTo()
+	return nil
+}
+
+func (f *Flow) AfterSave(*pop.Connection) error {
+	f.SetReturnTo()
+	return nil
+}
+
+func (f *Flow) ToLoggerField() map[string]any {
+	if f == nil {
+		return map[string]any{}
+	}
+	return map[string]any{
+		"id":          f.ID.String(),
+		"return_to":   f.ReturnTo,
+		"request_url": f.RequestURL,
+		"Type":        f.Type,
+		"nid":         f.NID,
+		"state":       f.State,
+	}
+}
@@ -0,0 +1,667 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/notification"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/errorx"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/urlx"
+)
+
+const (
+	RouteInitBrowserFlow = "/self-service/deletion/browser"
+	RouteInitAPIFlow     = "/self-service/deletion/api"
+	RouteGetFlow         = "/self-service/deletion/flows"
+
+	RouteSubmitFlow = "/self-service/deletion"
+)
+
+//go:embed .schema/deletion.schema.json
+var deletionSchema []byte
+
+// ErrDeletionDisabled is returned when the deletion flow is used although it
+// was not enabled.
+var ErrDeletionDisabled = herodot.ErrBadRequest.WithReasonf("Account deletion is not allowed because it was disabled.")
+
+type (
+	handlerDependencies interface {
+		nosurfx.CSRFProvider
+		nosurfx.CSRFTokenGeneratorProvider
+		x.WriterProvider
+		x.LoggingProvider
+		x.TracingProvider
+		x.CookieProvider
+
+		config.Provider
+
+		session.HandlerProvider
+		session.ManagementProvider
+
+		identity.PrivilegedPoolProvider
+
+		errorx.ManagementProvider
+
+		notification.SecurityNotifierProvider
+
+		ErrorHandlerProvider
+		FlowPersistenceProvider
+		HookExecutorProvider
+	}
+	HandlerProvider interface {
+		DeletionHandler() *Handler
+	}
+	Handler struct {
+		d handlerDependencies
+	}
+)
+
+func NewHandler(d handlerDependencies) *Handler { return &Handler{d: d} }
+
+func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
+	h.d.CSRFHandler().IgnorePath(RouteInitAPIFlow)
+	h.d.CSRFHandler().IgnorePath(RouteSubmitFlow)
+
+	public.GET(RouteInitBrowserFlow, h.d.SessionHandler().IsAuthenticated(h.createBrowserDeletionFlow, func(w http.ResponseWriter, r *http.Request) {
+		if x.IsJSONRequest(r) {
+			h.d.Writer().WriteError(w, r, session.NewErrNoActiveSessionFound())
+		} else {
+			loginFlowUrl := h.d.Config().SelfPublicURL(r.Context()).JoinPath(login.RouteInitBrowserFlow).String()
+			redirectUrl, err := redir.TakeOverReturnToParameter(r.URL.String(), loginFlowUrl)
+			if err != nil {
+				http.Redirect(w, r, h.d.Config().SelfServiceFlowLoginUI(r.Context()).String(), http.StatusSeeOther)
+			} else {
+				http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+			}
+		}
+	}))
+
+	public.GET(RouteInitAPIFlow, h.d.SessionHandler().IsAuthenticated(h.createNativeDeletionFlow, nil))
+	public.GET(RouteGetFlow, h.d.SessionHandler().IsAuthenticated(h.getDeletionFlow, settings.OnUnauthenticated(h.d)))
+
+	public.POST(RouteSubmitFlow, h.d.SessionHandler().IsAuthenticated(h.updateDeletionFlow, settings.OnUnauthenticated(h.d)))
+	public.GET(RouteSubmitFlow, h.d.SessionHandler().IsAuthenticated(h.updateDeletionFlow, settings.OnUnauthenticated(h.d)))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
+	admin.GET(RouteInitBrowserFlow, redir.RedirectToPublicRoute(h.d))
+	admin.GET(RouteInitAPIFlow, redir.RedirectToPublicRoute(h.d))
+	admin.GET(RouteGetFlow, redir.RedirectToPublicRoute(h.d))
+	admin.POST(RouteSubmitFlow, redir.RedirectToPublicRoute(h.d))
+	admin.GET(RouteSubmitFlow, redir.RedirectToPublicRoute(h.d))
+}
+
+// NewFlow initializes a deletion flow for the identity of the session, runs
+// the `selfservice.flows.deletion.before` hooks, and persists the flow.
+func (h *Handler) NewFlow(w http.ResponseWriter, r *http.Request, s *session.Session, ft flow.Type) (_ *Flow, err error) {
+	ctx, span := h.d.Tracer(r.Context()).Tracer().Start(r.Context(), "selfservice.flow.deletion.Handler.NewFlow")
+	defer otelx.End(span, &err)
+	r = r.WithContext(ctx)
+
+	f, err := NewFlow(h.d.Config(), h.d.Config().SelfServiceFlowDeletionRequestLifespan(ctx), h.d.GenerateCSRFToken(r), r, s, ft)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.d.DeletionExecutor().PreDeletionHook(w, r, f, s); err != nil {
+		return nil, err
+	}
+
+	if err := h.d.DeletionFlowPersister().CreateDeletionFlow(ctx, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Create Native Deletion Flow Parameters
+//
+// swagger:parameters createNativeDeletionFlow
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createNativeDeletionFlow struct {
+	// The Session Token of the Identity deleting its account.
+	//
+	// in: header
+	SessionToken string `json:"X-Session-Token"`
+}
+
+// swagger:route GET /self-service/deletion/api frontend createNativeDeletionFlow
+//
+// # Create Account Deletion Flow for Native Apps
+//
+// This endpoint initiates an account deletion flow for API clients such as mobile devices, smart TVs, and so on.
+// You must provide a valid Ory Kratos Session Token for this endpoint to respond with HTTP 200 OK.
+//
+// To fetch an existing deletion flow call `/self-service/deletion/flows?flow=<flow_id>`.
+//
+// You MUST NOT use this endpoint in client-side (Single Page Apps, ReactJS, AngularJS) nor server-side (Java Server
+// Pages, NodeJS, PHP, Golang, ...) browser applications. Using this endpoint in these applications will make
+// you vulnerable to a variety of CSRF attacks.
+//
+// Depending on your configuration this endpoint might return a 403 error if the session has a lower Authenticator
+// Assurance Level (AAL) than `selfservice.flows.settings.required_aal`.
+//
+// In the case of an error, the `error.id` of the JSON response body can be one of:
+//
+// - `session_inactive`: No Ory Session was found - sign in a user first.
+//
+// This endpoint MUST ONLY be used in scenarios such as native mobile apps (React Native, Objective C, Swift, Java, ...).
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: deletionFlow
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) createNativeDeletionFlow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !h.d.Config().SelfServiceFlowDeletionEnabled(ctx) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeletionDisabled))
+		return
+	}
+
+	s, err := h.d.SessionManager().FetchFromRequestContext(ctx, r)
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.d.SessionManager().DoesSessionSatisfy(ctx, s, h.d.Config().SelfServiceSettingsRequiredAAL(ctx)); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	f, err := h.NewFlow(w, r, s, flow.TypeAPI)
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.d.Writer().Write(w, r, f)
+}
+
+// Create Browser Deletion Flow Parameters
+//
+// swagger:parameters createBrowserDeletionFlow
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createBrowserDeletionFlow struct {
+	// The URL to return the browser to after the account was deleted.
+	//
+	// in: query
+	ReturnTo string `json:"return_to"`
+
+	// HTTP Cookies
+	//
+	// When using the SDK in a browser app, on the server side you must include the HTTP Cookie Header
+	// sent by the client to your server here. This ensures that CSRF and session cookies are respected.
+	//
+	// in: header
+	// name: Cookie
+	Cookies string `json:"Cookie"`
+}
+
+// swagger:route GET /self-service/deletion/browser frontend createBrowserDeletionFlow
+//
+// # Create Account Deletion Flow for Browsers
+//
+// This endpoint initializes a browser-based account deletion flow. Once initialized, the browser will be redirected to
+// `selfservice.flows.deletion.ui_url` with the flow ID set as the query parameter `?flow=`. If no valid
+// Ory Kratos Session Cookie is included in the request, a login flow will be initialized.
+//
+// If this endpoint is called via an AJAX request, the response contains the deletion flow without any redirects
+// or a 401 forbidden error if no valid session was set.
+//
+// Depending on your configuration this endpoint might return a 403 error if the session has a lower Authenticator
+// Assurance Level (AAL) than `selfservice.flows.settings.required_aal`.
+//
+// In the case of an error, the `error.id` of the JSON response body can be one of:
+//
+// - `security_csrf_violation`: Unable to fetch the flow because a CSRF violation occurred.
+// - `session_inactive`: No Ory Session was found - sign in a user first.
+// - `security_identity_mismatch`: The requested `?return_to` address is not allowed to be used. Adjust this in the configuration!
+//
+// This endpoint is NOT INTENDED for clients that do not have a browser (Chrome, Firefox, ...) as cookies are needed.
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: deletionFlow
+//	  303: emptyResponse
+//	  400: errorGeneric
+//	  401: errorGeneric
+//	  403: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) createBrowserDeletionFlow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !h.d.Config().SelfServiceFlowDeletionEnabled(ctx) {
+		h.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(ErrDeletionDisabled))
+		return
+	}
+
+	s, err := h.d.SessionManager().FetchFromRequestContext(ctx, r)
+	if err != nil {
+		h.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	var managerOptions []session.ManagerOptions
+	requestURL := x.RequestURL(r)
+	if requestURL.Query().Get("return_to") != "" {
+		managerOptions = append(managerOptions, session.WithRequestURL(requestURL.String()))
+	}
+
+	if err := h.d.SessionManager().DoesSessionSatisfy(ctx, s, h.d.Config().SelfServiceSettingsRequiredAAL(ctx), managerOptions...); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, nil, s, err)
+		return
+	}
+
+	f, err := h.NewFlow(w, r, s, flow.TypeBrowser)
+	if err != nil {
+		h.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	redirTo := f.AppendTo(h.d.Config().SelfServiceFlowDeletionUI(ctx)).String()
+	x.SendFlowCompletedAsRedirectOrJSON(w, r, h.d.Writer(), f, redirTo)
+}
+
+// Get Deletion Flow
+//
+// swagger:parameters getDeletionFlow
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getDeletionFlow struct {
+	// ID is the Deletion Flow ID
+	//
+	// The value for this parameter comes from `flow` URL Query parameter sent to your
+	// application (e.g. `/delete-account?flow=abcde`).
+	//
+	// required: true
+	// in: query
+	ID string `json:"id"`
+
+	// The Session Token
+	//
+	// When using the SDK in an app without a browser, please include the
+	// session token here.
+	//
+	// in: header
+	SessionToken string `json:"X-Session-Token"`
+
+	// HTTP Cookies
+	//
+	// When using the SDK in a browser app, on the server side you must include the HTTP Cookie Header
+	// sent by the client to your server here. This ensures that CSRF and session cookies are respected.
+	//
+	// in: header
+	// name: Cookie
+	Cookies string `json:"Cookie"`
+}
+
+// swagger:route GET /self-service/deletion/flows frontend getDeletionFlow
+//
+// # Get Account Deletion Flow
+//
+// When accessing this endpoint through Ory Kratos' Public API you must ensure that either the Ory Kratos Session Cookie
+// or the Ory Kratos Session Token are set.
+//
+// If this endpoint is called via an AJAX request, the response contains the flow without a redirect. In the
+// case of an error, the `error.id` of the JSON response body can be one of:
+//
+//   - `session_inactive`: No Ory Session was found - sign in a user first.
+//   - `security_identity_mismatch`: The flow was initiated by another identity.
+//
+// The flow expires after `selfservice.flows.deletion.lifespan`.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: deletionFlow
+//	  401: errorGeneric
+//	  403: errorGeneric
+//	  404: errorGeneric
+//	  410: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getDeletionFlow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !h.d.Config().SelfServiceFlowDeletionEnabled(ctx) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeletionDisabled))
+		return
+	}
+
+	rid := x.ParseUUID(r.URL.Query().Get("id"))
+	f, err := h.d.DeletionFlowPersister().GetDeletionFlow(ctx, rid)
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	sess, err := h.d.SessionManager().FetchFromRequestContext(ctx, r)
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if f.IdentityID != sess.Identity.ID {
+		h.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrForbidden.
+			WithID(text.ErrIDInitiatedBySomeoneElse).
+			WithReasonf("The request was made for another identity and has been blocked for security reasons.")))
+		return
+	}
+
+	if f.ExpiresAt.Before(time.Now().UTC()) {
+		if f.Type == flow.TypeBrowser {
+			redirectURL := flow.GetFlowExpiredRedirectURL(ctx, h.d.Config(), RouteInitBrowserFlow, f.ReturnTo)
+
+			h.d.Writer().WriteError(w, r, errors.WithStack(nosurfx.ErrGone.
+				WithReason("The deletion flow has expired. Redirect the user to the deletion flow init endpoint to initialize a new deletion flow.").
+				WithDetail("redirect_to", redirectURL.String()).
+				WithDetail("return_to", f.ReturnTo)))
+			return
+		}
+		h.d.Writer().WriteError(w, r, errors.WithStack(nosurfx.ErrGone.
+			WithReason("The deletion flow has expired. Call the deletion flow init API endpoint to initialize a new deletion flow.").
+			WithDetail("api", urlx.AppendPaths(h.d.Config().SelfPublicURL(ctx), RouteInitAPIFlow).String())))
+		return
+	}
+
+	h.d.Writer().Write(w, r, f)
+}
+
+// Update Deletion Flow Parameters
+//
+// swagger:parameters updateDeletionFlow
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateDeletionFlow struct {
+	// The Deletion Flow ID
+	//
+	// The value for this parameter comes from `flow` URL Query parameter sent to your
+	// application (e.g. `/delete-account?flow=abcde`).
+	//
+	// required: true
+	// in: query
+	Flow string `json:"flow"`
+
+	// in: body
+	// required: true
+	Body updateDeletionFlowBody
+
+	// The Session Token of the Identity deleting its account.
+	//
+	// in: header
+	SessionToken string `json:"X-Session-Token"`
+
+	// HTTP Cookies
+	//
+	// When using the SDK in a browser app, on the server side you must include the HTTP Cookie Header
+	// sent by the client to your server here. This ensures that CSRF and session cookies are respected.
+	//
+	// in: header
+	// name: Cookie
+	Cookies string `json:"Cookie"`
+}
+
+// Update Deletion Flow Request Body
+//
+// swagger:model updateDeletionFlowBody
+type updateDeletionFlowBody struct {
+	// Confirm must be set to true if `selfservice.flows.deletion.require_confirmation` is enabled.
+	Confirm bool `json:"confirm" form:"confirm"`
+
+	// The CSRF Token
+	CSRFToken string `json:"csrf_token" form:"csrf_token"`
+
+	// Method is the value of the submit button.
+	Method string `json:"method" form:"method"`
+
+	// Transient data to pass along to any webhooks
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
+}
+
+// swagger:route POST /self-service/deletion frontend updateDeletionFlow
+//
+// # Complete Account Deletion Flow
+//
+// Use this endpoint to delete the account of the identity of the session. This endpoint
+// behaves differently for API and browser flows.
+//
+// The session must be privileged, which means that it must have been authenticated within
+// `selfservice.flows.settings.privileged_session_max_age`. Impersonated sessions may not
+// delete the account. All sessions of the identity are revoked and a confirmation email is
+// sent to its email addresses.
+//
+// API-initiated flows expect `application/json` to be sent in the body and respond with
+//   - HTTP 200 and the flow in state `success` if the account was deleted;
+//   - HTTP 400 on form validation errors, for example if the deletion was not confirmed;
+//   - HTTP 401 when the endpoint is called without a valid session token;
+//   - HTTP 403 when `selfservice.flows.settings.privileged_session_max_age` was reached or the session's AAL is too low.
+//     Implies that the user needs to re-authenticate.
+//
+// Browser flows without HTTP Header `Accept` or with `Accept: text/*` respond with
+//   - a HTTP 303 redirect to the post/after deletion URL or the `return_to` value if it was set and if the account was deleted;
+//   - a HTTP 303 redirect to the Deletion UI URL with the flow ID containing the validation errors otherwise;
+//   - a HTTP 303 redirect to the login endpoint when `selfservice.flows.settings.privileged_session_max_age` was reached.
+//
+// Browser flows with HTTP Header `Accept: application/json` respond with
+//   - HTTP 200 and the flow in state `success` if the account was deleted;
+//   - HTTP 400 on form validation errors;
+//   - HTTP 401 when the endpoint is called without a valid session cookie;
+//   - HTTP 403 when the session needs to be refreshed or the session's AAL is too low.
+//
+// In the case of an error, the `error.id` of the JSON response body can be one of:
+//
+//   - `session_refresh_required`: The session is too old to delete the account. Redirect the identity to
+//     the login init endpoint with query parameters `?refresh=true&return_to=<the-current-browser-url>`,
+//     or initiate a refresh login flow otherwise.
+//   - `session_impersonated`: Impersonated sessions are not allowed to delete the account.
+//   - `security_csrf_violation`: Unable to fetch the flow because a CSRF violation occurred.
+//   - `session_inactive`: No Ory Session was found - sign in a user first.
+//   - `security_identity_mismatch`: The flow was initiated by another identity.
+//
+// The account can not be restored through this flow.
+//
+//	Consumes:
+//	- application/json
+//	- application/x-www-form-urlencoded
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  sessionToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: deletionFlow
+//	  303: emptyResponse
+//	  400: deletionFlow
+//	  401: errorGeneric
+//	  403: errorGeneric
+//	  410: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) updateDeletionFlow(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		ctx = r.Context()
+	)
+
+	ctx, span := h.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.flow.deletion.Handler.updateDeletionFlow")
+	r = r.WithContext(ctx)
+	defer otelx.End(span, &err)
+
+	if !h.d.Config().SelfServiceFlowDeletionEnabled(ctx) {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, nil, nil, errors.WithStack(ErrDeletionDisabled))
+		return
+	}
+
+	rid, err := flow.GetFlowID(r)
+	if err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, nil, nil, err)
+		return
+	}
+
+	f, err := h.d.DeletionFlowPersister().GetDeletionFlow(ctx, rid)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, nil, nil, errors.WithStack(herodot.ErrNotFound.WithReasonf("The deletion request could not be found. Please restart the flow.")))
+		return
+	} else if err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, nil, nil, err)
+		return
+	}
+
+	ss, err := h.d.SessionManager().FetchFromRequestContext(ctx, r)
+	if err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, nil, err)
+		return
+	}
+
+	requestURL := x.RequestURL(r).String()
+	if err := h.d.SessionManager().DoesSessionSatisfy(ctx, ss, h.d.Config().SelfServiceSettingsRequiredAAL(ctx), session.WithRequestURL(requestURL)); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+
+	if err := f.Valid(ss); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+
+	var p updateDeletionFlowBody
+	if err := h.decode(r, &p); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+	f.TransientPayload = p.TransientPayload
+
+	if err := flow.EnsureCSRF(h.d, r, f.Type, h.d.Config().DisableAPIFlowEnforcement(ctx), h.d.GenerateCSRFToken, p.CSRFToken); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+
+	if err := settings.EnsurePrivilegedSession(ctx, h.d.Config(), ss); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+
+	if h.d.Config().SelfServiceFlowDeletionRequireConfirmation(ctx) && !p.Confirm {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, schema.NewDeletionNotConfirmedError())
+		return
+	}
+
+	// The identity is loaded before it is deleted, because the hooks and the
+	// confirmation email need its traits and addresses.
+	i, err := h.d.PrivilegedIdentityPool().GetIdentity(ctx, ss.Identity.ID, identity.ExpandDefault)
+	if err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+
+	// Deleting the identity revokes all of its sessions, so the identity is
+	// only signed out if the deletion succeeded.
+	if h.d.Config().IdentitySoftDelete(ctx).Enabled {
+		err = h.d.PrivilegedIdentityPool().SoftDeleteIdentity(ctx, i.ID)
+	} else {
+		err = h.d.PrivilegedIdentityPool().DeleteIdentity(ctx, i.ID)
+	}
+	if err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, ss, err)
+		return
+	}
+
+	if err := x.SessionUnset(w, r, h.d.CookieManager(ctx), h.d.Config().SessionName(ctx)); err != nil {
+		h.d.Logger().
+			WithRequest(r).
+			WithError(err).
+			WithField("identity_id", i.ID).
+			Error("Unable to remove the session cookie after the account was deleted.")
+	}
+
+	events.SpanFromContext(ctx).AddEvent(events.NewDeletionSucceeded(ctx, f.ID, i.ID, string(f.Type)))
+	h.d.SecurityNotifier().NotifyAccountDeleted(ctx, r, i)
+
+	// The account is gone at this point, so a failing hook must not report
+	// the flow as failed.
+	if err := h.d.DeletionExecutor().PostDeletionHook(w, r, f, i); err != nil {
+		h.d.Logger().
+			WithRequest(r).
+			WithError(err).
+			WithField("identity_id", i.ID).
+			Error("A post deletion hook failed after the account was deleted.")
+	}
+
+	f.State = flow.StateSuccess
+	f.UI.ResetMessages()
+	f.UI.Messages.Add(text.NewInfoSelfServiceDeletionSuccessful())
+	if err := h.d.DeletionFlowPersister().UpdateDeletionFlow(ctx, f); err != nil {
+		h.d.DeletionFlowErrorHandler().WriteFlowError(ctx, w, r, f, nil, err)
+		return
+	}
+
+	if f.Type == flow.TypeAPI || x.IsJSONRequest(r) {
+		h.d.Writer().Write(w, r, f)
+		return
+	}
+
+	c := h.d.Config()
+	returnTo, err := redir.SecureRedirectTo(r, c.SelfServiceBrowserDefaultReturnTo(ctx),
+		redir.SecureRedirectUseSourceURL(f.RequestURL),
+		redir.SecureRedirectAllowURLs(c.SelfServiceBrowserAllowedReturnToDomains(ctx)),
+		redir.SecureRedirectAllowSelfServiceURLs(c.SelfPublicURL(ctx)),
+		redir.SecureRedirectOverrideDefaultReturnTo(c.SelfServiceFlowDeletionReturnTo(ctx, c.SelfServiceBrowserDefaultReturnTo(ctx))),
+	)
+	if err != nil {
+		h.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, returnTo.String(), http.StatusSeeOther)
+}
+
+func (h *Handler) decode(r *http.Request, dest *updateDeletionFlowBody) error {
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(deletionSchema)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return decoderx.NewHTTP().Decode(r, dest, compiler,
+		decoderx.HTTPDecoderSetValidatePayloads(true),
+		decoderx.HTTPDecoderJSONFollowsFormFormat(),
+	)
+}
@@ -0,0 +1,284 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/selfservice/flow/deletion"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlcon"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValue(config.ViperKeySelfServiceDeletionEnabled, true),
+	)
+
+	publicTS, _ := testhelpers.NewKratosServer(t, reg)
+	uiTS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Query().Get("flow")))
+	}))
+	t.Cleanup(uiTS.Close)
+	conf.MustSet(ctx, config.ViperKeySelfServiceDeletionUI, uiTS.URL)
+	returnTS := testhelpers.NewRedirNoSessionTS(t, reg)
+
+	newIdentity := func(t *testing.T) *identity.Identity {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"email":"` + uuid.Must(uuid.NewV4()).String() + `@ory.sh"}`)
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		return i
+	}
+
+	initFlow := func(t *testing.T, hc *http.Client, isAPI bool) (*http.Response, []byte) {
+		route := deletion.RouteInitBrowserFlow
+		if isAPI {
+			route = deletion.RouteInitAPIFlow
+		}
+		req := testhelpers.NewTestHTTPRequest(t, "GET", publicTS.URL+route, nil)
+		req.Header.Set("Accept", "application/json")
+		res, err := hc.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, body
+	}
+
+	submitFlow := func(t *testing.T, hc *http.Client, f []byte, payload map[string]any) (*http.Response, []byte) {
+		payload["method"] = "delete"
+		payload["csrf_token"] = gjson.GetBytes(f, `ui.nodes.#(attributes.name=="csrf_token").attributes.value`).String()
+		raw, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req := testhelpers.NewTestHTTPRequest(t, "POST", gjson.GetBytes(f, "ui.action").String(), strings.NewReader(string(raw)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		res, err := hc.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, body
+	}
+
+	assertDeleted := func(t *testing.T, i *identity.Identity) {
+		actual, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+		if conf.IdentitySoftDelete(ctx).Enabled {
+			require.NoError(t, err)
+			assert.Equal(t, identity.StateDeleted, actual.State)
+		} else {
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+		}
+
+		sessions, _, err := reg.SessionPersister().ListSessionsByIdentity(ctx, i.ID, nil, 1, 100, uuid.Nil, session.ExpandNothing)
+		require.NoError(t, err)
+		for _, s := range sessions {
+			assert.False(t, s.IsActive(), "session %s must be revoked", s.ID)
+		}
+
+		msgs, _, err := reg.CourierPersister().ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: i.RecoveryAddresses[0].Value}, nil)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, template.TypeAccountDeleted, msgs[0].TemplateType)
+	}
+
+	t.Run("case=fails if the flow is disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceDeletionEnabled, false)
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceDeletionEnabled, true) })
+
+		res, body := initFlow(t, testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, newIdentity(t)), true)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		assert.Contains(t, gjson.GetBytes(body, "error.reason").String(), "disabled", "%s", body)
+	})
+
+	t.Run("case=requires a session", func(t *testing.T) {
+		res, body := initFlow(t, http.DefaultClient, true)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "%s", body)
+	})
+
+	t.Run("description=api", func(t *testing.T) {
+		t.Run("case=requires confirmation before deleting the account", func(t *testing.T) {
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+			res, f := initFlow(t, hc, true)
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", f)
+			assert.Equal(t, "api", gjson.GetBytes(f, "type").String())
+			assert.Equal(t, i.ID.String(), gjson.GetBytes(f, "identity_id").String())
+
+			res, body := submitFlow(t, hc, f, map[string]any{})
+			require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+			assert.EqualValues(t, text.ErrorValidationDeletionNotConfirmed, gjson.GetBytes(body, `ui.nodes.#(attributes.name=="confirm").messages.0.id`).Int(), "%s", body)
+
+			_, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+			require.NoError(t, err)
+		})
+
+		t.Run("case=deletes the account", func(t *testing.T) {
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+			_, f := initFlow(t, hc, true)
+			res, body := submitFlow(t, hc, f, map[string]any{"confirm": true})
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, "success", gjson.GetBytes(body, "state").String(), "%s", body)
+			assert.EqualValues(t, text.InfoSelfServiceDeletionSuccessful, gjson.GetBytes(body, "ui.messages.0.id").Int(), "%s", body)
+
+			assertDeleted(t, i)
+		})
+
+		t.Run("case=does not require confirmation if disabled", func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeySelfServiceDeletionRequireConfirmation, false)
+			t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceDeletionRequireConfirmation, true) })
+
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+			_, f := initFlow(t, hc, true)
+			assert.False(t, gjson.GetBytes(f, `ui.nodes.#(attributes.name=="confirm")`).Exists(), "%s", f)
+
+			res, body := submitFlow(t, hc, f, map[string]any{})
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+			assertDeleted(t, i)
+		})
+
+		t.Run("case=requires a privileged session", func(t *testing.T) {
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+			_, f := initFlow(t, hc, true)
+
+			conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1ns")
+			t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1h") })
+
+			res, body := submitFlow(t, hc, f, map[string]any{"confirm": true})
+			require.Equal(t, http.StatusForbidden, res.StatusCode, "%s", body)
+			assert.Equal(t, text.ErrIDNeedsPrivilegedSession, gjson.GetBytes(body, "error.id").String(), "%s", body)
+
+			_, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+			require.NoError(t, err)
+		})
+
+		t.Run("case=rejects flows of other identities", func(t *testing.T) {
+			_, f := initFlow(t, testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, newIdentity(t)), true)
+
+			res, body := submitFlow(t, testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, newIdentity(t)), f, map[string]any{"confirm": true})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode, "%s", body)
+		})
+
+		t.Run("case=soft deletes the account if enabled", func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeyIdentitySoftDeleteEnabled, true)
+			t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeyIdentitySoftDeleteEnabled, false) })
+
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+			_, f := initFlow(t, hc, true)
+			res, body := submitFlow(t, hc, f, map[string]any{"confirm": true})
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+
+			assertDeleted(t, i)
+		})
+	})
+
+	t.Run("description=browser", func(t *testing.T) {
+		t.Run("case=deletes the account with a SPA", func(t *testing.T) {
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionCookie(ctx, t, reg, i)
+
+			res, f := initFlow(t, hc, false)
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", f)
+			assert.Equal(t, "browser", gjson.GetBytes(f, "type").String())
+
+			res, body := submitFlow(t, hc, f, map[string]any{"confirm": true})
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, "success", gjson.GetBytes(body, "state").String(), "%s", body)
+
+			var removed bool
+			for _, c := range res.Cookies() {
+				if c.Name == conf.SessionName(ctx) {
+					removed = c.MaxAge < 0
+				}
+			}
+			assert.True(t, removed, "the session cookie must be removed: %v", res.Header.Values("Set-Cookie"))
+
+			assertDeleted(t, i)
+		})
+
+		t.Run("case=deletes the account and redirects to the return address", func(t *testing.T) {
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionCookie(ctx, t, reg, i)
+
+			res, err := hc.Get(publicTS.URL + deletion.RouteInitBrowserFlow)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			fid, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Contains(t, res.Request.URL.String(), uiTS.URL)
+
+			f, err := reg.DeletionFlowPersister().GetDeletionFlow(ctx, uuid.FromStringOrNil(string(fid)))
+			require.NoError(t, err)
+
+			res, err = hc.PostForm(f.UI.Action, url.Values{
+				"method":     {"delete"},
+				"confirm":    {"true"},
+				"csrf_token": {f.UI.GetNodes().Find("csrf_token").GetValue().(string)},
+			})
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			// The return server asserts that the session cookie was removed.
+			assert.Equal(t, returnTS.URL+"/return-ts", res.Request.URL.String())
+
+			assertDeleted(t, i)
+		})
+
+		t.Run("case=redirects to the login refresh flow if the session is not privileged", func(t *testing.T) {
+			i := newIdentity(t)
+			hc := testhelpers.NewHTTPClientWithIdentitySessionCookie(ctx, t, reg, i)
+			_, f := initFlow(t, hc, false)
+
+			conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1ns")
+			t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1h") })
+
+			hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+			res, err := hc.PostForm(gjson.GetBytes(f, "ui.action").String(), url.Values{
+				"method":     {"delete"},
+				"confirm":    {"true"},
+				"csrf_token": {gjson.GetBytes(f, `ui.nodes.#(attributes.name=="csrf_token").attributes.value`).String()},
+			})
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusSeeOther, res.StatusCode)
+
+			location, err := url.Parse(res.Header.Get("Location"))
+			require.NoError(t, err)
+			assert.Equal(t, "/self-service/login/browser", location.Path)
+			assert.Equal(t, "true", location.Query().Get("refresh"))
+			assert.Contains(t, location.Query().Get("return_to"), deletion.RouteInitBrowserFlow)
+		})
+	})
+}
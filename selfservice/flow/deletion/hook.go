@@ -0,0 +1,115 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+)
+
+type (
+	PreHookExecutor interface {
+		ExecuteDeletionPreHook(w http.ResponseWriter, r *http.Request, a *Flow, s *session.Session) error
+	}
+	PreHookExecutorFunc func(w http.ResponseWriter, r *http.Request, a *Flow, s *session.Session) error
+
+	PostHookExecutor interface {
+		ExecuteDeletionPostHook(w http.ResponseWriter, r *http.Request, a *Flow, i *identity.Identity) error
+	}
+	PostHookExecutorFunc func(w http.ResponseWriter, r *http.Request, a *Flow, i *identity.Identity) error
+
+	HooksProvider interface {
+		PreDeletionHooks(ctx context.Context) ([]PreHookExecutor, error)
+		PostDeletionHooks(ctx context.Context) ([]PostHookExecutor, error)
+	}
+)
+
+func PostHookDeletionExecutorNames(e []PostHookExecutor) []string {
+	names := make([]string, len(e))
+	for k, ee := range e {
+		names[k] = fmt.Sprintf("%T", ee)
+	}
+	return names
+}
+
+func (f PreHookExecutorFunc) ExecuteDeletionPreHook(w http.ResponseWriter, r *http.Request, a *Flow, s *session.Session) error {
+	return f(w, r, a, s)
+}
+
+func (f PostHookExecutorFunc) ExecuteDeletionPostHook(w http.ResponseWriter, r *http.Request, a *Flow, i *identity.Identity) error {
+	return f(w, r, a, i)
+}
+
+type (
+	executorDependencies interface {
+		config.Provider
+		HooksProvider
+		x.LoggingProvider
+	}
+
+	HookExecutor struct {
+		d executorDependencies
+	}
+
+	HookExecutorProvider interface {
+		DeletionExecutor() *HookExecutor
+	}
+)
+
+func NewHookExecutor(d executorDependencies) *HookExecutor {
+	return &HookExecutor{
+		d: d,
+	}
+}
+
+// PreDeletionHook runs the `selfservice.flows.deletion.before` hooks when a
+// deletion flow is initiated.
+func (e *HookExecutor) PreDeletionHook(w http.ResponseWriter, r *http.Request, a *Flow, s *session.Session) error {
+	hooks, err := e.d.PreDeletionHooks(r.Context())
+	if err != nil {
+		return err
+	}
+	for _, executor := range hooks {
+		if err := executor.ExecuteDeletionPreHook(w, r, a, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PostDeletionHook runs the `selfservice.flows.deletion.after` hooks once the
+// identity was deleted. The identity is the state before the deletion.
+func (e *HookExecutor) PostDeletionHook(w http.ResponseWriter, r *http.Request, a *Flow, i *identity.Identity) error {
+	logger := e.d.Logger().
+		WithRequest(r).
+		WithField("identity_id", i.ID)
+
+	logger.Debug("Running ExecuteDeletionPostHooks.")
+	hooks, err := e.d.PostDeletionHooks(r.Context())
+	if err != nil {
+		return err
+	}
+	for k, executor := range hooks {
+		if err := executor.ExecuteDeletionPostHook(w, r, a, i); err != nil {
+			return err
+		}
+
+		logger.
+			WithField("executor", fmt.Sprintf("%T", executor)).
+			WithField("executor_position", k).
+			WithField("executors", PostHookDeletionExecutorNames(hooks)).
+			Debug("ExecuteDeletionPostHook completed successfully.")
+	}
+
+	logger.Debug("Post deletion execution hooks completed successfully.")
+
+	return nil
+}
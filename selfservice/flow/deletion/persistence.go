@@ -0,0 +1,23 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type (
+	FlowPersister interface {
+		CreateDeletionFlow(context.Context, *Flow) error
+		GetDeletionFlow(ctx context.Context, id uuid.UUID) (*Flow, error)
+		UpdateDeletionFlow(context.Context, *Flow) error
+		DeleteExpiredDeletionFlows(context.Context, time.Time, int) error
+	}
+	FlowPersistenceProvider interface {
+		DeletionFlowPersister() FlowPersister
+	}
+)
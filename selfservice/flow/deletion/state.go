@@ -0,0 +1,14 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import "github.com/ory/kratos/selfservice/flow"
+
+// State represents the state of this flow. It knows two states:
+//
+//   - show_form: The account was not deleted yet, and thus the form should be shown.
+//   - success: Indicates that the account was deleted.
+//
+// swagger:model deletionFlowState
+type State = flow.State
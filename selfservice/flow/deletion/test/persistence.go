@@ -0,0 +1,112 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/persistence"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/deletion"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/sqlcon"
+)
+
+func TestFlowPersister(ctx context.Context, p persistence.Persister) func(t *testing.T) {
+	return func(t *testing.T) {
+		_, p := testhelpers.NewNetworkUnlessExisting(t, ctx, p)
+
+		newFlow := func(t *testing.T) *deletion.Flow {
+			var r deletion.Flow
+			require.NoError(t, faker.FakeData(&r))
+			r.ID = x.NewUUID()
+			r.IdentityID = x.NewUUID()
+			r.State = flow.StateShowForm
+			return &r
+		}
+
+		t.Run("case=should error when the deletion flow does not exist", func(t *testing.T) {
+			_, err := p.GetDeletionFlow(ctx, x.NewUUID())
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+		})
+
+		t.Run("case=should create and fetch a deletion flow", func(t *testing.T) {
+			expected := newFlow(t)
+			require.NoError(t, p.CreateDeletionFlow(ctx, expected))
+
+			actual, err := p.GetDeletionFlow(ctx, expected.ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected.ID, actual.ID)
+			assert.Equal(t, expected.IdentityID, actual.IdentityID)
+			assert.Equal(t, expected.Type, actual.Type)
+			assert.Equal(t, expected.RequestURL, actual.RequestURL)
+			assert.Equal(t, expected.UI.Action, actual.UI.Action)
+			x.AssertEqualTime(t, expected.IssuedAt, actual.IssuedAt)
+			x.AssertEqualTime(t, expected.ExpiresAt, actual.ExpiresAt)
+
+			t.Run("can not fetch on another network", func(t *testing.T) {
+				_, p := testhelpers.NewNetwork(t, ctx, p)
+				_, err := p.GetDeletionFlow(ctx, expected.ID)
+				require.ErrorIs(t, err, sqlcon.ErrNoRows)
+			})
+		})
+
+		t.Run("case=should outlive the identity it belongs to", func(t *testing.T) {
+			// The identity does not exist, which is the case after it was deleted.
+			f := newFlow(t)
+			require.NoError(t, p.CreateDeletionFlow(ctx, f))
+
+			f.State = flow.StateSuccess
+			require.NoError(t, p.UpdateDeletionFlow(ctx, f))
+
+			actual, err := p.GetDeletionFlow(ctx, f.ID)
+			require.NoError(t, err)
+			assert.Equal(t, flow.StateSuccess, actual.State)
+		})
+
+		t.Run("case=should update a deletion flow", func(t *testing.T) {
+			expected := newFlow(t)
+			require.NoError(t, p.CreateDeletionFlow(ctx, expected))
+
+			expected.UI.Action = "/new-action"
+			expected.UI.Nodes = node.Nodes{node.NewInputField("confirm", false, node.DefaultGroup, node.InputAttributeTypeCheckbox)}
+			require.NoError(t, p.UpdateDeletionFlow(ctx, expected))
+
+			actual, err := p.GetDeletionFlow(ctx, expected.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "/new-action", actual.UI.Action)
+			require.Len(t, actual.UI.Nodes, 1)
+			assert.Equal(t, "confirm", actual.UI.Nodes[0].ID())
+
+			t.Run("can not update on another network", func(t *testing.T) {
+				_, p := testhelpers.NewNetwork(t, ctx, p)
+				expected.UI.Action = "/other-action"
+				require.ErrorIs(t, p.UpdateDeletionFlow(ctx, expected), sqlcon.ErrNoRows)
+			})
+		})
+
+		t.Run("case=should delete expired deletion flows", func(t *testing.T) {
+			expired, active := newFlow(t), newFlow(t)
+			expired.ExpiresAt = time.Now().Add(-time.Hour).UTC()
+			active.ExpiresAt = time.Now().Add(time.Hour).UTC()
+			require.NoError(t, p.CreateDeletionFlow(ctx, expired))
+			require.NoError(t, p.CreateDeletionFlow(ctx, active))
+
+			require.NoError(t, p.DeleteExpiredDeletionFlows(ctx, time.Now(), 100))
+
+			_, err := p.GetDeletionFlow(ctx, expired.ID)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows)
+			_, err = p.GetDeletionFlow(ctx, active.ID)
+			require.NoError(t, err)
+		})
+	}
+}
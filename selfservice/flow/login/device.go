@@ -0,0 +1,134 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package login
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/text"
+	"github.com/ory/x/randx"
+	"github.com/ory/x/sqlxx"
+)
+
+// DeviceAuthorizationState describes whether a device authorization is still
+// waiting for the user, or whether the user approved or denied it.
+//
+// swagger:enum deviceAuthorizationState
+type DeviceAuthorizationState string
+
+const (
+	DeviceAuthorizationStatePending  DeviceAuthorizationState = "pending"
+	DeviceAuthorizationStateApproved DeviceAuthorizationState = "approved"
+	DeviceAuthorizationStateDenied   DeviceAuthorizationState = "denied"
+)
+
+// userCodeAlphabet omits vowels and easily confused characters so that user
+// codes can be read off a TV screen and typed in without mistakes, and never
+// spell out words.
+var userCodeAlphabet = []rune("BCDFGHJKLMNPQRSTVWXZ")
+
+const userCodeLength = 8
+
+var (
+	ErrDeviceAuthorizationDisabled = herodot.ErrBadRequest.WithID(text.ErrIDSelfServiceFlowDisabled).WithError("device authorization disabled").WithReason("Device authorization is not allowed because it was disabled.")
+	ErrDeviceAuthorizationPending  = herodot.ErrBadRequest.WithID(text.ErrIDDeviceAuthorizationPending).WithError("authorization pending").WithReason("The user has not yet approved the device authorization.")
+	ErrDeviceSlowDown              = herodot.ErrBadRequest.WithID(text.ErrIDDeviceSlowDown).WithError("slow down").WithReason("The device is polling too frequently and must increase the polling interval.")
+	ErrDeviceAccessDenied          = herodot.ErrBadRequest.WithID(text.ErrIDDeviceAccessDenied).WithError("access denied").WithReason("The user denied the device authorization.")
+	ErrDeviceExpiredToken          = herodot.ErrBadRequest.WithID(text.ErrIDDeviceExpiredToken).WithError("expired token").WithReason("The device code has expired or is unknown. Please start a new device authorization.")
+)
+
+type (
+	// DeviceAuthorization links a device, which polls using the secret device
+	// code, to the browser session of the user who entered the user code.
+	DeviceAuthorization struct {
+		ID  uuid.UUID `db:"id"`
+		NID uuid.UUID `db:"nid"`
+
+		// DeviceCode is only known to the device and used to poll for the session token.
+		DeviceCode string `db:"device_code"`
+
+		// UserCode is shown by the device and entered by the user in the browser. It is
+		// stored without separators.
+		UserCode string `db:"user_code"`
+
+		State DeviceAuthorizationState `db:"state"`
+
+		// IdentityID is set once the user approved the device authorization.
+		IdentityID uuid.NullUUID `db:"identity_id"`
+
+		// SessionID is the session which approved the device authorization.
+		SessionID uuid.NullUUID `db:"session_id"`
+
+		ExpiresAt    time.Time      `db:"expires_at"`
+		LastPolledAt sqlxx.NullTime `db:"last_polled_at"`
+		CreatedAt    time.Time      `db:"created_at"`
+		UpdatedAt    time.Time      `db:"updated_at"`
+	}
+
+	DeviceAuthorizationPersister interface {
+		CreateLoginDeviceAuthorization(context.Context, *DeviceAuthorization) error
+		GetLoginDeviceAuthorizationByDeviceCode(ctx context.Context, deviceCode string) (*DeviceAuthorization, error)
+		GetLoginDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+
+		// SetLoginDeviceAuthorizationPolledAt records when the device polled. It
+		// returns sqlcon.ErrNoRows if the device authorization is no longer pending.
+		SetLoginDeviceAuthorizationPolledAt(ctx context.Context, id uuid.UUID, polledAt time.Time) error
+
+		// ResolveLoginDeviceAuthorization stores whether the user approved or denied
+		// the device authorization. It returns sqlcon.ErrNoRows if it was approved or
+		// denied already, so that only the first decision counts.
+		ResolveLoginDeviceAuthorization(context.Context, *DeviceAuthorization) error
+
+		// DeleteLoginDeviceAuthorization removes the device authorization. It returns
+		// sqlcon.ErrNoRows if it was deleted already, which allows consuming the grant
+		// exactly once.
+		DeleteLoginDeviceAuthorization(ctx context.Context, id uuid.UUID) error
+		DeleteExpiredLoginDeviceAuthorizations(context.Context, time.Time, int) error
+	}
+	DeviceAuthorizationPersistenceProvider interface {
+		LoginDeviceAuthorizationPersister() DeviceAuthorizationPersister
+	}
+)
+
+func (DeviceAuthorization) TableName() string {
+	return "selfservice_login_device_authorizations"
+}
+
+// NewDeviceAuthorization returns a pending device authorization with fresh codes.
+func NewDeviceAuthorization(lifespan time.Duration) *DeviceAuthorization {
+	now := time.Now().UTC()
+	return &DeviceAuthorization{
+		ID:         uuid.Must(uuid.NewV4()),
+		DeviceCode: randx.MustString(64, randx.AlphaNum),
+		UserCode:   randx.MustString(userCodeLength, userCodeAlphabet),
+		State:      DeviceAuthorizationStatePending,
+		ExpiresAt:  now.Add(lifespan),
+	}
+}
+
+func (a *DeviceAuthorization) IsExpired() bool {
+	return a.ExpiresAt.Before(time.Now())
+}
+
+// FormattedUserCode returns the user code as shown to users, e.g. `BCDF-GHJK`.
+func (a *DeviceAuthorization) FormattedUserCode() string {
+	return a.UserCode[:userCodeLength/2] + "-" + a.UserCode[userCodeLength/2:]
+}
+
+// NormalizeUserCode removes separators and whitespace which users may have
+// typed along with the user code.
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
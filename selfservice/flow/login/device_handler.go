@@ -0,0 +1,562 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package login
+
+import (
+	_ "embed"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/urlx"
+)
+
+const (
+	RouteDeviceAuthorization = "/self-service/login/device"
+	RouteDeviceToken         = "/self-service/login/device/token"
+	RouteDeviceVerify        = "/self-service/login/device/verify"
+)
+
+//go:embed .schema/device.schema.json
+var deviceSchema []byte
+
+// Device Authorization
+//
+// Contains the codes a device needs to sign in. The device shows the user code and
+// verification URI to the user and polls for the session token using the device code.
+//
+// swagger:model loginDeviceAuthorization
+type deviceAuthorizationResponse struct {
+	// The device code used to poll for the session token. It must be kept secret.
+	//
+	// required: true
+	DeviceCode string `json:"device_code"`
+
+	// The user code which the user enters at the verification URI.
+	//
+	// required: true
+	UserCode string `json:"user_code"`
+
+	// The URL where the user enters the user code.
+	//
+	// required: true
+	VerificationURI string `json:"verification_uri"`
+
+	// The verification URI with the user code already filled in, for example to be
+	// rendered as a QR code.
+	//
+	// required: true
+	VerificationURIComplete string `json:"verification_uri_complete"`
+
+	// The lifetime of the device and user codes in seconds.
+	//
+	// required: true
+	ExpiresIn int `json:"expires_in"`
+
+	// The minimum amount of seconds the device must wait between polling requests.
+	//
+	// required: true
+	Interval int `json:"interval"`
+}
+
+// Device Authorization Verification
+//
+// swagger:model loginDeviceAuthorizationVerification
+type deviceVerificationResponse struct {
+	// The user code, formatted for display.
+	//
+	// required: true
+	UserCode string `json:"user_code"`
+
+	// The state of the device authorization.
+	//
+	// required: true
+	State DeviceAuthorizationState `json:"state"`
+
+	// The time at which the device requested authorization.
+	//
+	// required: true
+	RequestedAt time.Time `json:"requested_at"`
+
+	// The time at which the user code expires.
+	//
+	// required: true
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// The anti-CSRF token to be sent when approving or denying the device.
+	CSRFToken string `json:"csrf_token,omitempty"`
+}
+
+// swagger:parameters pollLoginDeviceAuthorization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type pollLoginDeviceAuthorization struct {
+	// in: body
+	// required: true
+	Body pollLoginDeviceAuthorizationBody
+}
+
+// swagger:model pollLoginDeviceAuthorizationBody
+type pollLoginDeviceAuthorizationBody struct {
+	// The device code returned when the device authorization was created.
+	//
+	// required: true
+	DeviceCode string `json:"device_code" form:"device_code"`
+}
+
+// swagger:parameters getLoginDeviceAuthorization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getLoginDeviceAuthorization struct {
+	// The user code shown by the device.
+	//
+	// required: true
+	// in: query
+	UserCode string `json:"user_code"`
+
+	// HTTP Cookies
+	//
+	// When using the SDK in a browser app, on the server side you must include the HTTP Cookie Header
+	// sent by the client to your server here. This ensures that CSRF and session cookies are respected.
+	//
+	// in: header
+	// name: Cookie
+	Cookies string `json:"Cookie"`
+}
+
+// swagger:parameters updateLoginDeviceAuthorization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateLoginDeviceAuthorization struct {
+	// in: body
+	// required: true
+	Body updateLoginDeviceAuthorizationBody
+
+	// HTTP Cookies
+	//
+	// When using the SDK in a browser app, on the server side you must include the HTTP Cookie Header
+	// sent by the client to your server here. This ensures that CSRF and session cookies are respected.
+	//
+	// in: header
+	// name: Cookie
+	Cookies string `json:"Cookie"`
+}
+
+// swagger:model updateLoginDeviceAuthorizationBody
+type updateLoginDeviceAuthorizationBody struct {
+	// The user code shown by the device.
+	//
+	// required: true
+	UserCode string `json:"user_code" form:"user_code"`
+
+	// Either `approve` or `deny`.
+	//
+	// required: true
+	Action string `json:"action" form:"action"`
+
+	// The anti-CSRF token returned when fetching the device authorization.
+	//
+	// required: true
+	CSRFToken string `json:"csrf_token" form:"csrf_token"`
+}
+
+func (h *Handler) decodeDeviceBody(r *http.Request, dest interface{}) error {
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(deviceSchema)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return h.hd.Decode(r, dest, compiler,
+		decoderx.HTTPDecoderSetValidatePayloads(true),
+		decoderx.HTTPDecoderJSONFollowsFormFormat(),
+	)
+}
+
+// swagger:route POST /self-service/login/device frontend createLoginDeviceAuthorization
+//
+// # Create Device Authorization
+//
+// Use this endpoint on devices which can not host a browser, such as CLIs or smart TVs. The device
+// displays the returned `user_code` and `verification_uri` to the user, who approves the device in a
+// browser with an existing session. Meanwhile, the device polls the
+// `/self-service/login/device/token` endpoint with the `device_code` until it receives a session token.
+//
+// This endpoint returns a 400 error with `error.id` `self_service_flow_disabled` unless
+// `selfservice.flows.login.device.enabled` is set.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: loginDeviceAuthorization
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) createDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	conf := h.d.Config().SelfServiceFlowLoginDevice(ctx)
+	if !conf.Enabled {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAuthorizationDisabled))
+		return
+	}
+
+	var a *DeviceAuthorization
+	// User codes are short, so we retry in the unlikely case of a collision.
+	for range 3 {
+		a = NewDeviceAuthorization(conf.Lifespan)
+		err := h.d.LoginDeviceAuthorizationPersister().CreateLoginDeviceAuthorization(ctx, a)
+		if errors.Is(err, sqlcon.ErrUniqueViolation) {
+			continue
+		} else if err != nil {
+			h.d.Writer().WriteError(w, r, err)
+			return
+		}
+
+		h.d.Writer().Write(w, r, &deviceAuthorizationResponse{
+			DeviceCode:              a.DeviceCode,
+			UserCode:                a.FormattedUserCode(),
+			VerificationURI:         conf.UI.String(),
+			VerificationURIComplete: urlx.CopyWithQuery(conf.UI, url.Values{"user_code": {a.FormattedUserCode()}}).String(),
+			ExpiresIn:               int(conf.Lifespan.Seconds()),
+			Interval:                int(conf.PollingInterval.Seconds()),
+		})
+		return
+	}
+
+	h.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrInternalServerError.WithReason("Unable to generate a unique user code.")))
+}
+
+// swagger:route POST /self-service/login/device/token frontend pollLoginDeviceAuthorization
+//
+// # Poll Device Authorization
+//
+// Devices poll this endpoint with the `device_code` until the user approved or denied the
+// device. Once approved, a new session is issued for the identity which approved the device and
+// its session token is returned. The device code can only be exchanged once.
+//
+// This request fails with a 400 error while no session token can be issued. The `error.id` can be one of:
+//
+// - `authorization_pending`: The user has not approved the device yet. Keep polling.
+// - `slow_down`: The device polls faster than the returned `interval`. Keep polling, but less frequently.
+// - `access_denied`: The user denied the device. Stop polling.
+// - `expired_token`: The device code expired or was used already. Stop polling.
+//
+//	Consumes:
+//	- application/json
+//	- application/x-www-form-urlencoded
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: successfulCodeExchangeResponse
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) pollDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	conf := h.d.Config().SelfServiceFlowLoginDevice(ctx)
+	if !conf.Enabled {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAuthorizationDisabled))
+		return
+	}
+
+	var p pollLoginDeviceAuthorizationBody
+	if err := h.decodeDeviceBody(r, &p); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	a, err := h.d.LoginDeviceAuthorizationPersister().GetLoginDeviceAuthorizationByDeviceCode(ctx, p.DeviceCode)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceExpiredToken))
+		return
+	} else if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if a.IsExpired() {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceExpiredToken))
+		return
+	}
+
+	switch a.State {
+	case DeviceAuthorizationStateDenied:
+		if err := h.d.LoginDeviceAuthorizationPersister().DeleteLoginDeviceAuthorization(ctx, a.ID); err != nil && !errors.Is(err, sqlcon.ErrNoRows) {
+			h.d.Writer().WriteError(w, r, err)
+			return
+		}
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAccessDenied))
+		return
+	case DeviceAuthorizationStateApproved:
+		h.issueDeviceSession(w, r, a)
+		return
+	}
+
+	// Only the poll time is written, so that an approval or denial which
+	// happened since the device authorization was loaded is kept. The device
+	// learns about it with the next poll.
+	now := time.Now().UTC()
+	lastPolledAt := time.Time(a.LastPolledAt)
+	if err := h.d.LoginDeviceAuthorizationPersister().SetLoginDeviceAuthorizationPolledAt(ctx, a.ID, now); errors.Is(err, sqlcon.ErrNoRows) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAuthorizationPending))
+		return
+	} else if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if !lastPolledAt.IsZero() && now.Before(lastPolledAt.Add(conf.PollingInterval)) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceSlowDown))
+		return
+	}
+
+	h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAuthorizationPending))
+}
+
+func (h *Handler) issueDeviceSession(w http.ResponseWriter, r *http.Request, a *DeviceAuthorization) {
+	ctx := r.Context()
+
+	// Deleting the device authorization first ensures that concurrent polls can
+	// not exchange the same device code twice.
+	if err := h.d.LoginDeviceAuthorizationPersister().DeleteLoginDeviceAuthorization(ctx, a.ID); errors.Is(err, sqlcon.ErrNoRows) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceExpiredToken))
+		return
+	} else if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	// The device inherits the authentication methods of the session which
+	// approved it. If that session was revoked in the meantime, the approval is void.
+	approvedBy, err := h.d.SessionPersister().GetSession(ctx, a.SessionID.UUID, session.ExpandNothing)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAccessDenied))
+		return
+	} else if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	} else if !approvedBy.IsActive() {
+		h.d.Writer().WriteError(w, r, errors.WithStack(ErrDeviceAccessDenied))
+		return
+	}
+
+	i, err := h.d.IdentityPool().GetIdentity(ctx, a.IdentityID.UUID, identity.ExpandDefault)
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	s := session.NewInactiveSession()
+	s.AMR = append(s.AMR, approvedBy.AMR...)
+	if err := h.d.SessionManager().ActivateSession(r, s, i, time.Now().UTC()); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.d.SessionPersister().UpsertSession(ctx, s); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.d.Audit().
+		WithRequest(r).
+		WithField("identity_id", i.ID).
+		WithField("session_id", s.ID).
+		WithField("approved_by_session_id", approvedBy.ID).
+		Info("Issued a session to a device after the user approved the device authorization.")
+
+	h.d.Writer().Write(w, r, &session.CodeExchangeResponse{
+		Token:   s.Token,
+		Session: s.Declassified(),
+	})
+}
+
+// swagger:route GET /self-service/login/device/verify frontend getLoginDeviceAuthorization
+//
+// # Get Device Authorization
+//
+// Returns the device authorization for the user code entered by the user, together with the
+// anti-CSRF token needed to approve or deny it. This endpoint requires a session.
+//
+// Browser flows expect the anti-CSRF cookie to be included in the request's HTTP Cookie Header.
+// For AJAX requests you must ensure that cookies are included in the request or requests will fail.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: loginDeviceAuthorizationVerification
+//	  400: errorGeneric
+//	  401: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	a, _, err := h.fetchDeviceAuthorization(r, r.URL.Query().Get("user_code"))
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.d.Writer().Write(w, r, &deviceVerificationResponse{
+		UserCode:    a.FormattedUserCode(),
+		State:       a.State,
+		RequestedAt: a.CreatedAt,
+		ExpiresAt:   a.ExpiresAt,
+		CSRFToken:   h.d.GenerateCSRFToken(r),
+	})
+}
+
+// swagger:route POST /self-service/login/device/verify frontend updateLoginDeviceAuthorization
+//
+// # Approve or Deny Device Authorization
+//
+// Approves or denies the device which shows the given user code. Once approved, the device
+// receives a session for the identity of the current session on its next poll.
+//
+// This endpoint requires a session and the anti-CSRF token returned by `getLoginDeviceAuthorization`.
+// Browsers are redirected back to the device verification UI, while API and AJAX requests receive
+// the updated device authorization.
+//
+//	Consumes:
+//	- application/json
+//	- application/x-www-form-urlencoded
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: loginDeviceAuthorizationVerification
+//	  303: emptyResponse
+//	  400: errorGeneric
+//	  401: errorGeneric
+//	  403: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) updateDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var p updateLoginDeviceAuthorizationBody
+	if err := h.decodeDeviceBody(r, &p); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := flow.EnsureCSRF(h.d, r, flow.TypeBrowser, false, h.d.GenerateCSRFToken, p.CSRFToken); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	a, sess, err := h.fetchDeviceAuthorization(r, p.UserCode)
+	if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if a.State != DeviceAuthorizationStatePending {
+		h.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("This device was approved or denied already.")))
+		return
+	}
+
+	switch p.Action {
+	case "approve":
+		// Impersonated sessions must not be able to hand out sessions of their own.
+		if sess.Impersonation != nil {
+			h.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrForbidden.WithID(text.ErrIDSessionImpersonated).
+				WithReason("Impersonated sessions are not allowed to approve devices.")))
+			return
+		}
+		a.State = DeviceAuthorizationStateApproved
+		a.IdentityID = uuid.NullUUID{UUID: sess.IdentityID, Valid: true}
+		a.SessionID = uuid.NullUUID{UUID: sess.ID, Valid: true}
+	case "deny":
+		a.State = DeviceAuthorizationStateDenied
+	default:
+		h.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`The action must be either "approve" or "deny" but got "%s".`, p.Action)))
+		return
+	}
+
+	if err := h.d.LoginDeviceAuthorizationPersister().ResolveLoginDeviceAuthorization(ctx, a); errors.Is(err, sqlcon.ErrNoRows) {
+		h.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("This device was approved or denied already.")))
+		return
+	} else if err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.d.Audit().
+		WithRequest(r).
+		WithField("identity_id", sess.IdentityID).
+		WithField("session_id", sess.ID).
+		WithField("state", a.State).
+		Info("A device authorization was approved or denied.")
+
+	ui := h.d.Config().SelfServiceFlowLoginDevice(ctx).UI
+	redir.ContentNegotiationRedirection(w, r, &deviceVerificationResponse{
+		UserCode:    a.FormattedUserCode(),
+		State:       a.State,
+		RequestedAt: a.CreatedAt,
+		ExpiresAt:   a.ExpiresAt,
+	}, h.d.Writer(), urlx.CopyWithQuery(ui, url.Values{
+		"user_code": {a.FormattedUserCode()},
+		"state":     {string(a.State)},
+	}).String())
+}
+
+// fetchDeviceAuthorization loads the pending device authorization for the
+// user code and the session of the user who is about to approve it.
+func (h *Handler) fetchDeviceAuthorization(r *http.Request, userCode string) (*DeviceAuthorization, *session.Session, error) {
+	ctx := r.Context()
+	if !h.d.Config().SelfServiceFlowLoginDevice(ctx).Enabled {
+		return nil, nil, errors.WithStack(ErrDeviceAuthorizationDisabled)
+	}
+
+	sess, err := h.d.SessionManager().FetchFromRequest(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := h.d.SessionManager().DoesSessionSatisfy(ctx, sess, h.d.Config().SessionWhoAmIAAL(ctx)); err != nil {
+		return nil, nil, err
+	}
+
+	userCode = NormalizeUserCode(userCode)
+	if len(userCode) == 0 {
+		return nil, nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The user code must be set."))
+	}
+
+	a, err := h.d.LoginDeviceAuthorizationPersister().GetLoginDeviceAuthorizationByUserCode(ctx, userCode)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return nil, nil, errors.WithStack(herodot.ErrNotFound.WithReason("The user code is invalid or has expired."))
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if a.IsExpired() {
+		return nil, nil, errors.WithStack(herodot.ErrNotFound.WithReason("The user code is invalid or has expired."))
+	}
+
+	return a, sess, nil
+}
@@ -0,0 +1,242 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package login_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/x/sqlcon"
+)
+
+func TestDeviceAuthorization(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/password.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginDeviceUI, "https://www.ory.sh/device")
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginDevicePollingInterval, "1ns")
+	conf.MustSet(ctx, config.ViperKeySelfServiceLoginDeviceEnabled, true)
+
+	public, _ := testhelpers.NewKratosServer(t, reg)
+
+	post := func(t *testing.T, c *http.Client, path string, body any) (*http.Response, []byte) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest("POST", public.URL+path, strings.NewReader(string(raw)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, resBody
+	}
+
+	get := func(t *testing.T, c *http.Client, userCode string) (*http.Response, []byte) {
+		req, err := http.NewRequest("GET", public.URL+login.RouteDeviceVerify+"?"+url.Values{"user_code": {userCode}}.Encode(), nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/json")
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, body
+	}
+
+	authorize := func(t *testing.T) (deviceCode, userCode string) {
+		res, body := post(t, http.DefaultClient, login.RouteDeviceAuthorization, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Len(t, gjson.GetBytes(body, "device_code").String(), 64)
+		assert.Regexp(t, `^[B-Z]{4}-[B-Z]{4}$`, gjson.GetBytes(body, "user_code").String())
+		assert.Equal(t, "https://www.ory.sh/device", gjson.GetBytes(body, "verification_uri").String())
+		assert.Equal(t, "https://www.ory.sh/device?user_code="+gjson.GetBytes(body, "user_code").String(), gjson.GetBytes(body, "verification_uri_complete").String())
+		assert.EqualValues(t, 600, gjson.GetBytes(body, "expires_in").Int())
+		return gjson.GetBytes(body, "device_code").String(), gjson.GetBytes(body, "user_code").String()
+	}
+
+	poll := func(t *testing.T, deviceCode string) (*http.Response, []byte) {
+		return post(t, http.DefaultClient, login.RouteDeviceToken, map[string]string{"device_code": deviceCode})
+	}
+
+	verify := func(t *testing.T, c *http.Client, userCode, action string) (*http.Response, []byte) {
+		return post(t, c, login.RouteDeviceVerify, map[string]string{"user_code": userCode, "action": action, "csrf_token": nosurfx.FakeCSRFToken})
+	}
+
+	newBrowser := func(t *testing.T) (*http.Client, *identity.Identity) {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"username":"` + x.NewUUID().String() + `"}`)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return testhelpers.NewHTTPClientWithIdentitySessionCookie(ctx, t, reg, i), i
+	}
+
+	t.Run("case=device authorization is disabled", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginDeviceEnabled, false)
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceLoginDeviceEnabled, true) })
+
+		res, body := post(t, http.DefaultClient, login.RouteDeviceAuthorization, nil)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, text.ErrIDSelfServiceFlowDisabled, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=approved device receives a session token once", func(t *testing.T) {
+		deviceCode, userCode := authorize(t)
+
+		res, body := poll(t, deviceCode)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, text.ErrIDDeviceAuthorizationPending, gjson.GetBytes(body, "error.id").String(), "%s", body)
+
+		browser, i := newBrowser(t)
+
+		// Users may type the code in lower case and without the separator.
+		res, body = get(t, browser, strings.ToLower(strings.ReplaceAll(userCode, "-", "")))
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, userCode, gjson.GetBytes(body, "user_code").String())
+		assert.EqualValues(t, login.DeviceAuthorizationStatePending, gjson.GetBytes(body, "state").String())
+		assert.NotEmpty(t, gjson.GetBytes(body, "csrf_token").String())
+
+		res, body = verify(t, browser, userCode, "approve")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.EqualValues(t, login.DeviceAuthorizationStateApproved, gjson.GetBytes(body, "state").String())
+
+		res, body = verify(t, browser, userCode, "deny")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+		res, body = poll(t, deviceCode)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		token := gjson.GetBytes(body, "session_token").String()
+		require.NotEmpty(t, token)
+		assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "session.identity.id").String())
+		assert.Equal(t, "password", gjson.GetBytes(body, "session.authentication_methods.0.method").String(), "%s", body)
+
+		s, err := reg.SessionPersister().GetSessionByToken(ctx, token, session.ExpandNothing, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.True(t, s.IsActive())
+		assert.Equal(t, i.ID, s.IdentityID)
+
+		res, body = poll(t, deviceCode)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, text.ErrIDDeviceExpiredToken, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=denied device is told to stop polling", func(t *testing.T) {
+		deviceCode, userCode := authorize(t)
+		browser, _ := newBrowser(t)
+
+		res, body := verify(t, browser, userCode, "deny")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+
+		res, body = poll(t, deviceCode)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, text.ErrIDDeviceAccessDenied, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=a concurrent poll does not overwrite the approval", func(t *testing.T) {
+		deviceCode, userCode := authorize(t)
+		browser, i := newBrowser(t)
+
+		stale, err := reg.LoginDeviceAuthorizationPersister().GetLoginDeviceAuthorizationByDeviceCode(ctx, deviceCode)
+		require.NoError(t, err)
+
+		res, body := verify(t, browser, userCode, "approve")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+
+		require.ErrorIs(t, reg.LoginDeviceAuthorizationPersister().SetLoginDeviceAuthorizationPolledAt(ctx, stale.ID, time.Now().UTC()), sqlcon.ErrNoRows)
+		stale.State = login.DeviceAuthorizationStateDenied
+		require.ErrorIs(t, reg.LoginDeviceAuthorizationPersister().ResolveLoginDeviceAuthorization(ctx, stale), sqlcon.ErrNoRows)
+
+		res, body = poll(t, deviceCode)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "session.identity.id").String(), "%s", body)
+	})
+
+	t.Run("case=revoking the approving session voids the approval", func(t *testing.T) {
+		deviceCode, userCode := authorize(t)
+		browser, i := newBrowser(t)
+
+		res, body := verify(t, browser, userCode, "approve")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		_, err := reg.SessionPersister().RevokeSessionsIdentityExcept(ctx, i.ID, x.NewUUID())
+		require.NoError(t, err)
+
+		res, body = poll(t, deviceCode)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, text.ErrIDDeviceAccessDenied, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=polling too frequently asks to slow down", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginDevicePollingInterval, "1h")
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceLoginDevicePollingInterval, "1ns") })
+
+		deviceCode, _ := authorize(t)
+
+		_, body := poll(t, deviceCode)
+		assert.Equal(t, text.ErrIDDeviceAuthorizationPending, gjson.GetBytes(body, "error.id").String(), "%s", body)
+
+		_, body = poll(t, deviceCode)
+		assert.Equal(t, text.ErrIDDeviceSlowDown, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=expired device codes can not be used", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceLoginDeviceLifespan, "1ns")
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySelfServiceLoginDeviceLifespan, "10m") })
+
+		res, body := post(t, http.DefaultClient, login.RouteDeviceAuthorization, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		time.Sleep(time.Millisecond)
+
+		browser, _ := newBrowser(t)
+		res, _ = get(t, browser, gjson.GetBytes(body, "user_code").String())
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		_, body = poll(t, gjson.GetBytes(body, "device_code").String())
+		assert.Equal(t, text.ErrIDDeviceExpiredToken, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=approval requires a session", func(t *testing.T) {
+		_, userCode := authorize(t)
+
+		res, body := get(t, http.DefaultClient, userCode)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "%s", body)
+
+		res, body = verify(t, http.DefaultClient, userCode, "approve")
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=approval requires the anti-CSRF token", func(t *testing.T) {
+		_, userCode := authorize(t)
+		browser, _ := newBrowser(t)
+
+		res, body := post(t, browser, login.RouteDeviceVerify, map[string]string{"user_code": userCode, "action": "approve"})
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "%s", body)
+		assert.Equal(t, text.ErrIDCSRF, gjson.GetBytes(body, "error.id").String(), "%s", body)
+	})
+
+	t.Run("case=unknown user code", func(t *testing.T) {
+		browser, _ := newBrowser(t)
+		res, _ := get(t, browser, "BCDF-GHJK")
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
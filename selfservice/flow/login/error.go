@@ -101,13 +101,13 @@ func (s *ErrorHandler) WriteFlowError(w http.ResponseWriter, r *http.Request, f
 	logger.Info("Encountered self-service login error.")
 
 	if f == nil {
-		trace.SpanFromContext(r.Context()).AddEvent(events.NewLoginFailed(r.Context(), uuid.Nil, "", "", "", false, err))
+		events.SpanFromContext(r.Context()).AddEvent(events.NewLoginFailed(r.Context(), uuid.Nil, "", "", "", false, err))
 		s.forward(w, r, nil, err)
 		return
 	}
 
 	span.SetAttributes(attribute.String("flow_id", f.ID.String()))
-	trace.SpanFromContext(r.Context()).AddEvent(events.NewLoginFailed(r.Context(), f.ID, string(f.Type), ct.String(), string(f.RequestedAAL), f.Refresh, err))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewLoginFailed(r.Context(), f.ID, string(f.Type), ct.String(), string(f.RequestedAAL), f.Refresh, err))
 
 	if expired, inner := s.PrepareReplacementForExpiredFlow(w, r, f, err); inner != nil {
 		s.WriteFlowError(w, r, f, ct, group, inner)
@@ -143,7 +143,7 @@ func (s *ErrorHandler) WriteFlowError(w http.ResponseWriter, r *http.Request, f
 	}
 
 	_, hasCode, _ := s.d.SessionTokenExchangePersister().CodeForFlow(r.Context(), f.ID)
-	if f.Type == flow.TypeAPI && hasCode && group == node.OpenIDConnectGroup {
+	if f.Type == flow.TypeAPI && hasCode && (group == node.OpenIDConnectGroup || group == node.SAMLGroup) {
 		http.Redirect(w, r, f.ReturnTo, http.StatusSeeOther)
 		return
 	}
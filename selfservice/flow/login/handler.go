@@ -11,7 +11,6 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ory/herodot"
 	hydraclientgo "github.com/ory/hydra-client-go/v2"
@@ -19,6 +18,7 @@ import (
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/captcha"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
@@ -52,11 +52,14 @@ type (
 	handlerDependencies interface {
 		HookExecutorProvider
 		FlowPersistenceProvider
+		DeviceAuthorizationPersistenceProvider
 		errorx.ManagementProvider
 		hydra.Provider
 		StrategyProvider
 		session.HandlerProvider
 		session.ManagementProvider
+		session.PersistenceProvider
+		identity.PoolProvider
 		x.WriterProvider
 		nosurfx.CSRFTokenGeneratorProvider
 		nosurfx.CSRFProvider
@@ -65,6 +68,7 @@ type (
 		ErrorHandlerProvider
 		sessiontokenexchange.PersistenceProvider
 		x.LoggingProvider
+		captcha.VerificationProvider
 	}
 	HandlerProvider interface {
 		LoginHandler() *Handler
@@ -80,6 +84,9 @@ func NewHandler(d handlerDependencies) *Handler { return &Handler{d: d, hd: deco
 func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
 	h.d.CSRFHandler().IgnorePath(RouteInitAPIFlow)
 	h.d.CSRFHandler().IgnorePath(RouteSubmitFlow)
+	h.d.CSRFHandler().IgnorePath(RouteDeviceAuthorization)
+	h.d.CSRFHandler().IgnorePath(RouteDeviceToken)
+	h.d.CSRFHandler().IgnorePath(RouteDeviceVerify)
 
 	public.GET(RouteInitBrowserFlow, h.createBrowserLoginFlow)
 	public.GET(RouteInitAPIFlow, h.createNativeLoginFlow)
@@ -87,6 +94,11 @@ func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
 
 	public.POST(RouteSubmitFlow, h.updateLoginFlow)
 	public.GET(RouteSubmitFlow, h.updateLoginFlow)
+
+	public.POST(RouteDeviceAuthorization, h.createDeviceAuthorization)
+	public.POST(RouteDeviceToken, h.pollDeviceAuthorization)
+	public.GET(RouteDeviceVerify, h.getDeviceAuthorization)
+	public.POST(RouteDeviceVerify, h.updateDeviceAuthorization)
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
@@ -96,6 +108,11 @@ func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 
 	admin.POST(RouteSubmitFlow, redir.RedirectToPublicRoute(h.d))
 	admin.GET(RouteSubmitFlow, redir.RedirectToPublicRoute(h.d))
+
+	admin.POST(RouteDeviceAuthorization, redir.RedirectToPublicRoute(h.d))
+	admin.POST(RouteDeviceToken, redir.RedirectToPublicRoute(h.d))
+	admin.GET(RouteDeviceVerify, redir.RedirectToPublicRoute(h.d))
+	admin.POST(RouteDeviceVerify, redir.RedirectToPublicRoute(h.d))
 }
 
 type FlowOption func(f *Flow)
@@ -263,6 +280,12 @@ preLoginHook:
 		}
 	}
 
+	if f.RequestedAAL == identity.AuthenticatorAssuranceLevel1 {
+		if err := h.d.CaptchaVerifier().PopulateNodes(r, f); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if f.Refresh {
 		f.UI.Messages.Set(text.NewInfoLoginReAuth())
 	}
@@ -295,8 +318,7 @@ preLoginHook:
 			if err := strategy.FastLogin2FA(w, r, f, sess); errors.Is(err, flow.ErrStrategyNotResponsible) {
 				continue
 			} else if errors.Is(err, flow.ErrCompletedByStrategy) {
-				span := trace.SpanFromContext(r.Context())
-				span.AddEvent(events.NewLoginInitiated(r.Context(), f.ID, ft.String(), f.Refresh, f.OrganizationID, string(f.RequestedAAL)))
+				events.SpanFromContext(r.Context()).AddEvent(events.NewLoginInitiated(r.Context(), f.ID, ft.String(), f.Refresh, f.OrganizationID, string(f.RequestedAAL)))
 				return nil, nil, err
 			} else if err != nil {
 				return nil, nil, err
@@ -305,8 +327,7 @@ preLoginHook:
 		}
 	}
 
-	span := trace.SpanFromContext(r.Context())
-	span.AddEvent(events.NewLoginInitiated(r.Context(), f.ID, ft.String(), f.Refresh, f.OrganizationID, string(f.RequestedAAL)))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewLoginInitiated(r.Context(), f.ID, ft.String(), f.Refresh, f.OrganizationID, string(f.RequestedAAL)))
 	return f, nil, nil
 }
 
@@ -905,6 +926,13 @@ continueLogin:
 		return
 	}
 
+	if f.RequestedAAL == identity.AuthenticatorAssuranceLevel1 {
+		if err := h.d.CaptchaVerifier().Verify(r, f); err != nil {
+			h.d.LoginFlowErrorHandler().WriteFlowError(w, r, f, "", node.CaptchaGroup, err)
+			return
+		}
+	}
+
 	var ct identity.CredentialsType
 	var i *identity.Identity
 	var group node.UiNodeGroup
@@ -916,6 +944,19 @@ continueLogin:
 		} else if errors.Is(err, flow.ErrCompletedByStrategy) {
 			return
 		} else if err != nil {
+			// The failed attempt may have reached the number of failed
+			// attempts after which a captcha is required.
+			if f.RequestedAAL == identity.AuthenticatorAssuranceLevel1 {
+				if err := h.d.CaptchaVerifier().RecordFailedLogin(r); err != nil {
+					h.d.LoginFlowErrorHandler().WriteFlowError(w, r, f, ss.ID(), group, err)
+					return
+				}
+				if err := h.d.CaptchaVerifier().PopulateNodes(r, f); err != nil {
+					h.d.LoginFlowErrorHandler().WriteFlowError(w, r, f, ss.ID(), group, err)
+					return
+				}
+			}
+
 			h.d.LoginFlowErrorHandler().WriteFlowError(w, r, f, ss.ID(), group, err)
 			return
 		}
@@ -19,6 +19,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/notification"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
@@ -53,6 +54,9 @@ type (
 		identity.ManagementProvider
 		session.ManagementProvider
 		session.PersistenceProvider
+		session.DevicePersistenceProvider
+		session.RiskEvaluatorProvider
+		notification.SecurityNotifierProvider
 		nosurfx.CSRFTokenGeneratorProvider
 		x.WriterProvider
 		x.LoggingProvider
@@ -100,6 +104,47 @@ func (e *HookExecutor) checkAAL(ctx context.Context, s *session.Session, a *Flow
 	return err
 }
 
+// deviceHistory lists the devices the identity used before, if a first factor
+// login needs them for the risk evaluation or the new device notification. It
+// must be called before the session is persisted, as the history would
+// otherwise include the device of this login.
+func (e *HookExecutor) deviceHistory(ctx context.Context, s *session.Session, f *Flow) ([]session.Device, error) {
+	if f.RequestedAAL != identity.AuthenticatorAssuranceLevel1 ||
+		!e.d.Config().SecurityRiskBasedStepUp(ctx).Enabled && !e.d.Config().SecurityNotifications(ctx).NewDeviceLogin {
+		return nil, nil
+	}
+
+	return e.d.SessionDevicePersister().ListDevicesByIdentity(ctx, s.IdentityID, time.Time{}, session.DeviceHistoryLimit)
+}
+
+// evaluateRisk scores first factor logins if risk-based step-up is enabled.
+// Second factor logins keep the risk assessment of the session.
+func (e *HookExecutor) evaluateRisk(ctx context.Context, s *session.Session, f *Flow, history []session.Device) (*session.RiskAssessment, error) {
+	if !e.d.Config().SecurityRiskBasedStepUp(ctx).Enabled || f.RequestedAAL != identity.AuthenticatorAssuranceLevel1 {
+		return nil, nil
+	}
+
+	risk, err := e.d.SessionRiskEvaluator().EvaluateRisk(ctx, s, history)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Risk = risk
+	return risk, nil
+}
+
+func (e *HookExecutor) emitRiskEvaluated(ctx context.Context, s *session.Session, f *Flow, risk *session.RiskAssessment) {
+	if risk == nil {
+		return
+	}
+
+	signals := make([]string, len(risk.Signals))
+	for k, signal := range risk.Signals {
+		signals[k] = string(signal)
+	}
+	events.SpanFromContext(ctx).AddEvent(events.NewLoginRiskEvaluated(ctx, f.ID, s.ID, s.IdentityID, risk.Score, signals, risk.StepUpRequired))
+}
+
 func (e *HookExecutor) handleLoginError(_ http.ResponseWriter, r *http.Request, g node.UiNodeGroup, f *Flow, i *identity.Identity, flowError error) error {
 	if f != nil {
 		if i != nil {
@@ -141,7 +186,7 @@ func (e *HookExecutor) PostLoginHook(
 	s.IdentityID = i.ID
 	s.Identity = i
 
-	if err := e.maybeLinkCredentials(ctx, s, i, f); err != nil {
+	if err := e.maybeLinkCredentials(ctx, r, s, i, f); err != nil {
 		return err
 	}
 
@@ -149,6 +194,16 @@ func (e *HookExecutor) PostLoginHook(
 		return err
 	}
 
+	history, err := e.deviceHistory(ctx, s, f)
+	if err != nil {
+		return err
+	}
+
+	risk, err := e.evaluateRisk(ctx, s, f, history)
+	if err != nil {
+		return err
+	}
+
 	c := e.d.Config()
 	// Verify the redirect URL before we do any other processing.
 	returnTo, err := redir.SecureRedirectTo(r,
@@ -213,6 +268,10 @@ func (e *HookExecutor) PostLoginHook(
 			Debug("ExecuteLoginPostHook completed successfully.")
 	}
 
+	if f.RequestedAAL == identity.AuthenticatorAssuranceLevel1 {
+		e.d.SecurityNotifier().NotifyLogin(ctx, r, s, history)
+	}
+
 	if f.Type == flow.TypeAPI {
 		span.SetAttributes(attribute.String("flow_type", string(flow.TypeAPI)))
 		if err := e.d.SessionPersister().UpsertSession(ctx, s); err != nil {
@@ -224,7 +283,7 @@ func (e *HookExecutor) PostLoginHook(
 			WithField("identity_id", i.ID).
 			Info("Identity authenticated successfully and was issued an Ory Kratos Session Token.")
 
-		span.AddEvent(events.NewLoginSucceeded(ctx, &events.LoginSucceededOpts{
+		events.SpanFromContext(ctx).AddEvent(events.NewLoginSucceeded(ctx, &events.LoginSucceededOpts{
 			SessionID:    s.ID,
 			IdentityID:   i.ID,
 			FlowID:       f.ID,
@@ -234,6 +293,7 @@ func (e *HookExecutor) PostLoginHook(
 			Method:       f.Active.String(),
 			SSOProvider:  provider,
 		}))
+		e.emitRiskEvaluated(ctx, s, f, risk)
 		if f.IDToken != "" {
 			// We don't want to redirect with the code, if the flow was submitted with an ID token.
 			// This is the case for Sign in with native Apple SDK or Google SDK.
@@ -267,12 +327,13 @@ func (e *HookExecutor) PostLoginHook(
 		WithField("session_id", s.ID).
 		Info("Identity authenticated successfully and was issued an Ory Kratos Session Cookie.")
 
-	span.AddEvent(events.NewLoginSucceeded(ctx, &events.LoginSucceededOpts{
+	events.SpanFromContext(ctx).AddEvent(events.NewLoginSucceeded(ctx, &events.LoginSucceededOpts{
 		SessionID:  s.ID,
 		FlowID:     f.ID,
 		IdentityID: i.ID, FlowType: string(f.Type), RequestedAAL: string(f.RequestedAAL), IsRefresh: f.Refresh, Method: f.Active.String(),
 		SSOProvider: provider,
 	}))
+	e.emitRiskEvaluated(ctx, s, f, risk)
 
 	if x.IsJSONRequest(r) {
 		span.SetAttributes(attribute.String("flow_type", "spa"))
@@ -403,7 +464,9 @@ func (e *HookExecutor) PreLoginHook(w http.ResponseWriter, r *http.Request, a *F
 }
 
 // maybeLinkCredentials links the identity with the credentials of the inner context of the login flow.
-func (e *HookExecutor) maybeLinkCredentials(ctx context.Context, sess *session.Session, ident *identity.Identity, loginFlow *Flow) (err error) {
+// The identity is notified about the linked credentials the same way as about
+// credentials added in the settings flow.
+func (e *HookExecutor) maybeLinkCredentials(ctx context.Context, r *http.Request, sess *session.Session, ident *identity.Identity, loginFlow *Flow) (err error) {
 	ctx, span := e.d.Tracer(ctx).Tracer().Start(ctx, "HookExecutor.PostLoginHook.maybeLinkCredentials")
 	defer otelx.End(span, &err)
 
@@ -433,10 +496,22 @@ func (e *HookExecutor) maybeLinkCredentials(ctx context.Context, sess *session.S
 		return errors.Errorf("strategy is not linkable: %T", linkableStrategy)
 	}
 
+	var original *identity.Identity
+	if e.d.SecurityNotifier().CredentialsNotificationsEnabled(ctx) {
+		original, err = e.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, ident.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := linkableStrategy.Link(ctx, ident, lc.CredentialsConfig); err != nil {
 		return err
 	}
 
+	if original != nil {
+		e.d.SecurityNotifier().NotifyCredentialsChanged(ctx, r, original, ident)
+	}
+
 	if err = linkableStrategy.CompletedLogin(sess, lc); err != nil {
 		return err
 	}
@@ -21,6 +21,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
 
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
@@ -33,6 +35,7 @@ import (
 	"github.com/ory/kratos/ui/node"
 	"github.com/ory/kratos/x"
 	"github.com/ory/x/contextx"
+	"github.com/ory/x/pointerx"
 )
 
 func TestLoginExecutor(t *testing.T) {
@@ -319,6 +322,45 @@ func TestLoginExecutor(t *testing.T) {
 						assert.Equal(t, redirectBrowserTo.Query().Get("login_challenge"), hydra.FakeValidLoginChallenge)
 					})
 				})
+
+				t.Run("case=redirect to second factor if the login is risky", func(t *testing.T) {
+					conf.MustSet(ctx, config.ViperKeySessionWhoAmIAAL, "aal1")
+					conf.MustSet(ctx, config.ViperKeySecurityRiskBasedStepUpEnabled, true)
+					t.Cleanup(func() {
+						conf.MustSet(ctx, config.ViperKeySecurityRiskBasedStepUpEnabled, false)
+					})
+					t.Cleanup(testhelpers.SelfServiceHookConfigReset(t, conf))
+
+					// newRiskyIdentity creates an identity which only signed in from
+					// another device before.
+					newRiskyIdentity := func(t *testing.T) *identity.Identity {
+						i := &identity.Identity{Credentials: map[identity.CredentialsType]identity.Credentials{
+							identity.CredentialsTypePassword: {Type: identity.CredentialsTypePassword, Config: []byte(`{"hashed_password": "$argon2id$v=19$m=32,t=2,p=4$cm94YnRVOW5jZzFzcVE4bQ$MNzk5BtR2vUhrp6qQEjRNw"}`), Identifiers: []string{testhelpers.RandomEmail()}},
+							identity.CredentialsTypeWebAuthn: {Type: identity.CredentialsTypeWebAuthn, Config: []byte(`{"credentials":[{"is_passwordless":false}]}`), Identifiers: []string{testhelpers.RandomEmail()}},
+						}}
+						require.NoError(t, reg.Persister().CreateIdentity(context.Background(), i))
+
+						known, err := testhelpers.NewActiveSession(testhelpers.NewTestHTTPRequest(t, "GET", "/", nil), reg, i, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+						require.NoError(t, err)
+						known.Devices = []session.Device{{IPAddress: pointerx.Ptr("192.0.2.1"), UserAgent: pointerx.Ptr("known-agent"), Location: pointerx.Ptr("")}}
+						require.NoError(t, reg.SessionPersister().UpsertSession(ctx, known))
+						return i
+					}
+
+					t.Run("browser client", func(t *testing.T) {
+						res, body := makeRequestPost(t, newServer(t, flow.TypeBrowser, newRiskyIdentity(t)), false, url.Values{})
+						require.EqualValuesf(t, http.StatusNotFound, res.StatusCode, "%s", body)
+						assert.Contains(t, res.Request.URL.String(), "/self-service/login/browser?aal=aal2")
+					})
+
+					t.Run("api client returns the session with the risk but without the identity", func(t *testing.T) {
+						res, body := makeRequestPost(t, newServer(t, flow.TypeAPI, newRiskyIdentity(t)), true, url.Values{})
+						require.EqualValuesf(t, http.StatusOK, res.StatusCode, "%s", body)
+						assert.Empty(t, gjson.Get(body, "session.identity").String())
+						assert.EqualValues(t, 50, gjson.Get(body, "session.risk.score").Int(), "%s", body)
+						assert.True(t, gjson.Get(body, "session.risk.step_up_required").Bool(), "%s", body)
+					})
+				})
 			})
 
 			t.Run("case=maybe links credential", func(t *testing.T) {
@@ -441,6 +483,43 @@ func TestLoginExecutor(t *testing.T) {
 					assert.Len(t, ident.Credentials, 2)
 				})
 
+				t.Run("sub-case=notifies about the linked provider", func(t *testing.T) {
+					conf.MustSet(ctx, config.ViperKeySecurityNotificationsOIDCProviderLinked, true)
+					t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeySecurityNotificationsOIDCProviderLinked, false) })
+
+					email := testhelpers.RandomEmail()
+					id := identity.NewIdentity(testhelpers.UseIdentitySchema(t, conf, "file://./stub/recovery.schema.json"))
+					id.Traits = identity.Traits(`{"email":"` + email + `"}`)
+					id.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+						Type:        identity.CredentialsTypePassword,
+						Config:      []byte(`{"hashed_password": "$argon2id$v=19$m=32,t=2,p=4$cm94YnRVOW5jZzFzcVE4bQ$MNzk5BtR2vUhrp6qQEjRNw"}`),
+						Identifiers: []string{email},
+					})
+					require.NoError(t, reg.IdentityManager().Create(ctx, id))
+
+					creds, err := identity.NewCredentialsOIDC(
+						&identity.CredentialsOIDCEncryptedTokens{IDToken: "id-token", AccessToken: "access-token", RefreshToken: "refresh-token"},
+						"my-provider",
+						email,
+						"",
+					)
+					require.NoError(t, err)
+
+					res, body := makeRequestPost(t, newServer(t, flow.TypeBrowser, id, func(l *login.Flow) {
+						require.NoError(t, flow.SetDuplicateCredentials(l, flow.DuplicateCredentialsData{
+							CredentialsType:     identity.CredentialsTypeOIDC,
+							CredentialsConfig:   creds.Config,
+							DuplicateIdentifier: email,
+						}))
+					}), false, url.Values{})
+					require.Equalf(t, http.StatusOK, res.StatusCode, "%s", body)
+
+					msgs, _, err := reg.CourierPersister().ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: email}, nil)
+					require.NoError(t, err)
+					require.Len(t, msgs, 1)
+					assert.Equal(t, template.TypeOIDCProviderLinked, msgs[0].TemplateType)
+				})
+
 				t.Run("sub-case=errors on non-matching identity", func(t *testing.T) {
 					res, body := makeRequestPost(t, newServer(t, flow.TypeBrowser, passwordOnlyIdentity, func(l *login.Flow) {
 						require.NoError(t, flow.SetDuplicateCredentials(l, flow.DuplicateCredentialsData{
@@ -11,8 +11,6 @@ import (
 	"github.com/ory/kratos/x/redir"
 	"github.com/ory/x/httprouterx"
 
-	"go.opentelemetry.io/otel/trace"
-
 	"github.com/pkg/errors"
 
 	"github.com/ory/kratos/identity"
@@ -260,7 +258,7 @@ func (h *Handler) performNativeLogout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	trace.SpanFromContext(r.Context()).AddEvent(events.NewSessionRevoked(r.Context(), sess.ID, sess.IdentityID))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewSessionRevoked(r.Context(), sess.ID, sess.IdentityID))
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -348,7 +346,7 @@ func (h *Handler) updateLogoutFlow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	trace.SpanFromContext(r.Context()).AddEvent(events.NewSessionRevoked(r.Context(), sess.ID, sess.IdentityID))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewSessionRevoked(r.Context(), sess.ID, sess.IdentityID))
 
 	h.completeLogout(w, r)
 }
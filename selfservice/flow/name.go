@@ -11,6 +11,7 @@ package flow
 // - 'settings'
 // - 'recovery'
 // - 'verification'
+// - 'deletion'
 //
 // swagger:ignore
 type FlowName string
@@ -21,6 +22,7 @@ const (
 	SettingsFlow     FlowName = "settings"
 	RecoveryFlow     FlowName = "recovery"
 	VerificationFlow FlowName = "verification"
+	DeletionFlow     FlowName = "deletion"
 )
 
 func (t Type) String() string {
@@ -105,6 +105,10 @@ type Flow struct {
 	// the user.
 	DangerousSkipCSRFCheck bool `json:"-" faker:"-" db:"skip_csrf_check"`
 
+	// Locale is the preferred language of the browser which started the flow.
+	// It is used to localize messages if the identity has no locale.
+	Locale string `json:"-" faker:"-" db:"locale"`
+
 	// Contains possible actions that could follow this flow
 	ContinueWith []flow.ContinueWith `json:"continue_with,omitempty" faker:"-" db:"-"`
 
@@ -151,6 +155,7 @@ func NewFlow(conf *config.Config, exp time.Duration, csrf string, r *http.Reques
 		State:     state,
 		CSRFToken: csrf,
 		Type:      ft,
+		Locale:    x.PreferredLanguage(r.Header),
 	}
 
 	if strategy != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/captcha"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/session"
@@ -51,6 +52,7 @@ type (
 		config.Provider
 		ErrorHandlerProvider
 		HookExecutorProvider
+		captcha.VerificationProvider
 	}
 	Handler struct {
 		d handlerDependencies
@@ -130,6 +132,11 @@ func (h *Handler) createNativeRecoveryFlow(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if err := h.d.CaptchaVerifier().PopulateNodes(r, f); err != nil {
+		h.d.Writer().WriteError(w, r, err)
+		return
+	}
+
 	if err := h.d.RecoveryExecutor().PreRecoveryHook(w, r, f); err != nil {
 		h.d.Writer().WriteError(w, r, err)
 		return
@@ -195,6 +202,11 @@ func (h *Handler) createBrowserRecoveryFlow(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if err := h.d.CaptchaVerifier().PopulateNodes(r, f); err != nil {
+		h.d.SelfServiceErrorManager().Forward(r.Context(), w, r, err)
+		return
+	}
+
 	if err := h.d.RecoveryExecutor().PreRecoveryHook(w, r, f); err != nil {
 		h.d.Writer().WriteError(w, r, err)
 		return
@@ -414,6 +426,11 @@ func (h *Handler) updateRecoveryFlow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.d.CaptchaVerifier().Verify(r, f); err != nil {
+		h.d.RecoveryFlowErrorHandler().WriteFlowError(w, r, f, node.UiNodeGroup(f.Active.String()), err)
+		return
+	}
+
 	var g node.UiNodeGroup
 	var found bool
 	for _, ss := range h.d.AllRecoveryStrategies() {
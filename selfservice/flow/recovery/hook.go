@@ -10,8 +10,6 @@ import (
 
 	"github.com/ory/kratos/x/nosurfx"
 
-	"go.opentelemetry.io/otel/trace"
-
 	"github.com/ory/kratos/x/events"
 
 	"github.com/ory/kratos/driver/config"
@@ -111,7 +109,7 @@ func (e *HookExecutor) PostRecoveryHook(w http.ResponseWriter, r *http.Request,
 			Debug("ExecutePostRecoveryHook completed successfully.")
 	}
 
-	trace.SpanFromContext(r.Context()).AddEvent(events.NewRecoverySucceeded(r.Context(), a.ID, s.Identity.ID, string(a.Type), a.Active.String()))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewRecoverySucceeded(r.Context(), a.ID, s.Identity.ID, string(a.Type), a.Active.String()))
 
 	logger.Debug("Post recovery execution hooks completed successfully.")
 
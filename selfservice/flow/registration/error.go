@@ -16,6 +16,7 @@ import (
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
 	"github.com/ory/kratos/ui/node"
 	"github.com/ory/kratos/x/events"
@@ -49,6 +50,8 @@ type (
 		sessiontokenexchange.PersistenceProvider
 		FlowPersistenceProvider
 		HandlerProvider
+		login.HandlerProvider
+		login.FlowPersistenceProvider
 	}
 
 	ErrorHandlerProvider interface{ RegistrationFlowErrorHandler() *ErrorHandler }
@@ -96,7 +99,8 @@ func (s *ErrorHandler) WriteFlowError(
 	r = r.WithContext(ctx)
 	defer otelx.End(span, &err)
 
-	if dup := new(identity.ErrDuplicateCredentials); errors.As(err, &dup) {
+	var dup *identity.ErrDuplicateCredentials
+	if errors.As(err, &dup) {
 		err = schema.NewDuplicateCredentialsError(dup)
 	}
 
@@ -108,12 +112,21 @@ func (s *ErrorHandler) WriteFlowError(
 	logger.Info("Encountered self-service flow error.")
 
 	if f == nil {
-		trace.SpanFromContext(r.Context()).AddEvent(events.NewRegistrationFailed(r.Context(), uuid.Nil, "", "", err))
+		events.SpanFromContext(r.Context()).AddEvent(events.NewRegistrationFailed(r.Context(), uuid.Nil, "", "", err))
 		s.forward(w, r, nil, err)
 		return
 	}
 	span.SetAttributes(attribute.String("flow_id", f.ID.String()))
-	trace.SpanFromContext(r.Context()).AddEvent(events.NewRegistrationFailed(r.Context(), f.ID, string(f.Type), ct.String(), err))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewRegistrationFailed(r.Context(), f.ID, string(f.Type), ct.String(), err))
+
+	if dup != nil && s.d.Config().SelfServiceFlowRegistrationAccountLinking(ctx) {
+		if dc, _ := flow.DuplicateCredentials(f); dc != nil {
+			if err := s.writeAccountLinkingFlow(w, r, f, dc, dup); err != nil {
+				s.forward(w, r, f, err)
+			}
+			return
+		}
+	}
 
 	if expired, inner := s.PrepareReplacementForExpiredFlow(w, r, f, err); inner != nil {
 		s.forward(w, r, f, err)
@@ -153,7 +166,7 @@ func (s *ErrorHandler) WriteFlowError(
 		http.Redirect(w, r, f.AppendTo(s.d.Config().SelfServiceFlowRegistrationUI(r.Context())).String(), http.StatusFound)
 		return
 	}
-	if _, hasCode, _ := s.d.SessionTokenExchangePersister().CodeForFlow(r.Context(), f.ID); group == node.OpenIDConnectGroup && f.Type == flow.TypeAPI && hasCode {
+	if _, hasCode, _ := s.d.SessionTokenExchangePersister().CodeForFlow(r.Context(), f.ID); (group == node.OpenIDConnectGroup || group == node.SAMLGroup) && f.Type == flow.TypeAPI && hasCode {
 		http.Redirect(w, r, f.ReturnTo, http.StatusSeeOther)
 		return
 	}
@@ -179,6 +192,39 @@ func (s *ErrorHandler) WriteFlowError(
 	s.d.Writer().WriteCode(w, r, x.RecoverStatusCode(err, http.StatusBadRequest), updatedFlow)
 }
 
+// writeAccountLinkingFlow replaces the registration flow with a login flow in
+// which the user signs in to the conflicting identity. The credentials used in
+// the registration are stored in the login flow's internal context and linked
+// to the identity once the login succeeded.
+func (s *ErrorHandler) writeAccountLinkingFlow(w http.ResponseWriter, r *http.Request, f *Flow, dc *flow.DuplicateCredentialsData, dup *identity.ErrDuplicateCredentials) error {
+	ctx := r.Context()
+
+	opts := []login.FlowOption{login.WithInternalContext(f.InternalContext), login.WithIsAccountLinking()}
+	if len(f.ReturnTo) > 0 {
+		opts = append(opts, login.WithFlowReturnTo(f.ReturnTo))
+	}
+
+	lf, _, err := s.d.LoginHandler().NewLoginFlow(w, r, f.Type, opts...)
+	if err != nil {
+		return err
+	}
+
+	// Keep the request URL of the registration the user started with, as the
+	// current request is only an intermediate step.
+	lf.RequestURL = f.RequestURL
+	lf.TransientPayload = f.TransientPayload
+	lf.OrganizationID = f.OrganizationID
+	lf.IdentitySchema = f.IdentitySchema
+
+	login.PopulateAccountLinkingUI(ctx, s.d.Config(), lf, "", dc.CredentialsType.String(), dc.DuplicateIdentifier, dup.AvailableCredentials(), dup.AvailableOIDCProviders())
+	if err := s.d.LoginFlowPersister().UpdateLoginFlow(ctx, lf); err != nil {
+		return err
+	}
+
+	x.SendFlowErrorAsRedirectOrJSON(w, r, s.d.Writer(), lf, lf.AppendTo(s.d.Config().SelfServiceFlowLoginUI(ctx)).String())
+	return nil
+}
+
 func (s *ErrorHandler) forward(w http.ResponseWriter, r *http.Request, rr *Flow, err error) {
 	if rr == nil {
 		if x.IsJSONRequest(r) {
@@ -10,7 +10,6 @@ import (
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ory/herodot"
 	hydraclientgo "github.com/ory/hydra-client-go/v2"
@@ -18,6 +17,7 @@ import (
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/captcha"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
@@ -60,6 +60,7 @@ type (
 		ErrorHandlerProvider
 		sessiontokenexchange.PersistenceProvider
 		x.LoggingProvider
+		captcha.VerificationProvider
 	}
 	HandlerProvider interface {
 		RegistrationHandler() *Handler
@@ -158,6 +159,10 @@ func (h *Handler) NewRegistrationFlow(w http.ResponseWriter, r *http.Request, ft
 		}
 	}
 
+	if err := h.d.CaptchaVerifier().PopulateNodes(r, f); err != nil {
+		return nil, err
+	}
+
 	ds, err := f.IdentitySchema.URL(r.Context(), h.d.Config())
 	if err != nil {
 		return nil, err
@@ -175,8 +180,7 @@ func (h *Handler) NewRegistrationFlow(w http.ResponseWriter, r *http.Request, ft
 		return nil, err
 	}
 
-	span := trace.SpanFromContext(r.Context())
-	span.AddEvent(events.NewRegistrationInitiated(r.Context(), f.ID, string(ft), f.OrganizationID))
+	events.SpanFromContext(r.Context()).AddEvent(events.NewRegistrationInitiated(r.Context(), f.ID, string(ft), f.OrganizationID))
 
 	return f, nil
 }
@@ -683,6 +687,11 @@ func (h *Handler) updateRegistrationFlow(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := h.d.CaptchaVerifier().Verify(r, f); err != nil {
+		h.d.RegistrationFlowErrorHandler().WriteFlowError(w, r, f, "", node.CaptchaGroup, err)
+		return
+	}
+
 	i := identity.NewIdentity(f.IdentitySchema.ID(ctx, h.d.Config()))
 	var s Strategy
 	for _, ss := range h.d.AllRegistrationStrategies() {
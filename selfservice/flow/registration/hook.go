@@ -168,20 +168,27 @@ func (e *HookExecutor) PostRegistrationHook(w http.ResponseWriter, r *http.Reque
 				return err
 			}
 
-			if strategy, ok := strategy.(login.LinkableStrategy); ok {
-				duplicateIdentifier, err := e.getDuplicateIdentifier(ctx, i)
+			if linkable, ok := strategy.(login.LinkableStrategy); ok {
+				found, duplicateIdentifier, _, err := e.d.IdentityManager().ConflictingIdentity(ctx, i)
 				if err != nil {
 					return err
 				}
 
-				if err := strategy.SetDuplicateCredentials(
-					registrationFlow,
-					duplicateIdentifier,
-					i.Credentials[ct],
-					provider,
-				); err != nil {
+				allowed, err := e.linkingAllowed(ctx, strategy, ct, found)
+				if err != nil {
 					return err
 				}
+
+				if allowed {
+					if err := linkable.SetDuplicateCredentials(
+						registrationFlow,
+						duplicateIdentifier,
+						i.Credentials[ct],
+						provider,
+					); err != nil {
+						return err
+					}
+				}
 			}
 		}
 		return err
@@ -220,7 +227,7 @@ func (e *HookExecutor) PostRegistrationHook(w http.ResponseWriter, r *http.Reque
 		WithField("identity_id", i.ID).
 		Info("A new identity has registered using self-service registration.")
 
-	span.AddEvent(events.NewRegistrationSucceeded(ctx, registrationFlow.ID, i.ID, string(registrationFlow.Type), ct.String(), provider))
+	events.SpanFromContext(ctx).AddEvent(events.NewRegistrationSucceeded(ctx, registrationFlow.ID, i.ID, string(registrationFlow.Type), ct.String(), provider))
 
 	s := session.NewInactiveSession()
 
@@ -340,12 +347,39 @@ func (e *HookExecutor) PostRegistrationHook(w http.ResponseWriter, r *http.Reque
 	return nil
 }
 
-func (e *HookExecutor) getDuplicateIdentifier(ctx context.Context, i *identity.Identity) (string, error) {
-	_, id, _, err := e.d.IdentityManager().ConflictingIdentity(ctx, i)
+// linkingAllowed reports whether the credentials used in the registration can
+// be linked to the conflicting identity once the user signed in to it.
+//
+// Social sign in credentials, both OpenID Connect and SAML, hold one entry per
+// provider and can always be linked. Other credentials are only linked if
+// account linking is enabled. Code credentials are derived from the traits and
+// are never replaced. Passwords and passkeys are only linked if the identity
+// does not have active credentials of the same type yet, because they would
+// otherwise be replaced.
+func (e *HookExecutor) linkingAllowed(ctx context.Context, s login.Strategy, ct identity.CredentialsType, found *identity.Identity) (bool, error) {
+	if ct == identity.CredentialsTypeOIDC || ct == identity.CredentialsTypeSAML {
+		return true, nil
+	}
+
+	if found == nil || !e.d.Config().SelfServiceFlowRegistrationAccountLinking(ctx) {
+		return false, nil
+	}
+
+	if ct == identity.CredentialsTypeCodeAuth {
+		return true, nil
+	}
+
+	counter, ok := s.(identity.ActiveCredentialsCounter)
+	if !ok {
+		return false, nil
+	}
+
+	count, err := counter.CountActiveFirstFactorCredentials(ctx, found.Credentials)
 	if err != nil {
-		return "", err
+		return false, err
 	}
-	return id, nil
+
+	return count == 0, nil
 }
 
 func (e *HookExecutor) PreRegistrationHook(w http.ResponseWriter, r *http.Request, a *Flow) error {
@@ -87,6 +87,13 @@ func NewFlowNeedsReAuth() *FlowNeedsReAuth {
 	}
 }
 
+// NewImpersonatedSessionError is sent when an impersonated session attempts a
+// privileged settings update.
+func NewImpersonatedSessionError() *herodot.DefaultError {
+	return herodot.ErrForbidden.WithID(text.ErrIDSessionImpersonated).
+		WithReasonf("Impersonated sessions are not allowed to update these fields.")
+}
+
 func NewErrorHandler(d errorHandlerDependencies) *ErrorHandler {
 	return &ErrorHandler{d: d}
 }
@@ -186,11 +193,11 @@ func (s *ErrorHandler) WriteFlowError(
 	}
 
 	if f == nil {
-		trace.SpanFromContext(ctx).AddEvent(events.NewSettingsFailed(ctx, uuid.Nil, "", "", err))
+		events.SpanFromContext(ctx).AddEvent(events.NewSettingsFailed(ctx, uuid.Nil, "", "", err))
 		s.forward(ctx, w, r, nil, err)
 		return
 	}
-	trace.SpanFromContext(ctx).AddEvent(events.NewSettingsFailed(ctx, f.ID, string(f.Type), f.Active.String(), err))
+	events.SpanFromContext(ctx).AddEvent(events.NewSettingsFailed(ctx, f.ID, string(f.Type), f.Active.String(), err))
 
 	if expired, inner := s.PrepareReplacementForExpiredFlow(ctx, w, r, f, id, sess, err); inner != nil {
 		s.forward(ctx, w, r, f, err)
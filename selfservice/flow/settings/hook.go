@@ -7,14 +7,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/kratos/x/redir"
 
 	"github.com/ory/x/otelx"
-
-	"go.opentelemetry.io/otel/trace"
+	"github.com/ory/x/otelx/semconv"
 
 	"github.com/ory/kratos/x/events"
 
@@ -30,9 +28,11 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	otelattr "go.opentelemetry.io/otel/attribute"
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/notification"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/x"
 )
@@ -62,8 +62,10 @@ type (
 	executorDependencies interface {
 		identity.ManagementProvider
 		identity.ValidationProvider
+		identity.PrivilegedPoolProvider
 		session.ManagementProvider
 		config.Provider
+		notification.SecurityNotifierProvider
 
 		HandlerProvider
 		HooksProvider
@@ -219,15 +221,28 @@ func (e *HookExecutor) PostSettingsHook(ctx context.Context, w http.ResponseWrit
 	}
 
 	options := []identity.ManagerOption{identity.ManagerExposeValidationErrorsForInternalTypeAssertion}
-	ttl := e.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)
-	if ctxUpdate.Session.AuthenticatedAt.Add(ttl).After(time.Now()) {
+	privileged := EnsurePrivilegedSession(ctx, e.d.Config(), ctxUpdate.Session)
+	if privileged == nil {
 		options = append(options, identity.ManagerAllowWriteProtectedTraits)
 	}
 
+	var original *identity.Identity
+	if e.d.SecurityNotifier().CredentialsNotificationsEnabled(ctx) {
+		original, err = e.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctxUpdate.Session.IsImpersonated() {
+		// Attribute the change to the administrator impersonating the identity.
+		ctx = semconv.ContextWithAttributes(ctx, otelattr.String(events.AttributeKeyActor.String(), ctxUpdate.Session.Impersonation.ActorID))
+	}
+
 	if err := e.d.IdentityManager().Update(ctx, i, options...); err != nil {
-		if errors.Is(err, identity.ErrProtectedFieldModified) {
-			e.d.Logger().WithError(err).Debug("Modifying protected field requires re-authentication.")
-			return errors.WithStack(NewFlowNeedsReAuth())
+		if errors.Is(err, identity.ErrProtectedFieldModified) && privileged != nil {
+			e.d.Logger().WithError(err).Debug("Modifying protected field requires a privileged session.")
+			return privileged
 		}
 		if errors.Is(err, sqlcon.ErrUniqueViolation) {
 			return schema.NewDuplicateCredentialsError(err)
@@ -295,7 +310,11 @@ func (e *HookExecutor) PostSettingsHook(ctx context.Context, w http.ResponseWrit
 		WithField("flow_method", settingsType).
 		Debug("Completed all PostSettingsPrePersistHooks and PostSettingsPostPersistHooks.")
 
-	trace.SpanFromContext(ctx).AddEvent(events.NewSettingsSucceeded(
+	if original != nil {
+		e.d.SecurityNotifier().NotifyCredentialsChanged(ctx, r, original, i)
+	}
+
+	events.SpanFromContext(ctx).AddEvent(events.NewSettingsSucceeded(
 		ctx, ctxUpdate.Flow.ID, i.ID, string(ctxUpdate.Flow.Type), settingsType))
 
 	if ctxUpdate.Flow.Type == flow.TypeAPI {
@@ -4,6 +4,7 @@
 package settings
 
 import (
+	"context"
 	"net/http"
 	"runtime/debug"
 	"time"
@@ -54,6 +55,23 @@ func (c UpdateContext) GetSessionIdentity() *identity.Identity {
 	return c.Session.Identity
 }
 
+// EnsurePrivilegedSession returns an error if the session may not perform
+// privileged settings updates, either because it was authenticated too long
+// ago or because it was issued to impersonate the identity.
+func EnsurePrivilegedSession(ctx context.Context, c interface {
+	SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx context.Context) time.Duration
+}, s *session.Session) error {
+	if s.IsImpersonated() {
+		return errors.WithStack(NewImpersonatedSessionError())
+	}
+
+	if s.AuthenticatedAt.Add(c.SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
+		return errors.WithStack(NewFlowNeedsReAuth())
+	}
+
+	return nil
+}
+
 func PrepareUpdate(d interface {
 	x.LoggingProvider
 	continuity.ManagementProvider
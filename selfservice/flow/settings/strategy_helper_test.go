@@ -4,12 +4,19 @@
 package settings
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ory/herodot"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
 	"github.com/ory/kratos/x"
+	"github.com/ory/x/errorsx"
 )
 
 func TestGetIdentityToUpdate(t *testing.T) {
@@ -24,3 +31,27 @@ func TestGetIdentityToUpdate(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, actual)
 }
+
+type privilegedSessionMaxAge time.Duration
+
+func (d privilegedSessionMaxAge) SelfServiceFlowSettingsPrivilegedSessionMaxAge(context.Context) time.Duration {
+	return time.Duration(d)
+}
+
+func TestEnsurePrivilegedSession(t *testing.T) {
+	ctx := t.Context()
+	c := privilegedSessionMaxAge(time.Hour)
+
+	require.NoError(t, EnsurePrivilegedSession(ctx, c, &session.Session{AuthenticatedAt: time.Now()}))
+
+	err := EnsurePrivilegedSession(ctx, c, &session.Session{AuthenticatedAt: time.Now().Add(-2 * time.Hour)})
+	require.ErrorAs(t, err, new(*FlowNeedsReAuth))
+
+	err = EnsurePrivilegedSession(ctx, c, &session.Session{
+		AuthenticatedAt: time.Now(),
+		Impersonation:   &session.Impersonation{ActorID: "support-agent"},
+	})
+	require.Error(t, err)
+	assert.NotErrorAs(t, err, new(*FlowNeedsReAuth), "impersonated sessions must not be asked to re-authenticate")
+	assert.Equal(t, text.ErrIDSessionImpersonated, errorsx.Cause(err).(*herodot.DefaultError).ID())
+}
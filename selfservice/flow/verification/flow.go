@@ -97,6 +97,10 @@ type Flow struct {
 	//
 	// required: false
 	TransientPayload json.RawMessage `json:"transient_payload,omitempty" faker:"-" db:"-"`
+
+	// Locale is the preferred language of the browser which started the flow.
+	// It is used to localize messages if the identity has no locale.
+	Locale string `json:"-" faker:"-" db:"locale"`
 }
 
 type OAuth2LoginChallengeParams struct {
@@ -141,6 +145,7 @@ func NewFlow(conf *config.Config, exp time.Duration, csrf string, r *http.Reques
 		CSRFToken: csrf,
 		State:     flow.StateChooseMethod,
 		Type:      ft,
+		Locale:    x.PreferredLanguage(r.Header),
 	}
 
 	if strategy != nil {
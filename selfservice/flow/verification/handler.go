@@ -14,6 +14,7 @@ import (
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/captcha"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/session"
@@ -57,6 +58,7 @@ type (
 		ErrorHandlerProvider
 		StrategyProvider
 		HookExecutorProvider
+		captcha.VerificationProvider
 	}
 	Handler struct {
 		d handlerDependencies
@@ -108,6 +110,10 @@ func (h *Handler) NewVerificationFlow(w http.ResponseWriter, r *http.Request, ft
 		o(f)
 	}
 
+	if err := h.d.CaptchaVerifier().PopulateNodes(r, f); err != nil {
+		return nil, err
+	}
+
 	if err := h.d.VerificationExecutor().PreVerificationHook(w, r, f); err != nil {
 		return nil, err
 	}
@@ -423,6 +429,11 @@ func (h *Handler) updateVerificationFlow(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := h.d.CaptchaVerifier().Verify(r, f); err != nil {
+		h.d.VerificationFlowErrorHandler().WriteFlowError(w, r, f, node.UiNodeGroup(f.Active.String()), err)
+		return
+	}
+
 	var g node.UiNodeGroup
 	var found bool
 	for _, ss := range h.d.AllVerificationStrategies() {
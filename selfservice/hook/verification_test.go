@@ -115,7 +115,7 @@ func TestVerifier(t *testing.T) {
 						require.NotEmpty(t, expectedVerificationFlow.IdentityID.UUID)
 						require.NotNil(t, expectedVerificationFlow.UI.Nodes.Find("email"))
 
-						messages, err := reg.CourierPersister().NextMessages(context.Background(), 12)
+						messages, err := reg.CourierPersister().NextMessages(context.Background(), 12, "", time.Minute)
 						require.NoError(t, err)
 						if enabled {
 							require.Len(t, messages, 2)
@@ -162,7 +162,7 @@ func TestVerifier(t *testing.T) {
 		f := &login.Flow{RequestedAAL: "aal2"}
 		require.NoError(t, h.ExecuteLoginPostHook(httptest.NewRecorder(), u, node.CodeGroup, f, &session.Session{ID: x.NewUUID(), Identity: i}))
 
-		messages, err := reg.CourierPersister().NextMessages(context.Background(), 12)
+		messages, err := reg.CourierPersister().NextMessages(context.Background(), 12, "", time.Minute)
 		require.EqualError(t, err, "queue is empty")
 		require.Len(t, messages, 0)
 	})
@@ -215,7 +215,7 @@ func TestVerifier(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, expectedVerificationFlow.State, flow.StateEmailSent)
 
-		messages, err := reg.CourierPersister().NextMessages(context.Background(), 12)
+		messages, err := reg.CourierPersister().NextMessages(context.Background(), 12, "", time.Minute)
 		require.NoError(t, err)
 		require.Len(t, messages, 2)
 
@@ -245,7 +245,7 @@ func TestVerifier(t *testing.T) {
 		assert.Emptyf(t, originalFlow.ContinueWith(), "%+v", originalFlow.ContinueWith())
 
 		require.NoError(t, err)
-		messages, err = reg.CourierPersister().NextMessages(context.Background(), 12)
+		messages, err = reg.CourierPersister().NextMessages(context.Background(), 12, "", time.Minute)
 		require.EqualError(t, err, courier.ErrQueueEmpty.Error())
 		assert.Len(t, messages, 0)
 	})
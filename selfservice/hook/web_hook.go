@@ -29,6 +29,7 @@ import (
 	"github.com/ory/kratos/request"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/deletion"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -37,6 +38,7 @@ import (
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/text"
 	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/webhook"
 	"github.com/ory/kratos/x"
 	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/jsonnetsecure"
@@ -61,6 +63,9 @@ var _ interface {
 	settings.PreHookExecutor
 	settings.PostHookPrePersistExecutor
 	settings.PostHookPostPersistExecutor
+
+	deletion.PreHookExecutor
+	deletion.PostHookExecutor
 } = (*WebHook)(nil)
 
 var jsonnetCache, _ = ristretto.NewCache(&ristretto.Config[[]byte, []byte]{
@@ -78,6 +83,11 @@ type (
 		config.Provider
 	}
 
+	webHookOutboxDependencies interface {
+		webHookDependencies
+		webhook.OutboxProvider
+	}
+
 	templateContext struct {
 		Flow           flow.Flow          `json:"flow"`
 		RequestHeaders http.Header        `json:"request_headers"`
@@ -89,7 +99,7 @@ type (
 	}
 
 	WebHook struct {
-		deps webHookDependencies
+		deps webHookOutboxDependencies
 		conf *request.Config
 	}
 
@@ -120,7 +130,7 @@ func cookies(req *http.Request) map[string]string {
 	return cookies
 }
 
-func NewWebHook(r webHookDependencies, c *request.Config) *WebHook {
+func NewWebHook(r webHookOutboxDependencies, c *request.Config) *WebHook {
 	return &WebHook{deps: r, conf: c}
 }
 
@@ -297,6 +307,48 @@ func (e *WebHook) ExecuteSettingsPrePersistHook(_ http.ResponseWriter, req *http
 	})
 }
 
+func (e *WebHook) ExecuteDeletionPreHook(_ http.ResponseWriter, req *http.Request, flow *deletion.Flow, s *session.Session) error {
+	return otelx.WithSpan(req.Context(), "selfservice.hook.WebHook.ExecuteDeletionPreHook", func(ctx context.Context) error {
+		return e.execute(ctx, &templateContext{
+			Flow:           flow,
+			RequestHeaders: req.Header,
+			RequestMethod:  req.Method,
+			RequestURL:     x.RequestURL(req).String(),
+			RequestCookies: cookies(req),
+			Identity:       s.Identity,
+			Session:        s,
+		})
+	})
+}
+
+// ExecuteDeletionPostHook notifies the webhook about a deleted account. The
+// identity no longer exists at this point, so the webhook can neither
+// interrupt the flow nor modify the identity. Webhooks configured to do so
+// are called with their response ignored instead.
+func (e *WebHook) ExecuteDeletionPostHook(_ http.ResponseWriter, req *http.Request, flow *deletion.Flow, id *identity.Identity) error {
+	wh := e
+	if e.conf.CanInterrupt || e.conf.Response.Parse {
+		e.deps.Logger().
+			WithField("webhook_id", e.conf.ID).
+			Warn("A webhook in selfservice.flows.deletion.after is configured to interrupt the flow or parse the response, which is not possible after the account was deleted. The response of the webhook is ignored instead.")
+
+		conf := *e.conf
+		conf.CanInterrupt = false
+		conf.Response = request.ResponseConfig{Ignore: true}
+		wh = NewWebHook(e.deps, &conf)
+	}
+	return otelx.WithSpan(req.Context(), "selfservice.hook.WebHook.ExecuteDeletionPostHook", func(ctx context.Context) error {
+		return wh.execute(ctx, &templateContext{
+			Flow:           flow,
+			RequestHeaders: req.Header,
+			RequestMethod:  req.Method,
+			RequestURL:     x.RequestURL(req).String(),
+			RequestCookies: cookies(req),
+			Identity:       id,
+		})
+	})
+}
+
 func (e *WebHook) execute(ctx context.Context, data *templateContext) error {
 	var (
 		httpClient     = e.deps.HTTPClient(ctx)
@@ -313,6 +365,16 @@ func (e *WebHook) execute(ctx context.Context, data *templateContext) error {
 		return errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("A webhook is configured to ignore the response but also to parse the response. This is not possible."))
 	}
 
+	if ignoreResponse && e.deps.Config().WebhookOutbox(ctx).Enabled {
+		err := e.enqueue(ctx, tracer, data, triggerID)
+		if err == nil {
+			return nil
+		}
+		// The request is sent in the background without retries instead, so
+		// that it is not lost.
+		e.deps.Logger().WithError(err).Error("Unable to add the webhook request to the outbox, sending it without retries instead")
+	}
+
 	makeRequest := func() (finalErr error) {
 		if ignoreResponse {
 			// This means we want to run this closure asynchronously and not be
@@ -337,7 +399,7 @@ func (e *WebHook) execute(ctx context.Context, data *templateContext) error {
 			}).WithField("duration", time.Since(startTime))
 			if finalErr != nil {
 				if emitEvent && !errors.Is(finalErr, context.Canceled) {
-					span.AddEvent(events.NewWebhookFailed(ctx, finalErr, triggerID, webhookID))
+					events.SpanFromContext(ctx).AddEvent(events.NewWebhookFailed(ctx, finalErr, triggerID, webhookID))
 				}
 				if ignoreResponse {
 					logger.WithError(finalErr).Warning("Webhook request failed but the error was ignored because the configuration indicated that the upstream response should be ignored")
@@ -347,7 +409,7 @@ func (e *WebHook) execute(ctx context.Context, data *templateContext) error {
 			} else {
 				logger.Info("Webhook request succeeded")
 				if emitEvent {
-					span.AddEvent(events.NewWebhookSucceeded(ctx, triggerID, webhookID))
+					events.SpanFromContext(ctx).AddEvent(events.NewWebhookSucceeded(ctx, triggerID, webhookID))
 				}
 			}
 		}(time.Now())
@@ -437,6 +499,30 @@ func (e *WebHook) execute(ctx context.Context, data *templateContext) error {
 	return nil
 }
 
+// enqueue adds the request to the webhook outbox, which delivers it in the
+// background and retries it if it fails.
+func (e *WebHook) enqueue(ctx context.Context, tracer trace.Tracer, data *templateContext, triggerID uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "selfservice.webhook.enqueue")
+	defer otelx.End(span, &err)
+
+	builder, err := request.NewBuilder(ctx, e.conf, e.deps, request.WithCache(jsonnetCache), request.WithoutAuth())
+	if err != nil {
+		return err
+	}
+
+	data.RequestHeaders = RemoveDisallowedHeaders(data.RequestHeaders, e.deps.Config().WebhookHeaderAllowlist(ctx))
+
+	req, err := builder.BuildRequest(ctx, data)
+	if errors.Is(err, request.ErrCancel) {
+		span.SetAttributes(attribute.Bool("webhook.jsonnet.canceled", true))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return e.deps.WebhookOutbox().Enqueue(ctx, req, e.conf.Auth, e.conf.ID, triggerID)
+}
+
 // RemoveDisallowedHeaders removes all headers from httpHeaders that are not in
 // headerAllowlist.
 func RemoveDisallowedHeaders(httpHeaders http.Header, headerAllowlist []string) http.Header {
@@ -575,6 +661,6 @@ func InstrumentHTTPClientForEvents(ctx context.Context, httpClient *retryablehtt
 		// resBody = resBody[:min(len(resBody), 2<<10)] // truncate response body to 2 kB for event
 		// TODO(@alnr): redact sensitive data
 		resBody := []byte("<redacted>")
-		trace.SpanFromContext(ctx).AddEvent(events.NewWebhookDelivered(ctx, res.Request.URL, reqBody, res.StatusCode, resBody, attempt, requestID, triggerID, webhookID))
+		events.SpanFromContext(ctx).AddEvent(events.NewWebhookDelivered(ctx, res.Request.URL, reqBody, res.StatusCode, resBody, attempt, requestID, triggerID, webhookID))
 	}
 }
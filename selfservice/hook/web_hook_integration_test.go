@@ -16,10 +16,13 @@ import (
 	"net/url"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,12 +32,14 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
+	"github.com/ory/kratos/driver"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/internal"
 	"github.com/ory/kratos/request"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/deletion"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -44,6 +49,7 @@ import (
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/text"
 	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/webhook"
 	"github.com/ory/kratos/x"
 	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/jsonnetsecure"
@@ -91,10 +97,12 @@ func TestWebHooks(t *testing.T) {
 		x.SimpleLoggerWithClient
 		*jsonnetsecure.TestProvider
 		config.Provider
+		webhook.OutboxProvider
 	}{
 		x.SimpleLoggerWithClient{L: logger, C: reg.HTTPClient(ctx), T: otelx.NewNoop(logger, &otelx.Config{ServiceName: "kratos"})},
 		jsonnetsecure.NewTestProvider(t),
 		reg,
+		reg,
 	}
 	type WebHookRequest struct {
 		Body    string
@@ -1051,10 +1059,12 @@ func TestDisallowPrivateIPRanges(t *testing.T) {
 		x.SimpleLoggerWithClient
 		*jsonnetsecure.TestProvider
 		config.Provider
+		webhook.OutboxProvider
 	}{
 		x.SimpleLoggerWithClient{L: logger, C: reg.HTTPClient(context.Background()), T: otelx.NewNoop(logger, &otelx.Config{ServiceName: "kratos"})},
 		jsonnetsecure.NewTestProvider(t),
 		reg,
+		reg,
 	}
 
 	req := &http.Request{
@@ -1121,10 +1131,12 @@ func TestAsyncWebhook(t *testing.T) {
 		x.SimpleLoggerWithClient
 		*jsonnetsecure.TestProvider
 		config.Provider
+		webhook.OutboxProvider
 	}{
 		x.SimpleLoggerWithClient{L: logger, C: reg.HTTPClient(context.Background()), T: otelx.NewNoop(logger, &otelx.Config{ServiceName: "kratos"})},
 		jsonnetsecure.NewTestProvider(t),
 		reg,
+		reg,
 	}
 
 	req := &http.Request{
@@ -1194,6 +1206,197 @@ func TestAsyncWebhook(t *testing.T) {
 	require.True(t, found)
 }
 
+func TestWebhookOutbox(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t)
+	conf.MustSet(ctx, config.ViperKeyWebhookOutboxEnabled, true)
+	logger := logrusx.New("kratos", "test")
+	whDeps := struct {
+		x.SimpleLoggerWithClient
+		*jsonnetsecure.TestProvider
+		config.Provider
+		webhook.OutboxProvider
+	}{
+		x.SimpleLoggerWithClient{L: logger, C: reg.HTTPClient(ctx), T: otelx.NewNoop(logger, &otelx.Config{ServiceName: "kratos"})},
+		jsonnetsecure.NewTestProvider(t),
+		reg,
+		reg,
+	}
+
+	var received []string
+	webhookReceiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+	}))
+	t.Cleanup(webhookReceiver.Close)
+
+	req := &http.Request{
+		Header: map[string][]string{"Some-Header": {"Some-Value"}},
+		Host:   "www.ory.sh",
+		TLS:    new(tls.ConnectionState),
+		URL:    &url.URL{Path: "/some_end_point"},
+		Method: http.MethodPost,
+	}
+	f := &login.Flow{ID: x.NewUUID()}
+	s := &session.Session{ID: x.NewUUID(), Identity: &identity.Identity{ID: x.NewUUID()}}
+
+	t.Run("case=queues webhooks which ignore the response", func(t *testing.T) {
+		webhookID := x.NewUUID().String()
+		wh := hook.NewWebHook(&whDeps, &request.Config{
+			ID:          webhookID,
+			URL:         webhookReceiver.URL,
+			Method:      "POST",
+			TemplateURI: "file://stub/test_body.jsonnet",
+			Response: request.ResponseConfig{
+				Ignore: true,
+			},
+		})
+		require.NoError(t, wh.ExecuteLoginPostHook(nil, req, node.DefaultGroup, f, s))
+
+		deliveries, _, err := reg.WebhookPersister().ListWebhookDeliveries(ctx, webhook.ListDeliveriesParameters{WebhookID: webhookID}, nil)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, webhook.DeliveryStatusQueued, deliveries[0].Status)
+		assert.Equal(t, s.Identity.ID.String(), gjson.Get(deliveries[0].Body, "identity_id").String())
+		assert.Empty(t, received)
+
+		require.NoError(t, reg.WebhookOutbox().DispatchQueue(ctx))
+		require.Len(t, received, 1)
+		assert.JSONEq(t, deliveries[0].Body, received[0])
+	})
+
+	t.Run("case=sends webhooks which can not be queued", func(t *testing.T) {
+		received := make(chan string, 1)
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- string(body)
+		}))
+		t.Cleanup(receiver.Close)
+
+		failingDeps := whDeps
+		failingDeps.OutboxProvider = failingOutboxRegistry{RegistryDefault: reg}
+
+		webhookID := x.NewUUID().String()
+		wh := hook.NewWebHook(&failingDeps, &request.Config{
+			ID:          webhookID,
+			URL:         receiver.URL,
+			Method:      "POST",
+			TemplateURI: "file://stub/test_body.jsonnet",
+			Response: request.ResponseConfig{
+				Ignore: true,
+			},
+		})
+		require.NoError(t, wh.ExecuteLoginPostHook(nil, req, node.DefaultGroup, f, s))
+
+		select {
+		case body := <-received:
+			assert.Equal(t, s.Identity.ID.String(), gjson.Get(body, "identity_id").String(), "%s", body)
+		case <-time.After(10 * time.Second):
+			t.Fatal("the webhook was not sent")
+		}
+
+		deliveries, _, err := reg.WebhookPersister().ListWebhookDeliveries(ctx, webhook.ListDeliveriesParameters{WebhookID: webhookID}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+
+	t.Run("case=does not queue webhooks which parse the response", func(t *testing.T) {
+		webhookID := x.NewUUID().String()
+		wh := hook.NewWebHook(&whDeps, &request.Config{
+			ID:          webhookID,
+			URL:         webhookReceiver.URL,
+			Method:      "POST",
+			TemplateURI: "file://stub/test_body.jsonnet",
+		})
+		require.NoError(t, wh.ExecuteLoginPostHook(nil, req, node.DefaultGroup, f, s))
+
+		deliveries, _, err := reg.WebhookPersister().ListWebhookDeliveries(ctx, webhook.ListDeliveriesParameters{WebhookID: webhookID}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+}
+
+func TestWebhookDeletionPostHook(t *testing.T) {
+	t.Parallel()
+	_, reg := internal.NewFastRegistryWithMocks(t)
+	logger := logrusx.New("kratos", "test")
+	logHook := new(test.Hook)
+	logger.Logger.Hooks.Add(logHook)
+	whDeps := struct {
+		x.SimpleLoggerWithClient
+		*jsonnetsecure.TestProvider
+		config.Provider
+		webhook.OutboxProvider
+	}{
+		x.SimpleLoggerWithClient{L: logger, C: reg.HTTPClient(context.Background()), T: otelx.NewNoop(logger, &otelx.Config{ServiceName: "kratos"})},
+		jsonnetsecure.NewTestProvider(t),
+		reg,
+		reg,
+	}
+
+	received := make(chan string, 1)
+	webhookReceiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"messages":[{"instance_ptr":"#/traits/email","messages":[{"id":123,"text":"interrupted","type":"error"}]}]}`))
+	}))
+	t.Cleanup(webhookReceiver.Close)
+
+	req := &http.Request{
+		Header: map[string][]string{"Some-Header": {"Some-Value"}},
+		Host:   "www.ory.sh",
+		TLS:    new(tls.ConnectionState),
+		URL:    &url.URL{Path: "/some_end_point"},
+		Method: http.MethodPost,
+	}
+	f := &deletion.Flow{ID: x.NewUUID()}
+	i := &identity.Identity{ID: x.NewUUID()}
+
+	t.Run("case=ignores the response of webhooks which can interrupt", func(t *testing.T) {
+		wh := hook.NewWebHook(&whDeps, &request.Config{
+			URL:          webhookReceiver.URL,
+			Method:       "POST",
+			TemplateURI:  "file://stub/test_body.jsonnet",
+			CanInterrupt: true,
+		})
+		require.NoError(t, wh.ExecuteDeletionPostHook(nil, req, f, i))
+
+		select {
+		case body := <-received:
+			assert.Equal(t, i.ID.String(), gjson.Get(body, "identity_id").String(), "%s", body)
+		case <-time.After(10 * time.Second):
+			t.Fatal("the webhook was not called")
+		}
+
+		assert.True(t, slices.ContainsFunc(logHook.AllEntries(), func(e *logrus.Entry) bool {
+			return strings.Contains(e.Message, "The response of the webhook is ignored instead.")
+		}))
+	})
+}
+
+// failingOutboxRegistry is a registry whose webhook outbox can not add deliveries.
+type failingOutboxRegistry struct {
+	*driver.RegistryDefault
+}
+
+type failingWebhookPersister struct {
+	webhook.Persister
+}
+
+func (failingWebhookPersister) AddWebhookDelivery(context.Context, *webhook.Delivery) error {
+	return errors.New("the database is unavailable")
+}
+
+func (r failingOutboxRegistry) WebhookPersister() webhook.Persister {
+	return failingWebhookPersister{Persister: r.RegistryDefault.WebhookPersister()}
+}
+
+func (r failingOutboxRegistry) WebhookOutbox() *webhook.Outbox {
+	return webhook.NewOutbox(r)
+}
+
 func TestWebhookEvents(t *testing.T) {
 	t.Parallel()
 	_, reg := internal.NewFastRegistryWithMocks(t)
@@ -1202,10 +1405,12 @@ func TestWebhookEvents(t *testing.T) {
 		x.SimpleLoggerWithClient
 		*jsonnetsecure.TestProvider
 		config.Provider
+		webhook.OutboxProvider
 	}{
 		x.SimpleLoggerWithClient{L: logger, C: reg.HTTPClient(context.Background()), T: otelx.NewNoop(logger, &otelx.Config{ServiceName: "kratos"})},
 		jsonnetsecure.NewTestProvider(t),
 		reg,
+		reg,
 	}
 
 	req := &http.Request{
@@ -0,0 +1,67 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package code
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/session"
+)
+
+var _ login.LinkableStrategy = new(Strategy)
+
+// Link accepts the addresses verified in a registration which collided with
+// the identity the user signed in to afterwards.
+//
+// Code credentials are derived from the identity's traits, so nothing is
+// stored. At least one of the addresses must already be a code address of the
+// identity, otherwise the user could not sign in with it.
+func (s *Strategy) Link(ctx context.Context, i *identity.Identity, credentialsConfig sqlxx.JSONRawMessage) (err error) {
+	_, span := s.deps.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.code.Strategy.Link")
+	defer otelx.End(span, &err)
+
+	var conf identity.CredentialsCode
+	if err := json.Unmarshal(credentialsConfig, &conf); err != nil {
+		return errors.WithStack(err)
+	} else if len(conf.Addresses) == 0 {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReason("The linked code credentials do not contain an address."))
+	}
+
+	var existing identity.CredentialsCode
+	if _, err := i.ParseCredentials(s.ID(), &existing); err != nil && !errors.Is(err, herodot.ErrNotFound) {
+		return err
+	}
+
+	for _, a := range conf.Addresses {
+		if slices.Contains(existing.Addresses, a) {
+			return nil
+		}
+	}
+
+	return errors.WithStack(herodot.ErrConflict.WithReason("The identity can not sign in with a code sent to the verified address."))
+}
+
+func (s *Strategy) CompletedLogin(sess *session.Session, _ *flow.DuplicateCredentialsData) error {
+	sess.CompletedLoginFor(s.ID(), identity.AuthenticatorAssuranceLevel1)
+	return nil
+}
+
+func (s *Strategy) SetDuplicateCredentials(f flow.InternalContexter, duplicateIdentifier string, credentials identity.Credentials, _ string) error {
+	return flow.SetDuplicateCredentials(f, flow.DuplicateCredentialsData{
+		CredentialsType:     s.ID(),
+		CredentialsConfig:   credentials.Config,
+		DuplicateIdentifier: duplicateIdentifier,
+	})
+}
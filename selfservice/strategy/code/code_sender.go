@@ -14,6 +14,7 @@ import (
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/courier/template/email"
 	"github.com/ory/kratos/courier/template/sms"
 	"github.com/ory/kratos/driver/config"
@@ -74,6 +75,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	locale := s.locale(ctx, id, x.PreferredLanguage(header))
 
 	// send to all addresses
 	for _, address := range addresses {
@@ -117,6 +119,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 					Traits:             model,
 					RequestURL:         f.GetRequestURL(),
 					TransientPayload:   transientPayload,
+					Locale:             locale,
 					ExpiresInMinutes:   int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 					UserRequestHeaders: hook.RemoveDisallowedHeaders(header, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 				})
@@ -127,6 +130,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 					Identity:           model,
 					RequestURL:         f.GetRequestURL(),
 					TransientPayload:   transientPayload,
+					Locale:             locale,
 					ExpiresInMinutes:   int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 					UserRequestHeaders: hook.RemoveDisallowedHeaders(header, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 				})
@@ -170,6 +174,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 					Identity:           model,
 					RequestURL:         f.GetRequestURL(),
 					TransientPayload:   transientPayload,
+					Locale:             locale,
 					ExpiresInMinutes:   int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 					UserRequestHeaders: hook.RemoveDisallowedHeaders(header, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 				})
@@ -180,6 +185,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 					Identity:           model,
 					RequestURL:         f.GetRequestURL(),
 					TransientPayload:   transientPayload,
+					Locale:             locale,
 					ExpiresInMinutes:   int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 					UserRequestHeaders: hook.RemoveDisallowedHeaders(header, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 				})
@@ -223,6 +229,7 @@ func (s *Sender) SendRecoveryCode(ctx context.Context, f *recovery.Flow, via, to
 			return errors.WithStack(err)
 		}
 
+		locale := s.locale(ctx, nil, x.Coalesce(f.Locale, x.PreferredLanguage(requestHeader)))
 		// We only send a notification if the configuration allows it *and* the channel is email.
 		// That's because we pay per SMS sent (typically) so we want to avoid that, contrary to email.
 		shouldNotifyOfUnkownRecipient := notifyUnknownRecipients && via == identity.AddressTypeEmail
@@ -233,6 +240,7 @@ func (s *Sender) SendRecoveryCode(ctx context.Context, f *recovery.Flow, via, to
 			To:               to,
 			RequestURL:       f.RequestURL,
 			TransientPayload: transientPayload,
+			Locale:           locale,
 		})); err != nil {
 			return err
 		}
@@ -286,6 +294,7 @@ func (s *Sender) SendRecoveryCodeTo(ctx context.Context, i *identity.Identity, c
 		return errors.WithStack(err)
 	}
 
+	locale := s.locale(ctx, i, x.Coalesce(f.Locale, x.PreferredLanguage(requestHeader)))
 	var t courier.Template
 
 	switch code.RecoveryAddress.Via {
@@ -296,6 +305,7 @@ func (s *Sender) SendRecoveryCodeTo(ctx context.Context, i *identity.Identity, c
 			Identity:           model,
 			RequestURL:         f.GetRequestURL(),
 			TransientPayload:   transientPayload,
+			Locale:             locale,
 			ExpiresInMinutes:   int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 			UserRequestHeaders: hook.RemoveDisallowedHeaders(requestHeader, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 		})
@@ -312,6 +322,7 @@ func (s *Sender) SendRecoveryCodeTo(ctx context.Context, i *identity.Identity, c
 			RequestURL:         f.GetRequestURL(),
 			RequestURLDomain:   u.Hostname(),
 			TransientPayload:   transientPayload,
+			Locale:             locale,
 			ExpiresInMinutes:   int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 			UserRequestHeaders: hook.RemoveDisallowedHeaders(requestHeader, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 		})
@@ -347,12 +358,14 @@ func (s *Sender) SendVerificationCode(ctx context.Context, f *verification.Flow,
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		locale := s.locale(ctx, nil, f.Locale)
 		if !notifyUnknownRecipients {
 			// do nothing
 		} else if err := s.send(ctx, via, email.NewVerificationCodeInvalid(s.deps, &email.VerificationCodeInvalidModel{
 			To:               to,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 		})); err != nil {
 			return err
 		}
@@ -410,6 +423,7 @@ func (s *Sender) SendVerificationCodeTo(ctx context.Context, f *verification.Flo
 		return errors.WithStack(err)
 	}
 
+	locale := s.locale(ctx, i, f.Locale)
 	var t courier.Template
 
 	// TODO: this can likely be abstracted by making templates not specific to the channel they're using
@@ -422,6 +436,7 @@ func (s *Sender) SendVerificationCodeTo(ctx context.Context, f *verification.Flo
 			VerificationCode: codeString,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 			ExpiresInMinutes: int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 		})
 	case identity.ChannelTypeSMS:
@@ -431,6 +446,7 @@ func (s *Sender) SendVerificationCodeTo(ctx context.Context, f *verification.Flo
 			Identity:         model,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 			ExpiresInMinutes: int(s.deps.Config().SelfServiceCodeMethodLifespan(ctx).Minutes()),
 		})
 	default:
@@ -444,6 +460,15 @@ func (s *Sender) SendVerificationCodeTo(ctx context.Context, f *verification.Flo
 	return s.deps.PrivilegedIdentityPool().UpdateVerifiableAddress(ctx, code.VerifiableAddress, "status")
 }
 
+// locale returns the locale in which messages to the identity are rendered. The
+// identity may be nil if the recipient is unknown.
+func (s *Sender) locale(ctx context.Context, i *identity.Identity, fallback string) string {
+	if i == nil {
+		return template.ResolveLocale(ctx, s.deps, nil, nil, fallback)
+	}
+	return template.ResolveLocale(ctx, s.deps, i.Traits, i.MetadataPublic, fallback)
+}
+
 func (s *Sender) send(ctx context.Context, via string, t courier.Template) error {
 	switch f := stringsx.SwitchExact(via); {
 	case f.AddCase(identity.ChannelTypeEmail):
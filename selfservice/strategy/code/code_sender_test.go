@@ -81,7 +81,7 @@ func TestSender(t *testing.T) {
 
 		t.Run("case=with default templates", func(t *testing.T) {
 			recoveryCode(t)
-			messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+			messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 			require.NoError(t, err)
 			require.Len(t, messages, 2)
 
@@ -111,7 +111,7 @@ func TestSender(t *testing.T) {
 			conf.MustSet(ctx, config.ViperKeyCourierTemplatesRecoveryCodeInvalidEmail, fmt.Sprintf(`{ "subject": "base64://%s", "body": { "plaintext": "base64://%s", "html": "base64://%s" }}`, b64(subject+" invalid"), b64(body), b64(body)))
 			conf.MustSet(ctx, config.ViperKeyCourierTemplatesRecoveryCodeValidEmail, fmt.Sprintf(`{ "subject": "base64://%s", "body": { "plaintext": "base64://%s", "html": "base64://%s" }}`, b64(subject+" valid"), b64(body+" {{ .RecoveryCode }}"), b64(body+" {{ .RecoveryCode }}")))
 			recoveryCode(t)
-			messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+			messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 			require.NoError(t, err)
 			require.Len(t, messages, 2)
 
@@ -151,7 +151,7 @@ func TestSender(t *testing.T) {
 
 		t.Run("case=with default templates", func(t *testing.T) {
 			recoveryCode(t)
-			messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+			messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 			require.NoError(t, err)
 			require.Len(t, messages, 1)
 
@@ -169,7 +169,7 @@ func TestSender(t *testing.T) {
 			})
 			conf.MustSet(ctx, config.ViperKeyCourierTemplatesRecoveryCodeValidSMS, fmt.Sprintf(`{ "body": { "plaintext": "base64://%s"}}`, b64(body+" {{ .RecoveryCode }}")))
 			recoveryCode(t)
-			messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+			messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 			require.NoError(t, err)
 			require.Len(t, messages, 1)
 
@@ -199,7 +199,7 @@ func TestSender(t *testing.T) {
 
 		t.Run("case=with default templates", func(t *testing.T) {
 			verificationFlow(t)
-			messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+			messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 			require.NoError(t, err)
 			require.Len(t, messages, 2)
 
@@ -224,7 +224,7 @@ func TestSender(t *testing.T) {
 			conf.MustSet(ctx, config.ViperKeyCourierTemplatesVerificationCodeInvalidEmail, fmt.Sprintf(`{ "subject": "base64://%s", "body": { "plaintext": "base64://%s", "html": "base64://%s" }}`, b64(subject+" invalid"), b64(body), b64(body)))
 			conf.MustSet(ctx, config.ViperKeyCourierTemplatesVerificationCodeValidEmail, fmt.Sprintf(`{ "subject": "base64://%s", "body": { "plaintext": "base64://%s", "html": "base64://%s" }}`, b64(subject+" valid"), b64(body+" {{ .VerificationCode }}"), b64(body+" {{ .VerificationCode }}")))
 			verificationFlow(t)
-			messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+			messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 			require.NoError(t, err)
 			require.Len(t, messages, 2)
 
@@ -286,7 +286,7 @@ func TestSender(t *testing.T) {
 
 				tc.send(t)
 
-				messages, err := reg.CourierPersister().NextMessages(ctx, 0)
+				messages, err := reg.CourierPersister().NextMessages(ctx, 0, "", time.Minute)
 
 				require.ErrorIs(t, err, courier.ErrQueueEmpty)
 				require.Len(t, messages, 0)
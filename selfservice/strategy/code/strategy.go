@@ -71,6 +71,7 @@ type (
 
 		identity.ValidationProvider
 		identity.ManagementProvider
+		identity.LoginLockoutManagementProvider
 		identity.PoolProvider
 		identity.PrivilegedPoolProvider
 
@@ -239,7 +239,7 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		}
 		return nil, nil
 	case flow.StateEmailSent:
-		i, err := s.loginVerifyCode(ctx, f, &p, sess)
+		i, err := s.loginVerifyCode(ctx, r, f, &p, sess)
 		if err != nil {
 			return nil, s.HandleLoginError(r, f, &p, err, true)
 		}
@@ -536,7 +536,7 @@ func maybeNormalizeEmail(input string) string {
 	return input
 }
 
-func (s *Strategy) loginVerifyCode(ctx context.Context, f *login.Flow, p *updateLoginFlowWithCodeMethod, sess *session.Session) (_ *identity.Identity, err error) {
+func (s *Strategy) loginVerifyCode(ctx context.Context, r *http.Request, f *login.Flow, p *updateLoginFlowWithCodeMethod, sess *session.Session) (_ *identity.Identity, err error) {
 	ctx, span := s.deps.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.code.Strategy.loginVerifyCode")
 	defer otelx.End(span, &err)
 
@@ -563,14 +563,25 @@ func (s *Strategy) loginVerifyCode(ctx context.Context, f *login.Flow, p *update
 		return nil, err
 	}
 
+	// Failed attempts are counted per address, or per identity if the flow
+	// does not carry an address.
+	lockoutIdentifier := cmp.Or(p.Identifier, i.ID.String())
+	if err := s.deps.LoginLockoutManager().Check(r, s.ID(), lockoutIdentifier); err != nil {
+		return nil, x.WrapWithIdentityIDError(err, i.ID)
+	}
+
 	loginCode, err := s.deps.LoginCodePersister().UseLoginCode(ctx, f.ID, i.ID, p.Code)
 	if err != nil {
 		if errors.Is(err, ErrCodeNotFound) {
-			return nil, schema.NewLoginCodeInvalid()
+			return nil, x.WrapWithIdentityIDError(s.deps.LoginLockoutManager().RecordFailure(r, s.ID(), lockoutIdentifier, i.ID, schema.NewLoginCodeInvalid()), i.ID)
 		}
 		return nil, errors.WithStack(err)
 	}
 
+	if err := s.deps.LoginLockoutManager().RecordSuccess(r, s.ID(), lockoutIdentifier); err != nil {
+		return nil, err
+	}
+
 	i, err = s.deps.PrivilegedIdentityPool().GetIdentity(ctx, loginCode.IdentityID, identity.ExpandDefault)
 	if err != nil {
 		return nil, errors.WithStack(err)
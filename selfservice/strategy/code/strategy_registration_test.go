@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
 
+	"github.com/ory/herodot"
 	"github.com/ory/x/configx"
 
 	"github.com/ory/kratos/driver"
@@ -31,8 +32,10 @@ import (
 	oryClient "github.com/ory/kratos/internal/httpclient"
 	"github.com/ory/kratos/internal/testhelpers"
 	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/registration"
 	"github.com/ory/kratos/selfservice/strategy/code"
+	"github.com/ory/kratos/text"
 	"github.com/ory/kratos/ui/node"
 	"github.com/ory/pop/v6"
 	"github.com/ory/x/assertx"
@@ -645,6 +648,58 @@ func TestRegistrationCodeStrategy(t *testing.T) {
 		}
 	})
 
+	t.Run("test=account linking", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		_, reg, public := setup(ctx, t, configx.WithValue(config.ViperKeySelfServiceRegistrationAccountLinking, true))
+
+		t.Run("case=should offer to link the code to an existing identity", func(t *testing.T) {
+			email := testhelpers.RandomEmail()
+			existing := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+			existing.Traits = identity.Traits(`{"email":"` + email + `"}`)
+			require.NoError(t, existing.SetCredentialsWithConfig(identity.CredentialsTypePassword, identity.Credentials{Identifiers: []string{email}}, identity.CredentialsPassword{HashedPassword: "$2a$04$zvZz1zV"}))
+			require.NoError(t, reg.IdentityManager().Create(ctx, existing))
+
+			s := createRegistrationFlow(ctx, t, public, ApiTypeNative)
+			s.email = email
+			s = registerNewUser(ctx, t, s, ApiTypeNative, nil)
+
+			message := testhelpers.CourierExpectMessage(ctx, t, reg, email, "Use code")
+			registrationCode := testhelpers.CourierExpectCodeInMessage(t, message, 1)
+
+			s = submitOTP(ctx, t, reg, s, func(v *url.Values) {
+				v.Set("code", registrationCode)
+			}, ApiTypeNative, func(_ context.Context, t *testing.T, _ *state, body string, res *http.Response) {
+				require.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+				assert.EqualValues(t, text.InfoSelfServiceLoginLink, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+				assert.Contains(t, gjson.Get(body, "ui.action").String(), login.RouteSubmitFlow, "%s", body)
+			})
+
+			lf, err := reg.LoginFlowPersister().GetLoginFlow(ctx, uuid.Must(uuid.FromString(gjson.Get(s.body, "id").String())))
+			require.NoError(t, err)
+			dc, err := flow.DuplicateCredentials(lf)
+			require.NoError(t, err)
+			require.NotNil(t, dc)
+			assert.Equal(t, identity.CredentialsTypeCodeAuth, dc.CredentialsType)
+			assert.Equal(t, email, dc.DuplicateIdentifier)
+
+			strategy, err := reg.AllLoginStrategies().Strategy(identity.CredentialsTypeCodeAuth)
+			require.NoError(t, err)
+			i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, existing.ID)
+			require.NoError(t, err)
+			assert.NoError(t, strategy.(login.LinkableStrategy).Link(ctx, i, dc.CredentialsConfig))
+
+			// The address of another identity can not be linked.
+			other := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+			other.Traits = identity.Traits(`{"email":"` + testhelpers.RandomEmail() + `"}`)
+			require.NoError(t, reg.IdentityManager().Create(ctx, other))
+			other, err = reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, other.ID)
+			require.NoError(t, err)
+			assert.ErrorIs(t, strategy.(login.LinkableStrategy).Link(ctx, other, dc.CredentialsConfig), herodot.ErrConflict)
+		})
+	})
+
 	t.Run("test=multi-schema select", func(t *testing.T) {
 		t.Parallel()
 
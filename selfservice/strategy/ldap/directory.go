@@ -0,0 +1,164 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+)
+
+// errInvalidCredentials is returned if the user's entry could not be found or
+// the directory rejected the password. Both cases are reported to the user in
+// the same way to prevent account enumeration.
+var errInvalidCredentials = errors.New("the directory rejected the credentials")
+
+// entry is the user's directory entry.
+type entry struct {
+	// DN is the distinguished name of the entry.
+	DN string `json:"dn"`
+
+	// ID is the value of the configured unique ID attribute.
+	ID string `json:"-"`
+
+	// Attributes contains the attribute values of the entry. Values which are not
+	// valid UTF-8, such as Active Directory's objectGUID, are hex encoded.
+	Attributes map[string][]string `json:"attributes"`
+}
+
+func (c *Configuration) filter(username string) string {
+	filter := c.UserFilter
+	if filter == "" {
+		filter = "(uid={username})"
+	}
+	return strings.ReplaceAll(filter, "{username}", goldap.EscapeFilter(username))
+}
+
+func (c *Configuration) attributes() []string {
+	if len(c.Attributes) == 0 {
+		return nil
+	}
+	if c.IDAttribute != "" && !slices.Contains(c.Attributes, c.IDAttribute) {
+		return append(slices.Clone(c.Attributes), c.IDAttribute)
+	}
+	return c.Attributes
+}
+
+func (c *Configuration) dial() (*goldap.Conn, error) {
+	tlsConfig := &tls.Config{
+		// #nosec G402 -- explicitly opted in to by the operator
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	conn, err := goldap.DialURL(c.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: c.timeout()}),
+		goldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrUpstreamError.WithReason("Unable to connect to the LDAP server.").WithDebug(err.Error()))
+	}
+	conn.SetTimeout(c.timeout())
+
+	if c.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, errors.WithStack(herodot.ErrUpstreamError.WithReason("Unable to establish a TLS connection to the LDAP server.").WithDebug(err.Error()))
+		}
+	}
+
+	return conn, nil
+}
+
+// authenticate looks up the user's entry and verifies the password by binding
+// as that entry.
+func (s *Strategy) authenticate(ctx context.Context, c *Configuration, username, password string) (_ *entry, err error) {
+	_, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.ldap.Strategy.authenticate")
+	defer otelx.End(span, &err)
+
+	// Most directories treat a simple bind with an empty password as an
+	// unauthenticated bind, which succeeds for any DN.
+	if username == "" || password == "" {
+		return nil, errors.WithStack(errInvalidCredentials)
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if c.BindDN != "" {
+		if err := conn.Bind(c.BindDN, c.BindPassword); err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReason("Unable to bind to the LDAP server using the configured service account.").WithDebug(err.Error()))
+		}
+	}
+
+	res, err := conn.Search(goldap.NewSearchRequest(
+		c.BaseDN,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		2, // we only need to know whether there is more than one match
+		int(c.timeout().Seconds()),
+		false,
+		c.filter(username),
+		c.attributes(),
+		nil,
+	))
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, errors.WithStack(herodot.ErrUpstreamError.WithReason("Unable to search the LDAP server for the user.").WithDebug(err.Error()))
+	}
+
+	if res == nil || len(res.Entries) != 1 {
+		if res != nil && len(res.Entries) > 1 {
+			s.d.Logger().
+				WithField("base_dn", c.BaseDN).
+				WithField("user_filter", c.UserFilter).
+				Warn("The LDAP user filter matched more than one entry. Please make sure the filter matches users uniquely.")
+		}
+		return nil, errors.WithStack(errInvalidCredentials)
+	}
+	found := res.Entries[0]
+
+	if err := conn.Bind(found.DN, password); goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return nil, errors.WithStack(errInvalidCredentials)
+	} else if err != nil {
+		return nil, errors.WithStack(herodot.ErrUpstreamError.WithReason("Unable to verify the credentials with the LDAP server.").WithDebug(err.Error()))
+	}
+
+	e := &entry{DN: found.DN, ID: found.DN, Attributes: make(map[string][]string, len(found.Attributes))}
+	for _, a := range found.Attributes {
+		values := make([]string, len(a.ByteValues))
+		for k, v := range a.ByteValues {
+			values[k] = encodeValue(v)
+		}
+		e.Attributes[a.Name] = values
+	}
+
+	if c.IDAttribute != "" {
+		values := found.GetEqualFoldRawAttributeValues(c.IDAttribute)
+		if len(values) == 0 || len(values[0]) == 0 {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("The LDAP entry of the user does not have the configured ID attribute %q.", c.IDAttribute))
+		}
+		e.ID = encodeValue(values[0])
+	}
+
+	return e, nil
+}
+
+func encodeValue(v []byte) string {
+	if utf8.Valid(v) {
+		return string(v)
+	}
+	return hex.EncodeToString(v)
+}
@@ -0,0 +1,224 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flowhelpers"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+//go:embed .schema/login.schema.json
+var loginSchema []byte
+
+var _ login.AAL1FormHydrator = new(Strategy)
+
+func (s *Strategy) handleLoginError(r *http.Request, f *login.Flow, payload updateLoginFlowWithLdapMethod, err error) error {
+	if f != nil {
+		f.UI.Nodes.ResetNodes("password")
+		f.UI.Nodes.SetValueAttribute("identifier", payload.Identifier)
+		if f.Type == flow.TypeBrowser {
+			f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+		}
+	}
+
+	return err
+}
+
+func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow, _ *session.Session) (i *identity.Identity, err error) {
+	ctx, span := s.d.Tracer(r.Context()).Tracer().Start(r.Context(), "selfservice.strategy.ldap.Strategy.Login")
+	defer otelx.End(span, &err)
+
+	if err := login.CheckAAL(f, identity.AuthenticatorAssuranceLevel1); err != nil {
+		span.SetAttributes(attribute.String("not_responsible_reason", "requested AAL is not AAL1"))
+		return nil, err
+	}
+
+	if err := flow.MethodEnabledAndAllowedFromRequest(r, f.GetFlowName(), s.ID().String(), s.d); err != nil {
+		return nil, err
+	}
+
+	var p updateLoginFlowWithLdapMethod
+	if err := s.hd.Decode(r, &p,
+		decoderx.HTTPDecoderSetValidatePayloads(true),
+		decoderx.MustHTTPRawJSONSchemaCompiler(loginSchema),
+		decoderx.HTTPDecoderJSONFollowsFormFormat()); err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+	f.TransientPayload = p.TransientPayload
+
+	if err := flow.EnsureCSRF(s.d, r, f.Type, s.d.Config().DisableAPIFlowEnforcement(ctx), s.d.GenerateCSRFToken, p.CSRFToken); err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	if err := s.d.LoginLockoutManager().Check(r, s.ID(), p.Identifier); err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	c, err := s.config(ctx)
+	if err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	e, err := s.authenticate(ctx, c, p.Identifier, p.Password)
+	if errors.Is(err, errInvalidCredentials) {
+		return nil, s.handleLoginError(r, f, p, s.d.LoginLockoutManager().RecordFailure(r, s.ID(), p.Identifier, uuid.Nil, errors.WithStack(schema.NewInvalidCredentialsError())))
+	} else if err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	i, err = s.identityFromEntry(ctx, c, e, p.Identifier)
+	if err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
+	if err := s.d.LoginLockoutManager().RecordSuccess(r, s.ID(), p.Identifier); err != nil {
+		return nil, s.handleLoginError(r, f, p, x.WrapWithIdentityIDError(err, i.ID))
+	}
+
+	f.Active = s.ID()
+	if err = s.d.LoginFlowPersister().UpdateLoginFlow(ctx, f); err != nil {
+		return nil, s.handleLoginError(r, f, p, errors.WithStack(x.WrapWithIdentityIDError(herodot.ErrInternalServerError.WithReason("Could not update flow").WithDebug(err.Error()), i.ID)))
+	}
+
+	return i, nil
+}
+
+// identityFromEntry returns the identity linked to the directory entry. If no
+// identity is linked yet, one is created from the entry using the Jsonnet
+// mapper.
+func (s *Strategy) identityFromEntry(ctx context.Context, c *Configuration, e *entry, username string) (_ *identity.Identity, err error) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.ldap.Strategy.identityFromEntry")
+	defer otelx.End(span, &err)
+
+	creds := identity.CredentialsLDAP{DN: e.DN, Username: username}
+
+	i, cred, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), e.ID)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		i = identity.NewIdentity(s.d.Config().DefaultIdentityTraitsSchemaID(ctx))
+		if err := s.mapEntry(ctx, c, e, i); err != nil {
+			return nil, err
+		}
+
+		if err := i.SetCredentialsWithConfig(s.ID(), identity.Credentials{Type: s.ID(), Identifiers: []string{e.ID}}, creds); err != nil {
+			return nil, err
+		}
+
+		if err := s.d.IdentityManager().Create(ctx, i); err != nil {
+			return nil, err
+		}
+
+		span.SetAttributes(attribute.Bool("identity.created", true))
+		return i, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Keep track of renamed or moved entries, and of the username used, which is
+	// pre-filled when refreshing the session.
+	var existing identity.CredentialsLDAP
+	if _, err := i.ParseCredentials(s.ID(), &existing); err != nil {
+		return nil, x.WrapWithIdentityIDError(err, i.ID)
+	}
+
+	if existing != creds {
+		if err := i.SetCredentialsWithConfig(s.ID(), *cred, creds); err != nil {
+			return nil, x.WrapWithIdentityIDError(err, i.ID)
+		}
+		if err := s.d.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits); err != nil {
+			return nil, x.WrapWithIdentityIDError(err, i.ID)
+		}
+	}
+
+	return i, nil
+}
+
+func (s *Strategy) passwordNode() *node.Node {
+	return node.NewInputField("password", nil, node.LDAPGroup,
+		node.InputAttributeTypePassword,
+		node.WithRequiredInputAttribute,
+		node.WithInputAttributes(func(a *node.InputAttributes) {
+			a.Autocomplete = node.InputAttributeAutocompleteCurrentPassword
+		})).
+		WithMetaLabel(text.NewInfoNodeInputPassword())
+}
+
+// addCredentialNodes adds the password input, unless another method such as
+// the password method already did, and the submit button.
+func (s *Strategy) addCredentialNodes(r *http.Request, sr *login.Flow) {
+	sr.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+	if sr.UI.Nodes.Find("password") == nil {
+		sr.UI.SetNode(s.passwordNode())
+	}
+	sr.UI.GetNodes().Append(node.NewInputField("method", s.ID(), node.LDAPGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoLogin()))
+}
+
+func (s *Strategy) PopulateLoginMethodFirstFactorRefresh(r *http.Request, sr *login.Flow, _ *session.Session) (err error) {
+	_, span := s.d.Tracer(r.Context()).Tracer().Start(r.Context(), "selfservice.strategy.ldap.Strategy.PopulateLoginMethodFirstFactorRefresh")
+	defer otelx.End(span, &err)
+
+	_, id, _ := flowhelpers.GuessForcedLoginIdentifier(r, s.d, sr, s.ID())
+	if id == nil {
+		return nil
+	}
+
+	var creds identity.CredentialsLDAP
+	if _, err := id.ParseCredentials(s.ID(), &creds); err != nil || creds.Username == "" {
+		return nil
+	}
+
+	sr.UI.SetNode(node.NewInputField("identifier", creds.Username, node.DefaultGroup, node.InputAttributeTypeHidden))
+	s.addCredentialNodes(r, sr)
+	return nil
+}
+
+func (s *Strategy) PopulateLoginMethodFirstFactor(r *http.Request, sr *login.Flow) error {
+	ds, err := sr.IdentitySchema.URL(r.Context(), s.d.Config())
+	if err != nil {
+		return err
+	}
+
+	identifierLabel, err := login.GetIdentifierLabelFromSchema(r.Context(), ds.String())
+	if err != nil {
+		return err
+	}
+
+	if sr.UI.Nodes.Find("identifier") == nil {
+		sr.UI.SetNode(node.NewInputField("identifier", "", node.DefaultGroup, node.InputAttributeTypeText, node.WithRequiredInputAttribute).WithMetaLabel(identifierLabel))
+	}
+
+	s.addCredentialNodes(r, sr)
+	return nil
+}
+
+// PopulateLoginMethodIdentifierFirstCredentials always shows the LDAP method,
+// because identities are only created on the first login and the identifier
+// can therefore not be used to discover whether the user has an LDAP account.
+func (s *Strategy) PopulateLoginMethodIdentifierFirstCredentials(r *http.Request, sr *login.Flow, _ ...login.FormHydratorModifier) error {
+	s.addCredentialNodes(r, sr)
+	return nil
+}
+
+func (s *Strategy) PopulateLoginMethodIdentifierFirstIdentification(r *http.Request, sr *login.Flow) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+)
+
+// mapEntry maps the user's directory entry onto the identity using the
+// configured Jsonnet mapper.
+//
+// The mapper receives the entry as `std.extVar('ldap')` with the keys `dn` and
+// `attributes`, where each attribute is a list of values. It returns the
+// identity's traits and, optionally, its public and admin metadata, using the
+// same format as the OpenID Connect mapper.
+func (s *Strategy) mapEntry(ctx context.Context, c *Configuration, e *entry, i *identity.Identity) (err error) {
+	input, err := json.Marshal(e)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mapper := identity.JsonnetMapper{
+		Name:    "LDAP",
+		Method:  s.ID().String(),
+		URL:     c.MapperURL,
+		Var:     "ldap",
+		Subject: "directory entry",
+	}
+	evaluated, err := mapper.Map(ctx, s.d, input, i)
+	if err != nil {
+		return err
+	}
+
+	s.d.Logger().
+		WithSensitiveField("ldap_entry", e).
+		WithSensitiveField("mapper_jsonnet_output", evaluated).
+		WithField("mapper_jsonnet_url", c.MapperURL).
+		Debug("LDAP Jsonnet mapper completed.")
+
+	return nil
+}
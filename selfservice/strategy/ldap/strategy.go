@@ -0,0 +1,149 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/jsonnetsecure"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+)
+
+var (
+	_ login.Strategy                    = new(Strategy)
+	_ identity.ActiveCredentialsCounter = new(Strategy)
+)
+
+type dependencies interface {
+	x.LoggingProvider
+	x.WriterProvider
+	nosurfx.CSRFTokenGeneratorProvider
+	nosurfx.CSRFProvider
+	x.HTTPClientProvider
+	x.TracingProvider
+	jsonnetsecure.VMProvider
+	config.Provider
+
+	login.FlowPersistenceProvider
+
+	identity.PrivilegedPoolProvider
+	identity.ManagementProvider
+	identity.LoginLockoutManagementProvider
+
+	session.ManagementProvider
+}
+
+// Configuration is the configuration of the LDAP method.
+type Configuration struct {
+	// URL is the address of the directory, e.g. `ldaps://ad.example.org:636`.
+	URL string `json:"url"`
+
+	// StartTLS upgrades an `ldap://` connection using the StartTLS operation.
+	StartTLS bool `json:"start_tls"`
+
+	// InsecureSkipVerify disables verification of the directory's certificate.
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+
+	// BindDN and BindPassword are the credentials of the service account used to
+	// search for the user's entry. The search is anonymous if BindDN is empty.
+	BindDN       string `json:"bind_dn"`
+	BindPassword string `json:"bind_password"`
+
+	// BaseDN is where the search for the user's entry starts.
+	BaseDN string `json:"base_dn"`
+
+	// UserFilter finds the user's entry. The `{username}` placeholder is
+	// replaced with the escaped identifier.
+	UserFilter string `json:"user_filter"`
+
+	// IDAttribute uniquely identifies the user's entry. Defaults to the DN.
+	IDAttribute string `json:"id_attribute"`
+
+	// Attributes are fetched from the user's entry and passed to the mapper.
+	Attributes []string `json:"attributes"`
+
+	// MapperURL points to the Jsonnet code mapping the entry onto the identity.
+	MapperURL string `json:"mapper_url"`
+
+	// Timeout is the time to wait for the directory to respond.
+	Timeout string `json:"timeout"`
+}
+
+func (c *Configuration) timeout() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return 10 * time.Second
+}
+
+// Strategy authenticates users by binding against an LDAP directory such as
+// Active Directory. Identities are created on the first successful login and
+// linked to the directory entry using the `ldap` credentials.
+type Strategy struct {
+	d  dependencies
+	hd *decoderx.HTTP
+}
+
+func NewStrategy(d dependencies) *Strategy {
+	return &Strategy{
+		d:  d,
+		hd: decoderx.NewHTTP(),
+	}
+}
+
+func (s *Strategy) config(ctx context.Context) (*Configuration, error) {
+	var c Configuration
+
+	conf := s.d.Config().SelfServiceStrategy(ctx, s.ID().String()).Config
+	if err := json.
+		NewDecoder(bytes.NewBuffer(conf)).
+		Decode(&c); err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to decode LDAP configuration: %s", err))
+	}
+
+	return &c, nil
+}
+
+func (s *Strategy) ID() identity.CredentialsType {
+	return identity.CredentialsTypeLDAP
+}
+
+func (s *Strategy) NodeGroup() node.UiNodeGroup {
+	return node.LDAPGroup
+}
+
+func (s *Strategy) CompletedAuthenticationMethod(_ context.Context) session.AuthenticationMethod {
+	return session.AuthenticationMethod{
+		Method: s.ID(),
+		AAL:    identity.AuthenticatorAssuranceLevel1,
+	}
+}
+
+func (s *Strategy) CountActiveFirstFactorCredentials(_ context.Context, cc map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	for _, c := range cc {
+		if c.Type == s.ID() && len(strings.Join(c.Identifiers, "")) > 0 {
+			count++
+		}
+	}
+	return
+}
+
+func (s *Strategy) CountActiveMultiFactorCredentials(_ context.Context, _ map[identity.CredentialsType]identity.Credentials) (count int, err error) {
+	return 0, nil
+}
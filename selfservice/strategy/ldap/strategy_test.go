@@ -0,0 +1,201 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/x/configx"
+)
+
+func TestStrategy(t *testing.T) {
+	t.Parallel()
+
+	directory := testdirectory.Start(t,
+		testdirectory.WithNoTLS(t),
+		testdirectory.WithDefaults(t, &testdirectory.Defaults{
+			Users: append(
+				testdirectory.NewUsers(t, []string{"alice", "bob", "service"}),
+				gldap.NewEntry("cn=carol,"+testdirectory.DefaultUserDN, map[string][]string{
+					"email":      {"carol@example.com"},
+					"password":   {"carol-password"},
+					"objectGUID": {string([]byte{0xff, 0x00, 0xca, 0xfe})},
+				}),
+			),
+		}),
+	)
+
+	ldapConfig := func(c map[string]any) map[string]any {
+		base := map[string]any{
+			"url":         fmt.Sprintf("ldap://%s:%d", directory.Host(), directory.Port()),
+			"base_dn":     testdirectory.DefaultUserDN,
+			"user_filter": "(cn={username})",
+			"mapper_url":  "file://./stub/ldap.jsonnet",
+		}
+		for k, v := range c {
+			base[k] = v
+		}
+		return base
+	}
+
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValue(config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypeLDAP)+".enabled", true),
+		configx.WithValue(config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypeLDAP)+".config", ldapConfig(nil)),
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/default.schema.json")),
+	)
+	publicTS, _ := testhelpers.NewKratosServer(t, reg)
+	testhelpers.NewLoginUIFlowEchoServer(t, reg)
+	testhelpers.NewErrorTestServer(t, reg)
+
+	configKey := config.ViperKeySelfServiceStrategyConfig + "." + string(identity.CredentialsTypeLDAP) + ".config"
+	setConfig := func(t *testing.T, c map[string]any) {
+		defaults := ldapConfig(nil)
+		for k, v := range c {
+			conf.MustSet(t.Context(), configKey+"."+k, v)
+			t.Cleanup(func() {
+				if d, ok := defaults[k]; ok {
+					conf.MustSet(t.Context(), configKey+"."+k, d)
+				} else {
+					conf.MustSet(t.Context(), configKey+"."+k, "")
+				}
+			})
+		}
+	}
+
+	login := func(t *testing.T, identifier, password string) (string, *http.Response) {
+		client := testhelpers.NewDebugClient(t)
+		f := testhelpers.InitializeLoginFlowViaAPI(t, client, publicTS, false)
+		payload, err := json.Marshal(map[string]string{"method": "ldap", "identifier": identifier, "password": password})
+		require.NoError(t, err)
+		return testhelpers.LoginMakeRequest(t, true, false, f, client, string(payload))
+	}
+
+	assertInvalidCredentials := func(t *testing.T, body string, res *http.Response) {
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		assert.Empty(t, gjson.Get(body, "ui.nodes.#(attributes.name==password).attributes.value").String(), "%s", body)
+		assert.Equal(t, "alice", gjson.Get(body, "ui.nodes.#(attributes.name==identifier).attributes.value").String(), "%s", body)
+	}
+
+	t.Run("case=login form contains the ldap method", func(t *testing.T) {
+		f := testhelpers.InitializeLoginFlowViaAPI(t, testhelpers.NewDebugClient(t), publicTS, false)
+		raw, err := json.Marshal(f.Ui.Nodes)
+		require.NoError(t, err)
+
+		assert.Equal(t, "ldap", gjson.GetBytes(raw, `#(attributes.value=="ldap").group`).String(), "%s", raw)
+		assert.Equal(t, "submit", gjson.GetBytes(raw, `#(attributes.value=="ldap").attributes.type`).String(), "%s", raw)
+		assert.Equal(t, "default", gjson.GetBytes(raw, `#(attributes.name=="identifier").group`).String(), "%s", raw)
+
+		// The password input is shared with the password method.
+		assert.Len(t, gjson.GetBytes(raw, `#(attributes.name=="password")#`).Array(), 1, "%s", raw)
+	})
+
+	t.Run("case=creates the identity on the first login and reuses it afterwards", func(t *testing.T) {
+		body, res := login(t, "alice", "password")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.NotEmpty(t, gjson.Get(body, "session_token").String(), "%s", body)
+		assert.Equal(t, "ldap", gjson.Get(body, "session.authentication_methods.0.method").String(), "%s", body)
+		assert.Equal(t, "alice@example.com", gjson.Get(body, "session.identity.traits.email").String(), "%s", body)
+		assert.Equal(t, "alice", gjson.Get(body, "session.identity.traits.name").String(), "%s", body)
+		assert.Equal(t, "cn=alice,"+testdirectory.DefaultUserDN, gjson.Get(body, "session.identity.metadata_public.dn").String(), "%s", body)
+
+		identityID := gjson.Get(body, "session.identity.id").String()
+		i, c, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(t.Context(), identity.CredentialsTypeLDAP, "cn=alice,"+testdirectory.DefaultUserDN)
+		require.NoError(t, err)
+		assert.Equal(t, identityID, i.ID.String())
+		assert.JSONEq(t, `{"dn":"cn=alice,ou=people,dc=example,dc=org","username":"alice"}`, string(c.Config))
+
+		count, err := reg.IdentityManager().CountActiveFirstFactorCredentials(t.Context(), i)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		body, res = login(t, "alice", "password")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, identityID, gjson.Get(body, "session.identity.id").String(), "%s", body)
+	})
+
+	t.Run("case=rejects wrong passwords", func(t *testing.T) {
+		body, res := login(t, "alice", "not-the-password")
+		assertInvalidCredentials(t, body, res)
+	})
+
+	t.Run("case=rejects unknown users", func(t *testing.T) {
+		body, res := login(t, "mallory", "password")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+	})
+
+	t.Run("case=escapes the identifier in the search filter", func(t *testing.T) {
+		body, res := login(t, "*", "password")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+	})
+
+	t.Run("case=rejects empty passwords even if the directory allows unauthenticated binds", func(t *testing.T) {
+		directory.SetAllowAnonymousBind(true)
+		t.Cleanup(func() { directory.SetAllowAnonymousBind(false) })
+
+		body, res := login(t, "alice", "")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		assert.Empty(t, gjson.Get(body, "session_token").String(), "%s", body)
+	})
+
+	t.Run("case=searches using the service account", func(t *testing.T) {
+		t.Run("case=valid service account", func(t *testing.T) {
+			setConfig(t, map[string]any{"bind_dn": "cn=service," + testdirectory.DefaultUserDN, "bind_password": "password"})
+
+			body, res := login(t, "bob", "password")
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, "bob@example.com", gjson.Get(body, "session.identity.traits.email").String(), "%s", body)
+		})
+
+		t.Run("case=invalid service account", func(t *testing.T) {
+			setConfig(t, map[string]any{"bind_dn": "cn=service," + testdirectory.DefaultUserDN, "bind_password": "wrong"})
+
+			body, res := login(t, "bob", "password")
+			assert.Equal(t, http.StatusInternalServerError, res.StatusCode, "%s", body)
+			assert.Contains(t, gjson.Get(body, "error.reason").String(), "service account", "%s", body)
+		})
+	})
+
+	t.Run("case=links the identity using the configured ID attribute", func(t *testing.T) {
+		setConfig(t, map[string]any{"id_attribute": "objectGUID"})
+
+		body, res := login(t, "carol", "carol-password")
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+
+		i, _, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(t.Context(), identity.CredentialsTypeLDAP, "ff00cafe")
+		require.NoError(t, err)
+		assert.Equal(t, gjson.Get(body, "session.identity.id").String(), i.ID.String())
+	})
+
+	t.Run("case=fails if the ID attribute is missing", func(t *testing.T) {
+		setConfig(t, map[string]any{"id_attribute": "entryUUID"})
+
+		body, res := login(t, "alice", "password")
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode, "%s", body)
+		assert.Contains(t, gjson.Get(body, "error.reason").String(), "entryUUID", "%s", body)
+	})
+
+	t.Run("case=fails if the directory is unreachable", func(t *testing.T) {
+		setConfig(t, map[string]any{"url": fmt.Sprintf("ldap://%s:%d", directory.Host(), testdirectory.FreePort(t))})
+
+		body, res := login(t, "alice", "password")
+		assert.Equal(t, http.StatusBadGateway, res.StatusCode, "%s", body)
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import "encoding/json"
+
+// Update Login Flow with LDAP Method
+//
+// swagger:model updateLoginFlowWithLdapMethod
+type updateLoginFlowWithLdapMethod struct {
+	// Method should be set to "ldap" when logging in using the LDAP strategy.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// Sending the anti-csrf token is only required for browser login flows.
+	CSRFToken string `json:"csrf_token"`
+
+	// Identifier is the username the user is known by in the directory.
+	//
+	// required: true
+	Identifier string `json:"identifier"`
+
+	// The user's directory password.
+	//
+	// required: true
+	Password string `json:"password"`
+
+	// Transient data to pass along to any webhooks
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
+}
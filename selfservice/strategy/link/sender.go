@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/courier/template/email"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
@@ -76,12 +77,14 @@ func (s *Sender) SendRecoveryLink(ctx context.Context, f *recovery.Flow, via, to
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		locale := s.locale(ctx, nil, f.Locale)
 		if !notifyUnknownRecipients {
 			// do nothing
 		} else if err := s.send(ctx, string(via), email.NewRecoveryInvalid(s.r, &email.RecoveryInvalidModel{
 			To:               to,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 		})); err != nil {
 			return err
 		}
@@ -134,12 +137,14 @@ func (s *Sender) SendVerificationLink(ctx context.Context, f *verification.Flow,
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		locale := s.locale(ctx, nil, f.Locale)
 		if !notifyUnknownRecipients {
 			// do nothing
 		} else if err := s.send(ctx, string(via), email.NewVerificationInvalid(s.r, &email.VerificationInvalidModel{
 			To:               to,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 		})); err != nil {
 			return err
 		}
@@ -185,6 +190,7 @@ func (s *Sender) SendRecoveryTokenTo(ctx context.Context, f *recovery.Flow, i *i
 		return errors.WithStack(err)
 	}
 
+	locale := s.locale(ctx, i, f.Locale)
 	recoveryUrl := urlx.CopyWithQuery(
 		urlx.AppendPaths(s.r.Config().SelfServiceLinkMethodBaseURL(ctx), recovery.RouteSubmitFlow),
 		url.Values{
@@ -200,6 +206,7 @@ func (s *Sender) SendRecoveryTokenTo(ctx context.Context, f *recovery.Flow, i *i
 			Identity:         model,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 			ExpiresInMinutes: int(s.r.Config().SelfServiceLinkMethodLifespan(ctx).Minutes()),
 		}))
 }
@@ -223,6 +230,7 @@ func (s *Sender) SendVerificationTokenTo(ctx context.Context, f *verification.Fl
 		return errors.WithStack(err)
 	}
 
+	locale := s.locale(ctx, i, f.Locale)
 	verificationUrl := urlx.CopyWithQuery(
 		urlx.AppendPaths(s.r.Config().SelfServiceLinkMethodBaseURL(ctx), verification.RouteSubmitFlow),
 		url.Values{
@@ -237,6 +245,7 @@ func (s *Sender) SendVerificationTokenTo(ctx context.Context, f *verification.Fl
 			Identity:         model,
 			RequestURL:       f.GetRequestURL(),
 			TransientPayload: transientPayload,
+			Locale:           locale,
 			ExpiresInMinutes: int(s.r.Config().SelfServiceLinkMethodLifespan(ctx).Minutes()),
 		})); err != nil {
 		return err
@@ -248,6 +257,15 @@ func (s *Sender) SendVerificationTokenTo(ctx context.Context, f *verification.Fl
 	return nil
 }
 
+// locale returns the locale in which messages to the identity are rendered. The
+// identity may be nil if the recipient is unknown.
+func (s *Sender) locale(ctx context.Context, i *identity.Identity, fallback string) string {
+	if i == nil {
+		return template.ResolveLocale(ctx, s.r, nil, nil, fallback)
+	}
+	return template.ResolveLocale(ctx, s.r, i.Traits, i.MetadataPublic, fallback)
+}
+
 func (s *Sender) send(ctx context.Context, via string, t courier.EmailTemplate) error {
 	switch via {
 	case identity.AddressTypeEmail:
@@ -8,11 +8,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
@@ -80,7 +83,7 @@ func TestManager(t *testing.T) {
 				require.NoError(t, reg.LinkSender().SendRecoveryLink(ctx, f, "email", "tracked@ory.sh"))
 				require.EqualError(t, reg.LinkSender().SendRecoveryLink(ctx, f, "email", "not-tracked@ory.sh"), link.ErrUnknownAddress.Error())
 
-				messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+				messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 				require.NoError(t, err)
 				require.Len(t, messages, 2)
 
@@ -158,7 +161,7 @@ func TestManager(t *testing.T) {
 
 				require.NoError(t, reg.LinkSender().SendVerificationLink(ctx, f, "email", "tracked@ory.sh"))
 				require.EqualError(t, reg.LinkSender().SendVerificationLink(ctx, f, "email", "not-tracked@ory.sh"), link.ErrUnknownAddress.Error())
-				messages, err := reg.CourierPersister().NextMessages(ctx, 12)
+				messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
 				require.NoError(t, err)
 				require.Len(t, messages, 2)
 
@@ -178,6 +181,47 @@ func TestManager(t *testing.T) {
 		})
 	}
 
+	t.Run("case=should localize messages", func(t *testing.T) {
+		templates := t.TempDir()
+		for name, body := range map[string]string{
+			"recovery/valid/email.subject.de.gotmpl":   "Zugang wiederherstellen",
+			"recovery/invalid/email.subject.fr.gotmpl": "Tentative d'accès",
+		} {
+			require.NoError(t, os.MkdirAll(filepath.Join(templates, filepath.Dir(name)), 0o700))
+			require.NoError(t, os.WriteFile(filepath.Join(templates, name), []byte(body), 0o600))
+		}
+
+		ctx := contextx.WithConfigValues(ctx, map[string]any{
+			config.ViperKeyCourierTemplatesPath:        templates,
+			config.ViperKeyCourierLocaleMetadataPublic: "locale",
+		})
+
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"email": "localized@ory.sh"}`)
+		i.MetadataPublic = []byte(`{"locale": "de-DE"}`)
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+
+		r := &http.Request{URL: urlx.ParseOrPanic("https://www.ory.sh/"), Header: http.Header{"Accept-Language": {"fr-CH, fr;q=0.9"}}}
+		s, err := reg.RecoveryStrategies(ctx).Strategy("link")
+		require.NoError(t, err)
+		f, err := recovery.NewFlow(conf, time.Hour, "", r, s, flow.TypeBrowser)
+		require.NoError(t, err)
+		assert.Equal(t, "fr-CH", f.Locale)
+		require.NoError(t, reg.RecoveryFlowPersister().CreateRecoveryFlow(ctx, f))
+
+		require.NoError(t, reg.LinkSender().SendRecoveryLink(ctx, f, "email", "localized@ory.sh"))
+		require.ErrorIs(t, reg.LinkSender().SendRecoveryLink(ctx, f, "email", "not-tracked@ory.sh"), link.ErrUnknownAddress)
+
+		messages, err := reg.CourierPersister().NextMessages(ctx, 12, "", time.Minute)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+
+		assert.Equal(t, "Zugang wiederherstellen", messages[0].Subject)
+		assert.Equal(t, "de-DE", gjson.GetBytes(messages[0].TemplateData, "locale").String())
+		assert.Equal(t, "Tentative d'accès", messages[1].Subject)
+		assert.Equal(t, "fr-CH", gjson.GetBytes(messages[1].TemplateData, "locale").String())
+	})
+
 	t.Run("case=should be able to disable invalid email dispatch", func(t *testing.T) {
 		t.Parallel()
 
@@ -224,7 +268,7 @@ func TestManager(t *testing.T) {
 
 				tc.send(t)
 
-				messages, err := reg.CourierPersister().NextMessages(ctx, 0)
+				messages, err := reg.CourierPersister().NextMessages(ctx, 0, "", time.Minute)
 
 				require.ErrorIs(t, err, courier.ErrQueueEmpty)
 				require.Len(t, messages, 0)
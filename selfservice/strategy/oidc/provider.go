@@ -37,6 +37,13 @@ type (
 		Claims(ctx context.Context, token *oauth1.Token) (*Claims, error)
 		ExchangeToken(ctx context.Context, req *http.Request) (*oauth1.Token, error)
 	}
+
+	// AuthURLProvider is a provider which is neither OAuth1 nor OAuth2 but
+	// still starts the flow by redirecting the browser to an upstream URL.
+	AuthURLProvider interface {
+		Provider
+		AuthURL(ctx context.Context, state string) (string, error)
+	}
 )
 
 type OAuth2TokenExchanger interface {
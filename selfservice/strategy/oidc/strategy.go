@@ -149,11 +149,16 @@ type Strategy struct {
 	credType                    identity.CredentialsType
 	handleUnknownProviderError  func(err error) error
 	handleMethodNotAllowedError func(err error) error
+	resolveProvider             ProviderResolver
 
 	conflictingIdentityPolicy ConflictingIdentityPolicy
 }
 type ConflictingIdentityPolicy func(ctx context.Context, existingIdentity, newIdentity *identity.Identity, provider Provider, claims *Claims) ConflictingIdentityVerdict
 
+// ProviderResolver returns the provider with the given ID. It is used by
+// strategies which reuse this strategy but bring their own provider types.
+type ProviderResolver func(ctx context.Context, id string) (Provider, error)
+
 type AuthCodeContainer struct {
 	FlowID           string              `json:"flow_id"`
 	State            string              `json:"state"`
@@ -216,13 +221,13 @@ func (s *Strategy) RegisterPublicRoutes(r *httprouterx.RouterPublic) {
 	// When handler is called using POST method, the cookies are not attached to the request
 	// by the browser. So here we just redirect the request to the same location rewriting the
 	// form fields to query params. This second GET request should have the cookies attached.
-	r.POST(RouteCallback, s.redirectToGET)
+	r.POST(RouteCallback, s.RedirectToGET)
 }
 
 func (s *Strategy) RegisterAdminRoutes(*httprouterx.RouterAdmin) {}
 
-// Redirect POST request to GET rewriting form fields to query params.
-func (s *Strategy) redirectToGET(w http.ResponseWriter, r *http.Request) {
+// RedirectToGET redirects a POST request to GET rewriting form fields to query params.
+func (s *Strategy) RedirectToGET(w http.ResponseWriter, r *http.Request) {
 	publicURL := s.d.Config().SelfPublicURL(r.Context())
 	dest := *r.URL
 	dest.Host = publicURL.Host
@@ -260,6 +265,11 @@ func WithHandleMethodNotAllowedError(handler func(error) error) NewStrategyOpt {
 	return func(s *Strategy) { s.handleMethodNotAllowedError = handler }
 }
 
+// WithProviderResolver overrides how providers are looked up by their ID.
+func WithProviderResolver(resolver ProviderResolver) NewStrategyOpt {
+	return func(s *Strategy) { s.resolveProvider = resolver }
+}
+
 // WithOnConflictingIdentity sets a policy handler for deciding what to do when a
 // new identity conflicts with an existing one during login.
 func WithOnConflictingIdentity(handler ConflictingIdentityPolicy) NewStrategyOpt {
@@ -349,6 +359,28 @@ func (s *Strategy) ValidateCallback(w http.ResponseWriter, r *http.Request) (flo
 	if stateParam == "" {
 		return nil, nil, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete OpenID Connect flow because the OpenID Provider did not return the state query parameter.`))
 	}
+
+	f, state, cntnr, err := s.ValidateState(w, r, stateParam)
+	if err != nil {
+		return f, state, cntnr, err
+	}
+
+	if errorParam != "" {
+		return f, state, cntnr, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete OpenID Connect flow because the OpenID Provider returned error "%s": %s`, r.URL.Query().Get("error"), r.URL.Query().Get("error_description")))
+	}
+
+	if codeParam == "" {
+		return f, state, cntnr, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete OpenID Connect flow because the OpenID Provider did not return the code query parameter.`))
+	}
+
+	return f, state, cntnr, nil
+}
+
+// ValidateState decrypts the state parameter returned by the upstream provider
+// and loads the flow it belongs to. It also ensures that the state matches the
+// one stored in the continuity container or, for native flows, the session
+// token exchange code.
+func (s *Strategy) ValidateState(w http.ResponseWriter, r *http.Request, stateParam string) (flow.Flow, *oidcv1.State, *AuthCodeContainer, error) {
 	state, err := DecryptState(r.Context(), s.d.Cipher(r.Context()), stateParam)
 	if err != nil {
 		return nil, nil, nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete OpenID Connect flow because the state parameter is invalid.`))
@@ -399,14 +431,6 @@ func (s *Strategy) ValidateCallback(w http.ResponseWriter, r *http.Request) (flo
 		cntnr.FlowID = uuid.FromBytesOrNil(state.FlowId).String()
 	}
 
-	if errorParam != "" {
-		return f, state, &cntnr, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete OpenID Connect flow because the OpenID Provider returned error "%s": %s`, r.URL.Query().Get("error"), r.URL.Query().Get("error_description")))
-	}
-
-	if codeParam == "" {
-		return f, state, &cntnr, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete OpenID Connect flow because the OpenID Provider did not return the code query parameter.`))
-	}
-
 	return f, state, &cntnr, nil
 }
 
@@ -421,7 +445,9 @@ func registrationOrLoginFlowID(flow any) (uuid.UUID, bool) {
 	}
 }
 
-func (s *Strategy) alreadyAuthenticated(ctx context.Context, w http.ResponseWriter, r *http.Request, f interface{}) (bool, error) {
+// AlreadyAuthenticated redirects the browser if a session exists and the flow
+// is neither a settings nor a refresh flow. It returns true if it did.
+func (s *Strategy) AlreadyAuthenticated(ctx context.Context, w http.ResponseWriter, r *http.Request, f interface{}) (bool, error) {
 	if sess, _ := s.d.SessionManager().FetchFromRequest(ctx, r); sess != nil {
 		if _, ok := f.(*settings.Flow); ok {
 			// ignore this if it's a settings flow
@@ -465,22 +491,22 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	req, state, cntnr, err := s.ValidateCallback(w, r)
 	if err != nil {
 		if req != nil {
-			s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 		} else {
 			s.d.SelfServiceErrorManager().Forward(ctx, w, r, s.HandleError(ctx, w, r, nil, "", nil, err))
 		}
 		return
 	}
 
-	if authenticated, err := s.alreadyAuthenticated(ctx, w, r, req); err != nil {
-		s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+	if authenticated, err := s.AlreadyAuthenticated(ctx, w, r, req); err != nil {
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 	} else if authenticated {
 		return
 	}
 
 	provider, err := s.Provider(ctx, state.ProviderId)
 	if err != nil {
-		s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 		return
 	}
 
@@ -492,13 +518,13 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		token, err := s.exchangeCode(ctx, p, code, PKCEVerifier(state))
 		reqlog.AccumulateExternalLatency(ctx, time.Since(t0))
 		if err != nil {
-			s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 			return
 		}
 
 		et, err = s.encryptOAuth2Tokens(ctx, token)
 		if err != nil {
-			s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 			return
 		}
 
@@ -506,7 +532,7 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		claims, err = p.Claims(ctx, token, r.URL.Query())
 		reqlog.AccumulateExternalLatency(ctx, time.Since(t0))
 		if err != nil {
-			s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 			return
 		}
 	case OAuth1Provider:
@@ -514,7 +540,7 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		token, err := p.ExchangeToken(ctx, r)
 		reqlog.AccumulateExternalLatency(ctx, time.Since(t0))
 		if err != nil {
-			s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 			return
 		}
 
@@ -522,18 +548,24 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		claims, err = p.Claims(ctx, token)
 		reqlog.AccumulateExternalLatency(ctx, time.Since(t0))
 		if err != nil {
-			s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 			return
 		}
 	}
 
 	if err = claims.Validate(); err != nil {
-		s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
 		return
 	}
 
 	span.SetAttributes(attribute.StringSlice("claims", slices.Collect(maps.Keys(claims.RawClaims))))
 
+	s.ProcessCallback(ctx, w, r, req, state, cntnr, et, claims, provider)
+}
+
+// ProcessCallback continues the login, registration, or settings flow once
+// the upstream provider returned verified claims.
+func (s *Strategy) ProcessCallback(ctx context.Context, w http.ResponseWriter, r *http.Request, req flow.Flow, state *oidcv1.State, cntnr *AuthCodeContainer, et *identity.CredentialsOIDCEncryptedTokens, claims *Claims, provider Provider) {
 	switch a := req.(type) {
 	case *login.Flow:
 		a.Active = s.ID()
@@ -544,10 +576,10 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			if ff != nil {
-				s.forwardError(ctx, w, r, ff, err)
+				s.ForwardError(ctx, w, r, ff, err)
 				return
 			}
-			s.forwardError(ctx, w, r, a, err)
+			s.ForwardError(ctx, w, r, a, err)
 		}
 		return
 	case *registration.Flow:
@@ -558,10 +590,10 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 			return
 		} else if err != nil {
 			if ff != nil {
-				s.forwardError(ctx, w, r, ff, err)
+				s.ForwardError(ctx, w, r, ff, err)
 				return
 			}
-			s.forwardError(ctx, w, r, a, err)
+			s.ForwardError(ctx, w, r, a, err)
 		}
 		return
 	case *settings.Flow:
@@ -569,16 +601,16 @@ func (s *Strategy) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		a.TransientPayload = cntnr.TransientPayload
 		sess, err := s.d.SessionManager().FetchFromRequest(ctx, r)
 		if err != nil {
-			s.forwardError(ctx, w, r, a, s.HandleError(ctx, w, r, a, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, a, s.HandleError(ctx, w, r, a, state.ProviderId, nil, err))
 			return
 		}
 		if err := s.linkProvider(ctx, w, r, &settings.UpdateContext{Session: sess, Flow: a}, et, claims, provider); err != nil {
-			s.forwardError(ctx, w, r, a, s.HandleError(ctx, w, r, a, state.ProviderId, nil, err))
+			s.ForwardError(ctx, w, r, a, s.HandleError(ctx, w, r, a, state.ProviderId, nil, err))
 			return
 		}
 		return
 	default:
-		s.forwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, errors.WithStack(x.PseudoPanic.
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, errors.WithStack(x.PseudoPanic.
 			WithDetailf("cause", "Unexpected type in OpenID Connect flow: %T", a))))
 		return
 	}
@@ -632,14 +664,21 @@ func (s *Strategy) Config(ctx context.Context) (*ConfigurationCollection, error)
 func (s *Strategy) Provider(ctx context.Context, id string) (Provider, error) {
 	if c, err := s.Config(ctx); err != nil {
 		return nil, err
-	} else if provider, err := c.Provider(id, s.d); err != nil {
+	} else if provider, err := s.provider(ctx, c, id); err != nil {
 		return nil, s.handleUnknownProviderError(err)
 	} else {
 		return provider, nil
 	}
 }
 
-func (s *Strategy) forwardError(ctx context.Context, w http.ResponseWriter, r *http.Request, f flow.Flow, err error) {
+func (s *Strategy) provider(ctx context.Context, c *ConfigurationCollection, id string) (Provider, error) {
+	if s.resolveProvider != nil {
+		return s.resolveProvider(ctx, id)
+	}
+	return c.Provider(id, s.d)
+}
+
+func (s *Strategy) ForwardError(ctx context.Context, w http.ResponseWriter, r *http.Request, f flow.Flow, err error) {
 	switch ff := f.(type) {
 	case *login.Flow:
 		s.d.LoginFlowErrorHandler().WriteFlowError(w, r, ff, s.ID(), s.NodeGroup(), err)
@@ -744,7 +783,6 @@ func (s *Strategy) HandleError(ctx context.Context, w http.ResponseWriter, r *ht
 }
 
 func (s *Strategy) populateAccountLinkingUI(ctx context.Context, lf *login.Flow, usedProviderID string, duplicateIdentifier string, availableCredentials []string, availableProviders []string) {
-	newLoginURL := s.d.Config().SelfServiceFlowLoginUI(ctx).String()
 	usedProviderLabel := usedProviderID
 	provider, _ := s.Provider(ctx, usedProviderID)
 	if provider != nil && provider.Config() != nil {
@@ -753,69 +791,13 @@ func (s *Strategy) populateAccountLinkingUI(ctx context.Context, lf *login.Flow,
 			usedProviderLabel = provider.Config().Provider
 		}
 	}
-	loginHintsEnabled := s.d.Config().SelfServiceFlowRegistrationLoginHints(ctx)
-	nodes := []*node.Node{}
-	for _, n := range lf.UI.Nodes {
-		// We don't want to touch nodes unecessary nodes
-		if n.Meta == nil || n.Meta.Label == nil || n.Group == node.DefaultGroup {
-			nodes = append(nodes, n)
-			continue
-		}
-
-		// Skip the provider that was used to get here (in case they used an OIDC provider)
-		pID := gjson.GetBytes(n.Meta.Label.Context, "provider_id").String()
-		if n.Group == node.OpenIDConnectGroup {
-			if pID == usedProviderID {
-				continue
-			}
-			// Hide any provider that is not available for the user
-			if loginHintsEnabled && !slices.Contains(availableProviders, pID) {
-				continue
-			}
-		}
-
-		// Replace some labels to make it easier for the user to understand what's going on.
-		switch n.Meta.Label.ID {
-		case text.InfoSelfServiceLogin:
-			n.Meta.Label = text.NewInfoLoginAndLink()
-		case text.InfoSelfServiceLoginWith:
-			p := gjson.GetBytes(n.Meta.Label.Context, "provider").String()
-			n.Meta.Label = text.NewInfoLoginWithAndLink(p)
-		default:
-			// do nothing
-		}
-
-		// This can happen, if login hints are disabled. In that case, we need to make sure to show all credential options.
-		// It could in theory also happen due to a mis-configuration, and in that case, we should make sure to not delete the entire flow.
-		if !loginHintsEnabled {
-			nodes = append(nodes, n)
-		} else {
-			// Hide nodes from credentials that are not relevant for the user
-			for _, ct := range availableCredentials {
-				if ct == string(n.Group) {
-					nodes = append(nodes, n)
-					break
-				}
-			}
-		}
-	}
-
-	// Hide the "primary" identifier field present for Password, webauthn or passwordless, as we already know the identifier
-	identifierNode := lf.UI.Nodes.Find("identifier")
-	if identifierNode != nil {
-		if attributes, ok := identifierNode.Attributes.(*node.InputAttributes); ok {
-			attributes.Type = node.InputAttributeTypeHidden
-			attributes.SetValue(duplicateIdentifier)
-			identifierNode.Attributes = attributes
-		}
-	}
-
-	lf.UI.Nodes = nodes
-	lf.UI.Messages.Clear()
-	lf.UI.Messages.Add(text.NewInfoLoginLinkMessage(duplicateIdentifier, usedProviderLabel, newLoginURL, availableCredentials, availableProviders))
+	login.PopulateAccountLinkingUI(ctx, s.d.Config(), lf, usedProviderID, usedProviderLabel, duplicateIdentifier, availableCredentials, availableProviders)
 }
 
 func (s *Strategy) NodeGroup() node.UiNodeGroup {
+	if s.ID() == identity.CredentialsTypeSAML {
+		return node.SAMLGroup
+	}
 	return node.OpenIDConnectGroup
 }
 
@@ -924,6 +906,8 @@ func getAuthRedirectURL(ctx context.Context, provider Provider, req ider, state
 		return c.AuthCodeURL(state, opts...), nil
 	case OAuth1Provider:
 		return p.AuthURL(ctx, state)
+	case AuthURLProvider:
+		return p.AuthURL(ctx, state)
 	default:
 		return "", errors.WithStack(herodot.ErrInternalServerError.WithReasonf("The provider %s does not support the OAuth 2.0 or OAuth 1.0 protocol", provider.Config().Provider))
 	}
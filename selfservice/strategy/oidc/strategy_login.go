@@ -245,7 +245,7 @@ func (s *Strategy) ProcessLogin(ctx context.Context, w http.ResponseWriter, r *h
 
 	for _, c := range oidcCredentials.Providers {
 		if c.Subject == claims.Subject && c.Provider == provider.Config().ID {
-			if err = s.d.LoginHookExecutor().PostLoginHook(w, r, node.OpenIDConnectGroup, loginFlow, i, sess, provider.Config().ID); err != nil {
+			if err = s.d.LoginHookExecutor().PostLoginHook(w, r, s.NodeGroup(), loginFlow, i, sess, provider.Config().ID); err != nil {
 				return nil, x.WrapWithIdentityIDError(s.HandleError(ctx, w, r, loginFlow, provider.Config().ID, nil, err), i.ID)
 			}
 			return nil, nil
@@ -304,7 +304,7 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		return nil, s.HandleError(ctx, w, r, f, pid, nil, err)
 	}
 
-	if authenticated, err := s.alreadyAuthenticated(ctx, w, r, req); err != nil {
+	if authenticated, err := s.AlreadyAuthenticated(ctx, w, r, req); err != nil {
 		return nil, s.HandleError(ctx, w, r, f, pid, nil, err)
 	} else if authenticated {
 		return i, nil
@@ -442,7 +442,7 @@ func (s *Strategy) PopulateLoginMethodIdentifierFirstCredentials(r *http.Request
 	if o.IdentityHint != nil {
 		var err error
 		// If we have an identity hint we check if the identity has any providers configured.
-		if linked, err = s.linkedProviders(conf, o.IdentityHint); err != nil {
+		if linked, err = s.linkedProviders(ctx, conf, o.IdentityHint); err != nil {
 			return err
 		}
 	}
@@ -17,7 +17,6 @@ import (
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/continuity"
@@ -203,7 +202,7 @@ func (s *Strategy) Register(w http.ResponseWriter, r *http.Request, f *registrat
 		return s.HandleError(ctx, w, r, f, pid, nil, err)
 	}
 
-	if authenticated, err := s.alreadyAuthenticated(ctx, w, r, req); err != nil {
+	if authenticated, err := s.AlreadyAuthenticated(ctx, w, r, req); err != nil {
 		return s.HandleError(ctx, w, r, f, pid, nil, err)
 	} else if authenticated {
 		return errors.WithStack(registration.ErrAlreadyLoggedIn)
@@ -379,7 +378,7 @@ func (s *Strategy) newIdentityFromClaims(ctx context.Context, claims *Claims, pr
 
 	defer func() {
 		if err != nil {
-			trace.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(
+			events.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(
 				ctx, err, jsonClaims.Bytes(), evaluated, provider.Config().Provider, s.ID().String(),
 			))
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/ory/kratos/selfservice/flow/settings"
 	"github.com/ory/kratos/selfservice/strategy"
 	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/ui/node"
 	"github.com/ory/kratos/x"
 )
 
@@ -85,7 +86,7 @@ func (s *Strategy) decoderSettings(ctx context.Context, p *updateSettingsFlowWit
 	return nil
 }
 
-func (s *Strategy) linkedProviders(conf *ConfigurationCollection, confidential *identity.Identity) ([]Provider, error) {
+func (s *Strategy) linkedProviders(ctx context.Context, conf *ConfigurationCollection, confidential *identity.Identity) ([]Provider, error) {
 	creds, ok := confidential.GetCredentials(s.ID())
 	if !ok {
 		return nil, nil
@@ -98,7 +99,7 @@ func (s *Strategy) linkedProviders(conf *ConfigurationCollection, confidential *
 
 	var result []Provider
 	for _, p := range available.Providers {
-		prov, err := conf.Provider(p.Provider, s.d)
+		prov, err := s.provider(ctx, conf, p.Provider)
 		if errors.Is(err, herodot.ErrNotFound) {
 			continue
 		} else if err != nil {
@@ -110,7 +111,7 @@ func (s *Strategy) linkedProviders(conf *ConfigurationCollection, confidential *
 	return result, nil
 }
 
-func (s *Strategy) linkableProviders(conf *ConfigurationCollection, confidential *identity.Identity) ([]Provider, error) {
+func (s *Strategy) linkableProviders(ctx context.Context, conf *ConfigurationCollection, confidential *identity.Identity) ([]Provider, error) {
 	var available identity.CredentialsOIDC
 	creds, ok := confidential.GetCredentials(s.ID())
 	if ok {
@@ -130,7 +131,7 @@ func (s *Strategy) linkableProviders(conf *ConfigurationCollection, confidential
 		}
 
 		if !found {
-			prov, err := conf.Provider(p.ID, s.d)
+			prov, err := s.provider(ctx, conf, p.ID)
 			if err != nil {
 				return nil, err
 			}
@@ -154,24 +155,26 @@ func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request,
 		return err
 	}
 
-	linkable, err := s.linkableProviders(conf, id)
+	linkable, err := s.linkableProviders(ctx, conf, id)
 	if err != nil {
 		return err
 	}
 
-	linked, err := s.linkedProviders(conf, id)
+	linked, err := s.linkedProviders(ctx, conf, id)
 	if err != nil {
 		return err
 	}
 
-	sr.UI.GetNodes().Remove("unlink", "link")
+	for _, name := range []string{"unlink", "link"} {
+		sr.UI.GetNodes().RemoveMatching(&node.Node{Group: s.NodeGroup(), Attributes: &node.InputAttributes{Name: name}})
+	}
 	sr.UI.SetCSRF(s.d.GenerateCSRFToken(r))
 	for _, l := range linkable {
 		// We do not want to offer to link SSO providers in the settings.
 		if l.Config().OrganizationID != "" {
 			continue
 		}
-		sr.UI.GetNodes().Append(NewLinkNode(l.Config().ID, stringsx.Coalesce(l.Config().Label, l.Config().ID)))
+		sr.UI.GetNodes().Append(newLinkNode(s.NodeGroup(), l.Config().ID, stringsx.Coalesce(l.Config().Label, l.Config().ID)))
 	}
 
 	count, err := s.d.IdentityManager().CountActiveFirstFactorCredentials(ctx, id)
@@ -183,7 +186,7 @@ func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request,
 		// This means that we're able to remove a connection because it is the last configured credential. If it is
 		// removed, the identity is no longer able to sign in.
 		for _, l := range linked {
-			sr.UI.GetNodes().Append(NewUnlinkNode(l.Config().ID, stringsx.Coalesce(l.Config().Label, l.Config().ID)))
+			sr.UI.GetNodes().Append(newUnlinkNode(s.NodeGroup(), l.Config().ID, stringsx.Coalesce(l.Config().Label, l.Config().ID)))
 		}
 	}
 
@@ -331,7 +334,7 @@ func (s *Strategy) isLinkable(ctx context.Context, ctxUpdate *settings.UpdateCon
 		return nil, err
 	}
 
-	linkable, err := s.linkableProviders(providers, i)
+	linkable, err := s.linkableProviders(ctx, providers, i)
 	if err != nil {
 		return nil, err
 	}
@@ -355,8 +358,8 @@ func (s *Strategy) initLinkProvider(ctx context.Context, w http.ResponseWriter,
 		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
 
-	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
-		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, errors.WithStack(settings.NewFlowNeedsReAuth()))
+	if err := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session); err != nil {
+		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
 
 	provider, err := s.Provider(ctx, p.Link)
@@ -407,8 +410,8 @@ func (s *Strategy) linkProvider(ctx context.Context, w http.ResponseWriter, r *h
 		Link: provider.Config().ID, FlowID: ctxUpdate.Flow.ID.String(),
 	}
 
-	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
-		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, errors.WithStack(settings.NewFlowNeedsReAuth()))
+	if err := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session); err != nil {
+		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
 
 	i, err := s.isLinkable(ctx, ctxUpdate, p.Link)
@@ -445,8 +448,8 @@ func (s *Strategy) linkProvider(ctx context.Context, w http.ResponseWriter, r *h
 }
 
 func (s *Strategy) unlinkProvider(ctx context.Context, w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, p *updateSettingsFlowWithOidcMethod) error {
-	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
-		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, errors.WithStack(settings.NewFlowNeedsReAuth()))
+	if err := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session); err != nil {
+		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
 
 	providers, err := s.Config(ctx)
@@ -459,7 +462,7 @@ func (s *Strategy) unlinkProvider(ctx context.Context, w http.ResponseWriter, r
 		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
 
-	availableProviders, err := s.linkedProviders(providers, i)
+	availableProviders, err := s.linkedProviders(ctx, providers, i)
 	if err != nil {
 		return s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
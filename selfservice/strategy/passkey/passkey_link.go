@@ -0,0 +1,68 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package passkey
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/session"
+)
+
+var _ login.LinkableStrategy = (*Strategy)(nil)
+
+// Link adds the passkey created in a registration which collided with the
+// identity the user signed in to afterwards.
+//
+// Passkeys are discovered using the user handle, of which an identity has
+// exactly one. The passkey can therefore only be linked to identities without
+// passkeys, because it was created for the user handle of the registration.
+func (s *Strategy) Link(ctx context.Context, i *identity.Identity, credentialsConfig sqlxx.JSONRawMessage) (err error) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.passkey.Strategy.Link")
+	defer otelx.End(span, &err)
+
+	var conf identity.CredentialsWebAuthnConfig
+	if err := json.Unmarshal(credentialsConfig, &conf); err != nil {
+		return errors.WithStack(err)
+	} else if len(conf.UserHandle) == 0 || len(conf.Credentials) == 0 {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReason("The linked passkey credentials do not contain a passkey."))
+	}
+
+	if count, err := s.countCredentials(i.Credentials); err != nil {
+		return err
+	} else if count > 0 {
+		return errors.WithStack(herodot.ErrConflict.WithReason("The identity already has a passkey."))
+	}
+
+	i.SetCredentials(s.ID(), identity.Credentials{
+		Type:        s.ID(),
+		Identifiers: []string{string(conf.UserHandle)},
+		Config:      credentialsConfig,
+		Version:     1,
+	})
+
+	return s.d.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits)
+}
+
+func (s *Strategy) CompletedLogin(sess *session.Session, _ *flow.DuplicateCredentialsData) error {
+	sess.CompletedLoginFor(s.ID(), identity.AuthenticatorAssuranceLevel1)
+	return nil
+}
+
+func (s *Strategy) SetDuplicateCredentials(f flow.InternalContexter, duplicateIdentifier string, credentials identity.Credentials, _ string) error {
+	return flow.SetDuplicateCredentials(f, flow.DuplicateCredentialsData{
+		CredentialsType:     s.ID(),
+		CredentialsConfig:   credentials.Config,
+		DuplicateIdentifier: duplicateIdentifier,
+	})
+}
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 
@@ -254,8 +253,8 @@ func (s *Strategy) continueSettingsFlow(
 			return err
 		}
 
-		if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
-			return errors.WithStack(settings.NewFlowNeedsReAuth())
+		if err := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session); err != nil {
+			return err
 		}
 	} else {
 		return errors.New("ended up in unexpected state")
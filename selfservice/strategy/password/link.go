@@ -0,0 +1,63 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package password
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/session"
+)
+
+var _ login.LinkableStrategy = new(Strategy)
+
+// Link sets the password chosen in a registration which collided with the
+// identity the user signed in to afterwards.
+func (s *Strategy) Link(ctx context.Context, i *identity.Identity, credentialsConfig sqlxx.JSONRawMessage) (err error) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.password.Strategy.Link")
+	defer otelx.End(span, &err)
+
+	var conf identity.CredentialsPassword
+	if err := json.Unmarshal(credentialsConfig, &conf); err != nil {
+		return errors.WithStack(err)
+	} else if len(conf.HashedPassword) == 0 {
+		return errors.WithStack(herodot.ErrInternalServerError.WithReason("The linked password credentials do not contain a password hash."))
+	}
+
+	// Linking must never replace a password the identity already has.
+	if count, err := s.CountActiveFirstFactorCredentials(ctx, i.Credentials); err != nil {
+		return err
+	} else if count > 0 {
+		return errors.WithStack(herodot.ErrConflict.WithReason("The identity already has a password."))
+	}
+
+	// The identifiers are populated from the traits when the identity is validated.
+	c := i.GetCredentialsOr(s.ID(), &identity.Credentials{Type: s.ID(), Identifiers: []string{}})
+	c.Config = credentialsConfig
+	i.SetCredentials(s.ID(), *c)
+
+	return s.d.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits)
+}
+
+func (s *Strategy) CompletedLogin(sess *session.Session, _ *flow.DuplicateCredentialsData) error {
+	sess.CompletedLoginFor(s.ID(), identity.AuthenticatorAssuranceLevel1)
+	return nil
+}
+
+func (s *Strategy) SetDuplicateCredentials(f flow.InternalContexter, duplicateIdentifier string, credentials identity.Credentials, _ string) error {
+	return flow.SetDuplicateCredentials(f, flow.DuplicateCredentialsData{
+		CredentialsType:     s.ID(),
+		CredentialsConfig:   credentials.Config,
+		DuplicateIdentifier: duplicateIdentifier,
+	})
+}
@@ -75,10 +75,14 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 	}
 
 	identifier := stringsx.Coalesce(p.Identifier, p.LegacyIdentifier)
+	if err := s.d.LoginLockoutManager().Check(r, s.ID(), identifier); err != nil {
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
 	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), identifier)
 	if err != nil {
 		time.Sleep(x.RandomDelay(s.d.Config().HasherArgon2(ctx).ExpectedDuration, s.d.Config().HasherArgon2(ctx).ExpectedDeviation))
-		return nil, s.handleLoginError(r, f, p, errors.WithStack(schema.NewInvalidCredentialsError()))
+		return nil, s.handleLoginError(r, f, p, s.d.LoginLockoutManager().RecordFailure(r, s.ID(), identifier, uuid.Nil, errors.WithStack(schema.NewInvalidCredentialsError())))
 	}
 
 	var o identity.CredentialsPassword
@@ -108,7 +112,7 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		}
 	} else {
 		if err := hash.Compare(ctx, []byte(p.Password), []byte(o.HashedPassword)); err != nil {
-			return nil, s.handleLoginError(r, f, p, errors.WithStack(x.WrapWithIdentityIDError(schema.NewInvalidCredentialsError(), i.ID)))
+			return nil, s.handleLoginError(r, f, p, x.WrapWithIdentityIDError(s.d.LoginLockoutManager().RecordFailure(r, s.ID(), identifier, i.ID, errors.WithStack(schema.NewInvalidCredentialsError())), i.ID))
 		}
 
 		if !s.d.Hasher(ctx).Understands([]byte(o.HashedPassword)) {
@@ -118,6 +122,10 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		}
 	}
 
+	if err := s.d.LoginLockoutManager().RecordSuccess(r, s.ID(), identifier); err != nil {
+		return nil, s.handleLoginError(r, f, p, x.WrapWithIdentityIDError(err, i.ID))
+	}
+
 	f.Active = s.ID()
 	if err = s.d.LoginFlowPersister().UpdateLoginFlow(ctx, f); err != nil {
 		return nil, s.handleLoginError(r, f, p, errors.WithStack(x.WrapWithIdentityIDError(herodot.ErrInternalServerError.WithReason("Could not update flow").WithDebug(err.Error()), i.ID)))
@@ -347,6 +347,36 @@ func TestCompleteLogin(t *testing.T) {
 		})
 	})
 
+	t.Run("should lock the identifier after too many failed attempts", func(t *testing.T) {
+		conf.MustSet(t.Context(), config.ViperKeySecurityLoginLockoutEnabled, true)
+		conf.MustSet(t.Context(), config.ViperKeySecurityLoginLockoutMaxAttempts, 2)
+		conf.MustSet(t.Context(), config.ViperKeySecurityLoginLockoutMaxAttemptsPerIP, 0)
+		t.Cleanup(func() {
+			conf.MustSet(t.Context(), config.ViperKeySecurityLoginLockoutEnabled, false)
+		})
+
+		identifier, pwd := x.NewUUID().String(), "lockout-password"
+		createIdentity(t.Context(), reg, t, identifier, pwd)
+
+		values := func(password string) func(v url.Values) {
+			return func(v url.Values) {
+				v.Set("identifier", identifier)
+				v.Set("password", password)
+			}
+		}
+
+		body := expectValidationError(t, true, false, false, values("wrong-password"))
+		assert.EqualValues(t, text.ErrorValidationInvalidCredentials, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+
+		body = expectValidationError(t, true, false, false, values("wrong-password"))
+		assert.EqualValues(t, text.ErrorValidationLoginLockedOut, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+		assert.NotEmpty(t, gjson.Get(body, "ui.messages.0.context.locked_until").String(), "%s", body)
+
+		// The correct password is rejected as well while the identifier is locked.
+		body = expectValidationError(t, true, false, false, values(pwd))
+		assert.EqualValues(t, text.ErrorValidationLoginLockedOut, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+	})
+
 	t.Run("should return an error because no identifier is set", func(t *testing.T) {
 		check := func(t *testing.T, body string) {
 			assert.NotEmpty(t, gjson.Get(body, "id").String(), "%s", body)
@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
@@ -367,6 +368,75 @@ func TestRegistration(t *testing.T) {
 			})
 		})
 
+		t.Run("case=should offer to link the password to an existing identity", func(t *testing.T) {
+			conf.MustSet(t.Context(), config.ViperKeySelfServiceRegistrationAccountLinking, true)
+			t.Cleanup(func() {
+				conf.MustSet(t.Context(), config.ViperKeySelfServiceRegistrationAccountLinking, false)
+			})
+
+			newExistingIdentity := func(t *testing.T, username string, withPassword bool) *identity.Identity {
+				i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+				i.Traits = identity.Traits(`{"username":"` + username + `","foobar":"bar"}`)
+				oidc, err := identity.NewCredentialsOIDC(&identity.CredentialsOIDCEncryptedTokens{}, "github", x.NewUUID().String(), "")
+				require.NoError(t, err)
+				i.SetCredentials(identity.CredentialsTypeOIDC, *oidc)
+				if withPassword {
+					require.NoError(t, i.SetCredentialsWithConfig(identity.CredentialsTypePassword, identity.Credentials{Identifiers: []string{username}}, identity.CredentialsPassword{HashedPassword: "$2a$04$zvZz1zV"}))
+				}
+				require.NoError(t, reg.IdentityManager().Create(t.Context(), i))
+				return i
+			}
+
+			register := func(t *testing.T, username, password string, expectedStatusCode int) string {
+				return testhelpers.SubmitRegistrationForm(t, true, testhelpers.NewDebugClient(t), publicTS, func(v url.Values) {
+					v.Set("traits.username", username)
+					v.Set("traits.foobar", "bar")
+					v.Set("password", password)
+				}, false, expectedStatusCode, publicTS.URL+registration.RouteSubmitFlow)
+			}
+
+			t.Run("case=links the password after signing in", func(t *testing.T) {
+				username := "registration-identifier-link-" + x.NewUUID().String()
+				password := x.NewUUID().String()
+				existing := newExistingIdentity(t, username, false)
+
+				body := register(t, username, password, http.StatusBadRequest)
+				assert.EqualValues(t, text.InfoSelfServiceLoginLink, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+				assert.Equal(t, username, gjson.Get(body, "ui.messages.0.context.duplicate_identifier").String(), "%s", body)
+				assert.Contains(t, gjson.Get(body, "ui.action").String(), login.RouteSubmitFlow, "%s", body)
+				assert.Equal(t, username, gjson.Get(body, "ui.nodes.#(attributes.name==identifier).attributes.value").String(), "%s", body)
+
+				lf, err := reg.LoginFlowPersister().GetLoginFlow(t.Context(), uuid.Must(uuid.FromString(gjson.Get(body, "id").String())))
+				require.NoError(t, err)
+				dc, err := flow.DuplicateCredentials(lf)
+				require.NoError(t, err)
+				require.NotNil(t, dc)
+				assert.Equal(t, identity.CredentialsTypePassword, dc.CredentialsType)
+				assert.Equal(t, username, dc.DuplicateIdentifier)
+
+				// Signing in with the social sign in connection links the password.
+				strategy, err := reg.AllLoginStrategies().Strategy(identity.CredentialsTypePassword)
+				require.NoError(t, err)
+				i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), existing.ID)
+				require.NoError(t, err)
+				require.NoError(t, strategy.(login.LinkableStrategy).Link(t.Context(), i, dc.CredentialsConfig))
+
+				f := testhelpers.InitializeLoginFlowViaAPI(t, apiClient, publicTS, false)
+				body, res := testhelpers.LoginMakeRequest(t, true, false, f, apiClient, fmt.Sprintf(`{"method":"password","identifier":%q,"password":%q}`, username, password))
+				require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+				assert.Equal(t, existing.ID.String(), gjson.Get(body, "session.identity.id").String(), "%s", body)
+			})
+
+			t.Run("case=does not replace an existing password", func(t *testing.T) {
+				username := "registration-identifier-link-" + x.NewUUID().String()
+				_ = newExistingIdentity(t, username, true)
+
+				body := register(t, username, x.NewUUID().String(), http.StatusBadRequest)
+				assert.EqualValues(t, text.ErrorValidationDuplicateCredentialsWithHints, gjson.Get(body, "ui.messages.0.id").Int(), "%s", body)
+				assert.Contains(t, gjson.Get(body, "ui.action").String(), registration.RouteSubmitFlow, "%s", body)
+			})
+		})
+
 		t.Run("case=should return correct error ids from validation failures", func(t *testing.T) {
 			test := func(t *testing.T, constraint string, setValues func(url.Values), expectedId text.ID, expectedMesage string) {
 				template := `{
@@ -6,11 +6,11 @@ package password
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/ory/jsonschema/v3"
 	"github.com/ory/x/otelx"
 
 	"github.com/ory/kratos/hash"
@@ -32,6 +32,10 @@ import (
 	"github.com/ory/x/decoderx"
 )
 
+var ErrRemoveLastFirstFactor = &jsonschema.ValidationError{
+	Message: "can not remove the password because it is the last remaining first factor credential", InstancePtr: "#/password_remove",
+}
+
 func (s *Strategy) SettingsStrategyID() string {
 	return identity.CredentialsTypePassword.String()
 }
@@ -42,15 +46,22 @@ func (s *Strategy) SettingsStrategyID() string {
 type updateSettingsFlowWithPasswordMethod struct {
 	// Password is the updated password
 	//
-	// required: true
+	// Required unless the password is removed.
 	Password string `json:"password"`
 
+	// Remove the password
+	//
+	// The password can only be removed if the identity is able to sign in
+	// with another first factor afterwards.
+	Remove bool `json:"password_remove"`
+
 	// CSRFToken is the anti-CSRF token
 	CSRFToken string `json:"csrf_token"`
 
 	// Method
 	//
-	// Should be set to password when trying to update a password.
+	// Should be set to password when trying to update a password. Can be
+	// omitted when removing the password.
 	//
 	// required: true
 	Method string `json:"method"`
@@ -87,13 +98,20 @@ func (s *Strategy) Settings(ctx context.Context, w http.ResponseWriter, r *http.
 	}
 
 	if err := flow.MethodEnabledAndAllowedFromRequest(r, f.GetFlowName(), s.SettingsStrategyID(), s.d); err != nil {
-		return ctxUpdate, s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
+		// The button removing the password does not submit the method.
+		if !errors.Is(err, flow.ErrStrategyNotResponsible) || !s.removeRequested(r) {
+			return ctxUpdate, s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
+		}
 	}
 
 	if err := s.decodeSettingsFlow(r, &p); err != nil {
 		return ctxUpdate, s.handleSettingsError(ctx, w, r, ctxUpdate, p, err)
 	}
 
+	if p.Remove {
+		p.Method = s.SettingsStrategyID()
+	}
+
 	// This does not come from the payload!
 	p.Flow = ctxUpdate.Flow.ID.String()
 	if err := s.continueSettingsFlow(ctx, r, ctxUpdate, p); err != nil {
@@ -103,18 +121,26 @@ func (s *Strategy) Settings(ctx context.Context, w http.ResponseWriter, r *http.
 	return ctxUpdate, nil
 }
 
-func (s *Strategy) decodeSettingsFlow(r *http.Request, dest interface{}) error {
+func (s *Strategy) decodeSettingsFlow(r *http.Request, dest interface{}, opts ...decoderx.HTTPDecoderOption) error {
 	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(settingsSchema)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	return decoderx.NewHTTP().Decode(r, dest, compiler,
+	return decoderx.NewHTTP().Decode(r, dest, append([]decoderx.HTTPDecoderOption{
+		compiler,
 		decoderx.HTTPKeepRequestBody(true),
 		decoderx.HTTPDecoderAllowedMethods("POST", "GET"),
 		decoderx.HTTPDecoderSetValidatePayloads(true),
 		decoderx.HTTPDecoderJSONFollowsFormFormat(),
-	)
+	}, opts...)...)
+}
+
+// removeRequested returns true if the request was submitted using the button
+// removing the password.
+func (s *Strategy) removeRequested(r *http.Request) bool {
+	var p updateSettingsFlowWithPasswordMethod
+	return s.decodeSettingsFlow(r, &p, decoderx.HTTPDecoderSetValidatePayloads(false)) == nil && p.Remove
 }
 
 // Try to find a password hash in the credentials. Returns it if found, otherwise return an empty string.
@@ -157,8 +183,12 @@ func (s *Strategy) continueSettingsFlow(ctx context.Context, r *http.Request, ct
 		return err
 	}
 
-	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
-		return errors.WithStack(settings.NewFlowNeedsReAuth())
+	if err := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session); err != nil {
+		return err
+	}
+
+	if p.Remove {
+		return s.continueSettingsFlowRemove(ctx, ctxUpdate)
 	}
 
 	if len(p.Password) == 0 {
@@ -213,14 +243,58 @@ func (s *Strategy) continueSettingsFlow(ctx context.Context, r *http.Request, ct
 	return nil
 }
 
-func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request, _ *identity.Identity, f *settings.Flow) (err error) {
-	_, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.password.Strategy.PopulateSettingsMethod")
+func (s *Strategy) continueSettingsFlowRemove(ctx context.Context, ctxUpdate *settings.UpdateContext) error {
+	i, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, ctxUpdate.Session.Identity.ID)
+	if err != nil {
+		return err
+	}
+
+	if count, err := s.CountActiveFirstFactorCredentials(ctx, i.Credentials); err != nil {
+		return err
+	} else if count == 0 {
+		return errors.WithStack(herodot.ErrBadRequest.WithReason("You tried to remove a password but you have no password set up."))
+	}
+
+	count, err := s.d.IdentityManager().CountActiveFirstFactorCredentials(ctx, i)
+	if err != nil {
+		return err
+	}
+
+	if count < 2 {
+		return errors.WithStack(ErrRemoveLastFirstFactor)
+	}
+
+	i.DeleteCredentialsType(s.ID())
+	ctxUpdate.UpdateIdentity(i)
+	return nil
+}
+
+func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request, id *identity.Identity, f *settings.Flow) (err error) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.password.Strategy.PopulateSettingsMethod")
 	defer otelx.End(span, &err)
 
 	f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
 	f.UI.Nodes.Upsert(NewPasswordNode("password", node.InputAttributeAutocompleteNewPassword).WithMetaLabel(text.NewInfoNodeInputPassword()))
 	f.UI.Nodes.Append(node.NewInputField("method", "password", node.PasswordGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoNodeLabelSave()))
 
+	if id == nil {
+		return nil
+	}
+
+	// The password can only be removed if the identity can still sign in afterwards.
+	if count, err := s.CountActiveFirstFactorCredentials(ctx, id.Credentials); err != nil {
+		return err
+	} else if count > 0 {
+		total, err := s.d.IdentityManager().CountActiveFirstFactorCredentials(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if total > 1 {
+			f.UI.Nodes.Append(node.NewInputField("password_remove", "true", node.PasswordGroup, node.InputAttributeTypeSubmit).WithMetaLabel(text.NewInfoSelfServiceRemovePassword()))
+		}
+	}
+
 	return nil
 }
 
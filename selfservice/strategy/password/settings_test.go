@@ -680,3 +680,68 @@ func TestSettings(t *testing.T) {
 		}
 	})
 }
+
+func TestSettingsRemovePassword(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/profile.schema.json")),
+		configx.WithValues(testhelpers.MethodEnableConfig(identity.CredentialsTypePassword, true)),
+		configx.WithValues(testhelpers.MethodEnableConfig(settings.StrategyProfile, true)),
+		configx.WithValue(config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1m"),
+	)
+
+	_ = testhelpers.NewSettingsUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	publicTS, _ := testhelpers.NewKratosServer(t, reg)
+
+	removeNode := func(t *testing.T, f *kratos.SettingsFlow) gjson.Result {
+		raw, err := json.Marshal(f.Ui.Nodes)
+		require.NoError(t, err)
+		return gjson.GetBytes(raw, "#(attributes.name==password_remove)")
+	}
+
+	t.Run("case=removes the password if another first factor remains", func(t *testing.T) {
+		id := newIdentityWithPassword(testhelpers.RandomEmail())
+		oidc, err := identity.NewCredentialsOIDC(&identity.CredentialsOIDCEncryptedTokens{}, "github", x.NewUUID().String(), "")
+		require.NoError(t, err)
+		id.SetCredentials(identity.CredentialsTypeOIDC, *oidc)
+		apiUser := testhelpers.NewHTTPClientWithIdentitySessionToken(t.Context(), t, reg, id)
+
+		f := testhelpers.InitializeSettingsFlowViaAPI(t, apiUser, publicTS)
+		n := removeNode(t, f)
+		require.True(t, n.Exists())
+		assert.Equal(t, "password", n.Get("group").String())
+		assert.Equal(t, "submit", n.Get("attributes.type").String())
+
+		actual, res := testhelpers.SettingsMakeRequest(t, true, false, f, apiUser, `{"method":"password","password_remove":true}`)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", actual)
+		assert.EqualValues(t, flow.StateSuccess, gjson.Get(actual, "state").String(), "%s", actual)
+
+		actualIdentity, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), id.ID)
+		require.NoError(t, err)
+		// The identity schema still declares the identifier, so only the hash is gone.
+		if c, ok := actualIdentity.GetCredentials(identity.CredentialsTypePassword); ok {
+			assert.Empty(t, gjson.GetBytes(c.Config, "hashed_password").String())
+		}
+		_, ok := actualIdentity.GetCredentials(identity.CredentialsTypeOIDC)
+		assert.True(t, ok)
+	})
+
+	t.Run("case=does not remove the last first factor", func(t *testing.T) {
+		id := newIdentityWithPassword(testhelpers.RandomEmail())
+		apiUser := testhelpers.NewHTTPClientWithIdentitySessionToken(t.Context(), t, reg, id)
+
+		f := testhelpers.InitializeSettingsFlowViaAPI(t, apiUser, publicTS)
+		assert.False(t, removeNode(t, f).Exists())
+
+		actual, res := testhelpers.SettingsMakeRequest(t, true, false, f, apiUser, `{"method":"password","password_remove":true}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", actual)
+		assert.Contains(t, actual, "last remaining first factor credential")
+
+		actualIdentity, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(t.Context(), id.ID)
+		require.NoError(t, err)
+		_, ok := actualIdentity.GetCredentials(identity.CredentialsTypePassword)
+		assert.True(t, ok)
+	})
+}
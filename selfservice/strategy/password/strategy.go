@@ -71,6 +71,7 @@ type registrationStrategyDependencies interface {
 	identity.PrivilegedPoolProvider
 	identity.ValidationProvider
 	identity.ManagementProvider
+	identity.LoginLockoutManagementProvider
 
 	session.HandlerProvider
 	session.ManagementProvider
@@ -7,7 +7,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/ory/kratos/x/nosurfx"
 
@@ -173,15 +172,15 @@ func (s *Strategy) continueFlow(ctx context.Context, r *http.Request, ctxUpdate
 	}
 
 	options := []identity.ManagerOption{identity.ManagerExposeValidationErrorsForInternalTypeAssertion}
-	ttl := s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)
-	if ctxUpdate.Session.AuthenticatedAt.Add(ttl).After(time.Now()) {
+	privileged := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session)
+	if privileged == nil {
 		options = append(options, identity.ManagerAllowWriteProtectedTraits)
 	}
 
 	update, err := s.d.IdentityManager().SetTraits(ctx, ctxUpdate.GetSessionIdentity().ID, identity.Traits(p.Traits), options...)
 	if err != nil {
-		if errors.Is(err, identity.ErrProtectedFieldModified) {
-			return settings.NewFlowNeedsReAuth()
+		if errors.Is(err, identity.ErrProtectedFieldModified) && privileged != nil {
+			return privileged
 		}
 		return err
 	}
@@ -3,7 +3,17 @@
 
 package saml
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/session"
+)
 
 // Update login flow using SAML
 //
@@ -29,3 +39,13 @@ type _ struct {
 	// required: false
 	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
 }
+
+func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow, sess *session.Session) (*identity.Identity, error) {
+	if p, err := s.peek(r); err != nil {
+		return nil, s.HandleError(r.Context(), w, r, f, "", nil, err)
+	} else if !s.isResponsible(r.Context(), p) {
+		return nil, errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	return s.Strategy.Login(w, r, f, sess)
+}
@@ -0,0 +1,385 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/urlx"
+
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+)
+
+const (
+	BindingRedirect = "redirect"
+	BindingPost     = "post"
+)
+
+var metadataCache, _ = ristretto.NewCache(&ristretto.Config[[]byte, []byte]{
+	MaxCost:     10 << 20, // 10MB
+	NumCounters: 10_000,
+	BufferItems: 64,
+})
+
+// Configuration is the configuration of a SAML 2.0 Identity Provider.
+type Configuration struct {
+	// ID is the provider's ID
+	ID string `json:"id"`
+
+	// Label represents an optional label which can be used in the UI generation.
+	Label string `json:"label"`
+
+	// Mapper specifies the JSONNet code snippet which uses the SAML assertion's attributes to hydrate the identity's
+	// data.
+	//
+	// It can be either a URL (file://, http(s)://, base64://) or an inline JSONNet code snippet.
+	Mapper string `json:"mapper_url"`
+
+	// An optional organization ID that this provider belongs to.
+	// This parameter is only effective in the Ory Network.
+	OrganizationID string `json:"organization_id"`
+
+	// IDPMetadataURL is the location of the Identity Provider's metadata document (file://, http(s)://, base64://).
+	// If set, IDPEntityID, IDPSSOURL, and IDPCertificate are ignored.
+	IDPMetadataURL string `json:"idp_metadata_url"`
+
+	// IDPEntityID is the Identity Provider's entity ID.
+	IDPEntityID string `json:"idp_entity_id"`
+
+	// IDPSSOURL is the Identity Provider's single sign-on URL.
+	IDPSSOURL string `json:"idp_sso_url"`
+
+	// IDPCertificate is the PEM encoded certificate the Identity Provider signs assertions with.
+	IDPCertificate string `json:"idp_certificate"`
+
+	// SPEntityID is the entity ID of this service provider. Defaults to the metadata URL.
+	SPEntityID string `json:"sp_entity_id"`
+
+	// SPCertificate is the PEM encoded certificate published in the service provider metadata.
+	SPCertificate string `json:"sp_certificate"`
+
+	// SPPrivateKey is the PEM encoded RSA private key used to sign authentication requests and to decrypt
+	// encrypted assertions.
+	SPPrivateKey string `json:"sp_private_key"`
+
+	// Binding is the binding used to send the authentication request. Either `redirect` (default) or `post`.
+	Binding string `json:"binding"`
+
+	// NameIDFormat is the NameID format requested from the Identity Provider.
+	NameIDFormat string `json:"name_id_format"`
+}
+
+type ConfigurationCollection struct {
+	BaseRedirectURI string          `json:"base_redirect_uri"`
+	Providers       []Configuration `json:"providers"`
+}
+
+var _ oidc.AuthURLProvider = (*Provider)(nil)
+
+// Provider is a SAML 2.0 Identity Provider.
+type Provider struct {
+	config *Configuration
+	reg    Dependencies
+}
+
+func NewProvider(config *Configuration, reg Dependencies) *Provider {
+	return &Provider{config: config, reg: reg}
+}
+
+func (p *Provider) Config() *oidc.Configuration {
+	return &oidc.Configuration{
+		ID:             p.config.ID,
+		Provider:       "saml",
+		Label:          p.config.Label,
+		Mapper:         p.config.Mapper,
+		OrganizationID: p.config.OrganizationID,
+	}
+}
+
+func (p *Provider) url(ctx context.Context, route string) *url.URL {
+	return urlx.AppendPaths(p.reg.Config().SAMLRedirectURIBase(ctx), strings.Replace(route, "{provider}", p.config.ID, 1))
+}
+
+// MetadataURL returns the URL of this service provider's metadata document.
+func (p *Provider) MetadataURL(ctx context.Context) *url.URL {
+	return p.url(ctx, RouteMetadata)
+}
+
+// ACSURL returns the URL of this service provider's Assertion Consumer Service.
+func (p *Provider) ACSURL(ctx context.Context) *url.URL {
+	return p.url(ctx, RouteACS)
+}
+
+// AuthURL returns the URL the browser is sent to in order to start the SAML flow.
+//
+// For the redirect binding, this is the Identity Provider's SSO URL including the
+// encoded authentication request. For the POST binding, this is an endpoint of
+// Ory Kratos which renders a self-submitting form.
+func (p *Provider) AuthURL(ctx context.Context, state string) (string, error) {
+	if p.config.Binding == BindingPost {
+		u := p.url(ctx, RouteAuth)
+		u.RawQuery = url.Values{"RelayState": {state}}.Encode()
+		return u.String(), nil
+	}
+
+	sp, err := p.ServiceProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := p.authnRequest(sp, saml.HTTPRedirectBinding, state)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := req.Redirect(state, sp)
+	if err != nil {
+		return "", errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to encode the SAML authentication request: %s", err))
+	}
+
+	return u.String(), nil
+}
+
+// PostForm returns the HTML form which submits the authentication request to the
+// Identity Provider using the POST binding.
+func (p *Provider) PostForm(ctx context.Context, state string) ([]byte, error) {
+	sp, err := p.ServiceProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := p.authnRequest(sp, saml.HTTPPostBinding, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return req.Post(state), nil
+}
+
+func (p *Provider) authnRequest(sp *saml.ServiceProvider, binding, state string) (*saml.AuthnRequest, error) {
+	location := sp.GetSSOBindingLocation(binding)
+	if location == "" {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("SAML Identity Provider %q does not support the %s binding.", p.config.ID, binding))
+	}
+
+	// The request is signed below, after the ID has been set.
+	req, err := sp.MakeAuthenticationRequest(location, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to create the SAML authentication request: %s", err))
+	}
+	req.ID = requestID(state)
+
+	if binding == saml.HTTPPostBinding && len(sp.SignatureMethod) > 0 {
+		if err := sp.SignAuthnRequest(req); err != nil {
+			return nil, errors.WithStack(herodot.ErrInternalServerError.WithReasonf("Unable to sign the SAML authentication request: %s", err))
+		}
+	}
+
+	return req, nil
+}
+
+// requestID derives the ID of the authentication request from the state. This
+// binds the response's InResponseTo attribute to the flow without storing the
+// request ID separately.
+func requestID(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return "id-" + hex.EncodeToString(sum[:])
+}
+
+// Claims validates the SAML response and converts the assertion into claims.
+func (p *Provider) Claims(ctx context.Context, samlResponse, state string) (*oidc.Claims, error) {
+	sp, err := p.ServiceProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest.WithReasonf("Unable to complete SAML flow because the SAMLResponse parameter is not valid base64.").WithDebug(err.Error()))
+	}
+
+	assertion, err := sp.ParseXMLResponse(raw, []string{requestID(state)}, sp.AcsURL)
+	if err != nil {
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) {
+			return nil, errors.WithStack(herodot.ErrUpstreamError.WithReasonf("The SAML response could not be validated.").WithDebug(invalid.PrivateErr.Error()))
+		}
+		return nil, errors.WithStack(herodot.ErrUpstreamError.WithReasonf("The SAML response could not be validated.").WithDebug(err.Error()))
+	}
+
+	return claimsFromAssertion(assertion), nil
+}
+
+func claimsFromAssertion(assertion *saml.Assertion) *oidc.Claims {
+	attributes := map[string][]string{}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]string, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+			attributes[attr.Name] = append(attributes[attr.Name], values...)
+			if attr.FriendlyName != "" && attr.FriendlyName != attr.Name {
+				attributes[attr.FriendlyName] = append(attributes[attr.FriendlyName], values...)
+			}
+		}
+	}
+
+	first := func(names ...string) string {
+		for _, name := range names {
+			if v := attributes[name]; len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+
+	raw := map[string]interface{}{"attributes": attributes}
+	claims := &oidc.Claims{
+		Issuer:     assertion.Issuer.Value,
+		Email:      first("email", "mail", "emailAddress", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress", "urn:oid:0.9.2342.19200300.100.1.3"),
+		GivenName:  first("givenName", "firstName", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname", "urn:oid:2.5.4.42"),
+		FamilyName: first("sn", "surname", "lastName", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname", "urn:oid:2.5.4.4"),
+		Name:       first("displayName", "name", "cn", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name", "urn:oid:2.16.840.1.113730.3.1.241"),
+		RawClaims:  raw,
+	}
+
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		claims.Subject = assertion.Subject.NameID.Value
+		raw["name_id"] = assertion.Subject.NameID.Value
+		raw["name_id_format"] = assertion.Subject.NameID.Format
+	}
+	for _, statement := range assertion.AuthnStatements {
+		if statement.SessionIndex != "" {
+			raw["session_index"] = statement.SessionIndex
+			break
+		}
+	}
+
+	return claims
+}
+
+// ServiceProvider returns the SAML service provider for this Identity Provider.
+func (p *Provider) ServiceProvider(ctx context.Context) (*saml.ServiceProvider, error) {
+	idp, err := p.idpMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataURL := p.MetadataURL(ctx)
+	sp := &saml.ServiceProvider{
+		EntityID:          p.config.SPEntityID,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *p.ACSURL(ctx),
+		IDPMetadata:       idp,
+		AuthnNameIDFormat: saml.NameIDFormat(p.config.NameIDFormat),
+		HTTPClient:        p.reg.HTTPClient(ctx).HTTPClient,
+	}
+	if sp.EntityID == "" {
+		sp.EntityID = metadataURL.String()
+	}
+	if sp.AuthnNameIDFormat == "" {
+		sp.AuthnNameIDFormat = saml.UnspecifiedNameIDFormat
+	}
+
+	if p.config.SPPrivateKey != "" || p.config.SPCertificate != "" {
+		pair, err := tls.X509KeyPair([]byte(p.config.SPCertificate), []byte(p.config.SPPrivateKey))
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to parse the service provider key pair of SAML provider %q: %s", p.config.ID, err))
+		}
+		key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("The service provider private key of SAML provider %q must be an RSA key.", p.config.ID))
+		}
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to parse the service provider certificate of SAML provider %q: %s", p.config.ID, err))
+		}
+		sp.Key = key
+		sp.Certificate = cert
+		sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+
+	return sp, nil
+}
+
+func (p *Provider) idpMetadata(ctx context.Context) (*saml.EntityDescriptor, error) {
+	if p.config.IDPMetadataURL != "" {
+		raw, err := fetcher.NewFetcher(
+			fetcher.WithClient(p.reg.HTTPClient(ctx)),
+			fetcher.WithCache(metadataCache, 10*time.Minute),
+		).FetchBytes(ctx, p.config.IDPMetadataURL)
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to fetch the metadata of SAML provider %q: %s", p.config.ID, err))
+		}
+		return parseMetadata(raw)
+	}
+
+	block, _ := pem.Decode([]byte(p.config.IDPCertificate))
+	if block == nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("The Identity Provider certificate of SAML provider %q is not PEM encoded.", p.config.ID))
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to parse the Identity Provider certificate of SAML provider %q: %s", p.config.ID, err))
+	}
+
+	return &saml.EntityDescriptor{
+		EntityID: p.config.IDPEntityID,
+		IDPSSODescriptors: []saml.IDPSSODescriptor{{
+			SSODescriptor: saml.SSODescriptor{
+				RoleDescriptor: saml.RoleDescriptor{
+					KeyDescriptors: []saml.KeyDescriptor{{
+						Use: "signing",
+						KeyInfo: saml.KeyInfo{X509Data: saml.X509Data{
+							X509Certificates: []saml.X509Certificate{{Data: base64.StdEncoding.EncodeToString(block.Bytes)}},
+						}},
+					}},
+				},
+			},
+			SingleSignOnServices: []saml.Endpoint{
+				{Binding: saml.HTTPRedirectBinding, Location: p.config.IDPSSOURL},
+				{Binding: saml.HTTPPostBinding, Location: p.config.IDPSSOURL},
+			},
+		}},
+	}, nil
+}
+
+// parseMetadata parses an EntityDescriptor or the first EntityDescriptor of an
+// EntitiesDescriptor which describes an Identity Provider.
+func parseMetadata(raw []byte) (*saml.EntityDescriptor, error) {
+	var entity saml.EntityDescriptor
+	if err := xml.Unmarshal(raw, &entity); err == nil && len(entity.IDPSSODescriptors) > 0 {
+		return &entity, nil
+	}
+
+	var entities saml.EntitiesDescriptor
+	if err := xml.Unmarshal(raw, &entities); err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to parse the SAML Identity Provider metadata: %s", err))
+	}
+	for i := range entities.EntityDescriptors {
+		if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+			return &entities.EntityDescriptors[i], nil
+		}
+	}
+
+	return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReason("The SAML metadata does not describe an Identity Provider."))
+}
@@ -3,7 +3,16 @@
 
 package saml
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/registration"
+)
 
 // Update registration flow using SAML
 //
@@ -32,3 +41,13 @@ type _ struct {
 	// required: false
 	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
 }
+
+func (s *Strategy) Register(w http.ResponseWriter, r *http.Request, f *registration.Flow, i *identity.Identity) error {
+	if p, err := s.peek(r); err != nil {
+		return s.HandleError(r.Context(), w, r, f, "", nil, err)
+	} else if !s.isResponsible(r.Context(), p) {
+		return errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	return s.Strategy.Register(w, r, f, i)
+}
@@ -3,7 +3,17 @@
 
 package saml
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+)
 
 // Update settings flow using SAML
 //
@@ -50,3 +60,20 @@ type _ struct {
 	// required: false
 	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
 }
+
+func (s *Strategy) Settings(ctx context.Context, w http.ResponseWriter, r *http.Request, f *settings.Flow, ss *session.Session) (*settings.UpdateContext, error) {
+	p, err := s.peek(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests without any payload may resume a previous SAML action after the
+	// session has been refreshed, which the embedded strategy detects using the
+	// continuity container.
+	resume := p.Method == "" && p.Link == "" && p.Unlink == ""
+	if !resume && !s.isResponsible(ctx, p) {
+		return nil, errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	return s.Strategy.Settings(ctx, w, r, f, ss)
+}
@@ -0,0 +1,385 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+
+	"github.com/ory/kratos/continuity"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/strategy"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+)
+
+const (
+	RouteBase = "/self-service/methods/saml"
+
+	RouteMetadata = RouteBase + "/metadata/{provider}"
+	RouteAuth     = RouteBase + "/auth/{provider}"
+	RouteACS      = RouteBase + "/acs/{provider}"
+)
+
+var _ identity.ActiveCredentialsCounter = new(Strategy)
+
+//go:embed .schema/peek.schema.json
+var peekSchema []byte
+
+var dec = decoderx.NewHTTP()
+
+type Dependencies interface {
+	oidc.Dependencies
+	continuity.PersistenceProvider
+}
+
+// Strategy implements SAML 2.0 Web Browser SSO for login, registration, and
+// settings. It reuses the OpenID Connect strategy for everything that happens
+// after the assertion has been validated.
+type Strategy struct {
+	*oidc.Strategy
+	d Dependencies
+}
+
+func NewStrategy(d Dependencies) *Strategy {
+	s := &Strategy{d: d}
+	s.Strategy = oidc.NewStrategy(d,
+		oidc.ForCredentialType(identity.CredentialsTypeSAML),
+		oidc.WithProviderResolver(func(ctx context.Context, id string) (oidc.Provider, error) {
+			return s.provider(ctx, id)
+		}),
+	)
+	return s
+}
+
+func (s *Strategy) config(ctx context.Context) (*ConfigurationCollection, error) {
+	var c ConfigurationCollection
+
+	conf := s.d.Config().SelfServiceStrategy(ctx, s.ID().String()).Config
+	if err := json.
+		NewDecoder(bytes.NewBuffer(conf)).
+		Decode(&c); err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration.WithReasonf("Unable to decode SAML Identity Provider configuration: %s", err))
+	}
+
+	return &c, nil
+}
+
+func (s *Strategy) provider(ctx context.Context, id string) (*Provider, error) {
+	c, err := s.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range c.Providers {
+		if c.Providers[k].ID == id {
+			return NewProvider(&c.Providers[k], s.d), nil
+		}
+	}
+
+	return nil, errors.WithStack(herodot.ErrNotFound.WithReasonf(`SAML Identity Provider "%s" is unknown or has not been configured`, id))
+}
+
+// isProvider returns true if the SAML method is enabled and a SAML Identity
+// Provider with the given ID is configured.
+func (s *Strategy) isProvider(ctx context.Context, id string) bool {
+	if id == "" || !s.d.Config().SelfServiceStrategy(ctx, s.ID().String()).Enabled {
+		return false
+	}
+	_, err := s.provider(ctx, id)
+	return err == nil
+}
+
+type peekPayload struct {
+	Method   string `json:"method"`
+	Provider string `json:"provider"`
+	Link     string `json:"link"`
+	Unlink   string `json:"unlink"`
+}
+
+// peek decodes the method and provider fields of the request without
+// consuming the request body.
+func (s *Strategy) peek(r *http.Request) (*peekPayload, error) {
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(peekSchema)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var p peekPayload
+	if err := dec.Decode(r, &p, compiler,
+		decoderx.HTTPKeepRequestBody(true),
+		decoderx.HTTPDecoderAllowedMethods("POST", "PUT", "PATCH", "GET"),
+		decoderx.HTTPDecoderSetValidatePayloads(false),
+		decoderx.HTTPDecoderJSONFollowsFormFormat()); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &p, nil
+}
+
+// isResponsible decides whether the request is meant for a SAML Identity
+// Provider. The OpenID Connect strategy uses the same payload, so a request
+// without a method is only handled here if it references a SAML Identity
+// Provider.
+func (s *Strategy) isResponsible(ctx context.Context, p *peekPayload) bool {
+	switch p.Method {
+	case s.SettingsStrategyID():
+		return true
+	case "":
+		return s.isProvider(ctx, p.Provider) || s.isProvider(ctx, p.Link) || s.isProvider(ctx, p.Unlink)
+	default:
+		return false
+	}
+}
+
+func (s *Strategy) RegisterPublicRoutes(r *httprouterx.RouterPublic) {
+	r.GET(RouteMetadata, strategy.IsDisabled(s.d, s.ID().String(), s.handleMetadata))
+	r.GET(RouteAuth, strategy.IsDisabled(s.d, s.ID().String(), s.handleAuth))
+
+	wrappedHandleACS := strategy.IsDisabled(s.d, s.ID().String(), s.handleACS)
+	r.GET(RouteACS, wrappedHandleACS)
+
+	// The Identity Provider posts the SAML response to the ACS. Because the
+	// continuity cookie is not sent along with cross-site POST requests, the
+	// response is stored and we redirect to the same location using GET first.
+	s.d.CSRFHandler().IgnoreGlob(RouteBase + "/acs/*")
+	r.POST(RouteACS, strategy.IsDisabled(s.d, s.ID().String(), s.handleACSPost))
+}
+
+func (s *Strategy) RegisterAdminRoutes(*httprouterx.RouterAdmin) {}
+
+func (s *Strategy) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, err := s.provider(ctx, r.PathValue("provider"))
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	sp, err := p.ServiceProvider(ctx)
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	out, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		s.d.Writer().WriteError(w, r, errors.WithStack(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	_, _ = w.Write(out)
+}
+
+// handleAuth renders the self-submitting form of the POST binding.
+func (s *Strategy) handleAuth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	relayState := r.URL.Query().Get("RelayState")
+	state, err := oidc.DecryptState(ctx, s.d.Cipher(ctx), relayState)
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to start SAML flow because the RelayState parameter is invalid.`)))
+		return
+	}
+
+	if state.ProviderId != r.PathValue("provider") {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to start SAML flow: provider mismatch between internal state and URL.`)))
+		return
+	}
+
+	p, err := s.provider(ctx, state.ProviderId)
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	form, err := p.PostForm(ctx, relayState)
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte(`<!DOCTYPE html><html><body>`))
+	_, _ = w.Write(form)
+	_, _ = w.Write([]byte(`</body></html>`))
+}
+
+// acsResponseContainerName is the name of the continuity containers which
+// hold SAML responses between the POST and the GET request to the ACS.
+const acsResponseContainerName = "saml_acs_response"
+
+// acsResponseLifespan is how long a posted SAML response is kept.
+const acsResponseLifespan = 5 * time.Minute
+
+var acsResponseNamespace = uuid.Must(uuid.FromString("f5f5c8f7-1670-4939-8ffe-0d04b84183a4"))
+
+type acsResponsePayload struct {
+	SAMLResponse string `json:"saml_response"`
+}
+
+// acsResponseContainerID returns the ID of the continuity container which
+// holds the SAML response posted along with the given RelayState.
+func acsResponseContainerID(relayState string) uuid.UUID {
+	return uuid.NewV5(acsResponseNamespace, relayState)
+}
+
+// handleACSPost stores the SAML response posted by the Identity Provider and
+// redirects to the ACS using GET, with only the RelayState in the URL. This
+// keeps the signed assertion out of URLs, which are size limited and end up
+// in logs and the browser history.
+func (s *Strategy) handleACSPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	relayState := r.PostFormValue("RelayState")
+	if relayState == "" {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the Identity Provider did not return the RelayState parameter.`)))
+		return
+	}
+
+	// Only responses to flows started by this server are stored.
+	state, err := oidc.DecryptState(ctx, s.d.Cipher(ctx), relayState)
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the state parameter is invalid.`)))
+		return
+	} else if state.ProviderId != r.PathValue("provider") {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow: provider mismatch between internal state and URL.`)))
+		return
+	}
+
+	payload, err := json.Marshal(acsResponsePayload{SAMLResponse: r.PostFormValue("SAMLResponse")})
+	if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(err))
+		return
+	}
+
+	if err := s.d.ContinuityPersister().SaveContinuitySession(ctx, &continuity.Container{
+		ID:        acsResponseContainerID(relayState),
+		Name:      acsResponseContainerName,
+		ExpiresAt: time.Now().UTC().Add(acsResponseLifespan).Truncate(time.Second),
+		Payload:   sqlxx.NullJSONRawMessage(payload),
+	}); errors.Is(err, sqlcon.ErrUniqueViolation) {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the SAML response was already submitted.`)))
+		return
+	} else if err != nil {
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, err)
+		return
+	}
+
+	publicURL := s.d.Config().SelfPublicURL(ctx)
+	dest := *publicURL
+	dest.Path = path.Join(publicURL.Path, r.URL.Path)
+	dest.RawQuery = url.Values{"RelayState": {relayState}}.Encode()
+	http.Redirect(w, r, dest.String(), http.StatusSeeOther)
+}
+
+// acsResponse returns the SAML response which was posted along with the
+// RelayState and removes it, so that it can only be used once. It returns an
+// empty string if no response was posted or if it expired.
+func (s *Strategy) acsResponse(ctx context.Context, relayState string) (string, error) {
+	id := acsResponseContainerID(relayState)
+	c, err := s.d.ContinuityPersister().GetContinuitySession(ctx, id)
+	if errors.Is(err, sqlcon.ErrNoRows) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if err := s.d.ContinuityPersister().DeleteContinuitySession(ctx, id); errors.Is(err, sqlcon.ErrNoRows) {
+		// The response was used by a concurrent request.
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if c.Name != acsResponseContainerName || c.Valid(uuid.Nil) != nil {
+		return "", nil
+	}
+
+	var p acsResponsePayload
+	if err := json.Unmarshal(c.Payload, &p); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return p.SAMLResponse, nil
+}
+
+func (s *Strategy) handleACS(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx := r.Context()
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.saml.Strategy.handleACS")
+	defer otelx.End(span, &err)
+	r = r.WithContext(ctx)
+
+	relayState := r.URL.Query().Get("RelayState")
+	if relayState == "" {
+		err = errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the Identity Provider did not return the RelayState parameter.`))
+		s.d.SelfServiceErrorManager().Forward(ctx, w, r, s.HandleError(ctx, w, r, nil, "", nil, err))
+		return
+	}
+
+	req, state, cntnr, err := s.ValidateState(w, r, relayState)
+	if err != nil {
+		if req != nil {
+			s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		} else {
+			s.d.SelfServiceErrorManager().Forward(ctx, w, r, s.HandleError(ctx, w, r, nil, "", nil, err))
+		}
+		return
+	}
+
+	if authenticated, err := s.AlreadyAuthenticated(ctx, w, r, req); err != nil {
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		return
+	} else if authenticated {
+		return
+	}
+
+	p, err := s.provider(ctx, state.ProviderId)
+	if err != nil {
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		return
+	}
+
+	samlResponse, err := s.acsResponse(ctx, relayState)
+	if err != nil {
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		return
+	} else if samlResponse == "" {
+		err = errors.WithStack(herodot.ErrBadRequest.WithReasonf(`Unable to complete SAML flow because the Identity Provider did not return the SAMLResponse parameter.`))
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		return
+	}
+
+	claims, err := p.Claims(ctx, samlResponse, relayState)
+	if err != nil {
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		return
+	}
+
+	if err = claims.Validate(); err != nil {
+		s.ForwardError(ctx, w, r, req, s.HandleError(ctx, w, r, req, state.ProviderId, nil, err))
+		return
+	}
+
+	s.ProcessCallback(ctx, w, r, req, state, cntnr, nil, claims, p)
+}
@@ -0,0 +1,177 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/logger"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	samlstrategy "github.com/ory/kratos/selfservice/strategy/saml"
+)
+
+type testKeyPair struct {
+	key     *rsa.PrivateKey
+	cert    *x509.Certificate
+	keyPEM  string
+	certPEM string
+}
+
+func newKeyPair(t *testing.T) *testKeyPair {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "saml.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testKeyPair{
+		key:     key,
+		cert:    cert,
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+// testIDP is a SAML 2.0 Identity Provider which signs in whoever is set as
+// the current user.
+type testIDP struct {
+	*httptest.Server
+	keys *testKeyPair
+	user *saml.Session
+}
+
+var responseForm = template.Must(template.New("").Parse(
+	`<form method="post" action="{{.URL}}">` +
+		`<input type="hidden" name="SAMLResponse" value="{{.SAMLResponse}}" />` +
+		`<input type="hidden" name="RelayState" value="{{.RelayState}}" />` +
+		`</form>`))
+
+func newTestIDP(t *testing.T) *testIDP {
+	idp := &testIDP{keys: newKeyPair(t)}
+	mux := http.NewServeMux()
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	provider := &saml.IdentityProvider{
+		Key:                     idp.keys.key,
+		Certificate:             idp.keys.cert,
+		Logger:                  logger.DefaultLogger,
+		MetadataURL:             *urlMustParse(t, idp.URL+"/metadata"),
+		SSOURL:                  *urlMustParse(t, idp.URL+"/sso"),
+		ServiceProviderProvider: serviceProviderProvider{},
+		SessionProvider:         idp,
+		ResponseFormTemplate:    responseForm,
+	}
+	mux.Handle("/", provider.Handler())
+
+	return idp
+}
+
+func (i *testIDP) GetSession(w http.ResponseWriter, _ *http.Request, _ *saml.IdpAuthnRequest) *saml.Session {
+	if i.user == nil {
+		http.Error(w, "no user signed in", http.StatusForbidden)
+		return nil
+	}
+	s := *i.user
+	s.ID = fmt.Sprintf("session-%d", time.Now().UnixNano())
+	s.CreateTime = time.Now()
+	s.ExpireTime = time.Now().Add(time.Hour)
+	s.Index = s.ID
+	return &s
+}
+
+func (i *testIDP) signIn(email string) {
+	i.user = &saml.Session{
+		NameID:        email,
+		NameIDFormat:  string(saml.EmailAddressNameIDFormat),
+		UserEmail:     email,
+		UserGivenName: "Jane",
+		UserSurname:   "Doe",
+		Groups:        []string{"staff", "admin"},
+	}
+}
+
+// serviceProviderProvider loads the service provider metadata from Ory
+// Kratos, using the entity ID as the metadata URL.
+type serviceProviderProvider struct{}
+
+func (serviceProviderProvider) GetServiceProvider(_ *http.Request, serviceProviderID string) (*saml.EntityDescriptor, error) {
+	res, err := http.Get(serviceProviderID) //nolint:gosec // test code
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var ed saml.EntityDescriptor
+	if err := xml.NewDecoder(res.Body).Decode(&ed); err != nil {
+		return nil, err
+	}
+	return &ed, nil
+}
+
+func urlMustParse(t *testing.T, u string) *url.URL {
+	parsed, err := url.Parse(u)
+	require.NoError(t, err)
+	return parsed
+}
+
+func viperSetProviderConfig(t *testing.T, conf *config.Config, providers ...samlstrategy.Configuration) {
+	ctx := context.Background()
+	baseKey := fmt.Sprintf("%s.%s", config.ViperKeySelfServiceStrategyConfig, identity.CredentialsTypeSAML)
+
+	conf.MustSet(ctx, baseKey+".config", &samlstrategy.ConfigurationCollection{Providers: providers})
+	conf.MustSet(ctx, baseKey+".enabled", true)
+}
+
+var (
+	formActionRegexp = regexp.MustCompile(`<form method="post" action="([^"]*)"`)
+	formInputRegexp  = regexp.MustCompile(`<input type="hidden" name="([^"]*)" value="([^"]*)"`)
+)
+
+// parseForm extracts the action and hidden fields of the self-submitting
+// forms used by the SAML POST binding.
+func parseForm(t *testing.T, res *http.Response) (string, url.Values) {
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+
+	action := formActionRegexp.FindSubmatch(body)
+	require.Len(t, action, 2, "%s", body)
+
+	values := url.Values{}
+	for _, m := range formInputRegexp.FindAllSubmatch(body, -1) {
+		values.Set(html.UnescapeString(string(m[1])), html.UnescapeString(string(m[2])))
+	}
+	return html.UnescapeString(string(action[1])), values
+}
@@ -0,0 +1,346 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package saml_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/crewjam/saml"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	samlstrategy "github.com/ory/kratos/selfservice/strategy/saml"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlxx"
+)
+
+func TestStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/registration.schema.json")),
+		configx.WithValues(map[string]any{
+			config.HookStrategyKey(config.ViperKeySelfServiceRegistrationAfter, identity.CredentialsTypeSAML.String()): []config.SelfServiceHook{{Name: "session"}},
+		}),
+	)
+
+	idp := newTestIDP(t)
+	untrusted := newKeyPair(t)
+	sp := newKeyPair(t)
+
+	_ = testhelpers.NewRegistrationUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewLoginUIFlowEchoServer(t, reg)
+	settingsUI := testhelpers.NewSettingsUIFlowEchoServer(t, reg)
+	errTS := testhelpers.NewErrorTestServer(t, reg)
+	returnTS := testhelpers.NewRedirSessionEchoTS(t, reg)
+	ts, _ := testhelpers.NewKratosServer(t, reg)
+
+	viperSetProviderConfig(t, conf,
+		samlstrategy.Configuration{
+			ID:             "idp",
+			Label:          "Test IdP",
+			Mapper:         "file://./stub/saml.jsonnet",
+			IDPMetadataURL: idp.URL + "/metadata",
+		},
+		samlstrategy.Configuration{
+			ID:             "idp-post",
+			Mapper:         "file://./stub/saml.jsonnet",
+			IDPMetadataURL: idp.URL + "/metadata",
+			Binding:        samlstrategy.BindingPost,
+		},
+		samlstrategy.Configuration{
+			ID:             "idp-signed",
+			Mapper:         "file://./stub/saml.jsonnet",
+			IDPMetadataURL: idp.URL + "/metadata",
+			SPCertificate:  sp.certPEM,
+			SPPrivateKey:   sp.keyPEM,
+		},
+		samlstrategy.Configuration{
+			ID:             "idp-static",
+			Mapper:         "file://./stub/saml.jsonnet",
+			IDPEntityID:    idp.URL + "/metadata",
+			IDPSSOURL:      idp.URL + "/sso",
+			IDPCertificate: idp.keys.certPEM,
+		},
+		samlstrategy.Configuration{
+			ID:             "idp-untrusted",
+			Mapper:         "file://./stub/saml.jsonnet",
+			IDPEntityID:    idp.URL + "/metadata",
+			IDPSSOURL:      idp.URL + "/sso",
+			IDPCertificate: untrusted.certPEM,
+		},
+	)
+
+	newClient := func(t *testing.T) *http.Client {
+		return testhelpers.NewClientWithCookieJar(t, nil, nil)
+	}
+
+	// submit starts the SAML flow and returns the response of the Identity
+	// Provider, which contains the form posting the SAML response to Ory Kratos.
+	submit := func(t *testing.T, client *http.Client, action string, values url.Values) *http.Response {
+		res, err := client.PostForm(action, values)
+		require.NoError(t, err)
+
+		if strings.HasPrefix(res.Request.URL.Path, samlstrategy.RouteBase+"/auth/") {
+			// POST binding: submit the auto-submitting form to the Identity Provider.
+			action, values := parseForm(t, res)
+			assert.NotEmpty(t, values.Get("SAMLRequest"))
+			res, err = client.PostForm(action, values)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, idp.URL+"/sso", res.Request.URL.Scheme+"://"+res.Request.URL.Host+res.Request.URL.Path)
+		return res
+	}
+
+	// acs posts the SAML response of the Identity Provider to Ory Kratos.
+	acs := func(t *testing.T, client *http.Client, idpResponse *http.Response, modify func(url.Values)) (*http.Response, []byte) {
+		action, values := parseForm(t, idpResponse)
+		require.True(t, strings.HasPrefix(action, ts.URL+samlstrategy.RouteBase+"/acs/"), action)
+		if modify != nil {
+			modify(values)
+		}
+
+		res, err := client.PostForm(action, values)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res, body
+	}
+
+	register := func(t *testing.T, client *http.Client, provider string) (*http.Response, []byte) {
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, ts, false, false, false)
+		return acs(t, client, submit(t, client, f.Ui.Action, url.Values{"provider": {provider}}), nil)
+	}
+
+	assertSession := func(t *testing.T, res *http.Response, body []byte, email string) {
+		require.Contains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+		assert.Equal(t, email, gjson.GetBytes(body, "identity.traits.email").String(), "%s", body)
+		assert.Equal(t, identity.CredentialsTypeSAML.String(), gjson.GetBytes(body, "authentication_methods.0.method").String(), "%s", body)
+	}
+
+	t.Run("case=serves the service provider metadata", func(t *testing.T) {
+		res, err := ts.Client().Get(ts.URL + "/self-service/methods/saml/metadata/idp-signed")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/samlmetadata+xml", res.Header.Get("Content-Type"))
+
+		var ed saml.EntityDescriptor
+		require.NoError(t, xml.NewDecoder(res.Body).Decode(&ed))
+		assert.Equal(t, ts.URL+"/self-service/methods/saml/metadata/idp-signed", ed.EntityID)
+		require.Len(t, ed.SPSSODescriptors, 1)
+		require.Len(t, ed.SPSSODescriptors[0].AssertionConsumerServices, 2)
+		assert.Equal(t, ts.URL+"/self-service/methods/saml/acs/idp-signed", ed.SPSSODescriptors[0].AssertionConsumerServices[0].Location)
+		assert.True(t, *ed.SPSSODescriptors[0].AuthnRequestsSigned)
+		assert.NotEmpty(t, ed.SPSSODescriptors[0].KeyDescriptors)
+
+		t.Run("case=unknown provider", func(t *testing.T) {
+			res, err := ts.Client().Get(ts.URL + "/self-service/methods/saml/metadata/unknown")
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	})
+
+	t.Run("case=renders the providers in the saml group", func(t *testing.T) {
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, newClient(t), ts, false, false, false, false)
+		raw, err := json.Marshal(f.Ui.Nodes)
+		require.NoError(t, err)
+
+		var providers []string
+		for _, n := range gjson.ParseBytes(raw).Array() {
+			if n.Get("group").String() == node.SAMLGroup.String() && n.Get("attributes.name").String() == "provider" {
+				providers = append(providers, n.Get("attributes.value").String())
+			}
+		}
+		assert.ElementsMatch(t, []string{"idp", "idp-post", "idp-signed", "idp-static", "idp-untrusted"}, providers, "%s", raw)
+	})
+
+	for _, provider := range []string{"idp", "idp-post", "idp-signed", "idp-static"} {
+		t.Run("provider="+provider, func(t *testing.T) {
+			email := testhelpers.RandomEmail()
+			idp.signIn(email)
+
+			t.Run("case=registers a new identity", func(t *testing.T) {
+				res, body := register(t, newClient(t), provider)
+				assertSession(t, res, body, email)
+				assert.Equal(t, "Jane", gjson.GetBytes(body, "identity.traits.name").String(), "%s", body)
+				assert.Equal(t, `["staff","admin"]`, gjson.GetBytes(body, "identity.traits.groups").Raw, "%s", body)
+
+				i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, uuid.FromStringOrNil(gjson.GetBytes(body, "identity.id").String()))
+				require.NoError(t, err)
+				c, ok := i.GetCredentials(identity.CredentialsTypeSAML)
+				require.True(t, ok)
+				assert.Equal(t, []string{provider + ":" + email}, c.Identifiers)
+			})
+
+			t.Run("case=signs in the existing identity", func(t *testing.T) {
+				client := newClient(t)
+				f := testhelpers.InitializeLoginFlowViaBrowser(t, client, ts, false, false, false, false)
+				res, body := acs(t, client, submit(t, client, f.Ui.Action, url.Values{"provider": {provider}}), nil)
+				assertSession(t, res, body, email)
+			})
+
+			t.Run("case=accepts the method field", func(t *testing.T) {
+				client := newClient(t)
+				f := testhelpers.InitializeLoginFlowViaBrowser(t, client, ts, false, false, false, false)
+				res, body := acs(t, client, submit(t, client, f.Ui.Action, url.Values{"provider": {provider}, "method": {"saml"}}), nil)
+				assertSession(t, res, body, email)
+			})
+		})
+	}
+
+	t.Run("case=rejects assertions signed by an untrusted key", func(t *testing.T) {
+		idp.signIn(testhelpers.RandomEmail())
+		res, body := register(t, newClient(t), "idp-untrusted")
+		assert.NotContains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+		assert.Contains(t, string(body), "The SAML response could not be validated.", "%s", body)
+	})
+
+	t.Run("case=rejects tampered assertions", func(t *testing.T) {
+		email := testhelpers.RandomEmail()
+		idp.signIn(email)
+
+		client := newClient(t)
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, ts, false, false, false)
+		res, body := acs(t, client, submit(t, client, f.Ui.Action, url.Values{"provider": {"idp"}}), func(v url.Values) {
+			raw, err := base64.StdEncoding.DecodeString(v.Get("SAMLResponse"))
+			require.NoError(t, err)
+			tampered := strings.ReplaceAll(string(raw), email, "attacker@ory.sh")
+			require.NotEqual(t, string(raw), tampered)
+			v.Set("SAMLResponse", base64.StdEncoding.EncodeToString([]byte(tampered)))
+		})
+		assert.NotContains(t, res.Request.URL.String(), returnTS.URL, "%s", body)
+		assert.Contains(t, string(body), "The SAML response could not be validated.", "%s", body)
+	})
+
+	t.Run("case=rejects responses without the continuity cookie", func(t *testing.T) {
+		idp.signIn(testhelpers.RandomEmail())
+
+		client := newClient(t)
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, ts, false, false, false)
+		idpResponse := submit(t, client, f.Ui.Action, url.Values{"provider": {"idp"}})
+
+		res, body := acs(t, newClient(t), idpResponse, nil)
+		assert.Contains(t, res.Request.URL.String(), errTS.URL, "%s", body)
+	})
+
+	t.Run("case=rejects responses with a foreign relay state", func(t *testing.T) {
+		idp.signIn(testhelpers.RandomEmail())
+
+		client := newClient(t)
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, ts, false, false, false)
+		res, body := acs(t, client, submit(t, client, f.Ui.Action, url.Values{"provider": {"idp"}}), func(v url.Values) {
+			v.Set("RelayState", "not-a-state")
+		})
+		assert.Contains(t, res.Request.URL.String(), errTS.URL, "%s", body)
+		assert.Contains(t, gjson.GetBytes(body, "reason").String(), "state parameter is invalid", "%s", body)
+	})
+
+	t.Run("case=keeps the SAML response out of the redirect URL", func(t *testing.T) {
+		email := testhelpers.RandomEmail()
+		idp.signIn(email)
+
+		client := newClient(t)
+		f := testhelpers.InitializeRegistrationFlowViaBrowser(t, client, ts, false, false, false)
+		action, values := parseForm(t, submit(t, client, f.Ui.Action, url.Values{"provider": {"idp"}}))
+
+		noRedirects := *client
+		noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+		res, err := noRedirects.PostForm(action, values)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusSeeOther, res.StatusCode)
+
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, url.Values{"RelayState": {values.Get("RelayState")}}, location.Query())
+
+		// Posting the same response again before it was used is rejected.
+		res, err = noRedirects.PostForm(action, values)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.True(t, strings.HasPrefix(res.Header.Get("Location"), errTS.URL), "%s", res.Header.Get("Location"))
+
+		res, err = client.Get(location.String())
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assertSession(t, res, body, email)
+	})
+
+	t.Run("case=does not handle unknown providers", func(t *testing.T) {
+		client := newClient(t)
+		f := testhelpers.InitializeLoginFlowViaBrowser(t, client, ts, false, false, false, false)
+		res, err := client.PostForm(f.Ui.Action, url.Values{"provider": {"unknown"}, "method": {"saml"}})
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Contains(t, res.Request.URL.String(), errTS.URL, "%s", body)
+		assert.EqualValues(t, http.StatusNotFound, gjson.GetBytes(body, "code").Int(), "%s", body)
+		assert.Contains(t, gjson.GetBytes(body, "reason").String(), `SAML Identity Provider "unknown" is unknown or has not been configured`, "%s", body)
+	})
+
+	t.Run("case=links the provider in the settings flow", func(t *testing.T) {
+		email := testhelpers.RandomEmail()
+		i := &identity.Identity{
+			Traits:   identity.Traits(fmt.Sprintf(`{"email":%q}`, email)),
+			SchemaID: config.DefaultIdentityTraitsSchemaID,
+			State:    identity.StateActive,
+			Credentials: map[identity.CredentialsType]identity.Credentials{
+				identity.CredentialsTypePassword: {
+					Type:        identity.CredentialsTypePassword,
+					Identifiers: []string{email},
+					Config:      sqlxx.JSONRawMessage(`{"hashed_password":"$argon2id$iammocked...."}`),
+				},
+			},
+		}
+		client := testhelpers.NewHTTPClientWithIdentitySessionCookie(ctx, t, reg, i)
+
+		f := testhelpers.InitializeSettingsFlowViaBrowser(t, client, false, ts)
+		raw, err := json.Marshal(f.Ui.Nodes)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"group":"saml"`)
+
+		idp.signIn(email)
+		res, body := acs(t, client, submit(t, client, f.Ui.Action, url.Values{"link": {"idp"}}), nil)
+		require.Contains(t, res.Request.URL.String(), settingsUI.URL, "%s", body)
+		assert.Equal(t, "success", gjson.GetBytes(body, "state").String(), "%s", body)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, i.ID)
+		require.NoError(t, err)
+		c, ok := actual.GetCredentials(identity.CredentialsTypeSAML)
+		require.True(t, ok)
+		assert.Equal(t, []string{"idp:" + email}, c.Identifiers)
+
+		t.Run("case=signs in with the linked provider", func(t *testing.T) {
+			client := newClient(t)
+			f := testhelpers.InitializeLoginFlowViaBrowser(t, client, ts, false, false, false, false)
+			res, body := acs(t, client, submit(t, client, f.Ui.Action, url.Values{"provider": {"idp"}}), nil)
+			assertSession(t, res, body, email)
+			assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "identity.id").String())
+		})
+	})
+}
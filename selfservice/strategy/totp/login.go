@@ -115,6 +115,10 @@ func (s *Strategy) Login(_ http.ResponseWriter, r *http.Request, f *login.Flow,
 		return nil, s.handleLoginError(r, f, err)
 	}
 
+	if err := s.d.LoginLockoutManager().Check(r, s.ID(), sess.IdentityID.String()); err != nil {
+		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(err, sess.IdentityID))
+	}
+
 	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), sess.IdentityID.String())
 	if err != nil {
 		return nil, s.handleLoginError(r, f, errors.WithStack(schema.NewNoTOTPDeviceRegistered()))
@@ -131,7 +135,11 @@ func (s *Strategy) Login(_ http.ResponseWriter, r *http.Request, f *login.Flow,
 	}
 
 	if !totp.Validate(p.TOTPCode, key.Secret()) {
-		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(errors.WithStack(schema.NewTOTPVerifierWrongError("#/")), i.ID))
+		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(s.d.LoginLockoutManager().RecordFailure(r, s.ID(), i.ID.String(), i.ID, errors.WithStack(schema.NewTOTPVerifierWrongError("#/"))), i.ID))
+	}
+
+	if err := s.d.LoginLockoutManager().RecordSuccess(r, s.ID(), i.ID.String()); err != nil {
+		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(err, i.ID))
 	}
 
 	f.Active = s.ID()
@@ -7,7 +7,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/ory/x/otelx"
 
@@ -140,8 +139,8 @@ func (s *Strategy) continueSettingsFlow(ctx context.Context, r *http.Request, ct
 		return err
 	}
 
-	if ctxUpdate.Session.AuthenticatedAt.Add(s.d.Config().SelfServiceFlowSettingsPrivilegedSessionMaxAge(ctx)).Before(time.Now()) {
-		return errors.WithStack(settings.NewFlowNeedsReAuth())
+	if err := settings.EnsurePrivilegedSession(ctx, s.d.Config(), ctxUpdate.Session); err != nil {
+		return err
 	}
 
 	hasTOTP, err := s.identityHasTOTP(ctx, ctxUpdate.Session.Identity)
@@ -66,6 +66,7 @@ type totpStrategyDependencies interface {
 	settings.ErrorHandlerProvider
 
 	identity.PrivilegedPoolProvider
+	identity.LoginLockoutManagementProvider
 	identity.ValidationProvider
 
 	session.HandlerProvider
@@ -32,7 +32,10 @@ import (
 	"github.com/ory/herodot"
 
 	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/urlx"
 )
 
 type (
@@ -46,6 +49,8 @@ type (
 		config.Provider
 		sessiontokenexchange.PersistenceProvider
 		TokenizerProvider
+		identity.PoolProvider
+		RevocationNotifierProvider
 	}
 	HandlerProvider interface {
 		SessionHandler() *Handler
@@ -73,9 +78,10 @@ const (
 )
 
 const (
-	AdminRouteIdentity           = "/identities"
-	AdminRouteIdentitiesSessions = AdminRouteIdentity + "/{id}/sessions"
-	AdminRouteSessionExtendId    = RouteSession + "/extend"
+	AdminRouteIdentity            = "/identities"
+	AdminRouteIdentitiesSessions  = AdminRouteIdentity + "/{id}/sessions"
+	AdminRouteSessionExtendId     = RouteSession + "/extend"
+	AdminRouteIdentityImpersonate = AdminRouteIdentity + "/{id}/impersonate"
 )
 
 func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
@@ -86,6 +92,7 @@ func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.GET(AdminRouteIdentitiesSessions, h.listIdentitySessions)
 	admin.DELETE(AdminRouteIdentitiesSessions, h.deleteIdentitySessions)
 	admin.PATCH(AdminRouteSessionExtendId, h.adminSessionExtend)
+	admin.POST(AdminRouteIdentityImpersonate, h.impersonateIdentity)
 
 	admin.DELETE(RouteCollection, redir.RedirectToPublicRoute(h.r))
 }
@@ -260,6 +267,12 @@ func (h *Handler) whoami(w http.ResponseWriter, r *http.Request) {
 	// Set userId as the X-Kratos-Authenticated-Identity-Id header.
 	w.Header().Set("X-Kratos-Authenticated-Identity-Id", s.Identity.ID.String())
 
+	// Set the administrator's ID as the X-Kratos-Impersonated-By header, so that
+	// proxies can log or block impersonated sessions.
+	if s.IsImpersonated() {
+		w.Header().Set("X-Kratos-Impersonated-By", s.Impersonation.ActorID)
+	}
+
 	// Set Cache header only when configured, and when no tokenization is requested.
 	if c.SessionWhoAmICaching(ctx) && len(tokenizeTemplate) == 0 {
 		expiry := time.Until(s.ExpiresAt)
@@ -320,6 +333,7 @@ func (h *Handler) deleteIdentitySessions(w http.ResponseWriter, r *http.Request)
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	h.r.SessionRevocationNotifier().NotifySessionsRevoked(r.Context(), r, iID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -695,6 +709,9 @@ func (h *Handler) deleteMySessions(w http.ResponseWriter, r *http.Request) {
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
+	if n > 0 {
+		h.r.SessionRevocationNotifier().NotifySessionsRevoked(r.Context(), r, s.IdentityID)
+	}
 
 	h.r.Writer().WriteCode(w, r, http.StatusOK, &deleteMySessionsCount{Count: n})
 }
@@ -881,6 +898,142 @@ func (h *Handler) IsAuthenticated(wrap http.HandlerFunc, onUnauthenticated http.
 	}
 }
 
+// Impersonate Identity Parameters
+//
+// swagger:parameters impersonateIdentity
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type impersonateIdentity struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	// required: true
+	Body impersonateIdentityBody
+}
+
+// Impersonate Identity Request Body
+//
+// swagger:model impersonateIdentityBody
+type impersonateIdentityBody struct {
+	// Actor ID
+	//
+	// The ID of the administrator who impersonates the identity. It is recorded on the session
+	// and in the audit trail.
+	//
+	// required: true
+	ActorID string `json:"actor_id"`
+
+	// Reason
+	//
+	// The reason for the impersonation, for example a support ticket reference.
+	Reason string `json:"reason"`
+}
+
+// Impersonated Session
+//
+// swagger:model impersonatedSession
+type impersonatedSession struct {
+	// The Session Token
+	//
+	// Use this token to call the public API as the impersonated identity.
+	//
+	// required: true
+	Token string `json:"session_token"`
+
+	// The impersonated session.
+	//
+	// required: true
+	Session *Session `json:"session"`
+}
+
+// swagger:route POST /admin/identities/{id}/impersonate identity impersonateIdentity
+//
+// # Impersonate an Identity
+//
+// This endpoint issues a short-lived session for the given identity so that an administrator, for example
+// a support agent, can see exactly what the user sees. The session lifespan is defined by
+// `session.impersonation.lifespan` and can not be extended.
+//
+// The session is flagged as impersonated and records the administrator's ID. Impersonated sessions are
+// not allowed to perform privileged settings changes, such as changing the password.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: impersonatedSession
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) impersonateIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	iID, err := uuid.FromString(r.PathValue("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithError(err.Error()).WithDebug("could not parse UUID")))
+		return
+	}
+
+	var p impersonateIdentityBody
+	if err := h.dx.Decode(r, &p, decoderx.HTTPJSONDecoder()); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if len(p.ActorID) == 0 {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReasonf(`The "actor_id" must be set to the ID of the administrator who impersonates the identity.`)))
+		return
+	}
+
+	i, err := h.r.IdentityPool().GetIdentity(ctx, iID, identity.ExpandDefault)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	s := NewInactiveSession()
+	s.CompletedLoginFor(identity.CredentialsTypeImpersonation, identity.AuthenticatorAssuranceLevel1)
+	if err := h.r.SessionManager().ActivateSession(r, s, i, time.Now().UTC()); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	s.ExpiresAt = s.AuthenticatedAt.Add(h.r.Config().SessionImpersonationLifespan(ctx))
+	s.Impersonation = &Impersonation{ActorID: p.ActorID, Reason: p.Reason}
+
+	if err := h.r.SessionPersister().UpsertSession(ctx, s); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	events.SpanFromContext(ctx).AddEvent(events.NewSessionImpersonated(ctx, s.ID, i.ID, p.ActorID))
+
+	h.r.Audit().
+		WithRequest(r).
+		WithField("identity_id", i.ID).
+		WithField("session_id", s.ID).
+		WithField("actor_id", p.ActorID).
+		WithField("reason", p.Reason).
+		Info("An administrator impersonated an identity.")
+
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(h.r.Config().SelfAdminURL(ctx), "sessions", s.ID.String()).String(),
+		&impersonatedSession{Token: s.Token, Session: s.Declassified()},
+	)
+}
+
 // swagger:parameters extendSession
 //
 //nolint:deadcode,unused
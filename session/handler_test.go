@@ -1096,6 +1096,87 @@ func TestHandlerRefreshSessionBySessionID(t *testing.T) {
 	})
 }
 
+func TestHandlerImpersonateIdentity(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValue(config.ViperKeySessionImpersonationLifespan, "10m"),
+	)
+	publicServer, adminServer, _, _ := testhelpers.NewKratosServerWithCSRFAndRouters(t, reg)
+
+	i := identity.NewIdentity("")
+	require.NoError(t, reg.IdentityManager().Create(t.Context(), i))
+
+	impersonate := func(t *testing.T, id, body string) (*http.Response, []byte) {
+		res, err := adminServer.Client().Post(adminServer.URL+"/admin/identities/"+id+"/impersonate", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		return res, ioutilx.MustReadAll(res.Body)
+	}
+
+	t.Run("case=should issue an impersonated session", func(t *testing.T) {
+		res, body := impersonate(t, i.ID.String(), `{"actor_id":"support-agent","reason":"ticket 123"}`)
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+
+		token := gjson.GetBytes(body, "session_token").String()
+		require.NotEmpty(t, token, "%s", body)
+		sid := uuid.FromStringOrNil(gjson.GetBytes(body, "session.id").String())
+		assert.Equal(t, urlx.AppendPaths(reg.Config().SelfAdminURL(t.Context()), "sessions", sid.String()).String(), res.Header.Get("Location"))
+		assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "session.identity.id").String(), "%s", body)
+		assert.Equal(t, "support-agent", gjson.GetBytes(body, "session.impersonation.actor_id").String(), "%s", body)
+		assert.Equal(t, "ticket 123", gjson.GetBytes(body, "session.impersonation.reason").String(), "%s", body)
+		assert.Equal(t, string(identity.CredentialsTypeImpersonation), gjson.GetBytes(body, "session.authentication_methods.0.method").String(), "%s", body)
+		assert.False(t, gjson.GetBytes(body, "session.identity.credentials").Exists(), "%s", body)
+
+		sess, err := reg.SessionPersister().GetSession(t.Context(), sid, ExpandNothing)
+		require.NoError(t, err)
+		require.True(t, sess.IsImpersonated())
+		assert.Equal(t, "support-agent", sess.Impersonation.ActorID)
+		assert.WithinDuration(t, sess.AuthenticatedAt.Add(10*time.Minute), sess.ExpiresAt, time.Second)
+
+		t.Run("whoami exposes the impersonation", func(t *testing.T) {
+			req := testhelpers.NewTestHTTPRequest(t, "GET", publicServer.URL+"/sessions/whoami", nil)
+			req.Header.Set("X-Session-Token", token)
+			res, err := publicServer.Client().Do(req)
+			require.NoError(t, err)
+			defer func() { _ = res.Body.Close() }()
+			body := ioutilx.MustReadAll(res.Body)
+
+			require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+			assert.Equal(t, "support-agent", res.Header.Get("X-Kratos-Impersonated-By"))
+			assert.Equal(t, "support-agent", gjson.GetBytes(body, "impersonation.actor_id").String(), "%s", body)
+		})
+
+		t.Run("session can not be extended", func(t *testing.T) {
+			req, _ := http.NewRequest("PATCH", adminServer.URL+"/admin/sessions/"+sid.String()+"/extend", nil)
+			res, err := adminServer.Client().Do(req)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			extended, err := reg.SessionPersister().GetSession(t.Context(), sid, ExpandNothing)
+			require.NoError(t, err)
+			assert.Equal(t, sess.ExpiresAt, extended.ExpiresAt)
+		})
+	})
+
+	t.Run("case=should require the actor id", func(t *testing.T) {
+		res, body := impersonate(t, i.ID.String(), `{"reason":"ticket 123"}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=should return 404 for unknown identities", func(t *testing.T) {
+		res, body := impersonate(t, x.NewUUID().String(), `{"actor_id":"support-agent"}`)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=should return 400 for malformed identity ids", func(t *testing.T) {
+		res, body := impersonate(t, "BADUUID", `{"actor_id":"support-agent"}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+	})
+}
+
 type byCreatedAt []Session
 
 func (s byCreatedAt) Len() int      { return len(s) }
@@ -266,7 +266,7 @@ func (s *ManagerHTTP) FetchFromRequest(ctx context.Context, r *http.Request) (_
 		return nil, err
 	}
 
-	trace.SpanFromContext(ctx).AddEvent(events.NewSessionChecked(ctx, se.ID, se.IdentityID))
+	events.SpanFromContext(ctx).AddEvent(events.NewSessionChecked(ctx, se.ID, se.IdentityID))
 
 	if !se.IsActive() {
 		return nil, errors.WithStack(NewErrNoActiveSessionFound())
@@ -311,6 +311,12 @@ func (s *ManagerHTTP) DoesSessionSatisfy(ctx context.Context, sess *Session, req
 		return nil
 	}
 
+	// A risky login needs a second factor if the identity has one, even if AAL1
+	// would be sufficient otherwise.
+	if requestedAAL == string(identity.AuthenticatorAssuranceLevel1) && sess.RequiresStepUp() {
+		requestedAAL = config.HighestAvailableAAL
+	}
+
 	managerOpts := &options{}
 	for _, o := range opts {
 		o(managerOpts)
@@ -421,7 +427,7 @@ func (s *ManagerHTTP) MaybeRedirectAPICodeFlow(w http.ResponseWriter, r *http.Re
 	ctx, span := s.r.Tracer(r.Context()).Tracer().Start(r.Context(), "sessions.ManagerHTTP.MaybeRedirectAPICodeFlow")
 	defer otelx.End(span, &err)
 
-	if uiNode != node.OpenIDConnectGroup {
+	if uiNode != node.OpenIDConnectGroup && uiNode != node.SAMLGroup {
 		return false, nil
 	}
 
@@ -478,7 +484,7 @@ func (s *ManagerHTTP) ActivateSession(r *http.Request, session *Session, i *iden
 	session.ExpiresAt = authenticatedAt.Add(s.r.Config().SessionLifespan(ctx))
 	session.AuthenticatedAt = authenticatedAt
 
-	session.SetSessionDeviceInformation(r.WithContext(ctx))
+	session.SetSessionDeviceInformation(r.WithContext(ctx), s.r.Config().SecurityTrustedProxies(ctx))
 	session.SetAuthenticatorAssuranceLevel()
 
 	span.SetAttributes(
@@ -1043,3 +1043,58 @@ func TestDoesSessionSatisfy(t *testing.T) {
 		})
 	}
 }
+
+func TestDoesSessionSatisfyRiskStepUp(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+	)
+	ctx := t.Context()
+
+	password := identity.Credentials{
+		Type:        identity.CredentialsTypePassword,
+		Identifiers: []string{testhelpers.RandomEmail()},
+		Config:      []byte(`{"hashed_password": "$argon2id$v=19$m=32,t=2,p=4$cm94YnRVOW5jZzFzcVE4bQ$MNzk5BtR2vUhrp6qQEjRNw"}`),
+	}
+	totp := identity.Credentials{
+		Type:   identity.CredentialsTypeTOTP,
+		Config: []byte(`{"totp_url": "otpauth://totp/..."}`),
+	}
+
+	newSession := func(t *testing.T, risk *session.RiskAssessment, creds ...identity.Credentials) *session.Session {
+		id := identity.NewIdentity("default")
+		for _, c := range creds {
+			c.Identifiers = []string{testhelpers.RandomEmail()}
+			id.SetCredentials(c.Type, c)
+		}
+		require.NoError(t, reg.IdentityManager().Create(ctx, id, identity.ManagerAllowWriteProtectedTraits))
+
+		s, err := testhelpers.NewActiveSession(testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil), reg, id, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		s.Risk = risk
+		return s
+	}
+
+	t.Run("case=aal1 is sufficient without step-up", func(t *testing.T) {
+		s := newSession(t, &session.RiskAssessment{Score: 20}, password, totp)
+		require.NoError(t, reg.SessionManager().DoesSessionSatisfy(ctx, s, "aal1"))
+	})
+
+	t.Run("case=step-up requires the second factor", func(t *testing.T) {
+		s := newSession(t, &session.RiskAssessment{Score: 80, StepUpRequired: true}, password, totp)
+		err := reg.SessionManager().DoesSessionSatisfy(ctx, s, "aal1")
+		require.ErrorAs(t, err, new(*session.ErrAALNotSatisfied))
+	})
+
+	t.Run("case=step-up is satisfied without second factor", func(t *testing.T) {
+		s := newSession(t, &session.RiskAssessment{Score: 80, StepUpRequired: true}, password)
+		require.NoError(t, reg.SessionManager().DoesSessionSatisfy(ctx, s, "aal1"))
+	})
+
+	t.Run("case=step-up is satisfied after the second factor", func(t *testing.T) {
+		s := newSession(t, &session.RiskAssessment{Score: 80, StepUpRequired: true}, password, totp)
+		s.CompletedLoginFor(identity.CredentialsTypeTOTP, identity.AuthenticatorAssuranceLevel2)
+		require.NoError(t, reg.SessionManager().DoesSessionSatisfy(ctx, s, "aal1"))
+	})
+}
@@ -0,0 +1,24 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+)
+
+type (
+	// RevocationNotifier is told when all sessions of an identity, or all but
+	// the current one, were revoked using the API.
+	RevocationNotifier interface {
+		// NotifySessionsRevoked informs the identity. Failures are logged and
+		// do not fail the request.
+		NotifySessionsRevoked(ctx context.Context, r *http.Request, identityID uuid.UUID)
+	}
+	RevocationNotifierProvider interface {
+		SessionRevocationNotifier() RevocationNotifier
+	}
+)
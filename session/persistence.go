@@ -72,6 +72,14 @@ type Persister interface {
 	RevokeSessionsIdentityExcept(ctx context.Context, iID, sID uuid.UUID) (int, error)
 }
 
+type DevicePersistenceProvider interface {
+	SessionDevicePersister() DevicePersister
+}
+
 type DevicePersister interface {
 	CreateDevice(ctx context.Context, d *Device) error
+
+	// ListDevicesByIdentity lists the most recent devices of all sessions of an
+	// identity which were created after the given time.
+	ListDevicesByIdentity(ctx context.Context, identityID uuid.UUID, since time.Time, limit int) ([]Device, error)
 }
@@ -0,0 +1,183 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/otelx"
+)
+
+// DeviceHistoryLimit is the number of most recent devices of an identity
+// which are compared with the device of a login.
+const DeviceHistoryLimit = 1000
+
+// A RiskSignal names an anomaly of a login's context.
+type RiskSignal string
+
+const (
+	// RiskSignalNewIPAddress is set if the identity never signed in from the
+	// IP address before.
+	RiskSignalNewIPAddress RiskSignal = "new_ip_address"
+
+	// RiskSignalNewUserAgent is set if the identity never signed in with the
+	// user agent before.
+	RiskSignalNewUserAgent RiskSignal = "new_user_agent"
+
+	// RiskSignalNewLocation is set if the identity never signed in from the
+	// geo location before.
+	RiskSignalNewLocation RiskSignal = "new_location"
+)
+
+// Session Risk Assessment
+//
+// The risk of the login which issued the session.
+//
+// swagger:model sessionRiskAssessment
+type RiskAssessment struct {
+	// The risk score between 0 (no risk) and 100.
+	//
+	// required: true
+	Score int `json:"score"`
+
+	// The anomalies found in the login context.
+	//
+	// required: true
+	Signals []RiskSignal `json:"signals"`
+
+	// StepUpRequired is true if the score reached the configured threshold. The
+	// session then needs a second factor, even if `aal1` would otherwise be
+	// sufficient.
+	//
+	// required: true
+	StepUpRequired bool `json:"step_up_required"`
+}
+
+// Scan implements the Scanner interface.
+func (n *RiskAssessment) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	v := fmt.Sprintf("%s", value)
+	if len(v) == 0 {
+		return nil
+	}
+	return errors.WithStack(json.Unmarshal([]byte(v), n))
+}
+
+// Value implements the driver Valuer interface.
+func (n RiskAssessment) Value() (driver.Value, error) {
+	value, err := json.Marshal(n)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return string(value), nil
+}
+
+type (
+	// RiskEvaluator scores the context of a login.
+	RiskEvaluator interface {
+		// EvaluateRisk scores the latest device of the session, which is the
+		// device of the login that is currently performed, against the
+		// devices the identity used before, most recent first.
+		EvaluateRisk(ctx context.Context, s *Session, history []Device) (*RiskAssessment, error)
+	}
+	RiskEvaluatorProvider interface {
+		SessionRiskEvaluator() RiskEvaluator
+	}
+
+	deviceHistoryRiskEvaluatorDependencies interface {
+		config.Provider
+		x.TracingProvider
+	}
+
+	// DeviceHistoryRiskEvaluator compares the device of a login with the
+	// devices the identity used before.
+	DeviceHistoryRiskEvaluator struct {
+		r deviceHistoryRiskEvaluatorDependencies
+	}
+)
+
+var _ RiskEvaluator = (*DeviceHistoryRiskEvaluator)(nil)
+
+func NewDeviceHistoryRiskEvaluator(r deviceHistoryRiskEvaluatorDependencies) *DeviceHistoryRiskEvaluator {
+	return &DeviceHistoryRiskEvaluator{r: r}
+}
+
+// EvaluateRisk adds the configured weight of every IP address, user agent, and
+// geo location which the identity did not use within the lookback period. The
+// first login of an identity has no history to compare with and therefore no
+// risk, whereas all signals are new to an identity which did not sign in within
+// the lookback period.
+func (e *DeviceHistoryRiskEvaluator) EvaluateRisk(ctx context.Context, s *Session, history []Device) (_ *RiskAssessment, err error) {
+	ctx, span := e.r.Tracer(ctx).Tracer().Start(ctx, "session.DeviceHistoryRiskEvaluator.EvaluateRisk")
+	defer otelx.End(span, &err)
+
+	conf := e.r.Config().SecurityRiskBasedStepUp(ctx)
+	assessment := &RiskAssessment{Signals: []RiskSignal{}}
+	if len(s.Devices) == 0 {
+		return assessment, nil
+	}
+	current := s.Devices[len(s.Devices)-1]
+
+	if len(history) == 0 {
+		return assessment, nil
+	}
+	since := time.Now().UTC().Add(-conf.Lookback)
+	history = slices.DeleteFunc(slices.Clone(history), func(d Device) bool {
+		return !d.CreatedAt.After(since)
+	})
+
+	for _, check := range []struct {
+		signal RiskSignal
+		weight int
+		value  func(Device) *string
+	}{
+		{RiskSignalNewIPAddress, conf.Weights.NewIPAddress, deviceIPAddress},
+		{RiskSignalNewUserAgent, conf.Weights.NewUserAgent, func(d Device) *string { return d.UserAgent }},
+		{RiskSignalNewLocation, conf.Weights.NewLocation, func(d Device) *string { return d.Location }},
+	} {
+		value := check.value(current)
+		if value == nil || *value == "" || seenBefore(history, *value, check.value) {
+			continue
+		}
+		assessment.Signals = append(assessment.Signals, check.signal)
+		assessment.Score += check.weight
+	}
+
+	assessment.Score = min(assessment.Score, 100)
+	assessment.StepUpRequired = assessment.Score >= conf.Threshold
+	return assessment, nil
+}
+
+func seenBefore(history []Device, value string, extract func(Device) *string) bool {
+	for _, d := range history {
+		if v := extract(d); v != nil && *v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceIPAddress returns the IP address of the device without the port, which
+// devices recorded before the trusted proxies were honored may include.
+func deviceIPAddress(d Device) *string {
+	if d.IPAddress == nil {
+		return nil
+	}
+	if host, _, err := net.SplitHostPort(*d.IPAddress); err == nil {
+		return &host
+	}
+	return d.IPAddress
+}
@@ -0,0 +1,172 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/session"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/contextx"
+	"github.com/ory/x/pointerx"
+)
+
+func TestDeviceHistoryRiskEvaluator(t *testing.T) {
+	t.Parallel()
+
+	_, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+	)
+	ctx := t.Context()
+
+	newDevice := func(ip, userAgent, location string) session.Device {
+		return session.Device{IPAddress: pointerx.Ptr(ip), UserAgent: pointerx.Ptr(userAgent), Location: pointerx.Ptr(location)}
+	}
+
+	newIdentity := func(t *testing.T, history ...session.Device) *identity.Identity {
+		id := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		require.NoError(t, reg.IdentityManager().Create(ctx, id))
+
+		if len(history) > 0 {
+			s, err := testhelpers.NewActiveSession(testhelpers.NewTestHTTPRequest(t, "GET", "/", nil), reg, id, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+			require.NoError(t, err)
+			s.Devices = history
+			require.NoError(t, reg.SessionPersister().UpsertSession(ctx, s))
+		}
+		return id
+	}
+
+	evaluate := func(t *testing.T, ctx context.Context, id *identity.Identity, current session.Device) *session.RiskAssessment {
+		s := session.NewInactiveSession()
+		s.IdentityID = id.ID
+		s.Devices = []session.Device{current}
+		history, err := reg.SessionDevicePersister().ListDevicesByIdentity(ctx, id.ID, time.Time{}, session.DeviceHistoryLimit)
+		require.NoError(t, err)
+		risk, err := reg.SessionRiskEvaluator().EvaluateRisk(ctx, s, history)
+		require.NoError(t, err)
+		return risk
+	}
+
+	known := newDevice("192.0.2.1", "agent-a", "Berlin, DE")
+	id := newIdentity(t, known)
+
+	for _, tc := range []struct {
+		desc    string
+		current session.Device
+		score   int
+		signals []session.RiskSignal
+		stepUp  bool
+	}{
+		{
+			desc:    "known device",
+			current: known,
+			signals: []session.RiskSignal{},
+		},
+		{
+			desc:    "new ip address",
+			current: newDevice("192.0.2.2", "agent-a", "Berlin, DE"),
+			score:   20,
+			signals: []session.RiskSignal{session.RiskSignalNewIPAddress},
+		},
+		{
+			desc:    "new ip address and user agent",
+			current: newDevice("192.0.2.2", "agent-b", "Berlin, DE"),
+			score:   50,
+			signals: []session.RiskSignal{session.RiskSignalNewIPAddress, session.RiskSignalNewUserAgent},
+			stepUp:  true,
+		},
+		{
+			desc:    "new location",
+			current: newDevice("192.0.2.1", "agent-a", "Paris, FR"),
+			score:   50,
+			signals: []session.RiskSignal{session.RiskSignalNewLocation},
+			stepUp:  true,
+		},
+		{
+			desc:    "everything is new",
+			current: newDevice("192.0.2.2", "agent-b", "Paris, FR"),
+			score:   100,
+			signals: []session.RiskSignal{session.RiskSignalNewIPAddress, session.RiskSignalNewUserAgent, session.RiskSignalNewLocation},
+			stepUp:  true,
+		},
+		{
+			desc:    "unknown location is no signal",
+			current: newDevice("192.0.2.1", "agent-a", ""),
+			signals: []session.RiskSignal{},
+		},
+	} {
+		t.Run("case="+tc.desc, func(t *testing.T) {
+			risk := evaluate(t, ctx, id, tc.current)
+			assert.Equal(t, tc.score, risk.Score)
+			assert.Equal(t, tc.signals, risk.Signals)
+			assert.Equal(t, tc.stepUp, risk.StepUpRequired)
+		})
+	}
+
+	t.Run("case=uses the configured weights and threshold", func(t *testing.T) {
+		ctx := contextx.WithConfigValues(ctx, map[string]any{
+			config.ViperKeySecurityRiskBasedStepUpThreshold:          10,
+			config.ViperKeySecurityRiskBasedStepUpWeightNewIPAddress: 10,
+		})
+		risk := evaluate(t, ctx, id, newDevice("192.0.2.2", "agent-a", "Berlin, DE"))
+		assert.Equal(t, 10, risk.Score)
+		assert.True(t, risk.StepUpRequired)
+	})
+
+	t.Run("case=the first login has no risk", func(t *testing.T) {
+		risk := evaluate(t, ctx, newIdentity(t), newDevice("192.0.2.2", "agent-b", "Paris, FR"))
+		assert.Zero(t, risk.Score)
+		assert.False(t, risk.StepUpRequired)
+	})
+
+	t.Run("case=scores all signals if the identity did not sign in within the lookback period", func(t *testing.T) {
+		ctx := contextx.WithConfigValues(ctx, map[string]any{
+			config.ViperKeySecurityRiskBasedStepUpLookback: "1ns",
+		})
+		risk := evaluate(t, ctx, id, known)
+		assert.Equal(t, 100, risk.Score)
+		assert.Equal(t, []session.RiskSignal{session.RiskSignalNewIPAddress, session.RiskSignalNewUserAgent, session.RiskSignalNewLocation}, risk.Signals)
+		assert.True(t, risk.StepUpRequired)
+	})
+
+	t.Run("case=spoofed headers do not change the score", func(t *testing.T) {
+		ctx := contextx.WithConfigValues(ctx, map[string]any{
+			config.ViperKeySecurityTrustedProxies: []string{"10.0.0.0/8"},
+		})
+		req := testhelpers.NewTestHTTPRequest(t, "GET", "/", nil)
+		req.RemoteAddr = "192.0.2.2:51234"
+		req.Header.Set("User-Agent", "agent-a")
+		req.Header.Set("X-Forwarded-For", "192.0.2.1")
+		req.Header.Set("True-Client-IP", "192.0.2.1")
+		req.Header.Set("Cf-Ipcity", "Berlin")
+		req.Header.Set("Cf-Ipcountry", "DE")
+
+		s := session.NewInactiveSession()
+		s.SetSessionDeviceInformation(req, reg.Config().SecurityTrustedProxies(ctx))
+		risk := evaluate(t, ctx, id, s.Devices[0])
+		assert.Equal(t, 20, risk.Score)
+		assert.Equal(t, []session.RiskSignal{session.RiskSignalNewIPAddress}, risk.Signals)
+	})
+
+	t.Run("case=ignores the port of the remote address", func(t *testing.T) {
+		req := testhelpers.NewTestHTTPRequest(t, "GET", "/", nil)
+		req.RemoteAddr = "192.0.2.1:51234"
+		req.Header.Set("User-Agent", "agent-a")
+
+		s := session.NewInactiveSession()
+		s.SetSessionDeviceInformation(req, nil)
+		assert.Equal(t, "192.0.2.1", *s.Devices[0].IPAddress)
+		risk := evaluate(t, ctx, id, s.Devices[0])
+		assert.Zero(t, risk.Score)
+	})
+}
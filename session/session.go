@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/netip"
 	"strings"
 	"time"
 
@@ -18,7 +19,6 @@ import (
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/x"
-	"github.com/ory/x/httpx"
 	"github.com/ory/x/pagination/keysetpagination"
 	"github.com/ory/x/pointerx"
 	"github.com/ory/x/randx"
@@ -127,6 +127,17 @@ type Session struct {
 	// Devices has history of all endpoints where the session was used
 	Devices []Device `json:"devices" faker:"-" has_many:"session_devices" fk_id:"session_id"`
 
+	// The Session Impersonation
+	//
+	// Set if this session was issued by an administrator to impersonate the identity. Impersonated sessions
+	// are not allowed to perform privileged settings changes.
+	Impersonation *Impersonation `json:"impersonation,omitempty" faker:"-" db:"impersonation"`
+
+	// The Session Risk Assessment
+	//
+	// Set if risk-based step-up is enabled and the session was issued by a login.
+	Risk *RiskAssessment `json:"risk,omitempty" faker:"-" db:"risk"`
+
 	// IdentityID is a helper struct field for gobuffalo.pop.
 	IdentityID uuid.UUID `json:"-" faker:"-" db:"identity_id"`
 
@@ -250,11 +261,15 @@ func NewInactiveSession() *Session {
 	}
 }
 
-func (s *Session) SetSessionDeviceInformation(r *http.Request) {
+// SetSessionDeviceInformation records the device of the request. The IP
+// address and geo location are only taken from headers set by one of the
+// trusted proxies, because they are compared with earlier devices to assess
+// the risk of a login.
+func (s *Session) SetSessionDeviceInformation(r *http.Request, trustedProxies []netip.Prefix) {
 	device := Device{
 		SessionID:  s.ID,
 		IdentityID: pointerx.Ptr(s.IdentityID),
-		IPAddress:  pointerx.Ptr(httpx.ClientIP(r)),
+		IPAddress:  pointerx.Ptr(x.TrustedClientIP(r, trustedProxies)),
 	}
 
 	agent := r.Header["User-Agent"]
@@ -263,11 +278,13 @@ func (s *Session) SetSessionDeviceInformation(r *http.Request) {
 	}
 
 	var clientGeoLocation []string
-	if r.Header.Get("Cf-Ipcity") != "" {
-		clientGeoLocation = append(clientGeoLocation, r.Header.Get("Cf-Ipcity"))
-	}
-	if r.Header.Get("Cf-Ipcountry") != "" {
-		clientGeoLocation = append(clientGeoLocation, r.Header.Get("Cf-Ipcountry"))
+	if x.IsTrustedProxyRequest(r, trustedProxies) {
+		if r.Header.Get("Cf-Ipcity") != "" {
+			clientGeoLocation = append(clientGeoLocation, r.Header.Get("Cf-Ipcity"))
+		}
+		if r.Header.Get("Cf-Ipcountry") != "" {
+			clientGeoLocation = append(clientGeoLocation, r.Header.Get("Cf-Ipcountry"))
+		}
 	}
 	device.Location = pointerx.Ptr(strings.Join(clientGeoLocation, ", "))
 
@@ -295,7 +312,24 @@ func (s *Session) MarshalJSON() ([]byte, error) {
 	return json.Marshal(out)
 }
 
+// IsImpersonated returns true if the session was issued by an administrator
+// to impersonate the identity.
+func (s *Session) IsImpersonated() bool {
+	return s.Impersonation != nil
+}
+
+// RequiresStepUp returns true if the login which issued the session was risky
+// enough to require a second factor.
+func (s *Session) RequiresStepUp() bool {
+	return s.Risk != nil && s.Risk.StepUpRequired
+}
+
+// CanBeRefreshed returns true if the session's lifespan can be extended.
+// Impersonated sessions are short-lived and can never be extended.
 func (s *Session) CanBeRefreshed(ctx context.Context, c refreshWindowProvider) bool {
+	if s.IsImpersonated() {
+		return false
+	}
 	return s.ExpiresAt.Add(-c.SessionRefreshMinTimeLeft(ctx)).Before(time.Now())
 }
 
@@ -369,3 +403,37 @@ func (n AuthenticationMethods) Value() (driver.Value, error) {
 	}
 	return string(value), nil
 }
+
+// Session Impersonation
+//
+// swagger:model sessionImpersonation
+type Impersonation struct {
+	// The ID of the administrator who impersonates the identity.
+	//
+	// required: true
+	ActorID string `json:"actor_id"`
+
+	// The reason given for the impersonation.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Scan implements the Scanner interface.
+func (n *Impersonation) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	v := fmt.Sprintf("%s", value)
+	if len(v) == 0 {
+		return nil
+	}
+	return errors.WithStack(json.Unmarshal([]byte(v), n))
+}
+
+// Value implements the driver Valuer interface.
+func (n Impersonation) Value() (driver.Value, error) {
+	value, err := json.Marshal(n)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return string(value), nil
+}
@@ -5,6 +5,7 @@ package session_test
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -79,6 +80,15 @@ func TestSession(t *testing.T) {
 		assert.Empty(t, s.AuthenticatedAt)
 	})
 
+	proxyCtx := contextx.WithConfigValues(t.Context(), map[string]any{
+		config.ViperKeySecurityTrustedProxies: []string{"10.0.0.0/8", "172.16.0.0/12", "162.158.0.0/15"},
+	})
+	newProxiedRequest := func(t *testing.T) *http.Request {
+		req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil).WithContext(proxyCtx)
+		req.RemoteAddr = "172.19.2.8:443"
+		return req
+	}
+
 	t.Run("case=client information reverse proxy forward", func(t *testing.T) {
 		for _, tc := range []struct {
 			input    string
@@ -86,11 +96,11 @@ func TestSession(t *testing.T) {
 		}{
 			{
 				input:    "10.10.8.1, 172.19.2.7",
-				expected: "",
+				expected: "10.10.8.1",
 			},
 			{
 				input:    "217.73.188.139,162.158.203.149, 172.19.2.7",
-				expected: "162.158.203.149",
+				expected: "217.73.188.139",
 			},
 			{
 				input:    "122.122.122.122 , 123.123.123.123",
@@ -98,7 +108,7 @@ func TestSession(t *testing.T) {
 			},
 		} {
 			t.Run("case=parse "+tc.input, func(t *testing.T) {
-				req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+				req := newProxiedRequest(t)
 				req.Header["User-Agent"] = []string{"Mozilla/5.0 (X11; Linux x86_64)", "AppleWebKit/537.36 (KHTML, like Gecko)", "Chrome/51.0.2704.103 Safari/537.36"}
 				req.Header.Set("X-Forwarded-For", tc.input)
 
@@ -116,8 +126,8 @@ func TestSession(t *testing.T) {
 		}
 	})
 
-	t.Run("case=client information reverse proxy real IP set", func(t *testing.T) {
-		req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+	t.Run("case=client information reverse proxy real IP is ignored", func(t *testing.T) {
+		req := newProxiedRequest(t)
 		req.Header["User-Agent"] = []string{"Mozilla/5.0 (X11; Linux x86_64)", "AppleWebKit/537.36 (KHTML, like Gecko)", "Chrome/51.0.2704.103 Safari/537.36"}
 		req.Header.Set("X-Real-IP", "54.155.246.155")
 		req.Header["X-Forwarded-For"] = []string{"54.155.246.232", "10.145.1.10"}
@@ -131,13 +141,13 @@ func TestSession(t *testing.T) {
 		assert.Equal(t, s.ID.String(), s.Devices[0].SessionID.String())
 		assert.NotNil(t, s.Devices[0].UpdatedAt)
 		assert.NotNil(t, s.Devices[0].CreatedAt)
-		assert.Equal(t, "54.155.246.155", *s.Devices[0].IPAddress)
+		assert.Equal(t, "54.155.246.232", *s.Devices[0].IPAddress)
 		assert.Equal(t, "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/51.0.2704.103 Safari/537.36", *s.Devices[0].UserAgent)
 		assert.Equal(t, "", *s.Devices[0].Location)
 	})
 
-	t.Run("case=client information CF true client IP set", func(t *testing.T) {
-		req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+	t.Run("case=client information CF true client IP is ignored", func(t *testing.T) {
+		req := newProxiedRequest(t)
 		req.Header["User-Agent"] = []string{"Mozilla/5.0 (X11; Linux x86_64)", "AppleWebKit/537.36 (KHTML, like Gecko)", "Chrome/51.0.2704.103 Safari/537.36"}
 		req.Header.Set("True-Client-IP", "54.155.246.155")
 		req.Header.Set("X-Forwarded-For", "217.73.188.139,162.158.203.149, 172.19.2.7")
@@ -151,15 +161,15 @@ func TestSession(t *testing.T) {
 		assert.Equal(t, s.ID.String(), s.Devices[0].SessionID.String())
 		assert.NotNil(t, s.Devices[0].UpdatedAt)
 		assert.NotNil(t, s.Devices[0].CreatedAt)
-		assert.Equal(t, "54.155.246.155", *s.Devices[0].IPAddress)
+		assert.Equal(t, "217.73.188.139", *s.Devices[0].IPAddress)
 		assert.Equal(t, "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/51.0.2704.103 Safari/537.36", *s.Devices[0].UserAgent)
 		assert.Equal(t, "", *s.Devices[0].Location)
 	})
 
 	t.Run("case=client information CF", func(t *testing.T) {
-		req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+		req := newProxiedRequest(t)
 		req.Header["User-Agent"] = []string{"Mozilla/5.0 (X11; Linux x86_64)", "AppleWebKit/537.36 (KHTML, like Gecko)", "Chrome/51.0.2704.103 Safari/537.36"}
-		req.Header.Set("True-Client-IP", "54.155.246.232")
+		req.Header.Set("X-Forwarded-For", "54.155.246.232")
 		req.Header.Set("Cf-Ipcity", "Munich")
 		req.Header.Set("Cf-Ipcountry", "Germany")
 
@@ -175,6 +185,20 @@ func TestSession(t *testing.T) {
 		assert.Equal(t, "Munich, Germany", *s.Devices[0].Location)
 	})
 
+	t.Run("case=client information CF from an untrusted peer is ignored", func(t *testing.T) {
+		req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil).WithContext(proxyCtx)
+		req.RemoteAddr = "54.155.246.232:51234"
+		req.Header.Set("X-Forwarded-For", "217.73.188.139")
+		req.Header.Set("Cf-Ipcity", "Munich")
+		req.Header.Set("Cf-Ipcountry", "Germany")
+
+		s := session.NewInactiveSession()
+		require.NoError(t, reg.SessionManager().ActivateSession(req, s, &identity.Identity{NID: x.NewUUID(), State: identity.StateActive}, authAt))
+		require.Len(t, s.Devices, 1)
+		assert.Equal(t, "54.155.246.232", *s.Devices[0].IPAddress)
+		assert.Equal(t, "", *s.Devices[0].Location)
+	})
+
 	for k, tc := range []struct {
 		d        string
 		methods  []session.AuthenticationMethod
@@ -132,6 +132,35 @@ func TestPersister(ctx context.Context, conf *config.Config, p interface {
 				check(actual, err)
 				assert.Empty(t, actual.AMR)
 			})
+
+			t.Run("case=update risk", func(t *testing.T) {
+				expected.Risk = &session.RiskAssessment{
+					Score:          70,
+					Signals:        []session.RiskSignal{session.RiskSignalNewIPAddress, session.RiskSignalNewLocation},
+					StepUpRequired: true,
+				}
+				require.NoError(t, p.UpsertSession(ctx, &expected))
+
+				actual, err := p.GetSessionByToken(ctx, expected.Token, session.ExpandDefault, identity.ExpandDefault)
+				check(actual, err)
+				assert.Equal(t, expected.Risk, actual.Risk)
+			})
+
+			t.Run("method=list devices by identity", func(t *testing.T) {
+				actual, err := p.ListDevicesByIdentity(ctx, expected.IdentityID, time.Now().Add(-time.Hour), 10)
+				checkDevices(actual, err)
+
+				actual, err = p.ListDevicesByIdentity(ctx, expected.IdentityID, time.Now().Add(time.Hour), 10)
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+
+				t.Run("on another network", func(t *testing.T) {
+					_, p := testhelpers.NewNetwork(t, ctx, p)
+					actual, err := p.ListDevicesByIdentity(ctx, expected.IdentityID, time.Now().Add(-time.Hour), 10)
+					require.NoError(t, err)
+					assert.Empty(t, actual)
+				})
+			})
 		})
 
 		t.Run("case=list sessions", func(t *testing.T) {
@@ -13,7 +13,6 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/driver/config"
@@ -71,6 +70,15 @@ func SetSubjectClaim(claims jwt.MapClaims, session *Session, subjectSource strin
 	return nil
 }
 
+// SetActorClaim sets the `act` claim (RFC 8693) to the administrator who
+// impersonates the session's identity. It is set after the claims mapper ran
+// as well, so that the mapper can not hide the impersonation.
+func SetActorClaim(claims jwt.MapClaims, session *Session) {
+	if session.IsImpersonated() {
+		claims["act"] = map[string]any{"sub": session.Impersonation.ActorID}
+	}
+}
+
 func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, session *Session) (err error) {
 	ctx, span := s.r.Tracer(ctx).Tracer().Start(ctx, "sessions.ManagerHTTP.TokenizeSession")
 	defer otelx.End(span, &err)
@@ -119,6 +127,7 @@ func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, sessio
 	if err = SetSubjectClaim(claims, session, tpl.SubjectSource); err != nil {
 		return err
 	}
+	SetActorClaim(claims, session)
 
 	if mapper := tpl.ClaimsMapperURL; len(mapper) > 0 {
 		sessionRaw, err := json.Marshal(session)
@@ -141,7 +150,7 @@ func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, sessio
 		}
 		evaluated, err := vm.EvaluateAnonymousSnippet(tpl.ClaimsMapperURL, jsonnet.String())
 		if err != nil {
-			trace.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(
+			events.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(
 				ctx, err, jsonnet.Bytes(), evaluated, "", "",
 			))
 			return errors.WithStack(herodot.ErrBadRequest.WithWrap(err).WithDebug(err.Error()).WithReasonf("Unable to execute tokenizer JsonNet."))
@@ -149,7 +158,7 @@ func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, sessio
 
 		evaluatedClaims := gjson.Get(evaluated, "claims")
 		if !evaluatedClaims.IsObject() {
-			trace.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(
+			events.SpanFromContext(ctx).AddEvent(events.NewJsonnetMappingFailed(
 				ctx, err, jsonnet.Bytes(), evaluated, "", "",
 			))
 			return errors.WithStack(herodot.ErrBadRequest.WithWrap(err).WithReasonf("Expected tokenizer JsonNet to return a claims object but it did not."))
@@ -162,6 +171,7 @@ func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, sessio
 	if err = SetSubjectClaim(claims, session, tpl.SubjectSource); err != nil {
 		return err
 	}
+	SetActorClaim(claims, session)
 
 	var privateKey interface{}
 	if err := key.Raw(&privateKey); err != nil {
@@ -174,7 +184,7 @@ func (s *Tokenizer) TokenizeSession(ctx context.Context, template string, sessio
 		return errors.WithStack(herodot.ErrBadRequest.WithWrap(err).WithReasonf("Unable to sign JSON Web Token."))
 	}
 
-	trace.SpanFromContext(ctx).AddEvent(events.NewSessionJWTIssued(ctx, session.ID, session.IdentityID, tpl.TTL))
+	events.SpanFromContext(ctx).AddEvent(events.NewSessionJWTIssued(ctx, session.ID, session.IdentityID, tpl.TTL))
 	session.Tokenized = result
 	return nil
 }
@@ -157,6 +157,24 @@ func TestTokenizer(t *testing.T) {
 		require.Error(t, tkn.TokenizeSession(ctx, tid, s2))
 	})
 
+	t.Run("case=impersonated-session-sets-act-claim", func(t *testing.T) {
+		tid := "es256-impersonated"
+		ctx := setTokenizeConfig(t.Context(), tid, "jwk.es256.json", "file://stub/rs512-template.jsonnet")
+
+		impersonated := *s
+		impersonated.Impersonation = &session.Impersonation{ActorID: "support-agent"}
+
+		require.NoError(t, tkn.TokenizeSession(ctx, tid, &impersonated))
+		token := validateTokenized(t, impersonated.Tokenized, es256Key)
+
+		resultClaims := token.Claims.(jwt.MapClaims)
+		assert.Equal(t, map[string]any{"sub": "support-agent"}, resultClaims["act"])
+		assert.Equal(t, i.ID.String(), resultClaims["sub"])
+
+		require.NoError(t, tkn.TokenizeSession(ctx, tid, s))
+		assert.NotContains(t, validateTokenized(t, s.Tokenized, es256Key).Claims, "act")
+	})
+
 	t.Run("case=rs512-with-broken-keyfile", func(t *testing.T) {
 		tid := "rs512-template"
 		ctx := setTokenizeConfig(t.Context(), tid, "jwk.es512.broken.json", "file://stub/rs512-template.jsonnet")
@@ -78,6 +78,7 @@ const (
 	InfoSelfServiceSettingsRemoveWebAuthn
 	InfoSelfServiceSettingsRegisterPasskey
 	InfoSelfServiceSettingsRemovePasskey
+	InfoSelfServiceSettingsRemovePassword
 )
 
 const (
@@ -119,6 +120,13 @@ const (
 	InfoSelfServiceVerificationEmailWithCodeSent                     // 1080003
 )
 
+const (
+	InfoSelfServiceDeletion           ID = 1090000 + iota // 1090000
+	InfoSelfServiceDeletionSuccessful                     // 1090001
+	InfoSelfServiceDeletionConfirm                        // 1090002
+	InfoSelfServiceDeletionSubmit                         // 1090003
+)
+
 const (
 	ErrorValidation ID = 4000000 + iota
 	ErrorValidationGeneric
@@ -174,6 +182,7 @@ const (
 	ErrorValidationLoginCodeInvalidOrAlreadyUsed                        // 4010008
 	ErrorValidationLoginLinkedCredentialsDoNotMatch                     // 4010009
 	ErrorValidationLoginAddressUnknown                                  // 4010010
+	ErrorValidationLoginLockedOut                                       // 4010011
 )
 
 const (
@@ -208,6 +217,12 @@ const (
 	ErrorValidationVerificationCodeInvalidOrAlreadyUsed                      // 4070006
 )
 
+const (
+	ErrorValidationDeletion             ID = 4080000 + iota // 4080000
+	ErrorValidationDeletionFlowExpired                      // 4080001
+	ErrorValidationDeletionNotConfirmed                     // 4080002
+)
+
 const (
 	ErrorSystem ID = 5000000 + iota
 	ErrorSystemGeneric
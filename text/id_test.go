@@ -48,6 +48,7 @@ func TestIDs(t *testing.T) {
 
 	assert.Equal(t, 4010000, int(ErrorValidationLogin))
 	assert.Equal(t, 4010001, int(ErrorValidationLoginFlowExpired))
+	assert.Equal(t, 4010011, int(ErrorValidationLoginLockedOut))
 
 	assert.Equal(t, 4040000, int(ErrorValidationRegistration))
 	assert.Equal(t, 4040001, int(ErrorValidationRegistrationFlowExpired))
@@ -72,6 +73,15 @@ func TestIDs(t *testing.T) {
 	assert.Equal(t, 1080002, int(InfoSelfServiceVerificationSuccessful))
 	assert.Equal(t, 1080003, int(InfoSelfServiceVerificationEmailWithCodeSent))
 
+	assert.Equal(t, 1090000, int(InfoSelfServiceDeletion))
+	assert.Equal(t, 1090001, int(InfoSelfServiceDeletionSuccessful))
+	assert.Equal(t, 1090002, int(InfoSelfServiceDeletionConfirm))
+	assert.Equal(t, 1090003, int(InfoSelfServiceDeletionSubmit))
+
+	assert.Equal(t, 4080000, int(ErrorValidationDeletion))
+	assert.Equal(t, 4080001, int(ErrorValidationDeletionFlowExpired))
+	assert.Equal(t, 4080002, int(ErrorValidationDeletionNotConfirmed))
+
 	assert.Equal(t, 1070015, int(InfoNodeLabelCaptcha))
 	assert.Equal(t, 4000038, int(ErrorValidationCaptchaError))
 }
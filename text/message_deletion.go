@@ -0,0 +1,53 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package text
+
+import (
+	"fmt"
+	"time"
+)
+
+func NewErrorValidationDeletionFlowExpired(expiredAt time.Time) *Message {
+	return &Message{
+		ID:   ErrorValidationDeletionFlowExpired,
+		Text: fmt.Sprintf("The account deletion flow expired %.2f minutes ago, please try again.", Since(expiredAt).Minutes()),
+		Type: Error,
+		Context: context(map[string]any{
+			"expired_at":      expiredAt,
+			"expired_at_unix": expiredAt.Unix(),
+		}),
+	}
+}
+
+func NewErrorValidationDeletionNotConfirmed() *Message {
+	return &Message{
+		ID:   ErrorValidationDeletionNotConfirmed,
+		Text: "Please confirm that you want to delete your account.",
+		Type: Error,
+	}
+}
+
+func NewInfoSelfServiceDeletionSuccessful() *Message {
+	return &Message{
+		ID:   InfoSelfServiceDeletionSuccessful,
+		Text: "Your account has been deleted.",
+		Type: Success,
+	}
+}
+
+func NewInfoSelfServiceDeletionConfirm() *Message {
+	return &Message{
+		ID:   InfoSelfServiceDeletionConfirm,
+		Text: "I understand that my account will be deleted and that this can not be undone.",
+		Type: Info,
+	}
+}
+
+func NewInfoSelfServiceDeletionSubmit() *Message {
+	return &Message{
+		ID:   InfoSelfServiceDeletionSubmit,
+		Text: "Delete account",
+		Type: Info,
+	}
+}
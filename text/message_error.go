@@ -20,6 +20,12 @@ const (
 	ErrNoActiveSession               = "session_inactive"
 	ErrIDRedirectURLNotAllowed       = "self_service_flow_return_to_forbidden"
 	ErrIDInitiatedBySomeoneElse      = "security_identity_mismatch"
+	ErrIDSessionImpersonated         = "session_impersonated"
+
+	ErrIDDeviceAuthorizationPending = "authorization_pending"
+	ErrIDDeviceSlowDown             = "slow_down"
+	ErrIDDeviceAccessDenied         = "access_denied"
+	ErrIDDeviceExpiredToken         = "expired_token"
 
 	ErrIDCSRF = "security_csrf_violation"
 )
@@ -272,6 +272,18 @@ func NewErrorValidationAddressUnknown() *Message {
 	}
 }
 
+func NewErrorValidationLoginLockedOut(lockedUntil time.Time) *Message {
+	return &Message{
+		ID:   ErrorValidationLoginLockedOut,
+		Text: fmt.Sprintf("Too many failed login attempts. Please try again in %.2f minutes.", Until(lockedUntil).Minutes()),
+		Type: Error,
+		Context: context(map[string]any{
+			"locked_until":      lockedUntil,
+			"locked_until_unix": lockedUntil.Unix(),
+		}),
+	}
+}
+
 func NewInfoSelfServiceLoginCodeMFA() *Message {
 	return &Message{
 		ID:   InfoSelfServiceLoginCodeMFA,
@@ -207,3 +207,11 @@ func NewInfoSelfServiceRemovePasskey(name string, createdAt time.Time) *Message
 		}),
 	}
 }
+
+func NewInfoSelfServiceRemovePassword() *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsRemovePassword,
+		Text: "Remove password",
+		Type: Info,
+	}
+}
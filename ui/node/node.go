@@ -52,7 +52,8 @@ const (
 	PasskeyGroup         UiNodeGroup = "passkey"
 	IdentifierFirstGroup UiNodeGroup = "identifier_first"
 	CaptchaGroup         UiNodeGroup = "captcha" // Available in OEL
-	SAMLGroup            UiNodeGroup = "saml"    // Available in OEL
+	SAMLGroup            UiNodeGroup = "saml"
+	LDAPGroup            UiNodeGroup = "ldap"
 )
 
 func (g UiNodeGroup) String() string {
@@ -0,0 +1,226 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/sqlxx"
+)
+
+// A Webhook Delivery's Status
+//
+// swagger:enum webhookDeliveryStatus
+type DeliveryStatus string
+
+const (
+	DeliveryStatusQueued     DeliveryStatus = "queued"
+	DeliveryStatusProcessing DeliveryStatus = "processing"
+	DeliveryStatusSent       DeliveryStatus = "sent"
+	DeliveryStatusAbandoned  DeliveryStatus = "abandoned"
+)
+
+func (s DeliveryStatus) IsValid() error {
+	switch s {
+	case DeliveryStatusQueued, DeliveryStatusProcessing, DeliveryStatusSent, DeliveryStatusAbandoned:
+		return nil
+	default:
+		return errors.WithStack(herodot.ErrBadRequest.WithReasonf("Webhook delivery status %q is not valid.", s))
+	}
+}
+
+// DeliveryHeader are the HTTP headers of a webhook delivery.
+type DeliveryHeader http.Header
+
+// Scan implements the Scanner interface.
+func (h *DeliveryHeader) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	v := fmt.Sprintf("%s", value)
+	if len(v) == 0 {
+		return nil
+	}
+	return errors.WithStack(json.Unmarshal([]byte(v), h))
+}
+
+// Value implements the driver Valuer interface.
+func (h DeliveryHeader) Value() (driver.Value, error) {
+	value, err := json.Marshal(h)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return string(value), nil
+}
+
+// MarshalJSON implements json.Marshaler to avoid encoding a nil header as null.
+func (h DeliveryHeader) MarshalJSON() ([]byte, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(http.Header(h))
+}
+
+// A Webhook Delivery
+//
+// Webhooks which are configured to ignore the response are delivered
+// asynchronously through the webhook outbox, if it is enabled. Each delivery
+// is attempted until it succeeds or the configured number of retries is
+// exceeded, in which case it is abandoned.
+//
+// swagger:model webhookDelivery
+type Delivery struct {
+	// The delivery's unique ID.
+	//
+	// required: true
+	ID uuid.UUID `json:"id" faker:"-" db:"id"`
+
+	NID uuid.UUID `json:"-" faker:"-" db:"nid"`
+
+	// The ID of the webhook as configured in the hook's configuration, if any.
+	//
+	// required: true
+	WebhookID string `json:"webhook_id" db:"webhook_id"`
+
+	// The trigger ID correlates the requests of a delivery across retries. It is
+	// sent in the `Ory-Webhook-Trigger-ID` header.
+	//
+	// required: true
+	TriggerID uuid.UUID `json:"trigger_id" faker:"-" db:"trigger_id"`
+
+	// The delivery's status.
+	//
+	// required: true
+	Status DeliveryStatus `json:"status" db:"status"`
+
+	// The HTTP method of the request.
+	//
+	// required: true
+	Method string `json:"method" db:"method"`
+
+	// The URL the request is sent to.
+	//
+	// required: true
+	URL string `json:"url" db:"url"`
+
+	// The headers of the request. They are redacted unless dev mode is enabled
+	// because they may contain credentials.
+	//
+	// required: true
+	Header DeliveryHeader `json:"header" faker:"-" db:"header"`
+
+	// EncryptedHeader are the encrypted headers of the request, which are
+	// stored instead of Header because they may contain API keys. Header is
+	// only persisted for deliveries which were queued before the headers were
+	// encrypted.
+	EncryptedHeader sqlxx.NullString `json:"-" faker:"-" db:"encrypted_header"`
+
+	// The body of the request. It is redacted unless dev mode is enabled.
+	//
+	// required: true
+	Body string `json:"body" db:"body"`
+
+	// Auth is the encrypted auth config of the webhook. It is applied when the
+	// request is sent, so that signatures and tokens are always fresh.
+	Auth sqlxx.NullString `json:"-" faker:"-" db:"auth"`
+
+	// How often the delivery was attempted.
+	//
+	// required: true
+	SendCount int `json:"send_count" db:"send_count"`
+
+	// The error of the last failed attempt, if any.
+	LastError sqlxx.NullString `json:"last_error" db:"last_error"`
+
+	// WorkerID identifies the outbox worker which claimed the delivery last.
+	WorkerID sqlxx.NullString `json:"worker_id,omitempty" faker:"-" db:"worker_id"`
+
+	// LeaseExpiresAt is the time until which the delivery is claimed by the
+	// worker. Once the lease expired, the delivery may be claimed by another
+	// worker.
+	LeaseExpiresAt sqlxx.NullTime `json:"-" faker:"-" db:"lease_expires_at"`
+
+	// When the delivery is attempted next.
+	//
+	// required: true
+	NextAttemptAt time.Time `json:"next_attempt_at" faker:"-" db:"next_attempt_at"`
+
+	// CreatedAt is a helper struct field for gobuffalo.pop.
+	//
+	// required: true
+	CreatedAt time.Time `json:"created_at" faker:"-" db:"created_at"`
+
+	// UpdatedAt is a helper struct field for gobuffalo.pop.
+	//
+	// required: true
+	UpdatedAt time.Time `json:"updated_at" faker:"-" db:"updated_at"`
+}
+
+func (d Delivery) TableName() string { return "webhook_deliveries" }
+
+func (d Delivery) PageToken() keysetpagination.PageToken {
+	return keysetpagination.NewPageToken(
+		keysetpagination.Column{
+			Name:  "created_at",
+			Order: keysetpagination.OrderDescending,
+			Value: d.CreatedAt,
+		}, keysetpagination.Column{
+			Name:  "id",
+			Value: d.ID,
+		},
+	)
+}
+
+func (d Delivery) DefaultPageToken() keysetpagination.PageToken {
+	return Delivery{ID: uuid.Nil, CreatedAt: time.Date(2200, 12, 31, 23, 59, 59, 0, time.UTC)}.PageToken()
+}
+
+// NewDelivery creates a queued delivery of the request.
+func NewDelivery(req *retryablehttp.Request, webhookID string, triggerID uuid.UUID) (*Delivery, error) {
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Delivery{
+		WebhookID:     webhookID,
+		TriggerID:     triggerID,
+		Status:        DeliveryStatusQueued,
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		Header:        DeliveryHeader(req.Header.Clone()),
+		Body:          string(body),
+		NextAttemptAt: time.Now().UTC(),
+	}, nil
+}
+
+// NewRequest recreates the request of the delivery.
+func (d *Delivery) NewRequest() (*retryablehttp.Request, error) {
+	var body io.Reader
+	if d.Body != "" {
+		body = bytes.NewBufferString(d.Body)
+	}
+
+	req, err := retryablehttp.NewRequest(d.Method, d.URL, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header = http.Header(d.Header).Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	return req, nil
+}
@@ -0,0 +1,270 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/x/httprouterx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+const (
+	AdminRouteWebhooks         = "/webhooks"
+	AdminRouteListDeliveries   = AdminRouteWebhooks + "/deliveries"
+	AdminRouteGetDelivery      = AdminRouteListDeliveries + "/{id}"
+	AdminRouteReplayDelivery   = AdminRouteGetDelivery + "/replay"
+	redactedUnlessDevModeValue = "<redacted-unless-dev-mode>"
+)
+
+type (
+	handlerDependencies interface {
+		x.WriterProvider
+		nosurfx.CSRFProvider
+		PersistenceProvider
+		OutboxProvider
+		config.Provider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		WebhookHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
+	h.r.CSRFHandler().IgnoreGlobs(
+		httprouterx.AdminPrefix+AdminRouteListDeliveries, AdminRouteListDeliveries,
+		httprouterx.AdminPrefix+AdminRouteListDeliveries+"/*/replay", AdminRouteListDeliveries+"/*/replay",
+	)
+	public.GET(httprouterx.AdminPrefix+AdminRouteListDeliveries, redir.RedirectToAdminRoute(h.r))
+	public.GET(httprouterx.AdminPrefix+AdminRouteGetDelivery, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteReplayDelivery, redir.RedirectToAdminRoute(h.r))
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
+	admin.GET(AdminRouteListDeliveries, h.listWebhookDeliveries)
+	admin.GET(AdminRouteGetDelivery, h.getWebhookDelivery)
+	admin.POST(AdminRouteReplayDelivery, h.replayWebhookDelivery)
+}
+
+// Paginated Webhook Delivery List Response
+//
+// swagger:response listWebhookDeliveries
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listWebhookDeliveriesResponse struct {
+	keysetpagination.ResponseHeaders
+
+	// List of webhook deliveries
+	//
+	// in:body
+	Body []Delivery
+}
+
+// Paginated List Webhook Deliveries Parameters
+//
+// swagger:parameters listWebhookDeliveries
+type ListDeliveriesParameters struct {
+	keysetpagination.RequestParameters
+
+	// Status only returns deliveries with this status, for example `abandoned`
+	// to list failed deliveries.
+	//
+	// required: false
+	// in: query
+	Status DeliveryStatus `json:"status"`
+
+	// WebhookID only returns deliveries of the webhook with this ID.
+	//
+	// required: false
+	// in: query
+	WebhookID string `json:"webhook_id"`
+}
+
+// swagger:route GET /admin/webhooks/deliveries webhook listWebhookDeliveries
+//
+// # List Webhook Deliveries
+//
+// Lists the deliveries of the webhook outbox, newest first. Only webhooks
+// which ignore the response are delivered through the outbox, and only if the
+// outbox is enabled.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: listWebhookDeliveries
+//	  400: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	keys := h.r.Config().SecretsPagination(r.Context())
+	filter, paginator, err := parseDeliveriesFilter(r, keys)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	l, nextPage, err := h.r.WebhookPersister().ListWebhookDeliveries(r.Context(), filter, paginator)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	for i := range l {
+		if err := h.redact(r, &l[i]); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+	}
+
+	u := *r.URL
+	keysetpagination.SetLinkHeader(w, keys, &u, nextPage)
+	h.r.Writer().Write(w, r, l)
+}
+
+func parseDeliveriesFilter(r *http.Request, keys [][32]byte) (ListDeliveriesParameters, []keysetpagination.Option, error) {
+	query := r.URL.Query()
+	filter := ListDeliveriesParameters{WebhookID: query.Get("webhook_id")}
+
+	if query.Has("status") {
+		filter.Status = DeliveryStatus(query.Get("status"))
+		if err := filter.Status.IsValid(); err != nil {
+			return filter, nil, err
+		}
+	}
+
+	opts, err := keysetpagination.ParseQueryParams(keys, query)
+	if err != nil {
+		return filter, nil, errors.WithStack(herodot.ErrBadRequest.WithReason("The page token is invalid.").WithDebug(err.Error()))
+	}
+
+	return filter, opts, nil
+}
+
+// Get Webhook Delivery Parameters
+//
+// swagger:parameters getWebhookDelivery replayWebhookDelivery
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getWebhookDelivery struct {
+	// ID is the ID of the webhook delivery.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/webhooks/deliveries/{id} webhook getWebhookDelivery
+//
+// # Get a Webhook Delivery
+//
+// Gets the webhook delivery with the given ID.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: webhookDelivery
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) getWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.FromString(r.PathValue("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("The webhook delivery ID must be a UUID.").WithDebug(err.Error())))
+		return
+	}
+
+	d, err := h.r.WebhookPersister().GetWebhookDelivery(r.Context(), id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.redact(r, d); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, d)
+}
+
+// swagger:route POST /admin/webhooks/deliveries/{id}/replay webhook replayWebhookDelivery
+//
+// # Replay a Webhook Delivery
+//
+// Queues the webhook delivery again and resets its send count. Use this
+// endpoint to replay abandoned deliveries once the webhook target is
+// available again. Only sent and abandoned deliveries can be replayed.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: webhookDelivery
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) replayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.FromString(r.PathValue("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest.WithReason("The webhook delivery ID must be a UUID.").WithDebug(err.Error())))
+		return
+	}
+
+	d, err := h.r.WebhookOutbox().Replay(r.Context(), id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.redact(r, d); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, d)
+}
+
+// redact removes the request headers and body, which may contain credentials
+// and personal data, unless dev mode is enabled. In dev mode, the encrypted
+// headers are decrypted instead.
+func (h *Handler) redact(r *http.Request, d *Delivery) error {
+	if h.r.Config().IsInsecureDevMode(r.Context()) {
+		return h.r.WebhookOutbox().DecryptHeader(r.Context(), d)
+	}
+	d.Header = DeliveryHeader{}
+	d.Body = redactedUnlessDevModeValue
+	return nil
+}
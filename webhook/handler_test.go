@@ -0,0 +1,112 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/internal/testhelpers"
+	"github.com/ory/kratos/webhook"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/httprouterx"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValue(config.ViperKeyWebhookOutboxEnabled, true),
+		configx.WithValue(config.ViperKeyWebhookOutboxMaxRetries, 0),
+		configx.WithValue("dev", false),
+	)
+	publicTS, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	do := func(t *testing.T, ts *httptest.Server, method, href string, expectCode int) gjson.Result {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+href, nil)
+		require.NoError(t, err)
+		res, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, expectCode, res.StatusCode, "%s", body)
+		return gjson.ParseBytes(body)
+	}
+
+	status := http.StatusBadRequest
+	receiver, _ := newReceiver(t, &status)
+	webhookID := x.NewUUID().String()
+	enqueue(t, ctx, reg.WebhookOutbox(), receiver.URL, webhookID)
+	enqueue(t, ctx, reg.WebhookOutbox(), receiver.URL, x.NewUUID().String())
+	require.NoError(t, reg.WebhookOutbox().DispatchQueue(ctx))
+
+	listPath := "/admin" + webhook.AdminRouteListDeliveries
+
+	t.Run("case=lists abandoned deliveries", func(t *testing.T) {
+		l := do(t, adminTS, "GET", listPath+"?status=abandoned&webhook_id="+webhookID, http.StatusOK)
+		require.Len(t, l.Array(), 1, "%s", l.Raw)
+		assert.Equal(t, "abandoned", l.Get("0.status").String())
+		assert.Equal(t, webhookID, l.Get("0.webhook_id").String())
+		assert.EqualValues(t, 1, l.Get("0.send_count").Int())
+		assert.Contains(t, l.Get("0.last_error").String(), "status code 400")
+
+		t.Run("case=redacts the request", func(t *testing.T) {
+			assert.Equal(t, "<redacted-unless-dev-mode>", l.Get("0.body").String())
+			assert.Empty(t, l.Get("0.header").Map())
+		})
+
+		t.Run("case=shows the request in dev mode", func(t *testing.T) {
+			conf.MustSet(ctx, "dev", true)
+			t.Cleanup(func() { conf.MustSet(ctx, "dev", false) })
+
+			l := do(t, adminTS, "GET", listPath+"?webhook_id="+webhookID, http.StatusOK)
+			assert.Equal(t, `{"identity_id":"foo"}`, l.Get("0.body").String())
+			assert.Equal(t, "Bearer secret", l.Get("0.header.Authorization.0").String())
+		})
+
+		t.Run("case=is available through the public API prefix", func(t *testing.T) {
+			redirected := do(t, publicTS, "GET", httprouterx.AdminPrefix+webhook.AdminRouteListDeliveries+"?status=abandoned&webhook_id="+webhookID, http.StatusOK)
+			assert.Equal(t, l.Raw, redirected.Raw)
+		})
+	})
+
+	t.Run("case=rejects an invalid status", func(t *testing.T) {
+		do(t, adminTS, "GET", listPath+"?status=unknown", http.StatusBadRequest)
+	})
+
+	t.Run("case=gets and replays a delivery", func(t *testing.T) {
+		id := do(t, adminTS, "GET", listPath+"?webhook_id="+webhookID, http.StatusOK).Get("0.id").String()
+
+		d := do(t, adminTS, "GET", listPath+"/"+id, http.StatusOK)
+		assert.Equal(t, "abandoned", d.Get("status").String())
+
+		status = http.StatusOK
+		d = do(t, adminTS, "POST", listPath+"/"+id+"/replay", http.StatusOK)
+		assert.Equal(t, "queued", d.Get("status").String())
+		assert.EqualValues(t, 0, d.Get("send_count").Int())
+
+		do(t, adminTS, "POST", listPath+"/"+id+"/replay", http.StatusConflict)
+
+		require.NoError(t, reg.WebhookOutbox().DispatchQueue(ctx))
+		assert.Equal(t, "sent", do(t, adminTS, "GET", listPath+"/"+id, http.StatusOK).Get("status").String())
+	})
+
+	t.Run("case=returns not found for unknown deliveries", func(t *testing.T) {
+		do(t, adminTS, "GET", listPath+"/"+x.NewUUID().String(), http.StatusNotFound)
+		do(t, adminTS, "POST", listPath+"/"+x.NewUUID().String()+"/replay", http.StatusNotFound)
+		do(t, adminTS, "GET", listPath+"/not-a-uuid", http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,271 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/request"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/events"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlxx"
+)
+
+// maxRetryBackoff caps the exponential backoff between delivery attempts.
+const maxRetryBackoff = time.Hour
+
+type (
+	outboxDependencies interface {
+		PersistenceProvider
+		x.TracingProvider
+		x.LoggingProvider
+		x.HTTPClientProvider
+		cipher.Provider
+		config.Provider
+	}
+	// Outbox queues webhook requests and delivers them in the background.
+	// Every Kratos instance runs a worker, which claims due deliveries with a
+	// lease so that each delivery is attempted by one worker at a time.
+	Outbox struct {
+		r        outboxDependencies
+		workerID string
+	}
+	OutboxProvider interface {
+		WebhookOutbox() *Outbox
+	}
+)
+
+func NewOutbox(r outboxDependencies) *Outbox {
+	return &Outbox{r: r, workerID: x.NewWorkerID("webhook-outbox")}
+}
+
+// Enqueue persists the request so that it is delivered by the outbox worker.
+// The request must have been built without auth, see request.WithoutAuth. The
+// auth config is stored encrypted and applied whenever the request is sent.
+// The headers are stored encrypted as well, as they may contain API keys.
+func (o *Outbox) Enqueue(ctx context.Context, req *retryablehttp.Request, auth request.AuthConfig, webhookID string, triggerID uuid.UUID) (err error) {
+	ctx, span := o.r.Tracer(ctx).Tracer().Start(ctx, "webhook.Outbox.Enqueue")
+	defer otelx.End(span, &err)
+
+	d, err := NewDelivery(req, webhookID, triggerID)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(d.Header)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	encrypted, err := o.r.Cipher(ctx).Encrypt(ctx, raw)
+	if err != nil {
+		return err
+	}
+	d.EncryptedHeader = sqlxx.NullString(encrypted)
+	d.Header = DeliveryHeader{}
+
+	if auth.Type != "" {
+		raw, err := json.Marshal(auth)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		encrypted, err := o.r.Cipher(ctx).Encrypt(ctx, raw)
+		if err != nil {
+			return err
+		}
+		d.Auth = sqlxx.NullString(encrypted)
+	}
+
+	return o.r.WebhookPersister().AddWebhookDelivery(ctx, d)
+}
+
+// Work delivers queued webhooks until the context is canceled.
+func (o *Outbox) Work(ctx context.Context) error {
+	for {
+		if err := o.DispatchQueue(ctx); err != nil {
+			o.r.Logger().WithError(err).Error("Unable to dispatch queued webhook deliveries.")
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+		case <-time.After(o.r.Config().WebhookOutbox(ctx).PullWait):
+		}
+	}
+}
+
+// DispatchQueue attempts all deliveries which are due, up to the configured
+// pull count.
+func (o *Outbox) DispatchQueue(ctx context.Context) (err error) {
+	ctx, span := o.r.Tracer(ctx).Tracer().Start(ctx, "webhook.Outbox.DispatchQueue")
+	defer otelx.End(span, &err)
+
+	conf := o.r.Config().WebhookOutbox(ctx)
+	deliveries, err := o.r.WebhookPersister().NextWebhookDeliveries(ctx, conf.PullCount, o.workerID, conf.Lease)
+	if errors.Is(err, ErrQueueEmpty) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Int("deliveries_count", len(deliveries)))
+
+	for i := range deliveries {
+		// The lease is renewed for every delivery, as the previous ones may
+		// have taken up most of it.
+		err := o.r.WebhookPersister().RenewWebhookDeliveryLease(ctx, deliveries[i].ID, o.workerID, conf.Lease)
+		if err == nil {
+			err = o.Deliver(ctx, &deliveries[i])
+		}
+
+		if errors.Is(err, ErrLeaseLost) {
+			o.r.Logger().
+				WithField("webhook_delivery_id", deliveries[i].ID).
+				Warn("Skipping the webhook delivery because it was claimed by another worker or replayed.")
+		} else if err != nil {
+			o.r.Logger().
+				WithError(err).
+				WithField("webhook_delivery_id", deliveries[i].ID).
+				Error("Unable to update the webhook delivery.")
+		}
+	}
+	return nil
+}
+
+// Replay queues the delivery again and resets its send count. Only sent and
+// abandoned deliveries can be replayed, as queued and processing deliveries
+// are going to be attempted anyway.
+func (o *Outbox) Replay(ctx context.Context, id uuid.UUID) (_ *Delivery, err error) {
+	ctx, span := o.r.Tracer(ctx).Tracer().Start(ctx, "webhook.Outbox.Replay")
+	defer otelx.End(span, &err)
+
+	return o.r.WebhookPersister().ReplayWebhookDelivery(ctx, id)
+}
+
+// Deliver attempts a delivery which was claimed by the worker once. Failed
+// deliveries are queued again with an exponential backoff until the
+// configured number of retries is exceeded, after which they are abandoned.
+// The returned error is only set if the delivery could not be updated, and is
+// ErrLeaseLost if it is no longer claimed by the worker.
+func (o *Outbox) Deliver(ctx context.Context, d *Delivery) (err error) {
+	ctx, span := o.r.Tracer(ctx).Tracer().Start(ctx, "webhook.Outbox.Deliver", trace.WithAttributes(
+		attribute.Stringer("delivery.id", d.ID),
+		attribute.String("delivery.webhook_id", d.WebhookID),
+		attribute.Stringer("delivery.trigger_id", d.TriggerID),
+		attribute.Int("delivery.send_count", d.SendCount),
+	))
+	defer otelx.End(span, &err)
+
+	conf := o.r.Config().WebhookOutbox(ctx)
+	logger := o.r.Logger().
+		WithField("webhook_delivery_id", d.ID).
+		WithField("webhook_id", d.WebhookID).
+		WithField("webhook_trigger_id", d.TriggerID)
+
+	d.SendCount++
+	switch sendErr := o.send(ctx, d); {
+	case sendErr == nil:
+		d.Status = DeliveryStatusSent
+		d.LastError = ""
+		logger.Info("Webhook request succeeded")
+		events.SpanFromContext(ctx).AddEvent(events.NewWebhookSucceeded(ctx, d.TriggerID, d.WebhookID))
+	case d.SendCount > conf.MaxRetries:
+		d.Status = DeliveryStatusAbandoned
+		d.LastError = sqlxx.NullString(sendErr.Error())
+		logger.WithError(sendErr).Warnf("Webhook delivery was abandoned because it did not succeed after %d attempts", d.SendCount)
+		events.SpanFromContext(ctx).AddEvent(events.NewWebhookFailed(ctx, sendErr, d.TriggerID, d.WebhookID))
+		events.SpanFromContext(ctx).AddEvent(events.NewWebhookAbandoned(ctx, d.ID, d.TriggerID, d.WebhookID, d.SendCount))
+	default:
+		d.Status = DeliveryStatusQueued
+		d.LastError = sqlxx.NullString(sendErr.Error())
+		d.NextAttemptAt = time.Now().UTC().Add(retryBackoff(conf.RetryBackoff, d.SendCount))
+		logger.WithError(sendErr).Warn("Webhook request failed and will be retried")
+		events.SpanFromContext(ctx).AddEvent(events.NewWebhookFailed(ctx, sendErr, d.TriggerID, d.WebhookID))
+	}
+
+	return o.r.WebhookPersister().UpdateWebhookDelivery(ctx, d)
+}
+
+// DecryptHeader sets the headers of the delivery from its encrypted headers.
+// Deliveries which were queued before the headers were encrypted are left
+// unchanged.
+func (o *Outbox) DecryptHeader(ctx context.Context, d *Delivery) error {
+	if d.EncryptedHeader == "" {
+		return nil
+	}
+
+	raw, err := o.r.Cipher(ctx).Decrypt(ctx, d.EncryptedHeader.String())
+	if err != nil {
+		return err
+	}
+	var header DeliveryHeader
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return errors.WithStack(err)
+	}
+	d.Header = header
+	return nil
+}
+
+func (o *Outbox) send(ctx context.Context, d *Delivery) error {
+	decrypted := *d
+	if err := o.DecryptHeader(ctx, &decrypted); err != nil {
+		return err
+	}
+	req, err := decrypted.NewRequest()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Ory-Webhook-Request-ID", x.NewUUID().String())
+	req.Header.Set("Ory-Webhook-Trigger-ID", d.TriggerID.String())
+
+	if d.Auth != "" {
+		raw, err := o.r.Cipher(ctx).Decrypt(ctx, d.Auth.String())
+		if err != nil {
+			return err
+		}
+		var auth request.AuthConfig
+		if err := json.Unmarshal(raw, &auth); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := request.Authenticate(ctx, req, auth, o.r.HTTPClient(ctx)); err != nil {
+			return err
+		}
+	}
+
+	resp, err := o.r.HTTPClient(ctx).Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 5<<20))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.WithStack(fmt.Errorf("webhook failed with status code %v", resp.StatusCode))
+	}
+	return nil
+}
+
+// retryBackoff doubles the initial backoff with every attempt.
+func retryBackoff(initial time.Duration, attempt int) time.Duration {
+	backoff := initial
+	for i := 1; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
@@ -0,0 +1,231 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/internal"
+	"github.com/ory/kratos/request"
+	"github.com/ory/kratos/webhook"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/configx"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   string
+}
+
+func newReceiver(t *testing.T, status *int) (*httptest.Server, func() []receivedRequest) {
+	var (
+		mu       sync.Mutex
+		received []receivedRequest
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, receivedRequest{header: r.Header, body: string(body)})
+		w.WriteHeader(*status)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest{}, received...)
+	}
+}
+
+func enqueue(t *testing.T, ctx context.Context, o *webhook.Outbox, url, webhookID string) {
+	t.Helper()
+	enqueueWithAuth(t, ctx, o, url, webhookID, request.AuthConfig{})
+}
+
+func enqueueWithAuth(t *testing.T, ctx context.Context, o *webhook.Outbox, url, webhookID string, auth request.AuthConfig) {
+	t.Helper()
+	req, err := retryablehttp.NewRequest("POST", url, strings.NewReader(`{"identity_id":"foo"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	require.NoError(t, o.Enqueue(ctx, req, auth, webhookID, x.NewUUID()))
+}
+
+func deliveries(t *testing.T, ctx context.Context, p webhook.Persister, webhookID string) []webhook.Delivery {
+	t.Helper()
+	l, _, err := p.ListWebhookDeliveries(ctx, webhook.ListDeliveriesParameters{WebhookID: webhookID}, nil)
+	require.NoError(t, err)
+	return l
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := internal.NewFastRegistryWithMocks(t,
+		configx.WithValue(config.ViperKeyWebhookOutboxEnabled, true),
+		configx.WithValue(config.ViperKeyWebhookOutboxMaxRetries, 1),
+	)
+	o := reg.WebhookOutbox()
+	p := reg.WebhookPersister()
+
+	t.Run("case=delivers queued requests", func(t *testing.T) {
+		status := http.StatusOK
+		ts, received := newReceiver(t, &status)
+		webhookID := x.NewUUID().String()
+		enqueue(t, ctx, o, ts.URL, webhookID)
+
+		queued := deliveries(t, ctx, p, webhookID)
+		require.Len(t, queued, 1)
+		assert.Equal(t, webhook.DeliveryStatusQueued, queued[0].Status)
+		assert.Empty(t, received(), "requests must only be sent by the worker")
+		assert.Empty(t, queued[0].Header, "headers must only be stored encrypted")
+		assert.NotEmpty(t, queued[0].EncryptedHeader)
+		assert.NotContains(t, queued[0].EncryptedHeader.String(), "Bearer secret")
+
+		require.NoError(t, o.DispatchQueue(ctx))
+
+		require.Len(t, received(), 1)
+		assert.Equal(t, `{"identity_id":"foo"}`, received()[0].body)
+		assert.Equal(t, "Bearer secret", received()[0].header.Get("Authorization"))
+		assert.Equal(t, queued[0].TriggerID.String(), received()[0].header.Get("Ory-Webhook-Trigger-ID"))
+		assert.NotEmpty(t, received()[0].header.Get("Ory-Webhook-Request-ID"))
+
+		sent, err := p.GetWebhookDelivery(ctx, queued[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.DeliveryStatusSent, sent.Status)
+		assert.Equal(t, 1, sent.SendCount)
+
+		require.NoError(t, o.DispatchQueue(ctx))
+		assert.Len(t, received(), 1, "sent deliveries must not be sent again")
+	})
+
+	t.Run("case=authenticates requests when they are sent", func(t *testing.T) {
+		status := http.StatusOK
+		ts, received := newReceiver(t, &status)
+		webhookID := x.NewUUID().String()
+		enqueueWithAuth(t, ctx, o, ts.URL, webhookID, request.AuthConfig{
+			Type:   "hmac",
+			Config: map[string]any{"secrets": []any{"secret-0123456789"}},
+		})
+
+		queued := deliveries(t, ctx, p, webhookID)
+		require.Len(t, queued, 1)
+		assert.NotEmpty(t, queued[0].Auth)
+		raw, err := json.Marshal(queued[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "secret-0123456789")
+
+		require.NoError(t, o.DispatchQueue(ctx))
+
+		require.Len(t, received(), 1)
+		assert.NoError(t, request.VerifyHMACSignature(received()[0].header.Get(request.DefaultHMACHeader), []byte(received()[0].body), time.Minute, "secret-0123456789"))
+	})
+
+	t.Run("case=retries failed deliveries before abandoning them", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyWebhookOutboxRetryBackoff, "1ns")
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeyWebhookOutboxRetryBackoff, "30s") })
+
+		status := http.StatusBadRequest
+		ts, received := newReceiver(t, &status)
+		webhookID := x.NewUUID().String()
+		enqueue(t, ctx, o, ts.URL, webhookID)
+
+		require.NoError(t, o.DispatchQueue(ctx))
+		l := deliveries(t, ctx, p, webhookID)
+		require.Len(t, l, 1)
+		assert.Equal(t, webhook.DeliveryStatusQueued, l[0].Status)
+		assert.Equal(t, 1, l[0].SendCount)
+		assert.Contains(t, l[0].LastError.String(), "status code 400")
+
+		require.NoError(t, o.DispatchQueue(ctx))
+		l = deliveries(t, ctx, p, webhookID)
+		assert.Equal(t, webhook.DeliveryStatusAbandoned, l[0].Status)
+		assert.Equal(t, 2, l[0].SendCount)
+
+		require.NoError(t, o.DispatchQueue(ctx))
+		assert.Len(t, received(), 2, "abandoned deliveries must not be sent again")
+
+		t.Run("case=replays abandoned deliveries", func(t *testing.T) {
+			status = http.StatusOK
+
+			replayed, err := o.Replay(ctx, l[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, webhook.DeliveryStatusQueued, replayed.Status)
+			assert.Zero(t, replayed.SendCount)
+
+			_, err = o.Replay(ctx, l[0].ID)
+			require.ErrorIs(t, err, herodot.ErrConflict)
+
+			require.NoError(t, o.DispatchQueue(ctx))
+			assert.Len(t, received(), 3)
+			assert.Equal(t, webhook.DeliveryStatusSent, deliveries(t, ctx, p, webhookID)[0].Status)
+		})
+	})
+
+	t.Run("case=waits for the backoff before retrying", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyWebhookOutboxRetryBackoff, "1h")
+		t.Cleanup(func() { conf.MustSet(ctx, config.ViperKeyWebhookOutboxRetryBackoff, "30s") })
+
+		status := http.StatusBadRequest
+		ts, received := newReceiver(t, &status)
+		webhookID := x.NewUUID().String()
+		enqueue(t, ctx, o, ts.URL, webhookID)
+
+		require.NoError(t, o.DispatchQueue(ctx))
+		require.NoError(t, o.DispatchQueue(ctx))
+		assert.Len(t, received(), 1)
+
+		l := deliveries(t, ctx, p, webhookID)
+		assert.Equal(t, webhook.DeliveryStatusQueued, l[0].Status)
+		assert.WithinDuration(t, l[0].UpdatedAt.Add(time.Hour), l[0].NextAttemptAt, time.Minute)
+	})
+
+	t.Run("case=claims deliveries with a lease", func(t *testing.T) {
+		status := http.StatusOK
+		ts, received := newReceiver(t, &status)
+		webhookID := x.NewUUID().String()
+		enqueue(t, ctx, o, ts.URL, webhookID)
+		id := deliveries(t, ctx, p, webhookID)[0].ID
+
+		claimed, err := p.NextWebhookDeliveries(ctx, 10, "worker-0", time.Hour)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, webhook.DeliveryStatusProcessing, claimed[0].Status)
+		assert.EqualValues(t, "worker-0", claimed[0].WorkerID)
+
+		_, err = p.NextWebhookDeliveries(ctx, 10, "worker-1", time.Hour)
+		require.ErrorIs(t, err, webhook.ErrQueueEmpty, "deliveries with a valid lease must not be claimed again")
+
+		_, err = o.Replay(ctx, id)
+		require.ErrorIs(t, err, herodot.ErrConflict, "processing deliveries must not be replayed")
+
+		require.NoError(t, p.RenewWebhookDeliveryLease(ctx, id, "worker-0", -time.Second))
+		reclaimed, err := p.NextWebhookDeliveries(ctx, 10, "worker-1", time.Hour)
+		require.NoError(t, err)
+		require.Len(t, reclaimed, 1, "deliveries whose lease expired must be claimed again")
+		assert.EqualValues(t, "worker-1", reclaimed[0].WorkerID)
+
+		require.ErrorIs(t, p.RenewWebhookDeliveryLease(ctx, id, "worker-0", time.Hour), webhook.ErrLeaseLost)
+		require.ErrorIs(t, o.Deliver(ctx, &claimed[0]), webhook.ErrLeaseLost, "the previous worker must not update the delivery")
+
+		require.NoError(t, o.Deliver(ctx, &reclaimed[0]))
+		sent, err := p.GetWebhookDelivery(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.DeliveryStatusSent, sent.Status)
+		assert.Len(t, received(), 2)
+	})
+}
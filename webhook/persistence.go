@@ -0,0 +1,70 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+var (
+	ErrQueueEmpty = errors.New("queue is empty")
+
+	// ErrLeaseLost is returned if a delivery was claimed by another worker,
+	// for example because the lease expired, or was replayed meanwhile.
+	ErrLeaseLost = errors.New("the delivery is no longer claimed by the worker")
+
+	// ErrNotReplayable is returned if a delivery is queued or processing.
+	ErrNotReplayable = herodot.ErrConflict.WithReason("Only sent or abandoned webhook deliveries can be replayed.")
+)
+
+type (
+	Persister interface {
+		// AddWebhookDelivery queues the delivery.
+		AddWebhookDelivery(context.Context, *Delivery) error
+
+		// NextWebhookDeliveries claims up to limit deliveries which are due for
+		// the worker for the duration of the lease and marks them as
+		// processing. Deliveries which are still processing after their lease
+		// expired are claimed again. It returns ErrQueueEmpty if there are
+		// none.
+		NextWebhookDeliveries(ctx context.Context, limit int, workerID string, lease time.Duration) ([]Delivery, error)
+
+		// RenewWebhookDeliveryLease extends the lease of a delivery which is
+		// processing. It returns ErrLeaseLost if the delivery is no longer
+		// claimed by the worker.
+		RenewWebhookDeliveryLease(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration) error
+
+		// UpdateWebhookDelivery updates the status, send count, last error, and
+		// next attempt of a delivery which is processing. It returns
+		// ErrLeaseLost if the delivery is no longer claimed by the worker which
+		// is set on the delivery.
+		UpdateWebhookDelivery(context.Context, *Delivery) error
+
+		// ReplayWebhookDelivery queues a sent or abandoned delivery again and
+		// resets its send count. It returns ErrNotReplayable if the delivery
+		// is queued or processing.
+		ReplayWebhookDelivery(context.Context, uuid.UUID) (*Delivery, error)
+
+		// GetWebhookDelivery returns the delivery with the given ID.
+		GetWebhookDelivery(context.Context, uuid.UUID) (*Delivery, error)
+
+		// ListWebhookDeliveries lists the deliveries matching the filter, newest
+		// first.
+		ListWebhookDeliveries(context.Context, ListDeliveriesParameters, []keysetpagination.Option) ([]Delivery, *keysetpagination.Paginator, error)
+
+		// DeleteSentWebhookDeliveries removes sent deliveries which were
+		// created before the given time.
+		DeleteSentWebhookDeliveries(context.Context, time.Time, int) error
+	}
+	PersistenceProvider interface {
+		WebhookPersister() Persister
+	}
+)
@@ -6,6 +6,7 @@ package x
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gofrs/uuid"
 
@@ -40,6 +41,54 @@ func WrapWithIdentityIDError(err error, identityID uuid.UUID) error {
 	}
 }
 
+// WithLockoutError carries the state of the login lockout counter which was
+// updated or checked while producing the wrapped error.
+type WithLockoutError struct {
+	err            error
+	scope          string
+	failedAttempts int
+	lockedUntil    time.Time
+}
+
+func (e *WithLockoutError) Error() string {
+	return e.err.Error()
+}
+
+func (e *WithLockoutError) Unwrap() error {
+	return e.err
+}
+
+// Scope is the scope of the counter, e.g. "identifier" or "ip".
+func (e *WithLockoutError) Scope() string {
+	return e.scope
+}
+
+func (e *WithLockoutError) FailedAttempts() int {
+	return e.failedAttempts
+}
+
+// LockedUntil is zero if the login is not locked.
+func (e *WithLockoutError) LockedUntil() time.Time {
+	return e.lockedUntil
+}
+
+func (e *WithLockoutError) Locked() bool {
+	return !e.lockedUntil.IsZero()
+}
+
+func WrapWithLockoutError(err error, scope string, failedAttempts int, lockedUntil time.Time) error {
+	if err == nil {
+		return nil
+	}
+
+	return &WithLockoutError{
+		err:            err,
+		scope:          scope,
+		failedAttempts: failedAttempts,
+		lockedUntil:    lockedUntil,
+	}
+}
+
 var (
 	PseudoPanic = herodot.DefaultError{
 		StatusField: http.StatusText(http.StatusInternalServerError),
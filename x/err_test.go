@@ -6,6 +6,7 @@ package x_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
@@ -81,3 +82,30 @@ func TestWrapWithIdentityIDError(t *testing.T) {
 		assert.True(t, errors.Is(wrappedErr, baseErr))
 	})
 }
+
+func TestWrapWithLockoutError(t *testing.T) {
+	t.Run("case=wraps error with lockout state", func(t *testing.T) {
+		baseErr := errors.New("test error")
+		lockedUntil := time.Now().Add(time.Minute)
+
+		wrappedErr := x.WrapWithIdentityIDError(x.WrapWithLockoutError(baseErr, "identifier", 5, lockedUntil), uuid.Must(uuid.NewV4()))
+
+		var lockoutErr *x.WithLockoutError
+		require.True(t, errors.As(wrappedErr, &lockoutErr))
+		assert.Equal(t, "identifier", lockoutErr.Scope())
+		assert.Equal(t, 5, lockoutErr.FailedAttempts())
+		assert.Equal(t, lockedUntil, lockoutErr.LockedUntil())
+		assert.True(t, lockoutErr.Locked())
+		assert.True(t, errors.Is(wrappedErr, baseErr))
+	})
+
+	t.Run("case=is not locked without a lock time", func(t *testing.T) {
+		var lockoutErr *x.WithLockoutError
+		require.True(t, errors.As(x.WrapWithLockoutError(errors.New("test error"), "ip", 1, time.Time{}), &lockoutErr))
+		assert.False(t, lockoutErr.Locked())
+	})
+
+	t.Run("case=returns nil when wrapping nil error", func(t *testing.T) {
+		assert.Nil(t, x.WrapWithLockoutError(nil, "ip", 1, time.Time{}))
+	})
+}
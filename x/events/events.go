@@ -23,10 +23,13 @@ import (
 const (
 	IdentityCreated          semconv.Event = "IdentityCreated"
 	IdentityDeleted          semconv.Event = "IdentityDeleted"
+	IdentitySoftDeleted      semconv.Event = "IdentitySoftDeleted"
+	IdentityUndeleted        semconv.Event = "IdentityUndeleted"
 	IdentityUpdated          semconv.Event = "IdentityUpdated"
 	JsonnetMappingFailed     semconv.Event = "JsonnetMappingFailed"
 	LoginFailed              semconv.Event = "LoginFailed"
 	LoginInitiated           semconv.Event = "LoginInitiated"
+	LoginRiskEvaluated       semconv.Event = "LoginRiskEvaluated"
 	LoginSucceeded           semconv.Event = "LoginSucceeded"
 	RecoveryFailed           semconv.Event = "RecoveryFailed"
 	RecoveryInitiatedByAdmin semconv.Event = "RecoveryInitiatedByAdmin"
@@ -36,6 +39,7 @@ const (
 	RegistrationSucceeded    semconv.Event = "RegistrationSucceeded"
 	SessionChanged           semconv.Event = "SessionChanged"
 	SessionChecked           semconv.Event = "SessionChecked"
+	SessionImpersonated      semconv.Event = "SessionImpersonated"
 	SessionIssued            semconv.Event = "SessionIssued"
 	SessionLifespanExtended  semconv.Event = "SessionLifespanExtended"
 	SessionRevoked           semconv.Event = "SessionRevoked"
@@ -44,11 +48,19 @@ const (
 	SettingsSucceeded        semconv.Event = "SettingsSucceeded"
 	VerificationFailed       semconv.Event = "VerificationFailed"
 	VerificationSucceeded    semconv.Event = "VerificationSucceeded"
+	WebhookAbandoned         semconv.Event = "WebhookAbandoned"
 	WebhookDelivered         semconv.Event = "WebhookDelivered"
 	WebhookFailed            semconv.Event = "WebhookFailed"
 	WebhookSucceeded         semconv.Event = "WebhookSucceeded"
 	CourierMessageAbandoned  semconv.Event = "CourierMessageAbandoned"
+	CourierMessageCancelled  semconv.Event = "CourierMessageCancelled"
 	CourierMessageDispatched semconv.Event = "CourierMessageDispatched"
+	CourierMessageExpired    semconv.Event = "CourierMessageExpired"
+	CourierMessageRequeued   semconv.Event = "CourierMessageRequeued"
+	CourierMessageSuppressed semconv.Event = "CourierMessageSuppressed"
+	CourierMessageDelivery   semconv.Event = "CourierMessageDelivery"
+	DeletionFailed           semconv.Event = "DeletionFailed"
+	DeletionSucceeded        semconv.Event = "DeletionSucceeded"
 )
 
 const (
@@ -78,6 +90,7 @@ const (
 	AttributeKeySessionID                  semconv.AttributeKey = "SessionID"
 	AttributeKeyTokenizedSessionTTL        semconv.AttributeKey = "TokenizedSessionTTL"
 	AttributeKeyWebhookAttemptNumber       semconv.AttributeKey = "WebhookAttemptNumber"
+	AttributeKeyWebhookDeliveryID          semconv.AttributeKey = "WebhookDeliveryID"
 	AttributeKeyWebhookID                  semconv.AttributeKey = "WebhookID"
 	AttributeKeyWebhookRequestBody         semconv.AttributeKey = "WebhookRequestBody"
 	AttributeKeyWebhookRequestID           semconv.AttributeKey = "WebhookRequestID"
@@ -88,6 +101,16 @@ const (
 	AttributeKeyCourierMessageID           semconv.AttributeKey = "CourierMessageID"
 	AttributeKeyCourierMessageChannel      semconv.AttributeKey = "CourierMessageChannel"
 	AttributeKeyCourierMessageTemplateType semconv.AttributeKey = "CourierMessageTemplateType"
+	AttributeKeyCourierSuppressionReason   semconv.AttributeKey = "CourierSuppressionReason"
+	AttributeKeyCourierDeliveryStatus      semconv.AttributeKey = "CourierDeliveryStatus"
+	AttributeKeyLoginLockoutScope          semconv.AttributeKey = "LoginLockoutScope"
+	AttributeKeyLoginLockoutFailedAttempts semconv.AttributeKey = "LoginLockoutFailedAttempts"
+	AttributeKeyLoginLockoutLocked         semconv.AttributeKey = "LoginLockoutLocked"
+	AttributeKeyLoginLockoutLockedUntil    semconv.AttributeKey = "LoginLockoutLockedUntil"
+	AttributeKeyImpersonationActorID       semconv.AttributeKey = "ImpersonationActorID"
+	AttributeKeyRiskScore                  semconv.AttributeKey = "RiskScore"
+	AttributeKeyRiskSignals                semconv.AttributeKey = "RiskSignals"
+	AttributeKeyRiskStepUpRequired         semconv.AttributeKey = "RiskStepUpRequired"
 )
 
 func attrSessionID(val uuid.UUID) otelattr.KeyValue {
@@ -170,6 +193,10 @@ func attrWebhookTriggerID(id uuid.UUID) otelattr.KeyValue {
 	return otelattr.String(AttributeKeyWebhookTriggerID.String(), id.String())
 }
 
+func attrWebhookDeliveryID(id uuid.UUID) otelattr.KeyValue {
+	return otelattr.String(AttributeKeyWebhookDeliveryID.String(), id.String())
+}
+
 // deprecated: use attrErrorReason instead
 func attrReason(err error) otelattr.KeyValue {
 	return otelattr.String(AttributeKeyReason.String(), reasonForError(err))
@@ -203,6 +230,46 @@ func attrCourierMessageTemplateType(templateType string) otelattr.KeyValue {
 	return otelattr.String(AttributeKeyCourierMessageTemplateType.String(), templateType)
 }
 
+func attrCourierSuppressionReason(reason string) otelattr.KeyValue {
+	return otelattr.String(AttributeKeyCourierSuppressionReason.String(), reason)
+}
+
+func attrCourierDeliveryStatus(status string) otelattr.KeyValue {
+	return otelattr.String(AttributeKeyCourierDeliveryStatus.String(), status)
+}
+
+func attrLoginLockoutScope(scope string) otelattr.KeyValue {
+	return otelattr.String(AttributeKeyLoginLockoutScope.String(), scope)
+}
+
+func attrLoginLockoutFailedAttempts(n int) otelattr.KeyValue {
+	return otelattr.Int(AttributeKeyLoginLockoutFailedAttempts.String(), n)
+}
+
+func attrLoginLockoutLocked(val bool) otelattr.KeyValue {
+	return otelattr.Bool(AttributeKeyLoginLockoutLocked.String(), val)
+}
+
+func attrLoginLockoutLockedUntil(lockedUntil time.Time) otelattr.KeyValue {
+	return otelattr.String(AttributeKeyLoginLockoutLockedUntil.String(), lockedUntil.String())
+}
+
+func attrImpersonationActorID(actorID string) otelattr.KeyValue {
+	return otelattr.String(AttributeKeyImpersonationActorID.String(), actorID)
+}
+
+func attrRiskScore(score int) otelattr.KeyValue {
+	return otelattr.Int(AttributeKeyRiskScore.String(), score)
+}
+
+func attrRiskSignals(signals []string) otelattr.KeyValue {
+	return otelattr.StringSlice(AttributeKeyRiskSignals.String(), signals)
+}
+
+func attrRiskStepUpRequired(val bool) otelattr.KeyValue {
+	return otelattr.Bool(AttributeKeyRiskStepUpRequired.String(), val)
+}
+
 func NewSessionIssued(ctx context.Context, aal string, sessionID, identityID uuid.UUID) (string, trace.EventOption) {
 	return SessionIssued.String(),
 		trace.WithAttributes(
@@ -227,6 +294,18 @@ func NewSessionChanged(ctx context.Context, aal string, sessionID, identityID uu
 		)
 }
 
+func NewSessionImpersonated(ctx context.Context, sessionID, identityID uuid.UUID, actorID string) (string, trace.EventOption) {
+	return SessionImpersonated.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				semconv.AttrIdentityID(identityID),
+				attrSessionID(sessionID),
+				attrImpersonationActorID(actorID),
+			)...,
+		)
+}
+
 func NewSessionLifespanExtended(ctx context.Context, sessionID, identityID uuid.UUID, newExpiry time.Time) (string, trace.EventOption) {
 	return SessionLifespanExtended.String(),
 		trace.WithAttributes(
@@ -366,6 +445,27 @@ func NewSettingsFailed(ctx context.Context, flowID uuid.UUID, flowType, method s
 	return SettingsFailed.String(), trace.WithAttributes(attrs...)
 }
 
+func NewDeletionSucceeded(ctx context.Context, flowID, identityID uuid.UUID, flowType string) (string, trace.EventOption) {
+	return DeletionSucceeded.String(),
+		trace.WithAttributes(append(
+			semconv.AttributesFromContext(ctx),
+			attrSelfServiceFlowType(flowType),
+			semconv.AttrIdentityID(identityID),
+			attrFlowID(flowID),
+		)...)
+}
+
+func NewDeletionFailed(ctx context.Context, flowID uuid.UUID, flowType string, err error) (string, trace.EventOption) {
+	return DeletionFailed.String(),
+		trace.WithAttributes(append(
+			semconv.AttributesFromContext(ctx),
+			attrSelfServiceFlowType(flowType),
+			attrReason(err),
+			attrErrorReason(err),
+			attrFlowID(flowID),
+		)...)
+}
+
 func NewVerificationFailed(ctx context.Context, flowID uuid.UUID, flowType, method string, err error) (string, trace.EventOption) {
 	attrs := append(
 		semconv.AttributesFromContext(ctx),
@@ -405,6 +505,26 @@ func NewIdentityDeleted(ctx context.Context, identityID uuid.UUID) (string, trac
 		)
 }
 
+func NewIdentitySoftDeleted(ctx context.Context, identityID uuid.UUID) (string, trace.EventOption) {
+	return IdentitySoftDeleted.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				semconv.AttrIdentityID(identityID),
+			)...,
+		)
+}
+
+func NewIdentityUndeleted(ctx context.Context, identityID uuid.UUID) (string, trace.EventOption) {
+	return IdentityUndeleted.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				semconv.AttrIdentityID(identityID),
+			)...,
+		)
+}
+
 func NewIdentityUpdated(ctx context.Context, identityID uuid.UUID) (string, trace.EventOption) {
 	return IdentityUpdated.String(),
 		trace.WithAttributes(
@@ -415,6 +535,21 @@ func NewIdentityUpdated(ctx context.Context, identityID uuid.UUID) (string, trac
 		)
 }
 
+func NewLoginRiskEvaluated(ctx context.Context, flowID, sessionID, identityID uuid.UUID, score int, signals []string, stepUpRequired bool) (string, trace.EventOption) {
+	return LoginRiskEvaluated.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				semconv.AttrIdentityID(identityID),
+				attrSessionID(sessionID),
+				attrFlowID(flowID),
+				attrRiskScore(score),
+				attrRiskSignals(signals),
+				attrRiskStepUpRequired(stepUpRequired),
+			)...,
+		)
+}
+
 func NewLoginFailed(ctx context.Context, flowID uuid.UUID, flowType, method, requestedAAL string, isRefresh bool, err error) (string, trace.EventOption) {
 	attrs := append(
 		semconv.AttributesFromContext(ctx),
@@ -432,6 +567,18 @@ func NewLoginFailed(ctx context.Context, flowID uuid.UUID, flowType, method, req
 		attrs = append(attrs, semconv.AttrIdentityID(identityIDError.IdentityID()))
 	}
 
+	var lockoutError *x.WithLockoutError
+	if errors.As(err, &lockoutError) {
+		attrs = append(attrs,
+			attrLoginLockoutScope(lockoutError.Scope()),
+			attrLoginLockoutFailedAttempts(lockoutError.FailedAttempts()),
+			attrLoginLockoutLocked(lockoutError.Locked()),
+		)
+		if lockoutError.Locked() {
+			attrs = append(attrs, attrLoginLockoutLockedUntil(lockoutError.LockedUntil()))
+		}
+	}
+
 	return LoginFailed.String(), trace.WithAttributes(attrs...)
 }
 
@@ -509,6 +656,21 @@ func NewWebhookFailed(ctx context.Context, err error, triggerID uuid.UUID, id st
 		)
 }
 
+// NewWebhookAbandoned is emitted when a delivery of the webhook outbox is
+// abandoned because it failed too often.
+func NewWebhookAbandoned(ctx context.Context, deliveryID, triggerID uuid.UUID, webhookID string, attempts int) (string, trace.EventOption) {
+	return WebhookAbandoned.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				attrWebhookDeliveryID(deliveryID),
+				attrWebhookID(webhookID),
+				attrWebhookTriggerID(triggerID),
+				attrWebhookAttempt(attempts),
+			)...,
+		)
+}
+
 // NewJsonnetMappingFailed is used to log errors that occur during the Jsonnet
 // mapping process. The jsonnetInput and jsonnetOutput is anonymized before
 // emitting the event.
@@ -583,6 +745,18 @@ func NewCourierMessageAbandoned(ctx context.Context, messageID uuid.UUID, channe
 		)
 }
 
+func NewCourierMessageCancelled(ctx context.Context, messageID uuid.UUID, channel string, templateType string) (string, trace.EventOption) {
+	return CourierMessageCancelled.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				attrCourierMessageID(messageID),
+				attrCourierMessageChannel(channel),
+				attrCourierMessageTemplateType(templateType),
+			)...,
+		)
+}
+
 func NewCourierMessageDispatched(ctx context.Context, messageID uuid.UUID, channel string, templateType string) (string, trace.EventOption) {
 	return CourierMessageDispatched.String(),
 		trace.WithAttributes(
@@ -594,3 +768,52 @@ func NewCourierMessageDispatched(ctx context.Context, messageID uuid.UUID, chann
 			)...,
 		)
 }
+
+func NewCourierMessageExpired(ctx context.Context, messageID uuid.UUID, channel string, templateType string) (string, trace.EventOption) {
+	return CourierMessageExpired.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				attrCourierMessageID(messageID),
+				attrCourierMessageChannel(channel),
+				attrCourierMessageTemplateType(templateType),
+			)...,
+		)
+}
+
+func NewCourierMessageRequeued(ctx context.Context, messageID uuid.UUID, channel string, templateType string) (string, trace.EventOption) {
+	return CourierMessageRequeued.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				attrCourierMessageID(messageID),
+				attrCourierMessageChannel(channel),
+				attrCourierMessageTemplateType(templateType),
+			)...,
+		)
+}
+
+func NewCourierMessageSuppressed(ctx context.Context, messageID uuid.UUID, channel string, templateType string, reason string) (string, trace.EventOption) {
+	return CourierMessageSuppressed.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				attrCourierMessageID(messageID),
+				attrCourierMessageChannel(channel),
+				attrCourierMessageTemplateType(templateType),
+				attrCourierSuppressionReason(reason),
+			)...,
+		)
+}
+
+func NewCourierMessageDelivery(ctx context.Context, messageID uuid.UUID, channel string, deliveryStatus string) (string, trace.EventOption) {
+	return CourierMessageDelivery.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				attrCourierMessageID(messageID),
+				attrCourierMessageChannel(channel),
+				attrCourierDeliveryStatus(deliveryStatus),
+			)...,
+		)
+}
@@ -6,6 +6,7 @@ package events_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
@@ -117,6 +118,34 @@ func TestNewLoginFailed(t *testing.T) {
 		assert.Contains(t, attrs, attribute.String("SelfServiceFlowType", "browser"))
 		assert.Contains(t, attrs, attribute.String("ErrorReason", "login failed"))
 	})
+
+	t.Run("case=with lockout", func(t *testing.T) {
+		lockedUntil := time.Now().Add(time.Minute)
+		wrappedErr := x.WrapWithIdentityIDError(x.WrapWithLockoutError(baseErr, "identifier", 5, lockedUntil), identityID)
+		_, opts := events.NewLoginFailed(ctx, flowID, "browser", "password", "aal1", false, wrappedErr)
+
+		eventConfig := trace.NewEventConfig(opts)
+		attrs := eventConfig.Attributes()
+
+		assert.Contains(t, attrs, attribute.String("IdentityID", identityID.String()))
+		assert.Contains(t, attrs, attribute.String("LoginLockoutScope", "identifier"))
+		assert.Contains(t, attrs, attribute.Int("LoginLockoutFailedAttempts", 5))
+		assert.Contains(t, attrs, attribute.Bool("LoginLockoutLocked", true))
+		assert.Contains(t, attrs, attribute.String("LoginLockoutLockedUntil", lockedUntil.String()))
+	})
+
+	t.Run("case=with failed attempts below the lockout threshold", func(t *testing.T) {
+		_, opts := events.NewLoginFailed(ctx, flowID, "browser", "password", "aal1", false, x.WrapWithLockoutError(baseErr, "identifier", 2, time.Time{}))
+
+		eventConfig := trace.NewEventConfig(opts)
+		attrs := eventConfig.Attributes()
+
+		assert.Contains(t, attrs, attribute.Int("LoginLockoutFailedAttempts", 2))
+		assert.Contains(t, attrs, attribute.Bool("LoginLockoutLocked", false))
+		for _, attr := range attrs {
+			assert.NotEqual(t, "LoginLockoutLockedUntil", string(attr.Key))
+		}
+	})
 }
 
 func TestNewRecoveryFailed(t *testing.T) {
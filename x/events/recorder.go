@@ -0,0 +1,83 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/urfave/negroni"
+	otelattr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ory/x/otelx/semconv"
+)
+
+const (
+	// AttributeKeyActor is set for events which were triggered by someone other
+	// than the identity itself, for example by an administrator.
+	AttributeKeyActor     semconv.AttributeKey = "Actor"
+	AttributeKeyUserAgent semconv.AttributeKey = "UserAgent"
+)
+
+const (
+	// ActorAdmin is the actor of events triggered through the admin API.
+	ActorAdmin = "admin"
+	// ActorCLI is the actor of events triggered through the command line
+	// interface.
+	ActorCLI = "cli"
+)
+
+// Recorder is notified about every event added to a span returned by
+// SpanFromContext. Unlike span events, recorded events do not depend on trace
+// sampling.
+type Recorder interface {
+	RecordEvent(ctx context.Context, name string, attrs []otelattr.KeyValue)
+}
+
+type recorderContextKey struct{}
+
+// WithRecorder returns a context in which events are passed to the recorder.
+func WithRecorder(ctx context.Context, r Recorder) context.Context {
+	return context.WithValue(ctx, recorderContextKey{}, r)
+}
+
+// SpanFromContext returns the span of the context. Events added to the span
+// are also passed to the recorder of the context, if there is one, even if the
+// span is not recording.
+func SpanFromContext(ctx context.Context) trace.Span {
+	span := trace.SpanFromContext(ctx)
+	r, ok := ctx.Value(recorderContextKey{}).(Recorder)
+	if !ok {
+		return span
+	}
+	return &recordingSpan{Span: span, ctx: ctx, r: r}
+}
+
+type recordingSpan struct {
+	trace.Span
+	ctx context.Context
+	r   Recorder
+}
+
+func (s *recordingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	s.Span.AddEvent(name, opts...)
+	c := trace.NewEventConfig(opts...)
+	s.r.RecordEvent(s.ctx, name, c.Attributes())
+}
+
+// NewRecorderMiddleware passes the events emitted while handling a request to
+// the recorder. The user agent of the request and the actor, if not empty, are
+// added to the attributes of these events.
+func NewRecorderMiddleware(r Recorder, actor string) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		attrs := []otelattr.KeyValue{otelattr.String(AttributeKeyUserAgent.String(), req.UserAgent())}
+		if actor != "" {
+			attrs = append(attrs, otelattr.String(AttributeKeyActor.String(), actor))
+		}
+
+		ctx := semconv.ContextWithAttributes(req.Context(), attrs...)
+		next(w, req.WithContext(WithRecorder(ctx, r)))
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/x/events"
+)
+
+type recordedEvent struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+type recorder struct{ events []recordedEvent }
+
+func (r *recorder) RecordEvent(_ context.Context, name string, attrs []attribute.KeyValue) {
+	r.events = append(r.events, recordedEvent{name: name, attrs: attrs})
+}
+
+func TestRecorder(t *testing.T) {
+	id := uuid.Must(uuid.NewV4())
+
+	t.Run("case=does nothing without recorder", func(t *testing.T) {
+		ctx := context.Background()
+		events.SpanFromContext(ctx).AddEvent(events.NewIdentityUpdated(ctx, id))
+	})
+
+	t.Run("case=records events of non-recording spans", func(t *testing.T) {
+		r := new(recorder)
+		ctx := events.WithRecorder(context.Background(), r)
+
+		events.SpanFromContext(ctx).AddEvent(events.NewIdentityUpdated(ctx, id))
+
+		require.Len(t, r.events, 1)
+		assert.Equal(t, events.IdentityUpdated.String(), r.events[0].name)
+		assert.Contains(t, r.events[0].attrs, attribute.String("IdentityID", id.String()))
+	})
+
+	t.Run("case=middleware adds the actor and user agent", func(t *testing.T) {
+		r := new(recorder)
+		mw := events.NewRecorderMiddleware(r, events.ActorAdmin)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("User-Agent", "test-agent")
+		mw(httptest.NewRecorder(), req, func(_ http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			events.SpanFromContext(ctx).AddEvent(events.NewIdentityUpdated(ctx, id))
+		})
+
+		require.Len(t, r.events, 1)
+		assert.Contains(t, r.events[0].attrs, attribute.String(events.AttributeKeyUserAgent.String(), "test-agent"))
+		assert.Contains(t, r.events[0].attrs, attribute.String(events.AttributeKeyActor.String(), events.ActorAdmin))
+	})
+}
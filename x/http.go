@@ -6,8 +6,11 @@ package x
 import (
 	"cmp"
 	"context"
+	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"strings"
 
 	"github.com/golang/gddo/httputil"
 	"github.com/hashicorp/go-retryablehttp"
@@ -73,6 +76,66 @@ func RequestURL(r *http.Request) *url.URL {
 	return &source
 }
 
+// TrustedClientIP returns the client IP address of the request for security
+// decisions, such as counting failed login attempts. Unlike httpx.ClientIP, it
+// does not trust headers set by the client. The X-Forwarded-For header is only
+// used if the request was sent by one of the trusted proxies, in which case the
+// right-most address which is not a trusted proxy is returned. Malformed
+// addresses in the header end the search at the last trusted hop.
+func TrustedClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	addr, err := remoteAddr(r)
+	if err != nil {
+		return stripPort(r.RemoteAddr)
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0 && isTrustedProxy(addr, trustedProxies); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		addr = hop.Unmap()
+	}
+
+	return addr.String()
+}
+
+// IsTrustedProxyRequest returns true if the request was sent by one of the
+// trusted proxies, in which case the headers they set about the client, such as
+// its geo location, can be relied upon.
+func IsTrustedProxyRequest(r *http.Request, trustedProxies []netip.Prefix) bool {
+	addr, err := remoteAddr(r)
+	return err == nil && isTrustedProxy(addr, trustedProxies)
+}
+
+func remoteAddr(r *http.Request) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(stripPort(r.RemoteAddr))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap(), nil
+}
+
+func stripPort(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return hostport
+}
+
+func isTrustedProxy(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, p := range trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 // SendFlowCompletedAsRedirectOrJSON should be used when a login, registration, ... flow has been completed successfully.
 // It will redirect the user to the provided URL if the request accepts HTML, or return a JSON response if the request is
 // an SPA request
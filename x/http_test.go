@@ -7,8 +7,11 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -41,6 +44,33 @@ func TestRequestURL(t *testing.T) {
 	}).String(), "https://notfoobar/foo")
 }
 
+func TestTrustedClientIP(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+
+	for k, tc := range []struct {
+		remoteAddr string
+		header     http.Header
+		expected   string
+	}{
+		{remoteAddr: "192.0.2.1:1234", expected: "192.0.2.1"},
+		{remoteAddr: "[2001:db9::1]:1234", expected: "2001:db9::1"},
+		{remoteAddr: "192.0.2.1:1234", header: http.Header{"X-Forwarded-For": {"192.0.2.2"}, "X-Real-Ip": {"192.0.2.3"}, "True-Client-Ip": {"192.0.2.4"}}, expected: "192.0.2.1"},
+		{remoteAddr: "10.0.0.1:1234", expected: "10.0.0.1"},
+		{remoteAddr: "10.0.0.1:1234", header: http.Header{"X-Forwarded-For": {"192.0.2.2"}}, expected: "192.0.2.2"},
+		{remoteAddr: "10.0.0.1:1234", header: http.Header{"X-Forwarded-For": {"192.0.2.9, 192.0.2.2, 10.0.0.2"}}, expected: "192.0.2.2"},
+		{remoteAddr: "10.0.0.1:1234", header: http.Header{"X-Forwarded-For": {"192.0.2.9", "192.0.2.2"}}, expected: "192.0.2.2"},
+		{remoteAddr: "[2001:db8::1]:1234", header: http.Header{"X-Forwarded-For": {"::ffff:192.0.2.2"}}, expected: "192.0.2.2"},
+		{remoteAddr: "10.0.0.1:1234", header: http.Header{"X-Forwarded-For": {"not-an-ip"}}, expected: "10.0.0.1"},
+		{remoteAddr: "10.0.0.1:1234", header: http.Header{"X-Forwarded-For": {"192.0.2.2, " + strings.Repeat("a", 300)}}, expected: "10.0.0.1"},
+	} {
+		t.Run(fmt.Sprintf("case=%d", k), func(t *testing.T) {
+			assert.Equal(t, tc.expected, TrustedClientIP(&http.Request{RemoteAddr: tc.remoteAddr, Header: tc.header}, proxies))
+		})
+	}
+
+	assert.Equal(t, "10.0.0.1", TrustedClientIP(&http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{"X-Forwarded-For": {"192.0.2.2"}}}, nil))
+}
+
 func TestAcceptToRedirectOrJSON(t *testing.T) {
 	wr := herodot.NewJSONWriter(logrusx.New("", ""))
 
@@ -0,0 +1,53 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package x
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// NormalizeLocale returns the canonical BCP 47 form of the locale, for example
+// "de-CH" for "de_ch". It returns an empty string if the locale is invalid, so
+// that the result is safe to use in file names.
+func NormalizeLocale(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return ""
+	}
+	return localeString(tag)
+}
+
+// PreferredLanguage returns the most preferred locale of the Accept-Language
+// header, or an empty string if the header is missing or invalid.
+func PreferredLanguage(h http.Header) string {
+	tags, _, err := language.ParseAcceptLanguage(h.Get("Accept-Language"))
+	if err != nil {
+		return ""
+	}
+	for _, tag := range tags {
+		if s := localeString(tag); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// localeString returns the language, script, and region of the tag. Variants
+// and extensions are dropped to keep the locale short.
+func localeString(tag language.Tag) string {
+	tag, err := language.Compose(tag.Raw())
+	if err != nil {
+		return ""
+	}
+	// The wildcard "*" is parsed as "mul" (multiple languages).
+	if s := tag.String(); s != "und" && s != "mul" {
+		return s
+	}
+	return ""
+}
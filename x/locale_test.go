@@ -0,0 +1,47 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package x
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"de", "de"},
+		{"de_ch", "de-CH"},
+		{"EN-us", "en-US"},
+		{"", ""},
+		{"../../etc/passwd", ""},
+		{"und", ""},
+		{"en-US-u-ca-gregory-x-private", "en-US"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeLocale(test.input), "%s", test.input)
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"de-CH,de;q=0.9,en;q=0.8", "de-CH"},
+		{"en;q=0.5,fr", "fr"},
+		{"*", ""},
+		{"", ""},
+		{"not a language header;;", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, PreferredLanguage(http.Header{"Accept-Language": {test.input}}), "%s", test.input)
+	}
+}
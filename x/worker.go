@@ -0,0 +1,21 @@
+// Copyright © 2025 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package x
+
+import (
+	"os"
+
+	"github.com/ory/x/randx"
+)
+
+// NewWorkerID returns the host name followed by a random suffix, which tells
+// apart several workers running on the same host. The fallback is used if
+// the host name is unknown.
+func NewWorkerID(fallback string) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = fallback
+	}
+	return hostname + "-" + randx.MustString(8, randx.AlphaLowerNum)
+}